@@ -0,0 +1,246 @@
+package healthcheck
+
+import (
+	"github.com/buger/jsonparser"
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghealth "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/das/pkg/resp"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	dbConfigRuleIDJSON           = "id"
+	dbConfigRuleVariableNameJSON = "variable_name"
+
+	dbConfigRuleIDStruct           = "ID"
+	dbConfigRuleVariableNameStruct = "VariableName"
+)
+
+// @Tags	healthcheck
+// @Summary	get all healthcheck db config rules
+// @Accept	application/json
+// @Param	token body string true "token"
+// @Produce application/json
+// @Success	200 {string} string "{"db_config_rules":[{"id":1,"variable_name":"log_bin","comparator":"eq","expected_value":"ON","severity":1,"min_version":"","max_version":"","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/rule/all [get]
+func GetDBConfigRule(c *gin.Context) {
+	// init service
+	s := healthcheck.NewDBConfigRuleServiceWithDefault()
+	// get entities
+	err := s.GetAll()
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetDBConfigRuleAll, err)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetDBConfigRuleAll, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetDBConfigRuleAll)
+}
+
+// @Tags	healthcheck
+// @Summary	get healthcheck db config rule by id
+// @Accept	application/json
+// @Param	token	body string true "token"
+// @Param	id		body int	true "db config rule id"
+// @Produce application/json
+// @Success	200 {string} string "{"db_config_rules":[{"id":1,"variable_name":"log_bin","comparator":"eq","expected_value":"ON","severity":1,"min_version":"","max_version":"","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/rule/id [get]
+func GetDBConfigRuleByID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	id, err := jsonparser.GetInt(data, dbConfigRuleIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), dbConfigRuleIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewDBConfigRuleServiceWithDefault()
+	// get entity
+	err = s.GetByID(int(id))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetDBConfigRuleByID, err, id)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetDBConfigRuleByID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetDBConfigRuleByID, id)
+}
+
+// @Tags	healthcheck
+// @Summary	add a new healthcheck db config rule
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	variable_name	body string true	"variable name"
+// @Param	comparator		body string true	"comparator, eq, ge, le, in or regex"
+// @Param	expected_value	body string true	"expected value, it supports the placeholders: ${host_ip}, ${port_num}, ${server_name}"
+// @Param	severity		body int	false	"severity, 1-high, 2-medium"
+// @Param	min_version		body string false	"min mysql version, it is inclusive, empty means no limit"
+// @Param	max_version		body string false	"max mysql version, it is exclusive, empty means no limit"
+// @Produce application/json
+// @Success	200 {string} string "{"db_config_rules":[{"id":1,"variable_name":"log_bin","comparator":"eq","expected_value":"ON","severity":1,"min_version":"","max_version":"","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/rule/add [post]
+func AddDBConfigRule(c *gin.Context) {
+	var fields map[string]interface{}
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	// unmarshal data
+	fields, err = common.UnmarshalToMapWithStructTag(data, &healthcheck.DBConfigRule{}, constant.DefaultMiddlewareTag)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, err)
+		return
+	}
+	_, ok := fields[dbConfigRuleVariableNameStruct]
+	if !ok {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, dbConfigRuleVariableNameJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewDBConfigRuleServiceWithDefault()
+	// insert into middleware
+	err = s.Create(fields)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckAddDBConfigRule, err, fields[dbConfigRuleVariableNameStruct])
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckAddDBConfigRule, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckAddDBConfigRule, fields[dbConfigRuleVariableNameStruct])
+}
+
+// @Tags	healthcheck
+// @Summary	update healthcheck db config rule by id
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	id				body int	true	"db config rule id"
+// @Param	variable_name	body string false	"variable name"
+// @Param	comparator		body string false	"comparator, eq, ge, le, in or regex"
+// @Param	expected_value	body string false	"expected value, it supports the placeholders: ${host_ip}, ${port_num}, ${server_name}"
+// @Param	severity		body int	false	"severity, 1-high, 2-medium"
+// @Param	min_version		body string false	"min mysql version, it is inclusive, empty means no limit"
+// @Param	max_version		body string false	"max mysql version, it is exclusive, empty means no limit"
+// @Param	del_flag		body int	false	"delete flag"
+// @Produce application/json
+// @Success	200 {string} string "{"db_config_rules":[{"id":1,"variable_name":"log_bin","comparator":"eq","expected_value":"ON","severity":2,"min_version":"","max_version":"","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-02T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/rule/update [post]
+func UpdateDBConfigRuleByID(c *gin.Context) {
+	var fields map[string]interface{}
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	// unmarshal data
+	fields, err = common.UnmarshalToMapWithStructTag(data, &healthcheck.DBConfigRule{}, constant.DefaultMiddlewareTag)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, err)
+		return
+	}
+	idInterface, idExists := fields[dbConfigRuleIDStruct]
+	if !idExists {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, dbConfigRuleIDJSON)
+		return
+	}
+	id, ok := idInterface.(int)
+	if !ok {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, dbConfigRuleIDJSON)
+		return
+	}
+	delete(fields, dbConfigRuleIDStruct)
+	if len(fields) == constant.ZeroInt {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, "fields to be updated")
+		return
+	}
+	// init service
+	s := healthcheck.NewDBConfigRuleServiceWithDefault()
+	// update entity
+	err = s.Update(id, fields)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckUpdateDBConfigRule, err, id)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckUpdateDBConfigRule, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckUpdateDBConfigRule, id)
+}
+
+// @Tags	healthcheck
+// @Summary	delete healthcheck db config rule by id
+// @Accept	application/json
+// @Param	token	body string true "token"
+// @Param	id		body int	true "db config rule id"
+// @Produce application/json
+// @Success	200 {string} string "{"db_config_rules":[{"id":1,"variable_name":"log_bin","comparator":"eq","expected_value":"ON","severity":1,"min_version":"","max_version":"","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/rule/delete [post]
+func DeleteDBConfigRuleByID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	id, err := jsonparser.GetInt(data, dbConfigRuleIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), dbConfigRuleIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewDBConfigRuleServiceWithDefault()
+	// delete entity
+	err = s.Delete(int(id))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckDeleteDBConfigRuleByID, err, id)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckDeleteDBConfigRuleByID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckDeleteDBConfigRuleByID, id)
+}
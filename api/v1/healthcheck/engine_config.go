@@ -0,0 +1,225 @@
+package healthcheck
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghealth "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/das/pkg/resp"
+	utilhealth "github.com/romberli/das/pkg/util/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	engineConfigVersionJSON     = "version"
+	engineConfigItemConfigsJSON = "item_configs"
+	engineConfigLoginNameJSON   = "login_name"
+
+	engineConfigProfileIDStruct   = "ProfileID"
+	engineConfigVersionStruct     = "Version"
+	engineConfigItemConfigsStruct = "ItemConfigs"
+	engineConfigVersionsStruct    = "Versions"
+)
+
+// @Tags	healthcheck
+// @Summary	get the current healthcheck engine config and its version
+// @Accept	application/json
+// @Param	token		body string true	"token"
+// @Param	profile_id	body int	false	"scoring profile id, 0 or omitted means the global default engine config"
+// @Produce application/json
+// @Success	200 {string} string "{"profile_id":0,"version":3,"item_configs":[{"id":1,"item_name":"db_config","item_weight":5,"low_watermark":0,"high_watermark":1,"unit":1,"score_deduction_per_unit_high":5,"max_score_deduction_high":100,"score_deduction_per_unit_medium":0,"max_score_deduction_medium":0,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/config/get [post]
+func GetEngineConfig(c *gin.Context) {
+	var rd *utilhealth.EngineConfig
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewEngineConfigServiceWithDefault()
+	// get entity
+	err = s.GetByProfileID(rd.GetProfileID())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetEngineConfig, err, rd.GetProfileID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(engineConfigProfileIDStruct, engineConfigVersionStruct, engineConfigItemConfigsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetEngineConfig, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetEngineConfig, rd.GetProfileID())
+}
+
+// @Tags	healthcheck
+// @Summary	update the given items of the healthcheck engine config, the other items remain unchanged, the merged engine config must pass the validation, and it will be saved as a new version
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	profile_id		body int	false	"scoring profile id, 0 or omitted means the global default engine config"
+// @Param	item_configs	body array	true	"item configs to be updated"
+// @Param	login_name		body string true	"login name of the editor"
+// @Produce application/json
+// @Success	200 {string} string "{"profile_id":0,"version":4,"item_configs":[{"id":1,"item_name":"db_config","item_weight":5,"low_watermark":0,"high_watermark":1,"unit":1,"score_deduction_per_unit_high":5,"max_score_deduction_high":100,"score_deduction_per_unit_medium":0,"max_score_deduction_medium":0,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/config/update [post]
+func UpdateEngineConfig(c *gin.Context) {
+	saveEngineConfig(c, false)
+}
+
+// @Tags	healthcheck
+// @Summary	replace all the items of the healthcheck engine config, the new engine config must pass the validation, and it will be saved as a new version
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	profile_id		body int	false	"scoring profile id, 0 or omitted means the global default engine config"
+// @Param	item_configs	body array	true	"item configs, it must be a full item config set"
+// @Param	login_name		body string true	"login name of the editor"
+// @Produce application/json
+// @Success	200 {string} string "{"profile_id":0,"version":4,"item_configs":[{"id":1,"item_name":"db_config","item_weight":5,"low_watermark":0,"high_watermark":1,"unit":1,"score_deduction_per_unit_high":5,"max_score_deduction_high":100,"score_deduction_per_unit_medium":0,"max_score_deduction_medium":0,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/config/replace [post]
+func ReplaceEngineConfig(c *gin.Context) {
+	saveEngineConfig(c, true)
+}
+
+// saveEngineConfig updates or replaces the engine config
+func saveEngineConfig(c *gin.Context, isReplace bool) {
+	errCode := msghealth.ErrHealthcheckUpdateEngineConfig
+	debugCode := msghealth.DebugHealthcheckUpdateEngineConfig
+	infoCode := msghealth.InfoHealthcheckUpdateEngineConfig
+	if isReplace {
+		errCode = msghealth.ErrHealthcheckReplaceEngineConfig
+		debugCode = msghealth.DebugHealthcheckReplaceEngineConfig
+		infoCode = msghealth.InfoHealthcheckReplaceEngineConfig
+	}
+
+	var rd *utilhealth.EngineConfig
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	if len(rd.GetItemConfigs()) == constant.ZeroInt {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, engineConfigItemConfigsJSON)
+		return
+	}
+	if rd.GetLoginName() == constant.EmptyString {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, engineConfigLoginNameJSON)
+		return
+	}
+	itemConfigs, err := healthcheck.NewDefaultItemConfigsWithJSON(rd.GetItemConfigs())
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, err)
+		return
+	}
+	// init service
+	s := healthcheck.NewEngineConfigServiceWithDefault()
+	// save entity
+	if isReplace {
+		err = s.Replace(rd.GetProfileID(), itemConfigs, rd.GetLoginName())
+	} else {
+		err = s.Update(rd.GetProfileID(), itemConfigs, rd.GetLoginName())
+	}
+	if err != nil {
+		resp.ResponseNOK(c, errCode, err, rd.GetProfileID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(engineConfigProfileIDStruct, engineConfigVersionStruct, engineConfigItemConfigsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(debugCode, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, infoCode, rd.GetProfileID(), s.GetVersion(), rd.GetLoginName())
+}
+
+// @Tags	healthcheck
+// @Summary	get the version history of the healthcheck engine config, ordered by the version descending
+// @Accept	application/json
+// @Param	token		body string true	"token"
+// @Param	profile_id	body int	false	"scoring profile id, 0 or omitted means the global default engine config"
+// @Produce application/json
+// @Success	200 {string} string "{"profile_id":0,"versions":[{"id":3,"profile_id":0,"version":3,"item_configs":[{"item_name":"db_config","item_weight":5,"low_watermark":0,"high_watermark":1,"unit":1,"score_deduction_per_unit_high":5,"max_score_deduction_high":100,"score_deduction_per_unit_medium":0,"max_score_deduction_medium":0}],"login_name":"zhangs","description":"updated items: db_config","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/config/version [post]
+func GetEngineConfigVersions(c *gin.Context) {
+	var rd *utilhealth.EngineConfig
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewEngineConfigServiceWithDefault()
+	// get entities
+	err = s.GetVersionsByProfileID(rd.GetProfileID())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetEngineConfigVersions, err, rd.GetProfileID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(engineConfigProfileIDStruct, engineConfigVersionsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetEngineConfigVersions, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetEngineConfigVersions, rd.GetProfileID())
+}
+
+// @Tags	healthcheck
+// @Summary	rollback the healthcheck engine config to the given version, it will be saved as a new version
+// @Accept	application/json
+// @Param	token		body string true	"token"
+// @Param	profile_id	body int	false	"scoring profile id, 0 or omitted means the global default engine config"
+// @Param	version		body int	true	"the version to rollback to"
+// @Param	login_name	body string true	"login name of the editor"
+// @Produce application/json
+// @Success	200 {string} string "{"profile_id":0,"version":5,"item_configs":[{"id":1,"item_name":"db_config","item_weight":5,"low_watermark":0,"high_watermark":1,"unit":1,"score_deduction_per_unit_high":5,"max_score_deduction_high":100,"score_deduction_per_unit_medium":0,"max_score_deduction_medium":0,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/config/rollback [post]
+func RollbackEngineConfig(c *gin.Context) {
+	var rd *utilhealth.EngineConfig
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	if rd.GetVersion() == constant.ZeroInt {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, engineConfigVersionJSON)
+		return
+	}
+	if rd.GetLoginName() == constant.EmptyString {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, engineConfigLoginNameJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewEngineConfigServiceWithDefault()
+	// rollback
+	err = s.Rollback(rd.GetProfileID(), rd.GetVersion(), rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckRollbackEngineConfig, err, rd.GetProfileID(), rd.GetVersion())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(engineConfigProfileIDStruct, engineConfigVersionStruct, engineConfigItemConfigsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckRollbackEngineConfig, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckRollbackEngineConfig, rd.GetProfileID(), rd.GetVersion(), rd.GetLoginName())
+}
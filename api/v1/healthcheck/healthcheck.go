@@ -3,6 +3,7 @@ package healthcheck
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/buger/jsonparser"
@@ -25,11 +26,43 @@ const (
 	operationIDJSON = "operation_id"
 	reviewJSON      = "review"
 
+	clusterOperationIDJSON    = "cluster_operation_id"
+	middlewareOperationIDJSON = "middleware_operation_id"
+
 	healthcheckOperationHistoriesStruct = "OperationHistories"
+	healthcheckClusterResultStruct      = "ClusterResult"
+	healthcheckMiddlewareResultStruct   = "MiddlewareResult"
+	healthcheckTrendPointsStruct        = "TrendPoints"
+	healthcheckResultDiffStruct         = "ResultDiff"
+	healthcheckItemFeedbacksStruct      = "ItemFeedbacks"
+	healthcheckFeedbackStatsStruct      = "FeedbackStats"
+	healthcheckQueuedOperationsStruct   = "QueuedOperations"
+	healthcheckRunningOperationsStruct  = "RunningOperations"
+	healthcheckProgressStruct           = "Progress"
+	healthcheckCapacitiesStruct         = "Capacities"
+
+	checkRespMessage             = `{"operation_id": %d, "message": "healthcheck started"}`
+	checkByHostInfoRespMessage   = `{"operation_id": %d, "message": "healthcheck by host info started"}`
+	checkClusterRespMessage      = `{"cluster_operation_id": %d, "message": "cluster healthcheck started"}`
+	checkMiddlewareRespMessage   = `{"middleware_operation_id": %d, "message": "middleware healthcheck started"}`
+	reviewAccuracyRespMessage    = `{"operation_id": %d, "message": "reviewed accuracy completed"}`
+	cancelRespMessage            = `{"operation_id": %d, "message": "healthcheck cancelled"}`
+	checkWithSnapshotRespMessage = `{"operation_id": %d, "message": "healthcheck with snapshot started"}`
+
+	reportFileNameTemplate    = "healthcheck_report_%d.%s"
+	reportHTMLExtension       = "html"
+	reportMarkdownExtension   = "md"
+	reportHTMLContentType     = "text/html; charset=utf-8"
+	reportMarkdownContentType = "text/markdown; charset=utf-8"
+
+	snapshotFileNameTemplate = "healthcheck_snapshot_%d.json"
+	snapshotContentType      = "application/json; charset=utf-8"
 
-	checkRespMessage           = `{"operation_id": %d, "message": "healthcheck started"}`
-	checkByHostInfoRespMessage = `{"operation_id": %d, "message": "healthcheck by host info started"}`
-	reviewAccuracyRespMessage  = `{"operation_id": %d, "message": "reviewed accuracy completed"}`
+	// progressEventName is the sse event name of the progress snapshot, the other events are named by their event types
+	progressEventName = "progress"
+	// progressPollInterval is the interval of reading the progress from the middleware while streaming,
+	// so that the progress of the operations running in other processes could also be streamed
+	progressPollInterval = 2 * time.Second
 )
 
 // @Tags	healthcheck
@@ -127,6 +160,7 @@ func GetResultByOperationID(c *gin.Context) {
 // @Param	start_time	body string true "start time"
 // @Param	end_time	body string true "end time"
 // @Param	step		body string true "step"
+// @Param	callback_urls	body []string false "http or https callback urls which will be notified when the operation stops, their hosts must be in healthcheck.callback.allowedHosts"
 // @Produce application/json
 // @Success 200 {string} string "{"operation_id: 16", "message": "healthcheck started"}"
 // @Router /api/v1/healthcheck/check [post]
@@ -171,7 +205,7 @@ func Check(c *gin.Context) {
 	// init service
 	s := healthcheck.NewServiceWithDefault()
 	// check health
-	operationID, err := s.Check(rd.GetServerID(), startTime, endTime, step, rd.GetLoginName())
+	operationID, err := s.Check(rd.GetServerID(), startTime, endTime, step, rd.GetLoginName(), rd.GetCallbackURLs())
 	if err != nil {
 		resp.ResponseNOK(c, msghealth.ErrHealthcheckCheck, err, operationID)
 		return
@@ -181,6 +215,212 @@ func Check(c *gin.Context) {
 	resp.ResponseOK(c, fmt.Sprintf(checkRespMessage, operationID), msghealth.InfoHealthcheckCheck, operationID)
 }
 
+// @Tags	healthcheck
+// @Summary get cluster result by cluster operation id
+// @Accept	application/json
+// @Param	token					body string true "token"
+// @Param	cluster_operation_id	body int 	true "cluster operation id"
+// @Produce application/json
+// @Success 200 {string} string "{"cluster_result":{"id":1,"cluster_operation_id":1,"mysql_cluster_id":1,"weighted_average_score":85,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00","node_result":[{"operation_id":31,"mysql_server_id":1,"host_ip":"192.168.137.11","port_num":3306,"is_master":true,"status":2,"weighted_average_score":95,"worst_item_name":"db_config","worst_item_score":80,"message":""},{"operation_id":32,"mysql_server_id":2,"host_ip":"192.168.137.12","port_num":3306,"is_master":false,"status":2,"weighted_average_score":85,"worst_item_name":"cpu_usage","worst_item_score":60,"message":""}],"config_drift":[{"variable_name":"sync_binlog","master_value":"1","mysql_server_id":2,"host_ip":"192.168.137.12","port_num":3306,"value":"0"}]}}"
+// @Router	/api/v1/healthcheck/result/cluster [post]
+func GetClusterResultByClusterOperationID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	clusterOperationID, err := jsonparser.GetInt(data, clusterOperationIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), clusterOperationIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get entities
+	err = s.GetClusterResultByClusterOperationID(int(clusterOperationID))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetClusterResult, err, clusterOperationID)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckClusterResultStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetClusterResult, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetClusterResult, clusterOperationID)
+}
+
+// @Tags healthcheck
+// @Summary check health of all the mysql servers of the mysql cluster
+// @Accept	application/json
+// @Param	token	    body string true "token"
+// @Param	cluster_id	body int	true "mysql cluster id"
+// @Param	start_time	body string true "start time"
+// @Param	end_time	body string true "end time"
+// @Param	step		body string true "step"
+// @Param	login_name	body string true "login name"
+// @Produce application/json
+// @Success 200 {string} string "{"cluster_operation_id: 1", "message": "cluster healthcheck started"}"
+// @Router /api/v1/healthcheck/check/cluster [post]
+func CheckCluster(c *gin.Context) {
+	var rd *utilhealth.CheckCluster
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	startTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetStartTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetStartTime())
+		return
+	}
+	endTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetEndTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetEndTime())
+		return
+	}
+
+	checkRange := int(endTime.Sub(startTime).Hours() / oneDayHours)
+	maxRange := viper.GetInt(config.HealthcheckMaxRangeKey)
+	if checkRange > maxRange {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckCheckRange, checkRange, maxRange)
+		return
+	}
+
+	minStartTime := time.Now().Add(-constant.Day * time.Duration(maxRange))
+	if startTime.Before(minStartTime) {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckStartTime, startTime.Format(constant.TimeLayoutSecond), minStartTime.Format(constant.TimeLayoutSecond))
+		return
+	}
+
+	step, err := time.ParseDuration(rd.GetStep())
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeDuration, errors.Trace(err), rd.GetStep())
+		return
+	}
+
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// check health of the cluster
+	clusterOperationID, err := s.CheckCluster(rd.GetClusterID(), startTime, endTime, step, rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckCheckCluster, err, clusterOperationID)
+		return
+	}
+
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckCheckCluster, clusterOperationID).Error())
+	resp.ResponseOK(c, fmt.Sprintf(checkClusterRespMessage, clusterOperationID), msghealth.InfoHealthcheckCheckCluster, clusterOperationID)
+}
+
+// @Tags	healthcheck
+// @Summary get middleware result by middleware operation id
+// @Accept	application/json
+// @Param	token					body string true "token"
+// @Param	middleware_operation_id	body int 	true "middleware operation id"
+// @Produce application/json
+// @Success 200 {string} string "{"middleware_result":{"id":1,"middleware_operation_id":1,"middleware_cluster_id":1,"score":70,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00","server_result":[{"middleware_server_id":1,"server_name":"proxy01","middleware_role":1,"host_ip":"192.168.137.21","port_num":33061,"alive":true,"latency":1,"cpu_usage":0.35,"memory_usage":0.52,"message":""},{"middleware_server_id":2,"server_name":"proxy02","middleware_role":2,"host_ip":"192.168.137.22","port_num":33062,"alive":false,"latency":0,"cpu_usage":-1,"memory_usage":-1,"message":"dial tcp 192.168.137.22:33062: connect: connection refused"}],"endpoint_result":[{"mysql_cluster_id":1,"cluster_name":"mysql-cluster-pmm2","rw_alive":1,"ro_alive":0,"ok":false,"message":"no alive read-only endpoint"}]}}"
+// @Router	/api/v1/healthcheck/result/middleware [post]
+func GetMiddlewareResultByMiddlewareOperationID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	middlewareOperationID, err := jsonparser.GetInt(data, middlewareOperationIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), middlewareOperationIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get entities
+	err = s.GetMiddlewareResultByMiddlewareOperationID(int(middlewareOperationID))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetMiddlewareResult, err, middlewareOperationID)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckMiddlewareResultStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetMiddlewareResult, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetMiddlewareResult, middlewareOperationID)
+}
+
+// @Tags healthcheck
+// @Summary check health of all the middleware servers of the middleware cluster and the endpoints of the linked mysql clusters, only one middleware healthcheck of the middleware cluster could run at the same time, it does not wait in the queue and could not be cancelled, it stops when it exceeds the healthcheck timeout
+// @Accept	application/json
+// @Param	token	    			body string true "token"
+// @Param	middleware_cluster_id	body int	true "middleware cluster id"
+// @Param	start_time				body string true "start time"
+// @Param	end_time				body string true "end time"
+// @Param	step					body string true "step"
+// @Param	login_name				body string true "login name"
+// @Produce application/json
+// @Success 200 {string} string "{"middleware_operation_id: 1", "message": "middleware healthcheck started"}"
+// @Router /api/v1/healthcheck/check/middleware [post]
+func CheckMiddleware(c *gin.Context) {
+	var rd *utilhealth.CheckMiddleware
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	startTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetStartTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetStartTime())
+		return
+	}
+	endTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetEndTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetEndTime())
+		return
+	}
+
+	checkRange := int(endTime.Sub(startTime).Hours() / oneDayHours)
+	maxRange := viper.GetInt(config.HealthcheckMaxRangeKey)
+	if checkRange > maxRange {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckCheckRange, checkRange, maxRange)
+		return
+	}
+
+	minStartTime := time.Now().Add(-constant.Day * time.Duration(maxRange))
+	if startTime.Before(minStartTime) {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckStartTime, startTime.Format(constant.TimeLayoutSecond), minStartTime.Format(constant.TimeLayoutSecond))
+		return
+	}
+
+	step, err := time.ParseDuration(rd.GetStep())
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeDuration, errors.Trace(err), rd.GetStep())
+		return
+	}
+
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// check health of the middleware cluster
+	middlewareOperationID, err := s.CheckMiddlewareCluster(rd.GetMiddlewareClusterID(), startTime, endTime, step, rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckCheckMiddleware, err, middlewareOperationID)
+		return
+	}
+
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckCheckMiddleware, middlewareOperationID).Error())
+	resp.ResponseOK(c, fmt.Sprintf(checkMiddlewareRespMessage, middlewareOperationID), msghealth.InfoHealthcheckCheckMiddleware, middlewareOperationID)
+}
+
 // @Tags healthcheck
 // @Summary check health of the database by host ip and port number
 // @Accept	application/json
@@ -190,6 +430,7 @@ func Check(c *gin.Context) {
 // @Param	start_time	body string true "start time"
 // @Param	end_time	body string true "end time"
 // @Param	step		body string true "step"
+// @Param	callback_urls	body []string false "http or https callback urls which will be notified when the operation stops, their hosts must be in healthcheck.callback.allowedHosts"
 // @Produce application/json
 // @Success 200 {string} string "{"operation_id: 18", "message": "healthcheck by host info started"}"
 // @Router /api/v1/healthcheck/check/host-info [post]
@@ -227,7 +468,7 @@ func CheckByHostInfo(c *gin.Context) {
 	// init service
 	s := healthcheck.NewServiceWithDefault()
 	// get entities
-	operationID, err := s.CheckByHostInfo(rd.GetHostIP(), rd.GetPortNum(), startTime, endTime, step, rd.GetLoginName())
+	operationID, err := s.CheckByHostInfo(rd.GetHostIP(), rd.GetPortNum(), startTime, endTime, step, rd.GetLoginName(), rd.GetCallbackURLs())
 	if err != nil {
 		resp.ResponseNOK(c, msghealth.ErrHealthcheckCheckByHostInfo, err, operationID)
 		return
@@ -267,3 +508,589 @@ func ReviewAccuracy(c *gin.Context) {
 	log.Debug(message.NewMessage(msghealth.DebugHealthcheckReviewAccuracy, reviewAccuracyRespMessage).Error())
 	resp.ResponseOK(c, fmt.Sprintf(reviewAccuracyRespMessage, rd.GetOperationID()), msghealth.InfoHealthcheckReviewAccuracy, rd.GetOperationID())
 }
+
+// @Tags	healthcheck
+// @Summary	review the accuracy of a check item of the operation, reviewing again replaces the existing feedback of the reviewer
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	operation_id	body int	true	"operation id"
+// @Param	item_name		body string true	"item name"
+// @Param	accuracy		body int	true	"accuracy, 1 means accurate, 2 means inaccurate"
+// @Param	comment			body string false	"comment"
+// @Param	login_name		body string true	"login name of the reviewer"
+// @Produce application/json
+// @Success	200 {string} string "{"item_feedbacks":[{"id":1,"operation_id":16,"item_name":"cpu_usage","accuracy":2,"comment":"the cpu usage was high because of the backup","login_name":"zhangs","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/review/item [post]
+func ReviewItemAccuracy(c *gin.Context) {
+	var rd *utilhealth.ReviewItemAccuracy
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// review item accuracy
+	err = s.ReviewItemAccuracy(rd.GetOperationID(), rd.GetItemName(), rd.GetAccuracy(), rd.GetComment(), rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckReviewItemAccuracy, err, rd.GetOperationID(), rd.GetItemName())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckItemFeedbacksStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckReviewItemAccuracy, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckReviewItemAccuracy, rd.GetOperationID(), rd.GetItemName(), rd.GetLoginName())
+}
+
+// @Tags	healthcheck
+// @Summary	get the accuracy feedbacks of the check items of the operation
+// @Accept	application/json
+// @Param	token			body string true "token"
+// @Param	operation_id	body int	true "operation id"
+// @Produce application/json
+// @Success	200 {string} string "{"item_feedbacks":[{"id":1,"operation_id":16,"item_name":"cpu_usage","accuracy":2,"comment":"the cpu usage was high because of the backup","login_name":"zhangs","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/review/item/operation-id [post]
+func GetItemFeedbacks(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	operationID, err := jsonparser.GetInt(data, operationIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), operationIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get entities
+	err = s.GetItemFeedbacksByOperationID(int(operationID))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetItemFeedbacks, err, operationID)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckItemFeedbacksStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetItemFeedbacks, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetItemFeedbacks, operationID)
+}
+
+// @Tags	healthcheck
+// @Summary	get the inaccurate rates of the check items, grouped by the check date, the item, the environment and the engine config version
+// @Accept	application/json
+// @Param	token		body string true "token"
+// @Param	start_time	body string true "start time of the operations"
+// @Param	end_time	body string true "end time of the operations"
+// @Produce application/json
+// @Success	200 {string} string "{"feedback_stats":[{"check_date":"2022-06-01","item_name":"cpu_usage","env_id":1,"profile_id":0,"config_version":3,"feedback_count":4,"inaccurate_count":1,"inaccurate_rate":0.25}]}"
+// @Router	/api/v1/healthcheck/review/stats [post]
+func GetFeedbackStats(c *gin.Context) {
+	var rd *utilhealth.FeedbackStats
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	startTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetStartTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetStartTime())
+		return
+	}
+	endTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetEndTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetEndTime())
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get stats
+	err = s.GetFeedbackStatsByTimeRange(startTime, endTime)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetFeedbackStats, err, rd.GetStartTime(), rd.GetEndTime())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckFeedbackStatsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetFeedbackStats, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetFeedbackStats, rd.GetStartTime(), rd.GetEndTime())
+}
+
+// @Tags healthcheck
+// @Summary cancel the running or queued healthcheck
+// @Accept	application/json
+// @Param	token			body string true "token"
+// @Param	operation_id	body int	true "operation id"
+// @Param	login_name		body string true "login name"
+// @Produce application/json
+// @Success 200 {string} string "{"operation_id": 16, "message": "healthcheck cancelled"}"
+// @Router /api/v1/healthcheck/cancel [post]
+func Cancel(c *gin.Context) {
+	var rd *utilhealth.Cancel
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// cancel
+	err = s.Cancel(rd.GetOperationID(), rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckCancel, err, rd.GetOperationID())
+		return
+	}
+
+	jsonStr := fmt.Sprintf(cancelRespMessage, rd.GetOperationID())
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckCancel, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckCancel, rd.GetOperationID())
+}
+
+// @Tags	healthcheck
+// @Summary	get the queued and the running healthcheck operations of all the das processes, the queued operations are ordered by the priority descending and then the operation id, as each das process has its own queue, it is the execution order only if there is one das process
+// @Accept	application/json
+// @Param	token body string true "token"
+// @Produce application/json
+// @Success	200 {string} string "{"queued_operations":[{"id":31,"user_id":1,"account_name":"zhangs","schedule_id":1,"cluster_operation_id":0,"mysql_server_id":2,"host_ip":"192.168.137.12","port_num":3306,"start_time":"2022-03-11T19:46:16+08:00","end_time":"2022-03-18T19:46:16+08:00","step":60,"profile_id":0,"config_version":0,"priority":1,"status":6,"message":"healthcheck queued. operation_id: 31, priority: 1","del_flag":0,"create_time":"2022-03-18T19:46:16.215941+08:00","last_update_time":"2022-03-18T19:46:16.215941+08:00"}],"running_operations":[]}"
+// @Router	/api/v1/healthcheck/queue [post]
+func GetOperationQueue(c *gin.Context) {
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get queue
+	err := s.GetOperationQueue()
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetOperationQueue, err)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckQueuedOperationsStruct, healthcheckRunningOperationsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetOperationQueue, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetOperationQueue, len(s.GetQueuedOperations()), len(s.GetRunningOperations()))
+}
+
+// @Tags	healthcheck
+// @Summary	get the latest progress of the healthcheck operation
+// @Accept	application/json
+// @Param	token			body string true "token"
+// @Param	operation_id	body int	true "operation id"
+// @Param	login_name		body string true "login name"
+// @Produce application/json
+// @Success	200 {string} string "{"progress":{"operation_id":16,"status":1,"total_item_num":12,"finished_item_num":5,"percent":41,"current_item_name":"io_util","elapsed":3520,"last_update_time":"2022-06-01T10:00:03.520+08:00"}}"
+// @Router	/api/v1/healthcheck/progress [post]
+func GetProgress(c *gin.Context) {
+	var rd *utilhealth.Progress
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get progress
+	err = s.GetProgressByOperationID(rd.GetOperationID(), rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetProgress, err, rd.GetOperationID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckProgressStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetProgress, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetProgress, rd.GetOperationID())
+}
+
+// @Tags	healthcheck
+// @Summary	stream the progress of the healthcheck operation as server-sent events until the operation stops
+// @Accept	application/json
+// @Param	token			body string true "token"
+// @Param	operation_id	body int	true "operation id"
+// @Param	login_name		body string true "login name"
+// @Produce text/event-stream
+// @Success	200 {string} string "event:item_finished\ndata:{"operation_id":16,"event_type":"item_finished","item_name":"cpu_usage","item_status":0,"item_score":100,"item_elapsed":320,"finished_item_num":1,"total_item_num":12,"percent":8,"elapsed":410,"status":1,"event_time":"2022-06-01T10:00:00.410+08:00"}"
+// @Router	/api/v1/healthcheck/progress/stream [post]
+func GetProgressStream(c *gin.Context) {
+	var rd *utilhealth.Progress
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// subscribe before getting the progress, so that no event will be missed between them
+	events, unsubscribe := s.SubscribeProgress(rd.GetOperationID())
+	defer unsubscribe()
+	// get progress
+	err = s.GetProgressByOperationID(rd.GetOperationID(), rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetProgressStream, err, rd.GetOperationID())
+		return
+	}
+	// send the latest progress first
+	c.SSEvent(progressEventName, s.GetProgress())
+	if s.GetProgress().IsFinished() {
+		log.Info(message.NewMessage(msghealth.InfoHealthcheckGetProgressStream, rd.GetOperationID()).Error())
+		return
+	}
+
+	ticker := time.NewTicker(progressPollInterval)
+	defer ticker.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				// the operation completed in this process
+				return false
+			}
+			c.SSEvent(event.GetEventType(), event)
+			log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetProgressStream, event.GetEventType()).Error())
+			return event.GetEventType() != healthcheck.ProgressEventTypeCompleted
+		case <-ticker.C:
+			progress, err := s.GetDASRepo().GetProgressByOperationID(rd.GetOperationID())
+			if err != nil {
+				log.Errorf("%+v", message.NewMessage(msghealth.ErrHealthcheckGetProgressStream, err, rd.GetOperationID()))
+				return false
+			}
+			c.SSEvent(progressEventName, progress)
+			return !progress.IsFinished()
+		case <-c.Request.Context().Done():
+			// the client went away
+			return false
+		}
+	})
+
+	log.Info(message.NewMessage(msghealth.InfoHealthcheckGetProgressStream, rd.GetOperationID()).Error())
+}
+
+// @Tags	healthcheck
+// @Summary get score trend of the mysql server
+// @Accept	application/json
+// @Param	token		body string true "token"
+// @Param	server_id	body int	true "mysql server id"
+// @Param	start_time	body string true "start time"
+// @Param	end_time	body string true "end time"
+// @Param	login_name	body string true "login name"
+// @Produce application/json
+// @Success 200 {string} string "{"trend_points":[{"operation_id":30,"host_ip":"192.168.137.11","port_num":3306,"weighted_average_score":95,"item_scores":{"cpu_usage":100,"db_config":80},"check_time":"2022-03-18T19:46:17+08:00"}]}"
+// @Router	/api/v1/healthcheck/trend [post]
+func GetTrend(c *gin.Context) {
+	var rd *utilhealth.Trend
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	startTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetStartTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetStartTime())
+		return
+	}
+	endTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetEndTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetEndTime())
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get trend
+	err = s.GetTrendByMySQLServerID(rd.GetServerID(), startTime, endTime, rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetTrend, err, rd.GetServerID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckTrendPointsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetTrend, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetTrend, rd.GetServerID())
+}
+
+// @Tags	healthcheck
+// @Summary get score trend of all the mysql servers of the mysql cluster
+// @Accept	application/json
+// @Param	token		body string true "token"
+// @Param	cluster_id	body int	true "mysql cluster id"
+// @Param	start_time	body string true "start time"
+// @Param	end_time	body string true "end time"
+// @Param	login_name	body string true "login name"
+// @Produce application/json
+// @Success 200 {string} string "{"trend_points":[{"operation_id":31,"host_ip":"192.168.137.11","port_num":3306,"weighted_average_score":95,"item_scores":{"cpu_usage":100,"db_config":80},"check_time":"2022-06-01T10:00:00+08:00"},{"operation_id":32,"host_ip":"192.168.137.12","port_num":3306,"weighted_average_score":85,"item_scores":{"cpu_usage":60,"db_config":100},"check_time":"2022-06-01T10:00:01+08:00"}]}"
+// @Router	/api/v1/healthcheck/trend/cluster [post]
+func GetClusterTrend(c *gin.Context) {
+	var rd *utilhealth.ClusterTrend
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	startTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetStartTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetStartTime())
+		return
+	}
+	endTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetEndTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetEndTime())
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get trend
+	err = s.GetTrendByMySQLClusterID(rd.GetClusterID(), startTime, endTime, rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetClusterTrend, err, rd.GetClusterID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckTrendPointsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetClusterTrend, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetClusterTrend, rd.GetClusterID())
+}
+
+// @Tags	healthcheck
+// @Summary get capacity forecasts of the mysql servers of the target
+// @Accept	application/json
+// @Param	token		body string true "token"
+// @Param	target_type	body int	true "target type, 1-mysql server, 2-mysql cluster, 3-resource group"
+// @Param	target_id	body int	true "target id"
+// @Param	start_time	body string true "start time"
+// @Param	end_time	body string true "end time"
+// @Param	login_name	body string true "login name"
+// @Produce application/json
+// @Success 200 {string} string "{"capacities":[{"mysql_server_id":1,"operation_id":30,"host_ip":"192.168.137.11","port_num":3306,"disk_forecasts":[{"mount_point":"/data","size":500,"used":350,"usage":0.7,"growth_per_day":2.5,"days_to_full":60,"value":0.85}],"table_growths":[{"db_name":"db01","table_name":"t01","table_size":120.5,"growth_per_day":1.2}],"days_to_full":60,"check_time":"2022-03-18T19:46:17+08:00"}]}"
+// @Router	/api/v1/healthcheck/capacity [post]
+func GetCapacity(c *gin.Context) {
+	var rd *utilhealth.Capacity
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	startTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetStartTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetStartTime())
+		return
+	}
+	endTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetEndTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetEndTime())
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get capacity
+	err = s.GetCapacity(rd.GetTargetType(), rd.GetTargetID(), startTime, endTime, rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetCapacity, err, rd.GetTargetType(), rd.GetTargetID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckCapacitiesStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetCapacity, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetCapacity, rd.GetTargetType(), rd.GetTargetID())
+}
+
+// @Tags	healthcheck
+// @Summary compare the result of the target operation with the result of the base operation
+// @Accept	application/json
+// @Param	token				body string true "token"
+// @Param	base_operation_id	body int	true "base operation id"
+// @Param	target_operation_id	body int	true "target operation id"
+// @Produce application/json
+// @Success 200 {string} string "{"result_diff":{"base_operation_id":30,"target_operation_id":35,"base_score":95,"target_score":88,"weighted_average_score_delta":-7,"item_score_diffs":[{"item_name":"db_config","base_score":80,"target_score":60,"delta":-20,"message":""}],"new_large_tables":[{"db_name":"db1","table_name":"t01","table_rows":10000000,"table_size":12.5}],"new_slow_queries":null,"config_changes":[{"variable_name":"sync_binlog","base_value":"1","target_value":"0"}]}}"
+// @Router	/api/v1/healthcheck/compare [post]
+func CompareResults(c *gin.Context) {
+	var rd *utilhealth.Compare
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// compare results
+	err = s.CompareResults(rd.GetBaseOperationID(), rd.GetTargetOperationID())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckCompareResults, err, rd.GetBaseOperationID(), rd.GetTargetOperationID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(healthcheckResultDiffStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckCompareResults, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckCompareResults, rd.GetBaseOperationID(), rd.GetTargetOperationID())
+}
+
+// @Tags	healthcheck
+// @Summary download the report of the healthcheck
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	operation_id	body int	true	"operation id"
+// @Param	format			body string false	"report format, html or markdown, default is markdown"
+// @Param	login_name		body string true	"login name"
+// @Produce text/html,text/markdown
+// @Success 200 {string} string "# DAS Healthcheck Report ..."
+// @Router	/api/v1/healthcheck/report [post]
+func GetReport(c *gin.Context) {
+	var rd *utilhealth.Report
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	format := rd.GetFormat()
+	if format == constant.EmptyString {
+		format = healthcheck.ReportFormatMarkdown
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// generate report
+	err = s.GenerateReport(rd.GetOperationID(), rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetReport, err, rd.GetOperationID())
+		return
+	}
+	content, err := s.GetReport().Render(format)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetReport, err, rd.GetOperationID())
+		return
+	}
+	// response
+	extension := reportMarkdownExtension
+	contentType := reportMarkdownContentType
+	if format == healthcheck.ReportFormatHTML {
+		extension = reportHTMLExtension
+		contentType = reportHTMLContentType
+	}
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetReport, rd.GetOperationID(), format).Error())
+	resp.ResponseAttachment(c, fmt.Sprintf(reportFileNameTemplate, rd.GetOperationID(), extension), contentType, []byte(content),
+		msghealth.InfoHealthcheckGetReport, rd.GetOperationID())
+}
+
+// @Tags	healthcheck
+// @Summary download the snapshot of the inputs collected by the healthcheck
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	operation_id	body int	true	"operation id"
+// @Param	login_name		body string true	"login name"
+// @Produce application/json
+// @Success 200 {string} string "{"version":1,"operation_id":1,"host_ip":"192.168.137.11","port_num":3306,"start_time":"2022-04-03T20:00:00+08:00","end_time":"2022-04-04T20:00:00+08:00","step":30,"variables":[{"variable_name":"sync_binlog","variable_value":"1"}],"mysql_dirs":["/data/mysql/data"],"large_tables":[],"db_names":null,"replication_status":[],"gtid_executed":null,"master_gtid_executed":null,"gtid_subtracts":null,"group_members":null,"file_systems":[{"mount_point":"/","device":"/dev/sda1"}],"prometheus_series":{"cpu_usage":[{"timestamp":"2022-04-03 20:00:00","value":10.5}]},"slow_queries":[]}"
+// @Router	/api/v1/healthcheck/snapshot [post]
+func GetSnapshot(c *gin.Context) {
+	var rd *utilhealth.Snapshot
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// get snapshot
+	err = s.GetSnapshotByOperationID(rd.GetOperationID(), rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetSnapshot, err, rd.GetOperationID())
+		return
+	}
+	jsonBytes, err := s.GetSnapshot().Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetSnapshot, errors.Trace(err), rd.GetOperationID())
+		return
+	}
+	// response
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetSnapshot, rd.GetOperationID()).Error())
+	resp.ResponseAttachment(c, fmt.Sprintf(snapshotFileNameTemplate, rd.GetOperationID()), snapshotContentType, jsonBytes,
+		msghealth.InfoHealthcheckGetSnapshot, rd.GetOperationID())
+}
+
+// @Tags	healthcheck
+// @Summary check health of the database with the uploaded snapshot instead of the live data sources
+// @Accept	application/json
+// @Param	token		body string true	"token"
+// @Param	snapshot	body object true	"snapshot, the content of the downloaded snapshot file"
+// @Param	login_name	body string true	"login name"
+// @Produce application/json
+// @Success 200 {string} string "{"operation_id": 17, "message": "healthcheck with snapshot started"}"
+// @Router	/api/v1/healthcheck/check/snapshot [post]
+func CheckWithSnapshot(c *gin.Context) {
+	var rd *utilhealth.CheckWithSnapshot
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewServiceWithDefault()
+	// check health
+	operationID, err := s.CheckWithSnapshot(rd.GetSnapshot(), rd.GetLoginName())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckCheckWithSnapshot, err, operationID)
+		return
+	}
+
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckCheckWithSnapshot, operationID).Error())
+	resp.ResponseOK(c, fmt.Sprintf(checkWithSnapshotRespMessage, operationID), msghealth.InfoHealthcheckCheckWithSnapshot, operationID)
+}
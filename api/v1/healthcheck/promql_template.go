@@ -0,0 +1,250 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghealth "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/das/pkg/resp"
+	utilhealth "github.com/romberli/das/pkg/util/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	promQLTemplateMonitorSystemIDJSON = "monitor_system_id"
+	promQLTemplateTemplateNameJSON    = "template_name"
+	promQLTemplateTemplateTextJSON    = "template_text"
+
+	promQLTemplateRenderedQueryStruct = "RenderedQuery"
+	promQLTemplateDatasStruct         = "Datas"
+)
+
+// @Tags	healthcheck
+// @Summary	get all overridden promql templates
+// @Accept	application/json
+// @Param	token body string true "token"
+// @Produce application/json
+// @Success	200 {string} string "{"promql_templates":[{"id":1,"monitor_system_id":1,"template_name":"cpu_usage","template_text":"avg(rate(node_cpu_seconds_total{node_name=~\"{{.NodeName}}\",mode!=\"idle\"}[{{.Window}}]))","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/promql/all [get]
+func GetPromQLTemplate(c *gin.Context) {
+	// init service
+	s := healthcheck.NewPromQLTemplateServiceWithDefault()
+	// get entities
+	err := s.GetAll()
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetPromQLTemplateAll, err)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetPromQLTemplateAll, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetPromQLTemplateAll)
+}
+
+// @Tags	healthcheck
+// @Summary	get overridden promql templates by monitor system id
+// @Accept	application/json
+// @Param	token				body string true "token"
+// @Param	monitor_system_id	body int	true "monitor system id"
+// @Produce application/json
+// @Success	200 {string} string "{"promql_templates":[{"id":1,"monitor_system_id":1,"template_name":"cpu_usage","template_text":"avg(rate(node_cpu_seconds_total{node_name=~\"{{.NodeName}}\",mode!=\"idle\"}[{{.Window}}]))","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/promql/monitor-system [get]
+func GetPromQLTemplateByMonitorSystemID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	monitorSystemID, err := jsonparser.GetInt(data, promQLTemplateMonitorSystemIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), promQLTemplateMonitorSystemIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewPromQLTemplateServiceWithDefault()
+	// get entities
+	err = s.GetByMonitorSystemID(int(monitorSystemID))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetPromQLTemplateByMonitorSystemID, err, monitorSystemID)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetPromQLTemplateByMonitorSystemID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetPromQLTemplateByMonitorSystemID, monitorSystemID)
+}
+
+// @Tags	healthcheck
+// @Summary	save promql template of the monitor system, it overrides the built-in template of the same name
+// @Accept	application/json
+// @Param	token				body string true "token"
+// @Param	monitor_system_id	body int	true "monitor system id"
+// @Param	template_name		body string true "template name, e.g. cpu_usage, io_util, disk_capacity"
+// @Param	template_text		body string true "template text, the variables are {{.ServiceName}}, {{.NodeName}}, {{.MountPoints}}, {{.Window}}, {{.Step}}, {{.NodeSelector}} and {{.ServiceSelector}}"
+// @Produce application/json
+// @Success	200 {string} string "{"promql_templates":[{"id":1,"monitor_system_id":1,"template_name":"cpu_usage","template_text":"avg(rate(node_cpu_seconds_total{node_name=~\"{{.NodeName}}\",mode!=\"idle\"}[{{.Window}}]))","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/promql/save [post]
+func SavePromQLTemplate(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	monitorSystemID, err := jsonparser.GetInt(data, promQLTemplateMonitorSystemIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), promQLTemplateMonitorSystemIDJSON)
+		return
+	}
+	templateName, err := jsonparser.GetString(data, promQLTemplateTemplateNameJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), promQLTemplateTemplateNameJSON)
+		return
+	}
+	templateText, err := jsonparser.GetString(data, promQLTemplateTemplateTextJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), promQLTemplateTemplateTextJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewPromQLTemplateServiceWithDefault()
+	// save entity
+	err = s.Save(int(monitorSystemID), templateName, templateText)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckSavePromQLTemplate, err, monitorSystemID, templateName)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckSavePromQLTemplate, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckSavePromQLTemplate, monitorSystemID, templateName)
+}
+
+// @Tags	healthcheck
+// @Summary	delete promql template of the monitor system, the built-in template will be used after that
+// @Accept	application/json
+// @Param	token				body string true "token"
+// @Param	monitor_system_id	body int	true "monitor system id"
+// @Param	template_name		body string true "template name"
+// @Produce application/json
+// @Success	200 {string} string "{"promql_templates":[{"id":1,"monitor_system_id":1,"template_name":"cpu_usage","template_text":"avg(rate(node_cpu_seconds_total{node_name=~\"{{.NodeName}}\",mode!=\"idle\"}[{{.Window}}]))","del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/promql/delete [post]
+func DeletePromQLTemplate(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	monitorSystemID, err := jsonparser.GetInt(data, promQLTemplateMonitorSystemIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), promQLTemplateMonitorSystemIDJSON)
+		return
+	}
+	templateName, err := jsonparser.GetString(data, promQLTemplateTemplateNameJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), promQLTemplateTemplateNameJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewPromQLTemplateServiceWithDefault()
+	// delete entity
+	err = s.Delete(int(monitorSystemID), templateName)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckDeletePromQLTemplate, err, monitorSystemID, templateName)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckDeletePromQLTemplate, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckDeletePromQLTemplate, monitorSystemID, templateName)
+}
+
+// @Tags	healthcheck
+// @Summary	render the promql template with the variables of the mysql server and execute it on the monitor system, it does not change any data
+// @Accept	application/json
+// @Param	token				body string		true	"token"
+// @Param	monitor_system_id	body int		true	"monitor system id"
+// @Param	server_id			body int		true	"mysql server id"
+// @Param	template_name		body string		true	"template name"
+// @Param	template_text		body string		false	"template text, the overridden template or the built-in template will be used if it is empty"
+// @Param	mount_points		body []string	false	"mount points, they are used by the disk templates"
+// @Param	start_time			body string		true	"start time"
+// @Param	end_time			body string		true	"end time"
+// @Param	step				body string		true	"step"
+// @Produce application/json
+// @Success	200 {string} string "{"rendered_query":"avg(rate(node_cpu_seconds_total{node_name=~\"192-168-10-219\",mode!=\"idle\"}[5m]))","datas":[{"timestamp":"1654048800","value":0.12}]}"
+// @Router	/api/v1/healthcheck/promql/dry-run [post]
+func PromQLTemplateDryRun(c *gin.Context) {
+	var rd *utilhealth.PromQLTemplateDryRun
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	startTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetStartTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetStartTime())
+		return
+	}
+	endTime, err := time.ParseInLocation(constant.TimeLayoutSecond, rd.GetEndTime(), time.Local)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeLayout, errors.Trace(err), rd.GetEndTime())
+		return
+	}
+	step, err := time.ParseDuration(rd.GetStep())
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrNotValidTimeDuration, errors.Trace(err), rd.GetStep())
+		return
+	}
+	// init service
+	s := healthcheck.NewPromQLTemplateServiceWithDefault()
+	// dry run
+	err = s.DryRun(rd.GetMonitorSystemID(), rd.GetServerID(), rd.GetTemplateName(), rd.GetTemplateText(), rd.GetMountPoints(),
+		startTime, endTime, step)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckPromQLTemplateDryRun, err, rd.GetMonitorSystemID(), rd.GetTemplateName())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(promQLTemplateRenderedQueryStruct, promQLTemplateDatasStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckPromQLTemplateDryRun, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckPromQLTemplateDryRun, rd.GetMonitorSystemID(), rd.GetTemplateName())
+}
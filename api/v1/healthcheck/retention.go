@@ -0,0 +1,201 @@
+package healthcheck
+
+import (
+	"github.com/buger/jsonparser"
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghealth "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/das/pkg/resp"
+	"github.com/romberli/log"
+)
+
+const (
+	retentionEnvIDJSON        = "env_id"
+	retentionFullDataDaysJSON = "full_data_days"
+
+	retentionRetentionStatsStruct = "RetentionStats"
+)
+
+// @Tags	healthcheck
+// @Summary	get all healthcheck retention policies
+// @Accept	application/json
+// @Param	token body string true "token"
+// @Produce application/json
+// @Success	200 {string} string "{"retention_policies":[{"id":1,"env_id":1,"full_data_days":180,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/retention/all [get]
+func GetRetention(c *gin.Context) {
+	// init service
+	s := healthcheck.NewRetentionServiceWithDefault()
+	// get entities
+	err := s.GetAll()
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetRetentionAll, err)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetRetentionAll, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetRetentionAll)
+}
+
+// @Tags	healthcheck
+// @Summary	get healthcheck retention policy by env id
+// @Accept	application/json
+// @Param	token	body string true "token"
+// @Param	env_id	body int	true "env id"
+// @Produce application/json
+// @Success	200 {string} string "{"retention_policies":[{"id":1,"env_id":1,"full_data_days":180,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/retention/env [get]
+func GetRetentionByEnvID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	envID, err := jsonparser.GetInt(data, retentionEnvIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), retentionEnvIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewRetentionServiceWithDefault()
+	// get entity
+	err = s.GetByEnvID(int(envID))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetRetentionByEnvID, err, envID)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetRetentionByEnvID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetRetentionByEnvID, envID)
+}
+
+// @Tags	healthcheck
+// @Summary	save healthcheck retention policy of the env, it will be created if it does not exist
+// @Accept	application/json
+// @Param	token			body string true "token"
+// @Param	env_id			body int	true "env id"
+// @Param	full_data_days	body int	true "days to keep the full data, only the scores will be kept after that"
+// @Produce application/json
+// @Success	200 {string} string "{"retention_policies":[{"id":1,"env_id":1,"full_data_days":180,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/retention/save [post]
+func SaveRetention(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	envID, err := jsonparser.GetInt(data, retentionEnvIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), retentionEnvIDJSON)
+		return
+	}
+	fullDataDays, err := jsonparser.GetInt(data, retentionFullDataDaysJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), retentionFullDataDaysJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewRetentionServiceWithDefault()
+	// save entity
+	err = s.Save(int(envID), int(fullDataDays))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckSaveRetention, err, envID)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckSaveRetention, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckSaveRetention, envID)
+}
+
+// @Tags	healthcheck
+// @Summary	delete healthcheck retention policy by env id, the env will use the default full data days
+// @Accept	application/json
+// @Param	token	body string true "token"
+// @Param	env_id	body int	true "env id"
+// @Produce application/json
+// @Success	200 {string} string "{"retention_policies":[{"id":1,"env_id":1,"full_data_days":180,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/retention/delete [post]
+func DeleteRetentionByEnvID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	envID, err := jsonparser.GetInt(data, retentionEnvIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), retentionEnvIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewRetentionServiceWithDefault()
+	// delete entity
+	err = s.DeleteByEnvID(int(envID))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckDeleteRetentionByEnvID, err, envID)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckDeleteRetentionByEnvID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckDeleteRetentionByEnvID, envID)
+}
+
+// @Tags	healthcheck
+// @Summary	report how many rows and bytes of each env would be archived by the retention policies, deleted_operation_num is the number of the operations which have no result, they are removed only if purging is enabled, it does not change any data
+// @Accept	application/json
+// @Param	token body string true "token"
+// @Produce application/json
+// @Success	200 {string} string "{"retention_stats":[{"env_id":1,"operation_num":1200,"deleted_operation_num":35,"result_num":1165,"item_result_num":20970,"snapshot_num":1100,"bytes":5368709120}]}"
+// @Router	/api/v1/healthcheck/retention/dry-run [post]
+func RetentionDryRun(c *gin.Context) {
+	// init service
+	s := healthcheck.NewRetentionServiceWithDefault()
+	// get statistics
+	err := s.DryRun()
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckRetentionDryRun, err)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.MarshalWithFields(retentionRetentionStatsStruct)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckRetentionDryRun, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckRetentionDryRun)
+}
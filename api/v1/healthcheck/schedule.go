@@ -0,0 +1,250 @@
+package healthcheck
+
+import (
+	"github.com/buger/jsonparser"
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghealth "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/das/pkg/resp"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	scheduleIDJSON           = "id"
+	scheduleScheduleNameJSON = "schedule_name"
+
+	scheduleIDStruct           = "ID"
+	scheduleScheduleNameStruct = "ScheduleName"
+)
+
+// @Tags	healthcheck
+// @Summary	get all healthcheck schedules
+// @Accept	application/json
+// @Param	token body string true "token"
+// @Produce application/json
+// @Success	200 {string} string "{"schedules":[{"id":1,"schedule_name":"weekly-online","cron_expression":"0 2 * * 1","target_type":4,"target_id":1,"lookback":604800,"step":60,"login_name":"zhangs","enabled":1,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/schedule/all [get]
+func GetSchedule(c *gin.Context) {
+	// init service
+	s := healthcheck.NewScheduleServiceWithDefault()
+	// get entities
+	err := s.GetAll()
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetScheduleAll, err)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetScheduleAll, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetScheduleAll)
+}
+
+// @Tags	healthcheck
+// @Summary	get healthcheck schedule by id
+// @Accept	application/json
+// @Param	token	body string true "token"
+// @Param	id		body int	true "schedule id"
+// @Produce application/json
+// @Success	200 {string} string "{"schedules":[{"id":1,"schedule_name":"weekly-online","cron_expression":"0 2 * * 1","target_type":4,"target_id":1,"lookback":604800,"step":60,"login_name":"zhangs","enabled":1,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/schedule/id [get]
+func GetScheduleByID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	id, err := jsonparser.GetInt(data, scheduleIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), scheduleIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewScheduleServiceWithDefault()
+	// get entity
+	err = s.GetByID(int(id))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetScheduleByID, err, id)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetScheduleByID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetScheduleByID, id)
+}
+
+// @Tags	healthcheck
+// @Summary	add a new healthcheck schedule
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	schedule_name	body string true	"schedule name"
+// @Param	cron_expression	body string true	"cron expression, format: minute hour day-of-month month day-of-week, day of week is in [0, 6] and 0 means sunday"
+// @Param	target_type		body int	true	"target type, 1-mysql server, 2-mysql cluster, 3-resource group, 4-env"
+// @Param	target_id		body int	true	"target id"
+// @Param	lookback		body int	true	"lookback, the unit is seconds"
+// @Param	step			body int	true	"step, the unit is seconds"
+// @Param	login_name		body string true	"login name of the user who runs the healthcheck"
+// @Param	enabled			body int	false	"enabled, 0-disabled, 1-enabled"
+// @Produce application/json
+// @Success	200 {string} string "{"schedules":[{"id":1,"schedule_name":"weekly-online","cron_expression":"0 2 * * 1","target_type":4,"target_id":1,"lookback":604800,"step":60,"login_name":"zhangs","enabled":1,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/schedule/add [post]
+func AddSchedule(c *gin.Context) {
+	var fields map[string]interface{}
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	// unmarshal data
+	fields, err = common.UnmarshalToMapWithStructTag(data, &healthcheck.Schedule{}, constant.DefaultMiddlewareTag)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, err)
+		return
+	}
+	_, ok := fields[scheduleScheduleNameStruct]
+	if !ok {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, scheduleScheduleNameJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewScheduleServiceWithDefault()
+	// insert into middleware
+	err = s.Create(fields)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckAddSchedule, err, fields[scheduleScheduleNameStruct])
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckAddSchedule, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckAddSchedule, fields[scheduleScheduleNameStruct])
+}
+
+// @Tags	healthcheck
+// @Summary	update healthcheck schedule by id
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	id				body int	true	"schedule id"
+// @Param	schedule_name	body string false	"schedule name"
+// @Param	cron_expression	body string false	"cron expression, format: minute hour day-of-month month day-of-week, day of week is in [0, 6] and 0 means sunday"
+// @Param	target_type		body int	false	"target type, 1-mysql server, 2-mysql cluster, 3-resource group, 4-env"
+// @Param	target_id		body int	false	"target id"
+// @Param	lookback		body int	false	"lookback, the unit is seconds"
+// @Param	step			body int	false	"step, the unit is seconds"
+// @Param	login_name		body string false	"login name of the user who runs the healthcheck"
+// @Param	enabled			body int	false	"enabled, 0-disabled, 1-enabled"
+// @Param	del_flag		body int	false	"delete flag"
+// @Produce application/json
+// @Success	200 {string} string "{"schedules":[{"id":1,"schedule_name":"weekly-online","cron_expression":"0 3 * * 1","target_type":4,"target_id":1,"lookback":604800,"step":60,"login_name":"zhangs","enabled":1,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-02T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/schedule/update [post]
+func UpdateScheduleByID(c *gin.Context) {
+	var fields map[string]interface{}
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	// unmarshal data
+	fields, err = common.UnmarshalToMapWithStructTag(data, &healthcheck.Schedule{}, constant.DefaultMiddlewareTag)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, err)
+		return
+	}
+	idInterface, idExists := fields[scheduleIDStruct]
+	if !idExists {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, scheduleIDJSON)
+		return
+	}
+	id, ok := idInterface.(int)
+	if !ok {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, scheduleIDJSON)
+		return
+	}
+	delete(fields, scheduleIDStruct)
+	if len(fields) == constant.ZeroInt {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, "fields to be updated")
+		return
+	}
+	// init service
+	s := healthcheck.NewScheduleServiceWithDefault()
+	// update entity
+	err = s.Update(id, fields)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckUpdateSchedule, err, id)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckUpdateSchedule, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckUpdateSchedule, id)
+}
+
+// @Tags	healthcheck
+// @Summary	delete healthcheck schedule by id
+// @Accept	application/json
+// @Param	token	body string true "token"
+// @Param	id		body int	true "schedule id"
+// @Produce application/json
+// @Success	200 {string} string "{"schedules":[{"id":1,"schedule_name":"weekly-online","cron_expression":"0 2 * * 1","target_type":4,"target_id":1,"lookback":604800,"step":60,"login_name":"zhangs","enabled":1,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/schedule/delete [post]
+func DeleteScheduleByID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	id, err := jsonparser.GetInt(data, scheduleIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), scheduleIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewScheduleServiceWithDefault()
+	// delete entity
+	err = s.Delete(int(id))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckDeleteScheduleByID, err, id)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckDeleteScheduleByID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckDeleteScheduleByID, id)
+}
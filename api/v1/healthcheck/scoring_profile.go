@@ -0,0 +1,294 @@
+package healthcheck
+
+import (
+	"fmt"
+
+	"github.com/buger/jsonparser"
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghealth "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/das/pkg/resp"
+	utilhealth "github.com/romberli/das/pkg/util/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	scoringProfileIDJSON = "id"
+
+	unassignScoringProfileRespMessage = `{"target_type": %d, "target_id": %d, "message": "unassigned scoring profile completed"}`
+)
+
+// @Tags	healthcheck
+// @Summary	get all healthcheck scoring profiles
+// @Accept	application/json
+// @Param	token body string true "token"
+// @Produce application/json
+// @Success	200 {string} string "{"scoring_profiles":[{"id":1,"profile_name":"online-payment","description":"stricter watermarks for the online payment clusters","item_configs":[],"assignments":[],"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/profile/all [post]
+func GetScoringProfile(c *gin.Context) {
+	// init service
+	s := healthcheck.NewScoringProfileServiceWithDefault()
+	// get entities
+	err := s.GetAll()
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetScoringProfileAll, err)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetScoringProfileAll, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetScoringProfileAll)
+}
+
+// @Tags	healthcheck
+// @Summary	get healthcheck scoring profile by id, the item configs and the assignments are included
+// @Accept	application/json
+// @Param	token	body string true "token"
+// @Param	id		body int	true "scoring profile id"
+// @Produce application/json
+// @Success	200 {string} string "{"scoring_profiles":[{"id":1,"profile_name":"online-payment","description":"stricter watermarks for the online payment clusters","item_configs":[{"id":101,"item_name":"cpu_usage","item_weight":5,"low_watermark":0.4,"high_watermark":0.7,"unit":0.1,"score_deduction_per_unit_high":20,"max_score_deduction_high":100,"score_deduction_per_unit_medium":10,"max_score_deduction_medium":50,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}],"assignments":[{"id":1,"profile_id":1,"target_type":2,"target_id":1}],"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/profile/id [post]
+func GetScoringProfileByID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	id, err := jsonparser.GetInt(data, scoringProfileIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), scoringProfileIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewScoringProfileServiceWithDefault()
+	// get entity
+	err = s.GetByID(int(id))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckGetScoringProfileByID, err, id)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckGetScoringProfileByID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckGetScoringProfileByID, id)
+}
+
+// @Tags	healthcheck
+// @Summary	add a new healthcheck scoring profile
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	profile_name	body string true	"scoring profile name"
+// @Param	description		body string false	"description"
+// @Param	item_configs	body array	true	"item configs, it must be a full item config set which passes the engine config validation"
+// @Produce application/json
+// @Success	200 {string} string "{"scoring_profiles":[{"id":1,"profile_name":"online-payment","description":"stricter watermarks for the online payment clusters","item_configs":[{"id":101,"item_name":"cpu_usage","item_weight":5,"low_watermark":0.4,"high_watermark":0.7,"unit":0.1,"score_deduction_per_unit_high":20,"max_score_deduction_high":100,"score_deduction_per_unit_medium":10,"max_score_deduction_medium":50,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}],"assignments":[{"id":1,"profile_id":1,"target_type":2,"target_id":1}],"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/profile/add [post]
+func AddScoringProfile(c *gin.Context) {
+	var rd *utilhealth.ScoringProfile
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	itemConfigs, err := healthcheck.NewDefaultItemConfigsWithJSON(rd.GetItemConfigs())
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, err)
+		return
+	}
+	// init service
+	s := healthcheck.NewScoringProfileServiceWithDefault()
+	// insert into middleware
+	err = s.Create(rd.GetProfileName(), rd.GetDescription(), itemConfigs)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckAddScoringProfile, err, rd.GetProfileName())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckAddScoringProfile, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckAddScoringProfile, rd.GetProfileName())
+}
+
+// @Tags	healthcheck
+// @Summary	update healthcheck scoring profile by id, the item configs of the profile will be replaced
+// @Accept	application/json
+// @Param	token			body string true	"token"
+// @Param	id				body int	true	"scoring profile id"
+// @Param	profile_name	body string true	"scoring profile name"
+// @Param	description		body string false	"description"
+// @Param	item_configs	body array	true	"item configs, it must be a full item config set which passes the engine config validation"
+// @Produce application/json
+// @Success	200 {string} string "{"scoring_profiles":[{"id":1,"profile_name":"online-payment","description":"stricter watermarks for the online payment clusters","item_configs":[{"id":101,"item_name":"cpu_usage","item_weight":5,"low_watermark":0.4,"high_watermark":0.7,"unit":0.1,"score_deduction_per_unit_high":20,"max_score_deduction_high":100,"score_deduction_per_unit_medium":10,"max_score_deduction_medium":50,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}],"assignments":[{"id":1,"profile_id":1,"target_type":2,"target_id":1}],"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/profile/update [post]
+func UpdateScoringProfileByID(c *gin.Context) {
+	var rd *utilhealth.ScoringProfile
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	if rd.GetID() == constant.ZeroInt {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, scoringProfileIDJSON)
+		return
+	}
+	itemConfigs, err := healthcheck.NewDefaultItemConfigsWithJSON(rd.GetItemConfigs())
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, err)
+		return
+	}
+	// init service
+	s := healthcheck.NewScoringProfileServiceWithDefault()
+	// update entity
+	err = s.Update(rd.GetID(), rd.GetProfileName(), rd.GetDescription(), itemConfigs)
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckUpdateScoringProfile, err, rd.GetID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckUpdateScoringProfile, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckUpdateScoringProfile, rd.GetID())
+}
+
+// @Tags	healthcheck
+// @Summary	delete healthcheck scoring profile by id, the item configs and the assignments of the profile will also be deleted
+// @Accept	application/json
+// @Param	token	body string true "token"
+// @Param	id		body int	true "scoring profile id"
+// @Produce application/json
+// @Success	200 {string} string "{"scoring_profiles":[{"id":1,"profile_name":"online-payment","description":"stricter watermarks for the online payment clusters","item_configs":[{"id":101,"item_name":"cpu_usage","item_weight":5,"low_watermark":0.4,"high_watermark":0.7,"unit":0.1,"score_deduction_per_unit_high":20,"max_score_deduction_high":100,"score_deduction_per_unit_medium":10,"max_score_deduction_medium":50,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}],"assignments":[{"id":1,"profile_id":1,"target_type":2,"target_id":1}],"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/profile/delete [post]
+func DeleteScoringProfileByID(c *gin.Context) {
+	// get data
+	data, err := c.GetRawData()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrGetRawData, errors.Trace(err))
+		return
+	}
+	id, err := jsonparser.GetInt(data, scoringProfileIDJSON)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrFieldNotExistsOrWrongType, errors.Trace(err), scoringProfileIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewScoringProfileServiceWithDefault()
+	// delete entity
+	err = s.Delete(int(id))
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckDeleteScoringProfileByID, err, id)
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckDeleteScoringProfileByID, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckDeleteScoringProfileByID, id)
+}
+
+// @Tags	healthcheck
+// @Summary	assign healthcheck scoring profile to an env, a mysql cluster or a resource group, the former assignment of the target will be replaced
+// @Accept	application/json
+// @Param	token		body string true "token"
+// @Param	id			body int	true "scoring profile id"
+// @Param	target_type	body int	true "target type, 2-mysql cluster, 3-resource group, 4-env"
+// @Param	target_id	body int	true "target id"
+// @Produce application/json
+// @Success	200 {string} string "{"scoring_profiles":[{"id":1,"profile_name":"online-payment","description":"stricter watermarks for the online payment clusters","item_configs":[{"id":101,"item_name":"cpu_usage","item_weight":5,"low_watermark":0.4,"high_watermark":0.7,"unit":0.1,"score_deduction_per_unit_high":20,"max_score_deduction_high":100,"score_deduction_per_unit_medium":10,"max_score_deduction_medium":50,"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}],"assignments":[{"id":1,"profile_id":1,"target_type":2,"target_id":1}],"del_flag":0,"create_time":"2022-06-01T10:00:00+08:00","last_update_time":"2022-06-01T10:00:00+08:00"}]}"
+// @Router	/api/v1/healthcheck/profile/assign [post]
+func AssignScoringProfile(c *gin.Context) {
+	var rd *utilhealth.ScoringProfileAssignment
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	if rd.GetID() == constant.ZeroInt {
+		resp.ResponseNOK(c, message.ErrFieldNotExists, scoringProfileIDJSON)
+		return
+	}
+	// init service
+	s := healthcheck.NewScoringProfileServiceWithDefault()
+	// assign profile
+	err = s.Assign(rd.GetID(), rd.GetTargetType(), rd.GetTargetID())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckAssignScoringProfile, err, rd.GetID(), rd.GetTargetType(), rd.GetTargetID())
+		return
+	}
+	// marshal service
+	jsonBytes, err := s.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	// response
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckAssignScoringProfile, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckAssignScoringProfile, rd.GetID(), rd.GetTargetType(), rd.GetTargetID())
+}
+
+// @Tags	healthcheck
+// @Summary	remove the healthcheck scoring profile assignment of an env, a mysql cluster or a resource group
+// @Accept	application/json
+// @Param	token		body string true "token"
+// @Param	target_type	body int	true "target type, 2-mysql cluster, 3-resource group, 4-env"
+// @Param	target_id	body int	true "target id"
+// @Produce application/json
+// @Success	200 {string} string "{"target_type": 2, "target_id": 1, "message": "unassigned scoring profile completed"}"
+// @Router	/api/v1/healthcheck/profile/unassign [post]
+func UnassignScoringProfile(c *gin.Context) {
+	var rd *utilhealth.ScoringProfileAssignment
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	s := healthcheck.NewScoringProfileServiceWithDefault()
+	// unassign profile
+	err = s.Unassign(rd.GetTargetType(), rd.GetTargetID())
+	if err != nil {
+		resp.ResponseNOK(c, msghealth.ErrHealthcheckUnassignScoringProfile, err, rd.GetTargetType(), rd.GetTargetID())
+		return
+	}
+	// response
+	jsonStr := fmt.Sprintf(unassignScoringProfileRespMessage, rd.GetTargetType(), rd.GetTargetID())
+	log.Debug(message.NewMessage(msghealth.DebugHealthcheckUnassignScoringProfile, jsonStr).Error())
+	resp.ResponseOK(c, jsonStr, msghealth.InfoHealthcheckUnassignScoringProfile, rd.GetTargetType(), rd.GetTargetID())
+}
@@ -190,7 +190,7 @@ func GetMonitorSystemByHostInfo(c *gin.Context) {
 // @Accept	application/json
 // @Param	token         body string true "token"
 // @Param	system_name	  body string true	"system name"
-// @Param 	system_type   body int    true	"system type"
+// @Param 	system_type   body int    true	"system type, 1: pmm 1.x, 2: pmm 2.x, 3: generic prometheus, the slow queries are read from the performance_schema of the mysql server"
 // @Param 	host_ip       body string true	"host ip"
 // @Param 	port_num      body int    true	"port num"
 // @Param 	port_num_slow body int    true	"port num slow"
@@ -258,7 +258,7 @@ func AddMonitorSystem(c *gin.Context) {
 // @Param	token         body string true "token"
 // @Param	id		      body int	  true	"monitor system id"
 // @Param	system_name	  body string false	"system name"
-// @Param 	system_type   body int    false	"system type"
+// @Param 	system_type   body int    false	"system type, 1: pmm 1.x, 2: pmm 2.x, 3: generic prometheus, the slow queries are read from the performance_schema of the mysql server"
 // @Param 	host_ip       body string false	"host ip"
 // @Param 	port_num      body int    false	"port num"
 // @Param 	port_num_slow body int    false	"port num slow"
@@ -1,6 +1,8 @@
 package query
 
 import (
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/errors"
 	"github.com/romberli/das/internal/app/query"
@@ -239,3 +241,41 @@ func GetBySQLID(c *gin.Context) {
 	// response
 	resp.ResponseOK(c, jsonStr, msgquery.InfoQueryGetBySQLID, rd.GetSQLID())
 }
+
+// @Tags query
+// @Summary ingest the slow log of the mysql server, the standard format and the percona extended format are supported, the entries which were ingested before are skipped, the request body must not be larger than 32MB, the larger slow log files should be ingested with the slowlog command
+// @Accept  application/json
+// @Param	token	 		body string true "token"
+// @Param	mysql_server_id	body int	true "mysql server id"
+// @Param	content			body string	true "slow log content"
+// @Produce application/json
+// @Success 200 {string} string "{"entry_count":3,"skipped_count":1,"stat_count":1}"
+// @Router	/api/v1/query/slow-log/ingest [post]
+func IngestSlowLog(c *gin.Context) {
+	var rd *utilquery.SlowLogIngestion
+	// bind json
+	err := c.ShouldBindJSON(&rd)
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrUnmarshalRawData, errors.Trace(err))
+		return
+	}
+	// init service
+	service := query.NewSlowLogServiceWithDefault()
+	err = service.Ingest(rd.GetMySQLServerID(), strings.NewReader(rd.GetContent()))
+	if err != nil {
+		resp.ResponseNOK(c, msgquery.ErrQueryIngestSlowLog, err, rd.GetMySQLServerID())
+		return
+	}
+
+	// marshal
+	jsonBytes, err := service.Marshal()
+	if err != nil {
+		resp.ResponseNOK(c, message.ErrMarshalData, err)
+		return
+	}
+	jsonStr := string(jsonBytes)
+	log.Debug(message.NewMessage(msgquery.DebugQueryIngestSlowLog, rd.GetMySQLServerID(), jsonStr).Error())
+
+	// response
+	resp.ResponseOK(c, jsonStr, msgquery.InfoQueryIngestSlowLog, rd.GetMySQLServerID(), service.GetEntryCount(), service.GetSkippedCount(), service.GetStatCount())
+}
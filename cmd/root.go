@@ -71,6 +71,10 @@ var (
 	dbMonitorMySQLPass       string
 	dbApplicationMySQLUser   string
 	dbApplicationMySQLPass   string
+	dbMonitorNodeLabel       string
+	dbMonitorServiceLabel    string
+	dbMonitorNodeJob         string
+	dbMonitorMySQLJob        string
 	// privilege
 	privilegeEnabledStr string
 	// metadata
@@ -86,8 +90,24 @@ var (
 	alertHTTPURL        string
 	alertHTTPConfig     string
 	// healthcheck
-	healthcheckMaxRange       int
-	healthcheckAlertOwnerType string
+	healthcheckMaxRange           int
+	healthcheckAlertOwnerType     string
+	healthcheckOwnerThreshold     int
+	healthcheckScheduleEnabledStr string
+	healthcheckTimeout            int
+	healthcheckSnapshotEnabledStr string
+	healthcheckConcurrency        int
+	healthcheckSecurityAdmins     string
+	healthcheckSecurityExempts    string
+	healthcheckForecastDays       int
+	healthcheckCallbackSecret     string
+	healthcheckCallbackTimeout    int
+	healthcheckCallbackHosts      string
+	healthcheckArchiveEnabledStr  string
+	healthcheckRetentionDays      int
+	healthcheckRetentionBatchSize int
+	healthcheckRetentionInterval  int
+	healthcheckPurgeEnabledStr    string
 	// query
 	queryMinRowsExamined int
 	// sqladvisor
@@ -175,6 +195,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&dbMonitorClickhousePass, "db-monitor-clickhouse-pass", constant.DefaultRandomString, fmt.Sprintf("specify clickhouse user password of monitor system(default: %s)", config.DefaultDBPass))
 	rootCmd.PersistentFlags().StringVar(&dbMonitorMySQLUser, "db-monitor-mysql-user", constant.DefaultRandomString, fmt.Sprintf("specify mysql user name of monitor system(default: %s)", config.DefaultDBUser))
 	rootCmd.PersistentFlags().StringVar(&dbMonitorMySQLPass, "db-monitor-mysql-pass", constant.DefaultRandomString, fmt.Sprintf("specify mysql user password of monitor system(default: %s)", config.DefaultDBPass))
+	rootCmd.PersistentFlags().StringVar(&dbMonitorNodeLabel, "db-monitor-generic-node-label", constant.DefaultRandomString, fmt.Sprintf("specify the label which identifies the node of the node exporter series of the generic prometheus monitor system(default: %s)", config.DefaultDBMonitorGenericNodeLabel))
+	rootCmd.PersistentFlags().StringVar(&dbMonitorServiceLabel, "db-monitor-generic-service-label", constant.DefaultRandomString, fmt.Sprintf("specify the label which identifies the mysql server of the mysqld exporter series of the generic prometheus monitor system(default: %s)", config.DefaultDBMonitorGenericServiceLabel))
+	rootCmd.PersistentFlags().StringVar(&dbMonitorNodeJob, "db-monitor-generic-node-job", constant.DefaultRandomString, fmt.Sprintf("specify the job of the node exporter of the generic prometheus monitor system(default: %s)", config.DefaultDBMonitorGenericNodeJob))
+	rootCmd.PersistentFlags().StringVar(&dbMonitorMySQLJob, "db-monitor-generic-mysql-job", constant.DefaultRandomString, fmt.Sprintf("specify the job of the mysqld exporter of the generic prometheus monitor system(default: %s)", config.DefaultDBMonitorGenericMySQLJob))
 	// privilege
 	rootCmd.PersistentFlags().StringVar(&privilegeEnabledStr, "privilege-enabled", constant.DefaultRandomString, fmt.Sprintf("specify if enables privilege module(default: %s)", constant.TrueString))
 	// metadata
@@ -192,6 +216,22 @@ func init() {
 	// healthcheck
 	rootCmd.PersistentFlags().IntVar(&healthcheckMaxRange, "healthcheck-max-range", constant.DefaultRandomInt, fmt.Sprintf("specify healthcheck maximum range(default: %d)", config.DefaultHealthCheckMaxRange))
 	rootCmd.PersistentFlags().StringVar(&healthcheckAlertOwnerType, "healthcheck-alert-owner-type", constant.DefaultRandomString, fmt.Sprintf("specify healthcheck alert owner type(default: %s)", config.DefaultHealthcheckAlertOwnerType))
+	rootCmd.PersistentFlags().IntVar(&healthcheckOwnerThreshold, "healthcheck-alert-owner-threshold", constant.DefaultRandomInt, fmt.Sprintf("specify the score below which the owners are alerted besides the requester(default: %d)", config.DefaultHealthcheckAlertOwnerThreshold))
+	rootCmd.PersistentFlags().StringVar(&healthcheckScheduleEnabledStr, "healthcheck-schedule-enabled", constant.DefaultRandomString, fmt.Sprintf("specify if enables healthcheck scheduler(default: %s)", constant.FalseString))
+	rootCmd.PersistentFlags().IntVar(&healthcheckTimeout, "healthcheck-timeout", constant.DefaultRandomInt, fmt.Sprintf("specify the timeout in seconds of each healthcheck operation(default: %d)", config.DefaultHealthcheckTimeout))
+	rootCmd.PersistentFlags().StringVar(&healthcheckSnapshotEnabledStr, "healthcheck-snapshot-enabled", constant.DefaultRandomString, fmt.Sprintf("specify if saves the snapshot of the collected inputs of each healthcheck operation(default: %s)", constant.FalseString))
+	rootCmd.PersistentFlags().IntVar(&healthcheckConcurrency, "healthcheck-concurrency", constant.DefaultRandomInt, fmt.Sprintf("specify the maximum number of healthcheck operations which run concurrently(default: %d)", config.DefaultHealthcheckConcurrency))
+	rootCmd.PersistentFlags().StringVar(&healthcheckSecurityAdmins, "healthcheck-security-admin-accounts", constant.DefaultRandomString, fmt.Sprintf("specify the comma separated admin accounts which are allowed to have super, file and grant option privileges(default: %s)", config.DefaultHealthcheckSecurityAdminAccounts))
+	rootCmd.PersistentFlags().StringVar(&healthcheckSecurityExempts, "healthcheck-security-exempt-accounts", constant.DefaultRandomString, "specify the comma separated accounts which are not checked by the security item(default: \"\")")
+	rootCmd.PersistentFlags().IntVar(&healthcheckForecastDays, "healthcheck-capacity-forecast-days", constant.DefaultRandomInt, fmt.Sprintf("specify the days after which the disk usage is projected by the disk capacity forecast item(default: %d)", config.DefaultHealthcheckCapacityForecastDays))
+	rootCmd.PersistentFlags().StringVar(&healthcheckCallbackSecret, "healthcheck-callback-secret", constant.DefaultRandomString, "specify the secret which is used to sign the payload of the healthcheck callbacks(default: \"\")")
+	rootCmd.PersistentFlags().IntVar(&healthcheckCallbackTimeout, "healthcheck-callback-timeout", constant.DefaultRandomInt, fmt.Sprintf("specify the timeout in seconds of each healthcheck callback request(default: %d)", config.DefaultHealthcheckCallbackTimeout))
+	rootCmd.PersistentFlags().StringVar(&healthcheckCallbackHosts, "healthcheck-callback-allowed-hosts", constant.DefaultRandomString, "specify the comma separated hosts which the healthcheck callback urls are allowed to point to, the callbacks are disabled if it is empty(default: \"\")")
+	rootCmd.PersistentFlags().StringVar(&healthcheckArchiveEnabledStr, "healthcheck-retention-enabled", constant.DefaultRandomString, fmt.Sprintf("specify if enables the background job which archives the expired healthcheck data(default: %s)", constant.FalseString))
+	rootCmd.PersistentFlags().IntVar(&healthcheckRetentionDays, "healthcheck-retention-full-data-days", constant.DefaultRandomInt, fmt.Sprintf("specify the days to keep the full healthcheck data, only the scores will be kept after that(default: %d)", config.DefaultHealthcheckRetentionFullDataDays))
+	rootCmd.PersistentFlags().IntVar(&healthcheckRetentionBatchSize, "healthcheck-retention-batch-size", constant.DefaultRandomInt, fmt.Sprintf("specify the number of healthcheck operations which are archived in each batch(default: %d)", config.DefaultHealthcheckRetentionBatchSize))
+	rootCmd.PersistentFlags().IntVar(&healthcheckRetentionInterval, "healthcheck-retention-batch-interval", constant.DefaultRandomInt, fmt.Sprintf("specify the interval in milliseconds between the archive batches(default: %d)", config.DefaultHealthcheckRetentionBatchInterval))
+	rootCmd.PersistentFlags().StringVar(&healthcheckPurgeEnabledStr, "healthcheck-retention-purge-enabled", constant.DefaultRandomString, fmt.Sprintf("specify if purges the archived healthcheck operations which have no result together with their item results and feedbacks(default: %s)", constant.FalseString))
 	// query
 	rootCmd.PersistentFlags().IntVar(&queryMinRowsExamined, "query-min-rows-examined", constant.DefaultRandomInt, fmt.Sprintf("specify query min rows examined(default: %d", config.DefaultQueryMinRowsExamined))
 	// sqladvisor
@@ -390,6 +430,18 @@ func OverrideConfig() (err error) {
 	if dbMonitorMySQLPass != constant.DefaultRandomString {
 		viper.Set(config.DBMonitorMySQLPassKey, dbMonitorMySQLPass)
 	}
+	if dbMonitorNodeLabel != constant.DefaultRandomString {
+		viper.Set(config.DBMonitorGenericNodeLabelKey, dbMonitorNodeLabel)
+	}
+	if dbMonitorServiceLabel != constant.DefaultRandomString {
+		viper.Set(config.DBMonitorGenericServiceLabelKey, dbMonitorServiceLabel)
+	}
+	if dbMonitorNodeJob != constant.DefaultRandomString {
+		viper.Set(config.DBMonitorGenericNodeJobKey, dbMonitorNodeJob)
+	}
+	if dbMonitorMySQLJob != constant.DefaultRandomString {
+		viper.Set(config.DBMonitorGenericMySQLJobKey, dbMonitorMySQLJob)
+	}
 	if dbApplicationMySQLUser != constant.DefaultRandomString {
 		viper.Set(config.DBApplicationMySQLUserKey, dbApplicationMySQLUser)
 	}
@@ -457,6 +509,74 @@ func OverrideConfig() (err error) {
 	if healthcheckAlertOwnerType != constant.DefaultRandomString {
 		viper.Set(config.HealthcheckAlertOwnerTypeKey, healthcheckAlertOwnerType)
 	}
+	if healthcheckOwnerThreshold != constant.DefaultRandomInt {
+		viper.Set(config.HealthcheckAlertOwnerThresholdKey, healthcheckOwnerThreshold)
+	}
+	if healthcheckScheduleEnabledStr != constant.DefaultRandomString {
+		healthcheckScheduleEnabled, err := cast.ToBoolE(healthcheckScheduleEnabledStr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		viper.Set(config.HealthcheckScheduleEnabledKey, healthcheckScheduleEnabled)
+	}
+	if healthcheckTimeout != constant.DefaultRandomInt {
+		viper.Set(config.HealthcheckTimeoutKey, healthcheckTimeout)
+	}
+	if healthcheckSnapshotEnabledStr != constant.DefaultRandomString {
+		healthcheckSnapshotEnabled, err := cast.ToBoolE(healthcheckSnapshotEnabledStr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		viper.Set(config.HealthcheckSnapshotEnabledKey, healthcheckSnapshotEnabled)
+	}
+	if healthcheckConcurrency != constant.DefaultRandomInt {
+		viper.Set(config.HealthcheckConcurrencyKey, healthcheckConcurrency)
+	}
+	if healthcheckSecurityAdmins != constant.DefaultRandomString {
+		viper.Set(config.HealthcheckSecurityAdminAccountsKey, healthcheckSecurityAdmins)
+	}
+	if healthcheckSecurityExempts != constant.DefaultRandomString {
+		viper.Set(config.HealthcheckSecurityExemptAccountsKey, healthcheckSecurityExempts)
+	}
+	if healthcheckForecastDays != constant.DefaultRandomInt {
+		viper.Set(config.HealthcheckCapacityForecastDaysKey, healthcheckForecastDays)
+	}
+	if healthcheckCallbackSecret != constant.DefaultRandomString {
+		viper.Set(config.HealthcheckCallbackSecretKey, healthcheckCallbackSecret)
+	}
+	if healthcheckCallbackTimeout != constant.DefaultRandomInt {
+		viper.Set(config.HealthcheckCallbackTimeoutKey, healthcheckCallbackTimeout)
+	}
+	if healthcheckCallbackHosts != constant.DefaultRandomString {
+		viper.Set(config.HealthcheckCallbackAllowedHostsKey, healthcheckCallbackHosts)
+	}
+	if healthcheckArchiveEnabledStr != constant.DefaultRandomString {
+		healthcheckArchiveEnabled, err := cast.ToBoolE(healthcheckArchiveEnabledStr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		viper.Set(config.HealthcheckRetentionEnabledKey, healthcheckArchiveEnabled)
+	}
+	if healthcheckRetentionDays != constant.DefaultRandomInt {
+		viper.Set(config.HealthcheckRetentionFullDataDaysKey, healthcheckRetentionDays)
+	}
+	if healthcheckRetentionBatchSize != constant.DefaultRandomInt {
+		viper.Set(config.HealthcheckRetentionBatchSizeKey, healthcheckRetentionBatchSize)
+	}
+	if healthcheckRetentionInterval != constant.DefaultRandomInt {
+		viper.Set(config.HealthcheckRetentionBatchIntervalKey, healthcheckRetentionInterval)
+	}
+	if healthcheckPurgeEnabledStr != constant.DefaultRandomString {
+		healthcheckPurgeEnabled, err := cast.ToBoolE(healthcheckPurgeEnabledStr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		viper.Set(config.HealthcheckRetentionPurgeEnabledKey, healthcheckPurgeEnabled)
+	}
 
 	// override query
 	if queryMinRowsExamined != constant.DefaultRandomInt {
@@ -0,0 +1,96 @@
+/*
+Copyright © 2020 Romber Li <romber2001@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/app/query"
+	"github.com/romberli/das/pkg/message"
+	msgquery "github.com/romberli/das/pkg/message/query"
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// slow log
+	slowLogFile          string
+	slowLogMySQLServerID int
+)
+
+// slowLogCmd represents the slowlog command
+var slowLogCmd = &cobra.Command{
+	Use:   "slowlog",
+	Short: "slowlog command",
+	Long: `ingest the slow log file of the mysql server,
+the standard format and the percona extended format are supported,
+the entries which are earlier than the last ingested entry of the mysql server are skipped,
+the entries of the same time as the last ingested entry are skipped if they have the same time, thread id and statement,
+so the same file or a growing file could be ingested repeatedly.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// init config
+		err := initConfig()
+		if err != nil {
+			fmt.Println(fmt.Sprintf("%+v", message.NewMessage(message.ErrInitConfig, err)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+
+		// init connection pool
+		err = global.InitDASMySQLPool()
+		if err != nil {
+			fmt.Println(fmt.Sprintf("%+v", message.NewMessage(message.ErrInitConnectionPool, err)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+
+		// open slow log file
+		file, err := os.Open(slowLogFile)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("%+v", message.NewMessage(msgquery.ErrQueryOpenSlowLogFile, err, slowLogFile)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+		defer func() { _ = file.Close() }()
+
+		// ingest slow log
+		service := query.NewSlowLogServiceWithDefault()
+		err = service.Ingest(slowLogMySQLServerID, file)
+		if err != nil {
+			fmt.Println(fmt.Sprintf("%+v", message.NewMessage(msgquery.ErrQueryIngestSlowLog, err, slowLogMySQLServerID)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+
+		fmt.Println(message.NewMessage(msgquery.InfoQueryIngestSlowLog, slowLogMySQLServerID, service.GetEntryCount(), service.GetSkippedCount(), service.GetStatCount()).Error())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(slowLogCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// slowLogCmd.PersistentFlags().String("foo", "", "A help for foo")
+	slowLogCmd.PersistentFlags().StringVar(&slowLogFile, "file", constant.EmptyString, fmt.Sprintf("specify the slow log file"))
+	slowLogCmd.PersistentFlags().IntVar(&slowLogMySQLServerID, "mysql-server-id", constant.DefaultRandomInt, fmt.Sprintf("specify the mysql server id of the slow log file"))
+	_ = slowLogCmd.MarkPersistentFlagRequired("file")
+	_ = slowLogCmd.MarkPersistentFlagRequired("mysql-server-id")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// slowLogCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
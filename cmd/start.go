@@ -24,7 +24,9 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/romberli/das/config"
 	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/app/healthcheck"
 	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
 	msgrouter "github.com/romberli/das/pkg/message/router"
 	"github.com/romberli/das/router"
 	"github.com/romberli/das/server"
@@ -136,8 +138,27 @@ var startCmd = &cobra.Command{
 				viper.GetInt(config.ServerWriteTimeoutKey),
 				r,
 			)
+			// sync the item configs of the scoring profiles with the global default engine config which may be changed by the upgrade
+			err = healthcheck.NewEngineConfigServiceWithDefault().Sync()
+			if err != nil {
+				log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSyncEngineConfig, err))
+				os.Exit(constant.DefaultAbnormalExitCode)
+			}
 			// start server
 			go s.Run()
+			// start healthcheck scheduler
+			if viper.GetBool(config.HealthcheckScheduleEnabledKey) {
+				healthcheck.NewSchedulerWithDefault().Start()
+			}
+			// start healthcheck archiver
+			if viper.GetBool(config.HealthcheckRetentionEnabledKey) {
+				healthcheck.NewArchiverWithDefault().Start()
+			}
+			// put the healthcheck operations which were queued before the restart into the queue again
+			err = healthcheck.NewServiceWithDefault().RecoverQueuedOperations()
+			if err != nil {
+				log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckRecoverQueuedOperations, err))
+			}
 
 			log.CloneStdoutLogger().Info(message.NewMessage(message.InfoServerStart, s.Addr(), serverPid, serverPidFile).Error())
 
@@ -73,6 +73,10 @@ func SetDefaultConfig(baseDir string) {
 	viper.SetDefault(DBMonitorClickhousePassKey, DefaultDBMonitorClickhousePass)
 	viper.SetDefault(DBMonitorMySQLUserKey, DefaultDBMonitorMySQLUser)
 	viper.SetDefault(DBMonitorMySQLPassKey, DefaultDBMonitorMySQLPass)
+	viper.SetDefault(DBMonitorGenericNodeLabelKey, DefaultDBMonitorGenericNodeLabel)
+	viper.SetDefault(DBMonitorGenericServiceLabelKey, DefaultDBMonitorGenericServiceLabel)
+	viper.SetDefault(DBMonitorGenericNodeJobKey, DefaultDBMonitorGenericNodeJob)
+	viper.SetDefault(DBMonitorGenericMySQLJobKey, DefaultDBMonitorGenericMySQLJob)
 	viper.SetDefault(DBApplicationMySQLUserKey, DefaultDBApplicationMySQLUser)
 	viper.SetDefault(DBApplicationMySQLPassKey, DefaultDBApplicationMySQLPass)
 	// privilege
@@ -92,6 +96,22 @@ func SetDefaultConfig(baseDir string) {
 	// healthcheck
 	viper.SetDefault(HealthcheckMaxRangeKey, DefaultHealthCheckMaxRange)
 	viper.SetDefault(HealthcheckAlertOwnerTypeKey, DefaultHealthcheckAlertOwnerType)
+	viper.SetDefault(HealthcheckAlertOwnerThresholdKey, DefaultHealthcheckAlertOwnerThreshold)
+	viper.SetDefault(HealthcheckScheduleEnabledKey, DefaultHealthcheckScheduleEnabled)
+	viper.SetDefault(HealthcheckTimeoutKey, DefaultHealthcheckTimeout)
+	viper.SetDefault(HealthcheckSnapshotEnabledKey, DefaultHealthcheckSnapshotEnabled)
+	viper.SetDefault(HealthcheckConcurrencyKey, DefaultHealthcheckConcurrency)
+	viper.SetDefault(HealthcheckSecurityAdminAccountsKey, DefaultHealthcheckSecurityAdminAccounts)
+	viper.SetDefault(HealthcheckSecurityExemptAccountsKey, DefaultHealthcheckSecurityExemptAccounts)
+	viper.SetDefault(HealthcheckCapacityForecastDaysKey, DefaultHealthcheckCapacityForecastDays)
+	viper.SetDefault(HealthcheckCallbackSecretKey, DefaultHealthcheckCallbackSecret)
+	viper.SetDefault(HealthcheckCallbackTimeoutKey, DefaultHealthcheckCallbackTimeout)
+	viper.SetDefault(HealthcheckCallbackAllowedHostsKey, DefaultHealthcheckCallbackAllowedHosts)
+	viper.SetDefault(HealthcheckRetentionEnabledKey, DefaultHealthcheckRetentionEnabled)
+	viper.SetDefault(HealthcheckRetentionFullDataDaysKey, DefaultHealthcheckRetentionFullDataDays)
+	viper.SetDefault(HealthcheckRetentionBatchSizeKey, DefaultHealthcheckRetentionBatchSize)
+	viper.SetDefault(HealthcheckRetentionBatchIntervalKey, DefaultHealthcheckRetentionBatchInterval)
+	viper.SetDefault(HealthcheckRetentionPurgeEnabledKey, DefaultHealthcheckRetentionPurgeEnabled)
 	// query
 	viper.SetDefault(QueryMinRowsExaminedKey, DefaultQueryMinRowsExamined)
 	// sqladvisor
@@ -435,6 +455,32 @@ func ValidateDatabase() error {
 	if err != nil {
 		merr = multierror.Append(merr, errors.Trace(err))
 	}
+	// validate db.monitor.generic.nodeLabel
+	nodeLabel, err := cast.ToStringE(viper.Get(DBMonitorGenericNodeLabelKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if nodeLabel == constant.EmptyString {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidMonitorGenericLabel, DBMonitorGenericNodeLabelKey))
+	}
+	// validate db.monitor.generic.serviceLabel
+	serviceLabel, err := cast.ToStringE(viper.Get(DBMonitorGenericServiceLabelKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if serviceLabel == constant.EmptyString {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidMonitorGenericLabel, DBMonitorGenericServiceLabelKey))
+	}
+	// validate db.monitor.generic.nodeJob
+	_, err = cast.ToStringE(viper.Get(DBMonitorGenericNodeJobKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	// validate db.monitor.generic.mysqlJob
+	_, err = cast.ToStringE(viper.Get(DBMonitorGenericMySQLJobKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
 	// validate db.soar.mysql.addr
 	dbSoarAddr, err := cast.ToStringE(viper.Get(DBDASMySQLAddrKey))
 	if err != nil {
@@ -576,6 +622,136 @@ func ValidateHealthcheck() error {
 		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckAlertOwnerType, ownerType))
 	}
 
+	// validate healthcheck.alert.ownerThreshold
+	healthcheckAlertOwnerThreshold, err := cast.ToIntE(viper.Get(HealthcheckAlertOwnerThresholdKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if healthcheckAlertOwnerThreshold < MinHealthcheckAlertOwnerThreshold || healthcheckAlertOwnerThreshold > MaxHealthcheckAlertOwnerThreshold {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckOwnerThreshold,
+			MinHealthcheckAlertOwnerThreshold, MaxHealthcheckAlertOwnerThreshold, healthcheckAlertOwnerThreshold))
+	}
+
+	// validate healthcheck.schedule.enabled
+	_, err = cast.ToBoolE(viper.Get(HealthcheckScheduleEnabledKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate healthcheck.timeout
+	healthcheckTimeout, err := cast.ToIntE(viper.Get(HealthcheckTimeoutKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if healthcheckTimeout < MinHealthcheckTimeout || healthcheckTimeout > MaxHealthcheckTimeout {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckTimeout, MinHealthcheckTimeout, MaxHealthcheckTimeout, healthcheckTimeout))
+	}
+
+	// validate healthcheck.snapshot.enabled
+	_, err = cast.ToBoolE(viper.Get(HealthcheckSnapshotEnabledKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate healthcheck.concurrency
+	healthcheckConcurrency, err := cast.ToIntE(viper.Get(HealthcheckConcurrencyKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if healthcheckConcurrency < MinHealthcheckConcurrency || healthcheckConcurrency > MaxHealthcheckConcurrency {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckConcurrency, MinHealthcheckConcurrency, MaxHealthcheckConcurrency, healthcheckConcurrency))
+	}
+
+	// validate healthcheck.security.adminAccounts
+	_, err = cast.ToStringE(viper.Get(HealthcheckSecurityAdminAccountsKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate healthcheck.security.exemptAccounts
+	_, err = cast.ToStringE(viper.Get(HealthcheckSecurityExemptAccountsKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate healthcheck.capacity.forecastDays
+	healthcheckCapacityForecastDays, err := cast.ToIntE(viper.Get(HealthcheckCapacityForecastDaysKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if healthcheckCapacityForecastDays < MinHealthcheckCapacityForecastDays || healthcheckCapacityForecastDays > MaxHealthcheckCapacityForecastDays {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckForecastDays,
+			MinHealthcheckCapacityForecastDays, MaxHealthcheckCapacityForecastDays, healthcheckCapacityForecastDays))
+	}
+
+	// validate healthcheck.callback.secret
+	healthcheckCallbackSecret, err := cast.ToStringE(viper.Get(HealthcheckCallbackSecretKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate healthcheck.callback.timeout
+	healthcheckCallbackTimeout, err := cast.ToIntE(viper.Get(HealthcheckCallbackTimeoutKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if healthcheckCallbackTimeout < MinHealthcheckCallbackTimeout || healthcheckCallbackTimeout > MaxHealthcheckCallbackTimeout {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckCallbackTimeout,
+			MinHealthcheckCallbackTimeout, MaxHealthcheckCallbackTimeout, healthcheckCallbackTimeout))
+	}
+
+	// validate healthcheck.callback.allowedHosts
+	healthcheckCallbackAllowedHosts, err := cast.ToStringE(viper.Get(HealthcheckCallbackAllowedHostsKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	// the callbacks are enabled by the allowed hosts, and their payloads must be signed
+	if strings.TrimSpace(healthcheckCallbackAllowedHosts) != constant.EmptyString && healthcheckCallbackSecret == constant.EmptyString {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrEmptyHealthcheckCallbackSecret))
+	}
+
+	// validate healthcheck.retention.enabled
+	_, err = cast.ToBoolE(viper.Get(HealthcheckRetentionEnabledKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate healthcheck.retention.fullDataDays
+	healthcheckRetentionFullDataDays, err := cast.ToIntE(viper.Get(HealthcheckRetentionFullDataDaysKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if healthcheckRetentionFullDataDays < MinHealthcheckRetentionFullDataDays || healthcheckRetentionFullDataDays > MaxHealthcheckRetentionFullDataDays {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckRetentionDays,
+			MinHealthcheckRetentionFullDataDays, MaxHealthcheckRetentionFullDataDays, healthcheckRetentionFullDataDays))
+	}
+
+	// validate healthcheck.retention.batchSize
+	healthcheckRetentionBatchSize, err := cast.ToIntE(viper.Get(HealthcheckRetentionBatchSizeKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if healthcheckRetentionBatchSize < MinHealthcheckRetentionBatchSize || healthcheckRetentionBatchSize > MaxHealthcheckRetentionBatchSize {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckBatchSize,
+			MinHealthcheckRetentionBatchSize, MaxHealthcheckRetentionBatchSize, healthcheckRetentionBatchSize))
+	}
+
+	// validate healthcheck.retention.batchInterval
+	healthcheckRetentionBatchInterval, err := cast.ToIntE(viper.Get(HealthcheckRetentionBatchIntervalKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if healthcheckRetentionBatchInterval < MinHealthcheckRetentionBatchInterval || healthcheckRetentionBatchInterval > MaxHealthcheckRetentionBatchInterval {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidHealthcheckBatchInterval,
+			MinHealthcheckRetentionBatchInterval, MaxHealthcheckRetentionBatchInterval, healthcheckRetentionBatchInterval))
+	}
+
+	// validate healthcheck.retention.purgeEnabled
+	_, err = cast.ToBoolE(viper.Get(HealthcheckRetentionPurgeEnabledKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
 	return errors.Trace(merr.ErrorOrNil())
 }
 
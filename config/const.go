@@ -64,6 +64,11 @@ const (
 	DefaultDBMonitorMySQLPass      = "root"
 	DefaultDBApplicationMySQLUser  = "root"
 	DefaultDBApplicationMySQLPass  = "root"
+	// generic prometheus monitor system
+	DefaultDBMonitorGenericNodeLabel    = "instance"
+	DefaultDBMonitorGenericServiceLabel = "instance"
+	DefaultDBMonitorGenericNodeJob      = "node"
+	DefaultDBMonitorGenericMySQLJob     = "mysql"
 	// privilege
 	DefaultPrivilegeEnabled = true
 	// metadata
@@ -86,11 +91,43 @@ const (
 	DefaultAlertHTTPURL     = "http://127.0.0.1:8080"
 	DefaultAlertHTTPConfig  = "{}"
 	// healthcheck
-	DefaultHealthCheckMaxRange       = 30
-	HealthcheckAlertOwnerTypeApp     = "app"
-	HealthcheckAlertOwnerTypeDB      = "db"
-	HealthcheckAlertOwnerTypeAll     = "all"
-	DefaultHealthcheckAlertOwnerType = HealthcheckAlertOwnerTypeAll
+	DefaultHealthCheckMaxRange               = 30
+	HealthcheckAlertOwnerTypeApp             = "app"
+	HealthcheckAlertOwnerTypeDB              = "db"
+	HealthcheckAlertOwnerTypeAll             = "all"
+	DefaultHealthcheckAlertOwnerType         = HealthcheckAlertOwnerTypeAll
+	DefaultHealthcheckAlertOwnerThreshold    = 60
+	MinHealthcheckAlertOwnerThreshold        = 0
+	MaxHealthcheckAlertOwnerThreshold        = 100
+	DefaultHealthcheckScheduleEnabled        = false
+	DefaultHealthcheckTimeout                = 600
+	MinHealthcheckTimeout                    = 1
+	MaxHealthcheckTimeout                    = 86400
+	DefaultHealthcheckSnapshotEnabled        = false
+	DefaultHealthcheckConcurrency            = 10
+	MinHealthcheckConcurrency                = 1
+	MaxHealthcheckConcurrency                = 1000
+	DefaultHealthcheckSecurityAdminAccounts  = "root@localhost"
+	DefaultHealthcheckSecurityExemptAccounts = ""
+	DefaultHealthcheckCapacityForecastDays   = 30
+	MinHealthcheckCapacityForecastDays       = 1
+	MaxHealthcheckCapacityForecastDays       = 3650
+	DefaultHealthcheckCallbackSecret         = ""
+	DefaultHealthcheckCallbackTimeout        = 10
+	DefaultHealthcheckCallbackAllowedHosts   = ""
+	MinHealthcheckCallbackTimeout            = 1
+	MaxHealthcheckCallbackTimeout            = 3600
+	DefaultHealthcheckRetentionEnabled       = false
+	DefaultHealthcheckRetentionFullDataDays  = 90
+	MinHealthcheckRetentionFullDataDays      = 1
+	MaxHealthcheckRetentionFullDataDays      = 3650
+	DefaultHealthcheckRetentionBatchSize     = 500
+	MinHealthcheckRetentionBatchSize         = 1
+	MaxHealthcheckRetentionBatchSize         = 10000
+	DefaultHealthcheckRetentionBatchInterval = 1000
+	DefaultHealthcheckRetentionPurgeEnabled  = false
+	MinHealthcheckRetentionBatchInterval     = 0
+	MaxHealthcheckRetentionBatchInterval     = 60000
 	// query
 	DefaultQueryMinRowsExamined = 100000
 	// sqladvisor
@@ -135,6 +172,11 @@ const (
 	DBMonitorClickhousePassKey  = "db.monitor.clickhouse.pass"
 	DBMonitorMySQLUserKey       = "db.monitor.mysql.user"
 	DBMonitorMySQLPassKey       = "db.monitor.mysql.pass"
+	// generic prometheus monitor system
+	DBMonitorGenericNodeLabelKey    = "db.monitor.generic.nodeLabel"
+	DBMonitorGenericServiceLabelKey = "db.monitor.generic.serviceLabel"
+	DBMonitorGenericNodeJobKey      = "db.monitor.generic.nodeJob"
+	DBMonitorGenericMySQLJobKey     = "db.monitor.generic.mysqlJob"
 	// privilege
 	PrivilegeEnabledKey = "privilege.enabled"
 	// metadata
@@ -150,8 +192,24 @@ const (
 	AlertHTTPURLKey     = "alert.http.url"
 	AlertHTTPConfigKey  = "alert.http.config"
 	// healthcheck
-	HealthcheckMaxRangeKey       = "healthcheck.maxRange"
-	HealthcheckAlertOwnerTypeKey = "healthcheck.alert.ownerType"
+	HealthcheckMaxRangeKey               = "healthcheck.maxRange"
+	HealthcheckAlertOwnerTypeKey         = "healthcheck.alert.ownerType"
+	HealthcheckAlertOwnerThresholdKey    = "healthcheck.alert.ownerThreshold"
+	HealthcheckScheduleEnabledKey        = "healthcheck.schedule.enabled"
+	HealthcheckTimeoutKey                = "healthcheck.timeout"
+	HealthcheckSnapshotEnabledKey        = "healthcheck.snapshot.enabled"
+	HealthcheckConcurrencyKey            = "healthcheck.concurrency"
+	HealthcheckSecurityAdminAccountsKey  = "healthcheck.security.adminAccounts"
+	HealthcheckSecurityExemptAccountsKey = "healthcheck.security.exemptAccounts"
+	HealthcheckCapacityForecastDaysKey   = "healthcheck.capacity.forecastDays"
+	HealthcheckCallbackSecretKey         = "healthcheck.callback.secret"
+	HealthcheckCallbackTimeoutKey        = "healthcheck.callback.timeout"
+	HealthcheckCallbackAllowedHostsKey   = "healthcheck.callback.allowedHosts"
+	HealthcheckRetentionEnabledKey       = "healthcheck.retention.enabled"
+	HealthcheckRetentionFullDataDaysKey  = "healthcheck.retention.fullDataDays"
+	HealthcheckRetentionBatchSizeKey     = "healthcheck.retention.batchSize"
+	HealthcheckRetentionBatchIntervalKey = "healthcheck.retention.batchInterval"
+	HealthcheckRetentionPurgeEnabledKey  = "healthcheck.retention.purgeEnabled"
 	// query
 	QueryMinRowsExaminedKey = "query.minRowsExamined"
 	// sqladvisor
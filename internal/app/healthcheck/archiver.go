@@ -0,0 +1,134 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+	"github.com/spf13/viper"
+)
+
+const defaultArchiverInterval = time.Hour
+
+var _ healthcheck.Archiver = (*Archiver)(nil)
+
+// Archiver archives the expired healthcheck data periodically in throttled batches,
+// only the scores are kept after archiving
+type Archiver struct {
+	retentionRepo healthcheck.RetentionRepo
+	stopChan      chan struct{}
+	stopOnce      sync.Once
+}
+
+// NewArchiver returns a new healthcheck.Archiver
+func NewArchiver(retentionRepo healthcheck.RetentionRepo) healthcheck.Archiver {
+	return newArchiver(retentionRepo)
+}
+
+// NewArchiverWithDefault returns a new healthcheck.Archiver with default repository
+func NewArchiverWithDefault() healthcheck.Archiver {
+	return newArchiver(NewRetentionRepoWithGlobal())
+}
+
+// newArchiver returns a new *Archiver
+func newArchiver(retentionRepo healthcheck.RetentionRepo) *Archiver {
+	return &Archiver{
+		retentionRepo: retentionRepo,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start starts the archiver in the background
+func (a *Archiver) Start() {
+	go a.run()
+}
+
+// Stop stops the archiver, the running batch will not be interrupted
+func (a *Archiver) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.stopChan)
+	})
+}
+
+// run archives the expired data once the archiver starts, and then every hour
+func (a *Archiver) run() {
+	a.archive()
+
+	ticker := time.NewTicker(defaultArchiverInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.archive()
+		case <-a.stopChan:
+			return
+		}
+	}
+}
+
+// archive archives the expired data of the operations and the middleware operations,
+// and then purges the archived operations which have no result if purging is enabled
+func (a *Archiver) archive() {
+	var middlewareTotal, purgedTotal int
+
+	total, stopped := a.archiveBatches(a.retentionRepo.GetExpiredOperationIDs, a.retentionRepo.Archive)
+	if !stopped {
+		middlewareTotal, stopped = a.archiveBatches(a.retentionRepo.GetExpiredMiddlewareOperationIDs, a.retentionRepo.ArchiveMiddleware)
+	}
+	if !stopped && viper.GetBool(config.HealthcheckRetentionPurgeEnabledKey) {
+		purgedTotal, _ = a.archiveBatches(a.getPurgeableOperationIDs, a.retentionRepo.Purge)
+	}
+
+	if total > constant.ZeroInt || middlewareTotal > constant.ZeroInt || purgedTotal > constant.ZeroInt {
+		log.Info(message.NewMessage(msghc.InfoHealthcheckArchive, total, middlewareTotal, purgedTotal).Error())
+	}
+}
+
+// getPurgeableOperationIDs gets the ids of the archived operations which have no result, the full data days are not used
+func (a *Archiver) getPurgeableOperationIDs(fullDataDays, limit int) ([]int, error) {
+	return a.retentionRepo.GetPurgeableOperationIDs(limit)
+}
+
+// archiveBatches archives the expired data batch by batch until no expired operation is left,
+// it waits for the batch interval between the batches, so that the middleware will not be overloaded,
+// it returns the number of the archived operations and if the archiver is stopped
+func (a *Archiver) archiveBatches(getExpiredIDs func(fullDataDays, limit int) ([]int, error), archive func(ids []int) error) (int, bool) {
+	fullDataDays := viper.GetInt(config.HealthcheckRetentionFullDataDaysKey)
+	batchSize := viper.GetInt(config.HealthcheckRetentionBatchSizeKey)
+	batchInterval := time.Duration(viper.GetInt(config.HealthcheckRetentionBatchIntervalKey)) * time.Millisecond
+
+	var total int
+	for {
+		ids, err := getExpiredIDs(fullDataDays, batchSize)
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckArchive, err))
+			return total, false
+		}
+		if len(ids) == constant.ZeroInt {
+			return total, false
+		}
+		err = archive(ids)
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckArchive, err))
+			return total, false
+		}
+		total += len(ids)
+		if len(ids) < batchSize {
+			return total, false
+		}
+
+		timer := time.NewTimer(batchInterval)
+		select {
+		case <-timer.C:
+		case <-a.stopChan:
+			timer.Stop()
+			return total, true
+		}
+	}
+}
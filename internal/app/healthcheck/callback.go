@@ -0,0 +1,226 @@
+package healthcheck
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	CallbackEventCompleted = "completed"
+	CallbackEventFailed    = "failed"
+	CallbackEventCancelled = "cancelled"
+
+	callbackContentType     = "application/json"
+	callbackSignatureHeader = "X-DAS-Signature"
+	callbackSignaturePrefix = "sha256="
+	callbackSchemeHTTP      = "http"
+	callbackSchemeHTTPS     = "https"
+)
+
+// CallbackPayload is the json body which will be posted to the callback urls when the operation stops
+type CallbackPayload struct {
+	Event                string    `json:"event"`
+	OperationID          int       `json:"operation_id"`
+	MySQLServerID        int       `json:"mysql_server_id"`
+	HostIP               string    `json:"host_ip"`
+	PortNum              int       `json:"port_num"`
+	Status               int       `json:"status"`
+	Message              string    `json:"message"`
+	WeightedAverageScore int       `json:"weighted_average_score"`
+	NotifyTime           time.Time `json:"notify_time"`
+}
+
+// newCallbackPayload returns a new *CallbackPayload with the operation history,
+// it returns nil if the operation is still queued or running
+func newCallbackPayload(operationHistory healthcheck.OperationHistory, score int) *CallbackPayload {
+	var event string
+	switch operationHistory.GetStatus() {
+	case defaultSuccessStatus, defaultPartialStatus:
+		event = CallbackEventCompleted
+	case defaultFailedStatus:
+		event = CallbackEventFailed
+	case defaultCancelledStatus:
+		event = CallbackEventCancelled
+	default:
+		return nil
+	}
+
+	return &CallbackPayload{
+		Event:                event,
+		OperationID:          operationHistory.GetID(),
+		MySQLServerID:        operationHistory.GetMySQLServerID(),
+		HostIP:               operationHistory.GetHostIP(),
+		PortNum:              operationHistory.GetPortNum(),
+		Status:               operationHistory.GetStatus(),
+		Message:              operationHistory.GetMessage(),
+		WeightedAverageScore: score,
+		NotifyTime:           time.Now(),
+	}
+}
+
+// getCallbackAllowedHosts returns the lower case hosts of the comma separated string
+func getCallbackAllowedHosts(s string) []string {
+	var hosts []string
+	for _, host := range strings.Split(s, constant.CommaString) {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != constant.EmptyString {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// validateCallbackURLs checks if the callback urls could be notified,
+// the callbacks are allowed only if the secret is specified, so that the payloads are always signed,
+// and the callback urls must be http or https urls of which the hosts are in the allowed hosts,
+// so that the callbacks could not be used to post the healthcheck results to arbitrary internal addresses
+func validateCallbackURLs(callbackURLs []string, secret string, allowedHosts []string) error {
+	if len(callbackURLs) == constant.ZeroInt {
+		return nil
+	}
+	if secret == constant.EmptyString || len(allowedHosts) == constant.ZeroInt {
+		return errors.New("healthcheck: callbacks are disabled, please specify both healthcheck.callback.secret and healthcheck.callback.allowedHosts")
+	}
+
+	for _, callbackURL := range callbackURLs {
+		u, err := url.Parse(callbackURL)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if u.Scheme != callbackSchemeHTTP && u.Scheme != callbackSchemeHTTPS {
+			return errors.Errorf("healthcheck: callback url must be an http or https url. callback url: %s", callbackURL)
+		}
+		if !common.StringInSlice(allowedHosts, strings.ToLower(u.Hostname())) {
+			return errors.Errorf("healthcheck: host of the callback url is not allowed. callback url: %s, allowed hosts: %s",
+				callbackURL, strings.Join(allowedHosts, constant.CommaString))
+		}
+	}
+
+	return nil
+}
+
+// validateCallbackURLsWithConfig checks if the callback urls could be notified with the secret and the allowed hosts of the config
+func validateCallbackURLsWithConfig(callbackURLs []string) error {
+	return validateCallbackURLs(callbackURLs, viper.GetString(config.HealthcheckCallbackSecretKey),
+		getCallbackAllowedHosts(viper.GetString(config.HealthcheckCallbackAllowedHostsKey)))
+}
+
+// newCallbackClient returns a new *http.Client which does not follow the redirects,
+// so that the callbacks could not be redirected to the hosts which are not allowed
+func newCallbackClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// signCallbackBody returns the hex encoded hmac-sha256 signature of the body with given secret
+func signCallbackBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendCallback posts the body to the callback url, the signature of the body is set in the header
+func sendCallback(client *http.Client, callbackURL, secret string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", callbackContentType)
+	req.Header.Set(callbackSignatureHeader, callbackSignaturePrefix+signCallbackBody(secret, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("got http error when calling callback url. status code: %d, message: %s",
+			resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// notifyCallbacks posts the final status of the operation to all the callback urls of it,
+// it should be called after the final status of the operation was saved,
+// the failure of a callback url will only be logged, it does not affect the operation and the other callback urls
+func notifyCallbacks(dasRepo healthcheck.DASRepo, operationID int) {
+	callbackURLs, err := dasRepo.GetCallbackURLsByOperationID(operationID)
+	if err != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSendCallback, err, operationID, constant.EmptyString))
+		return
+	}
+	if len(callbackURLs) == constant.ZeroInt {
+		return
+	}
+
+	operationHistory, err := dasRepo.GetOperationHistoryByID(operationID)
+	if err != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSendCallback, err, operationID, constant.EmptyString))
+		return
+	}
+	var score int
+	if operationHistory.GetStatus() == defaultSuccessStatus || operationHistory.GetStatus() == defaultPartialStatus {
+		result, err := dasRepo.GetResultByOperationID(operationID)
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSendCallback, err, operationID, constant.EmptyString))
+			return
+		}
+		score = result.GetWeightedAverageScore()
+	}
+	payload := newCallbackPayload(operationHistory, score)
+	if payload == nil {
+		log.Warnf("healthcheck operation is not stopped, skip the callbacks. operation_id: %d, status: %d",
+			operationID, operationHistory.GetStatus())
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSendCallback, errors.Trace(err), operationID, constant.EmptyString))
+		return
+	}
+
+	client := newCallbackClient(time.Duration(viper.GetInt(config.HealthcheckCallbackTimeoutKey)) * time.Second)
+	secret := viper.GetString(config.HealthcheckCallbackSecretKey)
+	for _, callbackURL := range callbackURLs {
+		// the config may be changed after the callback url was saved
+		err = validateCallbackURLsWithConfig([]string{callbackURL})
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSendCallback, err, operationID, callbackURL))
+			continue
+		}
+		err = sendCallback(client, callbackURL, secret, body)
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSendCallback, err, operationID, callbackURL))
+		}
+	}
+}
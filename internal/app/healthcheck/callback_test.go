@@ -0,0 +1,91 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCallbackSecret  = "test_secret"
+	testCallbackTimeout = 3 * time.Second
+)
+
+var testCallbackURLs = []string{"http://127.0.0.1:8080/callback", "http://127.0.0.1:8081/callback"}
+
+func TestCallback_All(t *testing.T) {
+	TestCallback_newCallbackPayload(t)
+	TestCallback_validateCallbackURLs(t)
+	TestCallback_sendCallback(t)
+}
+
+func TestCallback_newCallbackPayload(t *testing.T) {
+	asst := assert.New(t)
+
+	operationHistory := &OperationHistory{ID: 1, MySQLServerID: testHealthcheckMySQLServerID, Status: defaultPartialStatus}
+	payload := newCallbackPayload(operationHistory, testMiddlewareResultScore)
+	asst.Equal(CallbackEventCompleted, payload.Event, "test newCallbackPayload() failed")
+	asst.Equal(testMiddlewareResultScore, payload.WeightedAverageScore, "test newCallbackPayload() failed")
+	operationHistory.Status = defaultCancelledStatus
+	asst.Equal(CallbackEventCancelled, newCallbackPayload(operationHistory, constant.ZeroInt).Event, "test newCallbackPayload() failed")
+	// the running operation has no payload
+	operationHistory.Status = defaultRunningStatus
+	asst.Nil(newCallbackPayload(operationHistory, constant.ZeroInt), "test newCallbackPayload() failed")
+}
+
+func TestCallback_validateCallbackURLs(t *testing.T) {
+	asst := assert.New(t)
+
+	allowedHosts := getCallbackAllowedHosts(" 127.0.0.1, Example.com ,")
+	asst.Equal([]string{"127.0.0.1", "example.com"}, allowedHosts, "test validateCallbackURLs() failed")
+	err := validateCallbackURLs(testCallbackURLs, testCallbackSecret, allowedHosts)
+	asst.Nil(err, common.CombineMessageWithError("test validateCallbackURLs() failed", err))
+	err = validateCallbackURLs([]string{"https://EXAMPLE.com/callback"}, testCallbackSecret, allowedHosts)
+	asst.Nil(err, common.CombineMessageWithError("test validateCallbackURLs() failed", err))
+	// no callback url is always valid
+	asst.Nil(validateCallbackURLs(nil, constant.EmptyString, nil), "test validateCallbackURLs() failed")
+	// the callbacks are disabled without the secret or the allowed hosts
+	asst.NotNil(validateCallbackURLs(testCallbackURLs, constant.EmptyString, allowedHosts), "test validateCallbackURLs() failed")
+	asst.NotNil(validateCallbackURLs(testCallbackURLs, testCallbackSecret, nil), "test validateCallbackURLs() failed")
+	// the scheme and the host must be allowed
+	asst.NotNil(validateCallbackURLs([]string{"file:///etc/passwd"}, testCallbackSecret, allowedHosts), "test validateCallbackURLs() failed")
+	asst.NotNil(validateCallbackURLs([]string{"http://169.254.169.254/latest"}, testCallbackSecret, allowedHosts), "test validateCallbackURLs() failed")
+}
+
+func TestCallback_sendCallback(t *testing.T) {
+	asst := assert.New(t)
+
+	var (
+		signature string
+		payload   CallbackPayload
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(callbackSignatureHeader)
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	body, err := json.Marshal(&CallbackPayload{Event: CallbackEventFailed, OperationID: 1})
+	asst.Nil(err, common.CombineMessageWithError("test sendCallback() failed", err))
+	client := &http.Client{Timeout: testCallbackTimeout}
+	err = sendCallback(client, server.URL, testCallbackSecret, body)
+	asst.Nil(err, common.CombineMessageWithError("test sendCallback() failed", err))
+	asst.Equal(callbackSignaturePrefix+signCallbackBody(testCallbackSecret, body), signature, "test sendCallback() failed")
+	asst.Equal(CallbackEventFailed, payload.Event, "test sendCallback() failed")
+	// the redirects are not followed
+	redirectServer := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusFound))
+	defer redirectServer.Close()
+	signature = constant.EmptyString
+	err = sendCallback(newCallbackClient(testCallbackTimeout), redirectServer.URL, testCallbackSecret, body)
+	asst.NotNil(err, "test sendCallback() failed")
+	asst.Equal(constant.EmptyString, signature, "test sendCallback() failed")
+}
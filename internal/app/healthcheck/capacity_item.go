@@ -0,0 +1,205 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultDiskCapacityForecastItemName = "disk_capacity_forecast"
+
+	bytesPerGB    = 1024 * 1024 * 1024
+	secondsPerDay = 24 * 60 * 60
+	// diskForecastNotGrowing is the days to full of the mount points which are not growing
+	diskForecastNotGrowing = -1
+)
+
+var _ healthcheck.CheckItem = (*DiskCapacityForecastItem)(nil)
+
+func init() {
+	RegisterCheckItem(NewDiskCapacityForecastItem())
+}
+
+// DiskForecast is the forecast of a mount point, the sizes are in GB
+type DiskForecast struct {
+	MountPoint   string  `json:"mount_point"`
+	Size         float64 `json:"size"`
+	Used         float64 `json:"used"`
+	Usage        float64 `json:"usage"`
+	GrowthPerDay float64 `json:"growth_per_day"`
+	DaysToFull   float64 `json:"days_to_full"`
+	// Value is the projected usage after the forecast days
+	Value float64 `json:"value"`
+}
+
+// NewDiskForecast fits the used bytes series of the disk capacity with the least squares linear regression,
+// and projects the usage after given forecast days with the growth rate
+func NewDiskForecast(diskCapacity healthcheck.DiskCapacity, forecastDays int) (*DiskForecast, error) {
+	usedDatas := diskCapacity.GetUsedDatas()
+	df := &DiskForecast{
+		MountPoint: diskCapacity.GetMountPoint(),
+		Size:       diskCapacity.GetSize() / bytesPerGB,
+		DaysToFull: diskForecastNotGrowing,
+	}
+	if len(usedDatas) == constant.ZeroInt || diskCapacity.GetSize() <= 0 {
+		return df, nil
+	}
+
+	xs := make([]float64, len(usedDatas))
+	ys := make([]float64, len(usedDatas))
+	for i, data := range usedDatas {
+		ts, err := parsePrometheusTimestamp(data.GetTimestamp())
+		if err != nil {
+			return nil, err
+		}
+		xs[i] = ts
+		ys[i] = data.GetValue()
+	}
+
+	used := ys[len(ys)-1]
+	df.Used = used / bytesPerGB
+	df.Usage = used / diskCapacity.GetSize()
+	df.Value = df.Usage
+
+	// the slope is bytes per second
+	slope := getLinearRegressionSlope(xs, ys)
+	if slope <= 0 {
+		return df, nil
+	}
+	df.GrowthPerDay = slope * secondsPerDay / bytesPerGB
+	df.DaysToFull = (diskCapacity.GetSize() - used) / (slope * secondsPerDay)
+	df.Value = (used + slope*secondsPerDay*float64(forecastDays)) / diskCapacity.GetSize()
+
+	return df, nil
+}
+
+// getLinearRegressionSlope returns the slope of the least squares linear regression of the points,
+// it returns 0 if the slope could not be calculated
+func getLinearRegressionSlope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if len(xs) < 2 || len(xs) != len(ys) {
+		return 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		// subtract the first x to avoid losing precision with the large unix timestamps
+		x := xs[i] - xs[constant.ZeroInt]
+		sumX += x
+		sumY += ys[i]
+		sumXY += x * ys[i]
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+// parsePrometheusTimestamp parses the timestamp of the prometheus data to unix seconds,
+// the timestamp is either the unix seconds which are returned by the prometheus or the time layout of the snapshot
+func parsePrometheusTimestamp(ts string) (float64, error) {
+	seconds, err := strconv.ParseFloat(ts, 64)
+	if err == nil {
+		return seconds, nil
+	}
+
+	t, err := time.ParseInLocation(constant.TimeLayoutSecond, ts, time.Local)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	return float64(t.Unix()), nil
+}
+
+// DiskCapacityForecastItem forecasts the disk capacity of the mount points of the mysql directories
+// with the growth rate over the check window, the projected usage after the forecast days is scored
+type DiskCapacityForecastItem struct{}
+
+// NewDiskCapacityForecastItem returns a new *DiskCapacityForecastItem
+func NewDiskCapacityForecastItem() *DiskCapacityForecastItem {
+	return &DiskCapacityForecastItem{}
+}
+
+// GetName returns the item name
+func (dcfi *DiskCapacityForecastItem) GetName() string {
+	return defaultDiskCapacityForecastItemName
+}
+
+// GetDataSource returns the data source of the item
+func (dcfi *DiskCapacityForecastItem) GetDataSource() string {
+	return DataSourcePrometheus
+}
+
+// Check forecasts the disk capacity and scores the projected usage
+func (dcfi *DiskCapacityForecastItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	// the mount points could not be got when preparing the check
+	if len(env.GetMountPoints()) == constant.ZeroInt {
+		return nil, message.NewMessage(msghc.ErrHealthcheckMountPointsNotFound)
+	}
+	diskCapacities, err := env.GetPrometheusRepo().GetDiskCapacities(env.GetMountPoints())
+	if err != nil {
+		return nil, err
+	}
+
+	forecastDays := viper.GetInt(config.HealthcheckCapacityForecastDaysKey)
+	cfg := env.GetItemConfig(dcfi.GetName())
+
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		forecasts     []*DiskForecast
+		highForecasts []*DiskForecast
+		advices       []string
+	)
+	for _, diskCapacity := range diskCapacities {
+		forecast, err := NewDiskForecast(diskCapacity, forecastDays)
+		if err != nil {
+			return nil, err
+		}
+		forecasts = append(forecasts, forecast)
+
+		switch {
+		case forecast.Value >= cfg.GetHighWatermark():
+			highForecasts = append(highForecasts, forecast)
+			highSum += forecast.Value
+			highCount++
+		case forecast.Value >= cfg.GetLowWatermark():
+			mediumSum += forecast.Value
+			mediumCount++
+		default:
+			continue
+		}
+		advices = append(advices, fmt.Sprintf("mount point %s grows %.2f GB per day, usage will be %.2f in %d days, days to full: %.1f",
+			forecast.MountPoint, forecast.GrowthPerDay, forecast.Value, forecastDays, forecast.DaysToFull))
+	}
+
+	jsonBytesTotal, err := json.Marshal(forecasts)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highForecasts)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(cfg, highSum, highCount, mediumSum, mediumCount)
+
+	return NewItemResultWithEnv(env, dcfi, score, string(jsonBytesTotal), string(jsonBytesHigh), strings.Join(advices, constant.CommaString)), nil
+}
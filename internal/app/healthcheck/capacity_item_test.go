@@ -0,0 +1,108 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCapacityMySQLServerID = 1
+	testCapacityMountPoint    = "/data"
+	testCapacityForecastDays  = 30
+	testCapacityTableSizeData = `[{"db_name":"db01","table_name":"t01","table_rows":1000,"table_size":10}]`
+	testCapacityTableSizeNew  = `[{"db_name":"db01","table_name":"t01","table_rows":2000,"table_size":14}]`
+	testCapacityForecastData  = `[{"mount_point":"/data","size":100,"used":60,"usage":0.6,"growth_per_day":1,"days_to_full":40,"value":0.9}]`
+)
+
+func TestCapacityItem_All(t *testing.T) {
+	TestCapacityItem_getLinearRegressionSlope(t)
+	TestCapacityItem_parsePrometheusTimestamp(t)
+	TestCapacityItem_NewDiskForecast(t)
+	TestCapacityItem_NewCapacity(t)
+}
+
+func TestCapacityItem_getLinearRegressionSlope(t *testing.T) {
+	asst := assert.New(t)
+
+	slope := getLinearRegressionSlope([]float64{1654048800, 1654052400, 1654056000}, []float64{10, 20, 30})
+	asst.InDelta(10.0/3600, slope, 0.000001, "test getLinearRegressionSlope() failed")
+	// not enough points
+	asst.Equal(0.0, getLinearRegressionSlope([]float64{1654048800}, []float64{10}), "test getLinearRegressionSlope() failed")
+	// all the points are at the same time
+	asst.Equal(0.0, getLinearRegressionSlope([]float64{1654048800, 1654048800}, []float64{10, 20}), "test getLinearRegressionSlope() failed")
+}
+
+func TestCapacityItem_parsePrometheusTimestamp(t *testing.T) {
+	asst := assert.New(t)
+
+	ts, err := parsePrometheusTimestamp("1654048800.123")
+	asst.Nil(err, common.CombineMessageWithError("test parsePrometheusTimestamp() failed", err))
+	asst.Equal(1654048800.123, ts, "test parsePrometheusTimestamp() failed")
+	ts, err = parsePrometheusTimestamp("2022-06-01 10:00:00")
+	asst.Nil(err, common.CombineMessageWithError("test parsePrometheusTimestamp() failed", err))
+	asst.Equal(float64(time.Date(2022, 6, 1, 10, 0, 0, 0, time.Local).Unix()), ts, "test parsePrometheusTimestamp() failed")
+	_, err = parsePrometheusTimestamp(constant.EmptyString)
+	asst.NotNil(err, "test parsePrometheusTimestamp() failed")
+}
+
+func TestCapacityItem_NewDiskForecast(t *testing.T) {
+	asst := assert.New(t)
+
+	// grows 10 GB per day
+	diskCapacity := NewDiskCapacity(testCapacityMountPoint, 100*bytesPerGB, []healthcheck.PrometheusData{
+		NewPrometheusData("2022-06-01 10:00:00", 50*bytesPerGB),
+		NewPrometheusData("2022-06-02 10:00:00", 60*bytesPerGB),
+	})
+	forecast, err := NewDiskForecast(diskCapacity, testCapacityForecastDays)
+	asst.Nil(err, common.CombineMessageWithError("test NewDiskForecast() failed", err))
+	asst.InDelta(10.0, forecast.GrowthPerDay, 0.000001, "test NewDiskForecast() failed")
+	asst.InDelta(4.0, forecast.DaysToFull, 0.000001, "test NewDiskForecast() failed")
+	asst.InDelta(3.6, forecast.Value, 0.000001, "test NewDiskForecast() failed")
+	// not growing
+	diskCapacity = NewDiskCapacity(testCapacityMountPoint, 100*bytesPerGB, []healthcheck.PrometheusData{
+		NewPrometheusData("2022-06-01 10:00:00", 60*bytesPerGB),
+		NewPrometheusData("2022-06-02 10:00:00", 50*bytesPerGB),
+	})
+	forecast, err = NewDiskForecast(diskCapacity, testCapacityForecastDays)
+	asst.Nil(err, common.CombineMessageWithError("test NewDiskForecast() failed", err))
+	asst.Equal(float64(diskForecastNotGrowing), forecast.DaysToFull, "test NewDiskForecast() failed")
+	asst.InDelta(0.5, forecast.Value, 0.000001, "test NewDiskForecast() failed")
+}
+
+func TestCapacityItem_NewCapacity(t *testing.T) {
+	asst := assert.New(t)
+
+	createTime := time.Date(2022, 6, 1, 10, 0, 0, 0, time.Local)
+	first := NewEmptyResultWithOperationIDAndHostInfo(1, testResultHostIP, testResultPortNum)
+	first.TableSizeData = testCapacityTableSizeData
+	first.CreateTime = createTime
+	last := NewEmptyResultWithOperationIDAndHostInfo(2, testResultHostIP, testResultPortNum)
+	last.TableSizeData = testCapacityTableSizeNew
+	last.CreateTime = createTime.Add(2 * constant.Day)
+	last.ItemResults = []healthcheck.ItemResult{NewItemResult(2, defaultDiskCapacityForecastItemName, DataSourcePrometheus,
+		constant.ZeroInt, constant.ZeroInt, testCapacityForecastData, constant.EmptyString, constant.EmptyString)}
+
+	c, err := NewCapacity(testCapacityMySQLServerID, []healthcheck.Result{first, last})
+	asst.Nil(err, common.CombineMessageWithError("test NewCapacity() failed", err))
+	asst.Equal(2, c.GetOperationID(), "test NewCapacity() failed")
+	asst.InDelta(2.0, c.TableGrowths[constant.ZeroInt].GrowthPerDay, 0.000001, "test NewCapacity() failed")
+	// the tables grow faster than the mount point
+	asst.InDelta(20.0, c.GetDaysToFull(), 0.000001, "test NewCapacity() failed")
+	// no disk capacity forecast
+	c, err = NewCapacity(testCapacityMySQLServerID, []healthcheck.Result{first})
+	asst.Nil(err, common.CombineMessageWithError("test NewCapacity() failed", err))
+	asst.Equal(float64(diskForecastNotGrowing), c.GetDaysToFull(), "test NewCapacity() failed")
+	// the data of the archived result is cleared
+	archived := NewEmptyResultWithOperationIDAndHostInfo(3, testResultHostIP, testResultPortNum)
+	archived.ItemResults = []healthcheck.ItemResult{NewItemResult(3, defaultDiskCapacityForecastItemName, DataSourcePrometheus,
+		constant.ZeroInt, constant.ZeroInt, constant.EmptyString, constant.EmptyString, constant.EmptyString)}
+	c, err = NewCapacity(testCapacityMySQLServerID, []healthcheck.Result{archived})
+	asst.Nil(err, common.CombineMessageWithError("test NewCapacity() failed", err))
+	asst.Equal(constant.ZeroInt, c.GetOperationID(), "test NewCapacity() failed")
+	asst.Equal(float64(diskForecastNotGrowing), c.GetDaysToFull(), "test NewCapacity() failed")
+}
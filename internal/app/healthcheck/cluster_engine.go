@@ -0,0 +1,240 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+var (
+	// clusterConfigDriftIgnoreList contains the variables that are expected to be different on each mysql server
+	clusterConfigDriftIgnoreList = []string{dbConfigReportHost, dbConfigReportPort}
+)
+
+// clusterNode is a mysql server that will be checked in the cluster check
+type clusterNode struct {
+	mysqlServer depmeta.MySQLServer
+	operationID int
+	done        <-chan struct{}
+	err         error
+}
+
+// ClusterEngine waits for the operations of all the mysql servers of a mysql cluster, and combines the results
+type ClusterEngine struct {
+	clusterOperationID int
+	mysqlClusterID     int
+	masterServerIDList []int
+	dasRepo            healthcheck.DASRepo
+	nodes              []*clusterNode
+}
+
+// NewClusterEngine returns a new *ClusterEngine
+func NewClusterEngine(clusterOperationID, mysqlClusterID int, masterServerIDList []int, dasRepo healthcheck.DASRepo) *ClusterEngine {
+	return &ClusterEngine{
+		clusterOperationID: clusterOperationID,
+		mysqlClusterID:     mysqlClusterID,
+		masterServerIDList: masterServerIDList,
+		dasRepo:            dasRepo,
+	}
+}
+
+// getDASRepo returns the das repository
+func (ce *ClusterEngine) getDASRepo() healthcheck.DASRepo {
+	return ce.dasRepo
+}
+
+// isMaster returns if the mysql server of given id is a master node
+func (ce *ClusterEngine) isMaster(mysqlServerID int) bool {
+	for _, masterServerID := range ce.masterServerIDList {
+		if masterServerID == mysqlServerID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddNode adds a mysql server to the cluster engine, done is the channel which will be closed after the operation of the mysql server stops,
+// if the operation could not be submitted, done should be nil and err should be the cause, the mysql server will be marked as failed in the cluster result
+func (ce *ClusterEngine) AddNode(mysqlServer depmeta.MySQLServer, operationID int, done <-chan struct{}, err error) {
+	ce.nodes = append(ce.nodes, &clusterNode{
+		mysqlServer: mysqlServer,
+		operationID: operationID,
+		done:        done,
+		err:         err,
+	})
+}
+
+// Run waits until the operations of all the mysql servers stop, the operations are run by the executor,
+// and then saves the combined cluster result
+func (ce *ClusterEngine) Run() {
+	for _, node := range ce.nodes {
+		if node.done == nil {
+			continue
+		}
+		<-node.done
+	}
+
+	// summarize
+	msg, err := ce.summarize()
+	if err != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckClusterEngineRun, err, ce.clusterOperationID))
+		// update status
+		updateErr := ce.getDASRepo().UpdateClusterOperationStatus(ce.clusterOperationID, defaultFailedStatus, err.Error())
+		if updateErr != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+		}
+		return
+	}
+
+	// update cluster operation status
+	updateErr := ce.getDASRepo().UpdateClusterOperationStatus(ce.clusterOperationID, defaultSuccessStatus, msg)
+	if updateErr != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+	}
+}
+
+// summarize combines the results of all the mysql servers and saves the cluster result to the middleware,
+// it returns the message of the cluster operation
+func (ce *ClusterEngine) summarize() (string, error) {
+	var (
+		failedCount    int
+		minScore       = int(defaultMaxScore)
+		nodeResults    []*NodeResult
+		masterNode     *NodeResult
+		masterConfig   []*GlobalVariable
+		replicaNodes   []*NodeResult
+		replicaConfigs [][]*GlobalVariable
+	)
+
+	for _, node := range ce.nodes {
+		isMaster := ce.isMaster(node.mysqlServer.Identity())
+		nodeResult := NewNodeResult(node.operationID, node.mysqlServer.Identity(), node.mysqlServer.GetHostIP(), node.mysqlServer.GetPortNum(), isMaster)
+		nodeResults = append(nodeResults, nodeResult)
+
+		if node.err != nil {
+			failedCount++
+			nodeResult.Status = defaultFailedStatus
+			nodeResult.Message = node.err.Error()
+			continue
+		}
+		// the result does not exist if the engine failed
+		result, err := ce.getDASRepo().GetResultByOperationID(node.operationID)
+		if err != nil {
+			failedCount++
+			nodeResult.Status = defaultFailedStatus
+			nodeResult.Message = err.Error()
+			continue
+		}
+
+		nodeResult.Status = defaultSuccessStatus
+		nodeResult.WeightedAverageScore = result.GetWeightedAverageScore()
+		nodeResult.WorstItemName, nodeResult.WorstItemScore = getWorstItem(result)
+		if nodeResult.WeightedAverageScore < minScore {
+			minScore = nodeResult.WeightedAverageScore
+		}
+
+		var variables []*GlobalVariable
+		if result.GetDBConfigData() != constant.EmptyString {
+			err = json.Unmarshal([]byte(result.GetDBConfigData()), &variables)
+			if err != nil {
+				return constant.EmptyString, errors.Trace(err)
+			}
+		}
+		if isMaster && masterNode == nil {
+			// if there are multiple master nodes, take the first one as the baseline
+			masterNode = nodeResult
+			masterConfig = variables
+			continue
+		}
+		replicaNodes = append(replicaNodes, nodeResult)
+		replicaConfigs = append(replicaConfigs, variables)
+	}
+
+	if failedCount == len(ce.nodes) {
+		return constant.EmptyString, errors.Errorf("healthcheck of all the mysql servers failed. mysql cluster id: %d", ce.mysqlClusterID)
+	}
+
+	// config drift
+	var configDrifts []*ConfigDrift
+	if masterNode != nil {
+		for i, replicaNode := range replicaNodes {
+			configDrifts = append(configDrifts, getConfigDrifts(masterConfig, replicaNode, replicaConfigs[i])...)
+		}
+	}
+
+	nodeResultBytes, err := json.Marshal(nodeResults)
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+	configDriftBytes, err := json.Marshal(configDrifts)
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+	if configDrifts == nil {
+		configDriftBytes = []byte(defaultEmptyJSONArray)
+	}
+
+	clusterResult := NewClusterResult(ce.clusterOperationID, ce.mysqlClusterID, minScore, string(nodeResultBytes), string(configDriftBytes))
+	err = ce.getDASRepo().SaveClusterResult(clusterResult)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	msg := fmt.Sprintf("cluster healthcheck completed. cluster_operation_id: %d, total nodes: %d, failed nodes: %d, config drifts: %d",
+		ce.clusterOperationID, len(ce.nodes), failedCount, len(configDrifts))
+	if masterNode == nil {
+		msg += ". config drift was not checked because the result of the master node is not available"
+	}
+
+	return msg, nil
+}
+
+// getWorstItem returns the item name and the score of the item which has the lowest score in the result
+func getWorstItem(result healthcheck.Result) (string, int) {
+	itemScores := getItemScores(result)
+	if len(itemScores) == constant.ZeroInt {
+		return constant.EmptyString, constant.ZeroInt
+	}
+
+	worst := itemScores[constant.ZeroInt]
+	for _, is := range itemScores[1:] {
+		if is.score < worst.score {
+			worst = is
+		}
+	}
+
+	return worst.name, worst.score
+}
+
+// getConfigDrifts compares the variables of the replica with the master, and returns the variables that differ
+func getConfigDrifts(masterConfig []*GlobalVariable, replicaNode *NodeResult, replicaConfig []*GlobalVariable) []*ConfigDrift {
+	replicaVariables := make(map[string]string, len(replicaConfig))
+	for _, variable := range replicaConfig {
+		replicaVariables[variable.GetName()] = variable.GetValue()
+	}
+
+	var configDrifts []*ConfigDrift
+	for _, variable := range masterConfig {
+		if common.StringInSlice(clusterConfigDriftIgnoreList, variable.GetName()) {
+			continue
+		}
+		value, ok := replicaVariables[variable.GetName()]
+		if ok && strings.EqualFold(value, variable.GetValue()) {
+			continue
+		}
+		configDrifts = append(configDrifts, NewConfigDrift(variable.GetName(), variable.GetValue(),
+			replicaNode.MySQLServerID, replicaNode.HostIP, replicaNode.PortNum, value))
+	}
+
+	return configDrifts
+}
@@ -0,0 +1,104 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testHealthcheckMySQLClusterID       = 1
+	testClusterResultClusterOperationID = 1
+	testClusterResultScore              = 85
+	testClusterReplicaMySQLServerID     = 2
+	testClusterReplicaHostIP            = "192.168.137.12"
+	testClusterReplicaPortNum           = 3306
+)
+
+var testClusterResult = NewClusterResult(testClusterResultClusterOperationID, testHealthcheckMySQLClusterID, testClusterResultScore,
+	`[{"operation_id":1,"mysql_server_id":1,"host_ip":"192.168.137.11","port_num":3306,"is_master":true,"status":2,"weighted_average_score":85,"worst_item_name":"db_config","worst_item_score":80,"message":""}]`,
+	defaultEmptyJSONArray)
+
+func TestClusterEngine_All(t *testing.T) {
+	TestClusterEngine_getWorstItem(t)
+	TestClusterEngine_getConfigDrifts(t)
+	TestClusterResult_MarshalJSON(t)
+}
+
+func TestClusterEngine_getWorstItem(t *testing.T) {
+	asst := assert.New(t)
+
+	result := NewEmptyResult()
+	result.DBConfigScore = 90
+	result.AvgBackupFailedRatioScore = 100
+	result.StatisticFailedRatioScore = 100
+	result.CPUUsageScore = 60
+	result.IOUtilScore = 100
+	result.DiskCapacityUsageScore = 100
+	result.ConnectionUsageScore = 100
+	result.AverageActiveSessionPercentsScore = 100
+	result.CacheMissRatioScore = 100
+	result.TableRowsScore = 100
+	result.TableSizeScore = 100
+	result.SlowQueryScore = 70
+
+	name, score := getWorstItem(result)
+	asst.Equal(defaultCPUUsageItemName, name, "test getWorstItem() failed")
+	asst.Equal(60, score, "test getWorstItem() failed")
+
+	// item results take precedence over the legacy score fields
+	err := result.addItemResult(NewItemResult(constant.ZeroInt, testItemConfigName, DataSourcePrometheus, testItemResultItemWeight,
+		50, constant.EmptyString, constant.EmptyString, constant.EmptyString))
+	asst.Nil(err, common.CombineMessageWithError("test getWorstItem() failed", err))
+	name, score = getWorstItem(result)
+	asst.Equal(testItemConfigName, name, "test getWorstItem() failed")
+	asst.Equal(50, score, "test getWorstItem() failed")
+	// the failed items are not scored
+	err = result.addItemResult(&ItemResult{ItemName: defaultIOUtilItemName, Status: ItemStatusError})
+	asst.Nil(err, common.CombineMessageWithError("test getWorstItem() failed", err))
+	name, score = getWorstItem(result)
+	asst.Equal(testItemConfigName, name, "test getWorstItem() failed")
+	asst.Equal(50, score, "test getWorstItem() failed")
+}
+
+func TestClusterEngine_getConfigDrifts(t *testing.T) {
+	asst := assert.New(t)
+
+	masterConfig := []*GlobalVariable{
+		NewGlobalVariable(dbConfigSyncBinlog, "1"),
+		NewGlobalVariable(dbConfigGTIDMode, "ON"),
+		NewGlobalVariable(dbConfigBinlogFormat, "ROW"),
+		NewGlobalVariable(dbConfigReportHost, "192.168.137.11"),
+	}
+	replicaConfig := []*GlobalVariable{
+		NewGlobalVariable(dbConfigSyncBinlog, "0"),
+		NewGlobalVariable(dbConfigGTIDMode, "on"),
+		NewGlobalVariable(dbConfigReportHost, testClusterReplicaHostIP),
+	}
+	replicaNode := NewNodeResult(constant.ZeroInt, testClusterReplicaMySQLServerID, testClusterReplicaHostIP, testClusterReplicaPortNum, false)
+
+	configDrifts := getConfigDrifts(masterConfig, replicaNode, replicaConfig)
+	// sync_binlog differs, binlog_format does not exist on the replica,
+	// gtid_mode is the same with different case, report_host is ignored
+	asst.Equal(2, len(configDrifts), "test getConfigDrifts() failed")
+	asst.Equal(dbConfigSyncBinlog, configDrifts[constant.ZeroInt].VariableName, "test getConfigDrifts() failed")
+	asst.Equal("0", configDrifts[constant.ZeroInt].Value, "test getConfigDrifts() failed")
+	asst.Equal(testClusterReplicaMySQLServerID, configDrifts[constant.ZeroInt].MySQLServerID, "test getConfigDrifts() failed")
+	asst.Equal(dbConfigBinlogFormat, configDrifts[1].VariableName, "test getConfigDrifts() failed")
+}
+
+func TestClusterResult_MarshalJSON(t *testing.T) {
+	asst := assert.New(t)
+
+	jsonBytes, err := testClusterResult.MarshalJSON()
+	asst.Nil(err, common.CombineMessageWithError("test MarshalJSON() failed", err))
+	var m map[string]interface{}
+	err = json.Unmarshal(jsonBytes, &m)
+	asst.Nil(err, common.CombineMessageWithError("test MarshalJSON() failed", err))
+	nodeResult, ok := m["node_result"].([]interface{})
+	asst.True(ok, "test MarshalJSON() failed")
+	asst.Equal(1, len(nodeResult), "test MarshalJSON() failed")
+}
@@ -0,0 +1,171 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const defaultEmptyJSONArray = "[]"
+
+var _ healthcheck.ClusterResult = (*ClusterResult)(nil)
+
+// ClusterResult is the combined result of all the mysql servers of a mysql cluster
+type ClusterResult struct {
+	ID                   int       `middleware:"id" json:"id"`
+	ClusterOperationID   int       `middleware:"cluster_operation_id" json:"cluster_operation_id"`
+	MySQLClusterID       int       `middleware:"mysql_cluster_id" json:"mysql_cluster_id"`
+	WeightedAverageScore int       `middleware:"weighted_average_score" json:"weighted_average_score"`
+	NodeResult           string    `middleware:"node_result" json:"node_result"`
+	ConfigDrift          string    `middleware:"config_drift" json:"config_drift"`
+	DelFlag              int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime           time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime       time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewClusterResult returns a new *ClusterResult
+func NewClusterResult(clusterOperationID, mysqlClusterID, weightedAverageScore int, nodeResult, configDrift string) *ClusterResult {
+	return &ClusterResult{
+		ClusterOperationID:   clusterOperationID,
+		MySQLClusterID:       mysqlClusterID,
+		WeightedAverageScore: weightedAverageScore,
+		NodeResult:           nodeResult,
+		ConfigDrift:          configDrift,
+	}
+}
+
+// NewEmptyClusterResult returns an empty *ClusterResult
+func NewEmptyClusterResult() *ClusterResult {
+	return &ClusterResult{}
+}
+
+// Identity returns the identity
+func (cr *ClusterResult) Identity() int {
+	return cr.ID
+}
+
+// GetClusterOperationID returns the cluster operation id
+func (cr *ClusterResult) GetClusterOperationID() int {
+	return cr.ClusterOperationID
+}
+
+// GetMySQLClusterID returns the mysql cluster id
+func (cr *ClusterResult) GetMySQLClusterID() int {
+	return cr.MySQLClusterID
+}
+
+// GetWeightedAverageScore returns the lowest weighted average score of the mysql servers
+func (cr *ClusterResult) GetWeightedAverageScore() int {
+	return cr.WeightedAverageScore
+}
+
+// GetNodeResult returns the result summary of each mysql server in json string
+func (cr *ClusterResult) GetNodeResult() string {
+	return cr.NodeResult
+}
+
+// GetConfigDrift returns the variables that differ between the master and the replicas in json string
+func (cr *ClusterResult) GetConfigDrift() string {
+	return cr.ConfigDrift
+}
+
+// GetDelFlag returns the delete flag
+func (cr *ClusterResult) GetDelFlag() int {
+	return cr.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (cr *ClusterResult) GetCreateTime() time.Time {
+	return cr.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (cr *ClusterResult) GetLastUpdateTime() time.Time {
+	return cr.LastUpdateTime
+}
+
+// MarshalJSON marshals ClusterResult to json string,
+// node result and config drift are json strings, they will be marshaled as json arrays
+func (cr *ClusterResult) MarshalJSON() ([]byte, error) {
+	type clusterResult ClusterResult
+
+	nodeResult := cr.GetNodeResult()
+	if nodeResult == constant.EmptyString {
+		nodeResult = defaultEmptyJSONArray
+	}
+	configDrift := cr.GetConfigDrift()
+	if configDrift == constant.EmptyString {
+		configDrift = defaultEmptyJSONArray
+	}
+
+	jsonBytes, err := json.Marshal(&struct {
+		*clusterResult
+		NodeResult  json.RawMessage `json:"node_result"`
+		ConfigDrift json.RawMessage `json:"config_drift"`
+	}{
+		clusterResult: (*clusterResult)(cr),
+		NodeResult:    json.RawMessage(nodeResult),
+		ConfigDrift:   json.RawMessage(configDrift),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return jsonBytes, nil
+}
+
+// MarshalJSONWithFields marshals only specified field of the ClusterResult to json string
+func (cr *ClusterResult) MarshalJSONWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(cr, fields...)
+}
+
+// NodeResult is the result summary of a mysql server in the cluster check
+type NodeResult struct {
+	OperationID          int    `json:"operation_id"`
+	MySQLServerID        int    `json:"mysql_server_id"`
+	HostIP               string `json:"host_ip"`
+	PortNum              int    `json:"port_num"`
+	IsMaster             bool   `json:"is_master"`
+	Status               int    `json:"status"`
+	WeightedAverageScore int    `json:"weighted_average_score"`
+	WorstItemName        string `json:"worst_item_name"`
+	WorstItemScore       int    `json:"worst_item_score"`
+	Message              string `json:"message"`
+}
+
+// NewNodeResult returns a new *NodeResult
+func NewNodeResult(operationID, mysqlServerID int, hostIP string, portNum int, isMaster bool) *NodeResult {
+	return &NodeResult{
+		OperationID:   operationID,
+		MySQLServerID: mysqlServerID,
+		HostIP:        hostIP,
+		PortNum:       portNum,
+		IsMaster:      isMaster,
+	}
+}
+
+// ConfigDrift is a variable of which the value on the replica differs from the master
+type ConfigDrift struct {
+	VariableName  string `json:"variable_name"`
+	MasterValue   string `json:"master_value"`
+	MySQLServerID int    `json:"mysql_server_id"`
+	HostIP        string `json:"host_ip"`
+	PortNum       int    `json:"port_num"`
+	Value         string `json:"value"`
+}
+
+// NewConfigDrift returns a new *ConfigDrift
+func NewConfigDrift(variableName, masterValue string, mysqlServerID int, hostIP string, portNum int, value string) *ConfigDrift {
+	return &ConfigDrift{
+		VariableName:  variableName,
+		MasterValue:   masterValue,
+		MySQLServerID: mysqlServerID,
+		HostIP:        hostIP,
+		PortNum:       portNum,
+		Value:         value,
+	}
+}
@@ -0,0 +1,37 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser parses the standard cron expression which has 5 fields
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// CronExpression is a parsed standard cron expression,
+// the format is "minute hour day-of-month month day-of-week",
+// each field supports "*", "?", single value, range "a-b", list "a,b" and stride "*/n" or "a-b/n",
+// the valid range of day of week is [0, 6], 0 means sunday
+type CronExpression struct {
+	schedule cron.Schedule
+}
+
+// ParseCronExpression parses given expression and returns a *CronExpression
+func ParseCronExpression(expr string) (*CronExpression, error) {
+	schedule, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, errors.Errorf("cron expression %s is not valid. error:\n%s", expr, err.Error())
+	}
+
+	return &CronExpression{schedule: schedule}, nil
+}
+
+// Match returns if given time matches the expression, the second and smaller parts of the time are ignored
+func (ce *CronExpression) Match(t time.Time) bool {
+	t = t.Truncate(time.Minute)
+
+	// the next activation time after the previous second is t itself only if t matches the expression
+	return ce.schedule.Next(t.Add(-time.Second)).Equal(t)
+}
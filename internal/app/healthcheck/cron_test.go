@@ -0,0 +1,56 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/romberli/go-util/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCron_All(t *testing.T) {
+	TestCron_ParseCronExpression(t)
+	TestCronExpression_Match(t)
+}
+
+func TestCron_ParseCronExpression(t *testing.T) {
+	asst := assert.New(t)
+
+	for _, expr := range []string{"* * * * *", "0 2 * * 1", "*/5 0-6 1,15 * ?", "30 1 * 1-12/3 0", "5/10 * * * *"} {
+		_, err := ParseCronExpression(expr)
+		asst.Nil(err, common.CombineMessageWithError("test ParseCronExpression() failed", err))
+	}
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 7", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseCronExpression(expr)
+		asst.NotNil(err, "test ParseCronExpression() failed, expression: %s", expr)
+	}
+}
+
+func TestCronExpression_Match(t *testing.T) {
+	asst := assert.New(t)
+
+	// 2022-06-06 is monday
+	monday := time.Date(2022, time.June, 6, 2, 0, 0, 0, time.Local)
+	sunday := time.Date(2022, time.June, 5, 2, 0, 0, 0, time.Local)
+
+	ce, err := ParseCronExpression("0 2 * * 1")
+	asst.Nil(err, common.CombineMessageWithError("test Match() failed", err))
+	asst.True(ce.Match(monday), "test Match() failed")
+	asst.False(ce.Match(sunday), "test Match() failed")
+	asst.False(ce.Match(monday.Add(time.Minute)), "test Match() failed")
+	// 0 means sunday
+	ce, err = ParseCronExpression("0 2 * * 0")
+	asst.Nil(err, common.CombineMessageWithError("test Match() failed", err))
+	asst.True(ce.Match(sunday), "test Match() failed")
+	// stride
+	ce, err = ParseCronExpression("*/15 * * * *")
+	asst.Nil(err, common.CombineMessageWithError("test Match() failed", err))
+	asst.True(ce.Match(monday.Add(45*time.Minute)), "test Match() failed")
+	asst.False(ce.Match(monday.Add(50*time.Minute)), "test Match() failed")
+	// both day of month and day of week are restricted, either of them matches
+	ce, err = ParseCronExpression("0 2 5 * 1")
+	asst.Nil(err, common.CombineMessageWithError("test Match() failed", err))
+	asst.True(ce.Match(monday), "test Match() failed")
+	asst.True(ce.Match(sunday), "test Match() failed")
+	asst.False(ce.Match(monday.AddDate(0, 0, 1)), "test Match() failed")
+}
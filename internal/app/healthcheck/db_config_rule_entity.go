@@ -0,0 +1,261 @@
+package healthcheck
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	dbConfigRuleVariableNameStruct  = "VariableName"
+	dbConfigRuleComparatorStruct    = "Comparator"
+	dbConfigRuleExpectedValueStruct = "ExpectedValue"
+	dbConfigRuleMinVersionStruct    = "MinVersion"
+	dbConfigRuleMaxVersionStruct    = "MaxVersion"
+
+	dbConfigRuleComparatorEq    = "eq"
+	dbConfigRuleComparatorGe    = "ge"
+	dbConfigRuleComparatorLe    = "le"
+	dbConfigRuleComparatorIn    = "in"
+	dbConfigRuleComparatorRegex = "regex"
+
+	DBConfigRuleSeverityHigh   = 1
+	DBConfigRuleSeverityMedium = 2
+
+	// the placeholders of the expected value, they will be replaced with the values of the checked mysql server
+	dbConfigRulePlaceholderHostIP     = "${host_ip}"
+	dbConfigRulePlaceholderPortNum    = "${port_num}"
+	dbConfigRulePlaceholderServerName = "${server_name}"
+
+	dbConfigRuleVersionSuffixSeparator = "-"
+)
+
+var _ healthcheck.DBConfigRule = (*DBConfigRule)(nil)
+
+// DBConfigRule is the rule of a database variable which is checked by the db config item
+type DBConfigRule struct {
+	healthcheck.DBConfigRuleRepo
+	ID             int       `middleware:"id" json:"id"`
+	VariableName   string    `middleware:"variable_name" json:"variable_name"`
+	Comparator     string    `middleware:"comparator" json:"comparator"`
+	ExpectedValue  string    `middleware:"expected_value" json:"expected_value"`
+	Severity       int       `middleware:"severity" json:"severity"`
+	MinVersion     string    `middleware:"min_version" json:"min_version"`
+	MaxVersion     string    `middleware:"max_version" json:"max_version"`
+	DelFlag        int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime     time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewDBConfigRuleWithDefault returns a new *DBConfigRule with default DBConfigRuleRepo
+func NewDBConfigRuleWithDefault(variableName, comparator, expectedValue string, severity int, minVersion, maxVersion string) *DBConfigRule {
+	return &DBConfigRule{
+		DBConfigRuleRepo: NewDBConfigRuleRepoWithGlobal(),
+		VariableName:     variableName,
+		Comparator:       comparator,
+		ExpectedValue:    expectedValue,
+		Severity:         severity,
+		MinVersion:       minVersion,
+		MaxVersion:       maxVersion,
+	}
+}
+
+// NewEmptyDBConfigRuleWithGlobal returns a new *DBConfigRule with global repository
+func NewEmptyDBConfigRuleWithGlobal() *DBConfigRule {
+	return &DBConfigRule{DBConfigRuleRepo: NewDBConfigRuleRepoWithGlobal()}
+}
+
+// NewDBConfigRuleWithMapAndRandom returns a new *DBConfigRule with given map
+func NewDBConfigRuleWithMapAndRandom(fields map[string]interface{}) (*DBConfigRule, error) {
+	dcr := &DBConfigRule{Severity: DBConfigRuleSeverityHigh}
+	err := common.SetValuesWithMapAndRandom(dcr, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return dcr, nil
+}
+
+// Identity returns the identity
+func (dcr *DBConfigRule) Identity() int {
+	return dcr.ID
+}
+
+// GetVariableName returns the variable name
+func (dcr *DBConfigRule) GetVariableName() string {
+	return dcr.VariableName
+}
+
+// GetComparator returns the comparator
+func (dcr *DBConfigRule) GetComparator() string {
+	return dcr.Comparator
+}
+
+// GetExpectedValue returns the expected value, it may contain placeholders
+func (dcr *DBConfigRule) GetExpectedValue() string {
+	return dcr.ExpectedValue
+}
+
+// GetSeverity returns the severity
+func (dcr *DBConfigRule) GetSeverity() int {
+	return dcr.Severity
+}
+
+// GetMinVersion returns the min mysql version, it is inclusive, empty means no limit
+func (dcr *DBConfigRule) GetMinVersion() string {
+	return dcr.MinVersion
+}
+
+// GetMaxVersion returns the max mysql version, it is exclusive, empty means no limit
+func (dcr *DBConfigRule) GetMaxVersion() string {
+	return dcr.MaxVersion
+}
+
+// GetDelFlag returns the delete flag
+func (dcr *DBConfigRule) GetDelFlag() int {
+	return dcr.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (dcr *DBConfigRule) GetCreateTime() time.Time {
+	return dcr.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (dcr *DBConfigRule) GetLastUpdateTime() time.Time {
+	return dcr.LastUpdateTime
+}
+
+// IsApplicable returns if the rule is applicable to the mysql version
+func (dcr *DBConfigRule) IsApplicable(mysqlVersion string) (bool, error) {
+	// the suffix of the mysql version such as "-log" will be parsed as a pre-release,
+	// which is less than the release version, so it must be removed before comparing
+	v, err := version.NewVersion(strings.SplitN(mysqlVersion, dbConfigRuleVersionSuffixSeparator, 2)[constant.ZeroInt])
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if dcr.GetMinVersion() != constant.EmptyString {
+		minVersion, err := version.NewVersion(dcr.GetMinVersion())
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if v.LessThan(minVersion) {
+			return false, nil
+		}
+	}
+	if dcr.GetMaxVersion() != constant.EmptyString {
+		maxVersion, err := version.NewVersion(dcr.GetMaxVersion())
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if !v.LessThan(maxVersion) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// getExpectedValue returns the expected value of which the placeholders are replaced with the values of the mysql server
+func (dcr *DBConfigRule) getExpectedValue(mysqlServer metadata.MySQLServer) string {
+	return strings.NewReplacer(
+		dbConfigRulePlaceholderHostIP, mysqlServer.GetHostIP(),
+		dbConfigRulePlaceholderPortNum, strconv.Itoa(mysqlServer.GetPortNum()),
+		dbConfigRulePlaceholderServerName, mysqlServer.GetServerName(),
+	).Replace(dcr.GetExpectedValue())
+}
+
+// GetAdvice returns the advice of the rule for the mysql server, the placeholders of the expected value are replaced
+func (dcr *DBConfigRule) GetAdvice(mysqlServer metadata.MySQLServer) string {
+	expectedValue := dcr.getExpectedValue(mysqlServer)
+
+	switch dcr.GetComparator() {
+	case dbConfigRuleComparatorGe:
+		return ">= " + expectedValue
+	case dbConfigRuleComparatorLe:
+		return "<= " + expectedValue
+	case dbConfigRuleComparatorRegex:
+		return "matches " + expectedValue
+	default:
+		return expectedValue
+	}
+}
+
+// Check checks if the variable value of the mysql server matches the rule,
+// the string values are compared case-insensitively
+func (dcr *DBConfigRule) Check(value string, mysqlServer metadata.MySQLServer) (bool, error) {
+	expectedValue := dcr.getExpectedValue(mysqlServer)
+
+	switch dcr.GetComparator() {
+	case dbConfigRuleComparatorEq:
+		return strings.EqualFold(value, expectedValue), nil
+	case dbConfigRuleComparatorGe, dbConfigRuleComparatorLe:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		expected, err := strconv.ParseFloat(expectedValue, 64)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if dcr.GetComparator() == dbConfigRuleComparatorGe {
+			return v >= expected, nil
+		}
+
+		return v <= expected, nil
+	case dbConfigRuleComparatorIn:
+		for _, expected := range strings.Split(expectedValue, constant.CommaString) {
+			if strings.EqualFold(value, strings.TrimSpace(expected)) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case dbConfigRuleComparatorRegex:
+		matched, err := regexp.MatchString(expectedValue, value)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+
+		return matched, nil
+	default:
+		return false, errors.Errorf("comparator must be one of [%s, %s, %s, %s, %s], %s is not valid",
+			dbConfigRuleComparatorEq, dbConfigRuleComparatorGe, dbConfigRuleComparatorLe, dbConfigRuleComparatorIn,
+			dbConfigRuleComparatorRegex, dcr.GetComparator())
+	}
+}
+
+// Set sets entity with given fields, key is the field name and value is the relevant value of the key
+func (dcr *DBConfigRule) Set(fields map[string]interface{}) error {
+	for fieldName, fieldValue := range fields {
+		err := common.SetValueOfStruct(dcr, fieldName, fieldValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete sets DelFlag to 1
+func (dcr *DBConfigRule) Delete() {
+	dcr.DelFlag = 1
+}
+
+// MarshalJSON marshals DBConfigRule to json string
+func (dcr *DBConfigRule) MarshalJSON() ([]byte, error) {
+	return common.MarshalStructWithTag(dcr, constant.DefaultMarshalTag)
+}
+
+// MarshalJSONWithFields marshals only specified fields of DBConfigRule to json string
+func (dcr *DBConfigRule) MarshalJSONWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(dcr, fields...)
+}
@@ -0,0 +1,163 @@
+package healthcheck
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware"
+	"github.com/romberli/log"
+)
+
+var _ healthcheck.DBConfigRuleRepo = (*DBConfigRuleRepo)(nil)
+
+// DBConfigRuleRepo is the repository of the healthcheck db config rule
+type DBConfigRuleRepo struct {
+	Database middleware.Pool
+}
+
+// NewDBConfigRuleRepo returns healthcheck.DBConfigRuleRepo with given middleware.Pool
+func NewDBConfigRuleRepo(db middleware.Pool) healthcheck.DBConfigRuleRepo {
+	return newDBConfigRuleRepo(db)
+}
+
+// NewDBConfigRuleRepoWithGlobal returns healthcheck.DBConfigRuleRepo with global mysql pool
+func NewDBConfigRuleRepoWithGlobal() healthcheck.DBConfigRuleRepo {
+	return newDBConfigRuleRepo(global.DASMySQLPool)
+}
+
+// newDBConfigRuleRepo returns *DBConfigRuleRepo with given middleware.Pool
+func newDBConfigRuleRepo(db middleware.Pool) *DBConfigRuleRepo {
+	return &DBConfigRuleRepo{Database: db}
+}
+
+// Execute executes given command and placeholders on the middleware
+func (dcrr *DBConfigRuleRepo) Execute(command string, args ...interface{}) (middleware.Result, error) {
+	conn, err := dcrr.Database.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = conn.Close()
+		if err != nil {
+			log.Errorf("healthcheck DBConfigRuleRepo.Execute(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	return conn.Execute(command, args...)
+}
+
+// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+func (dcrr *DBConfigRuleRepo) Transaction() (middleware.Transaction, error) {
+	return dcrr.Database.Transaction()
+}
+
+// GetAll gets all db config rules from the middleware
+func (dcrr *DBConfigRuleRepo) GetAll() ([]healthcheck.DBConfigRule, error) {
+	sql := `
+		select id, variable_name, comparator, expected_value, severity, min_version, max_version,
+			   del_flag, create_time, last_update_time
+		from t_hc_db_config_rule
+		where del_flag = 0
+		order by id;
+	`
+	log.Debugf("healthcheck DBConfigRuleRepo.GetAll() sql: \n%s", sql)
+
+	result, err := dcrr.Execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	// init []healthcheck.DBConfigRule
+	ruleList := make([]healthcheck.DBConfigRule, result.RowNumber())
+	for i := range ruleList {
+		ruleList[i] = NewEmptyDBConfigRuleWithGlobal()
+	}
+	// map to struct
+	err = result.MapToStructSlice(ruleList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleList, nil
+}
+
+// GetByID gets a db config rule by the identity from the middleware
+func (dcrr *DBConfigRuleRepo) GetByID(id int) (healthcheck.DBConfigRule, error) {
+	sql := `
+		select id, variable_name, comparator, expected_value, severity, min_version, max_version,
+			   del_flag, create_time, last_update_time
+		from t_hc_db_config_rule
+		where del_flag = 0
+		  and id = ?;
+	`
+	log.Debugf("healthcheck DBConfigRuleRepo.GetByID() sql: \n%s\nplaceholders: %d", sql, id)
+
+	result, err := dcrr.Execute(sql, id)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, errors.Errorf("healthcheck DBConfigRuleRepo.GetByID(): data does not exists, id: %d", id)
+	case 1:
+		rule := NewEmptyDBConfigRuleWithGlobal()
+		// map to struct
+		err = result.MapToStructByRowIndex(rule, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+
+		return rule, nil
+	default:
+		return nil, errors.Errorf("healthcheck DBConfigRuleRepo.GetByID(): duplicate key exists, id: %d", id)
+	}
+}
+
+// Create creates a db config rule in the middleware
+func (dcrr *DBConfigRuleRepo) Create(rule healthcheck.DBConfigRule) (healthcheck.DBConfigRule, error) {
+	sql := `
+		insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+		values(?, ?, ?, ?, ?, ?);
+	`
+	log.Debugf("healthcheck DBConfigRuleRepo.Create() insert sql: \n%s\nplaceholders: %s, %s, %s, %d, %s, %s",
+		sql, rule.GetVariableName(), rule.GetComparator(), rule.GetExpectedValue(), rule.GetSeverity(),
+		rule.GetMinVersion(), rule.GetMaxVersion())
+	// execute
+	result, err := dcrr.Execute(sql, rule.GetVariableName(), rule.GetComparator(), rule.GetExpectedValue(), rule.GetSeverity(),
+		rule.GetMinVersion(), rule.GetMaxVersion())
+	if err != nil {
+		return nil, err
+	}
+	// get id, the variable name is not unique as a variable may have different rules for different mysql versions
+	id, err := result.LastInsertID()
+	if err != nil {
+		return nil, err
+	}
+	// get db config rule
+	return dcrr.GetByID(id)
+}
+
+// Update updates the db config rule in the middleware
+func (dcrr *DBConfigRuleRepo) Update(rule healthcheck.DBConfigRule) error {
+	sql := `
+		update t_hc_db_config_rule set variable_name = ?, comparator = ?, expected_value = ?, severity = ?,
+		min_version = ?, max_version = ?, del_flag = ?
+		where id = ?;
+	`
+	log.Debugf("healthcheck DBConfigRuleRepo.Update() update sql: \n%s\nplaceholders: %s, %s, %s, %d, %s, %s, %d, %d",
+		sql, rule.GetVariableName(), rule.GetComparator(), rule.GetExpectedValue(), rule.GetSeverity(),
+		rule.GetMinVersion(), rule.GetMaxVersion(), rule.GetDelFlag(), rule.Identity())
+	_, err := dcrr.Execute(sql, rule.GetVariableName(), rule.GetComparator(), rule.GetExpectedValue(), rule.GetSeverity(),
+		rule.GetMinVersion(), rule.GetMaxVersion(), rule.GetDelFlag(), rule.Identity())
+
+	return err
+}
+
+// Delete deletes the db config rule in the middleware
+func (dcrr *DBConfigRuleRepo) Delete(id int) error {
+	sql := `delete from t_hc_db_config_rule where id = ?;`
+	log.Debugf("healthcheck DBConfigRuleRepo.Delete() delete sql: \n%s\nplaceholders: %d", sql, id)
+	_, err := dcrr.Execute(sql, id)
+
+	return err
+}
@@ -0,0 +1,191 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testDBConfigRuleVariableName        = "replica_parallel_workers"
+	testDBConfigRuleExpectedValue       = "16"
+	testDBConfigRuleUpdateExpectedValue = "32"
+	testDBConfigRuleMinVersion          = "8.0.26"
+)
+
+var testDBConfigRuleRepo *DBConfigRuleRepo
+
+func init() {
+	testInitDASMySQLPool()
+	testDBConfigRuleRepo = newDBConfigRuleRepo(global.DASMySQLPool)
+}
+
+func testCreateDBConfigRule() (healthcheck.DBConfigRule, error) {
+	rule := NewDBConfigRuleWithDefault(
+		testDBConfigRuleVariableName,
+		dbConfigRuleComparatorGe,
+		testDBConfigRuleExpectedValue,
+		DBConfigRuleSeverityMedium,
+		testDBConfigRuleMinVersion,
+		constant.EmptyString,
+	)
+
+	return testDBConfigRuleRepo.Create(rule)
+}
+
+func testDeleteDBConfigRuleByID(id int) error {
+	return testDBConfigRuleRepo.Delete(id)
+}
+
+func TestDBConfigRuleRepo_All(t *testing.T) {
+	TestDBConfigRuleRepo_Execute(t)
+	TestDBConfigRuleRepo_Transaction(t)
+	TestDBConfigRuleRepo_GetAll(t)
+	TestDBConfigRuleRepo_GetByID(t)
+	TestDBConfigRuleRepo_Create(t)
+	TestDBConfigRuleRepo_Update(t)
+	TestDBConfigRuleRepo_Delete(t)
+	TestDBConfigRule_IsApplicable(t)
+	TestDBConfigRule_Check(t)
+}
+
+func TestDBConfigRuleRepo_Execute(t *testing.T) {
+	asst := assert.New(t)
+
+	sql := `select 1;`
+	result, err := testDBConfigRuleRepo.Execute(sql)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	r, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	asst.Equal(1, r, "test Execute() failed")
+}
+
+func TestDBConfigRuleRepo_Transaction(t *testing.T) {
+	asst := assert.New(t)
+
+	tx, err := testDBConfigRuleRepo.Transaction()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Begin()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Rollback()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+}
+
+func TestDBConfigRuleRepo_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	rules, err := testDBConfigRuleRepo.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	asst.NotZero(len(rules), "test GetAll() failed")
+	// delete
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestDBConfigRuleRepo_GetByID(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	entity, err := testDBConfigRuleRepo.GetByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	asst.Equal(testDBConfigRuleVariableName, entity.GetVariableName(), "test GetByID() failed")
+	// delete
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+}
+
+func TestDBConfigRuleRepo_Create(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	asst.Equal(testDBConfigRuleMinVersion, rule.GetMinVersion(), "test Create() failed")
+	asst.Equal(DBConfigRuleSeverityMedium, rule.GetSeverity(), "test Create() failed")
+	// delete
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+}
+
+func TestDBConfigRuleRepo_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = rule.Set(map[string]interface{}{dbConfigRuleExpectedValueStruct: testDBConfigRuleUpdateExpectedValue})
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testDBConfigRuleRepo.Update(rule)
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	entity, err := testDBConfigRuleRepo.GetByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testDBConfigRuleUpdateExpectedValue, entity.GetExpectedValue(), "test Update() failed")
+	// delete
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestDBConfigRuleRepo_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	_, err = testDBConfigRuleRepo.GetByID(rule.Identity())
+	asst.NotNil(err, "test Delete() failed")
+}
+
+func TestDBConfigRule_IsApplicable(t *testing.T) {
+	asst := assert.New(t)
+
+	rule := &DBConfigRule{MinVersion: "5.7", MaxVersion: testDBConfigRuleMinVersion}
+	ok, err := rule.IsApplicable("5.7.21-log")
+	asst.Nil(err, common.CombineMessageWithError("test IsApplicable() failed", err))
+	asst.True(ok, "test IsApplicable() failed")
+	// the max version is exclusive
+	ok, err = rule.IsApplicable("8.0.26-log")
+	asst.Nil(err, common.CombineMessageWithError("test IsApplicable() failed", err))
+	asst.False(ok, "test IsApplicable() failed")
+	ok, err = rule.IsApplicable("5.6.51")
+	asst.Nil(err, common.CombineMessageWithError("test IsApplicable() failed", err))
+	asst.False(ok, "test IsApplicable() failed")
+	// no limit
+	ok, err = (&DBConfigRule{}).IsApplicable("8.0.30")
+	asst.Nil(err, common.CombineMessageWithError("test IsApplicable() failed", err))
+	asst.True(ok, "test IsApplicable() failed")
+}
+
+func TestDBConfigRule_Check(t *testing.T) {
+	asst := assert.New(t)
+
+	mysqlServer := testOperationInfo.GetMySQLServer()
+
+	ok, err := (&DBConfigRule{Comparator: dbConfigRuleComparatorEq, ExpectedValue: "ON"}).Check("on", mysqlServer)
+	asst.Nil(err, common.CombineMessageWithError("test Check() failed", err))
+	asst.True(ok, "test Check() failed")
+	ok, err = (&DBConfigRule{Comparator: dbConfigRuleComparatorGe, ExpectedValue: testDBConfigRuleExpectedValue}).Check("8", mysqlServer)
+	asst.Nil(err, common.CombineMessageWithError("test Check() failed", err))
+	asst.False(ok, "test Check() failed")
+	ok, err = (&DBConfigRule{Comparator: dbConfigRuleComparatorLe, ExpectedValue: testDBConfigRuleExpectedValue}).Check("8", mysqlServer)
+	asst.Nil(err, common.CombineMessageWithError("test Check() failed", err))
+	asst.True(ok, "test Check() failed")
+	ok, err = (&DBConfigRule{Comparator: dbConfigRuleComparatorIn, ExpectedValue: "O_DIRECT, O_DIRECT_NO_FSYNC"}).Check("o_direct_no_fsync", mysqlServer)
+	asst.Nil(err, common.CombineMessageWithError("test Check() failed", err))
+	asst.True(ok, "test Check() failed")
+	ok, err = (&DBConfigRule{Comparator: dbConfigRuleComparatorRegex, ExpectedValue: "^utf8mb4"}).Check("utf8mb4_general_ci", mysqlServer)
+	asst.Nil(err, common.CombineMessageWithError("test Check() failed", err))
+	asst.True(ok, "test Check() failed")
+	// placeholders
+	ok, err = (&DBConfigRule{Comparator: dbConfigRuleComparatorIn, ExpectedValue: "${host_ip},${server_name}"}).Check(mysqlServer.GetHostIP(), mysqlServer)
+	asst.Nil(err, common.CombineMessageWithError("test Check() failed", err))
+	asst.True(ok, "test Check() failed")
+	// invalid comparator
+	_, err = (&DBConfigRule{Comparator: "ne"}).Check(constant.EmptyString, mysqlServer)
+	asst.NotNil(err, "test Check() failed")
+}
@@ -0,0 +1,205 @@
+package healthcheck
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	dbConfigRuleDBConfigRulesStruct = "DBConfigRules"
+
+	dbConfigRulePlaceholderPrefix = "${"
+)
+
+var _ healthcheck.DBConfigRuleService = (*DBConfigRuleService)(nil)
+
+// DBConfigRuleService is the service of the healthcheck db config rule
+type DBConfigRuleService struct {
+	healthcheck.DBConfigRuleRepo
+	DBConfigRules []healthcheck.DBConfigRule `json:"db_config_rules"`
+}
+
+// NewDBConfigRuleService returns a new healthcheck.DBConfigRuleService
+func NewDBConfigRuleService(repo healthcheck.DBConfigRuleRepo) healthcheck.DBConfigRuleService {
+	return newDBConfigRuleService(repo)
+}
+
+// NewDBConfigRuleServiceWithDefault returns a new healthcheck.DBConfigRuleService with default repository
+func NewDBConfigRuleServiceWithDefault() healthcheck.DBConfigRuleService {
+	return newDBConfigRuleService(NewDBConfigRuleRepoWithGlobal())
+}
+
+// newDBConfigRuleService returns a new *DBConfigRuleService
+func newDBConfigRuleService(repo healthcheck.DBConfigRuleRepo) *DBConfigRuleService {
+	return &DBConfigRuleService{
+		DBConfigRuleRepo: repo,
+		DBConfigRules:    []healthcheck.DBConfigRule{},
+	}
+}
+
+// GetDBConfigRules returns the db config rules of the service
+func (dcrs *DBConfigRuleService) GetDBConfigRules() []healthcheck.DBConfigRule {
+	return dcrs.DBConfigRules
+}
+
+// GetAll gets all db config rules from the middleware
+func (dcrs *DBConfigRuleService) GetAll() error {
+	var err error
+
+	dcrs.DBConfigRules, err = dcrs.DBConfigRuleRepo.GetAll()
+
+	return err
+}
+
+// GetByID gets a db config rule of the given id from the middleware
+func (dcrs *DBConfigRuleService) GetByID(id int) error {
+	rule, err := dcrs.DBConfigRuleRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	dcrs.DBConfigRules = nil
+	dcrs.DBConfigRules = append(dcrs.DBConfigRules, rule)
+
+	return nil
+}
+
+// Create creates a db config rule in the middleware
+func (dcrs *DBConfigRuleService) Create(fields map[string]interface{}) error {
+	// check fields
+	for _, field := range []string{
+		dbConfigRuleVariableNameStruct,
+		dbConfigRuleComparatorStruct,
+		dbConfigRuleExpectedValueStruct,
+	} {
+		_, ok := fields[field]
+		if !ok {
+			return message.NewMessage(message.ErrFieldNotExists, field)
+		}
+	}
+	// create a new entity
+	ruleInfo, err := NewDBConfigRuleWithMapAndRandom(fields)
+	if err != nil {
+		return err
+	}
+	err = dcrs.validate(ruleInfo)
+	if err != nil {
+		return err
+	}
+	// insert into middleware
+	rule, err := dcrs.DBConfigRuleRepo.Create(ruleInfo)
+	if err != nil {
+		return err
+	}
+
+	dcrs.DBConfigRules = nil
+	dcrs.DBConfigRules = append(dcrs.DBConfigRules, rule)
+
+	return nil
+}
+
+// Update gets a db config rule of the given id from the middleware,
+// and then updates its fields that was specified in fields argument,
+// key is the filed name and value is the new field value,
+// it saves the changes to the middleware
+func (dcrs *DBConfigRuleService) Update(id int, fields map[string]interface{}) error {
+	err := dcrs.GetByID(id)
+	if err != nil {
+		return err
+	}
+	err = dcrs.DBConfigRules[constant.ZeroInt].Set(fields)
+	if err != nil {
+		return err
+	}
+	err = dcrs.validate(dcrs.DBConfigRules[constant.ZeroInt])
+	if err != nil {
+		return err
+	}
+
+	return dcrs.DBConfigRuleRepo.Update(dcrs.DBConfigRules[constant.ZeroInt])
+}
+
+// Delete deletes the db config rule of given id in the middleware
+func (dcrs *DBConfigRuleService) Delete(id int) error {
+	err := dcrs.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	return dcrs.DBConfigRuleRepo.Delete(id)
+}
+
+// validate validates if the db config rule is valid
+func (dcrs *DBConfigRuleService) validate(rule healthcheck.DBConfigRule) error {
+	if rule.GetVariableName() == constant.EmptyString {
+		return errors.New("variable name must not be empty")
+	}
+	// the placeholders could only be replaced when checking the mysql server,
+	// so the expected value which contains placeholders will not be validated
+	hasPlaceholder := strings.Contains(rule.GetExpectedValue(), dbConfigRulePlaceholderPrefix)
+	switch rule.GetComparator() {
+	case dbConfigRuleComparatorEq, dbConfigRuleComparatorIn:
+	case dbConfigRuleComparatorGe, dbConfigRuleComparatorLe:
+		if !hasPlaceholder {
+			_, err := strconv.ParseFloat(rule.GetExpectedValue(), 64)
+			if err != nil {
+				return errors.Errorf("expected value must be a number when the comparator is %s, %s is not valid",
+					rule.GetComparator(), rule.GetExpectedValue())
+			}
+		}
+	case dbConfigRuleComparatorRegex:
+		if !hasPlaceholder {
+			_, err := regexp.Compile(rule.GetExpectedValue())
+			if err != nil {
+				return errors.Errorf("expected value must be a valid regular expression when the comparator is %s, %s is not valid",
+					rule.GetComparator(), rule.GetExpectedValue())
+			}
+		}
+	default:
+		return errors.Errorf("comparator must be one of [%s, %s, %s, %s, %s], %s is not valid",
+			dbConfigRuleComparatorEq, dbConfigRuleComparatorGe, dbConfigRuleComparatorLe, dbConfigRuleComparatorIn,
+			dbConfigRuleComparatorRegex, rule.GetComparator())
+	}
+	if rule.GetSeverity() != DBConfigRuleSeverityHigh && rule.GetSeverity() != DBConfigRuleSeverityMedium {
+		return errors.Errorf("severity must be one of [%d, %d], %d is not valid",
+			DBConfigRuleSeverityHigh, DBConfigRuleSeverityMedium, rule.GetSeverity())
+	}
+	var minVersion, maxVersion *version.Version
+	var err error
+	if rule.GetMinVersion() != constant.EmptyString {
+		minVersion, err = version.NewVersion(rule.GetMinVersion())
+		if err != nil {
+			return errors.Errorf("min version is not a valid version, %s is not valid", rule.GetMinVersion())
+		}
+	}
+	if rule.GetMaxVersion() != constant.EmptyString {
+		maxVersion, err = version.NewVersion(rule.GetMaxVersion())
+		if err != nil {
+			return errors.Errorf("max version is not a valid version, %s is not valid", rule.GetMaxVersion())
+		}
+	}
+	if minVersion != nil && maxVersion != nil && !minVersion.LessThan(maxVersion) {
+		return errors.Errorf("min version must be less than max version, min version: %s, max version: %s",
+			rule.GetMinVersion(), rule.GetMaxVersion())
+	}
+
+	return nil
+}
+
+// Marshal marshals DBConfigRuleService.DBConfigRules to json bytes
+func (dcrs *DBConfigRuleService) Marshal() ([]byte, error) {
+	return dcrs.MarshalWithFields(dbConfigRuleDBConfigRulesStruct)
+}
+
+// MarshalWithFields marshals only specified fields of the DBConfigRuleService to json bytes
+func (dcrs *DBConfigRuleService) MarshalWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(dcrs, fields...)
+}
@@ -0,0 +1,119 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDBConfigRuleService *DBConfigRuleService
+
+func init() {
+	testInitDASMySQLPool()
+	testDBConfigRuleService = newDBConfigRuleService(NewDBConfigRuleRepoWithGlobal())
+}
+
+func testDBConfigRuleFields() map[string]interface{} {
+	return map[string]interface{}{
+		dbConfigRuleVariableNameStruct:  testDBConfigRuleVariableName,
+		dbConfigRuleComparatorStruct:    dbConfigRuleComparatorGe,
+		dbConfigRuleExpectedValueStruct: testDBConfigRuleExpectedValue,
+	}
+}
+
+func TestDBConfigRuleService_All(t *testing.T) {
+	TestDBConfigRuleService_GetAll(t)
+	TestDBConfigRuleService_GetByID(t)
+	TestDBConfigRuleService_Create(t)
+	TestDBConfigRuleService_Update(t)
+	TestDBConfigRuleService_Delete(t)
+	TestDBConfigRuleService_Marshal(t)
+}
+
+func TestDBConfigRuleService_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDBConfigRuleService.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestDBConfigRuleService_GetByID(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	err = testDBConfigRuleService.GetByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	asst.Equal(testDBConfigRuleVariableName, testDBConfigRuleService.GetDBConfigRules()[constant.ZeroInt].GetVariableName(), "test GetByID() failed")
+	// delete
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+}
+
+func TestDBConfigRuleService_Create(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDBConfigRuleService.Create(testDBConfigRuleFields())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	asst.Equal(DBConfigRuleSeverityHigh, testDBConfigRuleService.GetDBConfigRules()[constant.ZeroInt].GetSeverity(), "test Create() failed")
+	// delete
+	err = testDeleteDBConfigRuleByID(testDBConfigRuleService.GetDBConfigRules()[constant.ZeroInt].Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	// invalid comparator
+	fields := testDBConfigRuleFields()
+	fields[dbConfigRuleComparatorStruct] = "ne"
+	err = testDBConfigRuleService.Create(fields)
+	asst.NotNil(err, "test Create() failed")
+	// invalid expected value
+	fields = testDBConfigRuleFields()
+	fields[dbConfigRuleExpectedValueStruct] = "ON"
+	err = testDBConfigRuleService.Create(fields)
+	asst.NotNil(err, "test Create() failed")
+	// invalid version range
+	fields = testDBConfigRuleFields()
+	fields[dbConfigRuleMinVersionStruct] = testDBConfigRuleMinVersion
+	fields[dbConfigRuleMaxVersionStruct] = "5.7"
+	err = testDBConfigRuleService.Create(fields)
+	asst.NotNil(err, "test Create() failed")
+}
+
+func TestDBConfigRuleService_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testDBConfigRuleService.Update(rule.Identity(), map[string]interface{}{dbConfigRuleExpectedValueStruct: testDBConfigRuleUpdateExpectedValue})
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testDBConfigRuleService.GetByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testDBConfigRuleUpdateExpectedValue, testDBConfigRuleService.GetDBConfigRules()[constant.ZeroInt].GetExpectedValue(), "test Update() failed")
+	// delete
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestDBConfigRuleService_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testDBConfigRuleService.Delete(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+}
+
+func TestDBConfigRuleService_Marshal(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	err = testDBConfigRuleService.GetByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	jsonBytes, err := testDBConfigRuleService.Marshal()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	t.Log(string(jsonBytes))
+	// delete
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+}
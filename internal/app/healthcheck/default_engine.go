@@ -1,27 +1,25 @@
 package healthcheck
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/romberli/das/config"
 	"github.com/romberli/das/internal/app/alert"
-	"github.com/romberli/das/internal/app/metadata"
-	"github.com/romberli/das/internal/app/sqladvisor"
 	"github.com/romberli/das/internal/dependency/healthcheck"
-	depquery "github.com/romberli/das/internal/dependency/query"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
 	"github.com/romberli/das/pkg/message"
 	msghc "github.com/romberli/das/pkg/message/healthcheck"
-	util "github.com/romberli/das/pkg/util/query"
 	"github.com/romberli/go-multierror"
 	"github.com/romberli/go-util/common"
 	"github.com/romberli/go-util/constant"
 	"github.com/romberli/go-util/linux"
-	"github.com/romberli/go-util/middleware/sql/statement"
 	"github.com/romberli/log"
+	"github.com/spf13/viper"
 )
 
 const (
@@ -53,15 +51,19 @@ const (
 )
 
 var (
-	_ healthcheck.Engine = (*DefaultEngine)(nil)
+	_ healthcheck.Engine   = (*DefaultEngine)(nil)
+	_ healthcheck.CheckEnv = (*DefaultEngine)(nil)
 
 	ignoreDBList = []string{"information_schema", "performance_schema", "mysql", "test", "sys"}
 )
 
 // DefaultEngine work for health check module
 type DefaultEngine struct {
+	ctx                  context.Context
+	cancel               context.CancelFunc
 	operationInfo        healthcheck.OperationInfo
 	engineConfig         healthcheck.EngineConfig
+	dbConfigRules        []healthcheck.DBConfigRule
 	result               *Result
 	mountPoints          []string
 	devices              []string
@@ -69,24 +71,34 @@ type DefaultEngine struct {
 	applicationMySQLRepo healthcheck.ApplicationMySQLRepo
 	prometheusRepo       healthcheck.PrometheusRepo
 	queryRepo            healthcheck.QueryRepo
+	snapshot             *Snapshot
+	startTime            time.Time
+	claimedStatus        int
 }
 
-// NewDefaultEngine returns a new healthcheck.DefaultEngine
-func NewDefaultEngine(operationInfo healthcheck.OperationInfo,
+// NewDefaultEngine returns a new healthcheck.DefaultEngine,
+// ctx should be the same context used by the repositories, cancel will be called when the engine finishes running
+func NewDefaultEngine(ctx context.Context,
+	cancel context.CancelFunc,
+	operationInfo healthcheck.OperationInfo,
 	dasRepo healthcheck.DASRepo,
 	applicationMySQLRepo healthcheck.ApplicationMySQLRepo,
 	prometheusRepo healthcheck.PrometheusRepo,
 	queryRepo healthcheck.QueryRepo) healthcheck.Engine {
-	return newDefaultEngine(operationInfo, dasRepo, applicationMySQLRepo, prometheusRepo, queryRepo)
+	return newDefaultEngine(ctx, cancel, operationInfo, dasRepo, applicationMySQLRepo, prometheusRepo, queryRepo)
 }
 
 // newDefaultEngine returns a new *DefaultEngine
-func newDefaultEngine(operationInfo healthcheck.OperationInfo,
+func newDefaultEngine(ctx context.Context,
+	cancel context.CancelFunc,
+	operationInfo healthcheck.OperationInfo,
 	dasRepo healthcheck.DASRepo,
 	applicationMySQLRepo healthcheck.ApplicationMySQLRepo,
 	prometheusRepo healthcheck.PrometheusRepo,
 	queryRepo healthcheck.QueryRepo) *DefaultEngine {
 	return &DefaultEngine{
+		ctx:                  ctx,
+		cancel:               cancel,
 		operationInfo:        operationInfo,
 		engineConfig:         NewEmptyDefaultEngineConfig(),
 		result:               NewEmptyResultWithOperationIDAndHostInfo(operationInfo.GetOperationID(), operationInfo.GetMySQLServer().GetHostIP(), operationInfo.GetMySQLServer().GetPortNum()),
@@ -112,8 +124,8 @@ func (de *DefaultEngine) getResult() healthcheck.Result {
 	return de.result
 }
 
-// getMountPoints returns the mount points
-func (de *DefaultEngine) getMountPoints() []string {
+// GetMountPoints returns the mount points of the mysql directories
+func (de *DefaultEngine) GetMountPoints() []string {
 	return de.mountPoints
 }
 
@@ -127,29 +139,54 @@ func (de *DefaultEngine) getDASRepo() healthcheck.DASRepo {
 	return de.dasRepo
 }
 
-// getApplicationMySQLRepo returns the application mysql repository
-func (de *DefaultEngine) getApplicationMySQLRepo() healthcheck.ApplicationMySQLRepo {
+// GetApplicationMySQLRepo returns the application mysql repository
+func (de *DefaultEngine) GetApplicationMySQLRepo() healthcheck.ApplicationMySQLRepo {
 	return de.applicationMySQLRepo
 }
 
-// getPrometheusRepo returns the prometheus repository
-func (de *DefaultEngine) getPrometheusRepo() healthcheck.PrometheusRepo {
+// GetPrometheusRepo returns the prometheus repository
+func (de *DefaultEngine) GetPrometheusRepo() healthcheck.PrometheusRepo {
 	return de.prometheusRepo
 }
 
-// getQueryRepo returns the query repository
-func (de *DefaultEngine) getQueryRepo() healthcheck.QueryRepo {
+// GetQueryRepo returns the query repository
+func (de *DefaultEngine) GetQueryRepo() healthcheck.QueryRepo {
 	return de.queryRepo
 }
 
-// getItemConfig returns *DefaultItemConfig with given item name
-func (de *DefaultEngine) getItemConfig(item string) healthcheck.ItemConfig {
+// getSnapshot returns the snapshot, it returns nil if the inputs are not recorded
+func (de *DefaultEngine) getSnapshot() *Snapshot {
+	return de.snapshot
+}
+
+// recordSnapshot wraps the repositories, so that the inputs got from them are recorded into the snapshot,
+// the snapshot will be saved with the result, it must be called before running the engine
+func (de *DefaultEngine) recordSnapshot() {
+	de.snapshot = NewSnapshot(de.GetOperationInfo())
+	de.applicationMySQLRepo = newRecordingApplicationMySQLRepo(de.applicationMySQLRepo, de.snapshot)
+	de.prometheusRepo = newRecordingPrometheusRepo(de.prometheusRepo, de.snapshot)
+	de.queryRepo = newRecordingQueryRepo(de.queryRepo, de.snapshot)
+}
+
+// GetItemConfig returns the config of given item
+func (de *DefaultEngine) GetItemConfig(item string) healthcheck.ItemConfig {
 	return de.engineConfig.GetItemConfig(item)
 }
 
+// GetDBConfigRules returns the db config rules which are applicable to the mysql version of the mysql server
+func (de *DefaultEngine) GetDBConfigRules() []healthcheck.DBConfigRule {
+	return de.dbConfigRules
+}
+
 // Run runs healthcheck
 func (de *DefaultEngine) Run() {
+	de.startTime = time.Now()
 	defer func() {
+		runningOperations.deregister(de.GetOperationInfo().GetOperationID())
+		de.complete()
+		go notifyCallbacks(de.getDASRepo(), de.GetOperationInfo().GetOperationID())
+		de.cancel()
+		// closing the connections also stops the queries which are still running in the background
 		err := de.closeConnections()
 		if err != nil {
 			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckCloseConnection, err))
@@ -160,93 +197,93 @@ func (de *DefaultEngine) Run() {
 	err := de.run()
 	if err != nil {
 		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckDefaultEngineRun, err))
-		// update status
-		updateErr := de.getDASRepo().UpdateOperationStatus(de.GetOperationInfo().GetOperationID(), defaultFailedStatus, err.Error())
-		if updateErr != nil {
-			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+		status := defaultFailedStatus
+		msg := err.Error()
+		switch de.ctx.Err() {
+		case context.Canceled:
+			status = defaultCancelledStatus
+			msg = fmt.Sprintf("healthcheck was cancelled. engine: default, operation_id: %d", de.GetOperationInfo().GetOperationID())
+		case context.DeadlineExceeded:
+			msg = fmt.Sprintf("healthcheck timed out. engine: default, operation_id: %d, timeout: %ds",
+				de.GetOperationInfo().GetOperationID(), viper.GetInt(config.HealthcheckTimeoutKey))
 		}
-		return
+		// update status
+		de.updateStatus(status, msg)
 	}
+}
 
-	// update operation status
+// claimStatus updates the status of the running operation to the final status of the result before the result is saved,
+// it returns an error if the operation is not running any more, e.g. it was cancelled by another process,
+// so that the result of the cancelled operation will not be saved and the email will not be sent
+func (de *DefaultEngine) claimStatus() error {
+	status := defaultSuccessStatus
 	msg := fmt.Sprintf("healthcheck completed successfully. engine: default, operation_id: %d", de.GetOperationInfo().GetOperationID())
-	updateErr := de.getDASRepo().UpdateOperationStatus(de.GetOperationInfo().GetOperationID(), defaultSuccessStatus, msg)
-	if updateErr != nil {
-		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+	errorItemNames := de.result.getErrorItemNames()
+	if len(errorItemNames) > constant.ZeroInt {
+		status = defaultPartialStatus
+		msg = fmt.Sprintf("healthcheck completed partially. engine: default, operation_id: %d, failed items: %s",
+			de.GetOperationInfo().GetOperationID(), strings.Join(errorItemNames, constant.CommaString))
 	}
-}
 
-// run executes the healthcheck
-func (de *DefaultEngine) run() error {
-	// init MonitorRepo
-
-	// pre run
-	err := de.preRun()
-	if err != nil {
-		return err
-	}
-	// check db config
-	err = de.checkDBConfig()
+	ok, err := de.getDASRepo().CompareAndUpdateOperationStatus(de.GetOperationInfo().GetOperationID(), defaultRunningStatus, status, msg)
 	if err != nil {
 		return err
 	}
-	// check mysql average backup failed ratio
-	err = de.CheckAvgBackupFailedRatio()
-	if err != nil {
-		return err
-	}
-	// check mysql statistic failed ratio
-	err = de.CheckStatisticFailedRatio()
-	if err != nil {
-		return err
+	if !ok {
+		return errors.Errorf("healthcheck operation is not running any more, the result will not be saved. operation_id: %d",
+			de.GetOperationInfo().GetOperationID())
 	}
-	// check cpu usage
-	err = de.checkCPUUsage()
-	if err != nil {
-		return err
-	}
-	// check io util
-	err = de.checkIOUtil()
-	if err != nil {
-		return err
+	de.claimedStatus = status
+
+	return nil
+}
+
+// updateStatus updates the status of the operation, the expected current status is the running status,
+// or the claimed status if the final status was claimed before, e.g. saving the result failed after claiming,
+// the status will not be updated if the operation is not running any more, e.g. it was cancelled by another process
+func (de *DefaultEngine) updateStatus(status int, msg string) {
+	expectedStatus := defaultRunningStatus
+	if de.claimedStatus != constant.ZeroInt {
+		expectedStatus = de.claimedStatus
 	}
-	// check disk capacity usage
-	err = de.checkDiskCapacityUsage()
+	ok, err := de.getDASRepo().CompareAndUpdateOperationStatus(de.GetOperationInfo().GetOperationID(), expectedStatus, status, msg)
 	if err != nil {
-		return err
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, err))
+		return
 	}
-	// check connection usage
-	err = de.checkConnectionUsage()
-	if err != nil {
-		return err
+	if !ok {
+		log.Warnf("healthcheck operation is not running any more, its status will not be updated. operation_id: %d, status: %d",
+			de.GetOperationInfo().GetOperationID(), status)
 	}
-	// check average active session percents
-	err = de.checkAverageActiveSessionPercents()
+}
+
+// run executes the healthcheck
+func (de *DefaultEngine) run() error {
+	// pre run
+	err := de.preRun()
 	if err != nil {
 		return err
 	}
-	// check cache miss ratio
-	err = de.checkCacheMissRatio()
+	// check items
+	err = de.check()
 	if err != nil {
 		return err
 	}
-	// check table rows
-	err = de.checkTableRows()
+	// summarize
+	err = de.summarize()
 	if err != nil {
 		return err
 	}
-	// check table size
-	err = de.checkTableSize()
+	// the result should not be saved if the operation was cancelled or timed out
+	err = de.ctx.Err()
 	if err != nil {
-		return err
+		return errors.Trace(err)
 	}
-	// check slow query
-	err = de.checkSlowQuery()
+	// the operation may be cancelled by another process, which only updates the status in the middleware
+	err = de.claimStatus()
 	if err != nil {
 		return err
 	}
-	// summarize
-	de.summarize()
 	// post run
 	return de.postRun()
 }
@@ -254,12 +291,12 @@ func (de *DefaultEngine) run() error {
 func (de *DefaultEngine) closeConnections() error {
 	merr := &multierror.Error{}
 
-	err := de.getApplicationMySQLRepo().Close()
+	err := de.GetApplicationMySQLRepo().Close()
 	if err != nil {
 		merr = multierror.Append(merr, err)
 	}
 
-	err = de.getQueryRepo().Close()
+	err = de.GetQueryRepo().Close()
 	if err != nil {
 		merr = multierror.Append(merr, err)
 	}
@@ -274,8 +311,27 @@ func (de *DefaultEngine) preRun() error {
 	if err != nil {
 		return err
 	}
+	// load db config rules
+	err = de.loadDBConfigRules()
+	if err != nil {
+		return err
+	}
+	// the mount points are only used by the disk capacity usage item,
+	// so the healthcheck should continue even if they could not be got
+	err = de.loadMountPoints()
+	if err != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckGetMountPoints, err, de.GetOperationInfo().GetOperationID()))
+		de.mountPoints = nil
+		de.devices = nil
+	}
+
+	return nil
+}
+
+// loadMountPoints loads the mount points and the devices of the mysql directories
+func (de *DefaultEngine) loadMountPoints() error {
 	// get file systems
-	fileSystems, err := de.getPrometheusRepo().GetFileSystems()
+	fileSystems, err := de.GetPrometheusRepo().GetFileSystems()
 	if err != nil {
 		return err
 	}
@@ -285,7 +341,7 @@ func (de *DefaultEngine) preRun() error {
 		mountPoints = append(mountPoints, fileSystem.GetMountPoint())
 	}
 	// get mysql directories
-	dirs, err := de.getApplicationMySQLRepo().GetMySQLDirs()
+	dirs, err := de.GetApplicationMySQLRepo().GetMySQLDirs()
 	if err != nil {
 		return err
 	}
@@ -308,533 +364,144 @@ func (de *DefaultEngine) preRun() error {
 			}
 		}
 	}
-	// init default report host and port
-	dbConfigVariableNames[dbConfigReportHost] = de.GetOperationInfo().GetMySQLServer().GetHostIP()
-	dbConfigVariableNames[dbConfigReportPort] = strconv.Itoa(de.GetOperationInfo().GetMySQLServer().GetPortNum())
 
 	return nil
 }
 
-// loadEngineConfig loads engine config
+// loadEngineConfig loads the engine config of the most specific scoring profile of the mysql server,
+// and records the profile and the config version on the operation
 func (de *DefaultEngine) loadEngineConfig() error {
-	var err error
-	// load config
-	de.engineConfig, err = de.getDASRepo().LoadEngineConfig()
-	if err != nil {
-		return err
-	}
-	// validate config
-	return de.engineConfig.Validate()
-}
-
-// checkDBConfig checks database configuration
-func (de *DefaultEngine) checkDBConfig() error {
-	// load database config
-	var configItems []string
-	for item := range dbConfigVariableNames {
-		configItems = append(configItems, item)
-	}
-
-	globalVariables, err := de.getApplicationMySQLRepo().GetVariables(configItems)
-	if err != nil {
-		return err
-	}
-
-	dbConfigConfig := de.getItemConfig(defaultDBConfigItemName)
-
-	var (
-		dbConfigCount int
-		variables     []*Variable
-	)
-
-	for _, globalVariable := range globalVariables {
-		name := globalVariable.GetName()
-		value := globalVariable.GetValue()
-
-		switch name {
-		// max_user_connection
-		case dbConfigMaxUserConnection:
-			maxUserConnection, err := strconv.Atoi(value)
-			if err != nil {
-				return errors.Trace(err)
-			}
-			if maxUserConnection < dbConfigMaxUserConnectionValid {
-				dbConfigCount++
-				variables = append(variables, NewVariable(dbConfigMaxUserConnection, value, strconv.Itoa(dbConfigMaxUserConnectionValid)))
-			}
-		// slave_parallel_workers
-		case dbConfigSlaveParallelWorkers:
-			workers, err := strconv.Atoi(value)
-			if err != nil {
-				return errors.Trace(err)
-			}
-			if workers != dbConfigSlaveParallelWorkersValid {
-				dbConfigCount++
-				variables = append(variables, NewVariable(dbConfigSlaveParallelWorkers, value, strconv.Itoa(dbConfigSlaveParallelWorkersValid)))
-			}
-		// report_host
-		case dbConfigReportHost:
-			if value != dbConfigVariableNames[dbConfigReportHost] && value != de.GetOperationInfo().GetMySQLServer().GetServerName() {
-				dbConfigCount++
-				variables = append(variables, NewVariable(dbConfigReportHost, value, dbConfigVariableNames[dbConfigReportHost]))
-			}
-		// others
-		case dbConfigLogBin, dbConfigBinlogFormat, dbConfigBinlogRowImage, dbConfigSyncBinlog,
-			dbConfigInnodbFlushLogAtTrxCommit, dbConfigGTIDMode, dbConfigEnforceGTIDConsistency,
-			dbConfigSlaveParallelType, dbConfigMasterInfoRepository, dbConfigRelayLogInfoRepository,
-			dbConfigReportPort, dbConfigInnodbFlushMethod, dbConfigInnodbMonitorEnable,
-			dbConfigInnodbPrintAllDeadlocks, dbConfigSlowQueryLog, dbConfigPerformanceSchema:
-			if strings.ToUpper(value) != dbConfigVariableNames[name] {
-				dbConfigCount++
-				variables = append(variables, NewVariable(name, value, dbConfigVariableNames[name]))
-			}
-		}
-	}
-
-	// database config data
-	jsonBytesTotal, err := json.Marshal(globalVariables)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	de.result.DBConfigData = string(jsonBytesTotal)
-	// database config advice
-	jsonBytesVariables, err := json.Marshal(variables)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	de.result.DBConfigAdvice = string(jsonBytesVariables)
-	// database config score deduction
-	dbConfigScoreDeduction := float64(dbConfigCount) * dbConfigConfig.GetScoreDeductionPerUnitHigh()
-	if dbConfigScoreDeduction > dbConfigConfig.GetMaxScoreDeductionHigh() {
-		dbConfigScoreDeduction = dbConfigConfig.GetMaxScoreDeductionHigh()
-	}
-
-	if de.GetOperationInfo().GetMySQLServer().GetDeploymentType() != defaultCloudMySQLServer {
-		// normally, users can not modify the db config of cloud mysql server, therefore, only deduct non cloud mysql server
-		de.result.DBConfigScore = int(defaultMaxScore - dbConfigScoreDeduction)
-	}
-	if de.result.DBConfigScore < constant.ZeroInt {
-		de.result.DBConfigScore = constant.ZeroInt
-	}
-
-	return nil
-}
-
-// CheckAvgBackupFailedRatio checks the mysql backup information
-func (de *DefaultEngine) CheckAvgBackupFailedRatio() error {
-	// get data
-	datas, err := de.getPrometheusRepo().GetAvgBackupFailedRatio()
-	if err != nil {
-		return err
-	}
-	// parse data
-	de.result.AvgBackupFailedRatioScore, de.result.AvgBackupFailedRatioData, de.result.AvgBackupFailedRatioHigh, err = de.parsePrometheusDatas(defaultAvgBackupFailedRatioItemName, datas)
+	// get scoring profile
+	profileID, err := de.getDASRepo().GetScoringProfileIDByMySQLClusterID(de.GetOperationInfo().GetMySQLServer().GetClusterID())
 	if err != nil {
 		return err
 	}
-
-	return nil
-}
-
-// CheckStatisticFailedRatio checks the statistic of mysql
-func (de *DefaultEngine) CheckStatisticFailedRatio() error {
-	// get data
-	datas, err := de.getPrometheusRepo().GetCPUUsage()
+	// load config, the config version is recorded only after the config which it matches was loaded
+	engineConfig, configVersion, err := de.getDASRepo().LoadEngineConfigWithVersionByProfileID(profileID)
 	if err != nil {
 		return err
 	}
-	// parse data
-	de.result.StatisticFailedRatioScore, de.result.StatisticFailedRatioData, de.result.StatisticFailedRatioHigh, err = de.parsePrometheusDatas(defaultStatisticFailedRatioItemName, datas)
+	de.engineConfig = engineConfig
+	err = de.getDASRepo().UpdateOperationEngineConfig(de.GetOperationInfo().GetOperationID(), profileID, configVersion)
 	if err != nil {
 		return err
 	}
-
-	return nil
-}
-
-// checkCPUUsage checks cpu usage
-func (de *DefaultEngine) checkCPUUsage() error {
-	// get data
-	datas, err := de.getPrometheusRepo().GetCPUUsage()
-	if err != nil {
-		return err
-	}
-	// parse data
-	de.result.CPUUsageScore, de.result.CPUUsageData, de.result.CPUUsageHigh, err = de.parsePrometheusDatas(defaultCPUUsageItemName, datas)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// checkIOUtil check io util
-func (de *DefaultEngine) checkIOUtil() error {
-	// get data
-	datas, err := de.getPrometheusRepo().GetIOUtil()
-	if err != nil {
-		return err
-	}
-	// parse data
-	de.result.IOUtilScore, de.result.IOUtilData, de.result.IOUtilHigh, err = de.parsePrometheusDatas(defaultIOUtilItemName, datas)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// checkDiskCapacityUsage checks disk capacity usage
-func (de *DefaultEngine) checkDiskCapacityUsage() error {
-	// get data
-	datas, err := de.getPrometheusRepo().GetDiskCapacityUsage(de.getMountPoints())
-	if err != nil {
-		return err
-	}
-	// parse data
-	de.result.DiskCapacityUsageScore, de.result.DiskCapacityUsageData, de.result.DiskCapacityUsageHigh, err = de.parsePrometheusDatas(defaultDiskCapacityUsageItemName, datas)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// checkConnectionUsage checks connection usage
-func (de *DefaultEngine) checkConnectionUsage() error {
-	// get data
-	datas, err := de.getPrometheusRepo().GetConnectionUsage()
-	if err != nil {
-		return err
-	}
-	// parse data
-	de.result.ConnectionUsageScore, de.result.ConnectionUsageData, de.result.ConnectionUsageHigh, err = de.parsePrometheusDatas(defaultConnectionUsageItemName, datas)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// validate config
+	return de.engineConfig.Validate()
 }
 
-// checkAverageActiveSessionPercents check active session number
-func (de *DefaultEngine) checkAverageActiveSessionPercents() error {
-	// get data
-	datas, err := de.getPrometheusRepo().GetAverageActiveSessionPercents()
-	if err != nil {
-		return err
-	}
-	// parse data
-	de.result.AverageActiveSessionPercentsScore, de.result.AverageActiveSessionPercentsData, de.result.AverageActiveSessionPercentsHigh, err = de.parsePrometheusDatas(defaultAverageActiveSessionPercentsItemName, datas)
+// loadDBConfigRules loads the db config rules which are applicable to the mysql version of the mysql server
+func (de *DefaultEngine) loadDBConfigRules() error {
+	rules, err := de.getDASRepo().LoadDBConfigRules()
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
-
-// checkCacheMissRatio checks cache miss ratio
-func (de *DefaultEngine) checkCacheMissRatio() error {
-	// get data
-	datas, err := de.getPrometheusRepo().GetCacheMissRatio()
-	if err != nil {
-		return err
-	}
-	// parse data
-	de.result.CacheMissRatioScore, de.result.CacheMissRatioData, de.result.CacheMissRatioHigh, err = de.parsePrometheusDatas(defaultCacheMissRatioItemName, datas)
-	if err != nil {
-		return err
+	mysqlVersion := de.GetOperationInfo().GetMySQLServer().GetVersion()
+	de.dbConfigRules = nil
+	for _, rule := range rules {
+		ok, err := rule.IsApplicable(mysqlVersion)
+		if err != nil {
+			return err
+		}
+		if ok {
+			de.dbConfigRules = append(de.dbConfigRules, rule)
+		}
 	}
 
 	return nil
 }
 
-// checkTableSize checks table rows
-func (de *DefaultEngine) checkTableRows() error {
-	// get tables
-	tables, err := de.getApplicationMySQLRepo().GetLargeTables()
-	if err != nil {
-		return err
-	}
-
-	tableRowsConfig := de.getItemConfig(defaultTableRowsItemName)
-
-	var (
-		tableRowsHighSum              int
-		tableRowsHighCount            int
-		tableRowsMediumSum            int
-		tableRowsMediumCount          int
-		tableRowsScoreDeductionHigh   float64
-		tableRowsScoreDeductionMedium float64
-
-		tableRowsHigh []healthcheck.Table
-	)
-
-	for _, table := range tables {
-		switch {
-		case float64(table.GetRows()) >= tableRowsConfig.GetHighWatermark():
-			tableRowsHigh = append(tableRowsHigh, table)
-			tableRowsHighSum += table.GetRows()
-			tableRowsHighCount++
-		case float64(table.GetRows()) >= tableRowsConfig.GetLowWatermark():
-			tableRowsMediumSum += table.GetRows()
-			tableRowsMediumCount++
+// check runs the check items which are configured in the engine config one by one,
+// the failure of an item will be saved as the item result with the error status, and the other items will continue to run
+func (de *DefaultEngine) check() error {
+	itemNames := de.getEngineConfig().GetItemNames()
+	for i, itemName := range itemNames {
+		// stop checking if the operation was cancelled or timed out
+		err := de.ctx.Err()
+		if err != nil {
+			return errors.Trace(err)
 		}
-	}
-
-	// table rows data
-	jsonBytesTotal, err := json.Marshal(tables)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	de.result.TableRowsData = string(jsonBytesTotal)
-	// table rows high
-	jsonBytesHigh, err := json.Marshal(tableRowsHigh)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	de.result.TableRowsHigh = string(jsonBytesHigh)
-
-	// table rows high score deduction
-	if tableRowsHighCount > constant.ZeroInt {
-		tableRowsScoreDeductionHigh = (float64(tableRowsHighSum)/float64(tableRowsHighCount) - tableRowsConfig.GetHighWatermark()) / tableRowsConfig.GetUnit() * tableRowsConfig.GetScoreDeductionPerUnitHigh()
-		if tableRowsScoreDeductionHigh > tableRowsConfig.GetMaxScoreDeductionHigh() {
-			tableRowsScoreDeductionHigh = tableRowsConfig.GetMaxScoreDeductionHigh()
+		item, ok := GetCheckItem(itemName)
+		if !ok {
+			return message.NewMessage(msghc.ErrHealthcheckCheckItemNotRegistered, itemName)
 		}
-	}
-	// table rows medium score deduction
-	if tableRowsMediumCount > constant.ZeroInt {
-		tableRowsScoreDeductionMedium = (float64(tableRowsMediumSum)/float64(tableRowsMediumCount) - tableRowsConfig.GetLowWatermark()) / tableRowsConfig.GetUnit() * tableRowsConfig.GetScoreDeductionPerUnitMedium()
-		if tableRowsScoreDeductionMedium > tableRowsConfig.GetMaxScoreDeductionMedium() {
-			tableRowsScoreDeductionMedium = tableRowsConfig.GetMaxScoreDeductionMedium()
+		de.publish(newProgressEvent(de.GetOperationInfo().GetOperationID(), ProgressEventTypeItemStarted, itemName, i, len(itemNames), time.Since(de.startTime)))
+		itemStartTime := time.Now()
+		itemResult, err := item.Check(de)
+		if err != nil {
+			if de.ctx.Err() != nil {
+				return err
+			}
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckCheckItemFailed, err, itemName, de.GetOperationInfo().GetOperationID()))
+			itemResult = NewErrorItemResultWithEnv(de, item, err)
 		}
-	}
-	// table rows score
-	de.result.TableRowsScore = int(defaultMaxScore - tableRowsScoreDeductionHigh - tableRowsScoreDeductionMedium)
-	if de.result.TableRowsScore < constant.ZeroInt {
-		de.result.TableRowsScore = constant.ZeroInt
+		err = de.result.addItemResult(itemResult)
+		if err != nil {
+			return err
+		}
+		event := newProgressEvent(de.GetOperationInfo().GetOperationID(), ProgressEventTypeItemFinished, itemName, i+1, len(itemNames), time.Since(de.startTime))
+		event.ItemStatus = itemResult.GetStatus()
+		event.ItemScore = itemResult.GetScore()
+		event.ItemElapsed = int(time.Since(itemStartTime).Milliseconds())
+		de.publish(event)
 	}
 
 	return nil
 }
 
-// checkTableSize checks table sizes
-func (de *DefaultEngine) checkTableSize() error {
-	// get tables
-	tables, err := de.getApplicationMySQLRepo().GetLargeTables()
-	if err != nil {
-		return err
-	}
-
-	tableSizeConfig := de.getItemConfig(defaultTableSizeItemName)
+// publish sends the progress event to the subscribers and saves the latest progress in the middleware,
+// the failure of saving the progress will not stop the healthcheck
+func (de *DefaultEngine) publish(event *ProgressEvent) {
+	progressBroker.publish(event)
 
-	var (
-		tableSizeHighSum              float64
-		tableSizeHighCount            int
-		tableSizeMediumSum            float64
-		tableSizeMediumCount          int
-		tableSizeScoreDeductionHigh   float64
-		tableSizeScoreDeductionMedium float64
-
-		tableSizeHigh []healthcheck.Table
-	)
-
-	for _, table := range tables {
-		switch {
-		case table.GetSize() >= tableSizeConfig.GetHighWatermark():
-			tableSizeHigh = append(tableSizeHigh, table)
-			tableSizeHighSum += table.GetSize()
-			tableSizeHighCount++
-		case table.GetSize() >= tableSizeConfig.GetLowWatermark():
-			tableSizeMediumSum += table.GetSize()
-			tableSizeMediumCount++
-		}
-	}
-
-	// table size data
-	jsonBytesTotal, err := json.Marshal(tables)
+	err := de.getDASRepo().SaveProgress(NewProgressWithEvent(event))
 	if err != nil {
-		return errors.Trace(err)
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSaveProgress, err, event.GetOperationID()))
 	}
-	de.result.TableSizeData = string(jsonBytesTotal)
-	// table size high
-	jsonBytesHigh, err := json.Marshal(tableSizeHigh)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	de.result.TableSizeHigh = string(jsonBytesHigh)
-
-	// table size high score deduction
-	if tableSizeHighCount > constant.ZeroInt {
-		tableSizeScoreDeductionHigh = (tableSizeHighSum/float64(tableSizeHighCount) - tableSizeConfig.GetHighWatermark()) / tableSizeConfig.GetUnit() * tableSizeConfig.GetScoreDeductionPerUnitHigh()
-		if tableSizeScoreDeductionHigh > tableSizeConfig.GetMaxScoreDeductionHigh() {
-			tableSizeScoreDeductionHigh = tableSizeConfig.GetMaxScoreDeductionHigh()
-		}
-	}
-	// table size medium score deduction
-	if tableSizeMediumCount > constant.ZeroInt {
-		tableSizeScoreDeductionMedium = (tableSizeMediumSum/float64(tableSizeMediumCount) - tableSizeConfig.GetLowWatermark()) / tableSizeConfig.GetUnit() * tableSizeConfig.GetScoreDeductionPerUnitMedium()
-		if tableSizeScoreDeductionMedium > tableSizeConfig.GetMaxScoreDeductionMedium() {
-			tableSizeScoreDeductionMedium = tableSizeConfig.GetMaxScoreDeductionMedium()
-		}
-	}
-	// table size score
-	de.result.TableSizeScore = int(defaultMaxScore - tableSizeScoreDeductionHigh - tableSizeScoreDeductionMedium)
-	if de.result.TableSizeScore < constant.ZeroInt {
-		de.result.TableSizeScore = constant.ZeroInt
-	}
-
-	return nil
 }
 
-// checkSlowQuery checks slow query
-func (de *DefaultEngine) checkSlowQuery() error {
-	// check slow query execution time
-	slowQueries, err := de.getQueryRepo().GetSlowQuery()
+// complete publishes the completed event with the final status of the operation, and closes the channels of the subscribers,
+// it must be called after the operation status is updated
+func (de *DefaultEngine) complete() {
+	operationID := de.GetOperationInfo().GetOperationID()
+	defer progressBroker.complete(operationID)
+
+	progress, err := de.getDASRepo().GetProgressByOperationID(operationID)
 	if err != nil {
-		return err
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckGetProgressByOperationID, err, operationID))
+		return
 	}
+	event := newProgressEvent(operationID, ProgressEventTypeCompleted, constant.EmptyString,
+		progress.GetFinishedItemNum(), progress.GetTotalItemNum(), time.Since(de.startTime))
+	event.Status = progress.GetStatus()
+	de.publish(event)
+}
 
+// summarize summarizes the scores of the items which were checked successfully with weight,
+// the weights are re-normalized, so that the skipped and failed items do not affect the weighted average score
+func (de *DefaultEngine) summarize() error {
 	var (
-		i                                int
-		slowQueryRowsExaminedHighSum     int
-		slowQueryRowsExaminedHighCount   int
-		slowQueryRowsExaminedMediumSum   int
-		slowQueryRowsExaminedMediumCount int
-
-		slowQueryRowsExaminedHighScore   float64
-		slowQueryRowsExaminedMediumScore float64
-
-		topSQLList []depquery.Query
+		weightedScore int
+		totalWeight   int
 	)
-
-	// slow query data
-	jsonBytesRowsExamined, err := json.Marshal(slowQueries)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	de.result.SlowQueryData = string(jsonBytesRowsExamined)
-
-	slowQueryRowsExaminedConfig := de.getItemConfig(defaultSlowQueryRowsExaminedItemName)
-
-	for _, slowQuery := range slowQueries {
-		if statement.GetType(slowQuery.GetExample()) == statement.Unknown {
+	for _, itemResult := range de.getResult().GetItemResults() {
+		if !itemResult.IsOK() {
 			continue
 		}
-		if i < defaultSlowQueryTopSQLNum {
-			dbName, err := util.GetDBName(slowQuery.GetExample())
-			if err != nil {
-				return err
-			}
-			if dbName == constant.EmptyString {
-				tableNames, err := util.GetTableNames(slowQuery.GetExample())
-				if err != nil {
-					return err
-				}
-				dbName, err = de.getApplicationMySQLRepo().GetDBName(tableNames)
-				if err != nil {
-					return err
-				}
-			}
-			if !common.StringInSlice(ignoreDBList, dbName) {
-				slowQuery.SetDBName(dbName)
-				topSQLList = append(topSQLList, slowQuery)
-				i++
-			}
-		}
-		if slowQuery.GetRowsExaminedMax() >= int(slowQueryRowsExaminedConfig.GetHighWatermark()) {
-			// slow query rows examined high
-			slowQueryRowsExaminedHighSum += slowQuery.GetRowsExaminedMax()
-			slowQueryRowsExaminedHighCount++
-			continue
-		}
-		if slowQuery.GetRowsExaminedMax() >= int(slowQueryRowsExaminedConfig.GetLowWatermark()) {
-			// slow query rows examined medium
-			slowQueryRowsExaminedMediumSum += slowQuery.GetRowsExaminedMax()
-			slowQueryRowsExaminedMediumCount++
-		}
-	}
-	// slow query rows examined high score
-	if slowQueryRowsExaminedHighCount > constant.ZeroInt {
-		slowQueryRowsExaminedHighScore = (float64(slowQueryRowsExaminedHighSum)/float64(slowQueryRowsExaminedHighCount) - slowQueryRowsExaminedConfig.GetHighWatermark()) / slowQueryRowsExaminedConfig.GetUnit() * slowQueryRowsExaminedConfig.GetScoreDeductionPerUnitHigh()
-		if slowQueryRowsExaminedHighScore > slowQueryRowsExaminedConfig.GetMaxScoreDeductionHigh() {
-			slowQueryRowsExaminedHighScore = slowQueryRowsExaminedConfig.GetMaxScoreDeductionHigh()
-		}
+		weightedScore += itemResult.GetScore() * itemResult.GetItemWeight()
+		totalWeight += itemResult.GetItemWeight()
 	}
-	// slow query rows examined medium score
-	if slowQueryRowsExaminedMediumCount > constant.ZeroInt {
-		slowQueryRowsExaminedMediumScore = (float64(slowQueryRowsExaminedMediumSum)/float64(slowQueryRowsExaminedMediumCount) - slowQueryRowsExaminedConfig.GetLowWatermark()) / slowQueryRowsExaminedConfig.GetUnit() * slowQueryRowsExaminedConfig.GetScoreDeductionPerUnitMedium()
-		if slowQueryRowsExaminedMediumScore > slowQueryRowsExaminedConfig.GetMaxScoreDeductionMedium() {
-			slowQueryRowsExaminedMediumScore = slowQueryRowsExaminedConfig.GetMaxScoreDeductionMedium()
-		}
-	}
-	// slow query score
-	de.result.SlowQueryScore = int(defaultMaxScore - slowQueryRowsExaminedHighScore - slowQueryRowsExaminedMediumScore)
-	if de.result.SlowQueryScore < defaultMinScore {
-		de.result.SlowQueryScore = defaultMinScore
-	}
-
-	// sql tuning
-	clusterID := de.GetOperationInfo().GetMySQLServer().GetClusterID()
-	// init db service
-	dbService := metadata.NewDBServiceWithDefault()
-	for _, sql := range topSQLList {
-		var advice string
-
-		// get db info
-		if sql.GetDBName() != constant.EmptyString {
-			err = dbService.GetDBByNameAndClusterInfo(sql.GetDBName(), clusterID, defaultClusterType)
-			if err != nil {
-				return err
-			}
-			// get db id
-			dbID := dbService.GetDBs()[constant.ZeroInt].Identity()
-			// init sql advisor service
-			advisorService := sqladvisor.NewServiceWithDefault()
-			// get advice
-			advice, err = advisorService.Advise(dbID, sql.GetExample())
-			if err != nil {
-				// TODO: if the tables that were used in the slow query do not exist anymore,
-				//  sql advisor returns an error, and we can do nothing here,
-				//  so for now, log the error message and jump over this,
-				//  we may optimize this later
-				log.Error(message.NewMessage(msghc.ErrHealthcheckSQLAdvisorAdvice, err.Error()).Error())
-				continue
-			}
-		} else {
-			jsonBytes, err := json.Marshal(sql)
-			if err != nil {
-				return errors.Trace(err)
-			}
-			advice = string(jsonBytes)
-		}
-
-		de.result.SlowQueryAdvice += advice + constant.CommaString
+	if totalWeight == constant.ZeroInt {
+		return message.NewMessage(msghc.ErrHealthcheckAllCheckItemsFailed, de.GetOperationInfo().GetOperationID())
 	}
 
-	de.result.SlowQueryAdvice = strings.Trim(de.result.SlowQueryAdvice, constant.CommaString)
-
-	return nil
-}
-
-// summarize summarizes all item scores with weight
-func (de *DefaultEngine) summarize() {
-	de.result.WeightedAverageScore = (de.result.DBConfigScore*de.getItemConfig(defaultDBConfigItemName).GetItemWeight() +
-		de.result.AvgBackupFailedRatioScore*de.getItemConfig(defaultAvgBackupFailedRatioItemName).GetItemWeight() +
-		de.result.StatisticFailedRatioScore*de.getItemConfig(defaultStatisticFailedRatioItemName).GetItemWeight() +
-		de.result.CPUUsageScore*de.getItemConfig(defaultCPUUsageItemName).GetItemWeight() +
-		de.result.IOUtilScore*de.getItemConfig(defaultIOUtilItemName).GetItemWeight() +
-		de.result.DiskCapacityUsageScore*de.getItemConfig(defaultDiskCapacityUsageItemName).GetItemWeight() +
-		de.result.ConnectionUsageScore*de.getItemConfig(defaultConnectionUsageItemName).GetItemWeight() +
-		de.result.AverageActiveSessionPercentsScore*de.getItemConfig(defaultAverageActiveSessionPercentsItemName).GetItemWeight() +
-		de.result.CacheMissRatioScore*de.getItemConfig(defaultCacheMissRatioItemName).GetItemWeight() +
-		de.result.TableRowsScore*de.getItemConfig(defaultTableRowsItemName).GetItemWeight() +
-		de.result.TableSizeScore*de.getItemConfig(defaultTableSizeItemName).GetItemWeight() +
-		de.result.SlowQueryScore*(de.getItemConfig(defaultSlowQueryRowsExaminedItemName).GetItemWeight())) /
-		constant.MaxPercentage
-
+	de.result.WeightedAverageScore = weightedScore / totalWeight
 	if de.result.WeightedAverageScore < defaultMinScore {
 		de.result.WeightedAverageScore = defaultMinScore
 	}
+
+	return nil
 }
 
 // postRun performs post-run actions, for now, it ony saves healthcheck result to the middleware
@@ -844,6 +511,13 @@ func (de *DefaultEngine) postRun() error {
 	if err != nil {
 		return err
 	}
+	// save snapshot, the result was saved, so the failure of saving the snapshot should not fail the operation
+	if de.getSnapshot() != nil {
+		err = de.getDASRepo().SaveSnapshot(de.GetOperationInfo().GetOperationID(), de.getSnapshot())
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckSaveSnapshot, err, de.GetOperationInfo().GetOperationID()))
+		}
+	}
 
 	if de.getResult().GetWeightedAverageScore() < defaultMaxScore {
 		err = de.sendEmail()
@@ -855,76 +529,28 @@ func (de *DefaultEngine) postRun() error {
 	return nil
 }
 
-// parsePrometheusDatas parses prometheus datas
-func (de *DefaultEngine) parsePrometheusDatas(item string, datas []healthcheck.PrometheusData) (int, string, string, error) {
-	cfg := de.getItemConfig(item)
-
-	var (
-		highSum              float64
-		highCount            int
-		mediumSum            float64
-		mediumCount          int
-		scoreDeductionHigh   float64
-		scoreDeductionMedium float64
-
-		highDatas []healthcheck.PrometheusData
-	)
-	// parse monitor data
-	for _, data := range datas {
-		switch {
-		case data.GetValue() >= cfg.GetHighWatermark():
-			highDatas = append(highDatas, data)
-			highSum += data.GetValue()
-			highCount++
-		case data.GetValue() >= cfg.GetLowWatermark():
-			mediumSum += data.GetValue()
-			mediumCount++
-		}
-	}
-
-	// high score deduction
-	if highCount > constant.ZeroInt {
-		scoreDeductionHigh = (highSum/float64(highCount) - cfg.GetHighWatermark()) / cfg.GetUnit() * cfg.GetScoreDeductionPerUnitHigh()
-		if scoreDeductionHigh > cfg.GetMaxScoreDeductionHigh() {
-			scoreDeductionHigh = cfg.GetMaxScoreDeductionHigh()
-		}
-	}
-	// medium score deduction
-	if mediumCount > constant.ZeroInt {
-		scoreDeductionMedium = (mediumSum/float64(mediumCount) - cfg.GetLowWatermark()) / cfg.GetUnit() * cfg.GetScoreDeductionPerUnitMedium()
-		if scoreDeductionMedium > cfg.GetMaxScoreDeductionMedium() {
-			scoreDeductionMedium = cfg.GetMaxScoreDeductionMedium()
-		}
+// sendEmail sends the healthcheck report to the requester,
+// and to the owners of the mysql cluster if the score is less than the owner threshold
+func (de *DefaultEngine) sendEmail() error {
+	toAddrs, err := de.getToAddrs()
+	if err != nil {
+		return err
 	}
-	// calculate score
-	score := int(defaultMaxScore - scoreDeductionHigh - scoreDeductionMedium)
-	if score < constant.ZeroInt {
-		score = constant.ZeroInt
+	if toAddrs == constant.EmptyString {
+		return errors.New("send email toAddrs can't be null")
 	}
 
-	jsonBytesTotal, err := json.Marshal(datas)
-	if err != nil {
-		return constant.ZeroInt, constant.EmptyString, constant.EmptyString, errors.Trace(err)
-	}
-	jsonBytesHigh, err := json.Marshal(highDatas)
+	result, err := de.getDASRepo().GetResultByOperationID(de.GetOperationInfo().GetOperationID())
 	if err != nil {
-		return constant.ZeroInt, constant.EmptyString, constant.EmptyString, errors.Trace(err)
+		return err
 	}
 
-	return score, string(jsonBytesTotal), string(jsonBytesHigh), nil
-}
-
-func (de *DefaultEngine) sendEmail() error {
-	// todo: remove the commented code below
-	// toAddrs, err := de.getToAddrs()
-	// if err != nil {
-	// 	return err
-	// }
-	// if len(toAddrs) == constant.ZeroInt {
-	// 	return fmt.Errorf("send email toAddrs can't be null")
-	// }
-
-	result, err := de.getDASRepo().GetResultByOperationID(de.GetOperationInfo().GetOperationID())
+	// render the report
+	format := ReportFormatMarkdown
+	if viper.GetString(config.AlertSMTPFormatKey) == config.AlertSMTPHTMLFormat {
+		format = ReportFormatHTML
+	}
+	content, err := NewReport(de.GetOperationInfo(), result).Render(format)
 	if err != nil {
 		return err
 	}
@@ -937,47 +563,66 @@ func (de *DefaultEngine) sendEmail() error {
 	alertService := alert.NewServiceWithDefault(cfg)
 
 	return alertService.SendEmail(
-		de.GetOperationInfo().GetUser().GetEmail(),
+		toAddrs,
 		constant.EmptyString,
 		fmt.Sprintf(defaultAlertSubjectTemplate, de.GetOperationInfo().GetAppName()),
-		result.String(),
+		content,
 	)
 }
 
-// todo: remove this function, and remove the healthcheck.alert.ownerType parameter
-// // getToAddrs gets to addrs that will send email to
-// func (de *DefaultEngine) getToAddrs() (string, error) {
-// 	mysqlCluster, err := de.GetOperationInfo().GetMySQLServer().GetMySQLCluster()
-// 	if err != nil {
-// 		return constant.EmptyString, err
-// 	}
-//
-// 	var (
-// 		owners  []depmeta.User
-// 		toAddrs string
-// 	)
-// 	switch viper.GetString(config.HealthcheckAlertOwnerTypeKey) {
-// 	case config.HealthcheckAlertOwnerTypeApp:
-// 		owners, err = mysqlCluster.GetAppUsers()
-// 	case config.HealthcheckAlertOwnerTypeDB:
-// 		owners, err = mysqlCluster.GetDBUsers()
-// 	case config.HealthcheckAlertOwnerTypeAll:
-// 		owners, err = mysqlCluster.GetAllUsers()
-// 	}
-// 	if err != nil {
-// 		return constant.EmptyString, err
-// 	}
-//
-// 	smtpEnabled := viper.GetBool(config.AlertSMTPEnabledKey)
-// 	httpEnabled := viper.GetBool(config.AlertHTTPEnabledKey)
-// 	for _, owner := range owners {
-// 		if smtpEnabled {
-// 			toAddrs += owner.GetEmail() + constant.CommaString
-// 		}
-// 		if !smtpEnabled && httpEnabled {
-// 			toAddrs += fmt.Sprintf("%s(%s),", owner.GetUserName(), owner.GetAccountName())
-// 		}
-// 	}
-//
-// 	return strings.Trim(toAddrs, constant.CommaString), nil
-// }
+// getToAddrs gets to addrs that will send email to, the requester is always included,
+// the owners of the mysql cluster are included only if the score is less than the owner threshold
+func (de *DefaultEngine) getToAddrs() (string, error) {
+	users := []depmeta.User{de.GetOperationInfo().GetUser()}
+	if de.getResult().GetWeightedAverageScore() >= viper.GetInt(config.HealthcheckAlertOwnerThresholdKey) {
+		return getAlertAddrs(users), nil
+	}
+
+	mysqlCluster, err := de.GetOperationInfo().GetMySQLServer().GetMySQLCluster()
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	var owners []depmeta.User
+	switch viper.GetString(config.HealthcheckAlertOwnerTypeKey) {
+	case config.HealthcheckAlertOwnerTypeApp:
+		owners, err = mysqlCluster.GetAppUsers()
+	case config.HealthcheckAlertOwnerTypeDB:
+		owners, err = mysqlCluster.GetDBUsers()
+	case config.HealthcheckAlertOwnerTypeAll:
+		owners, err = mysqlCluster.GetAllUsers()
+	}
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return getAlertAddrs(append(users, owners...)), nil
+}
+
+// getAlertAddrs returns the deduplicated addrs of the users, the addr is the email if smtp is enabled,
+// otherwise, it is "username(account_name)" if http is enabled
+func getAlertAddrs(users []depmeta.User) string {
+	smtpEnabled := viper.GetBool(config.AlertSMTPEnabledKey)
+	httpEnabled := viper.GetBool(config.AlertHTTPEnabledKey)
+
+	var addrs []string
+	for _, user := range users {
+		var addr string
+		switch {
+		case smtpEnabled:
+			addr = user.GetEmail()
+		case httpEnabled:
+			addr = fmt.Sprintf("%s(%s)", user.GetUserName(), user.GetAccountName())
+		}
+		if addr == constant.EmptyString {
+			continue
+		}
+		exists, err := common.ElementInSlice(addrs, addr)
+		if err == nil && exists {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+
+	return strings.Join(addrs, constant.CommaString)
+}
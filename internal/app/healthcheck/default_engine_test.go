@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -91,6 +92,8 @@ func TestDefaultEngine_Run(t *testing.T) {
 
 	id, err := testDASRepo.InitOperation(
 		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
 		testHealthcheckMySQLServerID,
 		time.Now().Add(-constant.Week),
 		time.Now(),
@@ -108,7 +111,9 @@ func TestDefaultEngine_Run(t *testing.T) {
 		testOperationInfo.GetStep(),
 	)
 
-	de := newDefaultEngine(operationInfo, testDASRepo, testApplicationMySQLRepo, testPrometheusRepo, testQueryRepo)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt(config.HealthcheckTimeoutKey))*time.Second)
+	defer cancel()
+	de := newDefaultEngine(ctx, cancel, operationInfo, testDASRepo, testApplicationMySQLRepo, testPrometheusRepo, testQueryRepo)
 	err = de.run()
 	asst.Nil(err, common.CombineMessageWithError("test Run() failed", err))
 	r, err := testDASRepo.GetResultByOperationID(de.GetOperationInfo().GetOperationID())
@@ -0,0 +1,492 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/das/internal/app/sqladvisor"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	depquery "github.com/romberli/das/internal/dependency/query"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	util "github.com/romberli/das/pkg/util/query"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware/sql/statement"
+	"github.com/romberli/log"
+)
+
+var (
+	_ healthcheck.CheckItem = (*DBConfigItem)(nil)
+	_ healthcheck.CheckItem = (*PrometheusItem)(nil)
+	_ healthcheck.CheckItem = (*TableRowsItem)(nil)
+	_ healthcheck.CheckItem = (*TableSizeItem)(nil)
+	_ healthcheck.CheckItem = (*SlowQueryItem)(nil)
+)
+
+func init() {
+	RegisterCheckItem(NewDBConfigItem())
+	RegisterCheckItem(NewPrometheusItem(defaultAvgBackupFailedRatioItemName, func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error) {
+		return env.GetPrometheusRepo().GetAvgBackupFailedRatio()
+	}))
+	RegisterCheckItem(NewPrometheusItem(defaultStatisticFailedRatioItemName, func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error) {
+		return env.GetPrometheusRepo().GetStatisticFailedRatio()
+	}))
+	RegisterCheckItem(NewPrometheusItem(defaultCPUUsageItemName, func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error) {
+		return env.GetPrometheusRepo().GetCPUUsage()
+	}))
+	RegisterCheckItem(NewPrometheusItem(defaultIOUtilItemName, func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error) {
+		return env.GetPrometheusRepo().GetIOUtil()
+	}))
+	RegisterCheckItem(NewPrometheusItem(defaultDiskCapacityUsageItemName, func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error) {
+		// the mount points could not be got when preparing the check
+		if len(env.GetMountPoints()) == constant.ZeroInt {
+			return nil, message.NewMessage(msghc.ErrHealthcheckMountPointsNotFound)
+		}
+
+		return env.GetPrometheusRepo().GetDiskCapacityUsage(env.GetMountPoints())
+	}))
+	RegisterCheckItem(NewPrometheusItem(defaultConnectionUsageItemName, func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error) {
+		return env.GetPrometheusRepo().GetConnectionUsage()
+	}))
+	RegisterCheckItem(NewPrometheusItem(defaultAverageActiveSessionPercentsItemName, func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error) {
+		return env.GetPrometheusRepo().GetAverageActiveSessionPercents()
+	}))
+	RegisterCheckItem(NewPrometheusItem(defaultCacheMissRatioItemName, func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error) {
+		return env.GetPrometheusRepo().GetCacheMissRatio()
+	}))
+	RegisterCheckItem(NewTableRowsItem())
+	RegisterCheckItem(NewTableSizeItem())
+	RegisterCheckItem(NewSlowQueryItem())
+}
+
+// DBConfigItem checks the database configuration
+type DBConfigItem struct{}
+
+// NewDBConfigItem returns a new *DBConfigItem
+func NewDBConfigItem() *DBConfigItem {
+	return &DBConfigItem{}
+}
+
+// GetName returns the item name
+func (dci *DBConfigItem) GetName() string {
+	return defaultDBConfigItemName
+}
+
+// GetDataSource returns the data source of the item
+func (dci *DBConfigItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks database configuration with the db config rules which are applicable to the mysql version of the mysql server
+func (dci *DBConfigItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	// load database config
+	var configItems []string
+	for _, rule := range env.GetDBConfigRules() {
+		configItems = append(configItems, rule.GetVariableName())
+	}
+
+	globalVariables, err := env.GetApplicationMySQLRepo().GetVariables(configItems)
+	if err != nil {
+		return nil, err
+	}
+	// the variable names are case-insensitive
+	values := make(map[string]string)
+	for _, globalVariable := range globalVariables {
+		values[strings.ToLower(globalVariable.GetName())] = globalVariable.GetValue()
+	}
+
+	mysqlServer := env.GetOperationInfo().GetMySQLServer()
+	dbConfigConfig := env.GetItemConfig(dci.GetName())
+
+	var (
+		dbConfigCountHigh   int
+		dbConfigCountMedium int
+		variables           []*Variable
+	)
+
+	for _, rule := range env.GetDBConfigRules() {
+		value, ok := values[strings.ToLower(rule.GetVariableName())]
+		if !ok {
+			// the variable does not exist in this mysql server
+			continue
+		}
+		ok, err = rule.Check(value, mysqlServer)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			continue
+		}
+
+		if rule.GetSeverity() == DBConfigRuleSeverityMedium {
+			dbConfigCountMedium++
+		} else {
+			dbConfigCountHigh++
+		}
+		variables = append(variables, NewVariable(rule.GetVariableName(), value, rule.GetAdvice(mysqlServer)))
+	}
+
+	// database config data
+	jsonBytesTotal, err := json.Marshal(globalVariables)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// database config advice
+	jsonBytesVariables, err := json.Marshal(variables)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// database config score deduction
+	dbConfigScoreDeductionHigh := float64(dbConfigCountHigh) * dbConfigConfig.GetScoreDeductionPerUnitHigh()
+	if dbConfigScoreDeductionHigh > dbConfigConfig.GetMaxScoreDeductionHigh() {
+		dbConfigScoreDeductionHigh = dbConfigConfig.GetMaxScoreDeductionHigh()
+	}
+	dbConfigScoreDeductionMedium := float64(dbConfigCountMedium) * dbConfigConfig.GetScoreDeductionPerUnitMedium()
+	if dbConfigScoreDeductionMedium > dbConfigConfig.GetMaxScoreDeductionMedium() {
+		dbConfigScoreDeductionMedium = dbConfigConfig.GetMaxScoreDeductionMedium()
+	}
+	dbConfigScoreDeduction := dbConfigScoreDeductionHigh + dbConfigScoreDeductionMedium
+	if mysqlServer.GetDeploymentType() == defaultCloudMySQLServer {
+		// normally, users can not modify the db config of cloud mysql server, therefore, only deduct non cloud mysql server
+		dbConfigScoreDeduction = defaultMinScore
+	}
+	score := int(defaultMaxScore - dbConfigScoreDeduction)
+	if score < defaultMinScore {
+		score = defaultMinScore
+	}
+
+	return NewItemResultWithEnv(env, dci, score, string(jsonBytesTotal), constant.EmptyString, string(jsonBytesVariables)), nil
+}
+
+// PrometheusItem checks the item of which the data comes from the prometheus,
+// the data will be scored by the watermarks of the item config
+type PrometheusItem struct {
+	name    string
+	getData func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error)
+}
+
+// NewPrometheusItem returns a new *PrometheusItem, getData gets the data of the item from the prometheus
+func NewPrometheusItem(name string, getData func(env healthcheck.CheckEnv) ([]healthcheck.PrometheusData, error)) *PrometheusItem {
+	return &PrometheusItem{
+		name:    name,
+		getData: getData,
+	}
+}
+
+// GetName returns the item name
+func (pi *PrometheusItem) GetName() string {
+	return pi.name
+}
+
+// GetDataSource returns the data source of the item
+func (pi *PrometheusItem) GetDataSource() string {
+	return DataSourcePrometheus
+}
+
+// Check gets the data from the prometheus and scores it
+func (pi *PrometheusItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	// get data
+	datas, err := pi.getData(env)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := env.GetItemConfig(pi.GetName())
+
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		highDatas []healthcheck.PrometheusData
+	)
+	// parse monitor data
+	for _, data := range datas {
+		switch {
+		case data.GetValue() >= cfg.GetHighWatermark():
+			highDatas = append(highDatas, data)
+			highSum += data.GetValue()
+			highCount++
+		case data.GetValue() >= cfg.GetLowWatermark():
+			mediumSum += data.GetValue()
+			mediumCount++
+		}
+	}
+
+	jsonBytesTotal, err := json.Marshal(datas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highDatas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(cfg, highSum, highCount, mediumSum, mediumCount)
+
+	return NewItemResultWithEnv(env, pi, score, string(jsonBytesTotal), string(jsonBytesHigh), constant.EmptyString), nil
+}
+
+// TableRowsItem checks the rows of the large tables
+type TableRowsItem struct{}
+
+// NewTableRowsItem returns a new *TableRowsItem
+func NewTableRowsItem() *TableRowsItem {
+	return &TableRowsItem{}
+}
+
+// GetName returns the item name
+func (tri *TableRowsItem) GetName() string {
+	return defaultTableRowsItemName
+}
+
+// GetDataSource returns the data source of the item
+func (tri *TableRowsItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks table rows
+func (tri *TableRowsItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	// get tables
+	tables, err := env.GetApplicationMySQLRepo().GetLargeTables()
+	if err != nil {
+		return nil, err
+	}
+
+	tableRowsConfig := env.GetItemConfig(tri.GetName())
+
+	var (
+		tableRowsHighSum     int
+		tableRowsHighCount   int
+		tableRowsMediumSum   int
+		tableRowsMediumCount int
+
+		tableRowsHigh []healthcheck.Table
+	)
+
+	for _, table := range tables {
+		switch {
+		case float64(table.GetRows()) >= tableRowsConfig.GetHighWatermark():
+			tableRowsHigh = append(tableRowsHigh, table)
+			tableRowsHighSum += table.GetRows()
+			tableRowsHighCount++
+		case float64(table.GetRows()) >= tableRowsConfig.GetLowWatermark():
+			tableRowsMediumSum += table.GetRows()
+			tableRowsMediumCount++
+		}
+	}
+
+	// table rows data
+	jsonBytesTotal, err := json.Marshal(tables)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// table rows high
+	jsonBytesHigh, err := json.Marshal(tableRowsHigh)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(tableRowsConfig, float64(tableRowsHighSum), tableRowsHighCount, float64(tableRowsMediumSum), tableRowsMediumCount)
+
+	return NewItemResultWithEnv(env, tri, score, string(jsonBytesTotal), string(jsonBytesHigh), constant.EmptyString), nil
+}
+
+// TableSizeItem checks the sizes of the large tables
+type TableSizeItem struct{}
+
+// NewTableSizeItem returns a new *TableSizeItem
+func NewTableSizeItem() *TableSizeItem {
+	return &TableSizeItem{}
+}
+
+// GetName returns the item name
+func (tsi *TableSizeItem) GetName() string {
+	return defaultTableSizeItemName
+}
+
+// GetDataSource returns the data source of the item
+func (tsi *TableSizeItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks table sizes
+func (tsi *TableSizeItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	// get tables
+	tables, err := env.GetApplicationMySQLRepo().GetLargeTables()
+	if err != nil {
+		return nil, err
+	}
+
+	tableSizeConfig := env.GetItemConfig(tsi.GetName())
+
+	var (
+		tableSizeHighSum     float64
+		tableSizeHighCount   int
+		tableSizeMediumSum   float64
+		tableSizeMediumCount int
+
+		tableSizeHigh []healthcheck.Table
+	)
+
+	for _, table := range tables {
+		switch {
+		case table.GetSize() >= tableSizeConfig.GetHighWatermark():
+			tableSizeHigh = append(tableSizeHigh, table)
+			tableSizeHighSum += table.GetSize()
+			tableSizeHighCount++
+		case table.GetSize() >= tableSizeConfig.GetLowWatermark():
+			tableSizeMediumSum += table.GetSize()
+			tableSizeMediumCount++
+		}
+	}
+
+	// table size data
+	jsonBytesTotal, err := json.Marshal(tables)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// table size high
+	jsonBytesHigh, err := json.Marshal(tableSizeHigh)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(tableSizeConfig, tableSizeHighSum, tableSizeHighCount, tableSizeMediumSum, tableSizeMediumCount)
+
+	return NewItemResultWithEnv(env, tsi, score, string(jsonBytesTotal), string(jsonBytesHigh), constant.EmptyString), nil
+}
+
+// SlowQueryItem checks the rows examined of the slow queries, and gives the advice of the top slow queries
+type SlowQueryItem struct{}
+
+// NewSlowQueryItem returns a new *SlowQueryItem
+func NewSlowQueryItem() *SlowQueryItem {
+	return &SlowQueryItem{}
+}
+
+// GetName returns the item name
+func (sqi *SlowQueryItem) GetName() string {
+	return defaultSlowQueryRowsExaminedItemName
+}
+
+// GetDataSource returns the data source of the item
+func (sqi *SlowQueryItem) GetDataSource() string {
+	return DataSourceQuery
+}
+
+// Check checks slow query
+func (sqi *SlowQueryItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	// check slow query execution time
+	slowQueries, err := env.GetQueryRepo().GetSlowQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		i                                int
+		slowQueryRowsExaminedHighSum     int
+		slowQueryRowsExaminedHighCount   int
+		slowQueryRowsExaminedMediumSum   int
+		slowQueryRowsExaminedMediumCount int
+
+		topSQLList []depquery.Query
+	)
+
+	// slow query data
+	jsonBytesRowsExamined, err := json.Marshal(slowQueries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	slowQueryRowsExaminedConfig := env.GetItemConfig(sqi.GetName())
+
+	for _, slowQuery := range slowQueries {
+		if statement.GetType(slowQuery.GetExample()) == statement.Unknown {
+			continue
+		}
+		if i < defaultSlowQueryTopSQLNum {
+			dbName, err := util.GetDBName(slowQuery.GetExample())
+			if err != nil {
+				return nil, err
+			}
+			if dbName == constant.EmptyString {
+				tableNames, err := util.GetTableNames(slowQuery.GetExample())
+				if err != nil {
+					return nil, err
+				}
+				dbName, err = env.GetApplicationMySQLRepo().GetDBName(tableNames)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !common.StringInSlice(ignoreDBList, dbName) {
+				slowQuery.SetDBName(dbName)
+				topSQLList = append(topSQLList, slowQuery)
+				i++
+			}
+		}
+		if slowQuery.GetRowsExaminedMax() >= int(slowQueryRowsExaminedConfig.GetHighWatermark()) {
+			// slow query rows examined high
+			slowQueryRowsExaminedHighSum += slowQuery.GetRowsExaminedMax()
+			slowQueryRowsExaminedHighCount++
+			continue
+		}
+		if slowQuery.GetRowsExaminedMax() >= int(slowQueryRowsExaminedConfig.GetLowWatermark()) {
+			// slow query rows examined medium
+			slowQueryRowsExaminedMediumSum += slowQuery.GetRowsExaminedMax()
+			slowQueryRowsExaminedMediumCount++
+		}
+	}
+	// slow query score
+	score := calculateScore(slowQueryRowsExaminedConfig,
+		float64(slowQueryRowsExaminedHighSum), slowQueryRowsExaminedHighCount,
+		float64(slowQueryRowsExaminedMediumSum), slowQueryRowsExaminedMediumCount)
+
+	// sql tuning
+	var slowQueryAdvice string
+	clusterID := env.GetOperationInfo().GetMySQLServer().GetClusterID()
+	// init db service
+	dbService := metadata.NewDBServiceWithDefault()
+	for _, sql := range topSQLList {
+		var advice string
+
+		// get db info
+		if sql.GetDBName() != constant.EmptyString {
+			err = dbService.GetDBByNameAndClusterInfo(sql.GetDBName(), clusterID, defaultClusterType)
+			if err != nil {
+				return nil, err
+			}
+			// get db id
+			dbID := dbService.GetDBs()[constant.ZeroInt].Identity()
+			// init sql advisor service
+			advisorService := sqladvisor.NewServiceWithDefault()
+			// get advice
+			advice, err = advisorService.Advise(dbID, sql.GetExample())
+			if err != nil {
+				// TODO: if the tables that were used in the slow query do not exist anymore,
+				//  sql advisor returns an error, and we can do nothing here,
+				//  so for now, log the error message and jump over this,
+				//  we may optimize this later
+				log.Error(message.NewMessage(msghc.ErrHealthcheckSQLAdvisorAdvice, err.Error()).Error())
+				continue
+			}
+		} else {
+			jsonBytes, err := json.Marshal(sql)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			advice = string(jsonBytes)
+		}
+
+		slowQueryAdvice += advice + constant.CommaString
+	}
+
+	slowQueryAdvice = strings.Trim(slowQueryAdvice, constant.CommaString)
+
+	return NewItemResultWithEnv(env, sqi, score, string(jsonBytesRowsExamined), constant.EmptyString, slowQueryAdvice), nil
+}
@@ -0,0 +1,119 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	engineConfigBaselineDescription = "baseline before the first change"
+	engineConfigRollbackDescription = "rollback to version %d"
+	engineConfigSyncDescription     = "synced the item configs changed by the upgrade"
+	engineConfigSyncAddDescription  = "added items of the global default engine config with zero weight: %s"
+)
+
+var (
+	_ healthcheck.EngineConfigVersion = (*EngineConfigVersion)(nil)
+
+	// engineConfigVersionMarshalFields are the fields which will be marshaled, the raw item configs data is not included
+	engineConfigVersionMarshalFields = []string{"ID", "ProfileID", "Version", "ItemConfigs", "LoginName", "Description",
+		"DelFlag", "CreateTime", "LastUpdateTime"}
+)
+
+// EngineConfigVersion is a saved full item config set of the global default engine config or a scoring profile
+type EngineConfigVersion struct {
+	ID              int                  `middleware:"id" json:"id"`
+	ProfileID       int                  `middleware:"profile_id" json:"profile_id"`
+	Version         int                  `middleware:"version" json:"version"`
+	ItemConfigsData string               `middleware:"item_configs" json:"-"`
+	ItemConfigs     []*DefaultItemConfig `json:"item_configs"`
+	LoginName       string               `middleware:"login_name" json:"login_name"`
+	Description     string               `middleware:"description" json:"description"`
+	DelFlag         int                  `middleware:"del_flag" json:"del_flag"`
+	CreateTime      time.Time            `middleware:"create_time" json:"create_time"`
+	LastUpdateTime  time.Time            `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewEmptyEngineConfigVersion returns a new *EngineConfigVersion with empty value
+func NewEmptyEngineConfigVersion() *EngineConfigVersion {
+	return &EngineConfigVersion{ItemConfigs: []*DefaultItemConfig{}}
+}
+
+// Identity returns the identity
+func (ecv *EngineConfigVersion) Identity() int {
+	return ecv.ID
+}
+
+// GetProfileID returns the scoring profile id, 0 means the global default engine config
+func (ecv *EngineConfigVersion) GetProfileID() int {
+	return ecv.ProfileID
+}
+
+// GetVersion returns the config version
+func (ecv *EngineConfigVersion) GetVersion() int {
+	return ecv.Version
+}
+
+// GetItemConfigs returns the full item configs of the version
+func (ecv *EngineConfigVersion) GetItemConfigs() []healthcheck.ItemConfig {
+	itemConfigs := make([]healthcheck.ItemConfig, len(ecv.ItemConfigs))
+	for i, itemConfig := range ecv.ItemConfigs {
+		itemConfigs[i] = itemConfig
+	}
+
+	return itemConfigs
+}
+
+// GetLoginName returns the login name of the user who made the change
+func (ecv *EngineConfigVersion) GetLoginName() string {
+	return ecv.LoginName
+}
+
+// GetDescription returns the description of the change
+func (ecv *EngineConfigVersion) GetDescription() string {
+	return ecv.Description
+}
+
+// GetDelFlag returns the delete flag
+func (ecv *EngineConfigVersion) GetDelFlag() int {
+	return ecv.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (ecv *EngineConfigVersion) GetCreateTime() time.Time {
+	return ecv.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (ecv *EngineConfigVersion) GetLastUpdateTime() time.Time {
+	return ecv.LastUpdateTime
+}
+
+// unmarshalItemConfigs unmarshals the item configs data which was got from the middleware
+func (ecv *EngineConfigVersion) unmarshalItemConfigs() error {
+	if ecv.ItemConfigsData == constant.EmptyString {
+		return nil
+	}
+
+	return errors.Trace(json.Unmarshal([]byte(ecv.ItemConfigsData), &ecv.ItemConfigs))
+}
+
+// MarshalJSON marshals EngineConfigVersion to json string
+func (ecv *EngineConfigVersion) MarshalJSON() ([]byte, error) {
+	return common.MarshalStructWithFields(ecv, engineConfigVersionMarshalFields...)
+}
+
+// newDefaultEngineConfigWithItemConfigs returns a new healthcheck.EngineConfig which consists of the given item configs
+func newDefaultEngineConfigWithItemConfigs(itemConfigs []healthcheck.ItemConfig) healthcheck.EngineConfig {
+	engineConfig := NewEmptyDefaultEngineConfig()
+	for _, itemConfig := range itemConfigs {
+		engineConfig.SetItemConfig(itemConfig.GetItemName(), itemConfig)
+	}
+
+	return engineConfig
+}
@@ -0,0 +1,528 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware"
+	"github.com/romberli/log"
+)
+
+const engineConfigItemConfigsQuery = `
+	select id, item_name, item_weight, low_watermark, high_watermark, unit, score_deduction_per_unit_high, max_score_deduction_high,
+	score_deduction_per_unit_medium, max_score_deduction_medium, del_flag, create_time, last_update_time
+	from t_hc_default_engine_config
+	where del_flag = 0
+	  and profile_id = ?
+	order by id;
+`
+
+const engineConfigLatestVersionQuery = `
+	select id, profile_id, version, item_configs, login_name, description, del_flag, create_time, last_update_time
+	from t_hc_engine_config_version
+	where del_flag = 0
+	  and profile_id = ?
+	order by version desc
+	limit 1;
+`
+
+var _ healthcheck.EngineConfigRepo = (*EngineConfigRepo)(nil)
+
+// EngineConfigRepo is the repository of the healthcheck engine config and its versions
+type EngineConfigRepo struct {
+	Database middleware.Pool
+}
+
+// NewEngineConfigRepo returns healthcheck.EngineConfigRepo with given middleware.Pool
+func NewEngineConfigRepo(db middleware.Pool) healthcheck.EngineConfigRepo {
+	return newEngineConfigRepo(db)
+}
+
+// NewEngineConfigRepoWithGlobal returns healthcheck.EngineConfigRepo with global mysql pool
+func NewEngineConfigRepoWithGlobal() healthcheck.EngineConfigRepo {
+	return newEngineConfigRepo(global.DASMySQLPool)
+}
+
+// newEngineConfigRepo returns *EngineConfigRepo with given middleware.Pool
+func newEngineConfigRepo(db middleware.Pool) *EngineConfigRepo {
+	return &EngineConfigRepo{Database: db}
+}
+
+// Execute executes given command and placeholders on the middleware
+func (ecr *EngineConfigRepo) Execute(command string, args ...interface{}) (middleware.Result, error) {
+	conn, err := ecr.Database.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = conn.Close()
+		if err != nil {
+			log.Errorf("healthcheck EngineConfigRepo.Execute(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	return conn.Execute(command, args...)
+}
+
+// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+func (ecr *EngineConfigRepo) Transaction() (middleware.Transaction, error) {
+	return ecr.Database.Transaction()
+}
+
+// GetItemConfigs gets the item configs of the scoring profile from the middleware, ordered by the config identity
+func (ecr *EngineConfigRepo) GetItemConfigs(profileID int) ([]healthcheck.ItemConfig, error) {
+	log.Debugf("healthcheck EngineConfigRepo.GetItemConfigs() sql: \n%s\nplaceholders: %d", engineConfigItemConfigsQuery, profileID)
+
+	result, err := ecr.Execute(engineConfigItemConfigsQuery, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToItemConfigs(result)
+}
+
+// GetLatestVersion gets the latest config version of the scoring profile from the middleware,
+// it returns 0 if the config was never changed through the api
+func (ecr *EngineConfigRepo) GetLatestVersion(profileID int) (int, error) {
+	sql := `select ifnull(max(version), 0) from t_hc_engine_config_version where del_flag = 0 and profile_id = ?;`
+	log.Debugf("healthcheck EngineConfigRepo.GetLatestVersion() sql: \n%s\nplaceholders: %d", sql, profileID)
+
+	result, err := ecr.Execute(sql, profileID)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return result.GetInt(constant.ZeroInt, constant.ZeroInt)
+}
+
+// GetVersions gets all the config versions of the scoring profile from the middleware, ordered by the version descending
+func (ecr *EngineConfigRepo) GetVersions(profileID int) ([]healthcheck.EngineConfigVersion, error) {
+	sql := `
+		select id, profile_id, version, item_configs, login_name, description, del_flag, create_time, last_update_time
+		from t_hc_engine_config_version
+		where del_flag = 0
+		  and profile_id = ?
+		order by version desc;
+	`
+	log.Debugf("healthcheck EngineConfigRepo.GetVersions() sql: \n%s\nplaceholders: %d", sql, profileID)
+
+	result, err := ecr.Execute(sql, profileID)
+	if err != nil {
+		return nil, err
+	}
+	versionList := make([]*EngineConfigVersion, result.RowNumber())
+	for i := range versionList {
+		versionList[i] = NewEmptyEngineConfigVersion()
+	}
+	err = result.MapToStructSlice(versionList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]healthcheck.EngineConfigVersion, len(versionList))
+	for i, version := range versionList {
+		err = version.unmarshalItemConfigs()
+		if err != nil {
+			return nil, err
+		}
+		versions[i] = version
+	}
+
+	return versions, nil
+}
+
+// GetVersion gets the config version of the scoring profile from the middleware
+func (ecr *EngineConfigRepo) GetVersion(profileID, version int) (healthcheck.EngineConfigVersion, error) {
+	sql := `
+		select id, profile_id, version, item_configs, login_name, description, del_flag, create_time, last_update_time
+		from t_hc_engine_config_version
+		where del_flag = 0
+		  and profile_id = ?
+		  and version = ?;
+	`
+	log.Debugf("healthcheck EngineConfigRepo.GetVersion() sql: \n%s\nplaceholders: %d, %d", sql, profileID, version)
+
+	result, err := ecr.Execute(sql, profileID, version)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, errors.Errorf("healthcheck EngineConfigRepo.GetVersion(): data does not exists, profile_id: %d, version: %d", profileID, version)
+	case 1:
+		engineConfigVersion := NewEmptyEngineConfigVersion()
+		err = result.MapToStructByRowIndex(engineConfigVersion, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+		err = engineConfigVersion.unmarshalItemConfigs()
+		if err != nil {
+			return nil, err
+		}
+
+		return engineConfigVersion, nil
+	default:
+		return nil, errors.Errorf("healthcheck EngineConfigRepo.GetVersion(): duplicate key exists, profile_id: %d, version: %d", profileID, version)
+	}
+}
+
+// Save replaces the item configs of the scoring profile and saves them as a new config version in the middleware as a transaction,
+// it returns the new config version
+func (ecr *EngineConfigRepo) Save(profileID int, itemConfigs []healthcheck.ItemConfig, loginName, description string) (int, error) {
+	tx, err := ecr.Transaction()
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	defer func() {
+		err = tx.Close()
+		if err != nil {
+			log.Errorf("healthcheck EngineConfigRepo.Save(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	err = tx.Begin()
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	// keep the original item configs as the first config version, so that they could be rolled back to
+	err = saveBaselineVersion(tx, profileID)
+	if err != nil {
+		return constant.ZeroInt, ecr.rollback(tx, err)
+	}
+	sql := `delete from t_hc_default_engine_config where profile_id = ?;`
+	log.Debugf("healthcheck EngineConfigRepo.Save() delete sql: \n%s\nplaceholders: %d", sql, profileID)
+	_, err = tx.Execute(sql, profileID)
+	if err != nil {
+		return constant.ZeroInt, ecr.rollback(tx, err)
+	}
+	err = saveItemConfigs(tx, profileID, itemConfigs)
+	if err != nil {
+		return constant.ZeroInt, ecr.rollback(tx, err)
+	}
+	version, err := saveEngineConfigVersion(tx, profileID, itemConfigs, loginName, description)
+	if err != nil {
+		return constant.ZeroInt, ecr.rollback(tx, err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return version, nil
+}
+
+// Sync adds the item configs of the global default engine config which are missing in the scoring profiles with zero item weight,
+// the existing item configs of the scoring profiles are never changed,
+// and then saves the item configs of the global default engine config and each scoring profile as a new config version
+// if they differ from the latest config version, so that the item configs changed by the upgrade are versioned
+func (ecr *EngineConfigRepo) Sync() error {
+	defaultItemConfigs, err := ecr.GetItemConfigs(defaultScoringProfileID)
+	if err != nil {
+		return err
+	}
+
+	sql := `select distinct profile_id from t_hc_default_engine_config where del_flag = 0 order by profile_id;`
+	log.Debugf("healthcheck EngineConfigRepo.Sync() select sql: \n%s", sql)
+	result, err := ecr.Execute(sql)
+	if err != nil {
+		return err
+	}
+	for i := constant.ZeroInt; i < result.RowNumber(); i++ {
+		profileID, err := result.GetInt(i, constant.ZeroInt)
+		if err != nil {
+			return err
+		}
+		err = ecr.syncProfile(profileID, defaultItemConfigs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncProfile syncs the item configs of the scoring profile with the global default item configs as a transaction
+func (ecr *EngineConfigRepo) syncProfile(profileID int, defaultItemConfigs []healthcheck.ItemConfig) error {
+	tx, err := ecr.Transaction()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = tx.Close()
+		if err != nil {
+			log.Errorf("healthcheck EngineConfigRepo.syncProfile(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	err = tx.Begin()
+	if err != nil {
+		return err
+	}
+
+	// lock the versions of the scoring profile, and keep the current item configs as the first config version
+	err = saveBaselineVersion(tx, profileID)
+	if err != nil {
+		return ecr.rollback(tx, err)
+	}
+	log.Debugf("healthcheck EngineConfigRepo.syncProfile() select sql: \n%s\nplaceholders: %d", engineConfigItemConfigsQuery, profileID)
+	result, err := tx.Execute(engineConfigItemConfigsQuery, profileID)
+	if err != nil {
+		return ecr.rollback(tx, err)
+	}
+	itemConfigs, err := mapToItemConfigs(result)
+	if err != nil {
+		return ecr.rollback(tx, err)
+	}
+
+	description := engineConfigSyncDescription
+	if profileID != defaultScoringProfileID {
+		missingItemConfigs := getMissingItemConfigs(itemConfigs, defaultItemConfigs)
+		if len(missingItemConfigs) > constant.ZeroInt {
+			// the weights tuned by the operators are kept, the missing items are added with zero weight,
+			// so they are checked but do not affect the score until the scoring profile is rebalanced via the engine config api
+			missingItemConfigs = zeroItemWeights(missingItemConfigs)
+			err = saveItemConfigs(tx, profileID, missingItemConfigs)
+			if err != nil {
+				return ecr.rollback(tx, err)
+			}
+			itemNames := make([]string, len(missingItemConfigs))
+			for i, itemConfig := range missingItemConfigs {
+				itemNames[i] = itemConfig.GetItemName()
+			}
+			description = fmt.Sprintf(engineConfigSyncAddDescription, strings.Join(itemNames, constant.CommaString))
+			itemConfigs = append(itemConfigs, missingItemConfigs...)
+		}
+	}
+
+	latestItemConfigs, err := getLatestVersionItemConfigs(tx, profileID)
+	if err != nil {
+		return ecr.rollback(tx, err)
+	}
+	if !equalItemConfigs(itemConfigs, latestItemConfigs) {
+		_, err = saveEngineConfigVersion(tx, profileID, itemConfigs, constant.EmptyString, description)
+		if err != nil {
+			return ecr.rollback(tx, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rollback rolls back the transaction, and returns the original error
+func (ecr *EngineConfigRepo) rollback(tx middleware.Transaction, err error) error {
+	rollbackErr := tx.Rollback()
+	if rollbackErr != nil {
+		log.Errorf("healthcheck EngineConfigRepo.rollback(): rollback failed.\n%+v", rollbackErr)
+	}
+
+	return err
+}
+
+// mapToItemConfigs maps the result which is got by engineConfigItemConfigsQuery to the item configs
+func mapToItemConfigs(result middleware.Result) ([]healthcheck.ItemConfig, error) {
+	itemConfigList := make([]healthcheck.ItemConfig, result.RowNumber())
+	for i := range itemConfigList {
+		itemConfigList[i] = NewEmptyDefaultItemConfig()
+	}
+	err := result.MapToStructSlice(itemConfigList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemConfigList, nil
+}
+
+// saveBaselineVersion saves the current item configs of the scoring profile as the first config version with given transaction,
+// it does nothing if the scoring profile already has any config version or has no item config
+func saveBaselineVersion(tx middleware.Transaction, profileID int) error {
+	// lock the versions of the scoring profile, so that the concurrent changes do not save the baseline twice
+	sql := `select ifnull(max(version), 0) from t_hc_engine_config_version where profile_id = ? for update;`
+	log.Debugf("healthcheck saveBaselineVersion() select sql: \n%s\nplaceholders: %d", sql, profileID)
+	result, err := tx.Execute(sql, profileID)
+	if err != nil {
+		return err
+	}
+	version, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	if err != nil {
+		return err
+	}
+	if version > constant.ZeroInt {
+		return nil
+	}
+
+	log.Debugf("healthcheck saveBaselineVersion() select sql: \n%s\nplaceholders: %d", engineConfigItemConfigsQuery, profileID)
+	result, err = tx.Execute(engineConfigItemConfigsQuery, profileID)
+	if err != nil {
+		return err
+	}
+	itemConfigs, err := mapToItemConfigs(result)
+	if err != nil {
+		return err
+	}
+	if len(itemConfigs) == constant.ZeroInt {
+		return nil
+	}
+	_, err = saveEngineConfigVersion(tx, profileID, itemConfigs, constant.EmptyString, engineConfigBaselineDescription)
+
+	return err
+}
+
+// getLatestVersionItemConfigs gets the item configs of the latest config version of the scoring profile with given transaction,
+// it returns nil if the scoring profile has no config version
+func getLatestVersionItemConfigs(tx middleware.Transaction, profileID int) ([]healthcheck.ItemConfig, error) {
+	log.Debugf("healthcheck getLatestVersionItemConfigs() select sql: \n%s\nplaceholders: %d", engineConfigLatestVersionQuery, profileID)
+	result, err := tx.Execute(engineConfigLatestVersionQuery, profileID)
+	if err != nil {
+		return nil, err
+	}
+	engineConfigVersion, err := mapToLatestEngineConfigVersion(result)
+	if err != nil || engineConfigVersion == nil {
+		return nil, err
+	}
+
+	return engineConfigVersion.GetItemConfigs(), nil
+}
+
+// mapToLatestEngineConfigVersion maps the result of engineConfigLatestVersionQuery to the config version with the unmarshalled item configs,
+// it returns nil if the result is empty
+func mapToLatestEngineConfigVersion(result middleware.Result) (*EngineConfigVersion, error) {
+	if result.RowNumber() == constant.ZeroInt {
+		return nil, nil
+	}
+	engineConfigVersion := NewEmptyEngineConfigVersion()
+	err := result.MapToStructByRowIndex(engineConfigVersion, constant.ZeroInt, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+	err = engineConfigVersion.unmarshalItemConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	return engineConfigVersion, nil
+}
+
+// getMissingItemConfigs returns the default item configs of which the items are not in the item configs
+func getMissingItemConfigs(itemConfigs, defaultItemConfigs []healthcheck.ItemConfig) []healthcheck.ItemConfig {
+	itemNames := make(map[string]bool)
+	for _, itemConfig := range itemConfigs {
+		itemNames[itemConfig.GetItemName()] = true
+	}
+
+	var missingItemConfigs []healthcheck.ItemConfig
+	for _, defaultItemConfig := range defaultItemConfigs {
+		if !itemNames[defaultItemConfig.GetItemName()] {
+			missingItemConfigs = append(missingItemConfigs, defaultItemConfig)
+		}
+	}
+
+	return missingItemConfigs
+}
+
+// zeroItemWeights returns the copies of the item configs of which the item weights are 0
+func zeroItemWeights(itemConfigs []healthcheck.ItemConfig) []healthcheck.ItemConfig {
+	copied := NewScoringProfile(constant.EmptyString, constant.EmptyString, itemConfigs).ItemConfigs
+
+	zeroed := make([]healthcheck.ItemConfig, len(copied))
+	for i, itemConfig := range copied {
+		itemConfig.ItemWeight = constant.ZeroInt
+		zeroed[i] = itemConfig
+	}
+
+	return zeroed
+}
+
+// equalItemConfigs checks if the two item config sets have the same items and the same settings, the identities and the times are ignored
+func equalItemConfigs(itemConfigs, otherItemConfigs []healthcheck.ItemConfig) bool {
+	if len(itemConfigs) != len(otherItemConfigs) {
+		return false
+	}
+
+	otherItemConfigMap := make(map[string]healthcheck.ItemConfig)
+	for _, itemConfig := range otherItemConfigs {
+		otherItemConfigMap[itemConfig.GetItemName()] = itemConfig
+	}
+	for _, itemConfig := range itemConfigs {
+		other, ok := otherItemConfigMap[itemConfig.GetItemName()]
+		if !ok {
+			return false
+		}
+		if itemConfig.GetItemWeight() != other.GetItemWeight() ||
+			itemConfig.GetLowWatermark() != other.GetLowWatermark() ||
+			itemConfig.GetHighWatermark() != other.GetHighWatermark() ||
+			itemConfig.GetUnit() != other.GetUnit() ||
+			itemConfig.GetScoreDeductionPerUnitHigh() != other.GetScoreDeductionPerUnitHigh() ||
+			itemConfig.GetMaxScoreDeductionHigh() != other.GetMaxScoreDeductionHigh() ||
+			itemConfig.GetScoreDeductionPerUnitMedium() != other.GetScoreDeductionPerUnitMedium() ||
+			itemConfig.GetMaxScoreDeductionMedium() != other.GetMaxScoreDeductionMedium() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// saveItemConfigs saves the item configs of the scoring profile with given transaction
+func saveItemConfigs(tx middleware.Transaction, profileID int, itemConfigs []healthcheck.ItemConfig) error {
+	sql := `
+		insert into t_hc_default_engine_config(profile_id, item_name, item_weight, low_watermark, high_watermark, unit,
+		score_deduction_per_unit_high, max_score_deduction_high, score_deduction_per_unit_medium, max_score_deduction_medium)
+		values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	for _, itemConfig := range itemConfigs {
+		log.Debugf("healthcheck saveItemConfigs() insert sql: \n%s\nplaceholders: %d, %s, %d, %f, %f, %f, %f, %f, %f, %f",
+			sql, profileID, itemConfig.GetItemName(), itemConfig.GetItemWeight(), itemConfig.GetLowWatermark(), itemConfig.GetHighWatermark(),
+			itemConfig.GetUnit(), itemConfig.GetScoreDeductionPerUnitHigh(), itemConfig.GetMaxScoreDeductionHigh(),
+			itemConfig.GetScoreDeductionPerUnitMedium(), itemConfig.GetMaxScoreDeductionMedium())
+		_, err := tx.Execute(sql, profileID, itemConfig.GetItemName(), itemConfig.GetItemWeight(), itemConfig.GetLowWatermark(), itemConfig.GetHighWatermark(),
+			itemConfig.GetUnit(), itemConfig.GetScoreDeductionPerUnitHigh(), itemConfig.GetMaxScoreDeductionHigh(),
+			itemConfig.GetScoreDeductionPerUnitMedium(), itemConfig.GetMaxScoreDeductionMedium())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveEngineConfigVersion saves the item configs of the scoring profile as a new config version with given transaction,
+// it returns the new config version
+func saveEngineConfigVersion(tx middleware.Transaction, profileID int, itemConfigs []healthcheck.ItemConfig, loginName, description string) (int, error) {
+	// copy the item configs, so that the identities and the times which were got from the middleware are not saved
+	defaultItemConfigs := NewScoringProfile(constant.EmptyString, constant.EmptyString, itemConfigs).ItemConfigs
+	jsonBytes, err := json.Marshal(defaultItemConfigs)
+	if err != nil {
+		return constant.ZeroInt, errors.Trace(err)
+	}
+	// lock the versions of the scoring profile, so that the concurrent changes get different versions
+	sql := `select ifnull(max(version), 0) from t_hc_engine_config_version where profile_id = ? for update;`
+	log.Debugf("healthcheck saveEngineConfigVersion() select sql: \n%s\nplaceholders: %d", sql, profileID)
+	result, err := tx.Execute(sql, profileID)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	version, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	version++
+
+	sql = `
+		insert into t_hc_engine_config_version(profile_id, version, item_configs, login_name, description)
+		values(?, ?, ?, ?, ?);
+	`
+	log.Debugf("healthcheck saveEngineConfigVersion() insert sql: \n%s\nplaceholders: %d, %d, %s, %s, %s",
+		sql, profileID, version, string(jsonBytes), loginName, description)
+	_, err = tx.Execute(sql, profileID, version, string(jsonBytes), loginName, description)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return version, nil
+}
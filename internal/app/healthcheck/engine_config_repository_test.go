@@ -0,0 +1,297 @@
+package healthcheck
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testEngineConfigVersion     = 1
+	testEngineConfigLoginName   = "test_login_name"
+	testEngineConfigDescription = "test engine config description"
+
+	testEngineConfigLowWatermark                = 1
+	testEngineConfigHighWatermark               = 2
+	testEngineConfigUnit                        = 0.5
+	testEngineConfigScoreDeductionPerUnitHigh   = 4
+	testEngineConfigMaxScoreDeductionHigh       = 50
+	testEngineConfigScoreDeductionPerUnitMedium = 3
+	testEngineConfigMaxScoreDeductionMedium     = 30
+)
+
+var testEngineConfigRepo *EngineConfigRepo
+
+func init() {
+	testInitDASMySQLPool()
+	testEngineConfigRepo = newEngineConfigRepo(global.DASMySQLPool)
+}
+
+// testAssertItemConfig asserts that each getter of the actual item config returns the same value as the expected one
+func testAssertItemConfig(asst *assert.Assertions, expected, actual healthcheck.ItemConfig, message string) {
+	asst.Equal(expected.GetItemName(), actual.GetItemName(), message)
+	asst.Equal(expected.GetItemWeight(), actual.GetItemWeight(), message)
+	asst.Equal(expected.GetLowWatermark(), actual.GetLowWatermark(), message)
+	asst.Equal(expected.GetHighWatermark(), actual.GetHighWatermark(), message)
+	asst.Equal(expected.GetUnit(), actual.GetUnit(), message)
+	asst.Equal(expected.GetScoreDeductionPerUnitHigh(), actual.GetScoreDeductionPerUnitHigh(), message)
+	asst.Equal(expected.GetMaxScoreDeductionHigh(), actual.GetMaxScoreDeductionHigh(), message)
+	asst.Equal(expected.GetScoreDeductionPerUnitMedium(), actual.GetScoreDeductionPerUnitMedium(), message)
+	asst.Equal(expected.GetMaxScoreDeductionMedium(), actual.GetMaxScoreDeductionMedium(), message)
+}
+
+func TestEngineConfigRepo_All(t *testing.T) {
+	TestEngineConfigRepo_Execute(t)
+	TestEngineConfigRepo_Transaction(t)
+	TestEngineConfigRepo_GetItemConfigs(t)
+	TestEngineConfigRepo_GetLatestVersion(t)
+	TestEngineConfigRepo_GetVersions(t)
+	TestEngineConfigRepo_GetVersion(t)
+	TestEngineConfigRepo_Save(t)
+	TestEngineConfigRepo_GetItemConfigGetters(t)
+	TestEngineConfigRepo_Sync(t)
+	TestEngineConfigRepo_getMissingItemConfigs(t)
+	TestEngineConfigRepo_zeroItemWeights(t)
+	TestEngineConfigRepo_equalItemConfigs(t)
+}
+
+func TestEngineConfigRepo_Execute(t *testing.T) {
+	asst := assert.New(t)
+
+	sql := `select 1;`
+	result, err := testEngineConfigRepo.Execute(sql)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	r, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	asst.Equal(1, r, "test Execute() failed")
+}
+
+func TestEngineConfigRepo_Transaction(t *testing.T) {
+	asst := assert.New(t)
+
+	tx, err := testEngineConfigRepo.Transaction()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Begin()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Rollback()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+}
+
+func TestEngineConfigRepo_GetItemConfigs(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetItemConfigs() failed", err))
+	itemConfigs, err := testEngineConfigRepo.GetItemConfigs(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetItemConfigs() failed", err))
+	asst.Equal(len(profile.GetItemConfigs()), len(itemConfigs), "test GetItemConfigs() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetItemConfigs() failed", err))
+}
+
+func TestEngineConfigRepo_GetLatestVersion(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetLatestVersion() failed", err))
+	version, err := testEngineConfigRepo.GetLatestVersion(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetLatestVersion() failed", err))
+	asst.Equal(testEngineConfigVersion, version, "test GetLatestVersion() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetLatestVersion() failed", err))
+}
+
+func TestEngineConfigRepo_GetVersions(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetVersions() failed", err))
+	_, err = testEngineConfigRepo.Save(profile.Identity(), profile.GetItemConfigs(), testEngineConfigLoginName, testEngineConfigDescription)
+	asst.Nil(err, common.CombineMessageWithError("test GetVersions() failed", err))
+	versions, err := testEngineConfigRepo.GetVersions(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetVersions() failed", err))
+	asst.Equal(2, len(versions), "test GetVersions() failed")
+	asst.Equal(2, versions[constant.ZeroInt].GetVersion(), "test GetVersions() failed")
+	asst.Equal(testEngineConfigLoginName, versions[constant.ZeroInt].GetLoginName(), "test GetVersions() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetVersions() failed", err))
+}
+
+func TestEngineConfigRepo_GetVersion(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetVersion() failed", err))
+	version, err := testEngineConfigRepo.GetVersion(profile.Identity(), testEngineConfigVersion)
+	asst.Nil(err, common.CombineMessageWithError("test GetVersion() failed", err))
+	asst.Equal(len(profile.GetItemConfigs()), len(version.GetItemConfigs()), "test GetVersion() failed")
+	asst.Nil(newDefaultEngineConfigWithItemConfigs(version.GetItemConfigs()).Validate(), "test GetVersion() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetVersion() failed", err))
+}
+
+func TestEngineConfigRepo_Save(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	version, err := testEngineConfigRepo.Save(profile.Identity(), profile.GetItemConfigs()[1:], testEngineConfigLoginName, testEngineConfigDescription)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(testEngineConfigVersion+1, version, "test Save() failed")
+	itemConfigs, err := testEngineConfigRepo.GetItemConfigs(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(len(profile.GetItemConfigs())-1, len(itemConfigs), "test Save() failed")
+	// all the settings must be unchanged after they are saved and reloaded, the values are distinct, so that the getters could not be mixed up
+	itemConfig := itemConfigs[constant.ZeroInt]
+	changed := NewDefaultItemConfig(itemConfig.GetItemName(), itemConfig.GetItemWeight(), testEngineConfigLowWatermark, testEngineConfigHighWatermark,
+		testEngineConfigUnit, testEngineConfigScoreDeductionPerUnitHigh, testEngineConfigMaxScoreDeductionHigh,
+		testEngineConfigScoreDeductionPerUnitMedium, testEngineConfigMaxScoreDeductionMedium)
+	version, err = testEngineConfigRepo.Save(profile.Identity(), []healthcheck.ItemConfig{changed}, testEngineConfigLoginName, testEngineConfigDescription)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	itemConfigs, err = testEngineConfigRepo.GetItemConfigs(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	testAssertItemConfig(asst, changed, itemConfigs[constant.ZeroInt], "test Save() failed")
+	engineConfigVersion, err := testEngineConfigRepo.GetVersion(profile.Identity(), version)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	testAssertItemConfig(asst, changed, engineConfigVersion.GetItemConfigs()[constant.ZeroInt], "test Save() failed")
+	// the current item configs are saved as the baseline version if the scoring profile has no config version
+	sql := `delete from t_hc_engine_config_version where profile_id = ?;`
+	_, err = testEngineConfigRepo.Execute(sql, profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	version, err = testEngineConfigRepo.Save(profile.Identity(), profile.GetItemConfigs(), testEngineConfigLoginName, testEngineConfigDescription)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(testEngineConfigVersion+1, version, "test Save() failed")
+	engineConfigVersion, err = testEngineConfigRepo.GetVersion(profile.Identity(), testEngineConfigVersion)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(engineConfigBaselineDescription, engineConfigVersion.GetDescription(), "test Save() failed")
+	asst.Equal(constant.EmptyString, engineConfigVersion.GetLoginName(), "test Save() failed")
+	asst.Equal(len(itemConfigs), len(engineConfigVersion.GetItemConfigs()), "test Save() failed")
+	testAssertItemConfig(asst, changed, engineConfigVersion.GetItemConfigs()[constant.ZeroInt], "test Save() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+}
+
+func TestEngineConfigRepo_GetItemConfigGetters(t *testing.T) {
+	asst := assert.New(t)
+
+	now := time.Now()
+	itemConfig := &DefaultItemConfig{
+		ID:                          1,
+		ItemName:                    defaultDBConfigItemName,
+		ItemWeight:                  10,
+		LowWatermark:                testEngineConfigLowWatermark,
+		HighWatermark:               testEngineConfigHighWatermark,
+		Unit:                        testEngineConfigUnit,
+		ScoreDeductionPerUnitHigh:   testEngineConfigScoreDeductionPerUnitHigh,
+		MaxScoreDeductionHigh:       testEngineConfigMaxScoreDeductionHigh,
+		ScoreDeductionPerUnitMedium: testEngineConfigScoreDeductionPerUnitMedium,
+		MaxScoreDeductionMedium:     testEngineConfigMaxScoreDeductionMedium,
+		DelFlag:                     constant.ZeroInt,
+		CreateTime:                  now,
+		LastUpdateTime:              now,
+	}
+	asst.Equal(1, itemConfig.GetID(), "test GetItemConfigGetters() failed")
+	asst.Equal(defaultDBConfigItemName, itemConfig.GetItemName(), "test GetItemConfigGetters() failed")
+	asst.Equal(10, itemConfig.GetItemWeight(), "test GetItemConfigGetters() failed")
+	asst.Equal(float64(testEngineConfigLowWatermark), itemConfig.GetLowWatermark(), "test GetItemConfigGetters() failed")
+	asst.Equal(float64(testEngineConfigHighWatermark), itemConfig.GetHighWatermark(), "test GetItemConfigGetters() failed")
+	asst.Equal(testEngineConfigUnit, itemConfig.GetUnit(), "test GetItemConfigGetters() failed")
+	asst.Equal(float64(testEngineConfigScoreDeductionPerUnitHigh), itemConfig.GetScoreDeductionPerUnitHigh(), "test GetItemConfigGetters() failed")
+	asst.Equal(float64(testEngineConfigMaxScoreDeductionHigh), itemConfig.GetMaxScoreDeductionHigh(), "test GetItemConfigGetters() failed")
+	asst.Equal(float64(testEngineConfigScoreDeductionPerUnitMedium), itemConfig.GetScoreDeductionPerUnitMedium(), "test GetItemConfigGetters() failed")
+	asst.Equal(float64(testEngineConfigMaxScoreDeductionMedium), itemConfig.GetMaxScoreDeductionMedium(), "test GetItemConfigGetters() failed")
+	asst.Equal(constant.ZeroInt, itemConfig.GetDelFlag(), "test GetItemConfigGetters() failed")
+	asst.Equal(now, itemConfig.GetCreateTime(), "test GetItemConfigGetters() failed")
+	asst.Equal(now, itemConfig.GetLastUpdateTime(), "test GetItemConfigGetters() failed")
+}
+
+func TestEngineConfigRepo_Sync(t *testing.T) {
+	asst := assert.New(t)
+
+	defaultItemConfigs, err := testEngineConfigRepo.GetItemConfigs(defaultScoringProfileID)
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	// remove the first item from the scoring profile
+	_, err = testEngineConfigRepo.Save(profile.Identity(), profile.GetItemConfigs()[1:], testEngineConfigLoginName, testEngineConfigDescription)
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	err = testEngineConfigRepo.Sync()
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	// the missing item is added with zero weight, and the weights of the other items are not changed
+	itemConfigs, err := testEngineConfigRepo.GetItemConfigs(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	asst.Equal(len(defaultItemConfigs), len(itemConfigs), "test Sync() failed")
+	engineConfig := newDefaultEngineConfigWithItemConfigs(itemConfigs)
+	for i, itemConfig := range profile.GetItemConfigs() {
+		expected := itemConfig.GetItemWeight()
+		if i == constant.ZeroInt {
+			expected = constant.ZeroInt
+		}
+		asst.Equal(expected, engineConfig.GetItemConfig(itemConfig.GetItemName()).GetItemWeight(), "test Sync() failed")
+	}
+	version, err := testEngineConfigRepo.GetLatestVersion(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	asst.Equal(testEngineConfigVersion+2, version, "test Sync() failed")
+	engineConfigVersion, err := testEngineConfigRepo.GetVersion(profile.Identity(), version)
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	asst.Equal(fmt.Sprintf(engineConfigSyncAddDescription, profile.GetItemConfigs()[constant.ZeroInt].GetItemName()),
+		engineConfigVersion.GetDescription(), "test Sync() failed")
+	// nothing changes if the item configs are synced
+	err = testEngineConfigRepo.Sync()
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	version, err = testEngineConfigRepo.GetLatestVersion(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	asst.Equal(testEngineConfigVersion+2, version, "test Sync() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+}
+
+func TestEngineConfigRepo_getMissingItemConfigs(t *testing.T) {
+	asst := assert.New(t)
+
+	first := NewDefaultItemConfig(defaultDBConfigItemName, 60, 0, 0, 0, 0, 0, 0, 0)
+	second := NewDefaultItemConfig(defaultCPUUsageItemName, 40, 0, 0, 0, 0, 0, 0, 0)
+	missingItemConfigs := getMissingItemConfigs([]healthcheck.ItemConfig{first}, []healthcheck.ItemConfig{first, second})
+	asst.Equal([]healthcheck.ItemConfig{second}, missingItemConfigs, "test getMissingItemConfigs() failed")
+	missingItemConfigs = getMissingItemConfigs([]healthcheck.ItemConfig{first, second}, []healthcheck.ItemConfig{first})
+	asst.Nil(missingItemConfigs, "test getMissingItemConfigs() failed")
+}
+
+func TestEngineConfigRepo_zeroItemWeights(t *testing.T) {
+	asst := assert.New(t)
+
+	itemConfigs := []healthcheck.ItemConfig{
+		NewDefaultItemConfig(defaultDBConfigItemName, 60, 0, 0, 0, 0, 0, 0, 0),
+		NewDefaultItemConfig(defaultCPUUsageItemName, 40, 0, 0, 0, 0, 0, 0, 0),
+	}
+	zeroed := zeroItemWeights(itemConfigs)
+	for _, itemConfig := range zeroed {
+		asst.Zero(itemConfig.GetItemWeight(), "test zeroItemWeights() failed")
+	}
+	// the original item configs are not changed
+	asst.Equal(40, itemConfigs[1].GetItemWeight(), "test zeroItemWeights() failed")
+}
+
+func TestEngineConfigRepo_equalItemConfigs(t *testing.T) {
+	asst := assert.New(t)
+
+	first := NewDefaultItemConfig(defaultDBConfigItemName, 60, 1, 2, 1, 10, 100, 5, 50)
+	second := NewDefaultItemConfig(defaultCPUUsageItemName, 40, 1, 2, 1, 10, 100, 5, 50)
+	changed := NewDefaultItemConfig(defaultCPUUsageItemName, 40, 1, 2, 1, 10, 90, 5, 50)
+	asst.True(equalItemConfigs([]healthcheck.ItemConfig{first, second}, []healthcheck.ItemConfig{second, first}), "test equalItemConfigs() failed")
+	asst.False(equalItemConfigs([]healthcheck.ItemConfig{first, second}, []healthcheck.ItemConfig{first, changed}), "test equalItemConfigs() failed")
+	asst.False(equalItemConfigs([]healthcheck.ItemConfig{first, second}, []healthcheck.ItemConfig{first}), "test equalItemConfigs() failed")
+	asst.False(equalItemConfigs([]healthcheck.ItemConfig{first}, nil), "test equalItemConfigs() failed")
+}
@@ -0,0 +1,233 @@
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	engineConfigProfileIDStruct   = "ProfileID"
+	engineConfigVersionStruct     = "Version"
+	engineConfigItemConfigsStruct = "ItemConfigs"
+	engineConfigVersionsStruct    = "Versions"
+
+	engineConfigUpdateDescription  = "updated items: %s"
+	engineConfigReplaceDescription = "replaced all items"
+)
+
+var _ healthcheck.EngineConfigService = (*EngineConfigService)(nil)
+
+// EngineConfigService is the service of the healthcheck engine config
+type EngineConfigService struct {
+	healthcheck.EngineConfigRepo
+	ProfileID   int                               `json:"profile_id"`
+	Version     int                               `json:"version"`
+	ItemConfigs []healthcheck.ItemConfig          `json:"item_configs"`
+	Versions    []healthcheck.EngineConfigVersion `json:"versions"`
+}
+
+// NewEngineConfigService returns a new healthcheck.EngineConfigService
+func NewEngineConfigService(repo healthcheck.EngineConfigRepo) healthcheck.EngineConfigService {
+	return newEngineConfigService(repo)
+}
+
+// NewEngineConfigServiceWithDefault returns a new healthcheck.EngineConfigService with default repository
+func NewEngineConfigServiceWithDefault() healthcheck.EngineConfigService {
+	return newEngineConfigService(NewEngineConfigRepoWithGlobal())
+}
+
+// newEngineConfigService returns a new *EngineConfigService
+func newEngineConfigService(repo healthcheck.EngineConfigRepo) *EngineConfigService {
+	return &EngineConfigService{
+		EngineConfigRepo: repo,
+		ItemConfigs:      []healthcheck.ItemConfig{},
+		Versions:         []healthcheck.EngineConfigVersion{},
+	}
+}
+
+// GetItemConfigs returns the item configs of the service
+func (ecs *EngineConfigService) GetItemConfigs() []healthcheck.ItemConfig {
+	return ecs.ItemConfigs
+}
+
+// GetVersion returns the current config version of the service
+func (ecs *EngineConfigService) GetVersion() int {
+	return ecs.Version
+}
+
+// GetVersions returns the config versions of the service
+func (ecs *EngineConfigService) GetVersions() []healthcheck.EngineConfigVersion {
+	return ecs.Versions
+}
+
+// GetByProfileID gets the item configs and the current config version of the scoring profile from the middleware
+func (ecs *EngineConfigService) GetByProfileID(profileID int) error {
+	itemConfigs, err := ecs.getItemConfigs(profileID)
+	if err != nil {
+		return err
+	}
+	version, err := ecs.EngineConfigRepo.GetLatestVersion(profileID)
+	if err != nil {
+		return err
+	}
+
+	ecs.ProfileID = profileID
+	ecs.Version = version
+	ecs.ItemConfigs = itemConfigs
+
+	return nil
+}
+
+// GetVersionsByProfileID gets all the config versions of the scoring profile from the middleware
+func (ecs *EngineConfigService) GetVersionsByProfileID(profileID int) error {
+	versions, err := ecs.EngineConfigRepo.GetVersions(profileID)
+	if err != nil {
+		return err
+	}
+
+	ecs.ProfileID = profileID
+	ecs.Versions = versions
+
+	return nil
+}
+
+// Update updates the given item configs of the scoring profile, the other item configs remain unchanged,
+// the merged item configs must pass the engine config validation, and then they are saved as a new config version
+func (ecs *EngineConfigService) Update(profileID int, itemConfigs []healthcheck.ItemConfig, loginName string) error {
+	currentItemConfigs, err := ecs.getItemConfigs(profileID)
+	if err != nil {
+		return err
+	}
+	if len(itemConfigs) == constant.ZeroInt {
+		return errors.New("item configs to be updated must not be empty")
+	}
+	err = ecs.validateItemNames(itemConfigs)
+	if err != nil {
+		return err
+	}
+	// merge the item configs, the order of the existing items is kept, the new items are appended
+	updatedItemConfigs := make(map[string]healthcheck.ItemConfig)
+	var itemNames []string
+	for _, itemConfig := range itemConfigs {
+		updatedItemConfigs[itemConfig.GetItemName()] = itemConfig
+		itemNames = append(itemNames, itemConfig.GetItemName())
+	}
+	var mergedItemConfigs []healthcheck.ItemConfig
+	for _, itemConfig := range currentItemConfigs {
+		updatedItemConfig, ok := updatedItemConfigs[itemConfig.GetItemName()]
+		if ok {
+			mergedItemConfigs = append(mergedItemConfigs, updatedItemConfig)
+			delete(updatedItemConfigs, itemConfig.GetItemName())
+			continue
+		}
+		mergedItemConfigs = append(mergedItemConfigs, itemConfig)
+	}
+	for _, itemConfig := range itemConfigs {
+		_, ok := updatedItemConfigs[itemConfig.GetItemName()]
+		if ok {
+			mergedItemConfigs = append(mergedItemConfigs, itemConfig)
+		}
+	}
+
+	return ecs.save(profileID, mergedItemConfigs, loginName,
+		fmt.Sprintf(engineConfigUpdateDescription, strings.Join(itemNames, constant.CommaString)))
+}
+
+// Replace replaces all the item configs of the scoring profile with the given item configs,
+// they must pass the engine config validation, and then they are saved as a new config version
+func (ecs *EngineConfigService) Replace(profileID int, itemConfigs []healthcheck.ItemConfig, loginName string) error {
+	// check if the engine config of the profile exists
+	_, err := ecs.getItemConfigs(profileID)
+	if err != nil {
+		return err
+	}
+	err = ecs.validateItemNames(itemConfigs)
+	if err != nil {
+		return err
+	}
+
+	return ecs.save(profileID, itemConfigs, loginName, engineConfigReplaceDescription)
+}
+
+// Rollback replaces the item configs of the scoring profile with the ones of the given config version,
+// they are saved as a new config version
+func (ecs *EngineConfigService) Rollback(profileID, version int, loginName string) error {
+	// check if the engine config of the profile exists
+	_, err := ecs.getItemConfigs(profileID)
+	if err != nil {
+		return err
+	}
+	engineConfigVersion, err := ecs.EngineConfigRepo.GetVersion(profileID, version)
+	if err != nil {
+		return err
+	}
+
+	return ecs.save(profileID, engineConfigVersion.GetItemConfigs(), loginName, fmt.Sprintf(engineConfigRollbackDescription, version))
+}
+
+// Sync syncs the item configs of all the scoring profiles with the global default engine config,
+// the item configs changed by the upgrade are saved as new config versions
+func (ecs *EngineConfigService) Sync() error {
+	return ecs.EngineConfigRepo.Sync()
+}
+
+// getItemConfigs gets the item configs of the scoring profile, it returns error if the engine config of the profile does not exist
+func (ecs *EngineConfigService) getItemConfigs(profileID int) ([]healthcheck.ItemConfig, error) {
+	itemConfigs, err := ecs.EngineConfigRepo.GetItemConfigs(profileID)
+	if err != nil {
+		return nil, err
+	}
+	if len(itemConfigs) == constant.ZeroInt {
+		return nil, errors.Errorf("engine config of the scoring profile does not exist. profile id: %d", profileID)
+	}
+
+	return itemConfigs, nil
+}
+
+// save validates the item configs as a whole engine config, and then saves them as a new config version
+func (ecs *EngineConfigService) save(profileID int, itemConfigs []healthcheck.ItemConfig, loginName, description string) error {
+	if loginName == constant.EmptyString {
+		return errors.New("login name must not be empty")
+	}
+	err := newDefaultEngineConfigWithItemConfigs(itemConfigs).Validate()
+	if err != nil {
+		return err
+	}
+	_, err = ecs.EngineConfigRepo.Save(profileID, itemConfigs, loginName, description)
+	if err != nil {
+		return err
+	}
+
+	return ecs.GetByProfileID(profileID)
+}
+
+// validateItemNames validates if the item names of the item configs are not empty and not duplicated
+func (ecs *EngineConfigService) validateItemNames(itemConfigs []healthcheck.ItemConfig) error {
+	itemNames := make(map[string]bool)
+	for _, itemConfig := range itemConfigs {
+		if itemConfig.GetItemName() == constant.EmptyString {
+			return errors.New("item name must not be empty")
+		}
+		if itemNames[itemConfig.GetItemName()] {
+			return errors.Errorf("item %s is duplicated in the item configs", itemConfig.GetItemName())
+		}
+		itemNames[itemConfig.GetItemName()] = true
+	}
+
+	return nil
+}
+
+// Marshal marshals EngineConfigService to json bytes
+func (ecs *EngineConfigService) Marshal() ([]byte, error) {
+	return ecs.MarshalWithFields(engineConfigProfileIDStruct, engineConfigVersionStruct, engineConfigItemConfigsStruct, engineConfigVersionsStruct)
+}
+
+// MarshalWithFields marshals only specified fields of the EngineConfigService to json bytes
+func (ecs *EngineConfigService) MarshalWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(ecs, fields...)
+}
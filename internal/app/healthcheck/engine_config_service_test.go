@@ -0,0 +1,142 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+var testEngineConfigService *EngineConfigService
+
+func init() {
+	testInitDASMySQLPool()
+	testEngineConfigService = newEngineConfigService(NewEngineConfigRepoWithGlobal())
+}
+
+func TestEngineConfigService_All(t *testing.T) {
+	TestEngineConfigService_GetByProfileID(t)
+	TestEngineConfigService_GetVersionsByProfileID(t)
+	TestEngineConfigService_Update(t)
+	TestEngineConfigService_Replace(t)
+	TestEngineConfigService_Rollback(t)
+	TestEngineConfigService_Sync(t)
+	TestEngineConfigService_Marshal(t)
+}
+
+func TestEngineConfigService_GetByProfileID(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testEngineConfigService.GetByProfileID(defaultScoringProfileID)
+	asst.Nil(err, common.CombineMessageWithError("test GetByProfileID() failed", err))
+	asst.NotZero(len(testEngineConfigService.GetItemConfigs()), "test GetByProfileID() failed")
+	// the engine config of a nonexistent profile
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetByProfileID() failed", err))
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByProfileID() failed", err))
+	err = testEngineConfigService.GetByProfileID(profile.Identity())
+	asst.NotNil(err, "test GetByProfileID() failed")
+}
+
+func TestEngineConfigService_GetVersionsByProfileID(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetVersionsByProfileID() failed", err))
+	err = testEngineConfigService.GetVersionsByProfileID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetVersionsByProfileID() failed", err))
+	asst.Equal(1, len(testEngineConfigService.GetVersions()), "test GetVersionsByProfileID() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetVersionsByProfileID() failed", err))
+}
+
+func TestEngineConfigService_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	itemConfig := profile.GetItemConfigs()[constant.ZeroInt]
+	updated := NewDefaultItemConfig(itemConfig.GetItemName(), itemConfig.GetItemWeight(), itemConfig.GetLowWatermark(), itemConfig.GetHighWatermark()+1,
+		itemConfig.GetUnit(), itemConfig.GetScoreDeductionPerUnitHigh(), itemConfig.GetMaxScoreDeductionHigh(),
+		itemConfig.GetScoreDeductionPerUnitMedium(), itemConfig.GetMaxScoreDeductionMedium())
+	err = testEngineConfigService.Update(profile.Identity(), []healthcheck.ItemConfig{updated}, testEngineConfigLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testEngineConfigVersion+1, testEngineConfigService.GetVersion(), "test Update() failed")
+	asst.Equal(len(profile.GetItemConfigs()), len(testEngineConfigService.GetItemConfigs()), "test Update() failed")
+	asst.Equal(updated.GetHighWatermark(), testEngineConfigService.GetItemConfigs()[constant.ZeroInt].GetHighWatermark(), "test Update() failed")
+	// the item weights do not sum to 100, it will not be saved
+	invalid := NewDefaultItemConfig(itemConfig.GetItemName(), itemConfig.GetItemWeight()+1, itemConfig.GetLowWatermark(), itemConfig.GetHighWatermark(),
+		itemConfig.GetUnit(), itemConfig.GetScoreDeductionPerUnitHigh(), itemConfig.GetMaxScoreDeductionHigh(),
+		itemConfig.GetScoreDeductionPerUnitMedium(), itemConfig.GetMaxScoreDeductionMedium())
+	err = testEngineConfigService.Update(profile.Identity(), []healthcheck.ItemConfig{invalid}, testEngineConfigLoginName)
+	asst.NotNil(err, "test Update() failed")
+	err = testEngineConfigService.GetByProfileID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testEngineConfigVersion+1, testEngineConfigService.GetVersion(), "test Update() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestEngineConfigService_Replace(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Replace() failed", err))
+	err = testEngineConfigService.Replace(profile.Identity(), profile.GetItemConfigs(), testEngineConfigLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test Replace() failed", err))
+	asst.Equal(testEngineConfigVersion+1, testEngineConfigService.GetVersion(), "test Replace() failed")
+	// missing item
+	err = testEngineConfigService.Replace(profile.Identity(), profile.GetItemConfigs()[1:], testEngineConfigLoginName)
+	asst.NotNil(err, "test Replace() failed")
+	// duplicate item
+	err = testEngineConfigService.Replace(profile.Identity(), append(profile.GetItemConfigs(), profile.GetItemConfigs()[constant.ZeroInt]), testEngineConfigLoginName)
+	asst.NotNil(err, "test Replace() failed")
+	// empty login name
+	err = testEngineConfigService.Replace(profile.Identity(), profile.GetItemConfigs(), constant.EmptyString)
+	asst.NotNil(err, "test Replace() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Replace() failed", err))
+}
+
+func TestEngineConfigService_Rollback(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Rollback() failed", err))
+	err = testEngineConfigService.Rollback(profile.Identity(), testEngineConfigVersion, testEngineConfigLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test Rollback() failed", err))
+	asst.Equal(testEngineConfigVersion+1, testEngineConfigService.GetVersion(), "test Rollback() failed")
+	asst.Equal(len(profile.GetItemConfigs()), len(testEngineConfigService.GetItemConfigs()), "test Rollback() failed")
+	// nonexistent version
+	err = testEngineConfigService.Rollback(profile.Identity(), testEngineConfigVersion+10, testEngineConfigLoginName)
+	asst.NotNil(err, "test Rollback() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Rollback() failed", err))
+}
+
+func TestEngineConfigService_Sync(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testEngineConfigService.Sync()
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	// the global default engine config is versioned after syncing
+	version, err := testEngineConfigService.GetLatestVersion(defaultScoringProfileID)
+	asst.Nil(err, common.CombineMessageWithError("test Sync() failed", err))
+	asst.NotZero(version, "test Sync() failed")
+}
+
+func TestEngineConfigService_Marshal(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testEngineConfigService.GetByProfileID(defaultScoringProfileID)
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	_, err = testEngineConfigService.Marshal()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+}
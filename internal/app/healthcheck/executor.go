@@ -0,0 +1,130 @@
+package healthcheck
+
+import (
+	"sync"
+
+	"github.com/romberli/das/config"
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultScheduledPriority is the priority of the operations triggered by the scheduler
+	defaultScheduledPriority = 1
+	// defaultManualPriority is the priority of the operations triggered manually, they run before the scheduled ones
+	defaultManualPriority = 2
+)
+
+var (
+	// operationExecutor runs the operations of this process, it is initiated when it is used for the first time,
+	// so that the concurrency could be read from the config
+	operationExecutor     *executor
+	operationExecutorOnce sync.Once
+)
+
+// getExecutor returns the executor of this process
+func getExecutor() *executor {
+	operationExecutorOnce.Do(func() {
+		operationExecutor = newExecutor(viper.GetInt(config.HealthcheckConcurrencyKey))
+	})
+
+	return operationExecutor
+}
+
+// task is an operation waiting in the queue of the executor
+type task struct {
+	operationID int
+	priority    int
+	run         func()
+	done        chan struct{}
+}
+
+// executor runs the operations with a bounded number of workers,
+// the operations which exceed the concurrency wait in the queue,
+// the ones with higher priority run first, and the ones with the same priority run in the order of the operation id.
+// note that the queue and the concurrency are per process, each das process only runs the operations submitted to it,
+// so the priority is not respected across the processes, and the total concurrency is the sum of all the processes,
+// t_hc_operation_history only guarantees that an operation is started by only one process
+type executor struct {
+	mutex       sync.Mutex
+	concurrency int
+	running     int
+	queue       []*task
+}
+
+// newExecutor returns a new *executor
+func newExecutor(concurrency int) *executor {
+	return &executor{concurrency: concurrency}
+}
+
+// submit puts the operation into the queue, run will be called by a worker when the operation is dequeued,
+// it returns a channel which will be closed after run returns
+func (e *executor) submit(operationID, priority int, run func()) <-chan struct{} {
+	t := &task{
+		operationID: operationID,
+		priority:    priority,
+		run:         run,
+		done:        make(chan struct{}),
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.running < e.concurrency {
+		e.running++
+		go e.work(t)
+		return t.done
+	}
+	e.queue = append(e.queue, t)
+
+	return t.done
+}
+
+// remove removes the operation from the queue, it returns false if the operation is not in the queue
+func (e *executor) remove(operationID int) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for i, t := range e.queue {
+		if t.operationID == operationID {
+			e.queue = append(e.queue[:i], e.queue[i+1:]...)
+			close(t.done)
+			return true
+		}
+	}
+
+	return false
+}
+
+// work runs the given task, and then keeps running the tasks dequeued from the queue until the queue is empty
+func (e *executor) work(t *task) {
+	for t != nil {
+		t.run()
+		close(t.done)
+		t = e.dequeue()
+	}
+}
+
+// dequeue removes and returns the task with the highest priority from the queue,
+// if the queue is empty, it releases the worker and returns nil
+func (e *executor) dequeue() *task {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if len(e.queue) == constant.ZeroInt {
+		e.running--
+		return nil
+	}
+
+	index := constant.ZeroInt
+	for i, t := range e.queue {
+		highest := e.queue[index]
+		if t.priority > highest.priority || (t.priority == highest.priority && t.operationID < highest.operationID) {
+			index = i
+		}
+	}
+	t := e.queue[index]
+	e.queue = append(e.queue[:index], e.queue[index+1:]...)
+
+	return t
+}
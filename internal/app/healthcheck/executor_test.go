@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testExecutorConcurrency = 2
+	testExecutorTaskNum     = 10
+	testExecutorWaitTime    = 10 * time.Millisecond
+)
+
+func TestExecutor_All(t *testing.T) {
+	TestExecutor_Submit(t)
+	TestExecutor_Priority(t)
+	TestExecutor_Remove(t)
+}
+
+func TestExecutor_Submit(t *testing.T) {
+	asst := assert.New(t)
+
+	e := newExecutor(testExecutorConcurrency)
+	var (
+		mutex      sync.Mutex
+		running    int
+		maxRunning int
+		dones      []<-chan struct{}
+	)
+	for i := 1; i <= testExecutorTaskNum; i++ {
+		done := e.submit(i, defaultManualPriority, func() {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+			time.Sleep(testExecutorWaitTime)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		})
+		dones = append(dones, done)
+	}
+	for _, done := range dones {
+		<-done
+	}
+	asst.Equal(testExecutorConcurrency, maxRunning, "test Submit() failed")
+}
+
+func TestExecutor_Priority(t *testing.T) {
+	asst := assert.New(t)
+
+	e := newExecutor(1)
+	var (
+		mutex sync.Mutex
+		order []int
+	)
+	run := func(operationID int) func() {
+		return func() {
+			mutex.Lock()
+			order = append(order, operationID)
+			mutex.Unlock()
+			time.Sleep(testExecutorWaitTime)
+		}
+	}
+	// the first task occupies the only worker, the others wait in the queue
+	var dones []<-chan struct{}
+	dones = append(dones, e.submit(1, defaultScheduledPriority, run(1)))
+	dones = append(dones, e.submit(2, defaultScheduledPriority, run(2)))
+	dones = append(dones, e.submit(3, defaultManualPriority, run(3)))
+	dones = append(dones, e.submit(4, defaultScheduledPriority, run(4)))
+	dones = append(dones, e.submit(5, defaultManualPriority, run(5)))
+	for _, done := range dones {
+		<-done
+	}
+	asst.Equal([]int{1, 3, 5, 2, 4}, order, "test Priority() failed")
+}
+
+func TestExecutor_Remove(t *testing.T) {
+	asst := assert.New(t)
+
+	e := newExecutor(1)
+	started := false
+	first := e.submit(1, defaultManualPriority, func() { time.Sleep(testExecutorWaitTime) })
+	second := e.submit(2, defaultManualPriority, func() { started = true })
+	asst.True(e.remove(2), "test Remove() failed")
+	// the removed operation is not in the queue any more
+	asst.False(e.remove(2), "test Remove() failed")
+	// the running operation is not in the queue
+	asst.False(e.remove(1), "test Remove() failed")
+	<-second
+	<-first
+	asst.False(started, "test Remove() failed")
+}
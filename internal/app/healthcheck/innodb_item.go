@@ -0,0 +1,358 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	defaultInnoDBLongTransactionItemName   = "innodb_long_transaction"
+	defaultInnoDBHistoryListLengthItemName = "innodb_history_list_length"
+	defaultInnoDBLockWaitItemName          = "innodb_lock_wait"
+	defaultInnoDBDeadlockItemName          = "innodb_deadlock"
+
+	// the longest running transactions are most likely to block the purge thread
+	historyListLengthBlockerNum = 5
+	// the value of the deadlock which was detected during the check range
+	deadlockDetectedValue = 1
+)
+
+var (
+	_ healthcheck.CheckItem = (*InnoDBLongTransactionItem)(nil)
+	_ healthcheck.CheckItem = (*InnoDBHistoryListLengthItem)(nil)
+	_ healthcheck.CheckItem = (*InnoDBLockWaitItem)(nil)
+	_ healthcheck.CheckItem = (*InnoDBDeadlockItem)(nil)
+)
+
+func init() {
+	RegisterCheckItem(NewInnoDBLongTransactionItem())
+	RegisterCheckItem(NewInnoDBHistoryListLengthItem())
+	RegisterCheckItem(NewInnoDBLockWaitItem())
+	RegisterCheckItem(NewInnoDBDeadlockItem())
+}
+
+// HistoryListLengthData is the history list length and the transactions which may block the purge thread
+type HistoryListLengthData struct {
+	HistoryListLength int                             `json:"history_list_length"`
+	LongTransactions  []healthcheck.InnoDBTransaction `json:"long_transactions"`
+}
+
+// InnoDBLongTransactionItem checks the running innodb transactions, the value of each transaction is how long it has been running
+type InnoDBLongTransactionItem struct{}
+
+// NewInnoDBLongTransactionItem returns a new *InnoDBLongTransactionItem
+func NewInnoDBLongTransactionItem() *InnoDBLongTransactionItem {
+	return &InnoDBLongTransactionItem{}
+}
+
+// GetName returns the item name
+func (iltri *InnoDBLongTransactionItem) GetName() string {
+	return defaultInnoDBLongTransactionItemName
+}
+
+// GetDataSource returns the data source of the item
+func (iltri *InnoDBLongTransactionItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the long running transactions
+func (iltri *InnoDBLongTransactionItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	transactions, err := env.GetApplicationMySQLRepo().GetLongTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := env.GetItemConfig(iltri.GetName())
+
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		highTransactions []healthcheck.InnoDBTransaction
+		advices          []string
+	)
+	for _, transaction := range transactions {
+		duration := float64(transaction.GetDuration())
+		switch {
+		case duration >= cfg.GetHighWatermark():
+			highTransactions = append(highTransactions, transaction)
+			highSum += duration
+			highCount++
+			advices = append(advices, fmt.Sprintf("%s has been running for %d seconds, sql: %s",
+				getInnoDBTransactionDesc(transaction), transaction.GetDuration(), transaction.GetQuery()))
+		case duration >= cfg.GetLowWatermark():
+			mediumSum += duration
+			mediumCount++
+		}
+	}
+
+	jsonBytesTotal, err := json.Marshal(transactions)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highTransactions)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(cfg, highSum, highCount, mediumSum, mediumCount)
+
+	return NewItemResultWithEnv(env, iltri, score, string(jsonBytesTotal), string(jsonBytesHigh), strings.Join(advices, constant.CommaString)), nil
+}
+
+// InnoDBHistoryListLengthItem checks the history list length of the innodb undo logs,
+// the long running transactions are included in the data when the history list length is not normal, as they may block the purge thread
+type InnoDBHistoryListLengthItem struct{}
+
+// NewInnoDBHistoryListLengthItem returns a new *InnoDBHistoryListLengthItem
+func NewInnoDBHistoryListLengthItem() *InnoDBHistoryListLengthItem {
+	return &InnoDBHistoryListLengthItem{}
+}
+
+// GetName returns the item name
+func (ihlli *InnoDBHistoryListLengthItem) GetName() string {
+	return defaultInnoDBHistoryListLengthItemName
+}
+
+// GetDataSource returns the data source of the item
+func (ihlli *InnoDBHistoryListLengthItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the history list length
+func (ihlli *InnoDBHistoryListLengthItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	repo := env.GetApplicationMySQLRepo()
+	historyListLength, err := repo.GetHistoryListLength()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := env.GetItemConfig(ihlli.GetName())
+	data := &HistoryListLengthData{HistoryListLength: historyListLength}
+	value := float64(historyListLength)
+
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		highDatas []*HistoryListLengthData
+		advice    string
+	)
+	switch {
+	case value >= cfg.GetHighWatermark():
+		highDatas = append(highDatas, data)
+		highSum += value
+		highCount++
+	case value >= cfg.GetLowWatermark():
+		mediumSum += value
+		mediumCount++
+	}
+
+	if value >= cfg.GetLowWatermark() {
+		transactions, err := repo.GetLongTransactions()
+		if err != nil {
+			return nil, err
+		}
+		if len(transactions) > historyListLengthBlockerNum {
+			transactions = transactions[:historyListLengthBlockerNum]
+		}
+		data.LongTransactions = transactions
+
+		var descs []string
+		for _, transaction := range transactions {
+			descs = append(descs, fmt.Sprintf("%s(%d seconds), sql: %s",
+				getInnoDBTransactionDesc(transaction), transaction.GetDuration(), transaction.GetQuery()))
+		}
+		advice = fmt.Sprintf("history list length is %d, please check if the purge thread is blocked by the long running transactions", historyListLength)
+		if len(descs) > constant.ZeroInt {
+			advice += fmt.Sprintf(": %s", strings.Join(descs, constant.CommaString))
+		}
+	}
+
+	jsonBytesTotal, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highDatas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(cfg, highSum, highCount, mediumSum, mediumCount)
+
+	return NewItemResultWithEnv(env, ihlli, score, string(jsonBytesTotal), string(jsonBytesHigh), advice), nil
+}
+
+// InnoDBLockWaitItem checks the innodb transactions which are waiting for the locks, the value of each lock wait is the wait age
+type InnoDBLockWaitItem struct{}
+
+// NewInnoDBLockWaitItem returns a new *InnoDBLockWaitItem
+func NewInnoDBLockWaitItem() *InnoDBLockWaitItem {
+	return &InnoDBLockWaitItem{}
+}
+
+// GetName returns the item name
+func (ilwi *InnoDBLockWaitItem) GetName() string {
+	return defaultInnoDBLockWaitItemName
+}
+
+// GetDataSource returns the data source of the item
+func (ilwi *InnoDBLockWaitItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the lock waits
+func (ilwi *InnoDBLockWaitItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	lockWaits, err := env.GetApplicationMySQLRepo().GetLockWaits()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := env.GetItemConfig(ilwi.GetName())
+
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		highLockWaits []healthcheck.InnoDBLockWait
+		advices       []string
+	)
+	for _, lockWait := range lockWaits {
+		waitAge := float64(lockWait.GetWaitAge())
+		switch {
+		case waitAge >= cfg.GetHighWatermark():
+			highLockWaits = append(highLockWaits, lockWait)
+			highSum += waitAge
+			highCount++
+			advices = append(advices, fmt.Sprintf("transaction %s of thread %d has been waiting for the lock on %s for %d seconds, sql: %s, "+
+				"it is blocked by transaction %s of thread %d, sql: %s",
+				lockWait.GetWaitingTrxID(), lockWait.GetWaitingThreadID(), lockWait.GetLockedTable(), lockWait.GetWaitAge(), lockWait.GetWaitingQuery(),
+				lockWait.GetBlockingTrxID(), lockWait.GetBlockingThreadID(), lockWait.GetBlockingQuery()))
+		case waitAge >= cfg.GetLowWatermark():
+			mediumSum += waitAge
+			mediumCount++
+		}
+	}
+
+	jsonBytesTotal, err := json.Marshal(lockWaits)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highLockWaits)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(cfg, highSum, highCount, mediumSum, mediumCount)
+
+	return NewItemResultWithEnv(env, ilwi, score, string(jsonBytesTotal), string(jsonBytesHigh), strings.Join(advices, constant.CommaString)), nil
+}
+
+// InnoDBDeadlockItem checks the latest deadlock detected by innodb,
+// the value is 1 if the deadlock was detected between the start time and the end time of the operation, otherwise, it is 0
+type InnoDBDeadlockItem struct{}
+
+// NewInnoDBDeadlockItem returns a new *InnoDBDeadlockItem
+func NewInnoDBDeadlockItem() *InnoDBDeadlockItem {
+	return &InnoDBDeadlockItem{}
+}
+
+// GetName returns the item name
+func (idi *InnoDBDeadlockItem) GetName() string {
+	return defaultInnoDBDeadlockItemName
+}
+
+// GetDataSource returns the data source of the item
+func (idi *InnoDBDeadlockItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the latest deadlock
+func (idi *InnoDBDeadlockItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	deadlock, err := env.GetApplicationMySQLRepo().GetLatestDeadlock()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := env.GetItemConfig(idi.GetName())
+
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		highDeadlocks []healthcheck.InnoDBDeadlock
+		advice        string
+	)
+	deadlockValue := getInnoDBDeadlockValue(env.GetOperationInfo(), deadlock)
+	value := float64(deadlockValue)
+	switch {
+	case value >= cfg.GetHighWatermark():
+		highDeadlocks = append(highDeadlocks, deadlock)
+		highSum += value
+		highCount++
+	case value >= cfg.GetLowWatermark():
+		mediumSum += value
+		mediumCount++
+	}
+	if deadlockValue == deadlockDetectedValue {
+		advice = getInnoDBDeadlockDesc(deadlock)
+	}
+
+	// the deadlock which was detected before the start time is also shown in the data
+	var datas []healthcheck.InnoDBDeadlock
+	if !deadlock.GetDetectedTime().IsZero() {
+		datas = append(datas, deadlock)
+	}
+	jsonBytesTotal, err := json.Marshal(datas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highDeadlocks)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(cfg, highSum, highCount, mediumSum, mediumCount)
+
+	return NewItemResultWithEnv(env, idi, score, string(jsonBytesTotal), string(jsonBytesHigh), advice), nil
+}
+
+// getInnoDBDeadlockValue returns 1 if the deadlock was detected between the start time and the end time of the operation, otherwise, it returns 0
+func getInnoDBDeadlockValue(operationInfo healthcheck.OperationInfo, deadlock healthcheck.InnoDBDeadlock) int {
+	detectedTime := deadlock.GetDetectedTime()
+	if detectedTime.IsZero() || detectedTime.Before(operationInfo.GetStartTime()) || detectedTime.After(operationInfo.GetEndTime()) {
+		return constant.ZeroInt
+	}
+
+	return deadlockDetectedValue
+}
+
+// getInnoDBDeadlockDesc returns the description of the deadlock which contains the sqls of the transactions
+func getInnoDBDeadlockDesc(deadlock healthcheck.InnoDBDeadlock) string {
+	var descs []string
+	for _, transaction := range deadlock.GetTransactions() {
+		descs = append(descs, fmt.Sprintf("(%d) transaction %s of thread %d, sql: %s",
+			transaction.GetTrxNum(), transaction.GetTrxID(), transaction.GetThreadID(), transaction.GetQuery()))
+	}
+
+	return fmt.Sprintf("deadlock was detected at %s, transaction (%d) was rolled back: %s",
+		deadlock.GetDetectedTime().Format(constant.TimeLayoutSecond), deadlock.GetRolledBackTrxNum(), strings.Join(descs, constant.CommaString))
+}
+
+// getInnoDBTransactionDesc returns the description of the transaction
+func getInnoDBTransactionDesc(transaction healthcheck.InnoDBTransaction) string {
+	return fmt.Sprintf("transaction %s of thread %d(%s@%s)", transaction.GetTrxID(), transaction.GetThreadID(), transaction.GetUser(), transaction.GetHost())
+}
@@ -0,0 +1,108 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testInnoDBDeadlockDetectedTime = "2022-06-01 10:00:00"
+	testInnoDBStatus               = `
+=====================================
+2022-06-01 10:05:00 0x7f8b5c0c0700 INNODB MONITOR OUTPUT
+=====================================
+------------------------
+LATEST DETECTED DEADLOCK
+------------------------
+2022-06-01 10:00:00 0x7f8b5c0c0700
+*** (1) TRANSACTION:
+TRANSACTION 12345, ACTIVE 5 sec starting index read
+mysql tables in use 1, locked 1
+LOCK WAIT 3 lock struct(s), heap size 1136, 2 row lock(s)
+MySQL thread id 10, OS thread handle 140, query id 100 localhost root updating
+update t01
+set name = 'a' where id = 1
+*** (1) HOLDS THE LOCK(S):
+RECORD LOCKS space id 2 page no 4 n bits 72 index PRIMARY of table test.t01 trx id 12345 lock_mode X locks rec but not gap
+*** (1) WAITING FOR THIS LOCK TO BE GRANTED:
+RECORD LOCKS space id 2 page no 4 n bits 72 index PRIMARY of table test.t01 trx id 12345 lock_mode X locks rec but not gap waiting
+*** (2) TRANSACTION:
+TRANSACTION 12346, ACTIVE 3 sec starting index read
+mysql tables in use 1, locked 1
+MySQL thread id 11, OS thread handle 141, query id 101 localhost root updating
+update t01 set name = 'b' where id = 2
+*** (2) HOLDS THE LOCK(S):
+RECORD LOCKS space id 2 page no 4 n bits 72 index PRIMARY of table test.t01 trx id 12346 lock_mode X locks rec but not gap
+*** WE ROLL BACK TRANSACTION (2)
+------------
+TRANSACTIONS
+------------
+Trx id counter 12350
+`
+	testInnoDBStatusWithoutDeadlock = `
+------------
+TRANSACTIONS
+------------
+Trx id counter 12350
+`
+)
+
+func TestInnoDBItem_All(t *testing.T) {
+	TestInnoDBItem_parseLatestDeadlock(t)
+	TestInnoDBItem_getInnoDBDeadlockValue(t)
+	TestInnoDBItem_getInnoDBDeadlockDesc(t)
+}
+
+func TestInnoDBItem_parseLatestDeadlock(t *testing.T) {
+	asst := assert.New(t)
+
+	deadlock, err := parseLatestDeadlock(testInnoDBStatus)
+	asst.Nil(err, common.CombineMessageWithError("test parseLatestDeadlock() failed", err))
+	asst.Equal(testInnoDBDeadlockDetectedTime, deadlock.GetDetectedTime().Format(constant.TimeLayoutSecond), "test parseLatestDeadlock() failed")
+	asst.Equal(2, deadlock.GetRolledBackTrxNum(), "test parseLatestDeadlock() failed")
+	transactions := deadlock.GetTransactions()
+	asst.Equal(2, len(transactions), "test parseLatestDeadlock() failed")
+	asst.Equal("12345", transactions[constant.ZeroInt].GetTrxID(), "test parseLatestDeadlock() failed")
+	asst.Equal(10, transactions[constant.ZeroInt].GetThreadID(), "test parseLatestDeadlock() failed")
+	// the query contains multiple lines
+	asst.Equal("update t01\nset name = 'a' where id = 1", transactions[constant.ZeroInt].GetQuery(), "test parseLatestDeadlock() failed")
+	asst.Equal(2, transactions[1].GetTrxNum(), "test parseLatestDeadlock() failed")
+	asst.Equal("update t01 set name = 'b' where id = 2", transactions[1].GetQuery(), "test parseLatestDeadlock() failed")
+	// no deadlock
+	deadlock, err = parseLatestDeadlock(testInnoDBStatusWithoutDeadlock)
+	asst.Nil(err, common.CombineMessageWithError("test parseLatestDeadlock() failed", err))
+	asst.True(deadlock.GetDetectedTime().IsZero(), "test parseLatestDeadlock() failed")
+	// invalid detected time
+	_, err = parseLatestDeadlock("LATEST DETECTED DEADLOCK\n------------------------\n220601 10:00:00\n")
+	asst.NotNil(err, "test parseLatestDeadlock() failed")
+}
+
+func TestInnoDBItem_getInnoDBDeadlockValue(t *testing.T) {
+	asst := assert.New(t)
+
+	deadlock, err := parseLatestDeadlock(testInnoDBStatus)
+	asst.Nil(err, common.CombineMessageWithError("test getInnoDBDeadlockValue() failed", err))
+	startTime := deadlock.GetDetectedTime().Add(-time.Hour)
+	// the deadlock was detected during the check range
+	operationInfo := NewOperationInfo(testResultOperationID, nil, nil, nil, nil, startTime, startTime.Add(constant.Day), testHealthcheckStep)
+	asst.Equal(deadlockDetectedValue, getInnoDBDeadlockValue(operationInfo, deadlock), "test getInnoDBDeadlockValue() failed")
+	// the deadlock was detected before the start time
+	operationInfo = NewOperationInfo(testResultOperationID, nil, nil, nil, nil, startTime.Add(2*time.Hour), startTime.Add(constant.Day), testHealthcheckStep)
+	asst.Equal(constant.ZeroInt, getInnoDBDeadlockValue(operationInfo, deadlock), "test getInnoDBDeadlockValue() failed")
+	// no deadlock
+	asst.Equal(constant.ZeroInt, getInnoDBDeadlockValue(operationInfo, NewEmptyInnoDBDeadlock()), "test getInnoDBDeadlockValue() failed")
+}
+
+func TestInnoDBItem_getInnoDBDeadlockDesc(t *testing.T) {
+	asst := assert.New(t)
+
+	deadlock, err := parseLatestDeadlock(testInnoDBStatus)
+	asst.Nil(err, common.CombineMessageWithError("test getInnoDBDeadlockDesc() failed", err))
+	desc := getInnoDBDeadlockDesc(deadlock)
+	asst.Contains(desc, testInnoDBDeadlockDetectedTime, "test getInnoDBDeadlockDesc() failed")
+	asst.Contains(desc, "update t01 set name = 'b' where id = 2", "test getInnoDBDeadlockDesc() failed")
+}
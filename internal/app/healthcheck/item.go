@@ -0,0 +1,79 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	DataSourceApplicationMySQL = "application_mysql"
+	DataSourcePrometheus       = "prometheus"
+	DataSourceQuery            = "query"
+)
+
+var (
+	checkItemsMutex sync.RWMutex
+	checkItems      = make(map[string]healthcheck.CheckItem)
+)
+
+// RegisterCheckItem registers the check item, the items which are configured in t_hc_default_engine_config
+// must be registered before the engine runs, so it is supposed to be called in the init() function.
+// it panics if the item is nil or an item with the same name was already registered
+func RegisterCheckItem(item healthcheck.CheckItem) {
+	checkItemsMutex.Lock()
+	defer checkItemsMutex.Unlock()
+
+	if item == nil {
+		panic("healthcheck: RegisterCheckItem() item is nil")
+	}
+	if _, ok := checkItems[item.GetName()]; ok {
+		panic(fmt.Sprintf("healthcheck: RegisterCheckItem() item %s was already registered", item.GetName()))
+	}
+
+	checkItems[item.GetName()] = item
+}
+
+// GetCheckItem returns the registered check item of given name
+func GetCheckItem(name string) (healthcheck.CheckItem, bool) {
+	checkItemsMutex.RLock()
+	defer checkItemsMutex.RUnlock()
+
+	item, ok := checkItems[name]
+
+	return item, ok
+}
+
+// calculateScore calculates the score of the item with the watermarks of the item config,
+// highSum and highCount are the summary and the count of the values which are larger than the high watermark,
+// mediumSum and mediumCount are the summary and the count of the values which are between the low and the high watermark
+func calculateScore(cfg healthcheck.ItemConfig, highSum float64, highCount int, mediumSum float64, mediumCount int) int {
+	var (
+		scoreDeductionHigh   float64
+		scoreDeductionMedium float64
+	)
+
+	// high score deduction
+	if highCount > constant.ZeroInt {
+		scoreDeductionHigh = (highSum/float64(highCount) - cfg.GetHighWatermark()) / cfg.GetUnit() * cfg.GetScoreDeductionPerUnitHigh()
+		if scoreDeductionHigh > cfg.GetMaxScoreDeductionHigh() {
+			scoreDeductionHigh = cfg.GetMaxScoreDeductionHigh()
+		}
+	}
+	// medium score deduction
+	if mediumCount > constant.ZeroInt {
+		scoreDeductionMedium = (mediumSum/float64(mediumCount) - cfg.GetLowWatermark()) / cfg.GetUnit() * cfg.GetScoreDeductionPerUnitMedium()
+		if scoreDeductionMedium > cfg.GetMaxScoreDeductionMedium() {
+			scoreDeductionMedium = cfg.GetMaxScoreDeductionMedium()
+		}
+	}
+	// calculate score
+	score := int(defaultMaxScore - scoreDeductionHigh - scoreDeductionMedium)
+	if score < defaultMinScore {
+		score = defaultMinScore
+	}
+
+	return score
+}
@@ -0,0 +1,160 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	ItemAccuracyAccurate   = 1
+	ItemAccuracyInaccurate = 2
+)
+
+var (
+	_ healthcheck.ItemFeedback = (*ItemFeedback)(nil)
+	_ healthcheck.FeedbackStat = (*FeedbackStat)(nil)
+)
+
+// ItemFeedback is the accuracy feedback of a check item of an operation, it is saved as a row of t_hc_item_feedback
+type ItemFeedback struct {
+	ID             int       `middleware:"id" json:"id"`
+	OperationID    int       `middleware:"operation_id" json:"operation_id"`
+	ItemName       string    `middleware:"item_name" json:"item_name"`
+	Accuracy       int       `middleware:"accuracy" json:"accuracy"`
+	Comment        string    `middleware:"comment" json:"comment"`
+	LoginName      string    `middleware:"login_name" json:"login_name"`
+	DelFlag        int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime     time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewItemFeedback returns a new *ItemFeedback
+func NewItemFeedback(operationID int, itemName string, accuracy int, comment, loginName string) *ItemFeedback {
+	return &ItemFeedback{
+		OperationID: operationID,
+		ItemName:    itemName,
+		Accuracy:    accuracy,
+		Comment:     comment,
+		LoginName:   loginName,
+	}
+}
+
+// NewEmptyItemFeedback returns an empty *ItemFeedback
+func NewEmptyItemFeedback() *ItemFeedback {
+	return &ItemFeedback{}
+}
+
+// Identity returns the identity
+func (f *ItemFeedback) Identity() int {
+	return f.ID
+}
+
+// GetOperationID returns the operation id
+func (f *ItemFeedback) GetOperationID() int {
+	return f.OperationID
+}
+
+// GetItemName returns the item name
+func (f *ItemFeedback) GetItemName() string {
+	return f.ItemName
+}
+
+// GetAccuracy returns the accuracy, 1 means accurate, 2 means inaccurate
+func (f *ItemFeedback) GetAccuracy() int {
+	return f.Accuracy
+}
+
+// GetComment returns the comment
+func (f *ItemFeedback) GetComment() string {
+	return f.Comment
+}
+
+// GetLoginName returns the login name of the reviewer
+func (f *ItemFeedback) GetLoginName() string {
+	return f.LoginName
+}
+
+// GetDelFlag returns the delete flag
+func (f *ItemFeedback) GetDelFlag() int {
+	return f.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (f *ItemFeedback) GetCreateTime() time.Time {
+	return f.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (f *ItemFeedback) GetLastUpdateTime() time.Time {
+	return f.LastUpdateTime
+}
+
+// FeedbackStat is the aggregated accuracy feedbacks of a check item,
+// grouped by the check date, the environment and the engine config which was used by the checks
+type FeedbackStat struct {
+	CheckDate       string  `middleware:"check_date" json:"check_date"`
+	ItemName        string  `middleware:"item_name" json:"item_name"`
+	EnvID           int     `middleware:"env_id" json:"env_id"`
+	ProfileID       int     `middleware:"profile_id" json:"profile_id"`
+	ConfigVersion   int     `middleware:"config_version" json:"config_version"`
+	FeedbackCount   int     `middleware:"feedback_count" json:"feedback_count"`
+	InaccurateCount int     `middleware:"inaccurate_count" json:"inaccurate_count"`
+	InaccurateRate  float64 `json:"inaccurate_rate"`
+}
+
+// NewEmptyFeedbackStat returns an empty *FeedbackStat
+func NewEmptyFeedbackStat() *FeedbackStat {
+	return &FeedbackStat{}
+}
+
+// GetCheckDate returns the date when the checks were run
+func (fs *FeedbackStat) GetCheckDate() string {
+	return fs.CheckDate
+}
+
+// GetItemName returns the item name
+func (fs *FeedbackStat) GetItemName() string {
+	return fs.ItemName
+}
+
+// GetEnvID returns the environment id of the mysql servers
+func (fs *FeedbackStat) GetEnvID() int {
+	return fs.EnvID
+}
+
+// GetProfileID returns the id of the scoring profile which was used by the checks, 0 means the global default engine config
+func (fs *FeedbackStat) GetProfileID() int {
+	return fs.ProfileID
+}
+
+// GetConfigVersion returns the engine config version which was used by the checks
+func (fs *FeedbackStat) GetConfigVersion() int {
+	return fs.ConfigVersion
+}
+
+// GetFeedbackCount returns the number of the feedbacks
+func (fs *FeedbackStat) GetFeedbackCount() int {
+	return fs.FeedbackCount
+}
+
+// GetInaccurateCount returns the number of the inaccurate feedbacks
+func (fs *FeedbackStat) GetInaccurateCount() int {
+	return fs.InaccurateCount
+}
+
+// GetInaccurateRate returns the inaccurate count divided by the feedback count
+func (fs *FeedbackStat) GetInaccurateRate() float64 {
+	return fs.InaccurateRate
+}
+
+// calculateInaccurateRate calculates the inaccurate rate with the counts
+func (fs *FeedbackStat) calculateInaccurateRate() {
+	if fs.FeedbackCount == constant.ZeroInt {
+		fs.InaccurateRate = constant.ZeroInt
+		return
+	}
+
+	fs.InaccurateRate = float64(fs.InaccurateCount) / float64(fs.FeedbackCount)
+}
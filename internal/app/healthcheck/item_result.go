@@ -0,0 +1,174 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	ItemStatusOK      = 0
+	ItemStatusSkipped = 1
+	ItemStatusError   = 2
+)
+
+var _ healthcheck.ItemResult = (*ItemResult)(nil)
+
+// ItemResult is the result of a check item, it is saved as a row of t_hc_item_result
+type ItemResult struct {
+	ID             int       `middleware:"id" json:"id"`
+	OperationID    int       `middleware:"operation_id" json:"operation_id"`
+	ItemName       string    `middleware:"item_name" json:"item_name"`
+	DataSource     string    `middleware:"data_source" json:"data_source"`
+	ItemWeight     int       `middleware:"item_weight" json:"item_weight"`
+	Score          int       `middleware:"score" json:"score"`
+	Status         int       `middleware:"status" json:"status"`
+	Message        string    `middleware:"message" json:"message"`
+	Data           string    `middleware:"data" json:"data"`
+	High           string    `middleware:"high" json:"high"`
+	Advice         string    `middleware:"advice" json:"advice"`
+	DelFlag        int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime     time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewItemResult returns a new *ItemResult
+func NewItemResult(operationID int, itemName, dataSource string, itemWeight, score int, data, high, advice string) *ItemResult {
+	return &ItemResult{
+		OperationID: operationID,
+		ItemName:    itemName,
+		DataSource:  dataSource,
+		ItemWeight:  itemWeight,
+		Score:       score,
+		Data:        data,
+		High:        high,
+		Advice:      advice,
+	}
+}
+
+// NewItemResultWithEnv returns a new *ItemResult of given item,
+// the operation id and the item weight are taken from the check env
+func NewItemResultWithEnv(env healthcheck.CheckEnv, item healthcheck.CheckItem, score int, data, high, advice string) *ItemResult {
+	return NewItemResult(
+		env.GetOperationInfo().GetOperationID(),
+		item.GetName(),
+		item.GetDataSource(),
+		env.GetItemConfig(item.GetName()).GetItemWeight(),
+		score,
+		data,
+		high,
+		advice,
+	)
+}
+
+// NewSkippedItemResultWithEnv returns a new *ItemResult of given item which was skipped,
+// the skipped item will not be scored
+func NewSkippedItemResultWithEnv(env healthcheck.CheckEnv, item healthcheck.CheckItem, reason string) *ItemResult {
+	itemResult := NewItemResultWithEnv(env, item, constant.ZeroInt, constant.EmptyString, constant.EmptyString, constant.EmptyString)
+	itemResult.Status = ItemStatusSkipped
+	itemResult.Message = reason
+
+	return itemResult
+}
+
+// NewErrorItemResultWithEnv returns a new *ItemResult of given item which failed with given error,
+// the failed item will not be scored
+func NewErrorItemResultWithEnv(env healthcheck.CheckEnv, item healthcheck.CheckItem, err error) *ItemResult {
+	itemResult := NewItemResultWithEnv(env, item, constant.ZeroInt, constant.EmptyString, constant.EmptyString, constant.EmptyString)
+	itemResult.Status = ItemStatusError
+	itemResult.Message = err.Error()
+
+	return itemResult
+}
+
+// NewEmptyItemResult returns an empty *ItemResult
+func NewEmptyItemResult() *ItemResult {
+	return &ItemResult{}
+}
+
+// Identity returns the identity
+func (ir *ItemResult) Identity() int {
+	return ir.ID
+}
+
+// GetOperationID returns the operation id
+func (ir *ItemResult) GetOperationID() int {
+	return ir.OperationID
+}
+
+// GetItemName returns the item name
+func (ir *ItemResult) GetItemName() string {
+	return ir.ItemName
+}
+
+// GetDataSource returns the data source of the item
+func (ir *ItemResult) GetDataSource() string {
+	return ir.DataSource
+}
+
+// GetItemWeight returns the item weight that was used when checking
+func (ir *ItemResult) GetItemWeight() int {
+	return ir.ItemWeight
+}
+
+// GetScore returns the score of the item
+func (ir *ItemResult) GetScore() int {
+	return ir.Score
+}
+
+// GetStatus returns the status of the item, 0-ok, 1-skipped, 2-error
+func (ir *ItemResult) GetStatus() int {
+	return ir.Status
+}
+
+// GetMessage returns the reason why the item was skipped or failed
+func (ir *ItemResult) GetMessage() string {
+	return ir.Message
+}
+
+// IsOK returns if the item was checked successfully
+func (ir *ItemResult) IsOK() bool {
+	return ir.Status == ItemStatusOK
+}
+
+// GetData returns the data of the item
+func (ir *ItemResult) GetData() string {
+	return ir.Data
+}
+
+// GetHigh returns the abnormal data of the item
+func (ir *ItemResult) GetHigh() string {
+	return ir.High
+}
+
+// GetAdvice returns the advice of the item
+func (ir *ItemResult) GetAdvice() string {
+	return ir.Advice
+}
+
+// GetDelFlag returns the delete flag
+func (ir *ItemResult) GetDelFlag() int {
+	return ir.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (ir *ItemResult) GetCreateTime() time.Time {
+	return ir.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (ir *ItemResult) GetLastUpdateTime() time.Time {
+	return ir.LastUpdateTime
+}
+
+// MarshalJSON marshals ItemResult to json string
+func (ir *ItemResult) MarshalJSON() ([]byte, error) {
+	return common.MarshalStructWithTag(ir, constant.DefaultMarshalTag)
+}
+
+// MarshalJSONWithFields marshals only specified field of the ItemResult to json string
+func (ir *ItemResult) MarshalJSONWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(ir, fields...)
+}
@@ -0,0 +1,150 @@
+package healthcheck
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testItemResultItemWeight = 5
+	testItemResultScore      = 80
+	testItemResultData       = `[{"timestamp":"2021-01-01 00:00:00","value":0.9}]`
+	testItemResultHigh       = `[{"timestamp":"2021-01-01 00:00:00","value":0.9}]`
+	testItemConfigName       = "test_item"
+
+	testItemResultErrorMessage = "test error"
+)
+
+func TestItem_All(t *testing.T) {
+	TestItem_GetCheckItem(t)
+	TestItem_RegisterCheckItem(t)
+	TestItem_calculateScore(t)
+	TestItem_GetItemNames(t)
+	TestItem_addItemResult(t)
+	TestItem_ItemStatus(t)
+	TestItem_summarize(t)
+}
+
+func TestItem_GetCheckItem(t *testing.T) {
+	asst := assert.New(t)
+
+	for itemName := range resultItemFields {
+		item, ok := GetCheckItem(itemName)
+		asst.True(ok, "test GetCheckItem() failed")
+		asst.Equal(itemName, item.GetName(), "test GetCheckItem() failed")
+	}
+	_, ok := GetCheckItem(testItemConfigName)
+	asst.False(ok, "test GetCheckItem() failed")
+}
+
+func TestItem_RegisterCheckItem(t *testing.T) {
+	asst := assert.New(t)
+
+	asst.Panics(func() { RegisterCheckItem(NewDBConfigItem()) }, "test RegisterCheckItem() failed")
+	asst.Panics(func() { RegisterCheckItem(nil) }, "test RegisterCheckItem() failed")
+}
+
+func TestItem_calculateScore(t *testing.T) {
+	asst := assert.New(t)
+
+	cfg := NewDefaultItemConfig(defaultCPUUsageItemName, 5, 0.5, 0.8, 0.1, 20, 100, 10, 50)
+	// no data exceeds the watermarks
+	asst.Equal(int(defaultMaxScore), calculateScore(cfg, 0, 0, 0, 0), "test calculateScore() failed")
+	// average high value is 0.9, deduct (0.9 - 0.8) / 0.1 * 20 = 20
+	asst.Equal(80, calculateScore(cfg, 1.8, 2, 0, 0), "test calculateScore() failed")
+	// the score should not be less than the min score
+	asst.Equal(defaultMinScore, calculateScore(cfg, 100, 1, 100, 1), "test calculateScore() failed")
+}
+
+func TestItem_GetItemNames(t *testing.T) {
+	asst := assert.New(t)
+
+	engineConfig := NewEmptyDefaultEngineConfig()
+	engineConfig.SetItemConfig(defaultTableSizeItemName, &DefaultItemConfig{ID: 3, ItemName: defaultTableSizeItemName})
+	engineConfig.SetItemConfig(defaultDBConfigItemName, &DefaultItemConfig{ID: 1, ItemName: defaultDBConfigItemName})
+	engineConfig.SetItemConfig(defaultCPUUsageItemName, &DefaultItemConfig{ID: 2, ItemName: defaultCPUUsageItemName})
+
+	asst.Equal([]string{defaultDBConfigItemName, defaultCPUUsageItemName, defaultTableSizeItemName}, engineConfig.GetItemNames(), "test GetItemNames() failed")
+	// unregistered item
+	engineConfig.SetItemConfig(testItemConfigName, &DefaultItemConfig{ID: 4, ItemName: testItemConfigName})
+	err := engineConfig.Validate()
+	asst.NotNil(err, "test GetItemNames() failed")
+}
+
+func TestItem_addItemResult(t *testing.T) {
+	asst := assert.New(t)
+
+	result := NewEmptyResult()
+	// built-in item
+	err := result.addItemResult(NewItemResult(constant.ZeroInt, defaultCPUUsageItemName, DataSourcePrometheus, testItemResultItemWeight,
+		testItemResultScore, testItemResultData, testItemResultHigh, constant.EmptyString))
+	asst.Nil(err, common.CombineMessageWithError("test addItemResult() failed", err))
+	asst.Equal(testItemResultScore, result.GetCPUUsageScore(), "test addItemResult() failed")
+	asst.Equal(testItemResultHigh, result.GetCPUUsageHigh(), "test addItemResult() failed")
+	// item which does not have the relevant fields in the result
+	err = result.addItemResult(NewItemResult(constant.ZeroInt, testItemConfigName, DataSourcePrometheus, testItemResultItemWeight,
+		testItemResultScore, testItemResultData, testItemResultHigh, constant.EmptyString))
+	asst.Nil(err, common.CombineMessageWithError("test addItemResult() failed", err))
+	asst.Equal(2, len(result.GetItemResults()), "test addItemResult() failed")
+	// marshal
+	jsonBytes, err := result.MarshalJSON()
+	asst.Nil(err, common.CombineMessageWithError("test addItemResult() failed", err))
+	asst.Contains(string(jsonBytes), testItemConfigName, "test addItemResult() failed")
+
+	var _ healthcheck.ItemResult = result.GetItemResults()[constant.ZeroInt]
+}
+
+func TestItem_ItemStatus(t *testing.T) {
+	asst := assert.New(t)
+
+	engine := &DefaultEngine{operationInfo: testOperationInfo, engineConfig: NewEmptyDefaultEngineConfig()}
+	engine.engineConfig.SetItemConfig(defaultReplicationLagItemName, &DefaultItemConfig{ItemName: defaultReplicationLagItemName, ItemWeight: testItemResultItemWeight})
+	engine.engineConfig.SetItemConfig(defaultCPUUsageItemName, &DefaultItemConfig{ItemName: defaultCPUUsageItemName, ItemWeight: testItemResultItemWeight})
+
+	skipped := NewSkippedItemResultWithEnv(engine, NewReplicationLagItem(), replicationNotReplicaMessage)
+	asst.Equal(ItemStatusSkipped, skipped.GetStatus(), "test NewSkippedItemResultWithEnv() failed")
+	asst.Equal(replicationNotReplicaMessage, skipped.GetMessage(), "test NewSkippedItemResultWithEnv() failed")
+	asst.False(skipped.IsOK(), "test NewSkippedItemResultWithEnv() failed")
+
+	cpuItem, _ := GetCheckItem(defaultCPUUsageItemName)
+	failed := NewErrorItemResultWithEnv(engine, cpuItem, errors.New(testItemResultErrorMessage))
+	asst.Equal(ItemStatusError, failed.GetStatus(), "test NewErrorItemResultWithEnv() failed")
+	asst.Equal(testItemResultErrorMessage, failed.GetMessage(), "test NewErrorItemResultWithEnv() failed")
+	asst.Equal(testItemResultItemWeight, failed.GetItemWeight(), "test NewErrorItemResultWithEnv() failed")
+
+	result := NewEmptyResult()
+	err := result.addItemResult(skipped)
+	asst.Nil(err, common.CombineMessageWithError("test getErrorItemNames() failed", err))
+	err = result.addItemResult(failed)
+	asst.Nil(err, common.CombineMessageWithError("test getErrorItemNames() failed", err))
+	asst.Equal([]string{defaultCPUUsageItemName}, result.getErrorItemNames(), "test getErrorItemNames() failed")
+	// the legacy score column of the failed built-in item is marked as not checked
+	asst.Equal(ResultScoreNotChecked, result.GetCPUUsageScore(), "test addItemResult() failed")
+	asst.Equal(constant.EmptyString, result.GetCPUUsageData(), "test addItemResult() failed")
+}
+
+func TestItem_summarize(t *testing.T) {
+	asst := assert.New(t)
+
+	engine := &DefaultEngine{operationInfo: testOperationInfo, result: NewEmptyResult()}
+	// all the items failed
+	err := engine.result.addItemResult(&ItemResult{ItemName: defaultCPUUsageItemName, ItemWeight: 50, Status: ItemStatusError})
+	asst.Nil(err, common.CombineMessageWithError("test summarize() failed", err))
+	err = engine.summarize()
+	asst.NotNil(err, "test summarize() failed")
+	// the weights of the ok items are re-normalized, (80 * 20 + 50 * 30) / (20 + 30) = 62
+	err = engine.result.addItemResult(&ItemResult{ItemName: defaultIOUtilItemName, ItemWeight: 20, Score: 80, Status: ItemStatusOK})
+	asst.Nil(err, common.CombineMessageWithError("test summarize() failed", err))
+	err = engine.result.addItemResult(&ItemResult{ItemName: defaultTableRowsItemName, ItemWeight: 30, Score: 50, Status: ItemStatusOK})
+	asst.Nil(err, common.CombineMessageWithError("test summarize() failed", err))
+	err = engine.result.addItemResult(&ItemResult{ItemName: defaultReplicationLagItemName, ItemWeight: 10, Status: ItemStatusSkipped})
+	asst.Nil(err, common.CombineMessageWithError("test summarize() failed", err))
+	err = engine.summarize()
+	asst.Nil(err, common.CombineMessageWithError("test summarize() failed", err))
+	asst.Equal(62, engine.result.GetWeightedAverageScore(), "test summarize() failed")
+}
@@ -0,0 +1,274 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	middlewareRoleRW = 1
+	middlewareRoleRO = 2
+
+	defaultMiddlewareProbeTimeout = 3 * time.Second
+	// middlewareUsageNotAvailable is the usage of the middleware server of which the metrics are not available
+	middlewareUsageNotAvailable = -1
+
+	middlewareServerDownDeduction      = 20
+	middlewareEndpointMissingDeduction = 50
+	middlewareResourceUsageDeduction   = 10
+	middlewareResourceUsageHigh        = 0.8
+)
+
+// MiddlewareEngine probes all the middleware servers of a middleware cluster,
+// and checks if the mysql clusters which are linked to the middleware cluster have alive endpoints
+type MiddlewareEngine struct {
+	ctx                   context.Context
+	middlewareOperationID int
+	middlewareClusterID   int
+	middlewareServers     []depmeta.MiddlewareServer
+	mysqlClusters         []depmeta.MySQLCluster
+	dasRepo               healthcheck.DASRepo
+	prometheusRepo        healthcheck.MiddlewarePrometheusRepo
+	probeTimeout          time.Duration
+}
+
+// NewMiddlewareEngine returns a new *MiddlewareEngine, prometheusRepo could be nil if the monitor system is not available,
+// the resource usages will not be checked in that case, the engine stops checking the remaining servers when ctx is done
+func NewMiddlewareEngine(ctx context.Context, middlewareOperationID, middlewareClusterID int, middlewareServers []depmeta.MiddlewareServer,
+	mysqlClusters []depmeta.MySQLCluster, dasRepo healthcheck.DASRepo, prometheusRepo healthcheck.MiddlewarePrometheusRepo) *MiddlewareEngine {
+	return &MiddlewareEngine{
+		ctx:                   ctx,
+		middlewareOperationID: middlewareOperationID,
+		middlewareClusterID:   middlewareClusterID,
+		middlewareServers:     middlewareServers,
+		mysqlClusters:         mysqlClusters,
+		dasRepo:               dasRepo,
+		prometheusRepo:        prometheusRepo,
+		probeTimeout:          defaultMiddlewareProbeTimeout,
+	}
+}
+
+// getDASRepo returns the das repository
+func (me *MiddlewareEngine) getDASRepo() healthcheck.DASRepo {
+	return me.dasRepo
+}
+
+// getPrometheusRepo returns the prometheus repository of the middleware servers
+func (me *MiddlewareEngine) getPrometheusRepo() healthcheck.MiddlewarePrometheusRepo {
+	return me.prometheusRepo
+}
+
+// Run checks the middleware servers and the endpoints, and then saves the middleware result
+func (me *MiddlewareEngine) Run() {
+	// summarize
+	msg, err := me.summarize()
+	if err != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckMiddlewareEngineRun, err, me.middlewareOperationID))
+		msg = err.Error()
+		if me.ctx.Err() == context.DeadlineExceeded {
+			msg = fmt.Sprintf("middleware healthcheck timed out. middleware_operation_id: %d, timeout: %ds",
+				me.middlewareOperationID, viper.GetInt(config.HealthcheckTimeoutKey))
+		}
+		// update status
+		updateErr := me.getDASRepo().UpdateMiddlewareOperationStatus(me.middlewareOperationID, defaultFailedStatus, msg)
+		if updateErr != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+		}
+		return
+	}
+
+	// update middleware operation status
+	updateErr := me.getDASRepo().UpdateMiddlewareOperationStatus(me.middlewareOperationID, defaultSuccessStatus, msg)
+	if updateErr != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+	}
+}
+
+// summarize checks the middleware servers and the endpoints, and saves the middleware result to the middleware,
+// it returns the message of the middleware operation
+func (me *MiddlewareEngine) summarize() (string, error) {
+	serverResults := make([]*MiddlewareServerResult, len(me.middlewareServers))
+	for i, middlewareServer := range me.middlewareServers {
+		// stop checking if the operation timed out
+		err := me.ctx.Err()
+		if err != nil {
+			return constant.EmptyString, errors.Trace(err)
+		}
+		serverResults[i] = me.checkServer(middlewareServer)
+	}
+	endpointResults := make([]*MiddlewareEndpointResult, len(me.mysqlClusters))
+	for i, mysqlCluster := range me.mysqlClusters {
+		endpointResults[i] = getEndpointResult(mysqlCluster, serverResults)
+	}
+
+	serverResultBytes, err := json.Marshal(serverResults)
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+	endpointResultBytes, err := json.Marshal(endpointResults)
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+
+	score := getMiddlewareScore(serverResults, endpointResults)
+	middlewareResult := NewMiddlewareResult(me.middlewareOperationID, me.middlewareClusterID, score, string(serverResultBytes), string(endpointResultBytes))
+	err = me.getDASRepo().SaveMiddlewareResult(middlewareResult)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	var deadCount, failedEndpointCount int
+	for _, serverResult := range serverResults {
+		if !serverResult.Alive {
+			deadCount++
+		}
+	}
+	for _, endpointResult := range endpointResults {
+		if !endpointResult.OK {
+			failedEndpointCount++
+		}
+	}
+	msg := fmt.Sprintf("middleware healthcheck completed. middleware_operation_id: %d, total servers: %d, dead servers: %d, total mysql clusters: %d, mysql clusters without alive endpoints: %d",
+		me.middlewareOperationID, len(serverResults), deadCount, len(endpointResults), failedEndpointCount)
+	if me.getPrometheusRepo() == nil {
+		msg += ". resource usages were not checked because the monitor system is not available"
+	}
+
+	return msg, nil
+}
+
+// checkServer probes the middleware server and gets its resource usages
+func (me *MiddlewareEngine) checkServer(middlewareServer depmeta.MiddlewareServer) *MiddlewareServerResult {
+	serverResult := NewMiddlewareServerResult(middlewareServer.Identity(), middlewareServer.GetServerName(),
+		middlewareServer.GetMiddlewareRole(), middlewareServer.GetHostIP(), middlewareServer.GetPortNum())
+
+	var messages []string
+	latency, err := probeMiddlewareServer(me.ctx, middlewareServer.GetHostIP(), middlewareServer.GetPortNum(), me.probeTimeout)
+	if err != nil {
+		messages = append(messages, err.Error())
+	} else {
+		serverResult.Alive = true
+		serverResult.Latency = latency.Milliseconds()
+	}
+
+	if me.getPrometheusRepo() != nil {
+		cpuUsages, err := me.getPrometheusRepo().GetCPUUsage(middlewareServer.GetServerName())
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("get cpu usage failed. error: %s", err.Error()))
+		} else if len(cpuUsages) > constant.ZeroInt {
+			serverResult.CPUUsage = getMaxPrometheusValue(cpuUsages)
+		}
+		memoryUsages, err := me.getPrometheusRepo().GetMemoryUsage(middlewareServer.GetServerName())
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("get memory usage failed. error: %s", err.Error()))
+		} else if len(memoryUsages) > constant.ZeroInt {
+			serverResult.MemoryUsage = getMaxPrometheusValue(memoryUsages)
+		}
+	}
+	serverResult.Message = strings.Join(messages, constant.CommaString)
+
+	return serverResult
+}
+
+// probeMiddlewareServer connects to the host and port of the middleware server, and returns the latency of the connection,
+// the connection stops when ctx is done or it times out
+func probeMiddlewareServer(ctx context.Context, hostIP string, portNum int, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(hostIP, strconv.Itoa(portNum)))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	latency := time.Since(start)
+
+	err = conn.Close()
+	if err != nil {
+		log.Warnf("healthcheck probeMiddlewareServer(): close connection failed. host_ip: %s, port_num: %d, error: %s", hostIP, portNum, err.Error())
+	}
+
+	return latency, nil
+}
+
+// getMaxPrometheusValue returns the max value of the prometheus data
+func getMaxPrometheusValue(datas []healthcheck.PrometheusData) float64 {
+	var maxValue float64
+	for _, data := range datas {
+		if data.GetValue() > maxValue {
+			maxValue = data.GetValue()
+		}
+	}
+
+	return maxValue
+}
+
+// getEndpointResult counts the alive read-write and read-only middleware servers of the mysql cluster,
+// the mysql cluster is ok only if both of them are at least one
+func getEndpointResult(mysqlCluster depmeta.MySQLCluster, serverResults []*MiddlewareServerResult) *MiddlewareEndpointResult {
+	endpointResult := NewMiddlewareEndpointResult(mysqlCluster.Identity(), mysqlCluster.GetClusterName())
+	for _, serverResult := range serverResults {
+		if !serverResult.Alive {
+			continue
+		}
+		switch serverResult.MiddlewareRole {
+		case middlewareRoleRW:
+			endpointResult.RWAlive++
+		case middlewareRoleRO:
+			endpointResult.ROAlive++
+		}
+	}
+
+	var messages []string
+	if endpointResult.RWAlive == constant.ZeroInt {
+		messages = append(messages, "no alive read-write endpoint")
+	}
+	if endpointResult.ROAlive == constant.ZeroInt {
+		messages = append(messages, "no alive read-only endpoint")
+	}
+	endpointResult.OK = len(messages) == constant.ZeroInt
+	endpointResult.Message = strings.Join(messages, constant.CommaString)
+
+	return endpointResult
+}
+
+// getMiddlewareScore deducts the score of the middleware cluster with the dead servers, the missing endpoints and the high resource usages,
+// the score will not be less than 0
+func getMiddlewareScore(serverResults []*MiddlewareServerResult, endpointResults []*MiddlewareEndpointResult) int {
+	score := int(defaultMaxScore)
+	for _, serverResult := range serverResults {
+		if !serverResult.Alive {
+			score -= middlewareServerDownDeduction
+			continue
+		}
+		if serverResult.CPUUsage >= middlewareResourceUsageHigh || serverResult.MemoryUsage >= middlewareResourceUsageHigh {
+			score -= middlewareResourceUsageDeduction
+		}
+	}
+	for _, endpointResult := range endpointResults {
+		if endpointResult.RWAlive == constant.ZeroInt {
+			score -= middlewareEndpointMissingDeduction
+		}
+		if endpointResult.ROAlive == constant.ZeroInt {
+			score -= middlewareEndpointMissingDeduction
+		}
+	}
+	if score < constant.ZeroInt {
+		return constant.ZeroInt
+	}
+
+	return score
+}
@@ -0,0 +1,138 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testMiddlewareClusterID   = 1
+	testMiddlewareOperationID = 1
+	testMiddlewareResultScore = 70
+	testMiddlewareServerName  = "proxy01"
+	testMiddlewareHostIP      = "127.0.0.1"
+	testMiddlewareProbeTime   = time.Second
+)
+
+var testMiddlewareResult = NewMiddlewareResult(testMiddlewareOperationID, testMiddlewareClusterID, testMiddlewareResultScore,
+	`[{"middleware_server_id":1,"server_name":"proxy01","middleware_role":1,"host_ip":"192.168.137.21","port_num":33061,"alive":true,"latency":1,"cpu_usage":0.35,"memory_usage":0.52,"message":""}]`,
+	constant.EmptyString)
+
+func TestMiddlewareEngine_All(t *testing.T) {
+	TestMiddlewareEngine_probeMiddlewareServer(t)
+	TestMiddlewareEngine_checkServer(t)
+	TestMiddlewareEngine_getEndpointResult(t)
+	TestMiddlewareEngine_getMiddlewareScore(t)
+	TestMiddlewareResult_MarshalJSON(t)
+}
+
+// testListen listens on a random local port, and returns the listener and the port
+func testListen() (net.Listener, int, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(testMiddlewareHostIP, strconv.Itoa(constant.ZeroInt)))
+	if err != nil {
+		return nil, constant.ZeroInt, err
+	}
+
+	return listener, listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+func TestMiddlewareEngine_probeMiddlewareServer(t *testing.T) {
+	asst := assert.New(t)
+
+	listener, port, err := testListen()
+	asst.Nil(err, common.CombineMessageWithError("test probeMiddlewareServer() failed", err))
+	_, err = probeMiddlewareServer(context.Background(), testMiddlewareHostIP, port, testMiddlewareProbeTime)
+	asst.Nil(err, common.CombineMessageWithError("test probeMiddlewareServer() failed", err))
+	// the operation timed out
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = probeMiddlewareServer(ctx, testMiddlewareHostIP, port, testMiddlewareProbeTime)
+	asst.NotNil(err, "test probeMiddlewareServer() failed")
+	// the port is closed
+	err = listener.Close()
+	asst.Nil(err, common.CombineMessageWithError("test probeMiddlewareServer() failed", err))
+	_, err = probeMiddlewareServer(context.Background(), testMiddlewareHostIP, port, testMiddlewareProbeTime)
+	asst.NotNil(err, "test probeMiddlewareServer() failed")
+}
+
+func TestMiddlewareEngine_checkServer(t *testing.T) {
+	asst := assert.New(t)
+
+	listener, port, err := testListen()
+	asst.Nil(err, common.CombineMessageWithError("test checkServer() failed", err))
+	defer func() { _ = listener.Close() }()
+
+	middlewareServer := &metadata.MiddlewareServerInfo{
+		ID:             1,
+		ClusterID:      testMiddlewareClusterID,
+		ServerName:     testMiddlewareServerName,
+		MiddlewareRole: middlewareRoleRW,
+		HostIP:         testMiddlewareHostIP,
+		PortNum:        port,
+	}
+	// the resource usages are not checked without the prometheus repository
+	me := NewMiddlewareEngine(context.Background(), testMiddlewareOperationID, testMiddlewareClusterID, nil, nil, nil, nil)
+	serverResult := me.checkServer(middlewareServer)
+	asst.True(serverResult.Alive, "test checkServer() failed")
+	asst.Equal(float64(middlewareUsageNotAvailable), serverResult.CPUUsage, "test checkServer() failed")
+	asst.Equal(constant.EmptyString, serverResult.Message, "test checkServer() failed")
+}
+
+func TestMiddlewareEngine_getEndpointResult(t *testing.T) {
+	asst := assert.New(t)
+
+	mysqlCluster := &metadata.MySQLClusterInfo{ID: testHealthcheckMySQLClusterID, MiddlewareClusterID: testMiddlewareClusterID}
+	serverResults := []*MiddlewareServerResult{
+		{MiddlewareRole: middlewareRoleRW, Alive: true},
+		{MiddlewareRole: middlewareRoleRO, Alive: false},
+	}
+	endpointResult := getEndpointResult(mysqlCluster, serverResults)
+	asst.Equal(1, endpointResult.RWAlive, "test getEndpointResult() failed")
+	asst.Equal(constant.ZeroInt, endpointResult.ROAlive, "test getEndpointResult() failed")
+	asst.False(endpointResult.OK, "test getEndpointResult() failed")
+	// the read-only server is alive again
+	serverResults[1].Alive = true
+	endpointResult = getEndpointResult(mysqlCluster, serverResults)
+	asst.True(endpointResult.OK, "test getEndpointResult() failed")
+}
+
+func TestMiddlewareEngine_getMiddlewareScore(t *testing.T) {
+	asst := assert.New(t)
+
+	serverResults := []*MiddlewareServerResult{
+		{MiddlewareRole: middlewareRoleRW, Alive: true, CPUUsage: 0.9, MemoryUsage: 0.5},
+		{MiddlewareRole: middlewareRoleRO, Alive: false},
+	}
+	endpointResults := []*MiddlewareEndpointResult{{RWAlive: 1}}
+	// 100 - 10(high cpu usage) - 20(dead server) - 50(no read-only endpoint)
+	asst.Equal(20, getMiddlewareScore(serverResults, endpointResults), "test getMiddlewareScore() failed")
+	// the score is not less than 0
+	endpointResults = append(endpointResults, &MiddlewareEndpointResult{})
+	asst.Equal(constant.ZeroInt, getMiddlewareScore(serverResults, endpointResults), "test getMiddlewareScore() failed")
+}
+
+func TestMiddlewareResult_MarshalJSON(t *testing.T) {
+	asst := assert.New(t)
+
+	jsonBytes, err := testMiddlewareResult.MarshalJSON()
+	asst.Nil(err, common.CombineMessageWithError("test MarshalJSON() failed", err))
+	var m map[string]interface{}
+	err = json.Unmarshal(jsonBytes, &m)
+	asst.Nil(err, common.CombineMessageWithError("test MarshalJSON() failed", err))
+	serverResult, ok := m["server_result"].([]interface{})
+	asst.True(ok, "test MarshalJSON() failed")
+	asst.Equal(1, len(serverResult), "test MarshalJSON() failed")
+	// the empty endpoint result is marshaled as an empty json array
+	endpointResult, ok := m["endpoint_result"].([]interface{})
+	asst.True(ok, "test MarshalJSON() failed")
+	asst.Equal(constant.ZeroInt, len(endpointResult), "test MarshalJSON() failed")
+}
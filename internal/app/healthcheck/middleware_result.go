@@ -0,0 +1,168 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+var _ healthcheck.MiddlewareResult = (*MiddlewareResult)(nil)
+
+// MiddlewareResult is the combined result of all the middleware servers of a middleware cluster
+type MiddlewareResult struct {
+	ID                    int       `middleware:"id" json:"id"`
+	MiddlewareOperationID int       `middleware:"middleware_operation_id" json:"middleware_operation_id"`
+	MiddlewareClusterID   int       `middleware:"middleware_cluster_id" json:"middleware_cluster_id"`
+	Score                 int       `middleware:"score" json:"score"`
+	ServerResult          string    `middleware:"server_result" json:"server_result"`
+	EndpointResult        string    `middleware:"endpoint_result" json:"endpoint_result"`
+	DelFlag               int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime            time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime        time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewMiddlewareResult returns a new *MiddlewareResult
+func NewMiddlewareResult(middlewareOperationID, middlewareClusterID, score int, serverResult, endpointResult string) *MiddlewareResult {
+	return &MiddlewareResult{
+		MiddlewareOperationID: middlewareOperationID,
+		MiddlewareClusterID:   middlewareClusterID,
+		Score:                 score,
+		ServerResult:          serverResult,
+		EndpointResult:        endpointResult,
+	}
+}
+
+// NewEmptyMiddlewareResult returns an empty *MiddlewareResult
+func NewEmptyMiddlewareResult() *MiddlewareResult {
+	return &MiddlewareResult{}
+}
+
+// Identity returns the identity
+func (mr *MiddlewareResult) Identity() int {
+	return mr.ID
+}
+
+// GetMiddlewareOperationID returns the middleware operation id
+func (mr *MiddlewareResult) GetMiddlewareOperationID() int {
+	return mr.MiddlewareOperationID
+}
+
+// GetMiddlewareClusterID returns the middleware cluster id
+func (mr *MiddlewareResult) GetMiddlewareClusterID() int {
+	return mr.MiddlewareClusterID
+}
+
+// GetScore returns the score of the middleware cluster
+func (mr *MiddlewareResult) GetScore() int {
+	return mr.Score
+}
+
+// GetServerResult returns the liveness and the resource usages of each middleware server in json string
+func (mr *MiddlewareResult) GetServerResult() string {
+	return mr.ServerResult
+}
+
+// GetEndpointResult returns the alive endpoints of each linked mysql cluster in json string
+func (mr *MiddlewareResult) GetEndpointResult() string {
+	return mr.EndpointResult
+}
+
+// GetDelFlag returns the delete flag
+func (mr *MiddlewareResult) GetDelFlag() int {
+	return mr.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (mr *MiddlewareResult) GetCreateTime() time.Time {
+	return mr.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (mr *MiddlewareResult) GetLastUpdateTime() time.Time {
+	return mr.LastUpdateTime
+}
+
+// MarshalJSON marshals MiddlewareResult to json string,
+// server result and endpoint result are json strings, they will be marshaled as json arrays
+func (mr *MiddlewareResult) MarshalJSON() ([]byte, error) {
+	type middlewareResult MiddlewareResult
+
+	serverResult := mr.GetServerResult()
+	if serverResult == constant.EmptyString {
+		serverResult = defaultEmptyJSONArray
+	}
+	endpointResult := mr.GetEndpointResult()
+	if endpointResult == constant.EmptyString {
+		endpointResult = defaultEmptyJSONArray
+	}
+
+	jsonBytes, err := json.Marshal(&struct {
+		*middlewareResult
+		ServerResult   json.RawMessage `json:"server_result"`
+		EndpointResult json.RawMessage `json:"endpoint_result"`
+	}{
+		middlewareResult: (*middlewareResult)(mr),
+		ServerResult:     json.RawMessage(serverResult),
+		EndpointResult:   json.RawMessage(endpointResult),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return jsonBytes, nil
+}
+
+// MarshalJSONWithFields marshals only specified field of the MiddlewareResult to json string
+func (mr *MiddlewareResult) MarshalJSONWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(mr, fields...)
+}
+
+// MiddlewareServerResult is the liveness and the resource usages of a middleware server,
+// the usages are the max values in the check range, they are -1 if the metrics are not available
+type MiddlewareServerResult struct {
+	MiddlewareServerID int     `json:"middleware_server_id"`
+	ServerName         string  `json:"server_name"`
+	MiddlewareRole     int     `json:"middleware_role"`
+	HostIP             string  `json:"host_ip"`
+	PortNum            int     `json:"port_num"`
+	Alive              bool    `json:"alive"`
+	Latency            int64   `json:"latency"`
+	CPUUsage           float64 `json:"cpu_usage"`
+	MemoryUsage        float64 `json:"memory_usage"`
+	Message            string  `json:"message"`
+}
+
+// NewMiddlewareServerResult returns a new *MiddlewareServerResult
+func NewMiddlewareServerResult(middlewareServerID int, serverName string, middlewareRole int, hostIP string, portNum int) *MiddlewareServerResult {
+	return &MiddlewareServerResult{
+		MiddlewareServerID: middlewareServerID,
+		ServerName:         serverName,
+		MiddlewareRole:     middlewareRole,
+		HostIP:             hostIP,
+		PortNum:            portNum,
+		CPUUsage:           middlewareUsageNotAvailable,
+		MemoryUsage:        middlewareUsageNotAvailable,
+	}
+}
+
+// MiddlewareEndpointResult is the alive read-write and read-only endpoints of a mysql cluster which is linked to the middleware cluster
+type MiddlewareEndpointResult struct {
+	MySQLClusterID int    `json:"mysql_cluster_id"`
+	ClusterName    string `json:"cluster_name"`
+	RWAlive        int    `json:"rw_alive"`
+	ROAlive        int    `json:"ro_alive"`
+	OK             bool   `json:"ok"`
+	Message        string `json:"message"`
+}
+
+// NewMiddlewareEndpointResult returns a new *MiddlewareEndpointResult
+func NewMiddlewareEndpointResult(mysqlClusterID int, clusterName string) *MiddlewareEndpointResult {
+	return &MiddlewareEndpointResult{
+		MySQLClusterID: mysqlClusterID,
+		ClusterName:    clusterName,
+	}
+}
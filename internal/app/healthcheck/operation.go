@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+)
+
+// runningOperations stores the cancel functions of the operations which are running in this process
+var runningOperations = newOperationRegistry()
+
+// operationRegistry maps the operation id to the cancel function of the context of the operation
+type operationRegistry struct {
+	mutex   sync.Mutex
+	cancels map[int]context.CancelFunc
+}
+
+// newOperationRegistry returns a new *operationRegistry
+func newOperationRegistry() *operationRegistry {
+	return &operationRegistry{cancels: make(map[int]context.CancelFunc)}
+}
+
+// register registers the cancel function of given operation
+func (or *operationRegistry) register(operationID int, cancel context.CancelFunc) {
+	or.mutex.Lock()
+	defer or.mutex.Unlock()
+
+	or.cancels[operationID] = cancel
+}
+
+// deregister removes given operation from the registry
+func (or *operationRegistry) deregister(operationID int) {
+	or.mutex.Lock()
+	defer or.mutex.Unlock()
+
+	delete(or.cancels, operationID)
+}
+
+// cancel cancels the context of given operation, it returns false if the operation is not running in this process
+func (or *operationRegistry) cancel(operationID int) bool {
+	or.mutex.Lock()
+	defer or.mutex.Unlock()
+
+	cancel, ok := or.cancels[operationID]
+	if !ok {
+		return false
+	}
+	cancel()
+
+	return true
+}
+
+// release cancels the context of given operation and removes it from the registry,
+// it is used when the engine of the operation will not run
+func (or *operationRegistry) release(operationID int) {
+	or.mutex.Lock()
+	defer or.mutex.Unlock()
+
+	cancel, ok := or.cancels[operationID]
+	if !ok {
+		return
+	}
+	cancel()
+	delete(or.cancels, operationID)
+}
+
+// runWithContext runs f and waits until f returns, if the context is done before f returns,
+// interrupt will be called to stop f, e.g. killing the running query, and then it still waits until f returns,
+// so that the connection used by f could be closed safely after it returns.
+// interrupt could be nil if f could not be interrupted, in this case, the error of the context is returned at once,
+// and f keeps running in the background until the data source times out the request, its result is discarded,
+// so f must not write anything which is read after runWithContext returns an error
+func runWithContext(ctx context.Context, f func() error, interrupt func()) error {
+	err := ctx.Err()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- f()
+	}()
+
+	select {
+	case err = <-errChan:
+		return err
+	case <-ctx.Done():
+		if interrupt == nil {
+			// f could not be interrupted, do not wait for it, errChan is buffered so that f will not block when it returns
+			return errors.Trace(ctx.Err())
+		}
+		interrupt()
+		// wait for f, the error of f is meaningless as it was interrupted
+		<-errChan
+		return errors.Trace(ctx.Err())
+	}
+}
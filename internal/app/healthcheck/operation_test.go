@@ -0,0 +1,77 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOperationRegistryOperationID = 1
+	testRunWithContextTimeout        = 100 * time.Millisecond
+)
+
+func TestOperation_All(t *testing.T) {
+	TestOperation_operationRegistry(t)
+	TestOperation_runWithContext(t)
+}
+
+func TestOperation_operationRegistry(t *testing.T) {
+	asst := assert.New(t)
+
+	registry := newOperationRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	registry.register(testOperationRegistryOperationID, cancel)
+	asst.True(registry.cancel(testOperationRegistryOperationID), "test operationRegistry failed")
+	asst.Equal(context.Canceled, ctx.Err(), "test operationRegistry failed")
+	// the operation is not running in this process
+	registry.deregister(testOperationRegistryOperationID)
+	asst.False(registry.cancel(testOperationRegistryOperationID), "test operationRegistry failed")
+	// release
+	ctx, cancel = context.WithCancel(context.Background())
+	registry.register(testOperationRegistryOperationID, cancel)
+	registry.release(testOperationRegistryOperationID)
+	asst.Equal(context.Canceled, ctx.Err(), "test operationRegistry failed")
+	asst.False(registry.cancel(testOperationRegistryOperationID), "test operationRegistry failed")
+}
+
+func TestOperation_runWithContext(t *testing.T) {
+	asst := assert.New(t)
+
+	// f returns before the context is done
+	err := runWithContext(context.Background(), func() error { return nil }, nil)
+	asst.Nil(err, common.CombineMessageWithError("test runWithContext() failed", err))
+	err = runWithContext(context.Background(), func() error { return errors.New(testItemResultErrorMessage) }, nil)
+	asst.EqualError(err, testItemResultErrorMessage, "test runWithContext() failed")
+	// f hangs until it is interrupted
+	ctx, cancel := context.WithTimeout(context.Background(), testRunWithContextTimeout)
+	defer cancel()
+	done := make(chan struct{})
+	returned := false
+	err = runWithContext(ctx, func() error {
+		<-done
+		returned = true
+		return nil
+	}, func() { close(done) })
+	asst.Equal(context.DeadlineExceeded, errors.Cause(err), "test runWithContext() failed")
+	asst.True(returned, "test runWithContext() failed")
+	// f hangs and could not be interrupted, it returns once the context is done without waiting for f
+	ctx, cancel = context.WithTimeout(context.Background(), testRunWithContextTimeout)
+	defer cancel()
+	hang := make(chan struct{})
+	defer close(hang)
+	err = runWithContext(ctx, func() error {
+		<-hang
+		return nil
+	}, nil)
+	asst.Equal(context.DeadlineExceeded, errors.Cause(err), "test runWithContext() failed")
+	// the context was cancelled before f runs
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = runWithContext(ctx, func() error { return nil }, nil)
+	asst.Equal(context.Canceled, errors.Cause(err), "test runWithContext() failed")
+}
@@ -0,0 +1,280 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	ProgressEventTypeItemStarted  = "item_started"
+	ProgressEventTypeItemFinished = "item_finished"
+	ProgressEventTypeCompleted    = "completed"
+
+	defaultProgressEventBufferSize = 64
+)
+
+var (
+	_ healthcheck.ProgressEvent = (*ProgressEvent)(nil)
+	_ healthcheck.Progress      = (*Progress)(nil)
+
+	// progressBroker dispatches the progress events of the operations which are running in this process
+	progressBroker = newProgressEventBroker()
+)
+
+// ProgressEvent is published by the engine when an item starts or finishes, and when the operation completes
+type ProgressEvent struct {
+	OperationID     int       `json:"operation_id"`
+	EventType       string    `json:"event_type"`
+	ItemName        string    `json:"item_name"`
+	ItemStatus      int       `json:"item_status"`
+	ItemScore       int       `json:"item_score"`
+	ItemElapsed     int       `json:"item_elapsed"`
+	FinishedItemNum int       `json:"finished_item_num"`
+	TotalItemNum    int       `json:"total_item_num"`
+	Percent         int       `json:"percent"`
+	Elapsed         int       `json:"elapsed"`
+	Status          int       `json:"status"`
+	EventTime       time.Time `json:"event_time"`
+}
+
+// newProgressEvent returns a new *ProgressEvent of the running operation
+func newProgressEvent(operationID int, eventType, itemName string, finishedItemNum, totalItemNum int, elapsed time.Duration) *ProgressEvent {
+	return &ProgressEvent{
+		OperationID:     operationID,
+		EventType:       eventType,
+		ItemName:        itemName,
+		FinishedItemNum: finishedItemNum,
+		TotalItemNum:    totalItemNum,
+		Percent:         calculatePercent(finishedItemNum, totalItemNum),
+		Elapsed:         int(elapsed.Milliseconds()),
+		Status:          defaultRunningStatus,
+		EventTime:       time.Now(),
+	}
+}
+
+// GetOperationID returns the operation id
+func (pe *ProgressEvent) GetOperationID() int {
+	return pe.OperationID
+}
+
+// GetEventType returns the event type, it is one of item_started, item_finished and completed
+func (pe *ProgressEvent) GetEventType() string {
+	return pe.EventType
+}
+
+// GetItemName returns the name of the item which the event is about, it is empty for the completed event
+func (pe *ProgressEvent) GetItemName() string {
+	return pe.ItemName
+}
+
+// GetItemStatus returns the status of the finished item, 0-ok, 1-skipped, 2-error
+func (pe *ProgressEvent) GetItemStatus() int {
+	return pe.ItemStatus
+}
+
+// GetItemScore returns the score of the finished item
+func (pe *ProgressEvent) GetItemScore() int {
+	return pe.ItemScore
+}
+
+// GetItemElapsed returns the elapsed time of the finished item, the unit is millisecond
+func (pe *ProgressEvent) GetItemElapsed() int {
+	return pe.ItemElapsed
+}
+
+// GetFinishedItemNum returns the number of the finished items
+func (pe *ProgressEvent) GetFinishedItemNum() int {
+	return pe.FinishedItemNum
+}
+
+// GetTotalItemNum returns the number of all the items
+func (pe *ProgressEvent) GetTotalItemNum() int {
+	return pe.TotalItemNum
+}
+
+// GetPercent returns the percentage of the finished items
+func (pe *ProgressEvent) GetPercent() int {
+	return pe.Percent
+}
+
+// GetElapsed returns the elapsed time of the operation, the unit is millisecond
+func (pe *ProgressEvent) GetElapsed() int {
+	return pe.Elapsed
+}
+
+// GetStatus returns the status of the operation
+func (pe *ProgressEvent) GetStatus() int {
+	return pe.Status
+}
+
+// GetEventTime returns the time when the event happened
+func (pe *ProgressEvent) GetEventTime() time.Time {
+	return pe.EventTime
+}
+
+// Progress is the latest progress of the operation, it is saved as a row of t_hc_operation_progress
+type Progress struct {
+	OperationID     int       `middleware:"operation_id" json:"operation_id"`
+	Status          int       `middleware:"status" json:"status"`
+	TotalItemNum    int       `middleware:"total_item_num" json:"total_item_num"`
+	FinishedItemNum int       `middleware:"finished_item_num" json:"finished_item_num"`
+	Percent         int       `json:"percent"`
+	CurrentItemName string    `middleware:"current_item_name" json:"current_item_name"`
+	Elapsed         int       `middleware:"elapsed" json:"elapsed"`
+	LastUpdateTime  time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewProgressWithEvent returns a new *Progress with the progress event
+func NewProgressWithEvent(event healthcheck.ProgressEvent) *Progress {
+	p := &Progress{
+		OperationID:     event.GetOperationID(),
+		Status:          event.GetStatus(),
+		TotalItemNum:    event.GetTotalItemNum(),
+		FinishedItemNum: event.GetFinishedItemNum(),
+		Elapsed:         event.GetElapsed(),
+		LastUpdateTime:  event.GetEventTime(),
+	}
+	if event.GetEventType() == ProgressEventTypeItemStarted {
+		p.CurrentItemName = event.GetItemName()
+	}
+	p.calculatePercent()
+
+	return p
+}
+
+// NewEmptyProgress returns an empty *Progress
+func NewEmptyProgress() *Progress {
+	return &Progress{}
+}
+
+// GetOperationID returns the operation id
+func (p *Progress) GetOperationID() int {
+	return p.OperationID
+}
+
+// GetStatus returns the status of the operation
+func (p *Progress) GetStatus() int {
+	return p.Status
+}
+
+// GetTotalItemNum returns the number of all the items, it is 0 if the operation has not started checking the items
+func (p *Progress) GetTotalItemNum() int {
+	return p.TotalItemNum
+}
+
+// GetFinishedItemNum returns the number of the finished items
+func (p *Progress) GetFinishedItemNum() int {
+	return p.FinishedItemNum
+}
+
+// GetPercent returns the percentage of the finished items
+func (p *Progress) GetPercent() int {
+	return p.Percent
+}
+
+// GetCurrentItemName returns the name of the item which is being checked
+func (p *Progress) GetCurrentItemName() string {
+	return p.CurrentItemName
+}
+
+// GetElapsed returns the elapsed time of the operation, the unit is millisecond
+func (p *Progress) GetElapsed() int {
+	return p.Elapsed
+}
+
+// GetLastUpdateTime returns the last update time
+func (p *Progress) GetLastUpdateTime() time.Time {
+	return p.LastUpdateTime
+}
+
+// IsFinished returns if the operation has stopped, no matter it succeeded or not
+func (p *Progress) IsFinished() bool {
+	switch p.GetStatus() {
+	case defaultSuccessStatus, defaultFailedStatus, defaultPartialStatus, defaultCancelledStatus:
+		return true
+	default:
+		return false
+	}
+}
+
+// calculatePercent calculates the percentage with the item numbers
+func (p *Progress) calculatePercent() {
+	p.Percent = calculatePercent(p.FinishedItemNum, p.TotalItemNum)
+}
+
+// calculatePercent returns the percentage of the finished items
+func calculatePercent(finishedItemNum, totalItemNum int) int {
+	if totalItemNum == constant.ZeroInt {
+		return constant.ZeroInt
+	}
+
+	return finishedItemNum * defaultHundred / totalItemNum
+}
+
+// progressEventBroker dispatches the progress events of the operations to the subscribers
+type progressEventBroker struct {
+	mutex       sync.Mutex
+	subscribers map[int]map[chan healthcheck.ProgressEvent]struct{}
+}
+
+// newProgressEventBroker returns a new *progressEventBroker
+func newProgressEventBroker() *progressEventBroker {
+	return &progressEventBroker{subscribers: make(map[int]map[chan healthcheck.ProgressEvent]struct{})}
+}
+
+// subscribe returns a channel which receives the progress events of given operation,
+// the channel will be closed after the operation completes, unsubscribe must be called when the events are not needed any more
+func (peb *progressEventBroker) subscribe(operationID int) (<-chan healthcheck.ProgressEvent, func()) {
+	peb.mutex.Lock()
+	defer peb.mutex.Unlock()
+
+	events := make(chan healthcheck.ProgressEvent, defaultProgressEventBufferSize)
+	if peb.subscribers[operationID] == nil {
+		peb.subscribers[operationID] = make(map[chan healthcheck.ProgressEvent]struct{})
+	}
+	peb.subscribers[operationID][events] = struct{}{}
+
+	unsubscribe := func() {
+		peb.mutex.Lock()
+		defer peb.mutex.Unlock()
+
+		_, ok := peb.subscribers[operationID][events]
+		if ok {
+			delete(peb.subscribers[operationID], events)
+			close(events)
+		}
+		if len(peb.subscribers[operationID]) == constant.ZeroInt {
+			delete(peb.subscribers, operationID)
+		}
+	}
+
+	return events, unsubscribe
+}
+
+// publish sends the event to all the subscribers of the operation,
+// the event is dropped for the subscriber which is too slow to receive it, so that the engine will never be blocked
+func (peb *progressEventBroker) publish(event healthcheck.ProgressEvent) {
+	peb.mutex.Lock()
+	defer peb.mutex.Unlock()
+
+	for events := range peb.subscribers[event.GetOperationID()] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// complete closes the channels of all the subscribers of the operation
+func (peb *progressEventBroker) complete(operationID int) {
+	peb.mutex.Lock()
+	defer peb.mutex.Unlock()
+
+	for events := range peb.subscribers[operationID] {
+		close(events)
+	}
+	delete(peb.subscribers, operationID)
+}
@@ -0,0 +1,107 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testProgressOperationID  = 1
+	testProgressTotalItemNum = 4
+)
+
+func TestProgress_All(t *testing.T) {
+	TestProgress_NewProgressWithEvent(t)
+	TestProgress_IsFinished(t)
+	TestProgressEventBroker_Publish(t)
+	TestProgressEventBroker_Unsubscribe(t)
+	TestProgressEventBroker_Complete(t)
+}
+
+func TestProgress_NewProgressWithEvent(t *testing.T) {
+	asst := assert.New(t)
+
+	event := newProgressEvent(testProgressOperationID, ProgressEventTypeItemStarted, defaultCPUUsageItemName, 1, testProgressTotalItemNum, time.Second)
+	progress := NewProgressWithEvent(event)
+	asst.Equal(25, progress.GetPercent(), "test NewProgressWithEvent() failed")
+	asst.Equal(defaultCPUUsageItemName, progress.GetCurrentItemName(), "test NewProgressWithEvent() failed")
+	asst.Equal(1000, progress.GetElapsed(), "test NewProgressWithEvent() failed")
+	// the current item is empty after the item finished
+	event = newProgressEvent(testProgressOperationID, ProgressEventTypeItemFinished, defaultCPUUsageItemName, 2, testProgressTotalItemNum, time.Second)
+	progress = NewProgressWithEvent(event)
+	asst.Equal(50, progress.GetPercent(), "test NewProgressWithEvent() failed")
+	asst.Empty(progress.GetCurrentItemName(), "test NewProgressWithEvent() failed")
+	// the percent is 0 if there is no item
+	event = newProgressEvent(testProgressOperationID, ProgressEventTypeCompleted, "", 0, 0, time.Second)
+	progress = NewProgressWithEvent(event)
+	asst.Equal(0, progress.GetPercent(), "test NewProgressWithEvent() failed")
+}
+
+func TestProgress_IsFinished(t *testing.T) {
+	asst := assert.New(t)
+
+	progress := NewEmptyProgress()
+	progress.Status = defaultRunningStatus
+	asst.False(progress.IsFinished(), "test IsFinished() failed")
+	progress.Status = defaultQueuedStatus
+	asst.False(progress.IsFinished(), "test IsFinished() failed")
+	progress.Status = defaultPartialStatus
+	asst.True(progress.IsFinished(), "test IsFinished() failed")
+	progress.Status = defaultCancelledStatus
+	asst.True(progress.IsFinished(), "test IsFinished() failed")
+}
+
+func TestProgressEventBroker_Publish(t *testing.T) {
+	asst := assert.New(t)
+
+	peb := newProgressEventBroker()
+	events, unsubscribe := peb.subscribe(testProgressOperationID)
+	defer unsubscribe()
+	otherEvents, otherUnsubscribe := peb.subscribe(testProgressOperationID + 1)
+	defer otherUnsubscribe()
+
+	peb.publish(newProgressEvent(testProgressOperationID, ProgressEventTypeItemStarted, defaultCPUUsageItemName, 0, testProgressTotalItemNum, time.Second))
+	asst.Equal(1, len(events), "test Publish() failed")
+	asst.Equal(0, len(otherEvents), "test Publish() failed")
+	event := <-events
+	asst.Equal(ProgressEventTypeItemStarted, event.GetEventType(), "test Publish() failed")
+	// publishing never blocks even if the subscriber does not receive the events
+	for i := 0; i <= defaultProgressEventBufferSize; i++ {
+		peb.publish(newProgressEvent(testProgressOperationID, ProgressEventTypeItemFinished, defaultCPUUsageItemName, 1, testProgressTotalItemNum, time.Second))
+	}
+	asst.Equal(defaultProgressEventBufferSize, len(events), "test Publish() failed")
+}
+
+func TestProgressEventBroker_Unsubscribe(t *testing.T) {
+	asst := assert.New(t)
+
+	peb := newProgressEventBroker()
+	events, unsubscribe := peb.subscribe(testProgressOperationID)
+	unsubscribe()
+	_, ok := <-events
+	asst.False(ok, "test Unsubscribe() failed")
+	asst.Equal(0, len(peb.subscribers), "test Unsubscribe() failed")
+	// unsubscribing again is safe
+	unsubscribe()
+}
+
+func TestProgressEventBroker_Complete(t *testing.T) {
+	asst := assert.New(t)
+
+	peb := newProgressEventBroker()
+	events, unsubscribe := peb.subscribe(testProgressOperationID)
+	peb.publish(newProgressEvent(testProgressOperationID, ProgressEventTypeCompleted, "", testProgressTotalItemNum, testProgressTotalItemNum, time.Second))
+	peb.complete(testProgressOperationID)
+
+	var received []healthcheck.ProgressEvent
+	for event := range events {
+		received = append(received, event)
+	}
+	asst.Equal(1, len(received), "test Complete() failed")
+	asst.Equal(ProgressEventTypeCompleted, received[0].GetEventType(), "test Complete() failed")
+	// unsubscribing after the channel is closed is safe
+	unsubscribe()
+}
@@ -0,0 +1,199 @@
+package healthcheck
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	// promql template names
+	PromQLAvgBackupFailedRatio         = "avg_backup_failed_ratio"
+	PromQLStatisticFailedRatio         = "statistic_failed_ratio"
+	PromQLCPUUsage                     = "cpu_usage"
+	PromQLMemoryUsage                  = "memory_usage"
+	PromQLFileSystem                   = "file_system"
+	PromQLIOUtil                       = "io_util"
+	PromQLDiskCapacity                 = "disk_capacity"
+	PromQLDiskSize                     = "disk_size"
+	PromQLDiskUsed                     = "disk_used"
+	PromQLConnectionUsage              = "connection_usage"
+	PromQLAverageActiveSessionPercents = "average_active_session_percents"
+	PromQLCacheMissRatio               = "cache_miss_ratio"
+
+	defaultPromQLWindow = "5m"
+)
+
+// builtInPromQLTemplates are the built-in promql templates of each monitor system type,
+// they are used when the templates are not overridden in the das tables
+var builtInPromQLTemplates = map[int]map[string]string{
+	// pmm 1.x
+	1: {
+		PromQLAvgBackupFailedRatio:         PrometheusAvgBackupFailedRatioV1,
+		PromQLStatisticFailedRatio:         PrometheusStatisticFailedRatioV1,
+		PromQLCPUUsage:                     PrometheusCPUUsageV1,
+		PromQLMemoryUsage:                  PrometheusMemoryUsageV1,
+		PromQLFileSystem:                   PrometheusFileSystemV1,
+		PromQLIOUtil:                       PrometheusIOUtilV1,
+		PromQLDiskCapacity:                 PrometheusDiskCapacityV1,
+		PromQLDiskSize:                     PrometheusDiskSizeV1,
+		PromQLDiskUsed:                     PrometheusDiskUsedV1,
+		PromQLConnectionUsage:              PrometheusConnectionUsageV1,
+		PromQLAverageActiveSessionPercents: PrometheusAverageActiveSessionPercentsV1,
+		PromQLCacheMissRatio:               PrometheusCacheMissRatioV1,
+	},
+	// pmm 2.x
+	2: {
+		PromQLAvgBackupFailedRatio:         PrometheusAvgBackupFailedRatioV2,
+		PromQLStatisticFailedRatio:         PrometheusStatisticFailedRatioV2,
+		PromQLCPUUsage:                     PrometheusCPUUsageV2,
+		PromQLMemoryUsage:                  PrometheusMemoryUsageV2,
+		PromQLFileSystem:                   PrometheusFileSystemV2,
+		PromQLIOUtil:                       PrometheusIOUtilV2,
+		PromQLDiskCapacity:                 PrometheusDiskCapacityV2,
+		PromQLDiskSize:                     PrometheusDiskSizeV2,
+		PromQLDiskUsed:                     PrometheusDiskUsedV2,
+		PromQLConnectionUsage:              PrometheusConnectionUsageV2,
+		PromQLAverageActiveSessionPercents: PrometheusAverageActiveSessionPercentsV2,
+		PromQLCacheMissRatio:               PrometheusCacheMissRatioV2,
+	},
+	// generic prometheus
+	3: {
+		PromQLAvgBackupFailedRatio:         PrometheusAvgBackupFailedRatioGeneric,
+		PromQLStatisticFailedRatio:         PrometheusStatisticFailedRatioGeneric,
+		PromQLCPUUsage:                     PrometheusCPUUsageGeneric,
+		PromQLMemoryUsage:                  PrometheusMemoryUsageGeneric,
+		PromQLFileSystem:                   PrometheusFileSystemGeneric,
+		PromQLIOUtil:                       PrometheusIOUtilGeneric,
+		PromQLDiskCapacity:                 PrometheusDiskCapacityGeneric,
+		PromQLDiskSize:                     PrometheusDiskSizeGeneric,
+		PromQLDiskUsed:                     PrometheusDiskUsedGeneric,
+		PromQLConnectionUsage:              PrometheusConnectionUsageGeneric,
+		PromQLAverageActiveSessionPercents: PrometheusAverageActiveSessionPercentsGeneric,
+		PromQLCacheMissRatio:               PrometheusCacheMissRatioGeneric,
+	},
+}
+
+// GetBuiltInPromQLTemplate returns the built-in promql template of the monitor system type
+func GetBuiltInPromQLTemplate(systemType int, templateName string) (string, error) {
+	templates, ok := builtInPromQLTemplates[systemType]
+	if !ok {
+		return constant.EmptyString, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	}
+	text, ok := templates[templateName]
+	if !ok {
+		return constant.EmptyString, message.NewMessage(msghc.ErrHealthcheckPromQLTemplateNameNotValid, templateName)
+	}
+
+	return text, nil
+}
+
+// PromQLVariables are the variables which could be used in the promql templates, e.g. {{.NodeName}}
+type PromQLVariables struct {
+	// ServiceName is the service name of the mysql server
+	ServiceName string
+	// NodeName is the server name of the mysql server or the middleware server
+	NodeName string
+	// MountPoints are the mount points joined by the vertical bar, it could be used in the regular expression
+	MountPoints string
+	// Window is the range of the range vector selectors, e.g. 5m
+	Window string
+	// Step is the step of the range query, e.g. 60s
+	Step string
+	// NodeSelector is the label selector of the node exporter series, it is only used by the generic prometheus
+	NodeSelector string
+	// ServiceSelector is the label selector of the mysqld exporter series, it is only used by the generic prometheus
+	ServiceSelector string
+}
+
+// NewPromQLVariables returns a new *PromQLVariables with the default window
+func NewPromQLVariables(serviceName, nodeName, mountPoints string, step time.Duration) *PromQLVariables {
+	return &PromQLVariables{
+		ServiceName: serviceName,
+		NodeName:    nodeName,
+		MountPoints: mountPoints,
+		Window:      defaultPromQLWindow,
+		Step:        fmt.Sprintf("%ds", int(step.Seconds())),
+	}
+}
+
+// PromQLRenderer renders the promql templates of a monitor system,
+// the templates overridden in the das tables take precedence over the built-in templates
+type PromQLRenderer struct {
+	systemType int
+	overrides  map[string]string
+}
+
+// NewPromQLRenderer returns a new *PromQLRenderer, overrides maps the template names to the template texts
+func NewPromQLRenderer(systemType int, overrides map[string]string) *PromQLRenderer {
+	if overrides == nil {
+		overrides = make(map[string]string)
+	}
+
+	return &PromQLRenderer{
+		systemType: systemType,
+		overrides:  overrides,
+	}
+}
+
+// NewPromQLRendererWithGlobal returns a new *PromQLRenderer of the monitor system,
+// the overridden templates are loaded from the das tables
+func NewPromQLRendererWithGlobal(monitorSystem depmeta.MonitorSystem) (*PromQLRenderer, error) {
+	templates, err := NewPromQLTemplateRepoWithGlobal().GetByMonitorSystemID(monitorSystem.Identity())
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[string]string)
+	for _, t := range templates {
+		overrides[t.GetTemplateName()] = t.GetTemplateText()
+	}
+
+	return NewPromQLRenderer(monitorSystem.GetSystemType(), overrides), nil
+}
+
+// GetTemplate returns the template text of the given name, the overridden template is returned if it exists
+func (prr *PromQLRenderer) GetTemplate(templateName string) (string, error) {
+	builtIn, err := GetBuiltInPromQLTemplate(prr.systemType, templateName)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+	text, ok := prr.overrides[templateName]
+	if ok {
+		return text, nil
+	}
+
+	return builtIn, nil
+}
+
+// Render renders the template of the given name with the variables
+func (prr *PromQLRenderer) Render(templateName string, variables *PromQLVariables) (string, error) {
+	text, err := prr.GetTemplate(templateName)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return RenderPromQL(templateName, text, variables)
+}
+
+// RenderPromQL renders the template text with the variables
+func RenderPromQL(templateName, text string, variables *PromQLVariables) (string, error) {
+	tmpl, err := template.New(templateName).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return constant.EmptyString, message.NewMessage(msghc.ErrHealthcheckPromQLTemplateNotValid, err, templateName)
+	}
+
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, variables)
+	if err != nil {
+		return constant.EmptyString, message.NewMessage(msghc.ErrHealthcheckPromQLTemplateNotValid, err, templateName)
+	}
+
+	return buffer.String(), nil
+}
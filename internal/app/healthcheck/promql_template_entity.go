@@ -0,0 +1,117 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const promQLTemplateTemplateTextStruct = "TemplateText"
+
+var _ healthcheck.PromQLTemplate = (*PromQLTemplate)(nil)
+
+// PromQLTemplate is the promql template which overrides the built-in template of a monitor system
+type PromQLTemplate struct {
+	healthcheck.PromQLTemplateRepo
+	ID              int       `middleware:"id" json:"id"`
+	MonitorSystemID int       `middleware:"monitor_system_id" json:"monitor_system_id"`
+	TemplateName    string    `middleware:"template_name" json:"template_name"`
+	TemplateText    string    `middleware:"template_text" json:"template_text"`
+	DelFlag         int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime      time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime  time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewPromQLTemplate returns a new *PromQLTemplate
+func NewPromQLTemplate(repo healthcheck.PromQLTemplateRepo, id, monitorSystemID int, templateName, templateText string,
+	delFlag int, createTime, lastUpdateTime time.Time) *PromQLTemplate {
+	return &PromQLTemplate{
+		PromQLTemplateRepo: repo,
+		ID:                 id,
+		MonitorSystemID:    monitorSystemID,
+		TemplateName:       templateName,
+		TemplateText:       templateText,
+		DelFlag:            delFlag,
+		CreateTime:         createTime,
+		LastUpdateTime:     lastUpdateTime,
+	}
+}
+
+// NewPromQLTemplateWithDefault returns a new *PromQLTemplate with default PromQLTemplateRepo
+func NewPromQLTemplateWithDefault(monitorSystemID int, templateName, templateText string) *PromQLTemplate {
+	return &PromQLTemplate{
+		PromQLTemplateRepo: NewPromQLTemplateRepoWithGlobal(),
+		MonitorSystemID:    monitorSystemID,
+		TemplateName:       templateName,
+		TemplateText:       templateText,
+	}
+}
+
+// NewEmptyPromQLTemplateWithGlobal returns a new *PromQLTemplate with global repository
+func NewEmptyPromQLTemplateWithGlobal() *PromQLTemplate {
+	return &PromQLTemplate{PromQLTemplateRepo: NewPromQLTemplateRepoWithGlobal()}
+}
+
+// Identity returns the identity
+func (pt *PromQLTemplate) Identity() int {
+	return pt.ID
+}
+
+// GetMonitorSystemID returns the monitor system id
+func (pt *PromQLTemplate) GetMonitorSystemID() int {
+	return pt.MonitorSystemID
+}
+
+// GetTemplateName returns the template name
+func (pt *PromQLTemplate) GetTemplateName() string {
+	return pt.TemplateName
+}
+
+// GetTemplateText returns the template text
+func (pt *PromQLTemplate) GetTemplateText() string {
+	return pt.TemplateText
+}
+
+// GetDelFlag returns the delete flag
+func (pt *PromQLTemplate) GetDelFlag() int {
+	return pt.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (pt *PromQLTemplate) GetCreateTime() time.Time {
+	return pt.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (pt *PromQLTemplate) GetLastUpdateTime() time.Time {
+	return pt.LastUpdateTime
+}
+
+// Set sets entity with given fields, key is the field name and value is the relevant value of the key
+func (pt *PromQLTemplate) Set(fields map[string]interface{}) error {
+	for fieldName, fieldValue := range fields {
+		err := common.SetValueOfStruct(pt, fieldName, fieldValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete sets DelFlag to 1
+func (pt *PromQLTemplate) Delete() {
+	pt.DelFlag = 1
+}
+
+// MarshalJSON marshals PromQLTemplate to json string
+func (pt *PromQLTemplate) MarshalJSON() ([]byte, error) {
+	return common.MarshalStructWithTag(pt, constant.DefaultMarshalTag)
+}
+
+// MarshalJSONWithFields marshals only specified fields of PromQLTemplate to json string
+func (pt *PromQLTemplate) MarshalJSONWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(pt, fields...)
+}
@@ -0,0 +1,169 @@
+package healthcheck
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware"
+	"github.com/romberli/log"
+)
+
+var _ healthcheck.PromQLTemplateRepo = (*PromQLTemplateRepo)(nil)
+
+// PromQLTemplateRepo is the repository of the overridden promql templates
+type PromQLTemplateRepo struct {
+	Database middleware.Pool
+}
+
+// NewPromQLTemplateRepo returns healthcheck.PromQLTemplateRepo with given middleware.Pool
+func NewPromQLTemplateRepo(db middleware.Pool) healthcheck.PromQLTemplateRepo {
+	return newPromQLTemplateRepo(db)
+}
+
+// NewPromQLTemplateRepoWithGlobal returns healthcheck.PromQLTemplateRepo with global mysql pool
+func NewPromQLTemplateRepoWithGlobal() healthcheck.PromQLTemplateRepo {
+	return newPromQLTemplateRepo(global.DASMySQLPool)
+}
+
+// newPromQLTemplateRepo returns *PromQLTemplateRepo with given middleware.Pool
+func newPromQLTemplateRepo(db middleware.Pool) *PromQLTemplateRepo {
+	return &PromQLTemplateRepo{Database: db}
+}
+
+// Execute executes given command and placeholders on the middleware
+func (ptr *PromQLTemplateRepo) Execute(command string, args ...interface{}) (middleware.Result, error) {
+	conn, err := ptr.Database.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = conn.Close()
+		if err != nil {
+			log.Errorf("healthcheck PromQLTemplateRepo.Execute(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	return conn.Execute(command, args...)
+}
+
+// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+func (ptr *PromQLTemplateRepo) Transaction() (middleware.Transaction, error) {
+	return ptr.Database.Transaction()
+}
+
+// GetAll gets all overridden promql templates from the middleware
+func (ptr *PromQLTemplateRepo) GetAll() ([]healthcheck.PromQLTemplate, error) {
+	sql := `
+		select id, monitor_system_id, template_name, template_text, del_flag, create_time, last_update_time
+		from t_hc_promql_template
+		where del_flag = 0
+		order by monitor_system_id, template_name;
+	`
+	log.Debugf("healthcheck PromQLTemplateRepo.GetAll() sql: \n%s", sql)
+
+	return ptr.getPromQLTemplates(sql)
+}
+
+// GetByMonitorSystemID gets the overridden promql templates of the monitor system from the middleware
+func (ptr *PromQLTemplateRepo) GetByMonitorSystemID(monitorSystemID int) ([]healthcheck.PromQLTemplate, error) {
+	sql := `
+		select id, monitor_system_id, template_name, template_text, del_flag, create_time, last_update_time
+		from t_hc_promql_template
+		where del_flag = 0
+		  and monitor_system_id = ?
+		order by template_name;
+	`
+	log.Debugf("healthcheck PromQLTemplateRepo.GetByMonitorSystemID() sql: \n%s\nplaceholders: %d", sql, monitorSystemID)
+
+	return ptr.getPromQLTemplates(sql, monitorSystemID)
+}
+
+// GetByName gets the overridden promql template of the monitor system by the template name from the middleware
+func (ptr *PromQLTemplateRepo) GetByName(monitorSystemID int, templateName string) (healthcheck.PromQLTemplate, error) {
+	sql := `
+		select id, monitor_system_id, template_name, template_text, del_flag, create_time, last_update_time
+		from t_hc_promql_template
+		where del_flag = 0
+		  and monitor_system_id = ?
+		  and template_name = ?;
+	`
+	log.Debugf("healthcheck PromQLTemplateRepo.GetByName() sql: \n%s\nplaceholders: %d, %s", sql, monitorSystemID, templateName)
+
+	result, err := ptr.Execute(sql, monitorSystemID, templateName)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, errors.Errorf("healthcheck PromQLTemplateRepo.GetByName(): data does not exists, monitor_system_id: %d, template_name: %s",
+			monitorSystemID, templateName)
+	case 1:
+		promQLTemplate := NewEmptyPromQLTemplateWithGlobal()
+		// map to struct
+		err = result.MapToStructByRowIndex(promQLTemplate, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+
+		return promQLTemplate, nil
+	default:
+		return nil, errors.Errorf("healthcheck PromQLTemplateRepo.GetByName(): duplicate key exists, monitor_system_id: %d, template_name: %s",
+			monitorSystemID, templateName)
+	}
+}
+
+// Create creates a promql template in the middleware
+func (ptr *PromQLTemplateRepo) Create(promQLTemplate healthcheck.PromQLTemplate) (healthcheck.PromQLTemplate, error) {
+	sql := `insert into t_hc_promql_template(monitor_system_id, template_name, template_text) values(?, ?, ?);`
+	log.Debugf("healthcheck PromQLTemplateRepo.Create() insert sql: \n%s\nplaceholders: %d, %s, %s",
+		sql, promQLTemplate.GetMonitorSystemID(), promQLTemplate.GetTemplateName(), promQLTemplate.GetTemplateText())
+	// execute
+	_, err := ptr.Execute(sql, promQLTemplate.GetMonitorSystemID(), promQLTemplate.GetTemplateName(), promQLTemplate.GetTemplateText())
+	if err != nil {
+		return nil, err
+	}
+	// get promql template
+	return ptr.GetByName(promQLTemplate.GetMonitorSystemID(), promQLTemplate.GetTemplateName())
+}
+
+// Update updates the promql template in the middleware
+func (ptr *PromQLTemplateRepo) Update(promQLTemplate healthcheck.PromQLTemplate) error {
+	sql := `update t_hc_promql_template set monitor_system_id = ?, template_name = ?, template_text = ?, del_flag = ? where id = ?;`
+	log.Debugf("healthcheck PromQLTemplateRepo.Update() update sql: \n%s\nplaceholders: %d, %s, %s, %d, %d",
+		sql, promQLTemplate.GetMonitorSystemID(), promQLTemplate.GetTemplateName(), promQLTemplate.GetTemplateText(),
+		promQLTemplate.GetDelFlag(), promQLTemplate.Identity())
+	_, err := ptr.Execute(sql, promQLTemplate.GetMonitorSystemID(), promQLTemplate.GetTemplateName(), promQLTemplate.GetTemplateText(),
+		promQLTemplate.GetDelFlag(), promQLTemplate.Identity())
+
+	return err
+}
+
+// Delete deletes the promql template in the middleware
+func (ptr *PromQLTemplateRepo) Delete(id int) error {
+	sql := `delete from t_hc_promql_template where id = ?;`
+	log.Debugf("healthcheck PromQLTemplateRepo.Delete() delete sql: \n%s\nplaceholders: %d", sql, id)
+	_, err := ptr.Execute(sql, id)
+
+	return err
+}
+
+// getPromQLTemplates executes the select sql and maps the result to the promql templates
+func (ptr *PromQLTemplateRepo) getPromQLTemplates(sql string, args ...interface{}) ([]healthcheck.PromQLTemplate, error) {
+	result, err := ptr.Execute(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	// init []healthcheck.PromQLTemplate
+	templateList := make([]healthcheck.PromQLTemplate, result.RowNumber())
+	for i := range templateList {
+		templateList[i] = NewEmptyPromQLTemplateWithGlobal()
+	}
+	// map to struct
+	err = result.MapToStructSlice(templateList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return templateList, nil
+}
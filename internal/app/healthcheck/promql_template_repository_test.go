@@ -0,0 +1,144 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPromQLTemplateMonitorSystemID = 1
+	testPromQLTemplateName            = PromQLCPUUsage
+	testPromQLTemplateText            = `avg(rate(node_cpu_seconds_total{node_name=~"{{.NodeName}}",mode!="idle"}[{{.Window}}]))`
+	testPromQLTemplateUpdateText      = `avg(irate(node_cpu_seconds_total{node_name=~"{{.NodeName}}",mode!="idle"}[{{.Window}}]))`
+)
+
+var testPromQLTemplateRepo *PromQLTemplateRepo
+
+func init() {
+	testInitDASMySQLPool()
+	testPromQLTemplateRepo = newPromQLTemplateRepo(global.DASMySQLPool)
+}
+
+func testCreatePromQLTemplate() (healthcheck.PromQLTemplate, error) {
+	return testPromQLTemplateRepo.Create(NewPromQLTemplateWithDefault(testPromQLTemplateMonitorSystemID, testPromQLTemplateName, testPromQLTemplateText))
+}
+
+func testDeletePromQLTemplateByID(id int) error {
+	return testPromQLTemplateRepo.Delete(id)
+}
+
+func TestPromQLTemplateRepo_All(t *testing.T) {
+	TestPromQLTemplateRepo_Execute(t)
+	TestPromQLTemplateRepo_Transaction(t)
+	TestPromQLTemplateRepo_GetAll(t)
+	TestPromQLTemplateRepo_GetByMonitorSystemID(t)
+	TestPromQLTemplateRepo_GetByName(t)
+	TestPromQLTemplateRepo_Create(t)
+	TestPromQLTemplateRepo_Update(t)
+	TestPromQLTemplateRepo_Delete(t)
+}
+
+func TestPromQLTemplateRepo_Execute(t *testing.T) {
+	asst := assert.New(t)
+
+	sql := `select 1;`
+	result, err := testPromQLTemplateRepo.Execute(sql)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	r, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	asst.Equal(1, r, "test Execute() failed")
+}
+
+func TestPromQLTemplateRepo_Transaction(t *testing.T) {
+	asst := assert.New(t)
+
+	tx, err := testPromQLTemplateRepo.Transaction()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Begin()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Rollback()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+}
+
+func TestPromQLTemplateRepo_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	promQLTemplate, err := testCreatePromQLTemplate()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	promQLTemplates, err := testPromQLTemplateRepo.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	asst.NotZero(len(promQLTemplates), "test GetAll() failed")
+	// delete
+	err = testDeletePromQLTemplateByID(promQLTemplate.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestPromQLTemplateRepo_GetByMonitorSystemID(t *testing.T) {
+	asst := assert.New(t)
+
+	promQLTemplate, err := testCreatePromQLTemplate()
+	asst.Nil(err, common.CombineMessageWithError("test GetByMonitorSystemID() failed", err))
+	promQLTemplates, err := testPromQLTemplateRepo.GetByMonitorSystemID(testPromQLTemplateMonitorSystemID)
+	asst.Nil(err, common.CombineMessageWithError("test GetByMonitorSystemID() failed", err))
+	asst.NotZero(len(promQLTemplates), "test GetByMonitorSystemID() failed")
+	// delete
+	err = testDeletePromQLTemplateByID(promQLTemplate.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByMonitorSystemID() failed", err))
+}
+
+func TestPromQLTemplateRepo_GetByName(t *testing.T) {
+	asst := assert.New(t)
+
+	promQLTemplate, err := testCreatePromQLTemplate()
+	asst.Nil(err, common.CombineMessageWithError("test GetByName() failed", err))
+	entity, err := testPromQLTemplateRepo.GetByName(testPromQLTemplateMonitorSystemID, testPromQLTemplateName)
+	asst.Nil(err, common.CombineMessageWithError("test GetByName() failed", err))
+	asst.Equal(testPromQLTemplateText, entity.GetTemplateText(), "test GetByName() failed")
+	// delete
+	err = testDeletePromQLTemplateByID(promQLTemplate.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByName() failed", err))
+}
+
+func TestPromQLTemplateRepo_Create(t *testing.T) {
+	asst := assert.New(t)
+
+	promQLTemplate, err := testCreatePromQLTemplate()
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	asst.Equal(testPromQLTemplateName, promQLTemplate.GetTemplateName(), "test Create() failed")
+	// delete
+	err = testDeletePromQLTemplateByID(promQLTemplate.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+}
+
+func TestPromQLTemplateRepo_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	promQLTemplate, err := testCreatePromQLTemplate()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = promQLTemplate.Set(map[string]interface{}{promQLTemplateTemplateTextStruct: testPromQLTemplateUpdateText})
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testPromQLTemplateRepo.Update(promQLTemplate)
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	entity, err := testPromQLTemplateRepo.GetByName(testPromQLTemplateMonitorSystemID, testPromQLTemplateName)
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testPromQLTemplateUpdateText, entity.GetTemplateText(), "test Update() failed")
+	// delete
+	err = testDeletePromQLTemplateByID(promQLTemplate.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestPromQLTemplateRepo_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	promQLTemplate, err := testCreatePromQLTemplate()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testDeletePromQLTemplateByID(promQLTemplate.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	_, err = testPromQLTemplateRepo.GetByName(testPromQLTemplateMonitorSystemID, testPromQLTemplateName)
+	asst.NotNil(err, "test Delete() failed")
+}
@@ -0,0 +1,288 @@
+package healthcheck
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware/prometheus"
+	"github.com/romberli/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	promQLTemplatePromQLTemplatesStruct = "PromQLTemplates"
+	promQLTemplateRenderedQueryStruct   = "RenderedQuery"
+	promQLTemplateDatasStruct           = "Datas"
+)
+
+var _ healthcheck.PromQLTemplateService = (*PromQLTemplateService)(nil)
+
+// PromQLTemplateService is the service of the overridden promql templates
+type PromQLTemplateService struct {
+	healthcheck.PromQLTemplateRepo
+	PromQLTemplates []healthcheck.PromQLTemplate `json:"promql_templates"`
+	RenderedQuery   string                       `json:"rendered_query"`
+	Datas           []healthcheck.PrometheusData `json:"datas"`
+}
+
+// NewPromQLTemplateService returns a new healthcheck.PromQLTemplateService
+func NewPromQLTemplateService(repo healthcheck.PromQLTemplateRepo) healthcheck.PromQLTemplateService {
+	return newPromQLTemplateService(repo)
+}
+
+// NewPromQLTemplateServiceWithDefault returns a new healthcheck.PromQLTemplateService with default repository
+func NewPromQLTemplateServiceWithDefault() healthcheck.PromQLTemplateService {
+	return newPromQLTemplateService(NewPromQLTemplateRepoWithGlobal())
+}
+
+// newPromQLTemplateService returns a new *PromQLTemplateService
+func newPromQLTemplateService(repo healthcheck.PromQLTemplateRepo) *PromQLTemplateService {
+	return &PromQLTemplateService{
+		PromQLTemplateRepo: repo,
+		PromQLTemplates:    []healthcheck.PromQLTemplate{},
+		Datas:              []healthcheck.PrometheusData{},
+	}
+}
+
+// GetPromQLTemplates returns the promql templates of the service
+func (pts *PromQLTemplateService) GetPromQLTemplates() []healthcheck.PromQLTemplate {
+	return pts.PromQLTemplates
+}
+
+// GetRenderedQuery returns the rendered query of the dry run
+func (pts *PromQLTemplateService) GetRenderedQuery() string {
+	return pts.RenderedQuery
+}
+
+// GetDatas returns the prometheus datas of the dry run
+func (pts *PromQLTemplateService) GetDatas() []healthcheck.PrometheusData {
+	return pts.Datas
+}
+
+// GetAll gets all overridden promql templates from the middleware
+func (pts *PromQLTemplateService) GetAll() error {
+	var err error
+
+	pts.PromQLTemplates, err = pts.PromQLTemplateRepo.GetAll()
+
+	return err
+}
+
+// GetByMonitorSystemID gets the overridden promql templates of the monitor system from the middleware
+func (pts *PromQLTemplateService) GetByMonitorSystemID(monitorSystemID int) error {
+	var err error
+
+	pts.PromQLTemplates, err = pts.PromQLTemplateRepo.GetByMonitorSystemID(monitorSystemID)
+
+	return err
+}
+
+// Save creates the promql template of the monitor system, or updates it if it exists,
+// the template must be one of the built-in template names and must be rendered successfully
+func (pts *PromQLTemplateService) Save(monitorSystemID int, templateName, templateText string) error {
+	monitorSystem, err := pts.getMonitorSystem(monitorSystemID)
+	if err != nil {
+		return err
+	}
+	// check the template
+	_, err = GetBuiltInPromQLTemplate(monitorSystem.GetSystemType(), templateName)
+	if err != nil {
+		return err
+	}
+	_, err = RenderPromQL(templateName, templateText, NewPromQLVariables(constant.EmptyString, constant.EmptyString, constant.EmptyString, time.Minute))
+	if err != nil {
+		return err
+	}
+
+	promQLTemplates, err := pts.PromQLTemplateRepo.GetByMonitorSystemID(monitorSystemID)
+	if err != nil {
+		return err
+	}
+	for _, promQLTemplate := range promQLTemplates {
+		if promQLTemplate.GetTemplateName() != templateName {
+			continue
+		}
+		// update the existing promql template
+		err = promQLTemplate.Set(map[string]interface{}{promQLTemplateTemplateTextStruct: templateText})
+		if err != nil {
+			return err
+		}
+		err = pts.PromQLTemplateRepo.Update(promQLTemplate)
+		if err != nil {
+			return err
+		}
+
+		return pts.getByName(monitorSystemID, templateName)
+	}
+	// create a new promql template
+	promQLTemplate, err := pts.PromQLTemplateRepo.Create(NewPromQLTemplateWithDefault(monitorSystemID, templateName, templateText))
+	if err != nil {
+		return err
+	}
+
+	pts.PromQLTemplates = nil
+	pts.PromQLTemplates = append(pts.PromQLTemplates, promQLTemplate)
+
+	return nil
+}
+
+// Delete deletes the promql template of the monitor system, the built-in template will be used after that
+func (pts *PromQLTemplateService) Delete(monitorSystemID int, templateName string) error {
+	err := pts.getByName(monitorSystemID, templateName)
+	if err != nil {
+		return err
+	}
+
+	return pts.PromQLTemplateRepo.Delete(pts.PromQLTemplates[constant.ZeroInt].Identity())
+}
+
+// DryRun renders the promql template with the variables of the mysql server and executes it on the monitor system,
+// if templateText is empty, the overridden template or the built-in template of the monitor system will be used,
+// it does not change any data
+func (pts *PromQLTemplateService) DryRun(monitorSystemID, mysqlServerID int, templateName, templateText string, mountPoints []string,
+	startTime, endTime time.Time, step time.Duration) error {
+	monitorSystem, err := pts.getMonitorSystem(monitorSystemID)
+	if err != nil {
+		return err
+	}
+	mysqlServerService := metadata.NewMySQLServerServiceWithDefault()
+	err = mysqlServerService.GetByID(mysqlServerID)
+	if err != nil {
+		return err
+	}
+	mysqlServer := mysqlServerService.GetMySQLServers()[constant.ZeroInt]
+
+	// render the template
+	variables := NewPromQLVariables(mysqlServer.GetServiceName(), mysqlServer.GetServerName(),
+		common.ConvertStringSliceToString(mountPoints, constant.VerticalBarString), step)
+	variables.NodeSelector = getGenericSelector(viper.GetString(config.DBMonitorGenericNodeLabelKey), mysqlServer.GetServerName(),
+		viper.GetString(config.DBMonitorGenericNodeJobKey))
+	variables.ServiceSelector = getGenericSelector(viper.GetString(config.DBMonitorGenericServiceLabelKey), mysqlServer.GetServiceName(),
+		viper.GetString(config.DBMonitorGenericMySQLJobKey))
+	if templateText == constant.EmptyString {
+		renderer, err := NewPromQLRendererWithGlobal(monitorSystem)
+		if err != nil {
+			return err
+		}
+		pts.RenderedQuery, err = renderer.Render(templateName, variables)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = GetBuiltInPromQLTemplate(monitorSystem.GetSystemType(), templateName)
+		if err != nil {
+			return err
+		}
+		pts.RenderedQuery, err = RenderPromQL(templateName, templateText, variables)
+		if err != nil {
+			return err
+		}
+	}
+	log.Debugf("healthcheck PromQLTemplateService.DryRun() query: \n%s\n", pts.RenderedQuery)
+
+	// execute the query
+	conn, err := newPrometheusConn(monitorSystem)
+	if err != nil {
+		return err
+	}
+	pts.Datas = []healthcheck.PrometheusData{}
+	if templateName == PromQLFileSystem {
+		// the file systems are got by an instant query
+		result, err := conn.Execute(pts.RenderedQuery)
+		if err != nil {
+			return err
+		}
+		vector, err := result.Raw.GetVector()
+		if err != nil {
+			return err
+		}
+		for _, sample := range vector {
+			pts.Datas = append(pts.Datas, NewPrometheusData(sample.Timestamp.String(), float64(sample.Value)))
+		}
+
+		return nil
+	}
+
+	result, err := conn.Execute(pts.RenderedQuery, startTime, endTime, step)
+	if err != nil {
+		return err
+	}
+	matrix, err := result.Raw.GetMatrix()
+	if err != nil {
+		return err
+	}
+	for _, sampleStream := range matrix {
+		for _, samplePair := range sampleStream.Values {
+			pts.Datas = append(pts.Datas, NewPrometheusData(samplePair.Timestamp.String(), float64(samplePair.Value)))
+		}
+	}
+
+	return nil
+}
+
+// getByName gets the overridden promql template of the monitor system by the template name from the middleware
+func (pts *PromQLTemplateService) getByName(monitorSystemID int, templateName string) error {
+	promQLTemplate, err := pts.PromQLTemplateRepo.GetByName(monitorSystemID, templateName)
+	if err != nil {
+		return err
+	}
+
+	pts.PromQLTemplates = nil
+	pts.PromQLTemplates = append(pts.PromQLTemplates, promQLTemplate)
+
+	return nil
+}
+
+// getMonitorSystem gets the monitor system by the id
+func (pts *PromQLTemplateService) getMonitorSystem(monitorSystemID int) (depmeta.MonitorSystem, error) {
+	monitorSystemService := metadata.NewMonitorSystemServiceWithDefault()
+	err := monitorSystemService.GetByID(monitorSystemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return monitorSystemService.GetMonitorSystems()[constant.ZeroInt], nil
+}
+
+// Marshal marshals PromQLTemplateService.PromQLTemplates to json bytes
+func (pts *PromQLTemplateService) Marshal() ([]byte, error) {
+	return pts.MarshalWithFields(promQLTemplatePromQLTemplatesStruct)
+}
+
+// MarshalWithFields marshals only specified fields of the PromQLTemplateService to json bytes
+func (pts *PromQLTemplateService) MarshalWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(pts, fields...)
+}
+
+// newPrometheusConn returns a new prometheus connection of the monitor system
+func newPrometheusConn(monitorSystem depmeta.MonitorSystem) (*prometheus.Conn, error) {
+	var prometheusConfig prometheus.Config
+
+	prometheusAddr := fmt.Sprintf("%s:%d%s", monitorSystem.GetHostIP(), monitorSystem.GetPortNum(), monitorSystem.GetBaseURL())
+	prometheusUser := viper.GetString(config.DBMonitorPrometheusUserKey)
+	switch monitorSystem.GetSystemType() {
+	case 1:
+		// pmm 1.x
+		prometheusConfig = prometheus.NewConfig(prometheusAddr, prometheus.DefaultRoundTripper)
+	case 2, 3:
+		// pmm 2.x and generic prometheus
+		prometheusConfig = prometheus.NewConfigWithBasicAuth(prometheusAddr, prometheusUser, viper.GetString(config.DBMonitorPrometheusPassKey))
+	default:
+		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	}
+
+	conn, err := prometheus.NewConnWithConfig(prometheusConfig)
+	if err != nil {
+		return nil, message.NewMessage(msghc.ErrHealthcheckCreateMonitorPrometheusConnection, err, prometheusAddr, prometheusUser)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,106 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPromQLTemplateService *PromQLTemplateService
+
+func init() {
+	testInitDASMySQLPool()
+	testPromQLTemplateService = newPromQLTemplateService(NewPromQLTemplateRepoWithGlobal())
+}
+
+func TestPromQLTemplateService_All(t *testing.T) {
+	TestPromQLTemplateService_GetAll(t)
+	TestPromQLTemplateService_GetByMonitorSystemID(t)
+	TestPromQLTemplateService_Save(t)
+	TestPromQLTemplateService_Delete(t)
+	TestPromQLTemplateService_DryRun(t)
+	TestPromQLTemplateService_Marshal(t)
+}
+
+func TestPromQLTemplateService_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testPromQLTemplateService.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestPromQLTemplateService_GetByMonitorSystemID(t *testing.T) {
+	asst := assert.New(t)
+
+	promQLTemplate, err := testCreatePromQLTemplate()
+	asst.Nil(err, common.CombineMessageWithError("test GetByMonitorSystemID() failed", err))
+	err = testPromQLTemplateService.GetByMonitorSystemID(testPromQLTemplateMonitorSystemID)
+	asst.Nil(err, common.CombineMessageWithError("test GetByMonitorSystemID() failed", err))
+	asst.NotZero(len(testPromQLTemplateService.GetPromQLTemplates()), "test GetByMonitorSystemID() failed")
+	// delete
+	err = testDeletePromQLTemplateByID(promQLTemplate.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByMonitorSystemID() failed", err))
+}
+
+func TestPromQLTemplateService_Save(t *testing.T) {
+	asst := assert.New(t)
+
+	// create
+	err := testPromQLTemplateService.Save(testPromQLTemplateMonitorSystemID, testPromQLTemplateName, testPromQLTemplateText)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(testPromQLTemplateText, testPromQLTemplateService.GetPromQLTemplates()[constant.ZeroInt].GetTemplateText(), "test Save() failed")
+	// update
+	err = testPromQLTemplateService.Save(testPromQLTemplateMonitorSystemID, testPromQLTemplateName, testPromQLTemplateUpdateText)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(testPromQLTemplateUpdateText, testPromQLTemplateService.GetPromQLTemplates()[constant.ZeroInt].GetTemplateText(), "test Save() failed")
+	// delete
+	err = testDeletePromQLTemplateByID(testPromQLTemplateService.GetPromQLTemplates()[constant.ZeroInt].Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	// invalid template name
+	err = testPromQLTemplateService.Save(testPromQLTemplateMonitorSystemID, "invalid", testPromQLTemplateText)
+	asst.NotNil(err, "test Save() failed")
+	// invalid template text
+	err = testPromQLTemplateService.Save(testPromQLTemplateMonitorSystemID, testPromQLTemplateName, "{{.NodeName")
+	asst.NotNil(err, "test Save() failed")
+}
+
+func TestPromQLTemplateService_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	_, err := testCreatePromQLTemplate()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testPromQLTemplateService.Delete(testPromQLTemplateMonitorSystemID, testPromQLTemplateName)
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testPromQLTemplateService.Delete(testPromQLTemplateMonitorSystemID, testPromQLTemplateName)
+	asst.NotNil(err, "test Delete() failed")
+}
+
+func TestPromQLTemplateService_DryRun(t *testing.T) {
+	asst := assert.New(t)
+
+	monitorSystemID := testOperationInfo.GetMonitorSystem().Identity()
+	mysqlServerID := testOperationInfo.GetMySQLServer().Identity()
+	// built-in template
+	err := testPromQLTemplateService.DryRun(monitorSystemID, mysqlServerID, PromQLCPUUsage, constant.EmptyString, nil,
+		testOperationInfo.GetStartTime(), testOperationInfo.GetEndTime(), testOperationInfo.GetStep())
+	asst.Nil(err, common.CombineMessageWithError("test DryRun() failed", err))
+	asst.NotEmpty(testPromQLTemplateService.GetRenderedQuery(), "test DryRun() failed")
+	// given template
+	err = testPromQLTemplateService.DryRun(monitorSystemID, mysqlServerID, PromQLDiskCapacity, PrometheusDiskCapacityV2,
+		[]string{testCapacityMountPoint}, testOperationInfo.GetStartTime(), testOperationInfo.GetEndTime(), testOperationInfo.GetStep())
+	asst.Nil(err, common.CombineMessageWithError("test DryRun() failed", err))
+	asst.Contains(testPromQLTemplateService.GetRenderedQuery(), testCapacityMountPoint, "test DryRun() failed")
+}
+
+func TestPromQLTemplateService_Marshal(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testPromQLTemplateService.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	_, err = testPromQLTemplateService.Marshal()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	_, err = testPromQLTemplateService.MarshalWithFields(promQLTemplateRenderedQueryStruct, promQLTemplateDatasStruct)
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+}
@@ -0,0 +1,109 @@
+package healthcheck
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPromQLServiceName  = "192-168-10-219-mysql"
+	testPromQLNodeName     = "192-168-10-219"
+	testPromQLMountPoints  = "/data|/"
+	testPromQLOverrideText = `avg(rate(node_cpu_seconds_total{node_name=~"{{.NodeName}}",mode!="idle"}[{{.Window}}]))`
+)
+
+func testNewPromQLVariables() *PromQLVariables {
+	variables := NewPromQLVariables(testPromQLServiceName, testPromQLNodeName, testPromQLMountPoints, time.Minute)
+	variables.NodeSelector = getGenericSelector("instance", testPromQLNodeName, "node")
+	variables.ServiceSelector = getGenericSelector("instance", testPromQLServiceName, "mysql")
+
+	return variables
+}
+
+func TestPromQL_All(t *testing.T) {
+	TestPromQL_GetBuiltInPromQLTemplate(t)
+	TestPromQL_NewPromQLVariables(t)
+	TestPromQLRenderer_GetTemplate(t)
+	TestPromQLRenderer_Render(t)
+	TestPromQL_RenderPromQL(t)
+}
+
+func TestPromQL_GetBuiltInPromQLTemplate(t *testing.T) {
+	asst := assert.New(t)
+
+	for systemType, templates := range builtInPromQLTemplates {
+		for templateName, expect := range templates {
+			text, err := GetBuiltInPromQLTemplate(systemType, templateName)
+			asst.Nil(err, common.CombineMessageWithError("test GetBuiltInPromQLTemplate() failed", err))
+			asst.Equal(expect, text, "test GetBuiltInPromQLTemplate() failed")
+		}
+	}
+	// invalid system type
+	_, err := GetBuiltInPromQLTemplate(constant.ZeroInt, PromQLCPUUsage)
+	asst.NotNil(err, "test GetBuiltInPromQLTemplate() failed")
+	// invalid template name
+	_, err = GetBuiltInPromQLTemplate(2, "invalid")
+	asst.NotNil(err, "test GetBuiltInPromQLTemplate() failed")
+}
+
+func TestPromQL_NewPromQLVariables(t *testing.T) {
+	asst := assert.New(t)
+
+	variables := NewPromQLVariables(testPromQLServiceName, testPromQLNodeName, testPromQLMountPoints, time.Minute)
+	asst.Equal(defaultPromQLWindow, variables.Window, "test NewPromQLVariables() failed")
+	asst.Equal("60s", variables.Step, "test NewPromQLVariables() failed")
+}
+
+func TestPromQLRenderer_GetTemplate(t *testing.T) {
+	asst := assert.New(t)
+
+	renderer := NewPromQLRenderer(2, map[string]string{PromQLCPUUsage: testPromQLOverrideText})
+	// overridden template
+	text, err := renderer.GetTemplate(PromQLCPUUsage)
+	asst.Nil(err, common.CombineMessageWithError("test GetTemplate() failed", err))
+	asst.Equal(testPromQLOverrideText, text, "test GetTemplate() failed")
+	// built-in template
+	text, err = renderer.GetTemplate(PromQLIOUtil)
+	asst.Nil(err, common.CombineMessageWithError("test GetTemplate() failed", err))
+	asst.Equal(PrometheusIOUtilV2, text, "test GetTemplate() failed")
+}
+
+func TestPromQLRenderer_Render(t *testing.T) {
+	asst := assert.New(t)
+
+	variables := testNewPromQLVariables()
+	// all the built-in templates should be rendered without any placeholder left
+	for systemType, templates := range builtInPromQLTemplates {
+		renderer := NewPromQLRenderer(systemType, nil)
+		for templateName := range templates {
+			query, err := renderer.Render(templateName, variables)
+			asst.Nil(err, common.CombineMessageWithError("test Render() failed", err))
+			asst.False(strings.Contains(query, "{{"), "test Render() failed")
+			asst.False(strings.Contains(query, "%s"), "test Render() failed")
+		}
+	}
+	// overridden template
+	renderer := NewPromQLRenderer(2, map[string]string{PromQLCPUUsage: testPromQLOverrideText})
+	query, err := renderer.Render(PromQLCPUUsage, variables)
+	asst.Nil(err, common.CombineMessageWithError("test Render() failed", err))
+	asst.Equal(`avg(rate(node_cpu_seconds_total{node_name=~"192-168-10-219",mode!="idle"}[5m]))`, query, "test Render() failed")
+}
+
+func TestPromQL_RenderPromQL(t *testing.T) {
+	asst := assert.New(t)
+
+	query, err := RenderPromQL(PromQLDiskSize, PrometheusDiskSizeGeneric, testNewPromQLVariables())
+	asst.Nil(err, common.CombineMessageWithError("test RenderPromQL() failed", err))
+	asst.True(strings.Contains(query, `{ instance=~"192-168-10-219(:[0-9]+)?",job=~"node", mountpoint=~"(/data|/)"`), "test RenderPromQL() failed")
+	// invalid template
+	_, err = RenderPromQL(PromQLCPUUsage, `rate(node_cpu_seconds_total{node_name=~"{{.NodeName"}[5m])`, testNewPromQLVariables())
+	asst.NotNil(err, "test RenderPromQL() failed")
+	// unknown variable
+	_, err = RenderPromQL(PromQLCPUUsage, `rate(node_cpu_seconds_total{node_name=~"{{.HostName}}"}[5m])`, testNewPromQLVariables())
+	asst.NotNil(err, "test RenderPromQL() failed")
+}
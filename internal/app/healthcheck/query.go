@@ -1,5 +1,9 @@
 package healthcheck
 
+import (
+	"github.com/romberli/das/internal/app/query"
+)
+
 const (
 	// application mysql
 	applicationMySQLVariables = `
@@ -17,108 +21,311 @@ const (
 		  and table_rows > ?
 		order by table_rows desc;
     `
+	applicationMySQLReplicationStatus = `show slave status;`
+	applicationMySQLReplicaStatus     = `show replica status;`
+	applicationMySQLGTIDExecuted      = `select @@global.gtid_executed;`
+	applicationMySQLConnectionID      = `select connection_id();`
+	applicationMySQLKillQuery         = `kill query %d;`
+	applicationMySQLGTIDSubtract      = `select gtid_subtract(?, ?);`
+	applicationMySQLGroupMembers      = `
+		select member_host, member_port, member_state
+		from performance_schema.replication_group_members
+		where member_id <> '';
+    `
+	applicationMySQLLongTransactions = `
+		select cast(trx.trx_id as char) as trx_id,
+			   trx.trx_state,
+			   trx.trx_started,
+			   timestampdiff(second, trx.trx_started, now()) as trx_duration,
+			   trx.trx_mysql_thread_id as thread_id,
+			   ifnull(pl.user, '') as user,
+			   ifnull(pl.host, '') as host,
+			   ifnull(pl.db, '') as db_name,
+			   trx.trx_rows_locked,
+			   trx.trx_rows_modified,
+			   ifnull(trx.trx_query, '') as trx_query
+		from information_schema.innodb_trx trx
+			left join information_schema.processlist pl on trx.trx_mysql_thread_id = pl.id
+		where trx.trx_started <= now() - interval ? second
+		order by trx.trx_started;
+    `
+	applicationMySQLHistoryListLength = `
+		select count
+		from information_schema.innodb_metrics
+		where name = 'trx_rseg_history_len';
+    `
+	applicationMySQLLockWaitsV57 = `
+		select cast(w.requesting_trx_id as char) as waiting_trx_id,
+			   r.trx_mysql_thread_id as waiting_thread_id,
+			   ifnull(r.trx_query, '') as waiting_query,
+			   cast(w.blocking_trx_id as char) as blocking_trx_id,
+			   b.trx_mysql_thread_id as blocking_thread_id,
+			   ifnull(b.trx_query, '') as blocking_query,
+			   timestampdiff(second, r.trx_wait_started, now()) as wait_age,
+			   l.lock_table as locked_table
+		from information_schema.innodb_lock_waits w
+			inner join information_schema.innodb_trx r on w.requesting_trx_id = r.trx_id
+			inner join information_schema.innodb_trx b on w.blocking_trx_id = b.trx_id
+			inner join information_schema.innodb_locks l on w.requested_lock_id = l.lock_id
+		order by wait_age desc;
+    `
+	applicationMySQLLockWaitsV80 = `
+		select cast(w.requesting_engine_transaction_id as char) as waiting_trx_id,
+			   r.trx_mysql_thread_id as waiting_thread_id,
+			   ifnull(r.trx_query, '') as waiting_query,
+			   cast(w.blocking_engine_transaction_id as char) as blocking_trx_id,
+			   b.trx_mysql_thread_id as blocking_thread_id,
+			   ifnull(b.trx_query, '') as blocking_query,
+			   timestampdiff(second, r.trx_wait_started, now()) as wait_age,
+			   concat(l.object_schema, '.', l.object_name) as locked_table
+		from performance_schema.data_lock_waits w
+			inner join information_schema.innodb_trx r on w.requesting_engine_transaction_id = r.trx_id
+			inner join information_schema.innodb_trx b on w.blocking_engine_transaction_id = b.trx_id
+			inner join performance_schema.data_locks l on w.requesting_engine_lock_id = l.engine_lock_id
+		order by wait_age desc;
+    `
+	applicationMySQLInnoDBStatus = `show engine innodb status;`
+	applicationMySQLTableSchemas = `
+		select t.table_schema as db_name,
+			   t.table_name,
+			   ifnull(t.engine, '') as engine,
+			   if(tc.constraint_name is null, 'false', 'true') as has_primary_key
+		from information_schema.tables t
+			left join information_schema.table_constraints tc on t.table_schema = tc.table_schema
+			and t.table_name = tc.table_name
+			and tc.constraint_type = 'PRIMARY KEY'
+		where t.table_type = 'BASE TABLE'
+		  and t.table_schema not in (%s)
+		order by t.table_schema, t.table_name;
+    `
+	applicationMySQLIndexStatistics = `
+		select table_schema                        as db_name,
+			   table_name                          as table_name,
+			   index_name                          as index_name,
+			   seq_in_index                        as sequence,
+			   column_name                         as column_name,
+			   ifnull(cardinality, 0)              as cardinality,
+			   if(non_unique = 0, 'true', 'false') as is_unique,
+			   if(nullable = '', 'false', 'true')  as is_nullable
+		from information_schema.statistics
+		where table_schema not in (%s)
+		order by table_schema, table_name, index_name, seq_in_index;
+    `
+	applicationMySQLAutoIncrements = `
+		select t.table_schema as db_name,
+			   t.table_name,
+			   c.column_name,
+			   c.data_type,
+			   c.column_type,
+			   t.auto_increment
+		from information_schema.tables t
+			inner join information_schema.columns c on t.table_schema = c.table_schema
+			and t.table_name = c.table_name
+			and c.extra like '%%auto_increment%%'
+		where t.table_type = 'BASE TABLE'
+		  and t.auto_increment is not null
+		  and t.table_schema not in (%s)
+		order by t.table_schema, t.table_name;
+    `
+	applicationMySQLUsersV56 = `
+		select user,
+			   host,
+			   ifnull(plugin, '') as plugin,
+			   if(password = '' and ifnull(authentication_string, '') = ''
+				   and ifnull(plugin, '') in ('', 'mysql_native_password', 'mysql_old_password', 'sha256_password'), 'true', 'false') as empty_password,
+			   if(plugin = 'mysql_old_password' or length(password) = 16, 'true', 'false') as old_password,
+			   'false' as account_locked
+		from mysql.user
+		order by user, host;
+    `
+	applicationMySQLUsersV57 = `
+		select user,
+			   host,
+			   plugin,
+			   if(authentication_string = ''
+				   and plugin in ('mysql_native_password', 'caching_sha2_password', 'sha256_password'), 'true', 'false') as empty_password,
+			   if(plugin = 'mysql_old_password' or length(authentication_string) = 16, 'true', 'false') as old_password,
+			   if(account_locked = 'Y', 'true', 'false') as account_locked
+		from mysql.user
+		order by user, host;
+    `
+	applicationMySQLGrants = `show grants for '%s'@'%s';`
 	// Prometheus API
 	PrometheusAvgBackupFailedRatioV1 = `
-		1-(sum(mysqldumpbackup{instance=~"%s",type="status"})/count(mysqldumpbackup{instance=~"%s",type="status"})+
-		sum(xtrabackup{instance=~"%s",type="status"})/count(xtrabackup{instance=~"%s",type="status"})+
-		sum(mysqldumpnbubackup{instance=~"%s"})/count(mysqldumpnbubackup{instance=~"%s"})+
-		sum(nbubackup{instance=~"%s"})/count(nbubackup{instance=~"%s"}))/4
+		1-(sum(mysqldumpbackup{instance=~"{{.NodeName}}",type="status"})/count(mysqldumpbackup{instance=~"{{.NodeName}}",type="status"})+
+		sum(xtrabackup{instance=~"{{.NodeName}}",type="status"})/count(xtrabackup{instance=~"{{.NodeName}}",type="status"})+
+		sum(mysqldumpnbubackup{instance=~"{{.NodeName}}"})/count(mysqldumpnbubackup{instance=~"{{.NodeName}}"})+
+		sum(nbubackup{instance=~"{{.NodeName}}"})/count(nbubackup{instance=~"{{.NodeName}}"}))/4
     `
 	PrometheusAvgBackupFailedRatioV2 = `
-		1-(sum(mysqldumpbackup{node_name=~"%s",type="status"})/count(mysqldumpbackup{node_name=~"%s",type="status"})+
-		sum(xtrabackup{node_name=~"%s",type="status"})/count(xtrabackup{node_name=~"%s",type="status"})+
-		sum(mysqldumpnbubackup{node_name=~"%s"})/count(mysqldumpnbubackup{node_name=~"%s"})+
-		sum(nbubackup{node_name=~"%s"})/count(nbubackup{node_name=~"%s"}))/4
+		1-(sum(mysqldumpbackup{node_name=~"{{.NodeName}}",type="status"})/count(mysqldumpbackup{node_name=~"{{.NodeName}}",type="status"})+
+		sum(xtrabackup{node_name=~"{{.NodeName}}",type="status"})/count(xtrabackup{node_name=~"{{.NodeName}}",type="status"})+
+		sum(mysqldumpnbubackup{node_name=~"{{.NodeName}}"})/count(mysqldumpnbubackup{node_name=~"{{.NodeName}}"})+
+		sum(nbubackup{node_name=~"{{.NodeName}}"})/count(nbubackup{node_name=~"{{.NodeName}}"}))/4
     `
 	PrometheusStatisticFailedRatioV1 = `
-	1-(sum(mysqlupdbstat{instance=~"%s",type="dbupstatus"})/count(mysqlupdbstat{instance=~"%s",type="dbupstatus"}))
+	1-(sum(mysqlupdbstat{instance=~"{{.NodeName}}",type="dbupstatus"})/count(mysqlupdbstat{instance=~"{{.NodeName}}",type="dbupstatus"}))
 	`
 	PrometheusStatisticFailedRatioV2 = `
-	1-(sum(mysqlupdbstat{node_name=~"%s",type="dbupstatus"})/count(mysqlupdbstat{node_name=~"%s",type="dbupstatus"}))
+	1-(sum(mysqlupdbstat{node_name=~"{{.NodeName}}",type="dbupstatus"})/count(mysqlupdbstat{node_name=~"{{.NodeName}}",type="dbupstatus"}))
 	`
 	PrometheusCPUUsageV1 = `
 		clamp_max(sum by () ((avg by (mode) (
-		(clamp_max(rate(node_cpu{instance=~"%s",mode!="idle",mode!="iowait"}[5m]),1)) or
-		(clamp_max(irate(node_cpu{instance=~"%s",mode!="idle",mode!="iowait"}[5m]),1)) )) or
+		(clamp_max(rate(node_cpu{instance=~"{{.NodeName}}",mode!="idle",mode!="iowait"}[{{.Window}}]),1)) or
+		(clamp_max(irate(node_cpu{instance=~"{{.NodeName}}",mode!="idle",mode!="iowait"}[{{.Window}}]),1)) )) or
 		sum by () (
-		avg_over_time(node_cpu_average{instance=~"%s",mode!="total",mode!="idle"}[5m]) or
-		avg_over_time(node_cpu_average{instance=~"%s",mode!="total",mode!="idle"}[5m])) unless
-		(avg_over_time(node_cpu_average{instance=~"%s",mode="total",job="rds-basic"}[5m]) or
-		avg_over_time(node_cpu_average{instance=~"%s",mode="total",job="rds-basic"}[5m]))
+		avg_over_time(node_cpu_average{instance=~"{{.NodeName}}",mode!="total",mode!="idle"}[{{.Window}}]) or
+		avg_over_time(node_cpu_average{instance=~"{{.NodeName}}",mode!="total",mode!="idle"}[{{.Window}}])) unless
+		(avg_over_time(node_cpu_average{instance=~"{{.NodeName}}",mode="total",job="rds-basic"}[{{.Window}}]) or
+		avg_over_time(node_cpu_average{instance=~"{{.NodeName}}",mode="total",job="rds-basic"}[{{.Window}}]))
 		),100)
     `
 	PrometheusCPUUsageV2 = `
 		clamp_max(sum by () ((avg by (mode) ( 
-		(clamp_max(rate(node_cpu_seconds_total{node_name=~"%s",mode!="idle",mode!="iowait"}[5m]),1)) or 
-		(clamp_max(irate(node_cpu_seconds_total{node_name=~"%s",mode!="idle",mode!="iowait"}[5m]),1)) )) or
+		(clamp_max(rate(node_cpu_seconds_total{node_name=~"{{.NodeName}}",mode!="idle",mode!="iowait"}[{{.Window}}]),1)) or 
+		(clamp_max(irate(node_cpu_seconds_total{node_name=~"{{.NodeName}}",mode!="idle",mode!="iowait"}[{{.Window}}]),1)) )) or
 		sum by () (
-		avg_over_time(node_cpu_average{node_name=~"%s",mode!="total",mode!="idle"}[5m]) or 
-		avg_over_time(node_cpu_average{node_name=~"%s",mode!="total",mode!="idle"}[5m])) unless
-		(avg_over_time(node_cpu_average{node_name=~"%s",mode="total",job="rds-basic"}[5m]) or 
-		avg_over_time(node_cpu_average{node_name=~"%s",mode="total",job="rds-basic"}[5m]))
+		avg_over_time(node_cpu_average{node_name=~"{{.NodeName}}",mode!="total",mode!="idle"}[{{.Window}}]) or 
+		avg_over_time(node_cpu_average{node_name=~"{{.NodeName}}",mode!="total",mode!="idle"}[{{.Window}}])) unless
+		(avg_over_time(node_cpu_average{node_name=~"{{.NodeName}}",mode="total",job="rds-basic"}[{{.Window}}]) or 
+		avg_over_time(node_cpu_average{node_name=~"{{.NodeName}}",mode="total",job="rds-basic"}[{{.Window}}]))
 		),100)
+    `
+	PrometheusMemoryUsageV1 = `
+		1 - avg by () (node_memory_MemAvailable{instance=~"{{.NodeName}}"}) / avg by () (node_memory_MemTotal{instance=~"{{.NodeName}}"})
+    `
+	PrometheusMemoryUsageV2 = `
+		1 - avg by () (node_memory_MemAvailable_bytes{node_name=~"{{.NodeName}}"}) / avg by () (node_memory_MemTotal_bytes{node_name=~"{{.NodeName}}"})
     `
 	PrometheusFileSystemV1 = `
-		node_filesystem_files{instance=~"%s",fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}
+		node_filesystem_files{instance=~"{{.NodeName}}",fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}
     `
 	PrometheusFileSystemV2 = `
-		node_filesystem_files{node_name=~"%s",fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}
+		node_filesystem_files{node_name=~"{{.NodeName}}",fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}
     `
 	PrometheusIOUtilV1 = `
-		max by (instance) (rate(node_disk_io_time_ms{instance=~"%s"}[20s])/1000 or
-		irate(node_disk_io_time_ms{instance=~"%s"}[5m])/1000)
+		max by (instance) (rate(node_disk_io_time_ms{instance=~"{{.NodeName}}"}[20s])/1000 or
+		irate(node_disk_io_time_ms{instance=~"{{.NodeName}}"}[{{.Window}}])/1000)
     `
 	PrometheusIOUtilV2 = `
-		max by (node_name) (rate(node_disk_io_time_seconds_total{node_name=~"%s"}[20s]) or
-		irate(node_disk_io_time_seconds_total{node_name=~"%s"}[5m]) or
-		(max_over_time(rdsosmetrics_diskIO_util{node_name=~"%s"}[20s]) or
-		max_over_time(rdsosmetrics_diskIO_util{node_name=~"%s"}[5m]))/100)
+		max by (node_name) (rate(node_disk_io_time_seconds_total{node_name=~"{{.NodeName}}"}[20s]) or
+		irate(node_disk_io_time_seconds_total{node_name=~"{{.NodeName}}"}[{{.Window}}]) or
+		(max_over_time(rdsosmetrics_diskIO_util{node_name=~"{{.NodeName}}"}[20s]) or
+		max_over_time(rdsosmetrics_diskIO_util{node_name=~"{{.NodeName}}"}[{{.Window}}]))/100)
     `
 	PrometheusDiskCapacityV1 = `
-		1 - node_filesystem_free{instance=~"%s", mountpoint=~"(%s)", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"} /
-		node_filesystem_size{instance=~"%s", mountpoint=~"(%s)", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}
+		1 - node_filesystem_free{instance=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"} /
+		node_filesystem_size{instance=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}
     `
 	PrometheusDiskCapacityV2 = `
-		avg by (node_name, mountpoint) (1 - (max_over_time(node_filesystem_free_bytes{node_name=~"%s", mountpoint=~"(%s)", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[20s]) or
-		max_over_time(node_filesystem_free_bytes{node_name=~"%s", mountpoint=~"(%s)", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[5m])) /
-		(max_over_time(node_filesystem_size_bytes{node_name=~"%s", mountpoint=~"(%s)", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[20s]) or
-		max_over_time(node_filesystem_size_bytes{node_name=~"%s", mountpoint=~"(%s)", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[5m])))
+		avg by (node_name, mountpoint) (1 - (max_over_time(node_filesystem_free_bytes{node_name=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[20s]) or
+		max_over_time(node_filesystem_free_bytes{node_name=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}])) /
+		(max_over_time(node_filesystem_size_bytes{node_name=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[20s]) or
+		max_over_time(node_filesystem_size_bytes{node_name=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}])))
    `
+	PrometheusDiskSizeV1 = `
+		avg by (mountpoint) (node_filesystem_size{instance=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"})
+    `
+	PrometheusDiskSizeV2 = `
+		avg by (mountpoint) (max_over_time(node_filesystem_size_bytes{node_name=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}]))
+    `
+	PrometheusDiskUsedV1 = `
+		avg by (mountpoint) (node_filesystem_size{instance=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"} -
+		node_filesystem_free{instance=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"})
+    `
+	PrometheusDiskUsedV2 = `
+		avg by (mountpoint) (max_over_time(node_filesystem_size_bytes{node_name=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}]) -
+		max_over_time(node_filesystem_free_bytes{node_name=~"{{.NodeName}}", mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}]))
+    `
 	PrometheusConnectionUsageV1 = `
-		avg by (instance) (max(max_over_time(mysql_global_status_threads_connected{instance=~"%s"}[20s]) or
-		max_over_time(mysql_global_status_threads_connected{instance=~"%s"}[5m]))) /
-		avg by (instance) (max(max_over_time(mysql_global_variables_max_connections{instance=~"%s"}[20s]) or
-		max_over_time(mysql_global_variables_max_connections{instance=~"%s"}[5m])))
+		avg by (instance) (max(max_over_time(mysql_global_status_threads_connected{instance=~"{{.ServiceName}}"}[20s]) or
+		max_over_time(mysql_global_status_threads_connected{instance=~"{{.ServiceName}}"}[{{.Window}}]))) /
+		avg by (instance) (max(max_over_time(mysql_global_variables_max_connections{instance=~"{{.ServiceName}}"}[20s]) or
+		max_over_time(mysql_global_variables_max_connections{instance=~"{{.ServiceName}}"}[{{.Window}}])))
     `
 	PrometheusConnectionUsageV2 = `
-		avg by (service_name) (max(max_over_time(mysql_global_status_threads_connected{service_name=~"%s"}[20s]) or
-		max_over_time(mysql_global_status_threads_connected{service_name=~"%s"}[5m]))) /
-		avg by (service_name) (max_over_time(mysql_global_variables_max_connections{service_name=~"%s"}[20s]) or
-		max_over_time(mysql_global_variables_max_connections{service_name=~"%s"}[5m]))
+		avg by (service_name) (max(max_over_time(mysql_global_status_threads_connected{service_name=~"{{.ServiceName}}"}[20s]) or
+		max_over_time(mysql_global_status_threads_connected{service_name=~"{{.ServiceName}}"}[{{.Window}}]))) /
+		avg by (service_name) (max_over_time(mysql_global_variables_max_connections{service_name=~"{{.ServiceName}}"}[20s]) or
+		max_over_time(mysql_global_variables_max_connections{service_name=~"{{.ServiceName}}"}[{{.Window}}]))
     `
 	PrometheusAverageActiveSessionPercentsV1 = `
-		avg by (instance) (avg_over_time(mysql_global_status_threads_running{instance=~"%s"}[20s]) or
-		avg_over_time(mysql_global_status_threads_running{instance=~"%s"}[5m]))/
-		avg by (instance) (max_over_time(mysql_global_status_threads_connected{instance=~"%s"}[20s]) or
-		max_over_time(mysql_global_status_threads_connected{instance=~"%s"}[5m]))
+		avg by (instance) (avg_over_time(mysql_global_status_threads_running{instance=~"{{.ServiceName}}"}[20s]) or
+		avg_over_time(mysql_global_status_threads_running{instance=~"{{.ServiceName}}"}[{{.Window}}]))/
+		avg by (instance) (max_over_time(mysql_global_status_threads_connected{instance=~"{{.ServiceName}}"}[20s]) or
+		max_over_time(mysql_global_status_threads_connected{instance=~"{{.ServiceName}}"}[{{.Window}}]))
     `
 	PrometheusAverageActiveSessionPercentsV2 = `
-		avg by (service_name) (avg_over_time(mysql_global_status_threads_running{service_name=~"%s"}[20s]) or
-		avg_over_time(mysql_global_status_threads_running{service_name=~"%s"}[5m]))/
-		avg by (service_name) (max_over_time(mysql_global_status_threads_connected{service_name=~"%s"}[20s]) or
-		max_over_time(mysql_global_status_threads_connected{service_name=~"%s"}[5m]))
+		avg by (service_name) (avg_over_time(mysql_global_status_threads_running{service_name=~"{{.ServiceName}}"}[20s]) or
+		avg_over_time(mysql_global_status_threads_running{service_name=~"{{.ServiceName}}"}[{{.Window}}]))/
+		avg by (service_name) (max_over_time(mysql_global_status_threads_connected{service_name=~"{{.ServiceName}}"}[20s]) or
+		max_over_time(mysql_global_status_threads_connected{service_name=~"{{.ServiceName}}"}[{{.Window}}]))
     `
 	PrometheusCacheMissRatioV1 = `
-		avg by (instance) ((rate(mysql_global_status_innodb_buffer_pool_reads{instance=~"%s"}[5m]) or
-		irate(mysql_global_status_innodb_buffer_pool_reads{instance=~"%s"}[5m])) /
-		(rate(mysql_global_status_innodb_buffer_pool_read_requests{instance=~"%s"}[5m]) or
-		irate(mysql_global_status_innodb_buffer_pool_read_requests{instance=~"%s"}[5m])))
+		avg by (instance) ((rate(mysql_global_status_innodb_buffer_pool_reads{instance=~"{{.ServiceName}}"}[{{.Window}}]) or
+		irate(mysql_global_status_innodb_buffer_pool_reads{instance=~"{{.ServiceName}}"}[{{.Window}}])) /
+		(rate(mysql_global_status_innodb_buffer_pool_read_requests{instance=~"{{.ServiceName}}"}[{{.Window}}]) or
+		irate(mysql_global_status_innodb_buffer_pool_read_requests{instance=~"{{.ServiceName}}"}[{{.Window}}])))
     `
 	PrometheusCacheMissRatioV2 = `
-		avg by (service_name) ((rate(mysql_global_status_innodb_buffer_pool_reads{service_name=~"%s"}[5m]) or
-		irate(mysql_global_status_innodb_buffer_pool_reads{service_name=~"%s"}[5m])) /
-		(rate(mysql_global_status_innodb_buffer_pool_read_requests{service_name=~"%s"}[5m]) or
-		irate(mysql_global_status_innodb_buffer_pool_read_requests{service_name=~"%s"}[5m])))
+		avg by (service_name) ((rate(mysql_global_status_innodb_buffer_pool_reads{service_name=~"{{.ServiceName}}"}[{{.Window}}]) or
+		irate(mysql_global_status_innodb_buffer_pool_reads{service_name=~"{{.ServiceName}}"}[{{.Window}}])) /
+		(rate(mysql_global_status_innodb_buffer_pool_read_requests{service_name=~"{{.ServiceName}}"}[{{.Window}}]) or
+		irate(mysql_global_status_innodb_buffer_pool_read_requests{service_name=~"{{.ServiceName}}"}[{{.Window}}])))
+    `
+	// generic prometheus, the label selectors are generated by getGenericSelector()
+	PrometheusAvgBackupFailedRatioGeneric = `
+		1-(sum(mysqldumpbackup{ {{.NodeSelector}},type="status"})/count(mysqldumpbackup{ {{.NodeSelector}},type="status"})+
+		sum(xtrabackup{ {{.NodeSelector}},type="status"})/count(xtrabackup{ {{.NodeSelector}},type="status"})+
+		sum(mysqldumpnbubackup{ {{.NodeSelector}}})/count(mysqldumpnbubackup{ {{.NodeSelector}}})+
+		sum(nbubackup{ {{.NodeSelector}}})/count(nbubackup{ {{.NodeSelector}}}))/4
+    `
+	PrometheusStatisticFailedRatioGeneric = `
+	1-(sum(mysqlupdbstat{ {{.NodeSelector}},type="dbupstatus"})/count(mysqlupdbstat{ {{.NodeSelector}},type="dbupstatus"}))
+	`
+	PrometheusCPUUsageGeneric = `
+		clamp_max(sum by () (avg by (mode) (
+		(clamp_max(rate(node_cpu_seconds_total{ {{.NodeSelector}},mode!="idle",mode!="iowait"}[{{.Window}}]),1)) or
+		(clamp_max(irate(node_cpu_seconds_total{ {{.NodeSelector}},mode!="idle",mode!="iowait"}[{{.Window}}]),1)) )),100)
+    `
+	PrometheusMemoryUsageGeneric = `
+		1 - avg by () (node_memory_MemAvailable_bytes{ {{.NodeSelector}}}) / avg by () (node_memory_MemTotal_bytes{ {{.NodeSelector}}})
+    `
+	PrometheusFileSystemGeneric = `
+		node_filesystem_files{ {{.NodeSelector}},fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}
+    `
+	PrometheusIOUtilGeneric = `
+		max by () (rate(node_disk_io_time_seconds_total{ {{.NodeSelector}}}[20s]) or
+		irate(node_disk_io_time_seconds_total{ {{.NodeSelector}}}[{{.Window}}]))
+    `
+	PrometheusDiskCapacityGeneric = `
+		avg by (mountpoint) (1 - max_over_time(node_filesystem_free_bytes{ {{.NodeSelector}}, mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}]) /
+		max_over_time(node_filesystem_size_bytes{ {{.NodeSelector}}, mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}]))
+    `
+	PrometheusDiskSizeGeneric = `
+		avg by (mountpoint) (max_over_time(node_filesystem_size_bytes{ {{.NodeSelector}}, mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}]))
+    `
+	PrometheusDiskUsedGeneric = `
+		avg by (mountpoint) (max_over_time(node_filesystem_size_bytes{ {{.NodeSelector}}, mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}]) -
+		max_over_time(node_filesystem_free_bytes{ {{.NodeSelector}}, mountpoint=~"({{.MountPoints}})", fstype!~"rootfs|selinuxfs|autofs|rpc_pipefs|tmpfs"}[{{.Window}}]))
+    `
+	PrometheusConnectionUsageGeneric = `
+		max by () (max_over_time(mysql_global_status_threads_connected{ {{.ServiceSelector}}}[20s]) or
+		max_over_time(mysql_global_status_threads_connected{ {{.ServiceSelector}}}[{{.Window}}])) /
+		max by () (max_over_time(mysql_global_variables_max_connections{ {{.ServiceSelector}}}[20s]) or
+		max_over_time(mysql_global_variables_max_connections{ {{.ServiceSelector}}}[{{.Window}}]))
+    `
+	PrometheusAverageActiveSessionPercentsGeneric = `
+		avg by () (avg_over_time(mysql_global_status_threads_running{ {{.ServiceSelector}}}[20s]) or
+		avg_over_time(mysql_global_status_threads_running{ {{.ServiceSelector}}}[{{.Window}}]))/
+		avg by () (max_over_time(mysql_global_status_threads_connected{ {{.ServiceSelector}}}[20s]) or
+		max_over_time(mysql_global_status_threads_connected{ {{.ServiceSelector}}}[{{.Window}}]))
+    `
+	PrometheusCacheMissRatioGeneric = `
+		avg by () ((rate(mysql_global_status_innodb_buffer_pool_reads{ {{.ServiceSelector}}}[{{.Window}}]) or
+		irate(mysql_global_status_innodb_buffer_pool_reads{ {{.ServiceSelector}}}[{{.Window}}])) /
+		(rate(mysql_global_status_innodb_buffer_pool_read_requests{ {{.ServiceSelector}}}[{{.Window}}]) or
+		irate(mysql_global_status_innodb_buffer_pool_read_requests{ {{.ServiceSelector}}}[{{.Window}}])))
     `
 	// query
 	MonitorMySQLQuery = `
@@ -186,4 +393,32 @@ const (
 							group by queryid) m
 						   on sm.sql_id = m.sql_id;
     `
+	// the digests which were seen in the time range are returned with their lifetime statistics,
+	// see query.PerformanceSchemaDigestQuery
+	PerformanceSchemaQuery = query.PerformanceSchemaDigestQuery + `
+		  and sum_rows_examined / count_star >= ?
+		order by rows_examined_max desc
+		limit ?;
+    `
+	// the slow log statistics are ingested from the slow log files into the das database,
+	// they are aggregated in the windows of the slow log files, the windows which start in the time range are used
+	SlowLogQuery = `
+		select sql_id,
+			   max(fingerprint)                                    as fingerprint,
+			   max(example)                                        as example,
+			   db_name,
+			   sum(exec_count)                                     as exec_count,
+			   truncate(sum(total_exec_time), 2)                   as total_exec_time,
+			   truncate(sum(total_exec_time) / sum(exec_count), 2) as avg_exec_time,
+			   max(rows_examined_max)                              as rows_examined_max
+		from t_query_slow_log_stat
+		where del_flag = 0
+		  and mysql_server_id = ?
+		  and window_start >= ?
+		  and window_start < ?
+		group by sql_id, db_name
+		having rows_examined_max >= ?
+		order by rows_examined_max desc
+		limit ?;
+    `
 )
@@ -0,0 +1,438 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	defaultReplicationThreadItemName  = "replication_thread"
+	defaultReplicationLagItemName     = "replication_lag"
+	defaultReplicationGTIDGapItemName = "replication_gtid_gap"
+	defaultMGRMemberStateItemName     = "mgr_member_state"
+
+	replicationThreadRunning     = "Yes"
+	replicationNotReplicaMessage = "the mysql server is not a replica"
+	mgrNotMemberMessage          = "the mysql server is not a member of group replication"
+
+	mgrMemberStateOnline     = "ONLINE"
+	mgrMemberStateRecovering = "RECOVERING"
+
+	mgrMemberStateValueOnline     = 0
+	mgrMemberStateValueRecovering = 1
+	mgrMemberStateValueAbnormal   = 2
+
+	gtidRangeSeparator = "-"
+	gtidTagUnderscore  = '_'
+)
+
+var (
+	_ healthcheck.CheckItem = (*ReplicationThreadItem)(nil)
+	_ healthcheck.CheckItem = (*ReplicationLagItem)(nil)
+	_ healthcheck.CheckItem = (*ReplicationGTIDGapItem)(nil)
+	_ healthcheck.CheckItem = (*MGRMemberStateItem)(nil)
+)
+
+func init() {
+	RegisterCheckItem(NewReplicationThreadItem())
+	RegisterCheckItem(NewReplicationLagItem())
+	RegisterCheckItem(NewReplicationGTIDGapItem())
+	RegisterCheckItem(NewMGRMemberStateItem())
+}
+
+// ReplicationData is the value of a replication channel or a group member which is used to calculate the score
+type ReplicationData struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Detail string  `json:"detail"`
+}
+
+// NewReplicationData returns a new *ReplicationData
+func NewReplicationData(name string, value float64, detail string) *ReplicationData {
+	return &ReplicationData{
+		Name:   name,
+		Value:  value,
+		Detail: detail,
+	}
+}
+
+// GTIDGap is the gtid gap between the master and the replica of a replication channel
+type GTIDGap struct {
+	ChannelName  string `json:"channel_name"`
+	MasterHost   string `json:"master_host"`
+	MasterPort   int    `json:"master_port"`
+	MissingGTIDs string `json:"missing_gtids"`
+	MissingCount int    `json:"missing_count"`
+	ErrantGTIDs  string `json:"errant_gtids"`
+	ErrantCount  int    `json:"errant_count"`
+}
+
+// ReplicationThreadItem checks if the io thread and the sql thread of the replica are running,
+// the value of each replication channel is the number of the threads which are not running
+type ReplicationThreadItem struct{}
+
+// NewReplicationThreadItem returns a new *ReplicationThreadItem
+func NewReplicationThreadItem() *ReplicationThreadItem {
+	return &ReplicationThreadItem{}
+}
+
+// GetName returns the item name
+func (rti *ReplicationThreadItem) GetName() string {
+	return defaultReplicationThreadItemName
+}
+
+// GetDataSource returns the data source of the item
+func (rti *ReplicationThreadItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the replication threads
+func (rti *ReplicationThreadItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	replicationStatuses, err := env.GetApplicationMySQLRepo().GetReplicationStatus()
+	if err != nil {
+		return nil, err
+	}
+	if len(replicationStatuses) == constant.ZeroInt {
+		return NewSkippedItemResultWithEnv(env, rti, replicationNotReplicaMessage), nil
+	}
+
+	var (
+		datas   []*ReplicationData
+		advices []string
+	)
+	for _, rs := range replicationStatuses {
+		var (
+			value   float64
+			details []string
+		)
+		if !rs.IsIORunning() {
+			value++
+			details = append(details, fmt.Sprintf("io thread is not running, last io error: %s", rs.GetLastIOError()))
+		}
+		if !rs.IsSQLRunning() {
+			value++
+			details = append(details, fmt.Sprintf("sql thread is not running, last sql error: %s", rs.GetLastSQLError()))
+		}
+		detail := strings.Join(details, constant.CommaString)
+		if detail != constant.EmptyString {
+			advices = append(advices, fmt.Sprintf("%s: %s", getReplicationChannelDesc(rs), detail))
+		}
+		datas = append(datas, NewReplicationData(getReplicationChannelDesc(rs), value, detail))
+	}
+
+	return newReplicationItemResult(env, rti, datas, strings.Join(advices, constant.CommaString))
+}
+
+// ReplicationLagItem checks the replication lag of the replica, the value of each replication channel is the seconds behind master,
+// the channel of which the lag is unknown will not be scored, as it is already scored by the replication thread item
+type ReplicationLagItem struct{}
+
+// NewReplicationLagItem returns a new *ReplicationLagItem
+func NewReplicationLagItem() *ReplicationLagItem {
+	return &ReplicationLagItem{}
+}
+
+// GetName returns the item name
+func (rli *ReplicationLagItem) GetName() string {
+	return defaultReplicationLagItemName
+}
+
+// GetDataSource returns the data source of the item
+func (rli *ReplicationLagItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the replication lag
+func (rli *ReplicationLagItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	replicationStatuses, err := env.GetApplicationMySQLRepo().GetReplicationStatus()
+	if err != nil {
+		return nil, err
+	}
+	if len(replicationStatuses) == constant.ZeroInt {
+		return NewSkippedItemResultWithEnv(env, rli, replicationNotReplicaMessage), nil
+	}
+
+	var (
+		datas   []*ReplicationData
+		advices []string
+	)
+	for _, rs := range replicationStatuses {
+		if rs.GetSecondsBehindMaster() == replicationUnknownLag {
+			advices = append(advices, fmt.Sprintf("%s: replication lag is unknown", getReplicationChannelDesc(rs)))
+			continue
+		}
+		datas = append(datas, NewReplicationData(getReplicationChannelDesc(rs), float64(rs.GetSecondsBehindMaster()), constant.EmptyString))
+	}
+
+	return newReplicationItemResult(env, rli, datas, strings.Join(advices, constant.CommaString))
+}
+
+// ReplicationGTIDGapItem checks the gtid gap between the master and the replica,
+// the value of each replication channel is the number of the transactions which are executed on the master but not on the replica
+// plus the number of the errant transactions which are executed on the replica but not on the master
+type ReplicationGTIDGapItem struct{}
+
+// NewReplicationGTIDGapItem returns a new *ReplicationGTIDGapItem
+func NewReplicationGTIDGapItem() *ReplicationGTIDGapItem {
+	return &ReplicationGTIDGapItem{}
+}
+
+// GetName returns the item name
+func (rggi *ReplicationGTIDGapItem) GetName() string {
+	return defaultReplicationGTIDGapItemName
+}
+
+// GetDataSource returns the data source of the item
+func (rggi *ReplicationGTIDGapItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the gtid gap
+func (rggi *ReplicationGTIDGapItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	repo := env.GetApplicationMySQLRepo()
+	replicationStatuses, err := repo.GetReplicationStatus()
+	if err != nil {
+		return nil, err
+	}
+	if len(replicationStatuses) == constant.ZeroInt {
+		return NewSkippedItemResultWithEnv(env, rggi, replicationNotReplicaMessage), nil
+	}
+
+	var (
+		datas   []*ReplicationData
+		gaps    []*GTIDGap
+		advices []string
+	)
+	for _, rs := range replicationStatuses {
+		// get the gtid set of the replica first, so that the transactions which are committed on the master
+		// while checking will be treated as missing transactions rather than errant transactions
+		replicaGTIDSet, err := repo.GetGTIDExecuted()
+		if err != nil {
+			return nil, err
+		}
+		masterGTIDSet, err := repo.GetMasterGTIDExecuted(rs.GetMasterHost(), rs.GetMasterPort())
+		if err != nil {
+			// the unreachable master is already scored by the replication thread item
+			log.Errorf("healthcheck ReplicationGTIDGapItem.Check(): get gtid executed of the master failed. %s, error:\n%+v", getReplicationChannelDesc(rs), err)
+			advices = append(advices, fmt.Sprintf("%s: could not get the gtid executed of the master", getReplicationChannelDesc(rs)))
+			continue
+		}
+		missingGTIDs, err := repo.GetGTIDSubtract(masterGTIDSet, replicaGTIDSet)
+		if err != nil {
+			return nil, err
+		}
+		// a multi-source replica contains the transactions of the other masters,
+		// so the errant transactions could only be detected when there is only one replication channel
+		var errantGTIDs string
+		if len(replicationStatuses) == 1 {
+			errantGTIDs, err = repo.GetGTIDSubtract(replicaGTIDSet, masterGTIDSet)
+			if err != nil {
+				return nil, err
+			}
+		}
+		missingCount, err := countGTIDSet(missingGTIDs)
+		if err != nil {
+			return nil, err
+		}
+		errantCount, err := countGTIDSet(errantGTIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		gaps = append(gaps, &GTIDGap{
+			ChannelName:  rs.GetChannelName(),
+			MasterHost:   rs.GetMasterHost(),
+			MasterPort:   rs.GetMasterPort(),
+			MissingGTIDs: missingGTIDs,
+			MissingCount: missingCount,
+			ErrantGTIDs:  errantGTIDs,
+			ErrantCount:  errantCount,
+		})
+		datas = append(datas, NewReplicationData(getReplicationChannelDesc(rs), float64(missingCount+errantCount), constant.EmptyString))
+		if errantCount > constant.ZeroInt {
+			advices = append(advices, fmt.Sprintf("%s: errant transactions found on the replica: %s", getReplicationChannelDesc(rs), errantGTIDs))
+		}
+	}
+
+	cfg := env.GetItemConfig(rggi.GetName())
+	score, highDatas := scoreReplicationDatas(cfg, datas)
+
+	jsonBytesTotal, err := json.Marshal(gaps)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highDatas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return NewItemResultWithEnv(env, rggi, score, string(jsonBytesTotal), string(jsonBytesHigh), strings.Join(advices, constant.CommaString)), nil
+}
+
+// MGRMemberStateItem checks the member states of the group replication,
+// the value of each member is 0 if it is online, 1 if it is recovering, otherwise, it is 2
+type MGRMemberStateItem struct{}
+
+// NewMGRMemberStateItem returns a new *MGRMemberStateItem
+func NewMGRMemberStateItem() *MGRMemberStateItem {
+	return &MGRMemberStateItem{}
+}
+
+// GetName returns the item name
+func (mmsi *MGRMemberStateItem) GetName() string {
+	return defaultMGRMemberStateItemName
+}
+
+// GetDataSource returns the data source of the item
+func (mmsi *MGRMemberStateItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the member states of the group replication
+func (mmsi *MGRMemberStateItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	groupMembers, err := env.GetApplicationMySQLRepo().GetGroupMembers()
+	if err != nil {
+		return nil, err
+	}
+	if len(groupMembers) == constant.ZeroInt {
+		return NewSkippedItemResultWithEnv(env, mmsi, mgrNotMemberMessage), nil
+	}
+
+	var (
+		datas   []*ReplicationData
+		advices []string
+	)
+	for _, member := range groupMembers {
+		name := fmt.Sprintf("%s:%d", member.GetHostIP(), member.GetPortNum())
+		value := getMGRMemberStateValue(member.GetState())
+		if value != mgrMemberStateValueOnline {
+			advices = append(advices, fmt.Sprintf("member %s is %s", name, member.GetState()))
+		}
+		datas = append(datas, NewReplicationData(name, float64(value), member.GetState()))
+	}
+
+	return newReplicationItemResult(env, mmsi, datas, strings.Join(advices, constant.CommaString))
+}
+
+// newReplicationItemResult scores the replication datas and returns the item result
+func newReplicationItemResult(env healthcheck.CheckEnv, item healthcheck.CheckItem, datas []*ReplicationData, advice string) (healthcheck.ItemResult, error) {
+	cfg := env.GetItemConfig(item.GetName())
+	score, highDatas := scoreReplicationDatas(cfg, datas)
+
+	jsonBytesTotal, err := json.Marshal(datas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highDatas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return NewItemResultWithEnv(env, item, score, string(jsonBytesTotal), string(jsonBytesHigh), advice), nil
+}
+
+// scoreReplicationDatas calculates the score of the replication datas with the watermarks of the item config,
+// it returns the score and the datas which are larger than the high watermark
+func scoreReplicationDatas(cfg healthcheck.ItemConfig, datas []*ReplicationData) (int, []*ReplicationData) {
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		highDatas []*ReplicationData
+	)
+
+	for _, data := range datas {
+		switch {
+		case data.Value >= cfg.GetHighWatermark():
+			highDatas = append(highDatas, data)
+			highSum += data.Value
+			highCount++
+		case data.Value >= cfg.GetLowWatermark():
+			mediumSum += data.Value
+			mediumCount++
+		}
+	}
+
+	return calculateScore(cfg, highSum, highCount, mediumSum, mediumCount), highDatas
+}
+
+// getReplicationChannelDesc returns the description of the replication channel
+func getReplicationChannelDesc(rs healthcheck.ReplicationStatus) string {
+	return fmt.Sprintf("channel(%s) from %s:%d", rs.GetChannelName(), rs.GetMasterHost(), rs.GetMasterPort())
+}
+
+// getMGRMemberStateValue returns the value of the member state
+func getMGRMemberStateValue(state string) int {
+	switch strings.ToUpper(state) {
+	case mgrMemberStateOnline:
+		return mgrMemberStateValueOnline
+	case mgrMemberStateRecovering:
+		return mgrMemberStateValueRecovering
+	default:
+		return mgrMemberStateValueAbnormal
+	}
+}
+
+// countGTIDSet returns the number of the transactions in the gtid set,
+// the gtid set looks like: 3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5:7,4E11FA47-71CA-11E1-9E33-C80AA9429562:1,
+// since mysql 8.3, the intervals may be grouped by tags, e.g. 3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5:tag1:1-3:7
+func countGTIDSet(gtidSet string) (int, error) {
+	// the gtid set returned by mysql may contain new lines
+	gtidSet = strings.NewReplacer(constant.CRLFString, constant.EmptyString, constant.LFString, constant.EmptyString).Replace(gtidSet)
+
+	var count int
+	for _, uuidSet := range strings.Split(gtidSet, constant.CommaString) {
+		uuidSet = strings.TrimSpace(uuidSet)
+		if uuidSet == constant.EmptyString {
+			continue
+		}
+		intervals := strings.Split(uuidSet, constant.ColonString)
+		if len(intervals) < 2 {
+			return constant.ZeroInt, errors.Errorf("invalid gtid set: %s", gtidSet)
+		}
+		// the first part is the uuid of the server
+		for _, interval := range intervals[1:] {
+			if isGTIDTag(interval) {
+				// the tag only groups the following intervals
+				continue
+			}
+			bounds := strings.Split(interval, gtidRangeSeparator)
+			start, err := strconv.Atoi(bounds[constant.ZeroInt])
+			if err != nil {
+				return constant.ZeroInt, errors.Trace(err)
+			}
+			end := start
+			if len(bounds) > 1 {
+				end, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return constant.ZeroInt, errors.Trace(err)
+				}
+			}
+			if end < start {
+				return constant.ZeroInt, errors.Errorf("invalid gtid interval: %s", interval)
+			}
+			count += end - start + 1
+		}
+	}
+
+	return count, nil
+}
+
+// isGTIDTag returns if given part of the uuid set is a tag, the tag starts with a letter or an underscore
+func isGTIDTag(part string) bool {
+	if part == constant.EmptyString {
+		return false
+	}
+
+	return part[constant.ZeroInt] == gtidTagUnderscore || unicode.IsLetter(rune(part[constant.ZeroInt]))
+}
@@ -0,0 +1,84 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/go-util/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testGTIDSetMaster       = "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10:12,4e11fa47-71ca-11e1-9e33-c80aa9429562:1-3"
+	testGTIDSetReplica      = "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5,4e11fa47-71ca-11e1-9e33-c80aa9429562:1-3"
+	testGTIDSetMasterCount  = 14
+	testGTIDSetMissingCount = 6
+)
+
+func TestReplicationItem_All(t *testing.T) {
+	TestReplicationItem_countGTIDSet(t)
+	TestReplicationItem_getMGRMemberStateValue(t)
+	TestReplicationItem_scoreReplicationDatas(t)
+	TestReplicationItem_ReplicationStatus(t)
+}
+
+func TestReplicationItem_countGTIDSet(t *testing.T) {
+	asst := assert.New(t)
+
+	count, err := countGTIDSet(testGTIDSetMaster)
+	asst.Nil(err, common.CombineMessageWithError("test countGTIDSet() failed", err))
+	asst.Equal(testGTIDSetMasterCount, count, "test countGTIDSet() failed")
+	// mysql returns the gtid set with new lines
+	count, err = countGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10:12,\n4e11fa47-71ca-11e1-9e33-c80aa9429562:1-3")
+	asst.Nil(err, common.CombineMessageWithError("test countGTIDSet() failed", err))
+	asst.Equal(testGTIDSetMasterCount, count, "test countGTIDSet() failed")
+	// tagged gtid set since mysql 8.3
+	count, err = countGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:tag_1:1-5:12,4e11fa47-71ca-11e1-9e33-c80aa9429562:_tag2:1-3")
+	asst.Nil(err, common.CombineMessageWithError("test countGTIDSet() failed", err))
+	asst.Equal(testGTIDSetMasterCount, count, "test countGTIDSet() failed")
+	// empty gtid set
+	count, err = countGTIDSet("")
+	asst.Nil(err, common.CombineMessageWithError("test countGTIDSet() failed", err))
+	asst.Equal(0, count, "test countGTIDSet() failed")
+	// invalid gtid set
+	_, err = countGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562")
+	asst.NotNil(err, "test countGTIDSet() failed")
+	_, err = countGTIDSet("3e11fa47-71ca-11e1-9e33-c80aa9429562:10-1")
+	asst.NotNil(err, "test countGTIDSet() failed")
+}
+
+func TestReplicationItem_getMGRMemberStateValue(t *testing.T) {
+	asst := assert.New(t)
+
+	asst.Equal(mgrMemberStateValueOnline, getMGRMemberStateValue("ONLINE"), "test getMGRMemberStateValue() failed")
+	asst.Equal(mgrMemberStateValueRecovering, getMGRMemberStateValue("recovering"), "test getMGRMemberStateValue() failed")
+	asst.Equal(mgrMemberStateValueAbnormal, getMGRMemberStateValue("UNREACHABLE"), "test getMGRMemberStateValue() failed")
+	asst.Equal(mgrMemberStateValueAbnormal, getMGRMemberStateValue("ERROR"), "test getMGRMemberStateValue() failed")
+}
+
+func TestReplicationItem_scoreReplicationDatas(t *testing.T) {
+	asst := assert.New(t)
+
+	cfg := NewDefaultItemConfig(defaultReplicationLagItemName, 5, 60, 300, 60, 20, 100, 10, 50)
+	// no data
+	score, highDatas := scoreReplicationDatas(cfg, nil)
+	asst.Equal(int(defaultMaxScore), score, "test scoreReplicationDatas() failed")
+	asst.Equal(0, len(highDatas), "test scoreReplicationDatas() failed")
+	// medium lag, deduct (120 - 60) / 60 * 10 = 10
+	score, highDatas = scoreReplicationDatas(cfg, []*ReplicationData{NewReplicationData("channel", 120, "")})
+	asst.Equal(90, score, "test scoreReplicationDatas() failed")
+	asst.Equal(0, len(highDatas), "test scoreReplicationDatas() failed")
+	// high lag, deduct (600 - 300) / 60 * 20 = 100
+	score, highDatas = scoreReplicationDatas(cfg, []*ReplicationData{NewReplicationData("channel", 600, "")})
+	asst.Equal(defaultMinScore, score, "test scoreReplicationDatas() failed")
+	asst.Equal(1, len(highDatas), "test scoreReplicationDatas() failed")
+}
+
+func TestReplicationItem_ReplicationStatus(t *testing.T) {
+	asst := assert.New(t)
+
+	rs := NewReplicationStatus("", "192.168.137.11", 3306, "Yes", "No", replicationUnknownLag, "", "error")
+	asst.True(rs.IsIORunning(), "test IsIORunning() failed")
+	asst.False(rs.IsSQLRunning(), "test IsSQLRunning() failed")
+	asst.Equal(replicationUnknownLag, rs.GetSecondsBehindMaster(), "test GetSecondsBehindMaster() failed")
+	asst.Equal("channel() from 192.168.137.11:3306", getReplicationChannelDesc(rs), "test getReplicationChannelDesc() failed")
+}
@@ -0,0 +1,540 @@
+package healthcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/query"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	ReportFormatHTML     = "html"
+	ReportFormatMarkdown = "markdown"
+
+	defaultReportTitle = "DAS Healthcheck Report"
+
+	reportItemStatusOK      = "ok"
+	reportItemStatusSkipped = "skipped"
+	reportItemStatusError   = "error"
+	reportJSONNull          = "null"
+)
+
+var _ healthcheck.Report = (*Report)(nil)
+
+// reportMarkdownTemplate is the template of the markdown report
+const reportMarkdownTemplate = `# {{ .Title }}
+
+|operation id|host|apps|start time|end time|step|check time|score|
+|:-----------|:---|:---|:---------|:-------|:---|:---------|----:|
+|{{ .OperationID }}|{{ .HostIP }}:{{ .PortNum }}|{{ md .AppName }}|{{ .StartTime }}|{{ .EndTime }}|{{ .Step }}|{{ .CheckTime }}|{{ .Score }}|
+
+## Summary
+
+|item|status|weight|score|message|
+|:---|:-----|-----:|----:|:------|
+{{- range .Items }}
+|{{ .Name }}|{{ .Status }}|{{ .Weight }}|{{ .Score }}|{{ md .Message }}|
+{{- end }}
+{{ range .Items }}
+## {{ .Name }}
+
+- status: {{ .Status }}
+- score: {{ .Score }}
+{{- if .Message }}
+- message: {{ .Message }}
+{{- end }}
+{{- if .HighData }}
+
+|time|value|
+|:---|----:|
+{{- range .HighData }}
+|{{ .Timestamp }}|{{ .Value }}|
+{{- end }}
+{{- end }}
+{{- if .HighTables }}
+
+|db name|table name|rows|size(GB)|
+|:------|:---------|---:|-------:|
+{{- range .HighTables }}
+|{{ .DBName }}|{{ .TableName }}|{{ .TableRows }}|{{ .TableSize }}|
+{{- end }}
+{{- end }}
+{{- if .Variables }}
+
+|variable|value|advice|
+|:-------|:----|:-----|
+{{- range .Variables }}
+|{{ .Name }}|{{ md .Value }}|{{ md .Advice }}|
+{{- end }}
+{{- end }}
+{{- if .SlowQueries }}
+
+|sql id|db name|exec count|avg exec time|max rows examined|fingerprint|
+|:-----|:------|---------:|------------:|----------------:|:----------|
+{{- range .SlowQueries }}
+|{{ .SQLID }}|{{ .DBName }}|{{ .ExecCount }}|{{ .AvgExecTime }}|{{ .RowsExaminedMax }}|{{ md .Fingerprint }}|
+{{- end }}
+{{- end }}
+{{- if .DiskForecasts }}
+
+|mount point|size(GB)|used(GB)|growth per day(GB)|days to full|projected usage|
+|:----------|-------:|-------:|-----------------:|-----------:|--------------:|
+{{- range .DiskForecasts }}
+|{{ .MountPoint }}|{{ printf "%.2f" .Size }}|{{ printf "%.2f" .Used }}|{{ printf "%.2f" .GrowthPerDay }}|{{ printf "%.1f" .DaysToFull }}|{{ printf "%.2f" .Value }}|
+{{- end }}
+{{- end }}
+{{- if .Securities }}
+
+|account|value|issues|suggestions|
+|:------|----:|:-----|:----------|
+{{- range .Securities }}
+|{{ md .Name }}|{{ .Value }}|{{ range $i, $issue := .Issues }}{{ if $i }}<br>{{ end }}{{ md $issue }}{{ end }}|{{ range $i, $suggestion := .Suggestions }}{{ if $i }}<br>{{ end }}` + "`" + `{{ md $suggestion }}` + "`" + `{{ end }}|
+{{- end }}
+{{- end }}
+{{- range .SQLAdvices }}
+
+### advice of {{ .ID }} (score: {{ .Score }})
+
+` + "```sql" + `
+{{ .Sample }}
+` + "```" + `
+{{ range .HeuristicRules }}
+- [{{ .Severity }}] {{ .Item }}: {{ .Summary }}
+{{- end }}
+{{- range .IndexRules }}
+- [{{ .Severity }}] {{ .Item }}: {{ .Summary }}, {{ .Content }}
+{{- if .Case }}
+  ` + "`" + `{{ .Case }}` + "`" + `
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .RawHigh }}
+
+` + "```json" + `
+{{ .RawHigh }}
+` + "```" + `
+{{- end }}
+{{- if .RawAdvice }}
+
+` + "```" + `
+{{ .RawAdvice }}
+` + "```" + `
+{{- end }}
+{{ end }}`
+
+// reportHTMLTemplate is the template of the html report
+const reportHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: Arial, Helvetica, sans-serif; font-size: 14px; }
+table { border-collapse: collapse; margin: 8px 0; }
+th, td { border: 1px solid #999999; padding: 4px 8px; text-align: left; }
+th { background-color: #eeeeee; }
+pre { background-color: #f6f6f6; padding: 8px; white-space: pre-wrap; }
+.ok { color: #008000; }
+.skipped { color: #808080; }
+.error { color: #ff0000; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<table>
+<tr><th>operation id</th><th>host</th><th>apps</th><th>start time</th><th>end time</th><th>step</th><th>check time</th><th>score</th></tr>
+<tr><td>{{ .OperationID }}</td><td>{{ .HostIP }}:{{ .PortNum }}</td><td>{{ .AppName }}</td><td>{{ .StartTime }}</td><td>{{ .EndTime }}</td><td>{{ .Step }}</td><td>{{ .CheckTime }}</td><td>{{ .Score }}</td></tr>
+</table>
+<h2>Summary</h2>
+<table>
+<tr><th>item</th><th>status</th><th>weight</th><th>score</th><th>message</th></tr>
+{{- range .Items }}
+<tr><td>{{ .Name }}</td><td class="{{ .Status }}">{{ .Status }}</td><td>{{ .Weight }}</td><td>{{ .Score }}</td><td>{{ .Message }}</td></tr>
+{{- end }}
+</table>
+{{- range .Items }}
+<h2>{{ .Name }}</h2>
+<ul>
+<li>status: <span class="{{ .Status }}">{{ .Status }}</span></li>
+<li>score: {{ .Score }}</li>
+{{- if .Message }}
+<li>message: {{ .Message }}</li>
+{{- end }}
+</ul>
+{{- if .HighData }}
+<table>
+<tr><th>time</th><th>value</th></tr>
+{{- range .HighData }}
+<tr><td>{{ .Timestamp }}</td><td>{{ .Value }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .HighTables }}
+<table>
+<tr><th>db name</th><th>table name</th><th>rows</th><th>size(GB)</th></tr>
+{{- range .HighTables }}
+<tr><td>{{ .DBName }}</td><td>{{ .TableName }}</td><td>{{ .TableRows }}</td><td>{{ .TableSize }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Variables }}
+<table>
+<tr><th>variable</th><th>value</th><th>advice</th></tr>
+{{- range .Variables }}
+<tr><td>{{ .Name }}</td><td>{{ .Value }}</td><td>{{ .Advice }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .SlowQueries }}
+<table>
+<tr><th>sql id</th><th>db name</th><th>exec count</th><th>avg exec time</th><th>max rows examined</th><th>fingerprint</th></tr>
+{{- range .SlowQueries }}
+<tr><td>{{ .SQLID }}</td><td>{{ .DBName }}</td><td>{{ .ExecCount }}</td><td>{{ .AvgExecTime }}</td><td>{{ .RowsExaminedMax }}</td><td>{{ .Fingerprint }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .DiskForecasts }}
+<table>
+<tr><th>mount point</th><th>size(GB)</th><th>used(GB)</th><th>growth per day(GB)</th><th>days to full</th><th>projected usage</th></tr>
+{{- range .DiskForecasts }}
+<tr><td>{{ .MountPoint }}</td><td>{{ printf "%.2f" .Size }}</td><td>{{ printf "%.2f" .Used }}</td><td>{{ printf "%.2f" .GrowthPerDay }}</td><td>{{ printf "%.1f" .DaysToFull }}</td><td>{{ printf "%.2f" .Value }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Securities }}
+<table>
+<tr><th>account</th><th>value</th><th>issues</th><th>suggestions</th></tr>
+{{- range .Securities }}
+<tr><td>{{ .Name }}</td><td>{{ .Value }}</td><td>{{ range $i, $issue := .Issues }}{{ if $i }}<br>{{ end }}{{ $issue }}{{ end }}</td><td>{{ range $i, $suggestion := .Suggestions }}{{ if $i }}<br>{{ end }}<code>{{ $suggestion }}</code>{{ end }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- range .SQLAdvices }}
+<h3>advice of {{ .ID }} (score: {{ .Score }})</h3>
+<pre>{{ .Sample }}</pre>
+<ul>
+{{- range .HeuristicRules }}
+<li>[{{ .Severity }}] {{ .Item }}: {{ .Summary }}</li>
+{{- end }}
+{{- range .IndexRules }}
+<li>[{{ .Severity }}] {{ .Item }}: {{ .Summary }}, {{ .Content }}{{ if .Case }}<pre>{{ .Case }}</pre>{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- if .RawHigh }}
+<pre>{{ .RawHigh }}</pre>
+{{- end }}
+{{- if .RawAdvice }}
+<pre>{{ .RawAdvice }}</pre>
+{{- end }}
+{{- end }}
+</body>
+</html>
+`
+
+var (
+	reportMarkdown = template.Must(template.New(ReportFormatMarkdown).Funcs(template.FuncMap{"md": escapeMarkdown}).Parse(reportMarkdownTemplate))
+	reportHTML     = htmltemplate.Must(htmltemplate.New(ReportFormatHTML).Parse(reportHTMLTemplate))
+)
+
+// escapeMarkdown escapes the string so that it could be put into a cell of the markdown table
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, constant.CRLFString, constant.SpaceString)
+
+	return s
+}
+
+// sqlAdviceRule is a rule of the soar report
+type sqlAdviceRule struct {
+	Item     string `json:"Item"`
+	Severity string `json:"Severity"`
+	Summary  string `json:"Summary"`
+	Content  string `json:"Content"`
+	Case     string `json:"Case"`
+}
+
+// sqlAdvice is the soar report of a slow query
+type sqlAdvice struct {
+	ID             string           `json:"ID"`
+	Fingerprint    string           `json:"Fingerprint"`
+	Score          int              `json:"Score"`
+	Sample         string           `json:"Sample"`
+	HeuristicRules []*sqlAdviceRule `json:"HeuristicRules"`
+	IndexRules     []*sqlAdviceRule `json:"IndexRules"`
+}
+
+// reportItem is the view of an item result in the report
+type reportItem struct {
+	Name          string
+	Status        string
+	Weight        int
+	Score         int
+	Message       string
+	HighData      []*PrometheusData
+	HighTables    []*Table
+	Variables     []*Variable
+	SlowQueries   []*query.Query
+	SQLAdvices    []*sqlAdvice
+	Securities    []*SecurityData
+	DiskForecasts []*DiskForecast
+	RawHigh       string
+	RawAdvice     string
+}
+
+// reportData is the view of the whole report
+type reportData struct {
+	Title       string
+	OperationID int
+	HostIP      string
+	PortNum     int
+	AppName     string
+	StartTime   string
+	EndTime     string
+	Step        string
+	CheckTime   string
+	Score       int
+	Items       []*reportItem
+}
+
+// Report renders the result of a healthcheck operation as a readable document
+type Report struct {
+	operationInfo healthcheck.OperationInfo
+	result        healthcheck.Result
+}
+
+// NewReport returns a new *Report
+func NewReport(operationInfo healthcheck.OperationInfo, result healthcheck.Result) *Report {
+	return &Report{
+		operationInfo: operationInfo,
+		result:        result,
+	}
+}
+
+// GetOperationInfo returns the operation information
+func (r *Report) GetOperationInfo() healthcheck.OperationInfo {
+	return r.operationInfo
+}
+
+// GetResult returns the result
+func (r *Report) GetResult() healthcheck.Result {
+	return r.result
+}
+
+// HTML renders the report as a html document
+func (r *Report) HTML() (string, error) {
+	buffer := &bytes.Buffer{}
+	err := reportHTML.Execute(buffer, r.getData())
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+
+	return buffer.String(), nil
+}
+
+// Markdown renders the report as a markdown document
+func (r *Report) Markdown() (string, error) {
+	buffer := &bytes.Buffer{}
+	err := reportMarkdown.Execute(buffer, r.getData())
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+
+	return buffer.String(), nil
+}
+
+// Render renders the report with given format, the format could be html or markdown
+func (r *Report) Render(format string) (string, error) {
+	switch format {
+	case ReportFormatHTML:
+		return r.HTML()
+	case ReportFormatMarkdown:
+		return r.Markdown()
+	default:
+		return constant.EmptyString, errors.Errorf("healthcheck: report format must be either %s or %s. format: %s", ReportFormatHTML, ReportFormatMarkdown, format)
+	}
+}
+
+// getData returns the view of the report
+func (r *Report) getData() *reportData {
+	data := &reportData{
+		Title:       defaultReportTitle,
+		OperationID: r.GetResult().GetOperationID(),
+		HostIP:      r.GetResult().GetHostIP(),
+		PortNum:     r.GetResult().GetPortNum(),
+		CheckTime:   r.GetResult().GetCreateTime().Format(constant.TimeLayoutSecond),
+		Score:       r.GetResult().GetWeightedAverageScore(),
+	}
+	if r.GetOperationInfo() != nil {
+		data.AppName = r.GetOperationInfo().GetAppName()
+		data.StartTime = r.GetOperationInfo().GetStartTime().Format(constant.TimeLayoutSecond)
+		data.EndTime = r.GetOperationInfo().GetEndTime().Format(constant.TimeLayoutSecond)
+		data.Step = r.GetOperationInfo().GetStep().String()
+	}
+
+	for _, itemResult := range getReportItemResults(r.GetResult()) {
+		data.Items = append(data.Items, newReportItem(itemResult))
+	}
+
+	return data
+}
+
+// getReportItemResults returns the item results of the result,
+// the results which were saved before the item results were introduced only have the built-in item columns
+func getReportItemResults(result healthcheck.Result) []healthcheck.ItemResult {
+	if len(result.GetItemResults()) > constant.ZeroInt {
+		return result.GetItemResults()
+	}
+
+	operationID := result.GetOperationID()
+	legacyItemResults := []*ItemResult{
+		NewItemResult(operationID, defaultDBConfigItemName, DataSourceApplicationMySQL, constant.ZeroInt, result.GetDBConfigScore(),
+			result.GetDBConfigData(), constant.EmptyString, result.GetDBConfigAdvice()),
+		NewItemResult(operationID, defaultAvgBackupFailedRatioItemName, DataSourcePrometheus, constant.ZeroInt, result.GetAvgBackupFailedRatioScore(),
+			result.GetAvgBackupFailedRatioData(), result.GetAvgBackupFailedRatioHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultStatisticFailedRatioItemName, DataSourcePrometheus, constant.ZeroInt, result.GetStatisticFailedRatioScore(),
+			result.GetStatisticFailedRatioData(), result.GetStatisticFailedRatioHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultCPUUsageItemName, DataSourcePrometheus, constant.ZeroInt, result.GetCPUUsageScore(),
+			result.GetCPUUsageData(), result.GetCPUUsageHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultIOUtilItemName, DataSourcePrometheus, constant.ZeroInt, result.GetIOUtilScore(),
+			result.GetIOUtilData(), result.GetIOUtilHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultDiskCapacityUsageItemName, DataSourcePrometheus, constant.ZeroInt, result.GetDiskCapacityUsageScore(),
+			result.GetDiskCapacityUsageData(), result.GetDiskCapacityUsageHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultConnectionUsageItemName, DataSourcePrometheus, constant.ZeroInt, result.GetConnectionUsageScore(),
+			result.GetConnectionUsageData(), result.GetConnectionUsageHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultAverageActiveSessionPercentsItemName, DataSourcePrometheus, constant.ZeroInt, result.GetAverageActiveSessionPercentsScore(),
+			result.GetAverageActiveSessionPercentsData(), result.GetAverageActiveSessionPercentsHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultCacheMissRatioItemName, DataSourcePrometheus, constant.ZeroInt, result.GetCacheMissRatioScore(),
+			result.GetCacheMissRatioData(), result.GetCacheMissRatioHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultTableRowsItemName, DataSourceApplicationMySQL, constant.ZeroInt, result.GetTableRowsScore(),
+			result.GetTableRowsData(), result.GetTableRowsHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultTableSizeItemName, DataSourceApplicationMySQL, constant.ZeroInt, result.GetTableSizeScore(),
+			result.GetTableSizeData(), result.GetTableSizeHigh(), constant.EmptyString),
+		NewItemResult(operationID, defaultSlowQueryRowsExaminedItemName, DataSourceQuery, constant.ZeroInt, result.GetSlowQueryScore(),
+			result.GetSlowQueryData(), constant.EmptyString, result.GetSlowQueryAdvice()),
+	}
+
+	itemResults := make([]healthcheck.ItemResult, len(legacyItemResults))
+	for i, itemResult := range legacyItemResults {
+		if itemResult.GetScore() == ResultScoreNotChecked {
+			// the reason why the item was not checked is only saved in t_hc_item_result
+			itemResult.Score = constant.ZeroInt
+			itemResult.Status = ItemStatusSkipped
+		}
+		itemResults[i] = itemResult
+	}
+
+	return itemResults
+}
+
+// newReportItem returns the view of given item result,
+// the data of the built-in items are rendered as tables, the data which could not be parsed are rendered as they are
+func newReportItem(itemResult healthcheck.ItemResult) *reportItem {
+	item := &reportItem{
+		Name:    itemResult.GetItemName(),
+		Status:  getReportItemStatus(itemResult.GetStatus()),
+		Weight:  itemResult.GetItemWeight(),
+		Score:   itemResult.GetScore(),
+		Message: itemResult.GetMessage(),
+	}
+	if !itemResult.IsOK() {
+		return item
+	}
+
+	switch itemResult.GetItemName() {
+	case defaultDBConfigItemName:
+		if !unmarshalReportData(itemResult.GetAdvice(), &item.Variables) {
+			item.RawAdvice = itemResult.GetAdvice()
+		}
+	case defaultTableRowsItemName, defaultTableSizeItemName:
+		if !unmarshalReportData(itemResult.GetHigh(), &item.HighTables) {
+			item.RawHigh = itemResult.GetHigh()
+		}
+	case defaultSlowQueryRowsExaminedItemName:
+		// the data of the slow queries may be huge, so it is not rendered as it is when it could not be parsed
+		unmarshalReportData(itemResult.GetData(), &item.SlowQueries)
+		item.SQLAdvices, item.RawAdvice = parseSQLAdvices(itemResult.GetAdvice())
+	case defaultSecurityItemName:
+		// all the accounts which have security issues are listed, not only the high ones
+		if !unmarshalReportData(itemResult.GetData(), &item.Securities) {
+			item.RawHigh = itemResult.GetHigh()
+			item.RawAdvice = itemResult.GetAdvice()
+		}
+	case defaultDiskCapacityForecastItemName:
+		// all the mount points are listed, so the ones which will be full soon could be compared with the others
+		if !unmarshalReportData(itemResult.GetData(), &item.DiskForecasts) {
+			item.RawHigh = itemResult.GetHigh()
+		}
+		item.RawAdvice = itemResult.GetAdvice()
+	default:
+		if itemResult.GetDataSource() != DataSourcePrometheus || !unmarshalReportData(itemResult.GetHigh(), &item.HighData) {
+			// the high data of the other items may be in any format
+			item.RawHigh = itemResult.GetHigh()
+		}
+		item.RawAdvice = itemResult.GetAdvice()
+	}
+	if item.RawHigh == reportJSONNull || item.RawHigh == defaultEmptyJSONArray {
+		item.RawHigh = constant.EmptyString
+	}
+
+	return item
+}
+
+// getReportItemStatus returns the readable status of the item
+func getReportItemStatus(status int) string {
+	switch status {
+	case ItemStatusSkipped:
+		return reportItemStatusSkipped
+	case ItemStatusError:
+		return reportItemStatusError
+	default:
+		return reportItemStatusOK
+	}
+}
+
+// unmarshalReportData unmarshals the json data of the item result, empty data is ignored,
+// it returns false if the data is not a valid json
+func unmarshalReportData(data string, v interface{}) bool {
+	if data == constant.EmptyString || data == reportJSONNull {
+		return true
+	}
+
+	return json.Unmarshal([]byte(data), v) == nil
+}
+
+// parseSQLAdvices parses the advices of the slow queries, the advices are concatenated with comma,
+// each of them is either a soar report or the slow query itself if the db of the slow query is unknown,
+// it returns the advices which could not be parsed as the soar report in raw string
+func parseSQLAdvices(advice string) ([]*sqlAdvice, string) {
+	if advice == constant.EmptyString {
+		return nil, constant.EmptyString
+	}
+
+	var rawAdvices []json.RawMessage
+	err := json.Unmarshal([]byte("["+advice+"]"), &rawAdvices)
+	if err != nil {
+		return nil, advice
+	}
+
+	var (
+		sqlAdvices []*sqlAdvice
+		rawList    []string
+	)
+	for _, rawAdvice := range rawAdvices {
+		var reports []*sqlAdvice
+		err = json.Unmarshal(rawAdvice, &reports)
+		if err != nil {
+			rawList = append(rawList, string(rawAdvice))
+			continue
+		}
+		sqlAdvices = append(sqlAdvices, reports...)
+	}
+
+	return sqlAdvices, strings.Join(rawList, constant.CRLFString)
+}
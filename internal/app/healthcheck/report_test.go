@@ -0,0 +1,151 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/romberli/das/internal/app/query"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testReportSQLAdvice = `[{"ID": "B95017DB61875675", "Fingerprint": "select * from t_meta_db_info where create_time<?", "Score": 85, "Sample": "select * from t_meta_db_info where create_time<'2021-01-01'", "Explain": null, "HeuristicRules": [{"Item": "COL.001", "Severity": "L1", "Summary": "select * is not recommended", "Content": "", "Case": "select * from tbl where id=1", "Position": 0}], "IndexRules": [{"Item": "IDX.001", "Severity": "L2", "Summary": "add index on t_meta_db_info", "Content": "add index on create_time", "Case": "ALTER TABLE t_meta_db_info add index idx_create_time (create_time)", "Position": 0}], "Tables": ["das.t_meta_db_info"]}]`
+	testReportRawAdvice = `{"sql_id":"F4F85858E527B409","db_name":""}`
+)
+
+func TestReport_All(t *testing.T) {
+	TestReport_Markdown(t)
+	TestReport_HTML(t)
+	TestReport_Render(t)
+	TestReport_parseSQLAdvices(t)
+}
+
+func testInitReportResult() (*Result, error) {
+	result := NewEmptyResultWithOperationIDAndHostInfo(testResultOperationID, testResultHostIP, testResultPortNum)
+	result.WeightedAverageScore = 75
+
+	variableBytes, err := json.Marshal([]*Variable{NewVariable(dbConfigSyncBinlog, "0", "1")})
+	if err != nil {
+		return nil, err
+	}
+	err = result.addItemResult(NewItemResult(testResultOperationID, defaultDBConfigItemName, DataSourceApplicationMySQL, testItemResultItemWeight,
+		90, defaultEmptyJSONArray, constant.EmptyString, string(variableBytes)))
+	if err != nil {
+		return nil, err
+	}
+	highBytes, err := json.Marshal([]*PrometheusData{{Timestamp: "2022-06-01 10:00:00", Value: 0.95}})
+	if err != nil {
+		return nil, err
+	}
+	err = result.addItemResult(NewItemResult(testResultOperationID, defaultCPUUsageItemName, DataSourcePrometheus, testItemResultItemWeight,
+		60, defaultEmptyJSONArray, string(highBytes), constant.EmptyString))
+	if err != nil {
+		return nil, err
+	}
+	tableBytes, err := json.Marshal([]*Table{{DBName: testResultTrendDBName, TableName: testResultTrendTableName, TableRows: 50000000, TableSize: 40}})
+	if err != nil {
+		return nil, err
+	}
+	err = result.addItemResult(NewItemResult(testResultOperationID, defaultTableRowsItemName, DataSourceApplicationMySQL, testItemResultItemWeight,
+		80, defaultEmptyJSONArray, string(tableBytes), constant.EmptyString))
+	if err != nil {
+		return nil, err
+	}
+	queryBytes, err := json.Marshal([]*query.Query{{SQLID: testResultTrendSQLID, Fingerprint: "select * from t01 where id = ? | ?", RowsExaminedMax: 1000000}})
+	if err != nil {
+		return nil, err
+	}
+	err = result.addItemResult(NewItemResult(testResultOperationID, defaultSlowQueryRowsExaminedItemName, DataSourceQuery, testItemResultItemWeight,
+		70, string(queryBytes), constant.EmptyString, testReportSQLAdvice+constant.CommaString+testReportRawAdvice))
+	if err != nil {
+		return nil, err
+	}
+	securityBytes, err := json.Marshal([]*SecurityData{{Name: "'u01'@'%'", Value: securityIssueValueHigh,
+		Issues: []string{"non-admin account has SUPER privileges"}, Suggestions: []string{"revoke super on *.* from 'u01'@'%';"}}})
+	if err != nil {
+		return nil, err
+	}
+	err = result.addItemResult(NewItemResult(testResultOperationID, defaultSecurityItemName, DataSourceApplicationMySQL, testItemResultItemWeight,
+		50, string(securityBytes), string(securityBytes), constant.EmptyString))
+	if err != nil {
+		return nil, err
+	}
+	err = result.addItemResult(NewItemResult(testResultOperationID, defaultDiskCapacityForecastItemName, DataSourcePrometheus, testItemResultItemWeight,
+		60, testCapacityForecastData, testCapacityForecastData, constant.EmptyString))
+	if err != nil {
+		return nil, err
+	}
+	err = result.addItemResult(&ItemResult{ItemName: defaultIOUtilItemName, Status: ItemStatusError, Message: "io util <error>"})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func TestReport_Markdown(t *testing.T) {
+	asst := assert.New(t)
+
+	result, err := testInitReportResult()
+	asst.Nil(err, common.CombineMessageWithError("test Markdown() failed", err))
+	content, err := NewReport(nil, result).Markdown()
+	asst.Nil(err, common.CombineMessageWithError("test Markdown() failed", err))
+	asst.True(strings.Contains(content, "## "+defaultCPUUsageItemName), "test Markdown() failed")
+	asst.True(strings.Contains(content, "|"+dbConfigSyncBinlog+"|0|1|"), "test Markdown() failed")
+	asst.True(strings.Contains(content, "|"+testResultTrendDBName+"|"+testResultTrendTableName+"|50000000|40|"), "test Markdown() failed")
+	asst.True(strings.Contains(content, `select * from t01 where id = ? \| ?`), "test Markdown() failed")
+	asst.True(strings.Contains(content, "[L2] IDX.001"), "test Markdown() failed")
+	asst.True(strings.Contains(content, testReportRawAdvice), "test Markdown() failed")
+	asst.True(strings.Contains(content, "|'u01'@'%'|1|non-admin account has SUPER privileges|"), "test Markdown() failed")
+	asst.True(strings.Contains(content, "|"+testCapacityMountPoint+"|100.00|60.00|1.00|40.0|0.90|"), "test Markdown() failed")
+	asst.True(strings.Contains(content, "|"+defaultIOUtilItemName+"|"+reportItemStatusError+"|"), "test Markdown() failed")
+
+	// the results which were saved before the item results were introduced
+	content, err = NewReport(nil, testResult).Markdown()
+	asst.Nil(err, common.CombineMessageWithError("test Markdown() failed", err))
+	asst.True(strings.Contains(content, testResultTableRowsHigh), "test Markdown() failed")
+}
+
+func TestReport_HTML(t *testing.T) {
+	asst := assert.New(t)
+
+	result, err := testInitReportResult()
+	asst.Nil(err, common.CombineMessageWithError("test HTML() failed", err))
+	content, err := NewReport(nil, result).HTML()
+	asst.Nil(err, common.CombineMessageWithError("test HTML() failed", err))
+	asst.True(strings.Contains(content, "<h2>"+defaultCPUUsageItemName+"</h2>"), "test HTML() failed")
+	asst.True(strings.Contains(content, "<td>"+testResultTrendTableName+"</td>"), "test HTML() failed")
+	asst.True(strings.Contains(content, "io util &lt;error&gt;"), "test HTML() failed")
+	asst.True(strings.Contains(content, "<td>non-admin account has SUPER privileges</td>"), "test HTML() failed")
+}
+
+func TestReport_Render(t *testing.T) {
+	asst := assert.New(t)
+
+	result, err := testInitReportResult()
+	asst.Nil(err, common.CombineMessageWithError("test Render() failed", err))
+	report := NewReport(nil, result)
+	_, err = report.Render(ReportFormatHTML)
+	asst.Nil(err, common.CombineMessageWithError("test Render() failed", err))
+	_, err = report.Render(ReportFormatMarkdown)
+	asst.Nil(err, common.CombineMessageWithError("test Render() failed", err))
+	_, err = report.Render("pdf")
+	asst.NotNil(err, "test Render() failed")
+}
+
+func TestReport_parseSQLAdvices(t *testing.T) {
+	asst := assert.New(t)
+
+	sqlAdvices, rawAdvice := parseSQLAdvices(testReportSQLAdvice + constant.CommaString + testReportRawAdvice)
+	asst.Equal(1, len(sqlAdvices), "test parseSQLAdvices() failed")
+	asst.Equal("B95017DB61875675", sqlAdvices[constant.ZeroInt].ID, "test parseSQLAdvices() failed")
+	asst.Equal(1, len(sqlAdvices[constant.ZeroInt].IndexRules), "test parseSQLAdvices() failed")
+	asst.Equal(testReportRawAdvice, rawAdvice, "test parseSQLAdvices() failed")
+	// the advice is not a valid json
+	sqlAdvices, rawAdvice = parseSQLAdvices(testResultSlowQueryAdvice)
+	asst.Nil(sqlAdvices, "test parseSQLAdvices() failed")
+	asst.Equal(testResultSlowQueryAdvice, rawAdvice, "test parseSQLAdvices() failed")
+}
@@ -1,17 +1,25 @@
 package healthcheck
 
 import (
+	"context"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/pingcap/errors"
+	"github.com/romberli/das/config"
 	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/app/metadata"
 	"github.com/romberli/das/internal/app/query"
 	"github.com/romberli/das/internal/dependency/healthcheck"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
 	depquery "github.com/romberli/das/internal/dependency/query"
 	"github.com/romberli/das/pkg/message"
 	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-multierror"
 	"github.com/romberli/go-util/common"
 	"github.com/romberli/go-util/constant"
 	"github.com/romberli/go-util/middleware"
@@ -19,10 +27,13 @@ import (
 	"github.com/romberli/go-util/middleware/mysql"
 	"github.com/romberli/go-util/middleware/prometheus"
 	"github.com/romberli/log"
+	"github.com/spf13/viper"
 )
 
 const (
 	mysql57           = "5.7"
+	mysql80           = "8.0"
+	mysql8022         = "8.0.22"
 	performanceSchema = "performance_schema"
 	informationSchema = "information_schema"
 
@@ -35,14 +46,57 @@ const (
 	minTableRows      = 30000000
 	minRowsExamined   = 1
 	SlowQueryNumLimit = 100
+
+	replicationChannelNameColumn         = "Channel_Name"
+	replicationMasterHostColumn          = "Master_Host"
+	replicationMasterPortColumn          = "Master_Port"
+	replicationSlaveIORunningColumn      = "Slave_IO_Running"
+	replicationSlaveSQLRunningColumn     = "Slave_SQL_Running"
+	replicationSecondsBehindMasterColumn = "Seconds_Behind_Master"
+	replicationLastIOErrorColumn         = "Last_IO_Error"
+	replicationLastSQLErrorColumn        = "Last_SQL_Error"
+	replicationSourceHostColumn          = "Source_Host"
+	replicationSourcePortColumn          = "Source_Port"
+	replicationReplicaIORunningColumn    = "Replica_IO_Running"
+	replicationReplicaSQLRunningColumn   = "Replica_SQL_Running"
+	replicationSecondsBehindSourceColumn = "Seconds_Behind_Source"
+	replicationUnknownLag                = -1
+	// the channels of group replication are also shown in the replication status, but they are not asynchronous replication
+	replicationGroupReplicationChannelPrefix = "group_replication_"
+
+	minTransactionDuration       = 1
+	innodbStatusColumn           = "Status"
+	innodbStatusLatestDeadlock   = "LATEST DETECTED DEADLOCK"
+	innodbStatusSectionSeparator = "------------"
+	innodbStatusLockInfoPrefix   = "***"
+
+	grantPasswordMask = "IDENTIFIED BY PASSWORD <secret>"
+)
+
+var (
+	_ healthcheck.DASRepo                  = (*DASRepo)(nil)
+	_ healthcheck.ApplicationMySQLRepo     = (*ApplicationMySQLRepo)(nil)
+	_ healthcheck.PrometheusRepo           = (*PrometheusRepo)(nil)
+	_ healthcheck.MiddlewarePrometheusRepo = (*MiddlewarePrometheusRepo)(nil)
+	_ healthcheck.QueryRepo                = (*MySQLQueryRepo)(nil)
+	_ healthcheck.QueryRepo                = (*ClickhouseQueryRepo)(nil)
+	_ healthcheck.QueryRepo                = (*PerformanceSchemaQueryRepo)(nil)
+	_ healthcheck.QueryRepo                = (*SlowLogQueryRepo)(nil)
 )
 
 var (
-	_ healthcheck.DASRepo              = (*DASRepo)(nil)
-	_ healthcheck.ApplicationMySQLRepo = (*ApplicationMySQLRepo)(nil)
-	_ healthcheck.PrometheusRepo       = (*PrometheusRepo)(nil)
-	_ healthcheck.QueryRepo            = (*MySQLQueryRepo)(nil)
-	_ healthcheck.QueryRepo            = (*ClickhouseQueryRepo)(nil)
+	// the lines of the latest detected deadlock section in the output of show engine innodb status, they look like:
+	// *** (1) TRANSACTION:
+	// TRANSACTION 12345, ACTIVE 5 sec starting index read
+	// MySQL thread id 10, OS thread handle 140, query id 100 localhost root updating
+	// *** WE ROLL BACK TRANSACTION (2)
+	deadlockTrxNumRegexp   = regexp.MustCompile(`^\*\*\* \((\d+)\) TRANSACTION:`)
+	deadlockTrxIDRegexp    = regexp.MustCompile(`^TRANSACTION (\w+),`)
+	deadlockThreadIDRegexp = regexp.MustCompile(`^MySQL thread id (\d+),`)
+	deadlockRollBackRegexp = regexp.MustCompile(`^\*\*\* WE ROLL BACK TRANSACTION \((\d+)\)`)
+	// the grant statements of mysql 5.6 contain the password hashes, they look like:
+	// GRANT USAGE ON *.* TO 'u01'@'%' IDENTIFIED BY PASSWORD '*0D3CED9BEC10A777AEC23CCC353A8C08A633045E'
+	grantPasswordRegexp = regexp.MustCompile(`IDENTIFIED BY PASSWORD '[^']*'`)
 )
 
 // DASRepo for health check
@@ -98,12 +152,17 @@ func (dr *DASRepo) GetHealthCheckHistories(mysqlServerIDList []int, limit int) (
 		select oh.id,
 			   oh.user_id,
 			   ui.account_name,
+			   oh.schedule_id,
+			   oh.cluster_operation_id,
 			   oh.mysql_server_id,
 			   msi.host_ip,
 			   msi.port_num,
 			   oh.start_time,
 			   oh.end_time,
 			   oh.step,
+			   oh.profile_id,
+			   oh.config_version,
+			   oh.priority,
 			   oh.status,
 			   oh.message,
 			   oh.del_flag,
@@ -139,17 +198,75 @@ func (dr *DASRepo) GetHealthCheckHistories(mysqlServerIDList []int, limit int) (
 	return operationHistories, nil
 }
 
-// LoadEngineConfig loads engine config from the middleware
+// GetOperationHistoryByID gets the operation history by the operation id from the middleware
+func (dr *DASRepo) GetOperationHistoryByID(operationID int) (healthcheck.OperationHistory, error) {
+	sql := `
+		select oh.id,
+			   oh.user_id,
+			   ui.account_name,
+			   oh.schedule_id,
+			   oh.cluster_operation_id,
+			   oh.mysql_server_id,
+			   msi.host_ip,
+			   msi.port_num,
+			   oh.start_time,
+			   oh.end_time,
+			   oh.step,
+			   oh.profile_id,
+			   oh.config_version,
+			   oh.priority,
+			   oh.status,
+			   oh.message,
+			   oh.del_flag,
+			   oh.create_time,
+			   oh.last_update_time
+		from t_hc_operation_history oh
+			inner join t_meta_mysql_server_info msi on oh.mysql_server_id = msi.id
+		    inner join t_meta_user_info ui on oh.user_id = ui.id
+		where oh.del_flag = 0
+		  and msi.del_flag = 0
+		  and ui.del_flag = 0
+		  and oh.id = ?;
+	`
+	log.Debugf("healthCheck DASRepo.GetOperationHistoryByID() select sql: \n%s\nplaceholders: %d", sql, operationID)
+	result, err := dr.Execute(sql, operationID)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetOperationHistoryByID(): data does not exists, operation_id: %d", operationID)
+	case 1:
+		operationHistory := NewEmptyOperationHistory()
+		err = result.MapToStructByRowIndex(operationHistory, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+
+		return operationHistory, nil
+	default:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetOperationHistoryByID(): duplicate key exists, operation_id: %d", operationID)
+	}
+}
+
+// LoadEngineConfig loads the global default engine config from the middleware
 func (dr *DASRepo) LoadEngineConfig() (healthcheck.EngineConfig, error) {
+	return dr.LoadEngineConfigByProfileID(defaultScoringProfileID)
+}
+
+// LoadEngineConfigByProfileID loads the engine config of the scoring profile from the middleware,
+// profile id 0 means the global default engine config
+func (dr *DASRepo) LoadEngineConfigByProfileID(profileID int) (healthcheck.EngineConfig, error) {
 	// load config
 	sql := `
 		select id, item_name, item_weight, low_watermark, high_watermark, unit, score_deduction_per_unit_high, max_score_deduction_high,
 		score_deduction_per_unit_medium, max_score_deduction_medium, del_flag, create_time, last_update_time
 		from t_hc_default_engine_config
-		where del_flag = 0;
+		where del_flag = 0
+		  and profile_id = ?;
 	`
-	log.Debugf("healthcheck DASRepo.loadEngineConfig() sql: \n%s\n", sql)
-	result, err := dr.Execute(sql)
+	log.Debugf("healthcheck DASRepo.LoadEngineConfigByProfileID() sql: \n%s\nplaceholders: %d", sql, profileID)
+	result, err := dr.Execute(sql, profileID)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +288,69 @@ func (dr *DASRepo) LoadEngineConfig() (healthcheck.EngineConfig, error) {
 	return engineConfig, nil
 }
 
+// LoadEngineConfigWithVersionByProfileID loads the engine config of the scoring profile and its config version from the middleware,
+// the engine config is loaded from the latest config version if the config was ever changed through the api,
+// so the returned config version always matches the returned engine config even if the config is changed concurrently,
+// it returns 0 as the config version if the config was never changed through the api
+func (dr *DASRepo) LoadEngineConfigWithVersionByProfileID(profileID int) (healthcheck.EngineConfig, int, error) {
+	// the item configs must be loaded before the config version, a change committed in between always saves a new config version,
+	// which takes precedence over the loaded item configs
+	engineConfig, err := dr.LoadEngineConfigByProfileID(profileID)
+	if err != nil {
+		return nil, constant.ZeroInt, err
+	}
+	log.Debugf("healthcheck DASRepo.LoadEngineConfigWithVersionByProfileID() sql: \n%s\nplaceholders: %d", engineConfigLatestVersionQuery, profileID)
+	result, err := dr.Execute(engineConfigLatestVersionQuery, profileID)
+	if err != nil {
+		return nil, constant.ZeroInt, err
+	}
+	engineConfigVersion, err := mapToLatestEngineConfigVersion(result)
+	if err != nil {
+		return nil, constant.ZeroInt, err
+	}
+	if engineConfigVersion == nil {
+		return engineConfig, constant.ZeroInt, nil
+	}
+
+	return newDefaultEngineConfigWithItemConfigs(engineConfigVersion.GetItemConfigs()), engineConfigVersion.GetVersion(), nil
+}
+
+// LoadDBConfigRules loads all the db config rules from the middleware
+func (dr *DASRepo) LoadDBConfigRules() ([]healthcheck.DBConfigRule, error) {
+	return newDBConfigRuleRepo(dr.Database).GetAll()
+}
+
+// GetScoringProfileIDByMySQLClusterID returns the id of the most specific scoring profile of the mysql cluster,
+// the profile assigned to the mysql cluster takes precedence over the one assigned to its resource groups,
+// and the latter takes precedence over the one assigned to its env, it returns 0 if no profile was assigned
+func (dr *DASRepo) GetScoringProfileIDByMySQLClusterID(mysqlClusterID int) (int, error) {
+	sql := `
+		select spa.profile_id
+		from t_hc_scoring_profile_assignment spa
+			inner join t_hc_scoring_profile sp on spa.profile_id = sp.id
+		where spa.del_flag = 0
+		  and sp.del_flag = 0
+		  and ((spa.target_type = 2 and spa.target_id = ?)
+			or (spa.target_type = 3 and spa.target_id in (
+				select resource_group_id from t_meta_mysql_cluster_resource_group_map where del_flag = 0 and mysql_cluster_id = ?))
+			or (spa.target_type = 4 and spa.target_id = (
+				select env_id from t_meta_mysql_cluster_info where del_flag = 0 and id = ?)))
+		order by spa.target_type, spa.profile_id
+		limit 1;
+	`
+	log.Debugf("healthcheck DASRepo.GetScoringProfileIDByMySQLClusterID() sql: \n%s\nplaceholders: %d, %d, %d",
+		sql, mysqlClusterID, mysqlClusterID, mysqlClusterID)
+	result, err := dr.Execute(sql, mysqlClusterID, mysqlClusterID, mysqlClusterID)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	if result.RowNumber() == constant.ZeroInt {
+		return defaultScoringProfileID, nil
+	}
+
+	return result.GetInt(constant.ZeroInt, constant.ZeroInt)
+}
+
 // GetResultByOperationID gets a Result by the operationID from the middleware
 func (dr *DASRepo) GetResultByOperationID(operationID int) (healthcheck.Result, error) {
 	sql := `
@@ -207,6 +387,11 @@ func (dr *DASRepo) GetResultByOperationID(operationID int) (healthcheck.Result,
 		if err != nil {
 			return nil, err
 		}
+		// get item results
+		hcInfo.ItemResults, err = dr.GetItemResultsByOperationID(operationID)
+		if err != nil {
+			return nil, err
+		}
 
 		return hcInfo, nil
 	default:
@@ -214,9 +399,77 @@ func (dr *DASRepo) GetResultByOperationID(operationID int) (healthcheck.Result,
 	}
 }
 
-// IsRunning gets status by the mysqlServerID from the middleware
+// GetResultsByMySQLServerIDs returns the results of given mysql servers which were saved between start time and end time,
+// the results are ordered by the create time
+func (dr *DASRepo) GetResultsByMySQLServerIDs(mysqlServerIDList []int, startTime, endTime time.Time) ([]healthcheck.Result, error) {
+	if len(mysqlServerIDList) == constant.ZeroInt {
+		return []healthcheck.Result{}, nil
+	}
+	msl, err := common.ConvertInterfaceToSliceInterface(mysqlServerIDList)
+	if err != nil {
+		return nil, err
+	}
+	inClause, err := middleware.ConvertSliceToString(msl...)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `
+		select r.id, r.operation_id, r.host_ip, r.port_num, r.weighted_average_score, r.db_config_score, r.db_config_data,
+		r.db_config_advice, r.avg_backup_failed_ratio_score, r.avg_backup_failed_ratio_data,
+		r.avg_backup_failed_ratio_high, r.statistics_failed_ratio_score, r.statistics_failed_ratio_data,
+		r.statistics_failed_ratio_high, r.cpu_usage_score, r.cpu_usage_data, r.cpu_usage_high,
+		r.io_util_score, r.io_util_data, r.io_util_high,
+		r.disk_capacity_usage_score, r.disk_capacity_usage_data, r.disk_capacity_usage_high,
+		r.connection_usage_score, r.connection_usage_data, r.connection_usage_high,
+		r.average_active_session_percents_score, r.average_active_session_percents_data,
+		r.average_active_session_percents_high, r.cache_miss_ratio_score, r.cache_miss_ratio_data,
+		r.cache_miss_ratio_high, r.table_rows_score, r.table_rows_data, r.table_rows_high,
+		r.table_size_score, r.table_size_data, r.table_size_high, r.slow_query_score,
+		r.slow_query_data, r.slow_query_advice, r.accuracy_review, r.del_flag, r.create_time, r.last_update_time
+		from t_hc_result r
+			inner join t_hc_operation_history oh on r.operation_id = oh.id
+		where r.del_flag = 0
+		  and oh.del_flag = 0
+		  and oh.mysql_server_id in (%s)
+		  and r.create_time >= ?
+		  and r.create_time <= ?
+		order by r.create_time, r.id;
+	`
+	sql = fmt.Sprintf(sql, inClause)
+	startTimeStr := startTime.Format(constant.TimeLayoutSecond)
+	endTimeStr := endTime.Format(constant.TimeLayoutSecond)
+	log.Debugf("healthCheck DASRepo.GetResultsByMySQLServerIDs() select sql: \n%s\nplaceholders: %s, %s", sql, startTimeStr, endTimeStr)
+
+	result, err := dr.Execute(sql, startTimeStr, endTimeStr)
+	if err != nil {
+		return nil, err
+	}
+	hcInfos := make([]*Result, result.RowNumber())
+	results := make([]healthcheck.Result, result.RowNumber())
+	for i := range results {
+		hcInfos[i] = NewEmptyResultWithRepo(dr)
+		results[i] = hcInfos[i]
+	}
+	// map to struct
+	err = result.MapToStructSlice(results, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+	// get item results
+	for _, hcInfo := range hcInfos {
+		hcInfo.ItemResults, err = dr.GetItemResultsByOperationID(hcInfo.GetOperationID())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// IsRunning gets status by the mysqlServerID from the middleware, the queued operation is also considered as running
 func (dr *DASRepo) IsRunning(mysqlServerID int) (bool, error) {
-	sql := `select count(1) from t_hc_operation_history where del_flag = 0 and mysql_server_id = ? and status = 1;`
+	sql := `select count(1) from t_hc_operation_history where del_flag = 0 and mysql_server_id = ? and status in (1, 6);`
 	log.Debugf("healthCheck DASRepo.IsRunning() select sql: \n%s\nplaceholders: %d", sql, mysqlServerID)
 
 	result, err := dr.Execute(sql, mysqlServerID)
@@ -228,17 +481,18 @@ func (dr *DASRepo) IsRunning(mysqlServerID int) (bool, error) {
 	return count != constant.ZeroInt, nil
 }
 
-// InitOperation creates a testOperationInfo in the middleware
-func (dr *DASRepo) InitOperation(userID, mysqlServerID int, startTime, endTime time.Time, step time.Duration) (int, error) {
+// InitOperation creates a testOperationInfo in the middleware, scheduleID is 0 if the operation was triggered manually,
+// clusterOperationID is 0 if the operation was not a part of a cluster check
+func (dr *DASRepo) InitOperation(userID, scheduleID, clusterOperationID, mysqlServerID int, startTime, endTime time.Time, step time.Duration) (int, error) {
 	startTimeStr := startTime.Format(constant.TimeLayoutSecond)
 	endTimeStr := endTime.Format(constant.TimeLayoutSecond)
 	stepInt := int(step.Seconds())
 
-	sql := `insert into t_hc_operation_history(user_id, mysql_server_id, start_time, end_time, step) values(?, ?, ?, ?, ?);`
-	log.Debugf("healthCheck DASRepo.InitOperation() insert sql: \n%s\nplaceholders: %d, %d, %s, %s, %d",
-		sql, userID, mysqlServerID, startTimeStr, endTimeStr, stepInt)
+	sql := `insert into t_hc_operation_history(user_id, schedule_id, cluster_operation_id, mysql_server_id, start_time, end_time, step) values(?, ?, ?, ?, ?, ?, ?);`
+	log.Debugf("healthCheck DASRepo.InitOperation() insert sql: \n%s\nplaceholders: %d, %d, %d, %d, %s, %s, %d",
+		sql, userID, scheduleID, clusterOperationID, mysqlServerID, startTimeStr, endTimeStr, stepInt)
 
-	result, err := dr.Execute(sql, userID, mysqlServerID, startTimeStr, endTimeStr, stepInt)
+	result, err := dr.Execute(sql, userID, scheduleID, clusterOperationID, mysqlServerID, startTimeStr, endTimeStr, stepInt)
 	if err != nil {
 		return constant.ZeroInt, err
 	}
@@ -246,6 +500,15 @@ func (dr *DASRepo) InitOperation(userID, mysqlServerID int, startTime, endTime t
 	return result.LastInsertID()
 }
 
+// UpdateOperationEngineConfig updates the id of the scoring profile and the engine config version which were used by the operation in the middleware
+func (dr *DASRepo) UpdateOperationEngineConfig(operationID, profileID, configVersion int) error {
+	sql := `update t_hc_operation_history set profile_id = ?, config_version = ? where id = ?;`
+	log.Debugf("healthCheck DASRepo.UpdateOperationEngineConfig() update sql: \n%s\nplaceholders: %d, %d, %d", sql, profileID, configVersion, operationID)
+	_, err := dr.Execute(sql, profileID, configVersion, operationID)
+
+	return err
+}
+
 // UpdateOperationStatus updates the status and message by the operationID in the middleware
 func (dr *DASRepo) UpdateOperationStatus(operationID int, status int, message string) error {
 	sql := `update t_hc_operation_history set status = ?, message = ? where id = ?;`
@@ -255,8 +518,122 @@ func (dr *DASRepo) UpdateOperationStatus(operationID int, status int, message st
 	return err
 }
 
-// SaveResult saves the result in the middleware
+// CompareAndUpdateOperationStatus updates the status and message by the operationID in the middleware
+// only if the current status is the expected status, it returns false if the current status is not the expected status,
+// e.g. the operation was cancelled by another process
+func (dr *DASRepo) CompareAndUpdateOperationStatus(operationID int, expectedStatus, status int, message string) (bool, error) {
+	sql := `update t_hc_operation_history set status = ?, message = ? where id = ? and status = ?;`
+	log.Debugf("healthCheck DASRepo.CompareAndUpdateOperationStatus() update sql: \n%s\nplaceholders: %d, %s, %d, %d",
+		sql, status, message, operationID, expectedStatus)
+	result, err := dr.Execute(sql, status, message, operationID, expectedStatus)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// QueueOperation marks the operation as queued with given priority in the middleware
+func (dr *DASRepo) QueueOperation(operationID, priority int) error {
+	message := fmt.Sprintf("healthcheck queued. operation_id: %d, priority: %d", operationID, priority)
+	sql := `update t_hc_operation_history set status = 6, priority = ?, message = ? where id = ?;`
+	log.Debugf("healthCheck DASRepo.QueueOperation() update sql: \n%s\nplaceholders: %d, %s, %d", sql, priority, message, operationID)
+	_, err := dr.Execute(sql, priority, message, operationID)
+
+	return err
+}
+
+// StartQueuedOperation marks the queued operation as running in the middleware,
+// it returns false if the operation is not queued any more, e.g. it was cancelled or started by another process
+func (dr *DASRepo) StartQueuedOperation(operationID int) (bool, error) {
+	message := fmt.Sprintf("healthcheck started. operation_id: %d", operationID)
+	sql := `update t_hc_operation_history set status = 1, message = ? where id = ? and status = 6;`
+	log.Debugf("healthCheck DASRepo.StartQueuedOperation() update sql: \n%s\nplaceholders: %s, %d", sql, message, operationID)
+	result, err := dr.Execute(sql, message, operationID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// GetOperationHistoriesByStatus gets the operation histories with given status from the middleware,
+// the operation histories are ordered by the priority descending and then the operation id
+func (dr *DASRepo) GetOperationHistoriesByStatus(status int) ([]healthcheck.OperationHistory, error) {
+	sql := `
+		select oh.id,
+			   oh.user_id,
+			   ui.account_name,
+			   oh.schedule_id,
+			   oh.cluster_operation_id,
+			   oh.mysql_server_id,
+			   msi.host_ip,
+			   msi.port_num,
+			   oh.start_time,
+			   oh.end_time,
+			   oh.step,
+			   oh.profile_id,
+			   oh.config_version,
+			   oh.priority,
+			   oh.status,
+			   oh.message,
+			   oh.del_flag,
+			   oh.create_time,
+			   oh.last_update_time
+		from t_hc_operation_history oh
+			inner join t_meta_mysql_server_info msi on oh.mysql_server_id = msi.id
+		    inner join t_meta_user_info ui on oh.user_id = ui.id
+		where oh.del_flag = 0
+		  and msi.del_flag = 0
+		  and ui.del_flag = 0
+		  and oh.status = ?
+		order by oh.priority desc, oh.id;
+	`
+	log.Debugf("healthCheck DASRepo.GetOperationHistoriesByStatus() select sql: \n%s\nplaceholders: %d", sql, status)
+	result, err := dr.Execute(sql, status)
+	if err != nil {
+		return nil, err
+	}
+
+	operationHistories := make([]healthcheck.OperationHistory, result.RowNumber())
+	for i := range operationHistories {
+		operationHistories[i] = NewEmptyOperationHistory()
+	}
+
+	err = result.MapToStructSlice(operationHistories, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return operationHistories, nil
+}
+
+// SaveResult saves the result and the item results in the middleware as a transaction
 func (dr *DASRepo) SaveResult(result healthcheck.Result) error {
+	tx, err := dr.Transaction()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = tx.Close()
+		if err != nil {
+			log.Errorf("healthcheck DASRepo.SaveResult(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	err = tx.Begin()
+	if err != nil {
+		return err
+	}
+
 	sql := `insert into t_hc_result(operation_id, host_ip, port_num, weighted_average_score, db_config_score, db_config_data,
 		db_config_advice, avg_backup_failed_ratio_score, avg_backup_failed_ratio_data, 
 		avg_backup_failed_ratio_high, statistics_failed_ratio_score, statistics_failed_ratio_data, 
@@ -286,7 +663,7 @@ func (dr *DASRepo) SaveResult(result healthcheck.Result) error {
 		result.GetSlowQueryScore(), result.GetSlowQueryData(), result.GetSlowQueryAdvice(), result.GetAccuracyReview())
 
 	// execute
-	_, err := dr.Execute(sql, result.GetOperationID(), result.GetHostIP(), result.GetPortNum(), result.GetWeightedAverageScore(),
+	_, err = tx.Execute(sql, result.GetOperationID(), result.GetHostIP(), result.GetPortNum(), result.GetWeightedAverageScore(),
 		result.GetDBConfigScore(), result.GetDBConfigData(), result.GetDBConfigAdvice(),
 		result.GetAvgBackupFailedRatioScore(), result.GetAvgBackupFailedRatioData(), result.GetAvgBackupFailedRatioHigh(),
 		result.GetStatisticFailedRatioScore(), result.GetStatisticFailedRatioData(), result.GetStatisticFailedRatioHigh(),
@@ -299,64 +676,588 @@ func (dr *DASRepo) SaveResult(result healthcheck.Result) error {
 		result.GetTableRowsScore(), result.GetTableRowsData(), result.GetTableRowsHigh(),
 		result.GetTableSizeScore(), result.GetTableSizeData(), result.GetTableSizeHigh(),
 		result.GetSlowQueryScore(), result.GetSlowQueryData(), result.GetSlowQueryAdvice(), result.GetAccuracyReview())
+	if err != nil {
+		return dr.rollback(tx, err)
+	}
 
-	return err
+	// save item results
+	sql = `insert into t_hc_item_result(operation_id, item_name, data_source, item_weight, score, status, message, data, high, advice)
+		values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+	for _, itemResult := range result.GetItemResults() {
+		log.Debugf("healthCheck DASRepo.SaveResult() insert sql: \n%s\nplaceholders: %d, %s, %s, %d, %d, %d, %s, %s, %s, %s",
+			sql, itemResult.GetOperationID(), itemResult.GetItemName(), itemResult.GetDataSource(), itemResult.GetItemWeight(),
+			itemResult.GetScore(), itemResult.GetStatus(), itemResult.GetMessage(), itemResult.GetData(), itemResult.GetHigh(), itemResult.GetAdvice())
+		_, err = tx.Execute(sql, itemResult.GetOperationID(), itemResult.GetItemName(), itemResult.GetDataSource(), itemResult.GetItemWeight(),
+			itemResult.GetScore(), itemResult.GetStatus(), itemResult.GetMessage(), itemResult.GetData(), itemResult.GetHigh(), itemResult.GetAdvice())
+		if err != nil {
+			return dr.rollback(tx, err)
+		}
+	}
+
+	return tx.Commit()
 }
 
-// UpdateAccuracyReviewByOperationID updates the accuracyReview by the operationID in the middleware
-func (dr *DASRepo) UpdateAccuracyReviewByOperationID(operationID int, review int) error {
-	sql := `update t_hc_result set accuracy_review = ? where operation_id = ?;`
-	log.Debugf("healthCheck DASRepo.UpdateAccuracyReviewByOperationID() update sql: \n%s\nplaceholders: %s, %s", sql, operationID, review)
+// rollback rollbacks the transaction and returns the original error,
+// the error of the rollback will only be logged
+func (dr *DASRepo) rollback(tx middleware.Transaction, err error) error {
+	rollbackErr := tx.Rollback()
+	if rollbackErr != nil {
+		log.Errorf("healthcheck DASRepo.rollback(): rollback failed.\n%+v", rollbackErr)
+	}
 
-	_, err := dr.Execute(sql, review, operationID)
 	return err
 }
 
-type ApplicationMySQLRepo struct {
-	operationInfo healthcheck.OperationInfo
-	conn          *mysql.Conn
-}
+// GetItemResultsByOperationID gets the results of all the check items by the operationID from the middleware
+func (dr *DASRepo) GetItemResultsByOperationID(operationID int) ([]healthcheck.ItemResult, error) {
+	sql := `
+		select id, operation_id, item_name, data_source, item_weight, score, status, message, data, high, advice,
+		del_flag, create_time, last_update_time
+		from t_hc_item_result
+		where del_flag = 0
+		and operation_id = ?
+		order by id;
+	`
+	log.Debugf("healthCheck DASRepo.GetItemResultsByOperationID() select sql: \n%s\nplaceholders: %d", sql, operationID)
 
-// NewApplicationMySQLRepo returns a new healthcheck.ApplicationMySQLRepo
-func NewApplicationMySQLRepo(operationInfo healthcheck.OperationInfo, conn *mysql.Conn) healthcheck.ApplicationMySQLRepo {
-	return newApplicationMySQLRepo(operationInfo, conn)
-}
+	result, err := dr.Execute(sql, operationID)
+	if err != nil {
+		return nil, err
+	}
 
-// newApplicationMySQLRepo returns a new *ApplicationMySQLRepo
-func newApplicationMySQLRepo(operationInfo healthcheck.OperationInfo, conn *mysql.Conn) *ApplicationMySQLRepo {
-	return &ApplicationMySQLRepo{
-		operationInfo: operationInfo,
-		conn:          conn,
+	itemResults := make([]healthcheck.ItemResult, result.RowNumber())
+	for i := range itemResults {
+		itemResults[i] = NewEmptyItemResult()
+	}
+	// map to struct
+	err = result.MapToStructSlice(itemResults, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// GetOperationInfo returns the operation information
-func (amr *ApplicationMySQLRepo) GetOperationInfo() healthcheck.OperationInfo {
-	return amr.operationInfo
+	return itemResults, nil
 }
 
-// getConnection returns the connection
-func (amr *ApplicationMySQLRepo) getConnection() *mysql.Conn {
-	return amr.conn
+// InitClusterOperation creates a cluster operation in the middleware
+func (dr *DASRepo) InitClusterOperation(userID, mysqlClusterID int, startTime, endTime time.Time, step time.Duration) (int, error) {
+	startTimeStr := startTime.Format(constant.TimeLayoutSecond)
+	endTimeStr := endTime.Format(constant.TimeLayoutSecond)
+	stepInt := int(step.Seconds())
+
+	sql := `insert into t_hc_cluster_operation_history(user_id, mysql_cluster_id, start_time, end_time, step) values(?, ?, ?, ?, ?);`
+	log.Debugf("healthCheck DASRepo.InitClusterOperation() insert sql: \n%s\nplaceholders: %d, %d, %s, %s, %d",
+		sql, userID, mysqlClusterID, startTimeStr, endTimeStr, stepInt)
+
+	result, err := dr.Execute(sql, userID, mysqlClusterID, startTimeStr, endTimeStr, stepInt)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return result.LastInsertID()
 }
 
-// Close closes the application mysql connection
-func (amr *ApplicationMySQLRepo) Close() error {
-	return amr.getConnection().Close()
+// UpdateClusterOperationStatus updates the status and message by the clusterOperationID in the middleware
+func (dr *DASRepo) UpdateClusterOperationStatus(clusterOperationID int, status int, message string) error {
+	sql := `update t_hc_cluster_operation_history set status = ?, message = ? where id = ?;`
+	log.Debugf("healthCheck DASRepo.UpdateClusterOperationStatus() update sql: \n%s\nplaceholders: %d, %s, %d", sql, status, message, clusterOperationID)
+	_, err := dr.Execute(sql, status, message, clusterOperationID)
+
+	return err
 }
 
-// GetVariables gets db config with given items
-func (amr *ApplicationMySQLRepo) GetVariables(items []string) ([]healthcheck.Variable, error) {
-	// prepare args
-	interfaces, err := common.ConvertInterfaceToSliceInterface(items)
+// GetClusterResultByClusterOperationID gets a ClusterResult by the clusterOperationID from the middleware
+func (dr *DASRepo) GetClusterResultByClusterOperationID(clusterOperationID int) (healthcheck.ClusterResult, error) {
+	sql := `
+		select id, cluster_operation_id, mysql_cluster_id, weighted_average_score, node_result, config_drift,
+		del_flag, create_time, last_update_time
+		from t_hc_cluster_result
+		where del_flag = 0
+		and cluster_operation_id = ?;
+	`
+	log.Debugf("healthCheck DASRepo.GetClusterResultByClusterOperationID() select sql: \n%s\nplaceholders: %d", sql, clusterOperationID)
+
+	result, err := dr.Execute(sql, clusterOperationID)
 	if err != nil {
 		return nil, err
 	}
-	inClause, err := middleware.ConvertSliceToString(interfaces...)
+	switch result.RowNumber() {
+	case 0:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetClusterResultByClusterOperationID(): data does not exists, cluster_operation_id: %d", clusterOperationID)
+	case 1:
+		clusterResult := NewEmptyClusterResult()
+		// map to struct
+		err = result.MapToStructByRowIndex(clusterResult, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+
+		return clusterResult, nil
+	default:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetClusterResultByClusterOperationID(): duplicate key exists, cluster_operation_id: %d", clusterOperationID)
+	}
+}
+
+// SaveClusterResult saves the cluster result in the middleware
+func (dr *DASRepo) SaveClusterResult(clusterResult healthcheck.ClusterResult) error {
+	sql := `insert into t_hc_cluster_result(cluster_operation_id, mysql_cluster_id, weighted_average_score, node_result, config_drift)
+		values(?, ?, ?, ?, ?);`
+	log.Debugf("healthCheck DASRepo.SaveClusterResult() insert sql: \n%s\nplaceholders: %d, %d, %d, %s, %s",
+		sql, clusterResult.GetClusterOperationID(), clusterResult.GetMySQLClusterID(), clusterResult.GetWeightedAverageScore(),
+		clusterResult.GetNodeResult(), clusterResult.GetConfigDrift())
+
+	_, err := dr.Execute(sql, clusterResult.GetClusterOperationID(), clusterResult.GetMySQLClusterID(), clusterResult.GetWeightedAverageScore(),
+		clusterResult.GetNodeResult(), clusterResult.GetConfigDrift())
+
+	return err
+}
+
+// GetMySQLClusterIDsByMiddlewareClusterID returns the ids of the mysql clusters which are linked to the middleware cluster
+func (dr *DASRepo) GetMySQLClusterIDsByMiddlewareClusterID(middlewareClusterID int) ([]int, error) {
+	sql := `select id from t_meta_mysql_cluster_info where del_flag = 0 and middleware_cluster_id = ? order by id;`
+	log.Debugf("healthCheck DASRepo.GetMySQLClusterIDsByMiddlewareClusterID() select sql: \n%s\nplaceholders: %d", sql, middlewareClusterID)
+
+	result, err := dr.Execute(sql, middlewareClusterID)
 	if err != nil {
 		return nil, err
 	}
-	// check mysql version
+
+	mysqlClusterIDList := make([]int, result.RowNumber())
+	for i := range mysqlClusterIDList {
+		mysqlClusterIDList[i], err = result.GetInt(i, constant.ZeroInt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mysqlClusterIDList, nil
+}
+
+// IsMiddlewareRunning returns if the healthcheck of given middleware cluster is still running
+func (dr *DASRepo) IsMiddlewareRunning(middlewareClusterID int) (bool, error) {
+	sql := `select count(1) from t_hc_middleware_operation_history where del_flag = 0 and middleware_cluster_id = ? and status = 1;`
+	log.Debugf("healthCheck DASRepo.IsMiddlewareRunning() select sql: \n%s\nplaceholders: %d", sql, middlewareClusterID)
+
+	result, err := dr.Execute(sql, middlewareClusterID)
+	if err != nil {
+		return false, err
+	}
+	count, _ := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+
+	return count != constant.ZeroInt, nil
+}
+
+// InitMiddlewareOperation creates a middleware operation in the middleware
+func (dr *DASRepo) InitMiddlewareOperation(userID, middlewareClusterID int, startTime, endTime time.Time, step time.Duration) (int, error) {
+	startTimeStr := startTime.Format(constant.TimeLayoutSecond)
+	endTimeStr := endTime.Format(constant.TimeLayoutSecond)
+	stepInt := int(step.Seconds())
+
+	sql := `insert into t_hc_middleware_operation_history(user_id, middleware_cluster_id, start_time, end_time, step) values(?, ?, ?, ?, ?);`
+	log.Debugf("healthCheck DASRepo.InitMiddlewareOperation() insert sql: \n%s\nplaceholders: %d, %d, %s, %s, %d",
+		sql, userID, middlewareClusterID, startTimeStr, endTimeStr, stepInt)
+
+	result, err := dr.Execute(sql, userID, middlewareClusterID, startTimeStr, endTimeStr, stepInt)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return result.LastInsertID()
+}
+
+// UpdateMiddlewareOperationStatus updates the status and message by the middlewareOperationID in the middleware
+func (dr *DASRepo) UpdateMiddlewareOperationStatus(middlewareOperationID int, status int, message string) error {
+	sql := `update t_hc_middleware_operation_history set status = ?, message = ? where id = ?;`
+	log.Debugf("healthCheck DASRepo.UpdateMiddlewareOperationStatus() update sql: \n%s\nplaceholders: %d, %s, %d", sql, status, message, middlewareOperationID)
+	_, err := dr.Execute(sql, status, message, middlewareOperationID)
+
+	return err
+}
+
+// GetMiddlewareResultByMiddlewareOperationID gets a MiddlewareResult by the middlewareOperationID from the middleware
+func (dr *DASRepo) GetMiddlewareResultByMiddlewareOperationID(middlewareOperationID int) (healthcheck.MiddlewareResult, error) {
+	sql := `
+		select id, middleware_operation_id, middleware_cluster_id, score, server_result, endpoint_result,
+		del_flag, create_time, last_update_time
+		from t_hc_middleware_result
+		where del_flag = 0
+		and middleware_operation_id = ?;
+	`
+	log.Debugf("healthCheck DASRepo.GetMiddlewareResultByMiddlewareOperationID() select sql: \n%s\nplaceholders: %d", sql, middlewareOperationID)
+
+	result, err := dr.Execute(sql, middlewareOperationID)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetMiddlewareResultByMiddlewareOperationID(): data does not exists, middleware_operation_id: %d", middlewareOperationID)
+	case 1:
+		middlewareResult := NewEmptyMiddlewareResult()
+		// map to struct
+		err = result.MapToStructByRowIndex(middlewareResult, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+
+		return middlewareResult, nil
+	default:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetMiddlewareResultByMiddlewareOperationID(): duplicate key exists, middleware_operation_id: %d", middlewareOperationID)
+	}
+}
+
+// SaveMiddlewareResult saves the middleware result in the middleware
+func (dr *DASRepo) SaveMiddlewareResult(middlewareResult healthcheck.MiddlewareResult) error {
+	sql := `insert into t_hc_middleware_result(middleware_operation_id, middleware_cluster_id, score, server_result, endpoint_result)
+		values(?, ?, ?, ?, ?);`
+	log.Debugf("healthCheck DASRepo.SaveMiddlewareResult() insert sql: \n%s\nplaceholders: %d, %d, %d, %s, %s",
+		sql, middlewareResult.GetMiddlewareOperationID(), middlewareResult.GetMiddlewareClusterID(), middlewareResult.GetScore(),
+		middlewareResult.GetServerResult(), middlewareResult.GetEndpointResult())
+
+	_, err := dr.Execute(sql, middlewareResult.GetMiddlewareOperationID(), middlewareResult.GetMiddlewareClusterID(), middlewareResult.GetScore(),
+		middlewareResult.GetServerResult(), middlewareResult.GetEndpointResult())
+
+	return err
+}
+
+// UpdateAccuracyReviewByOperationID updates the accuracyReview by the operationID in the middleware
+func (dr *DASRepo) UpdateAccuracyReviewByOperationID(operationID int, review int) error {
+	sql := `update t_hc_result set accuracy_review = ? where operation_id = ?;`
+	log.Debugf("healthCheck DASRepo.UpdateAccuracyReviewByOperationID() update sql: \n%s\nplaceholders: %s, %s", sql, operationID, review)
+
+	_, err := dr.Execute(sql, review, operationID)
+	return err
+}
+
+// GetItemFeedbacksByOperationID gets the accuracy feedbacks of the check items of the operation from the middleware
+func (dr *DASRepo) GetItemFeedbacksByOperationID(operationID int) ([]healthcheck.ItemFeedback, error) {
+	sql := `
+		select id, operation_id, item_name, accuracy, comment, login_name, del_flag, create_time, last_update_time
+		from t_hc_item_feedback
+		where del_flag = 0
+		and operation_id = ?
+		order by id;
+	`
+	log.Debugf("healthCheck DASRepo.GetItemFeedbacksByOperationID() select sql: \n%s\nplaceholders: %d", sql, operationID)
+
+	result, err := dr.Execute(sql, operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	itemFeedbacks := make([]healthcheck.ItemFeedback, result.RowNumber())
+	for i := range itemFeedbacks {
+		itemFeedbacks[i] = NewEmptyItemFeedback()
+	}
+	// map to struct
+	err = result.MapToStructSlice(itemFeedbacks, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemFeedbacks, nil
+}
+
+// SaveItemFeedback saves the accuracy feedback of the check item in the middleware,
+// a reviewer has only one feedback for a check item of an operation, the existing feedback will be replaced
+func (dr *DASRepo) SaveItemFeedback(itemFeedback healthcheck.ItemFeedback) error {
+	sql := `
+		insert into t_hc_item_feedback(operation_id, item_name, accuracy, comment, login_name)
+		values(?, ?, ?, ?, ?)
+		on duplicate key update accuracy = values(accuracy), comment = values(comment), del_flag = 0;
+	`
+	log.Debugf("healthCheck DASRepo.SaveItemFeedback() insert sql: \n%s\nplaceholders: %d, %s, %d, %s, %s",
+		sql, itemFeedback.GetOperationID(), itemFeedback.GetItemName(), itemFeedback.GetAccuracy(), itemFeedback.GetComment(), itemFeedback.GetLoginName())
+
+	_, err := dr.Execute(sql, itemFeedback.GetOperationID(), itemFeedback.GetItemName(), itemFeedback.GetAccuracy(),
+		itemFeedback.GetComment(), itemFeedback.GetLoginName())
+
+	return err
+}
+
+// GetFeedbackStatsByTimeRange gets the aggregated accuracy feedbacks of the operations which were created between start time and end time,
+// the feedbacks are grouped by the check date, the item, the environment and the engine config which was used by the operations
+func (dr *DASRepo) GetFeedbackStatsByTimeRange(startTime, endTime time.Time) ([]healthcheck.FeedbackStat, error) {
+	sql := `
+		select date_format(oh.create_time, '%Y-%m-%d') as check_date,
+			   f.item_name,
+			   mci.env_id,
+			   oh.profile_id,
+			   oh.config_version,
+			   count(1) as feedback_count,
+			   cast(sum(case when f.accuracy = 2 then 1 else 0 end) as signed) as inaccurate_count
+		from t_hc_item_feedback f
+			inner join t_hc_operation_history oh on f.operation_id = oh.id
+			inner join t_meta_mysql_server_info msi on oh.mysql_server_id = msi.id
+			inner join t_meta_mysql_cluster_info mci on msi.cluster_id = mci.id
+		where f.del_flag = 0
+		  and oh.del_flag = 0
+		  and oh.create_time >= ?
+		  and oh.create_time < ?
+		group by check_date, f.item_name, mci.env_id, oh.profile_id, oh.config_version
+		order by check_date, f.item_name, mci.env_id, oh.profile_id, oh.config_version;
+	`
+	startTimeStr := startTime.Format(constant.TimeLayoutSecond)
+	endTimeStr := endTime.Format(constant.TimeLayoutSecond)
+	log.Debugf("healthCheck DASRepo.GetFeedbackStatsByTimeRange() select sql: \n%s\nplaceholders: %s, %s", sql, startTimeStr, endTimeStr)
+
+	result, err := dr.Execute(sql, startTimeStr, endTimeStr)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbackStatList := make([]*FeedbackStat, result.RowNumber())
+	for i := range feedbackStatList {
+		feedbackStatList[i] = NewEmptyFeedbackStat()
+	}
+	// map to struct
+	err = result.MapToStructSlice(feedbackStatList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbackStats := make([]healthcheck.FeedbackStat, len(feedbackStatList))
+	for i, feedbackStat := range feedbackStatList {
+		feedbackStat.calculateInaccurateRate()
+		feedbackStats[i] = feedbackStat
+	}
+
+	return feedbackStats, nil
+}
+
+// GetSnapshotByOperationID gets the snapshot of the inputs collected by the operation from the middleware
+func (dr *DASRepo) GetSnapshotByOperationID(operationID int) (healthcheck.Snapshot, error) {
+	sql := `select snapshot from t_hc_snapshot where del_flag = 0 and operation_id = ?;`
+	log.Debugf("healthCheck DASRepo.GetSnapshotByOperationID() select sql: \n%s\nplaceholders: %d", sql, operationID)
+
+	result, err := dr.Execute(sql, operationID)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetSnapshotByOperationID(): data does not exists, operation_id: %d", operationID)
+	case 1:
+		snapshotJSON, err := result.GetString(constant.ZeroInt, constant.ZeroInt)
+		if err != nil {
+			return nil, err
+		}
+		snapshot, err := NewSnapshotWithJSON([]byte(snapshotJSON))
+		if err != nil {
+			return nil, err
+		}
+
+		return snapshot, nil
+	default:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetSnapshotByOperationID(): duplicate key exists, operation_id: %d", operationID)
+	}
+}
+
+// SaveSnapshot saves the snapshot of the inputs collected by the operation in the middleware
+func (dr *DASRepo) SaveSnapshot(operationID int, snapshot healthcheck.Snapshot) error {
+	jsonBytes, err := snapshot.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	sql := `insert into t_hc_snapshot(operation_id, snapshot) values(?, ?);`
+	log.Debugf("healthCheck DASRepo.SaveSnapshot() insert sql: \n%s\nplaceholders: %d, %d bytes", sql, operationID, len(jsonBytes))
+
+	_, err = dr.Execute(sql, operationID, string(jsonBytes))
+
+	return err
+}
+
+// GetProgressByOperationID gets the latest progress of the operation from the middleware,
+// the item numbers are 0 if the operation has not started checking the items
+func (dr *DASRepo) GetProgressByOperationID(operationID int) (healthcheck.Progress, error) {
+	sql := `
+		select oh.id as operation_id,
+			   oh.status,
+			   coalesce(op.total_item_num, 0) as total_item_num,
+			   coalesce(op.finished_item_num, 0) as finished_item_num,
+			   coalesce(op.current_item_name, '') as current_item_name,
+			   coalesce(op.elapsed, 0) as elapsed,
+			   coalesce(op.last_update_time, oh.last_update_time) as last_update_time
+		from t_hc_operation_history oh
+			left join t_hc_operation_progress op on oh.id = op.operation_id and op.del_flag = 0
+		where oh.del_flag = 0
+		  and oh.id = ?;
+	`
+	log.Debugf("healthCheck DASRepo.GetProgressByOperationID() select sql: \n%s\nplaceholders: %d", sql, operationID)
+
+	result, err := dr.Execute(sql, operationID)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetProgressByOperationID(): data does not exists, operation_id: %d", operationID)
+	case 1:
+		progress := NewEmptyProgress()
+		err = result.MapToStructByRowIndex(progress, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+		progress.calculatePercent()
+
+		return progress, nil
+	default:
+		return nil, fmt.Errorf("healthCheck DASRepo.GetProgressByOperationID(): duplicate key exists, operation_id: %d", operationID)
+	}
+}
+
+// SaveProgress saves the latest progress of the operation in the middleware, the existing progress will be replaced
+func (dr *DASRepo) SaveProgress(progress healthcheck.Progress) error {
+	sql := `
+		insert into t_hc_operation_progress(operation_id, total_item_num, finished_item_num, current_item_name, elapsed)
+		values(?, ?, ?, ?, ?)
+		on duplicate key update total_item_num = values(total_item_num), finished_item_num = values(finished_item_num),
+			current_item_name = values(current_item_name), elapsed = values(elapsed), del_flag = 0;
+	`
+	log.Debugf("healthCheck DASRepo.SaveProgress() insert sql: \n%s\nplaceholders: %d, %d, %d, %s, %d",
+		sql, progress.GetOperationID(), progress.GetTotalItemNum(), progress.GetFinishedItemNum(), progress.GetCurrentItemName(), progress.GetElapsed())
+
+	_, err := dr.Execute(sql, progress.GetOperationID(), progress.GetTotalItemNum(), progress.GetFinishedItemNum(),
+		progress.GetCurrentItemName(), progress.GetElapsed())
+
+	return err
+}
+
+// GetCallbackURLsByOperationID gets the callback urls of the operation from the middleware
+func (dr *DASRepo) GetCallbackURLsByOperationID(operationID int) ([]string, error) {
+	sql := `select callback_url from t_hc_operation_callback where del_flag = 0 and operation_id = ? order by id;`
+	log.Debugf("healthCheck DASRepo.GetCallbackURLsByOperationID() select sql: \n%s\nplaceholders: %d", sql, operationID)
+
+	result, err := dr.Execute(sql, operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	callbackURLs := make([]string, result.RowNumber())
+	for i := range callbackURLs {
+		callbackURLs[i], err = result.GetString(i, constant.ZeroInt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return callbackURLs, nil
+}
+
+// SaveCallbackURLs saves the callback urls of the operation in the middleware as a transaction
+func (dr *DASRepo) SaveCallbackURLs(operationID int, callbackURLs []string) error {
+	tx, err := dr.Transaction()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = tx.Close()
+		if err != nil {
+			log.Errorf("healthcheck DASRepo.SaveCallbackURLs(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	err = tx.Begin()
+	if err != nil {
+		return err
+	}
+
+	sql := `insert into t_hc_operation_callback(operation_id, callback_url) values(?, ?);`
+	for _, callbackURL := range callbackURLs {
+		log.Debugf("healthCheck DASRepo.SaveCallbackURLs() insert sql: \n%s\nplaceholders: %d, %s", sql, operationID, callbackURL)
+		_, err = tx.Execute(sql, operationID, callbackURL)
+		if err != nil {
+			return dr.rollback(tx, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+type ApplicationMySQLRepo struct {
+	ctx           context.Context
+	operationInfo healthcheck.OperationInfo
+	conn          *mysql.Conn
+	killer        *mysqlKiller
+	// the replication status is cached, so that it is fetched only once by all the replication items of the operation
+	replicationStatuses []healthcheck.ReplicationStatus
+	// the connections of the masters are kept until the repository is closed, the key is the address of the master
+	masterConns map[string]*masterConn
+}
+
+// masterConn is the connection to the master of a replication channel
+type masterConn struct {
+	conn   *mysql.Conn
+	killer *mysqlKiller
+}
+
+// NewApplicationMySQLRepo returns a new healthcheck.ApplicationMySQLRepo,
+// the running query will be killed when ctx is done
+func NewApplicationMySQLRepo(ctx context.Context, operationInfo healthcheck.OperationInfo, conn *mysql.Conn) healthcheck.ApplicationMySQLRepo {
+	return newApplicationMySQLRepo(ctx, operationInfo, conn)
+}
+
+// newApplicationMySQLRepo returns a new *ApplicationMySQLRepo
+func newApplicationMySQLRepo(ctx context.Context, operationInfo healthcheck.OperationInfo, conn *mysql.Conn) *ApplicationMySQLRepo {
+	mysqlServerAddr := fmt.Sprintf("%s:%d", operationInfo.GetMySQLServer().GetHostIP(), operationInfo.GetMySQLServer().GetPortNum())
+
+	return &ApplicationMySQLRepo{
+		ctx:           ctx,
+		operationInfo: operationInfo,
+		conn:          conn,
+		killer: newMySQLKiller(mysqlServerAddr, viper.GetString(config.DBApplicationMySQLUserKey),
+			viper.GetString(config.DBApplicationMySQLPassKey)),
+		masterConns: make(map[string]*masterConn),
+	}
+}
+
+// GetOperationInfo returns the operation information
+func (amr *ApplicationMySQLRepo) GetOperationInfo() healthcheck.OperationInfo {
+	return amr.operationInfo
+}
+
+// getConnection returns the connection
+func (amr *ApplicationMySQLRepo) getConnection() *mysql.Conn {
+	return amr.conn
+}
+
+// Close closes the application mysql connection and the connections of the masters
+func (amr *ApplicationMySQLRepo) Close() error {
+	merr := &multierror.Error{}
+
+	for addr, mc := range amr.masterConns {
+		err := mc.conn.Close()
+		if err != nil {
+			merr = multierror.Append(merr, errors.Annotatef(err, "close master connection failed. addr: %s", addr))
+		}
+		delete(amr.masterConns, addr)
+	}
+
+	err := amr.getConnection().Close()
+	if err != nil {
+		merr = multierror.Append(merr, err)
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// execute executes given command and placeholders on the application mysql connection
+func (amr *ApplicationMySQLRepo) execute(command string, args ...interface{}) (middleware.Result, error) {
+	return executeWithContext(amr.ctx, amr.getConnection(), amr.killer, command, args...)
+}
+
+// GetVariables gets db config with given items
+func (amr *ApplicationMySQLRepo) GetVariables(items []string) ([]healthcheck.Variable, error) {
+	// prepare args
+	interfaces, err := common.ConvertInterfaceToSliceInterface(items)
+	if err != nil {
+		return nil, err
+	}
+	inClause, err := middleware.ConvertSliceToString(interfaces...)
+	if err != nil {
+		return nil, err
+	}
+	// check mysql version
 	mysqlVersion, err := version.NewVersion(amr.GetOperationInfo().GetMySQLServer().GetVersion())
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -371,7 +1272,7 @@ func (amr *ApplicationMySQLRepo) GetVariables(items []string) ([]healthcheck.Var
 		sql = fmt.Sprintf(applicationMySQLVariables, informationSchema, inClause)
 	}
 	// get result
-	result, err := amr.getConnection().Execute(sql)
+	result, err := amr.execute(sql)
 	if err != nil {
 		return nil, err
 	}
@@ -399,62 +1300,667 @@ func (amr *ApplicationMySQLRepo) GetMySQLDirs() ([]string, error) {
 		dirs = append(dirs, variable.GetValue())
 	}
 
-	return dirs, nil
+	return dirs, nil
+}
+
+// GetLargeTables gets the large tables
+func (amr *ApplicationMySQLRepo) GetLargeTables() ([]healthcheck.Table, error) {
+	result, err := amr.execute(applicationMySQLTableSize, minTableRows)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]healthcheck.Table, result.RowNumber())
+	for i := range tables {
+		tables[i] = NewEmptyTable()
+	}
+	err = result.MapToStructSlice(tables, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// GetDBName gets the db name of given table names
+func (amr *ApplicationMySQLRepo) GetDBName(tableNames []string) (string, error) {
+	if len(tableNames) == constant.ZeroInt {
+		return constant.EmptyString, nil
+	}
+
+	sql := `select table_schema from information_schema.tables where table_name in (%s);`
+	interfaces, err := common.ConvertInterfaceToSliceInterface(tableNames)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+	inClause, err := middleware.ConvertSliceToString(interfaces...)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+	sql = fmt.Sprintf(sql, inClause)
+
+	result, err := amr.execute(sql)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return result.GetString(constant.ZeroInt, constant.ZeroInt)
+}
+
+// GetReplicationStatus gets the replication status of all the replication channels,
+// it returns an empty slice if the mysql server is not a replica, the channels of group replication are skipped,
+// the status is fetched only once and cached, as it is used by several replication items of the same operation
+func (amr *ApplicationMySQLRepo) GetReplicationStatus() ([]healthcheck.ReplicationStatus, error) {
+	if amr.replicationStatuses != nil {
+		return amr.replicationStatuses, nil
+	}
+
+	// show slave status is deprecated since mysql 8.0.22, and the columns are renamed in show replica status
+	mysqlVersion, err := version.NewVersion(amr.GetOperationInfo().GetMySQLServer().GetVersion())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defaultVersion, err := version.NewVersion(mysql8022)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sql := applicationMySQLReplicaStatus
+	masterHostColumn := replicationSourceHostColumn
+	masterPortColumn := replicationSourcePortColumn
+	slaveIORunningColumn := replicationReplicaIORunningColumn
+	slaveSQLRunningColumn := replicationReplicaSQLRunningColumn
+	secondsBehindMasterColumn := replicationSecondsBehindSourceColumn
+	if mysqlVersion.LessThan(defaultVersion) {
+		sql = applicationMySQLReplicationStatus
+		masterHostColumn = replicationMasterHostColumn
+		masterPortColumn = replicationMasterPortColumn
+		slaveIORunningColumn = replicationSlaveIORunningColumn
+		slaveSQLRunningColumn = replicationSlaveSQLRunningColumn
+		secondsBehindMasterColumn = replicationSecondsBehindMasterColumn
+	}
+
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetReplicationStatus() sql: \n%s\n", sql)
+
+	result, err := amr.execute(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	replicationStatuses := make([]healthcheck.ReplicationStatus, constant.ZeroInt, result.RowNumber())
+	for i := constant.ZeroInt; i < result.RowNumber(); i++ {
+		// channel name is not supported before mysql 5.7
+		channelName, _ := result.GetStringByName(i, replicationChannelNameColumn)
+		if strings.HasPrefix(channelName, replicationGroupReplicationChannelPrefix) {
+			continue
+		}
+		masterHost, err := result.GetStringByName(i, masterHostColumn)
+		if err != nil {
+			return nil, err
+		}
+		masterPort, err := result.GetStringByName(i, masterPortColumn)
+		if err != nil {
+			return nil, err
+		}
+		masterPortNum, err := strconv.Atoi(masterPort)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		slaveIORunning, err := result.GetStringByName(i, slaveIORunningColumn)
+		if err != nil {
+			return nil, err
+		}
+		slaveSQLRunning, err := result.GetStringByName(i, slaveSQLRunningColumn)
+		if err != nil {
+			return nil, err
+		}
+		// seconds behind master is null when the sql thread is not running
+		secondsBehindMaster := replicationUnknownLag
+		lag, err := result.GetStringByName(i, secondsBehindMasterColumn)
+		if err == nil {
+			lagSeconds, err := strconv.Atoi(lag)
+			if err == nil {
+				secondsBehindMaster = lagSeconds
+			}
+		}
+		lastIOError, err := result.GetStringByName(i, replicationLastIOErrorColumn)
+		if err != nil {
+			return nil, err
+		}
+		lastSQLError, err := result.GetStringByName(i, replicationLastSQLErrorColumn)
+		if err != nil {
+			return nil, err
+		}
+
+		replicationStatuses = append(replicationStatuses, NewReplicationStatus(channelName, masterHost, masterPortNum,
+			slaveIORunning, slaveSQLRunning, secondsBehindMaster, lastIOError, lastSQLError))
+	}
+	amr.replicationStatuses = replicationStatuses
+
+	return replicationStatuses, nil
+}
+
+// GetGTIDExecuted gets the executed gtid set
+func (amr *ApplicationMySQLRepo) GetGTIDExecuted() (string, error) {
+	return getGTIDExecuted(amr.ctx, amr.getConnection(), amr.killer)
+}
+
+// GetMasterGTIDExecuted gets the executed gtid set of the master with given host ip and port number,
+// the connection of the master is reused until the repository is closed
+func (amr *ApplicationMySQLRepo) GetMasterGTIDExecuted(hostIP string, portNum int) (string, error) {
+	mc, err := amr.getMasterConn(hostIP, portNum)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return getGTIDExecuted(amr.ctx, mc.conn, mc.killer)
+}
+
+// getMasterConn returns the connection of the master with given host ip and port number, it connects to the master only once
+func (amr *ApplicationMySQLRepo) getMasterConn(hostIP string, portNum int) (*masterConn, error) {
+	addr := fmt.Sprintf("%s:%d", hostIP, portNum)
+	mc, ok := amr.masterConns[addr]
+	if ok {
+		return mc, nil
+	}
+
+	conn, err := mysql.NewConn(
+		addr,
+		constant.EmptyString,
+		viper.GetString(config.DBApplicationMySQLUserKey),
+		viper.GetString(config.DBApplicationMySQLPassKey),
+	)
+	if err != nil {
+		return nil, message.NewMessage(
+			msghc.ErrHealthcheckCreateApplicationMySQLConnection, err, addr, viper.GetString(config.DBApplicationMySQLUserKey))
+	}
+	mc = &masterConn{
+		conn: conn,
+		killer: newMySQLKiller(addr, viper.GetString(config.DBApplicationMySQLUserKey),
+			viper.GetString(config.DBApplicationMySQLPassKey)),
+	}
+	amr.masterConns[addr] = mc
+
+	return mc, nil
+}
+
+// GetGTIDSubtract returns the gtids of the first gtid set which are not in the second gtid set
+func (amr *ApplicationMySQLRepo) GetGTIDSubtract(gtidSet1, gtidSet2 string) (string, error) {
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetGTIDSubtract() sql: \n%s\nplaceholders: %s, %s", applicationMySQLGTIDSubtract, gtidSet1, gtidSet2)
+
+	result, err := amr.execute(applicationMySQLGTIDSubtract, gtidSet1, gtidSet2)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return result.GetString(constant.ZeroInt, constant.ZeroInt)
+}
+
+// GetGroupMembers gets the members of the group replication,
+// it returns an empty slice if the mysql server is not a member of group replication
+func (amr *ApplicationMySQLRepo) GetGroupMembers() ([]healthcheck.GroupMember, error) {
+	// group replication is not supported before mysql 5.7
+	mysqlVersion, err := version.NewVersion(amr.GetOperationInfo().GetMySQLServer().GetVersion())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defaultVersion, err := version.NewVersion(mysql57)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if mysqlVersion.LessThan(defaultVersion) {
+		return []healthcheck.GroupMember{}, nil
+	}
+
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetGroupMembers() sql: \n%s\n", applicationMySQLGroupMembers)
+
+	result, err := amr.execute(applicationMySQLGroupMembers)
+	if err != nil {
+		return nil, err
+	}
+	groupMembers := make([]healthcheck.GroupMember, result.RowNumber())
+	for i := range groupMembers {
+		groupMembers[i] = NewEmptyGroupMember()
+	}
+	err = result.MapToStructSlice(groupMembers, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupMembers, nil
+}
+
+// GetLongTransactions gets the running innodb transactions, the longest one comes first
+func (amr *ApplicationMySQLRepo) GetLongTransactions() ([]healthcheck.InnoDBTransaction, error) {
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetLongTransactions() sql: \n%s\nplaceholders: %d", applicationMySQLLongTransactions, minTransactionDuration)
+
+	result, err := amr.execute(applicationMySQLLongTransactions, minTransactionDuration)
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]healthcheck.InnoDBTransaction, result.RowNumber())
+	for i := range transactions {
+		transactions[i] = NewEmptyInnoDBTransaction()
+	}
+	err = result.MapToStructSlice(transactions, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// GetHistoryListLength gets the history list length of the innodb undo logs
+func (amr *ApplicationMySQLRepo) GetHistoryListLength() (int, error) {
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetHistoryListLength() sql: \n%s\n", applicationMySQLHistoryListLength)
+
+	result, err := amr.execute(applicationMySQLHistoryListLength)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return result.GetInt(constant.ZeroInt, constant.ZeroInt)
+}
+
+// GetLockWaits gets the innodb transactions which are waiting for the locks and the transactions which block them
+func (amr *ApplicationMySQLRepo) GetLockWaits() ([]healthcheck.InnoDBLockWait, error) {
+	// the lock waits are moved to performance_schema since mysql 8.0
+	mysqlVersion, err := version.NewVersion(amr.GetOperationInfo().GetMySQLServer().GetVersion())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defaultVersion, err := version.NewVersion(mysql80)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sql := applicationMySQLLockWaitsV80
+	if mysqlVersion.LessThan(defaultVersion) {
+		sql = applicationMySQLLockWaitsV57
+	}
+
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetLockWaits() sql: \n%s\n", sql)
+
+	result, err := amr.execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	lockWaits := make([]healthcheck.InnoDBLockWait, result.RowNumber())
+	for i := range lockWaits {
+		lockWaits[i] = NewEmptyInnoDBLockWait()
+	}
+	err = result.MapToStructSlice(lockWaits, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return lockWaits, nil
+}
+
+// GetLatestDeadlock gets the latest deadlock detected by innodb since the mysql server started,
+// it returns an empty deadlock of which the detected time is zero if there was no deadlock
+func (amr *ApplicationMySQLRepo) GetLatestDeadlock() (healthcheck.InnoDBDeadlock, error) {
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetLatestDeadlock() sql: \n%s\n", applicationMySQLInnoDBStatus)
+
+	result, err := amr.execute(applicationMySQLInnoDBStatus)
+	if err != nil {
+		return nil, err
+	}
+	status, err := result.GetStringByName(constant.ZeroInt, innodbStatusColumn)
+	if err != nil {
+		return nil, err
+	}
+	deadlock, err := parseLatestDeadlock(status)
+	if err != nil {
+		return nil, err
+	}
+
+	return deadlock, nil
+}
+
+// parseLatestDeadlock parses the latest detected deadlock section of the output of show engine innodb status,
+// it returns an empty deadlock if the section does not exist
+func parseLatestDeadlock(status string) (*InnoDBDeadlock, error) {
+	deadlock := NewEmptyInnoDBDeadlock()
+
+	lines := strings.Split(strings.ReplaceAll(status, constant.CRLFString, constant.LFString), constant.LFString)
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == innodbStatusLatestDeadlock {
+			// skip the separator line below the section name
+			start = i + 2
+			break
+		}
+	}
+	if start < constant.ZeroInt || start >= len(lines) {
+		return deadlock, nil
+	}
+
+	// the first line of the section is the detected time, it looks like: 2022-06-01 10:00:00 0x7f8b5c0c0700
+	timeLine := strings.TrimSpace(lines[start])
+	if len(timeLine) < len(constant.TimeLayoutSecond) {
+		return nil, errors.Errorf("invalid deadlock detected time: %s", timeLine)
+	}
+	detectedTime, err := time.ParseInLocation(constant.TimeLayoutSecond, timeLine[:len(constant.TimeLayoutSecond)], time.Local)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	deadlock.DetectedTime = detectedTime
+
+	var (
+		transaction *InnoDBDeadlockTransaction
+		inQuery     bool
+	)
+	for _, line := range lines[start+1:] {
+		if strings.HasPrefix(line, innodbStatusSectionSeparator) {
+			// the next section starts
+			break
+		}
+		if matches := deadlockTrxNumRegexp.FindStringSubmatch(line); matches != nil {
+			trxNum, err := strconv.Atoi(matches[1])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			transaction = &InnoDBDeadlockTransaction{TrxNum: trxNum}
+			deadlock.Transactions = append(deadlock.Transactions, transaction)
+			inQuery = false
+			continue
+		}
+		if strings.HasPrefix(line, innodbStatusLockInfoPrefix) {
+			// the lock information or the roll back information, the query of the transaction ends here
+			inQuery = false
+			if matches := deadlockRollBackRegexp.FindStringSubmatch(line); matches != nil {
+				deadlock.RolledBackTrxNum, err = strconv.Atoi(matches[1])
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+			continue
+		}
+		if transaction == nil {
+			continue
+		}
+		if inQuery {
+			// the query may contain multiple lines
+			if transaction.Query != constant.EmptyString {
+				transaction.Query += constant.LFString
+			}
+			transaction.Query += line
+			continue
+		}
+		if matches := deadlockTrxIDRegexp.FindStringSubmatch(line); matches != nil {
+			transaction.TrxID = matches[1]
+			continue
+		}
+		if matches := deadlockThreadIDRegexp.FindStringSubmatch(line); matches != nil {
+			transaction.ThreadID, err = strconv.Atoi(matches[1])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			// the query follows the thread line
+			inQuery = true
+		}
+	}
+
+	return deadlock, nil
+}
+
+// GetTableSchemas gets the engines and the primary key states of the tables which are not in the ignored dbs
+func (amr *ApplicationMySQLRepo) GetTableSchemas() ([]healthcheck.TableSchema, error) {
+	sql, err := getIgnoreDBSQL(applicationMySQLTableSchemas)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetTableSchemas() sql: \n%s\n", sql)
+
+	result, err := amr.execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	tableSchemas := make([]healthcheck.TableSchema, result.RowNumber())
+	for i := range tableSchemas {
+		tableSchemas[i] = NewEmptyTableSchema()
+	}
+	err = result.MapToStructSlice(tableSchemas, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return tableSchemas, nil
+}
+
+// GetIndexStatistics gets the index statistics of the tables which are not in the ignored dbs,
+// the columns of an index are ordered by the sequence in the index
+func (amr *ApplicationMySQLRepo) GetIndexStatistics() ([]depmeta.IndexStatistic, error) {
+	sql, err := getIgnoreDBSQL(applicationMySQLIndexStatistics)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetIndexStatistics() sql: \n%s\n", sql)
+
+	result, err := amr.execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	indexStatistics := make([]depmeta.IndexStatistic, result.RowNumber())
+	for i := range indexStatistics {
+		indexStatistics[i] = metadata.NewEmptyIndexStatistic()
+	}
+	err = result.MapToStructSlice(indexStatistics, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return indexStatistics, nil
+}
+
+// GetAutoIncrements gets the auto increment columns and the next auto increment values of the tables which are not in the ignored dbs
+func (amr *ApplicationMySQLRepo) GetAutoIncrements() ([]healthcheck.AutoIncrement, error) {
+	sql, err := getIgnoreDBSQL(applicationMySQLAutoIncrements)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetAutoIncrements() sql: \n%s\n", sql)
+
+	result, err := amr.execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	autoIncrements := make([]healthcheck.AutoIncrement, result.RowNumber())
+	for i := range autoIncrements {
+		autoIncrements[i] = NewEmptyAutoIncrement()
+	}
+	err = result.MapToStructSlice(autoIncrements, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return autoIncrements, nil
+}
+
+// GetUsers gets the accounts of the mysql server, the password hashes are not returned
+func (amr *ApplicationMySQLRepo) GetUsers() ([]healthcheck.MySQLUser, error) {
+	// the password column is removed and the account_locked column is added since mysql 5.7
+	mysqlVersion, err := version.NewVersion(amr.GetOperationInfo().GetMySQLServer().GetVersion())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defaultVersion, err := version.NewVersion(mysql57)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sql := applicationMySQLUsersV57
+	if mysqlVersion.LessThan(defaultVersion) {
+		sql = applicationMySQLUsersV56
+	}
+
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetUsers() sql: \n%s\n", sql)
+
+	result, err := amr.execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]healthcheck.MySQLUser, result.RowNumber())
+	for i := range users {
+		users[i] = NewEmptyMySQLUser()
+	}
+	err = result.MapToStructSlice(users, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetGrants gets the grant statements of given account, the password hashes in the statements are masked
+func (amr *ApplicationMySQLRepo) GetGrants(user, host string) ([]string, error) {
+	sql := fmt.Sprintf(applicationMySQLGrants, escapeSingleQuote(user), escapeSingleQuote(host))
+	log.Debugf("healthcheck ApplicationMySQLRepo.GetGrants() sql: \n%s\n", sql)
+
+	result, err := amr.execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	grants := make([]string, result.RowNumber())
+	for i := range grants {
+		grant, err := result.GetString(i, constant.ZeroInt)
+		if err != nil {
+			return nil, err
+		}
+		grants[i] = grantPasswordRegexp.ReplaceAllString(grant, grantPasswordMask)
+	}
+
+	return grants, nil
+}
+
+// escapeSingleQuote escapes the single quotes of the string, so that it could be quoted by single quotes in the sql
+func escapeSingleQuote(s string) string {
+	return strings.ReplaceAll(s, `'`, `''`)
+}
+
+// getIgnoreDBSQL fills the ignored dbs into the in clause of the given sql
+func getIgnoreDBSQL(sql string) (string, error) {
+	interfaces, err := common.ConvertInterfaceToSliceInterface(ignoreDBList)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+	inClause, err := middleware.ConvertSliceToString(interfaces...)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return fmt.Sprintf(sql, inClause), nil
+}
+
+// getGTIDExecuted gets the executed gtid set with given connection
+func getGTIDExecuted(ctx context.Context, conn *mysql.Conn, killer *mysqlKiller) (string, error) {
+	log.Debugf("healthcheck getGTIDExecuted() sql: \n%s\n", applicationMySQLGTIDExecuted)
+
+	result, err := executeWithContext(ctx, conn, killer, applicationMySQLGTIDExecuted)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return result.GetString(constant.ZeroInt, constant.ZeroInt)
 }
 
-// GetLargeTables gets the large tables
-func (amr *ApplicationMySQLRepo) GetLargeTables() ([]healthcheck.Table, error) {
-	result, err := amr.getConnection().Execute(applicationMySQLTableSize, minTableRows)
+// executeWithContext executes given command and placeholders with given mysql connection,
+// if ctx is done before the command returns, the command will be killed by killer,
+// it returns after the command returns, so the connection could be closed safely then
+func executeWithContext(ctx context.Context, conn *mysql.Conn, killer *mysqlKiller, command string, args ...interface{}) (middleware.Result, error) {
+	err := killer.init(conn)
 	if err != nil {
 		return nil, err
 	}
-	tables := make([]healthcheck.Table, result.RowNumber())
-	for i := range tables {
-		tables[i] = NewEmptyTable()
-	}
-	err = result.MapToStructSlice(tables, constant.DefaultMiddlewareTag)
+
+	var result middleware.Result
+	err = runWithContext(ctx, func() error {
+		var err error
+		result, err = conn.Execute(command, args...)
+		return err
+	}, killer.kill)
 	if err != nil {
 		return nil, err
 	}
 
-	return tables, nil
+	return result, nil
 }
 
-// GetDBName gets the db name of given table names
-func (amr *ApplicationMySQLRepo) GetDBName(tableNames []string) (string, error) {
-	if len(tableNames) == constant.ZeroInt {
-		return constant.EmptyString, nil
+// mysqlKiller kills the running query of a mysql connection,
+// as the connection is busy while the query is running, it kills the query with a new connection to the same mysql server
+type mysqlKiller struct {
+	addr         string
+	user         string
+	pass         string
+	connectionID int
+}
+
+// newMySQLKiller returns a new *mysqlKiller, addr, user and pass are used to connect to the mysql server of the connection
+func newMySQLKiller(addr, user, pass string) *mysqlKiller {
+	return &mysqlKiller{
+		addr: addr,
+		user: user,
+		pass: pass,
 	}
+}
 
-	sql := `select table_schema from information_schema.tables where table_name in (%s);`
-	interfaces, err := common.ConvertInterfaceToSliceInterface(tableNames)
+// init gets the connection id of given connection if it was not got yet, it must be called when the connection is idle
+func (mk *mysqlKiller) init(conn *mysql.Conn) error {
+	if mk.connectionID != constant.ZeroInt {
+		return nil
+	}
+
+	log.Debugf("healthcheck mysqlKiller.init() sql: \n%s\n", applicationMySQLConnectionID)
+	result, err := conn.Execute(applicationMySQLConnectionID)
 	if err != nil {
-		return constant.EmptyString, err
+		return err
 	}
-	inClause, err := middleware.ConvertSliceToString(interfaces...)
+	mk.connectionID, err = result.GetInt(constant.ZeroInt, constant.ZeroInt)
+
+	return err
+}
+
+// kill kills the running query of the connection, the errors are logged only,
+// as the query will stop anyway when the connection is closed
+func (mk *mysqlKiller) kill() {
+	conn, err := mysql.NewConn(mk.addr, constant.EmptyString, mk.user, mk.pass)
 	if err != nil {
-		return constant.EmptyString, err
+		log.Errorf("healthcheck mysqlKiller.kill(): create connection failed. addr: %s, user: %s, error:\n%+v", mk.addr, mk.user, err)
+		return
 	}
-	sql = fmt.Sprintf(sql, inClause)
+	defer func() {
+		err = conn.Close()
+		if err != nil {
+			log.Errorf("healthcheck mysqlKiller.kill(): close connection failed. addr: %s, error:\n%+v", mk.addr, err)
+		}
+	}()
 
-	result, err := amr.getConnection().Execute(sql)
+	sql := fmt.Sprintf(applicationMySQLKillQuery, mk.connectionID)
+	log.Debugf("healthcheck mysqlKiller.kill() sql: \n%s\n", sql)
+	_, err = conn.Execute(sql)
 	if err != nil {
-		return constant.EmptyString, err
+		log.Errorf("healthcheck mysqlKiller.kill(): kill query failed. addr: %s, connection id: %d, error:\n%+v", mk.addr, mk.connectionID, err)
 	}
+}
 
-	return result.GetString(constant.ZeroInt, constant.ZeroInt)
+// getGenericSelector returns the label selector of the generic prometheus monitor system,
+// the value of the instance label usually contains the port of the exporter, so the port is optional
+func getGenericSelector(label, value, job string) string {
+	return fmt.Sprintf(`%s=~"%s(:[0-9]+)?",job=~"%s"`, label, value, job)
 }
 
 type PrometheusRepo struct {
+	ctx           context.Context
 	operationInfo healthcheck.OperationInfo
 	conn          *prometheus.Conn
+	renderer      *PromQLRenderer
 }
 
-// NewPrometheusRepo returns a new *PrometheusRepo
-func NewPrometheusRepo(operationInfo healthcheck.OperationInfo, conn *prometheus.Conn) *PrometheusRepo {
+// NewPrometheusRepo returns a new *PrometheusRepo, the queries could not be interrupted, no more query runs after ctx is done,
+// the queries are rendered from the promql templates of the monitor system by renderer
+func NewPrometheusRepo(ctx context.Context, operationInfo healthcheck.OperationInfo, conn *prometheus.Conn, renderer *PromQLRenderer) *PrometheusRepo {
 	return &PrometheusRepo{
+		ctx:           ctx,
 		operationInfo: operationInfo,
 		conn:          conn,
+		renderer:      renderer,
 	}
 }
 
@@ -470,36 +1976,33 @@ func (pr *PrometheusRepo) getConnection() *prometheus.Conn {
 
 // GetFileSystems gets the file systems from the prometheus
 func (pr *PrometheusRepo) GetFileSystems() ([]healthcheck.FileSystem, error) {
-	var prometheusQuery string
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = PrometheusFileSystemV1
-	case 2:
-		// pmm 2.x
-		prometheusQuery = PrometheusFileSystemV2
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
-	}
-
-	prometheusQuery = fmt.Sprintf(prometheusQuery, pr.getNodeName())
-	log.Debugf("healthcheck PrometheusRepo.GetFileSystems() query: \n%s\n", prometheusQuery)
-	// get data
-	result, err := pr.getConnection().Execute(prometheusQuery)
-	if err != nil {
-		return nil, err
-	}
-	// parse result
-	vector, err := result.Raw.GetVector()
+	prometheusQuery, err := pr.render(PromQLFileSystem, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	log.Debugf("healthcheck PrometheusRepo.GetFileSystems() query: \n%s\n", prometheusQuery)
 	var fileSystems []healthcheck.FileSystem
-	for _, sample := range vector {
-		fileSystems = append(fileSystems, NewFileSystem(string(sample.Metric[mountPointLabel]), string(sample.Metric[deviceLabel])))
+	err = runWithContext(pr.ctx, func() error {
+		// get data
+		result, err := pr.getConnection().Execute(prometheusQuery)
+		if err != nil {
+			return err
+		}
+		// parse result
+		vector, err := result.Raw.GetVector()
+		if err != nil {
+			return err
+		}
+		for _, sample := range vector {
+			fileSystems = append(fileSystems, NewFileSystem(string(sample.Metric[mountPointLabel]), string(sample.Metric[deviceLabel])))
+		}
+
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	return fileSystems, nil
@@ -507,21 +2010,12 @@ func (pr *PrometheusRepo) GetFileSystems() ([]healthcheck.FileSystem, error) {
 
 // GetAvgBackupFailedRatio gets the mysql backup information
 func (pr *PrometheusRepo) GetAvgBackupFailedRatio() ([]healthcheck.PrometheusData, error) {
-	var prometheusQuery string
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = PrometheusAvgBackupFailedRatioV1
-	case 2:
-		// pmm 2.x
-		prometheusQuery = PrometheusAvgBackupFailedRatioV2
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	prometheusQuery, err := pr.render(PromQLAvgBackupFailedRatio, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	prometheusQuery = fmt.Sprintf(prometheusQuery, pr.getNodeName(), pr.getNodeName(), pr.getNodeName(), pr.getNodeName(), pr.getNodeName(), pr.getNodeName(), pr.getNodeName(), pr.getNodeName())
 	log.Debugf("healthcheck PrometheusRepo.GetAvgBackupFailedRatio() query: \n%s\n", prometheusQuery)
 
 	return pr.execute(prometheusQuery)
@@ -529,21 +2023,12 @@ func (pr *PrometheusRepo) GetAvgBackupFailedRatio() ([]healthcheck.PrometheusDat
 
 // GetStatisticFailedRatio gets the statistic of mysql
 func (pr *PrometheusRepo) GetStatisticFailedRatio() ([]healthcheck.PrometheusData, error) {
-	var prometheusQuery string
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = PrometheusStatisticFailedRatioV1
-	case 2:
-		// pmm 2.x
-		prometheusQuery = PrometheusStatisticFailedRatioV2
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	prometheusQuery, err := pr.render(PromQLStatisticFailedRatio, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	prometheusQuery = fmt.Sprintf(prometheusQuery, pr.getNodeName(), pr.getNodeName())
 	log.Debugf("healthcheck PrometheusRepo.GetStatisticFailedRatio() query: \n%s\n", prometheusQuery)
 
 	return pr.execute(prometheusQuery)
@@ -551,22 +2036,12 @@ func (pr *PrometheusRepo) GetStatisticFailedRatio() ([]healthcheck.PrometheusDat
 
 // GetCPUUsage gets the cpu usage
 func (pr *PrometheusRepo) GetCPUUsage() ([]healthcheck.PrometheusData, error) {
-	var prometheusQuery string
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = PrometheusCPUUsageV1
-	case 2:
-		// pmm 2.x
-		prometheusQuery = PrometheusCPUUsageV2
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	prometheusQuery, err := pr.render(PromQLCPUUsage, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	nodeName := pr.getNodeName()
-	prometheusQuery = fmt.Sprintf(prometheusQuery, nodeName, nodeName, nodeName, nodeName, nodeName, nodeName)
 	log.Debugf("healthcheck PrometheusRepo.GetCPUUsage() query: \n%s\n", prometheusQuery)
 
 	return pr.execute(prometheusQuery)
@@ -574,20 +2049,10 @@ func (pr *PrometheusRepo) GetCPUUsage() ([]healthcheck.PrometheusData, error) {
 
 // GetIOUtil gets the io util
 func (pr *PrometheusRepo) GetIOUtil() ([]healthcheck.PrometheusData, error) {
-	var prometheusQuery string
-
-	nodeName := pr.getNodeName()
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = fmt.Sprintf(PrometheusIOUtilV1, nodeName, nodeName)
-	case 2:
-		// pmm 2.x
-		prometheusQuery = fmt.Sprintf(PrometheusIOUtilV2, nodeName, nodeName, nodeName, nodeName)
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	prometheusQuery, err := pr.render(PromQLIOUtil, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Debugf("healthcheck PrometheusRepo.GetIOUtil() query: \n%s\n", prometheusQuery)
@@ -597,21 +2062,10 @@ func (pr *PrometheusRepo) GetIOUtil() ([]healthcheck.PrometheusData, error) {
 
 // GetDiskCapacityUsage gets the disk capacity usage
 func (pr *PrometheusRepo) GetDiskCapacityUsage(mountPoints []string) ([]healthcheck.PrometheusData, error) {
-	var prometheusQuery string
-
-	mps := common.ConvertStringSliceToString(mountPoints, constant.VerticalBarString)
-	nodeName := pr.getNodeName()
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = fmt.Sprintf(PrometheusDiskCapacityV1, nodeName, mps, nodeName, mps)
-	case 2:
-		// pmm 2.x
-		prometheusQuery = fmt.Sprintf(PrometheusDiskCapacityV2, nodeName, mps, nodeName, mps, nodeName, mps, nodeName, mps)
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	prometheusQuery, err := pr.render(PromQLDiskCapacity, mountPoints)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Debugf("healthcheck PrometheusRepo.GetDiskCapacityUsage() query: \n%s\n", prometheusQuery)
@@ -619,24 +2073,51 @@ func (pr *PrometheusRepo) GetDiskCapacityUsage(mountPoints []string) ([]healthch
 	return pr.execute(prometheusQuery)
 }
 
+// GetDiskCapacities gets the size and the used bytes series of the mount points,
+// the size is the latest value of the size series
+func (pr *PrometheusRepo) GetDiskCapacities(mountPoints []string) ([]healthcheck.DiskCapacity, error) {
+	// prepare query
+	sizeQuery, err := pr.render(PromQLDiskSize, mountPoints)
+	if err != nil {
+		return nil, err
+	}
+	usedQuery, err := pr.render(PromQLDiskUsed, mountPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("healthcheck PrometheusRepo.GetDiskCapacities() size query: \n%s\n", sizeQuery)
+	sizes, err := pr.executeByMountPoint(sizeQuery)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("healthcheck PrometheusRepo.GetDiskCapacities() used query: \n%s\n", usedQuery)
+	useds, err := pr.executeByMountPoint(usedQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	var diskCapacities []healthcheck.DiskCapacity
+	for _, mountPoint := range mountPoints {
+		sizeDatas := sizes[mountPoint]
+		if len(sizeDatas) == constant.ZeroInt {
+			continue
+		}
+		size := sizeDatas[len(sizeDatas)-1].GetValue()
+		diskCapacities = append(diskCapacities, NewDiskCapacity(mountPoint, size, useds[mountPoint]))
+	}
+
+	return diskCapacities, nil
+}
+
 // GetConnectionUsage gets the connection usage
 func (pr *PrometheusRepo) GetConnectionUsage() ([]healthcheck.PrometheusData, error) {
-	var prometheusQuery string
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = PrometheusConnectionUsageV1
-	case 2:
-		// pmm 2.x
-		prometheusQuery = PrometheusConnectionUsageV2
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	prometheusQuery, err := pr.render(PromQLConnectionUsage, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	serviceName := pr.getServiceName()
-	prometheusQuery = fmt.Sprintf(prometheusQuery, serviceName, serviceName, serviceName, serviceName)
 	log.Debugf("healthcheck PrometheusRepo.GetConnectionUsage() query: \n%s\n", prometheusQuery)
 	// get data
 	return pr.execute(prometheusQuery)
@@ -644,22 +2125,12 @@ func (pr *PrometheusRepo) GetConnectionUsage() ([]healthcheck.PrometheusData, er
 
 // GetAverageActiveSessionPercents gets the average active session percents
 func (pr *PrometheusRepo) GetAverageActiveSessionPercents() ([]healthcheck.PrometheusData, error) {
-	var prometheusQuery string
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = PrometheusAverageActiveSessionPercentsV1
-	case 2:
-		// pmm 2.x
-		prometheusQuery = PrometheusAverageActiveSessionPercentsV2
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	prometheusQuery, err := pr.render(PromQLAverageActiveSessionPercents, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	serviceName := pr.getServiceName()
-	prometheusQuery = fmt.Sprintf(prometheusQuery, serviceName, serviceName, serviceName, serviceName)
 	log.Debugf("healthcheck PrometheusRepo.GetAverageActiveSessionPercents() query: \n%s\n", prometheusQuery)
 	// get data
 	return pr.execute(prometheusQuery)
@@ -667,22 +2138,12 @@ func (pr *PrometheusRepo) GetAverageActiveSessionPercents() ([]healthcheck.Prome
 
 // GetCacheMissRatio gets the cache miss ratio
 func (pr *PrometheusRepo) GetCacheMissRatio() ([]healthcheck.PrometheusData, error) {
-	var prometheusQuery string
-
 	// prepare query
-	switch pr.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = PrometheusCacheMissRatioV1
-	case 2:
-		// pmm 2.x
-		prometheusQuery = PrometheusCacheMissRatioV2
-	default:
-		return nil, message.NewMessage(msghc.ErrHealthcheckPmmVersionInvalid)
+	prometheusQuery, err := pr.render(PromQLCacheMissRatio, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	serviceName := pr.getServiceName()
-	prometheusQuery = fmt.Sprintf(prometheusQuery, serviceName, serviceName, serviceName, serviceName)
 	log.Debugf("healthcheck PrometheusRepo.getCacheMissRatio() query: \n%s\n", prometheusQuery)
 	// get data
 	return pr.execute(prometheusQuery)
@@ -698,45 +2159,205 @@ func (pr *PrometheusRepo) getNodeName() string {
 	return pr.GetOperationInfo().GetMySQLServer().GetServerName()
 }
 
-// getPMMVersion returns the pmm version
-func (pr *PrometheusRepo) getPMMVersion() int {
-	return pr.GetOperationInfo().GetMonitorSystem().GetSystemType()
+// getNodeSelector returns the label selector of the node exporter series of the generic prometheus monitor system
+func (pr *PrometheusRepo) getNodeSelector() string {
+	return getGenericSelector(viper.GetString(config.DBMonitorGenericNodeLabelKey), pr.getNodeName(),
+		viper.GetString(config.DBMonitorGenericNodeJobKey))
+}
+
+// getServiceSelector returns the label selector of the mysqld exporter series of the generic prometheus monitor system
+func (pr *PrometheusRepo) getServiceSelector() string {
+	return getGenericSelector(viper.GetString(config.DBMonitorGenericServiceLabelKey), pr.getServiceName(),
+		viper.GetString(config.DBMonitorGenericMySQLJobKey))
+}
+
+// render renders the promql template of the given name with the variables of the operation
+func (pr *PrometheusRepo) render(templateName string, mountPoints []string) (string, error) {
+	variables := NewPromQLVariables(pr.getServiceName(), pr.getNodeName(),
+		common.ConvertStringSliceToString(mountPoints, constant.VerticalBarString), pr.GetOperationInfo().GetStep())
+	variables.NodeSelector = pr.getNodeSelector()
+	variables.ServiceSelector = pr.getServiceSelector()
+
+	return pr.renderer.Render(templateName, variables)
 }
 
 // execute executes the given query
 func (pr *PrometheusRepo) execute(query string) ([]healthcheck.PrometheusData, error) {
-	// execute query
-	result, err := pr.getConnection().Execute(query, pr.GetOperationInfo().GetStartTime(),
-		pr.GetOperationInfo().GetEndTime(), pr.GetOperationInfo().GetStep())
+	var datas []healthcheck.PrometheusData
+
+	err := runWithContext(pr.ctx, func() error {
+		// execute query
+		result, err := pr.getConnection().Execute(query, pr.GetOperationInfo().GetStartTime(),
+			pr.GetOperationInfo().GetEndTime(), pr.GetOperationInfo().GetStep())
+		if err != nil {
+			return err
+		}
+		// parse result
+		matrix, err := result.Raw.GetMatrix()
+		if err != nil {
+			return err
+		}
+		for _, sampleStream := range matrix {
+			for _, samplePair := range sampleStream.Values {
+				datas = append(datas, NewPrometheusData(samplePair.Timestamp.String(), float64(samplePair.Value)))
+			}
+		}
+
+		return nil
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
-	// parse result
-	var datas []healthcheck.PrometheusData
 
-	matrix, err := result.Raw.GetMatrix()
+	return datas, nil
+}
+
+// executeByMountPoint executes the given query and groups the series by the mount point
+func (pr *PrometheusRepo) executeByMountPoint(query string) (map[string][]healthcheck.PrometheusData, error) {
+	datas := make(map[string][]healthcheck.PrometheusData)
+
+	err := runWithContext(pr.ctx, func() error {
+		// execute query
+		result, err := pr.getConnection().Execute(query, pr.GetOperationInfo().GetStartTime(),
+			pr.GetOperationInfo().GetEndTime(), pr.GetOperationInfo().GetStep())
+		if err != nil {
+			return err
+		}
+		// parse result
+		matrix, err := result.Raw.GetMatrix()
+		if err != nil {
+			return err
+		}
+		for _, sampleStream := range matrix {
+			mountPoint := string(sampleStream.Metric[mountPointLabel])
+			for _, samplePair := range sampleStream.Values {
+				datas[mountPoint] = append(datas[mountPoint], NewPrometheusData(samplePair.Timestamp.String(), float64(samplePair.Value)))
+			}
+		}
+
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+type MiddlewarePrometheusRepo struct {
+	ctx       context.Context
+	conn      *prometheus.Conn
+	startTime time.Time
+	endTime   time.Time
+	step      time.Duration
+	renderer  *PromQLRenderer
+}
+
+// NewMiddlewarePrometheusRepo returns a new *MiddlewarePrometheusRepo, the queries could not be interrupted, no more query runs after ctx is done,
+// the queries are rendered from the promql templates of the monitor system by renderer
+func NewMiddlewarePrometheusRepo(ctx context.Context, conn *prometheus.Conn,
+	startTime, endTime time.Time, step time.Duration, renderer *PromQLRenderer) *MiddlewarePrometheusRepo {
+	return &MiddlewarePrometheusRepo{
+		ctx:       ctx,
+		conn:      conn,
+		startTime: startTime,
+		endTime:   endTime,
+		step:      step,
+		renderer:  renderer,
+	}
+}
+
+// getConnection returns the connection
+func (mpr *MiddlewarePrometheusRepo) getConnection() *prometheus.Conn {
+	return mpr.conn
+}
+
+// GetCPUUsage gets the cpu usage of the middleware server
+func (mpr *MiddlewarePrometheusRepo) GetCPUUsage(serverName string) ([]healthcheck.PrometheusData, error) {
+	// prepare query
+	prometheusQuery, err := mpr.render(PromQLCPUUsage, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("healthcheck MiddlewarePrometheusRepo.GetCPUUsage() query: \n%s\n", prometheusQuery)
+
+	return mpr.execute(prometheusQuery)
+}
+
+// GetMemoryUsage gets the memory usage of the middleware server
+func (mpr *MiddlewarePrometheusRepo) GetMemoryUsage(serverName string) ([]healthcheck.PrometheusData, error) {
+	// prepare query
+	prometheusQuery, err := mpr.render(PromQLMemoryUsage, serverName)
 	if err != nil {
 		return nil, err
 	}
-	for _, sampleStream := range matrix {
-		for _, samplePair := range sampleStream.Values {
-			datas = append(datas, NewPrometheusData(samplePair.Timestamp.String(), float64(samplePair.Value)))
+
+	log.Debugf("healthcheck MiddlewarePrometheusRepo.GetMemoryUsage() query: \n%s\n", prometheusQuery)
+
+	return mpr.execute(prometheusQuery)
+}
+
+// getNodeSelector returns the label selector of the node exporter series of the generic prometheus monitor system
+func (mpr *MiddlewarePrometheusRepo) getNodeSelector(serverName string) string {
+	return getGenericSelector(viper.GetString(config.DBMonitorGenericNodeLabelKey), serverName,
+		viper.GetString(config.DBMonitorGenericNodeJobKey))
+}
+
+// render renders the promql template of the given name with the variables of the middleware server
+func (mpr *MiddlewarePrometheusRepo) render(templateName, serverName string) (string, error) {
+	variables := NewPromQLVariables(constant.EmptyString, serverName, constant.EmptyString, mpr.step)
+	variables.NodeSelector = mpr.getNodeSelector(serverName)
+
+	return mpr.renderer.Render(templateName, variables)
+}
+
+// execute executes the given query
+func (mpr *MiddlewarePrometheusRepo) execute(query string) ([]healthcheck.PrometheusData, error) {
+	var datas []healthcheck.PrometheusData
+
+	err := runWithContext(mpr.ctx, func() error {
+		// execute query
+		result, err := mpr.getConnection().Execute(query, mpr.startTime, mpr.endTime, mpr.step)
+		if err != nil {
+			return err
 		}
+		// parse result
+		matrix, err := result.Raw.GetMatrix()
+		if err != nil {
+			return err
+		}
+		for _, sampleStream := range matrix {
+			for _, samplePair := range sampleStream.Values {
+				datas = append(datas, NewPrometheusData(samplePair.Timestamp.String(), float64(samplePair.Value)))
+			}
+		}
+
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	return datas, nil
 }
 
 type MySQLQueryRepo struct {
+	ctx           context.Context
 	operationInfo healthcheck.OperationInfo
 	conn          *mysql.Conn
+	killer        *mysqlKiller
 }
 
-// NewMySQLQueryRepo returns the new *MySQLQueryRepo
-func NewMySQLQueryRepo(operationInfo healthcheck.OperationInfo, conn *mysql.Conn) *MySQLQueryRepo {
+// NewMySQLQueryRepo returns the new *MySQLQueryRepo, the running query will be killed when ctx is done
+func NewMySQLQueryRepo(ctx context.Context, operationInfo healthcheck.OperationInfo, conn *mysql.Conn) *MySQLQueryRepo {
+	slowQueryAddr := fmt.Sprintf("%s:%d", operationInfo.GetMonitorSystem().GetHostIP(), operationInfo.GetMonitorSystem().GetPortNumSlow())
+
 	return &MySQLQueryRepo{
+		ctx:           ctx,
 		operationInfo: operationInfo,
 		conn:          conn,
+		killer:        newMySQLKiller(slowQueryAddr, viper.GetString(config.DBMonitorMySQLUserKey), viper.GetString(config.DBMonitorMySQLPassKey)),
 	}
 }
 
@@ -758,7 +2379,7 @@ func (mqr *MySQLQueryRepo) Close() error {
 // GetSlowQuery gets the slow query
 func (mqr *MySQLQueryRepo) GetSlowQuery() ([]depquery.Query, error) {
 	// get result
-	result, err := mqr.getConnection().Execute(MonitorMySQLQuery, mqr.getServiceName(), mqr.GetOperationInfo().GetStartTime(),
+	result, err := executeWithContext(mqr.ctx, mqr.getConnection(), mqr.killer, MonitorMySQLQuery, mqr.getServiceName(), mqr.GetOperationInfo().GetStartTime(),
 		mqr.GetOperationInfo().GetEndTime(), minRowsExamined, SlowQueryNumLimit)
 	if err != nil {
 		return nil, err
@@ -787,13 +2408,15 @@ func (mqr *MySQLQueryRepo) getPMMVersion() int {
 }
 
 type ClickhouseQueryRepo struct {
+	ctx           context.Context
 	operationInfo healthcheck.OperationInfo
 	conn          *clickhouse.Conn
 }
 
-// NewClickhouseQueryRepo returns the new *ClickhouseQueryRepo
-func NewClickhouseQueryRepo(operationInfo healthcheck.OperationInfo, conn *clickhouse.Conn) *ClickhouseQueryRepo {
+// NewClickhouseQueryRepo returns the new *ClickhouseQueryRepo, the query could not be interrupted, it does not run after ctx is done
+func NewClickhouseQueryRepo(ctx context.Context, operationInfo healthcheck.OperationInfo, conn *clickhouse.Conn) *ClickhouseQueryRepo {
 	return &ClickhouseQueryRepo{
+		ctx:           ctx,
 		operationInfo: operationInfo,
 		conn:          conn,
 	}
@@ -817,9 +2440,14 @@ func (cqr *ClickhouseQueryRepo) Close() error {
 // GetSlowQuery gets the slow query
 func (cqr *ClickhouseQueryRepo) GetSlowQuery() ([]depquery.Query, error) {
 	// get result
-	result, err := cqr.getConnection().Execute(MonitorClickhouseQuery, cqr.getServiceName(), cqr.GetOperationInfo().GetStartTime(),
-		cqr.GetOperationInfo().GetEndTime(), minRowsExamined, SlowQueryNumLimit,
-		cqr.getServiceName(), cqr.GetOperationInfo().GetStartTime(), cqr.GetOperationInfo().GetEndTime(), minRowsExamined)
+	var result middleware.Result
+	err := runWithContext(cqr.ctx, func() error {
+		var err error
+		result, err = cqr.getConnection().Execute(MonitorClickhouseQuery, cqr.getServiceName(), cqr.GetOperationInfo().GetStartTime(),
+			cqr.GetOperationInfo().GetEndTime(), minRowsExamined, SlowQueryNumLimit,
+			cqr.getServiceName(), cqr.GetOperationInfo().GetStartTime(), cqr.GetOperationInfo().GetEndTime(), minRowsExamined)
+		return err
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -845,3 +2473,112 @@ func (cqr *ClickhouseQueryRepo) getServiceName() string {
 func (cqr *ClickhouseQueryRepo) getPMMVersion() int {
 	return cqr.GetOperationInfo().GetMonitorSystem().GetSystemType()
 }
+
+type PerformanceSchemaQueryRepo struct {
+	ctx           context.Context
+	operationInfo healthcheck.OperationInfo
+	conn          *mysql.Conn
+	killer        *mysqlKiller
+}
+
+// NewPerformanceSchemaQueryRepo returns the new *PerformanceSchemaQueryRepo,
+// conn should connect to the application mysql server, the running query will be killed when ctx is done
+func NewPerformanceSchemaQueryRepo(ctx context.Context, operationInfo healthcheck.OperationInfo, conn *mysql.Conn) *PerformanceSchemaQueryRepo {
+	mysqlServerAddr := fmt.Sprintf("%s:%d", operationInfo.GetMySQLServer().GetHostIP(), operationInfo.GetMySQLServer().GetPortNum())
+
+	return &PerformanceSchemaQueryRepo{
+		ctx:           ctx,
+		operationInfo: operationInfo,
+		conn:          conn,
+		killer: newMySQLKiller(mysqlServerAddr, viper.GetString(config.DBApplicationMySQLUserKey),
+			viper.GetString(config.DBApplicationMySQLPassKey)),
+	}
+}
+
+// GetOperationInfo returns the operation information
+func (psqr *PerformanceSchemaQueryRepo) GetOperationInfo() healthcheck.OperationInfo {
+	return psqr.operationInfo
+}
+
+// getConnection returns the connection
+func (psqr *PerformanceSchemaQueryRepo) getConnection() *mysql.Conn {
+	return psqr.conn
+}
+
+// Close closes the connection
+func (psqr *PerformanceSchemaQueryRepo) Close() error {
+	return psqr.getConnection().Close()
+}
+
+// GetSlowQuery gets the slow query from performance_schema.events_statements_summary_by_digest of the application mysql server
+func (psqr *PerformanceSchemaQueryRepo) GetSlowQuery() ([]depquery.Query, error) {
+	// get result
+	result, err := executeWithContext(psqr.ctx, psqr.getConnection(), psqr.killer, PerformanceSchemaQuery, psqr.GetOperationInfo().GetStartTime(),
+		psqr.GetOperationInfo().GetEndTime(), minRowsExamined, SlowQueryNumLimit)
+	if err != nil {
+		return nil, err
+	}
+	// map result to slice
+	queries := make([]depquery.Query, result.RowNumber())
+	for i := constant.ZeroInt; i < result.RowNumber(); i++ {
+		queries[i] = query.NewEmptyQuery()
+	}
+	err = result.MapToStructSlice(queries, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
+
+type SlowLogQueryRepo struct {
+	ctx           context.Context
+	operationInfo healthcheck.OperationInfo
+	dasRepo       healthcheck.DASRepo
+}
+
+// NewSlowLogQueryRepo returns the new *SlowLogQueryRepo, the slow queries are read from the slow log statistics
+// which are ingested from the slow log files into the das database, the query does not run after ctx is done
+func NewSlowLogQueryRepo(ctx context.Context, operationInfo healthcheck.OperationInfo, dasRepo healthcheck.DASRepo) *SlowLogQueryRepo {
+	return &SlowLogQueryRepo{
+		ctx:           ctx,
+		operationInfo: operationInfo,
+		dasRepo:       dasRepo,
+	}
+}
+
+// GetOperationInfo returns the operation information
+func (slqr *SlowLogQueryRepo) GetOperationInfo() healthcheck.OperationInfo {
+	return slqr.operationInfo
+}
+
+// Close does nothing, the connections of the das repository are returned to the pool after each execution
+func (slqr *SlowLogQueryRepo) Close() error {
+	return nil
+}
+
+// GetSlowQuery gets the slow query from the slow log statistics of the mysql server
+func (slqr *SlowLogQueryRepo) GetSlowQuery() ([]depquery.Query, error) {
+	// get result
+	var result middleware.Result
+	err := runWithContext(slqr.ctx, func() error {
+		var err error
+		result, err = slqr.dasRepo.Execute(SlowLogQuery, slqr.GetOperationInfo().GetMySQLServer().Identity(),
+			slqr.GetOperationInfo().GetStartTime(), slqr.GetOperationInfo().GetEndTime(), minRowsExamined, SlowQueryNumLimit)
+		return err
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	// map result to slice
+	queries := make([]depquery.Query, result.RowNumber())
+	for i := constant.ZeroInt; i < result.RowNumber(); i++ {
+		queries[i] = query.NewEmptyQuery()
+	}
+	err = result.MapToStructSlice(queries, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
@@ -1,12 +1,14 @@
 package healthcheck
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/romberli/das/config"
 	"github.com/romberli/das/global"
 	"github.com/romberli/das/internal/app/metadata"
 	"github.com/romberli/das/internal/dependency/healthcheck"
@@ -18,6 +20,7 @@ import (
 	"github.com/romberli/go-util/middleware/mysql"
 	"github.com/romberli/go-util/middleware/prometheus"
 	"github.com/romberli/log"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,6 +45,8 @@ const (
 	testHealthcheckVariableName  = "datadir"
 	testHealthcheckVariableValue = "/data/mysql/mysqld_multi/mysqld3306/data"
 	testHealthcheckFileSystemNum = 3
+
+	testItemFeedbackComment = "test item feedback comment"
 )
 
 var (
@@ -143,7 +148,7 @@ func testInitApplicationMySQLRepo() *ApplicationMySQLRepo {
 		os.Exit(constant.DefaultAbnormalExitCode)
 	}
 
-	return newApplicationMySQLRepo(testOperationInfo, conn)
+	return newApplicationMySQLRepo(context.Background(), testOperationInfo, conn)
 }
 
 func testInitPrometheusRepo() *PrometheusRepo {
@@ -154,7 +159,7 @@ func testInitPrometheusRepo() *PrometheusRepo {
 	switch testOperationInfo.GetMonitorSystem().GetSystemType() {
 	case 1:
 		cfg = prometheus.NewConfig(addr, prometheus.DefaultRoundTripper)
-	case 2:
+	case 2, 3:
 		cfg = prometheus.NewConfigWithBasicAuth(addr, testHealthcheckPrometheusUser, testHealthcheckPrometheusPass)
 	}
 
@@ -164,7 +169,9 @@ func testInitPrometheusRepo() *PrometheusRepo {
 		os.Exit(constant.DefaultAbnormalExitCode)
 	}
 
-	return NewPrometheusRepo(testOperationInfo, conn)
+	renderer := NewPromQLRenderer(testOperationInfo.GetMonitorSystem().GetSystemType(), nil)
+
+	return NewPrometheusRepo(context.Background(), testOperationInfo, conn, renderer)
 }
 
 func testInitQueryRepo() healthcheck.QueryRepo {
@@ -178,14 +185,21 @@ func testInitQueryRepo() healthcheck.QueryRepo {
 			log.Error(common.CombineMessageWithError("testInitQueryRepo() failed", err))
 			os.Exit(constant.DefaultAbnormalExitCode)
 		}
-		queryRepo = NewMySQLQueryRepo(testOperationInfo, conn)
+		queryRepo = NewMySQLQueryRepo(context.Background(), testOperationInfo, conn)
 	case 2:
 		conn, err := clickhouse.NewConnWithDefault(addr, testHealthcheckDBName, constant.EmptyString, constant.EmptyString)
 		if err != nil {
 			log.Error(common.CombineMessageWithError("testInitQueryRepo() failed", err))
 			os.Exit(constant.DefaultAbnormalExitCode)
 		}
-		queryRepo = NewClickhouseQueryRepo(testOperationInfo, conn)
+		queryRepo = NewClickhouseQueryRepo(context.Background(), testOperationInfo, conn)
+	case 3:
+		conn, err := mysql.NewConn(testHealthcheckApplicationAddr, performanceSchema, testHealthcheckApplicationUser, testHealthcheckApplicationPass)
+		if err != nil {
+			log.Error(common.CombineMessageWithError("testInitQueryRepo() failed", err))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+		queryRepo = NewPerformanceSchemaQueryRepo(context.Background(), testOperationInfo, conn)
 	}
 
 	return queryRepo
@@ -243,6 +257,34 @@ func testDeleteOperationInfoByID(id int) error {
 	return err
 }
 
+func testDeleteClusterOperationByID(id int) error {
+	sql := `delete from t_hc_cluster_operation_history where id = ?`
+	_, err := testDASRepo.Execute(sql, id)
+
+	return err
+}
+
+func testDeleteClusterResultByID(id int) error {
+	sql := `delete from t_hc_cluster_result where id = ?`
+	_, err := testDASRepo.Execute(sql, id)
+
+	return err
+}
+
+func testDeleteMiddlewareOperationByID(id int) error {
+	sql := `delete from t_hc_middleware_operation_history where id = ?`
+	_, err := testDASRepo.Execute(sql, id)
+
+	return err
+}
+
+func testDeleteMiddlewareResultByID(id int) error {
+	sql := `delete from t_hc_middleware_result where id = ?`
+	_, err := testDASRepo.Execute(sql, id)
+
+	return err
+}
+
 func testDeleteResultByID(id int) error {
 	sql := `delete from t_hc_result where id = ?`
 	_, err := testDASRepo.Execute(sql, id)
@@ -250,19 +292,117 @@ func testDeleteResultByID(id int) error {
 	return err
 }
 
+func testDeleteSnapshotByOperationID(operationID int) error {
+	sql := `delete from t_hc_snapshot where operation_id = ?`
+	_, err := testDASRepo.Execute(sql, operationID)
+
+	return err
+}
+
+func testDeleteItemResultsByOperationID(operationID int) error {
+	sql := `delete from t_hc_item_result where operation_id = ?`
+	_, err := testDASRepo.Execute(sql, operationID)
+
+	return err
+}
+
+func testDeleteItemFeedbacksByOperationID(operationID int) error {
+	sql := `delete from t_hc_item_feedback where operation_id = ?`
+	_, err := testDASRepo.Execute(sql, operationID)
+
+	return err
+}
+
+func testDeleteProgressByOperationID(operationID int) error {
+	sql := `delete from t_hc_operation_progress where operation_id = ?`
+	_, err := testDASRepo.Execute(sql, operationID)
+
+	return err
+}
+
+// testDeleteCallbackURLsByOperationID deletes the callback urls of the operation
+func testDeleteCallbackURLsByOperationID(operationID int) error {
+	sql := `delete from t_hc_operation_callback where operation_id = ?`
+	_, err := testDASRepo.Execute(sql, operationID)
+
+	return err
+}
+
+// testInitItemFeedback initiates an operation and saves an inaccurate feedback of the cpu usage item of the operation
+func testInitItemFeedback() (int, error) {
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return id, testDASRepo.SaveItemFeedback(NewItemFeedback(id, defaultCPUUsageItemName, ItemAccuracyInaccurate, testItemFeedbackComment, testHealthcheckLoginName))
+}
+
 func TestRepository_All(t *testing.T) {
 	// das repository
 	TestDASRepo_Execute(t)
 	TestDASRepo_GetResultByOperationID(t)
+	TestDASRepo_GetResultsByMySQLServerIDs(t)
 	TestDASRepo_IsRunning(t)
+	TestDASRepo_GetOperationHistoryByID(t)
 	TestDASRepo_InitOperation(t)
 	TestDASRepo_UpdateOperationStatus(t)
+	TestDASRepo_CompareAndUpdateOperationStatus(t)
+	TestDASRepo_QueueOperation(t)
+	TestDASRepo_StartQueuedOperation(t)
+	TestDASRepo_GetOperationHistoriesByStatus(t)
+	TestDASRepo_UpdateOperationEngineConfig(t)
+	TestDASRepo_LoadEngineConfigByProfileID(t)
+	TestDASRepo_GetScoringProfileIDByMySQLClusterID(t)
+	TestDASRepo_LoadEngineConfigWithVersionByProfileID(t)
+	TestDASRepo_LoadDBConfigRules(t)
 	TestDASRepo_SaveResult(t)
+	TestDASRepo_GetItemResultsByOperationID(t)
 	TestDASRepo_UpdateAccuracyReviewByOperationID(t)
+	TestDASRepo_SaveItemFeedback(t)
+	TestDASRepo_GetItemFeedbacksByOperationID(t)
+	TestDASRepo_GetFeedbackStatsByTimeRange(t)
+	TestDASRepo_SaveProgress(t)
+	TestDASRepo_GetProgressByOperationID(t)
+	TestDASRepo_SaveCallbackURLs(t)
+	TestDASRepo_GetCallbackURLsByOperationID(t)
+	TestDASRepo_InitClusterOperation(t)
+	TestDASRepo_UpdateClusterOperationStatus(t)
+	TestDASRepo_SaveClusterResult(t)
+	TestDASRepo_GetClusterResultByClusterOperationID(t)
+	TestDASRepo_GetMySQLClusterIDsByMiddlewareClusterID(t)
+	TestDASRepo_IsMiddlewareRunning(t)
+	TestDASRepo_InitMiddlewareOperation(t)
+	TestDASRepo_UpdateMiddlewareOperationStatus(t)
+	TestDASRepo_SaveMiddlewareResult(t)
+	TestDASRepo_GetMiddlewareResultByMiddlewareOperationID(t)
+	TestDASRepo_SaveSnapshot(t)
+	TestDASRepo_GetSnapshotByOperationID(t)
 	// application mysql repository
 	TestApplicationMySQLRepo_GetVariables(t)
 	TestApplicationMySQLRepo_GetMySQLDirs(t)
 	TestApplicationMySQLRepo_GetLargeTables(t)
+	TestApplicationMySQLRepo_GetReplicationStatus(t)
+	TestApplicationMySQLRepo_GetGTIDExecuted(t)
+	TestApplicationMySQLRepo_GetGTIDSubtract(t)
+	TestApplicationMySQLRepo_GetGroupMembers(t)
+	TestApplicationMySQLRepo_GetLongTransactions(t)
+	TestApplicationMySQLRepo_GetHistoryListLength(t)
+	TestApplicationMySQLRepo_GetLockWaits(t)
+	TestApplicationMySQLRepo_GetLatestDeadlock(t)
+	TestApplicationMySQLRepo_GetTableSchemas(t)
+	TestApplicationMySQLRepo_GetIndexStatistics(t)
+	TestApplicationMySQLRepo_GetAutoIncrements(t)
+	TestApplicationMySQLRepo_GetUsers(t)
+	TestApplicationMySQLRepo_GetGrants(t)
 	// prometheus repository
 	TestPrometheusRepo_GetFileSystems(t)
 	TestPrometheusRepo_GetAvgBackupFailedRatio(t)
@@ -270,11 +410,14 @@ func TestRepository_All(t *testing.T) {
 	TestPrometheusRepo_GetCPUUsage(t)
 	TestPrometheusRepo_GetIOUtil(t)
 	TestPrometheusRepo_GetDiskCapacityUsage(t)
+	TestPrometheusRepo_GetDiskCapacities(t)
 	TestPrometheusRepo_GetConnectionUsage(t)
 	TestPrometheusRepo_GetAverageActiveSessionPercents(t)
 	TestPrometheusRepo_GetCacheMissRatio(t)
 	TestPrometheusRepo_getServiceName(t)
-	TestPrometheusRepo_getPMMVersion(t)
+	TestPrometheusRepo_getNodeSelector(t)
+	TestPrometheusRepo_getServiceSelector(t)
+	TestPrometheusRepo_render(t)
 	TestPrometheusRepo_execute(t)
 	// mysql query repository
 	TestMySQLQueryRepo_GetSlowQuery(t)
@@ -284,6 +427,10 @@ func TestRepository_All(t *testing.T) {
 	TestClickhouseQueryRepo_GetSlowQuery(t)
 	TestClickhouseQueryRepo_getServiceName(t)
 	TestClickhouseQueryRepo_getPMMVersion(t)
+	// performance schema query repository
+	TestPerformanceSchemaQueryRepo_GetSlowQuery(t)
+	// slow log query repository
+	TestSlowLogQueryRepo_GetSlowQuery(t)
 }
 
 func TestDASRepo_Execute(t *testing.T) {
@@ -383,11 +530,30 @@ func TestDASRepo_GetResultByOperationID(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test GetResultByOperationID() failed", err))
 }
 
+func TestDASRepo_GetResultsByMySQLServerIDs(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDASRepo.SaveResult(testResult)
+	asst.Nil(err, common.CombineMessageWithError("test GetResultsByMySQLServerIDs() failed", err))
+	results, err := testDASRepo.GetResultsByMySQLServerIDs([]int{testHealthcheckMySQLServerID}, time.Now().Add(-constant.Week), time.Now().Add(time.Hour))
+	asst.Nil(err, common.CombineMessageWithError("test GetResultsByMySQLServerIDs() failed", err))
+	for i := 1; i < len(results); i++ {
+		asst.False(results[i].GetCreateTime().Before(results[i-1].GetCreateTime()), "test GetResultsByMySQLServerIDs() failed")
+	}
+	// delete
+	result, err := testDASRepo.GetResultByOperationID(testResult.GetOperationID())
+	asst.Nil(err, common.CombineMessageWithError("test GetResultsByMySQLServerIDs() failed", err))
+	err = testDeleteResultByID(result.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetResultsByMySQLServerIDs() failed", err))
+}
+
 func TestDASRepo_IsRunning(t *testing.T) {
 	asst := assert.New(t)
 
 	id, err := testDASRepo.InitOperation(
 		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
 		testHealthcheckMySQLServerID,
 		time.Now().Add(-constant.Week),
 		time.Now(),
@@ -402,11 +568,38 @@ func TestDASRepo_IsRunning(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test IsRunning() failed", err))
 }
 
+func TestDASRepo_GetOperationHistoryByID(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test GetOperationHistoryByID() failed", err))
+	operationHistory, err := testDASRepo.GetOperationHistoryByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetOperationHistoryByID() failed", err))
+	asst.Equal(id, operationHistory.GetID(), "test GetOperationHistoryByID() failed")
+	asst.Equal(testHealthcheckMySQLServerID, operationHistory.GetMySQLServerID(), "test GetOperationHistoryByID() failed")
+	// delete
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetOperationHistoryByID() failed", err))
+	// the operation does not exist
+	_, err = testDASRepo.GetOperationHistoryByID(id)
+	asst.NotNil(err, "test GetOperationHistoryByID() failed")
+}
+
 func TestDASRepo_InitOperation(t *testing.T) {
 	asst := assert.New(t)
 
 	id, err := testDASRepo.InitOperation(
 		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
 		testHealthcheckMySQLServerID,
 		time.Now().Add(-constant.Week),
 		time.Now(),
@@ -423,6 +616,8 @@ func TestDASRepo_UpdateOperationStatus(t *testing.T) {
 
 	id, err := testDASRepo.InitOperation(
 		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
 		testHealthcheckMySQLServerID,
 		time.Now().Add(-constant.Week),
 		time.Now(),
@@ -442,6 +637,33 @@ func TestDASRepo_UpdateOperationStatus(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test UpdateOperationStatus() failed", err))
 }
 
+func TestDASRepo_CompareAndUpdateOperationStatus(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test CompareAndUpdateOperationStatus() failed", err))
+	err = testDASRepo.UpdateOperationStatus(id, defaultRunningStatus, constant.EmptyString)
+	asst.Nil(err, common.CombineMessageWithError("test CompareAndUpdateOperationStatus() failed", err))
+	ok, err := testDASRepo.CompareAndUpdateOperationStatus(id, defaultRunningStatus, defaultCancelledStatus, constant.EmptyString)
+	asst.Nil(err, common.CombineMessageWithError("test CompareAndUpdateOperationStatus() failed", err))
+	asst.True(ok, "test CompareAndUpdateOperationStatus() failed")
+	// the operation is not running any more
+	ok, err = testDASRepo.CompareAndUpdateOperationStatus(id, defaultRunningStatus, defaultSuccessStatus, constant.EmptyString)
+	asst.Nil(err, common.CombineMessageWithError("test CompareAndUpdateOperationStatus() failed", err))
+	asst.False(ok, "test CompareAndUpdateOperationStatus() failed")
+	// delete
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test CompareAndUpdateOperationStatus() failed", err))
+}
+
 func TestDASRepo_SaveResult(t *testing.T) {
 	asst := assert.New(t)
 
@@ -455,6 +677,237 @@ func TestDASRepo_SaveResult(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test SaveResult() failed", err))
 }
 
+func TestDASRepo_GetItemResultsByOperationID(t *testing.T) {
+	asst := assert.New(t)
+
+	result := testInitResult()
+	result.ItemResults = []healthcheck.ItemResult{
+		NewItemResult(result.GetOperationID(), defaultCPUUsageItemName, DataSourcePrometheus, testItemResultItemWeight,
+			result.GetCPUUsageScore(), defaultEmptyJSONArray, defaultEmptyJSONArray, constant.EmptyString),
+	}
+	err := testDASRepo.SaveResult(result)
+	asst.Nil(err, common.CombineMessageWithError("test GetItemResultsByOperationID() failed", err))
+	itemResults, err := testDASRepo.GetItemResultsByOperationID(result.GetOperationID())
+	asst.Nil(err, common.CombineMessageWithError("test GetItemResultsByOperationID() failed", err))
+	asst.Equal(1, len(itemResults), "test GetItemResultsByOperationID() failed")
+	asst.Equal(defaultCPUUsageItemName, itemResults[constant.ZeroInt].GetItemName(), "test GetItemResultsByOperationID() failed")
+	r, err := testDASRepo.GetResultByOperationID(result.GetOperationID())
+	asst.Nil(err, common.CombineMessageWithError("test GetItemResultsByOperationID() failed", err))
+	asst.Equal(1, len(r.GetItemResults()), "test GetItemResultsByOperationID() failed")
+	// delete
+	err = testDeleteItemResultsByOperationID(result.GetOperationID())
+	asst.Nil(err, common.CombineMessageWithError("test GetItemResultsByOperationID() failed", err))
+	err = testDeleteResultByID(r.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetItemResultsByOperationID() failed", err))
+}
+
+func TestDASRepo_SaveSnapshot(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDASRepo.SaveSnapshot(testResultOperationID, testInitSnapshot())
+	asst.Nil(err, common.CombineMessageWithError("test SaveSnapshot() failed", err))
+	snapshot, err := testDASRepo.GetSnapshotByOperationID(testResultOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test SaveSnapshot() failed", err))
+	asst.Equal(testResultHostIP, snapshot.GetHostIP(), "test SaveSnapshot() failed")
+	// delete
+	err = testDeleteSnapshotByOperationID(testResultOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test SaveSnapshot() failed", err))
+}
+
+func TestDASRepo_QueueOperation(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test QueueOperation() failed", err))
+	err = testDASRepo.QueueOperation(id, defaultScheduledPriority)
+	asst.Nil(err, common.CombineMessageWithError("test QueueOperation() failed", err))
+	operationHistory, err := testDASRepo.GetOperationHistoryByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test QueueOperation() failed", err))
+	asst.Equal(defaultQueuedStatus, operationHistory.GetStatus(), "test QueueOperation() failed")
+	asst.Equal(defaultScheduledPriority, operationHistory.GetPriority(), "test QueueOperation() failed")
+	// the queued operation is considered as running
+	isRunning, err := testDASRepo.IsRunning(testHealthcheckMySQLServerID)
+	asst.Nil(err, common.CombineMessageWithError("test QueueOperation() failed", err))
+	asst.True(isRunning, "test QueueOperation() failed")
+	// delete
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test QueueOperation() failed", err))
+}
+
+func TestDASRepo_StartQueuedOperation(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test StartQueuedOperation() failed", err))
+	// the operation is not queued
+	ok, err := testDASRepo.StartQueuedOperation(id)
+	asst.Nil(err, common.CombineMessageWithError("test StartQueuedOperation() failed", err))
+	asst.False(ok, "test StartQueuedOperation() failed")
+	err = testDASRepo.QueueOperation(id, defaultManualPriority)
+	asst.Nil(err, common.CombineMessageWithError("test StartQueuedOperation() failed", err))
+	ok, err = testDASRepo.StartQueuedOperation(id)
+	asst.Nil(err, common.CombineMessageWithError("test StartQueuedOperation() failed", err))
+	asst.True(ok, "test StartQueuedOperation() failed")
+	// the operation could be started only once
+	ok, err = testDASRepo.StartQueuedOperation(id)
+	asst.Nil(err, common.CombineMessageWithError("test StartQueuedOperation() failed", err))
+	asst.False(ok, "test StartQueuedOperation() failed")
+	operationHistory, err := testDASRepo.GetOperationHistoryByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test StartQueuedOperation() failed", err))
+	asst.Equal(defaultRunningStatus, operationHistory.GetStatus(), "test StartQueuedOperation() failed")
+	// delete
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test StartQueuedOperation() failed", err))
+}
+
+func TestDASRepo_GetOperationHistoriesByStatus(t *testing.T) {
+	asst := assert.New(t)
+
+	var idList []int
+	for _, priority := range []int{defaultScheduledPriority, defaultManualPriority} {
+		id, err := testDASRepo.InitOperation(
+			testOperationInfo.GetUser().Identity(),
+			constant.ZeroInt,
+			constant.ZeroInt,
+			testHealthcheckMySQLServerID,
+			time.Now().Add(-constant.Week),
+			time.Now(),
+			testHealthcheckStep,
+		)
+		asst.Nil(err, common.CombineMessageWithError("test GetOperationHistoriesByStatus() failed", err))
+		err = testDASRepo.QueueOperation(id, priority)
+		asst.Nil(err, common.CombineMessageWithError("test GetOperationHistoriesByStatus() failed", err))
+		idList = append(idList, id)
+	}
+	operationHistories, err := testDASRepo.GetOperationHistoriesByStatus(defaultQueuedStatus)
+	asst.Nil(err, common.CombineMessageWithError("test GetOperationHistoriesByStatus() failed", err))
+	var queuedIDList []int
+	for _, operationHistory := range operationHistories {
+		asst.Equal(defaultQueuedStatus, operationHistory.GetStatus(), "test GetOperationHistoriesByStatus() failed")
+		if operationHistory.GetID() == idList[constant.ZeroInt] || operationHistory.GetID() == idList[1] {
+			queuedIDList = append(queuedIDList, operationHistory.GetID())
+		}
+	}
+	// the operation with higher priority comes first
+	asst.Equal([]int{idList[1], idList[constant.ZeroInt]}, queuedIDList, "test GetOperationHistoriesByStatus() failed")
+	// delete
+	for _, id := range idList {
+		err = testDeleteOperationInfoByID(id)
+		asst.Nil(err, common.CombineMessageWithError("test GetOperationHistoriesByStatus() failed", err))
+	}
+}
+
+func TestDASRepo_UpdateOperationEngineConfig(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateOperationEngineConfig() failed", err))
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test UpdateOperationEngineConfig() failed", err))
+	err = testDASRepo.UpdateOperationEngineConfig(id, profile.Identity(), testEngineConfigVersion)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateOperationEngineConfig() failed", err))
+	operationHistory, err := testDASRepo.GetOperationHistoryByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateOperationEngineConfig() failed", err))
+	asst.Equal(profile.Identity(), operationHistory.GetProfileID(), "test UpdateOperationEngineConfig() failed")
+	asst.Equal(testEngineConfigVersion, operationHistory.GetConfigVersion(), "test UpdateOperationEngineConfig() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test UpdateOperationEngineConfig() failed", err))
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateOperationEngineConfig() failed", err))
+}
+
+func TestDASRepo_LoadEngineConfigByProfileID(t *testing.T) {
+	asst := assert.New(t)
+
+	defaultEngineConfig, err := testDASRepo.LoadEngineConfigByProfileID(defaultScoringProfileID)
+	asst.Nil(err, common.CombineMessageWithError("test LoadEngineConfigByProfileID() failed", err))
+	asst.Nil(defaultEngineConfig.Validate(), "test LoadEngineConfigByProfileID() failed")
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test LoadEngineConfigByProfileID() failed", err))
+	engineConfig, err := testDASRepo.LoadEngineConfigByProfileID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test LoadEngineConfigByProfileID() failed", err))
+	asst.Equal(defaultEngineConfig.GetItemNames(), engineConfig.GetItemNames(), "test LoadEngineConfigByProfileID() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test LoadEngineConfigByProfileID() failed", err))
+}
+
+func TestDASRepo_GetScoringProfileIDByMySQLClusterID(t *testing.T) {
+	asst := assert.New(t)
+
+	profileID, err := testDASRepo.GetScoringProfileIDByMySQLClusterID(testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	asst.Equal(defaultScoringProfileID, profileID, "test GetScoringProfileIDByMySQLClusterID() failed")
+	envProfile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	clusterProfile, err := testScoringProfileRepo.Create(NewScoringProfile(testScoringProfileProfileName+"_cluster",
+		testScoringProfileDescription, envProfile.GetItemConfigs()))
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	// the env profile is used if only the env was assigned
+	sql := `select env_id from t_meta_mysql_cluster_info where id = ?;`
+	result, err := testDASRepo.Execute(sql, testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	envID, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	err = testScoringProfileRepo.Assign(envProfile.Identity(), ScoringProfileTargetTypeEnv, envID)
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	profileID, err = testDASRepo.GetScoringProfileIDByMySQLClusterID(testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	asst.Equal(envProfile.Identity(), profileID, "test GetScoringProfileIDByMySQLClusterID() failed")
+	// the mysql cluster profile takes precedence over the env profile
+	err = testScoringProfileRepo.Assign(clusterProfile.Identity(), ScoringProfileTargetTypeMySQLCluster, testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	profileID, err = testDASRepo.GetScoringProfileIDByMySQLClusterID(testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	asst.Equal(clusterProfile.Identity(), profileID, "test GetScoringProfileIDByMySQLClusterID() failed")
+	// delete
+	err = testDeleteScoringProfileByID(envProfile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+	err = testDeleteScoringProfileByID(clusterProfile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetScoringProfileIDByMySQLClusterID() failed", err))
+}
+
+func TestDASRepo_GetSnapshotByOperationID(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDASRepo.SaveSnapshot(testResultOperationID, testInitSnapshot())
+	asst.Nil(err, common.CombineMessageWithError("test GetSnapshotByOperationID() failed", err))
+	snapshot, err := testDASRepo.GetSnapshotByOperationID(testResultOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test GetSnapshotByOperationID() failed", err))
+	asst.Equal(testResultOperationID, snapshot.GetOperationID(), "test GetSnapshotByOperationID() failed")
+	asst.Equal(testHealthcheckStep, snapshot.GetStep(), "test GetSnapshotByOperationID() failed")
+	// delete
+	err = testDeleteSnapshotByOperationID(testResultOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test GetSnapshotByOperationID() failed", err))
+	_, err = testDASRepo.GetSnapshotByOperationID(testResultOperationID)
+	asst.NotNil(err, "test GetSnapshotByOperationID() failed")
+}
+
 func TestDASRepo_UpdateAccuracyReviewByOperationID(t *testing.T) {
 	asst := assert.New(t)
 
@@ -470,6 +923,343 @@ func TestDASRepo_UpdateAccuracyReviewByOperationID(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test UpdateAccuracyReviewByOperationID() failed", err))
 }
 
+func TestDASRepo_SaveItemFeedback(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testInitItemFeedback()
+	asst.Nil(err, common.CombineMessageWithError("test SaveItemFeedback() failed", err))
+	// save again will replace the existing feedback
+	err = testDASRepo.SaveItemFeedback(NewItemFeedback(id, defaultCPUUsageItemName, ItemAccuracyAccurate, testItemFeedbackComment, testHealthcheckLoginName))
+	asst.Nil(err, common.CombineMessageWithError("test SaveItemFeedback() failed", err))
+	itemFeedbacks, err := testDASRepo.GetItemFeedbacksByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveItemFeedback() failed", err))
+	asst.Equal(1, len(itemFeedbacks), "test SaveItemFeedback() failed")
+	asst.Equal(ItemAccuracyAccurate, itemFeedbacks[constant.ZeroInt].GetAccuracy(), "test SaveItemFeedback() failed")
+	// delete
+	err = testDeleteItemFeedbacksByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveItemFeedback() failed", err))
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveItemFeedback() failed", err))
+}
+
+func TestDASRepo_GetItemFeedbacksByOperationID(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testInitItemFeedback()
+	asst.Nil(err, common.CombineMessageWithError("test GetItemFeedbacksByOperationID() failed", err))
+	itemFeedbacks, err := testDASRepo.GetItemFeedbacksByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetItemFeedbacksByOperationID() failed", err))
+	asst.Equal(1, len(itemFeedbacks), "test GetItemFeedbacksByOperationID() failed")
+	asst.Equal(defaultCPUUsageItemName, itemFeedbacks[constant.ZeroInt].GetItemName(), "test GetItemFeedbacksByOperationID() failed")
+	asst.Equal(testHealthcheckLoginName, itemFeedbacks[constant.ZeroInt].GetLoginName(), "test GetItemFeedbacksByOperationID() failed")
+	// delete
+	err = testDeleteItemFeedbacksByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetItemFeedbacksByOperationID() failed", err))
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetItemFeedbacksByOperationID() failed", err))
+}
+
+func TestDASRepo_GetFeedbackStatsByTimeRange(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testInitItemFeedback()
+	asst.Nil(err, common.CombineMessageWithError("test GetFeedbackStatsByTimeRange() failed", err))
+	feedbackStats, err := testDASRepo.GetFeedbackStatsByTimeRange(time.Now().Add(-constant.Day), time.Now().Add(constant.Day))
+	asst.Nil(err, common.CombineMessageWithError("test GetFeedbackStatsByTimeRange() failed", err))
+	asst.NotZero(len(feedbackStats), "test GetFeedbackStatsByTimeRange() failed")
+	for _, feedbackStat := range feedbackStats {
+		asst.True(feedbackStat.GetInaccurateCount() <= feedbackStat.GetFeedbackCount(), "test GetFeedbackStatsByTimeRange() failed")
+		if feedbackStat.GetItemName() == defaultCPUUsageItemName {
+			asst.True(feedbackStat.GetInaccurateRate() > constant.ZeroInt, "test GetFeedbackStatsByTimeRange() failed")
+		}
+	}
+	// delete
+	err = testDeleteItemFeedbacksByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetFeedbackStatsByTimeRange() failed", err))
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetFeedbackStatsByTimeRange() failed", err))
+}
+
+func TestDASRepo_SaveProgress(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test SaveProgress() failed", err))
+	err = testDASRepo.SaveProgress(NewProgressWithEvent(newProgressEvent(id, ProgressEventTypeItemStarted, defaultCPUUsageItemName, constant.ZeroInt, 2, time.Second)))
+	asst.Nil(err, common.CombineMessageWithError("test SaveProgress() failed", err))
+	// save again will replace the existing progress
+	err = testDASRepo.SaveProgress(NewProgressWithEvent(newProgressEvent(id, ProgressEventTypeItemFinished, defaultCPUUsageItemName, 1, 2, 2*time.Second)))
+	asst.Nil(err, common.CombineMessageWithError("test SaveProgress() failed", err))
+	progress, err := testDASRepo.GetProgressByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveProgress() failed", err))
+	asst.Equal(1, progress.GetFinishedItemNum(), "test SaveProgress() failed")
+	asst.Equal(50, progress.GetPercent(), "test SaveProgress() failed")
+	asst.Equal(constant.EmptyString, progress.GetCurrentItemName(), "test SaveProgress() failed")
+	// delete
+	err = testDeleteProgressByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveProgress() failed", err))
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveProgress() failed", err))
+}
+
+func TestDASRepo_GetProgressByOperationID(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+	// the progress is empty before the operation starts checking the items
+	progress, err := testDASRepo.GetProgressByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+	asst.Equal(constant.ZeroInt, progress.GetStatus(), "test GetProgressByOperationID() failed")
+	asst.Equal(constant.ZeroInt, progress.GetTotalItemNum(), "test GetProgressByOperationID() failed")
+	asst.False(progress.IsFinished(), "test GetProgressByOperationID() failed")
+	err = testDASRepo.SaveProgress(NewProgressWithEvent(newProgressEvent(id, ProgressEventTypeItemStarted, defaultCPUUsageItemName, constant.ZeroInt, 2, time.Second)))
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+	progress, err = testDASRepo.GetProgressByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+	asst.Equal(2, progress.GetTotalItemNum(), "test GetProgressByOperationID() failed")
+	asst.Equal(defaultCPUUsageItemName, progress.GetCurrentItemName(), "test GetProgressByOperationID() failed")
+	// delete
+	err = testDeleteProgressByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+}
+
+func TestDASRepo_SaveCallbackURLs(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test SaveCallbackURLs() failed", err))
+	err = testDASRepo.SaveCallbackURLs(id, testCallbackURLs)
+	asst.Nil(err, common.CombineMessageWithError("test SaveCallbackURLs() failed", err))
+	callbackURLs, err := testDASRepo.GetCallbackURLsByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveCallbackURLs() failed", err))
+	asst.Equal(testCallbackURLs, callbackURLs, "test SaveCallbackURLs() failed")
+	// delete
+	err = testDeleteCallbackURLsByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveCallbackURLs() failed", err))
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test SaveCallbackURLs() failed", err))
+}
+
+func TestDASRepo_GetCallbackURLsByOperationID(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitOperation(
+		testOperationInfo.GetUser().Identity(),
+		constant.ZeroInt,
+		constant.ZeroInt,
+		testHealthcheckMySQLServerID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test GetCallbackURLsByOperationID() failed", err))
+	// the operation without callback urls returns an empty slice
+	callbackURLs, err := testDASRepo.GetCallbackURLsByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetCallbackURLsByOperationID() failed", err))
+	asst.Equal(constant.ZeroInt, len(callbackURLs), "test GetCallbackURLsByOperationID() failed")
+	err = testDASRepo.SaveCallbackURLs(id, testCallbackURLs)
+	asst.Nil(err, common.CombineMessageWithError("test GetCallbackURLsByOperationID() failed", err))
+	callbackURLs, err = testDASRepo.GetCallbackURLsByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetCallbackURLsByOperationID() failed", err))
+	asst.Equal(len(testCallbackURLs), len(callbackURLs), "test GetCallbackURLsByOperationID() failed")
+	// delete
+	err = testDeleteCallbackURLsByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetCallbackURLsByOperationID() failed", err))
+	err = testDeleteOperationInfoByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetCallbackURLsByOperationID() failed", err))
+}
+
+func TestDASRepo_InitClusterOperation(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitClusterOperation(
+		testOperationInfo.GetUser().Identity(),
+		testHealthcheckMySQLClusterID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test InitClusterOperation() failed", err))
+	// delete
+	err = testDeleteClusterOperationByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test InitClusterOperation() failed", err))
+}
+
+func TestDASRepo_UpdateClusterOperationStatus(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitClusterOperation(
+		testOperationInfo.GetUser().Identity(),
+		testHealthcheckMySQLClusterID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateClusterOperationStatus() failed", err))
+	err = testDASRepo.UpdateClusterOperationStatus(id, testHealthcheckResultUpdateStatus, constant.EmptyString)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateClusterOperationStatus() failed", err))
+	sql := `select status from t_hc_cluster_operation_history where id = ?;`
+	r, err := testDASRepo.Execute(sql, id)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateClusterOperationStatus() failed", err))
+	status, err := r.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateClusterOperationStatus() failed", err))
+	asst.Equal(testHealthcheckResultUpdateStatus, status, "test UpdateClusterOperationStatus() failed")
+	// delete
+	err = testDeleteClusterOperationByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateClusterOperationStatus() failed", err))
+}
+
+func TestDASRepo_SaveClusterResult(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDASRepo.SaveClusterResult(testClusterResult)
+	asst.Nil(err, common.CombineMessageWithError("test SaveClusterResult() failed", err))
+	r, err := testDASRepo.GetClusterResultByClusterOperationID(testClusterResult.GetClusterOperationID())
+	asst.Nil(err, common.CombineMessageWithError("test SaveClusterResult() failed", err))
+	asst.Equal(testClusterResult.GetWeightedAverageScore(), r.GetWeightedAverageScore(), "test SaveClusterResult() failed")
+	// delete
+	err = testDeleteClusterResultByID(r.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test SaveClusterResult() failed", err))
+}
+
+func TestDASRepo_GetClusterResultByClusterOperationID(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDASRepo.SaveClusterResult(testClusterResult)
+	asst.Nil(err, common.CombineMessageWithError("test GetClusterResultByClusterOperationID() failed", err))
+	r, err := testDASRepo.GetClusterResultByClusterOperationID(testClusterResult.GetClusterOperationID())
+	asst.Nil(err, common.CombineMessageWithError("test GetClusterResultByClusterOperationID() failed", err))
+	asst.Equal(testHealthcheckMySQLClusterID, r.GetMySQLClusterID(), "test GetClusterResultByClusterOperationID() failed")
+	// delete
+	err = testDeleteClusterResultByID(r.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetClusterResultByClusterOperationID() failed", err))
+}
+
+func TestDASRepo_GetMySQLClusterIDsByMiddlewareClusterID(t *testing.T) {
+	asst := assert.New(t)
+
+	mysqlClusterIDList, err := testDASRepo.GetMySQLClusterIDsByMiddlewareClusterID(testMiddlewareClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test GetMySQLClusterIDsByMiddlewareClusterID() failed", err))
+	asst.GreaterOrEqual(len(mysqlClusterIDList), constant.ZeroInt, "test GetMySQLClusterIDsByMiddlewareClusterID() failed")
+}
+
+func TestDASRepo_IsMiddlewareRunning(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitMiddlewareOperation(
+		testOperationInfo.GetUser().Identity(),
+		testMiddlewareClusterID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test IsMiddlewareRunning() failed", err))
+	isRunning, err := testDASRepo.IsMiddlewareRunning(testMiddlewareClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test IsMiddlewareRunning() failed", err))
+	asst.False(isRunning, "test IsMiddlewareRunning() failed")
+	err = testDASRepo.UpdateMiddlewareOperationStatus(id, defaultRunningStatus, constant.EmptyString)
+	asst.Nil(err, common.CombineMessageWithError("test IsMiddlewareRunning() failed", err))
+	isRunning, err = testDASRepo.IsMiddlewareRunning(testMiddlewareClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test IsMiddlewareRunning() failed", err))
+	asst.True(isRunning, "test IsMiddlewareRunning() failed")
+	// delete
+	err = testDeleteMiddlewareOperationByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test IsMiddlewareRunning() failed", err))
+}
+
+func TestDASRepo_InitMiddlewareOperation(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitMiddlewareOperation(
+		testOperationInfo.GetUser().Identity(),
+		testMiddlewareClusterID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test InitMiddlewareOperation() failed", err))
+	// delete
+	err = testDeleteMiddlewareOperationByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test InitMiddlewareOperation() failed", err))
+}
+
+func TestDASRepo_UpdateMiddlewareOperationStatus(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testDASRepo.InitMiddlewareOperation(
+		testOperationInfo.GetUser().Identity(),
+		testMiddlewareClusterID,
+		time.Now().Add(-constant.Week),
+		time.Now(),
+		testHealthcheckStep,
+	)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateMiddlewareOperationStatus() failed", err))
+	err = testDASRepo.UpdateMiddlewareOperationStatus(id, testHealthcheckResultUpdateStatus, constant.EmptyString)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateMiddlewareOperationStatus() failed", err))
+	sql := `select status from t_hc_middleware_operation_history where id = ?;`
+	r, err := testDASRepo.Execute(sql, id)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateMiddlewareOperationStatus() failed", err))
+	status, err := r.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateMiddlewareOperationStatus() failed", err))
+	asst.Equal(testHealthcheckResultUpdateStatus, status, "test UpdateMiddlewareOperationStatus() failed")
+	// delete
+	err = testDeleteMiddlewareOperationByID(id)
+	asst.Nil(err, common.CombineMessageWithError("test UpdateMiddlewareOperationStatus() failed", err))
+}
+
+func TestDASRepo_SaveMiddlewareResult(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDASRepo.SaveMiddlewareResult(testMiddlewareResult)
+	asst.Nil(err, common.CombineMessageWithError("test SaveMiddlewareResult() failed", err))
+	r, err := testDASRepo.GetMiddlewareResultByMiddlewareOperationID(testMiddlewareResult.GetMiddlewareOperationID())
+	asst.Nil(err, common.CombineMessageWithError("test SaveMiddlewareResult() failed", err))
+	asst.Equal(testMiddlewareResult.GetScore(), r.GetScore(), "test SaveMiddlewareResult() failed")
+	// delete
+	err = testDeleteMiddlewareResultByID(r.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test SaveMiddlewareResult() failed", err))
+}
+
+func TestDASRepo_GetMiddlewareResultByMiddlewareOperationID(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testDASRepo.SaveMiddlewareResult(testMiddlewareResult)
+	asst.Nil(err, common.CombineMessageWithError("test GetMiddlewareResultByMiddlewareOperationID() failed", err))
+	r, err := testDASRepo.GetMiddlewareResultByMiddlewareOperationID(testMiddlewareResult.GetMiddlewareOperationID())
+	asst.Nil(err, common.CombineMessageWithError("test GetMiddlewareResultByMiddlewareOperationID() failed", err))
+	asst.Equal(testMiddlewareClusterID, r.GetMiddlewareClusterID(), "test GetMiddlewareResultByMiddlewareOperationID() failed")
+	// delete
+	err = testDeleteMiddlewareResultByID(r.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetMiddlewareResultByMiddlewareOperationID() failed", err))
+}
+
 func TestApplicationMySQLRepo_GetVariables(t *testing.T) {
 	asst := assert.New(t)
 
@@ -503,6 +1293,139 @@ func TestApplicationMySQLRepo_GetLargeTables(t *testing.T) {
 	asst.Equal(constant.ZeroInt, len(tables), "test TestApplicationMySQLRepo_GetLargeTables() failed")
 }
 
+func TestApplicationMySQLRepo_GetReplicationStatus(t *testing.T) {
+	asst := assert.New(t)
+
+	replicationStatuses, err := testApplicationMySQLRepo.GetReplicationStatus()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetReplicationStatus() failed", err))
+	for _, rs := range replicationStatuses {
+		asst.NotEmpty(rs.GetMasterHost(), "test TestApplicationMySQLRepo_GetReplicationStatus() failed")
+	}
+	// the replication status is fetched only once
+	cached, err := testApplicationMySQLRepo.GetReplicationStatus()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetReplicationStatus() failed", err))
+	asst.Equal(replicationStatuses, cached, "test TestApplicationMySQLRepo_GetReplicationStatus() failed")
+}
+
+func TestApplicationMySQLRepo_GetGTIDExecuted(t *testing.T) {
+	asst := assert.New(t)
+
+	gtidSet, err := testApplicationMySQLRepo.GetGTIDExecuted()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetGTIDExecuted() failed", err))
+	_, err = countGTIDSet(gtidSet)
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetGTIDExecuted() failed", err))
+}
+
+func TestApplicationMySQLRepo_GetGTIDSubtract(t *testing.T) {
+	asst := assert.New(t)
+
+	gtidSet, err := testApplicationMySQLRepo.GetGTIDSubtract(testGTIDSetMaster, testGTIDSetReplica)
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetGTIDSubtract() failed", err))
+	count, err := countGTIDSet(gtidSet)
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetGTIDSubtract() failed", err))
+	asst.Equal(testGTIDSetMissingCount, count, "test TestApplicationMySQLRepo_GetGTIDSubtract() failed")
+}
+
+func TestApplicationMySQLRepo_GetGroupMembers(t *testing.T) {
+	asst := assert.New(t)
+
+	groupMembers, err := testApplicationMySQLRepo.GetGroupMembers()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetGroupMembers() failed", err))
+	for _, member := range groupMembers {
+		asst.NotEmpty(member.GetState(), "test TestApplicationMySQLRepo_GetGroupMembers() failed")
+	}
+}
+
+func TestApplicationMySQLRepo_GetLongTransactions(t *testing.T) {
+	asst := assert.New(t)
+
+	transactions, err := testApplicationMySQLRepo.GetLongTransactions()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetLongTransactions() failed", err))
+	for _, transaction := range transactions {
+		asst.NotEmpty(transaction.GetTrxID(), "test TestApplicationMySQLRepo_GetLongTransactions() failed")
+	}
+}
+
+func TestApplicationMySQLRepo_GetHistoryListLength(t *testing.T) {
+	asst := assert.New(t)
+
+	historyListLength, err := testApplicationMySQLRepo.GetHistoryListLength()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetHistoryListLength() failed", err))
+	asst.GreaterOrEqual(historyListLength, constant.ZeroInt, "test TestApplicationMySQLRepo_GetHistoryListLength() failed")
+}
+
+func TestApplicationMySQLRepo_GetLockWaits(t *testing.T) {
+	asst := assert.New(t)
+
+	lockWaits, err := testApplicationMySQLRepo.GetLockWaits()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetLockWaits() failed", err))
+	for _, lockWait := range lockWaits {
+		asst.NotEmpty(lockWait.GetBlockingTrxID(), "test TestApplicationMySQLRepo_GetLockWaits() failed")
+	}
+}
+
+func TestApplicationMySQLRepo_GetLatestDeadlock(t *testing.T) {
+	asst := assert.New(t)
+
+	deadlock, err := testApplicationMySQLRepo.GetLatestDeadlock()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetLatestDeadlock() failed", err))
+	if !deadlock.GetDetectedTime().IsZero() {
+		asst.NotEmpty(deadlock.GetTransactions(), "test TestApplicationMySQLRepo_GetLatestDeadlock() failed")
+	}
+}
+
+func TestApplicationMySQLRepo_GetTableSchemas(t *testing.T) {
+	asst := assert.New(t)
+
+	tableSchemas, err := testApplicationMySQLRepo.GetTableSchemas()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetTableSchemas() failed", err))
+	for _, tableSchema := range tableSchemas {
+		asst.NotEmpty(tableSchema.GetTableName(), "test TestApplicationMySQLRepo_GetTableSchemas() failed")
+	}
+}
+
+func TestApplicationMySQLRepo_GetIndexStatistics(t *testing.T) {
+	asst := assert.New(t)
+
+	indexStatistics, err := testApplicationMySQLRepo.GetIndexStatistics()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetIndexStatistics() failed", err))
+	for _, indexStatistic := range indexStatistics {
+		asst.NotEmpty(indexStatistic.GetIndexName(), "test TestApplicationMySQLRepo_GetIndexStatistics() failed")
+	}
+}
+
+func TestApplicationMySQLRepo_GetAutoIncrements(t *testing.T) {
+	asst := assert.New(t)
+
+	autoIncrements, err := testApplicationMySQLRepo.GetAutoIncrements()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetAutoIncrements() failed", err))
+	for _, autoIncrement := range autoIncrements {
+		asst.NotEmpty(autoIncrement.GetColumnName(), "test TestApplicationMySQLRepo_GetAutoIncrements() failed")
+	}
+}
+
+func TestApplicationMySQLRepo_GetUsers(t *testing.T) {
+	asst := assert.New(t)
+
+	users, err := testApplicationMySQLRepo.GetUsers()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetUsers() failed", err))
+	asst.NotZero(len(users), "test TestApplicationMySQLRepo_GetUsers() failed")
+}
+
+func TestApplicationMySQLRepo_GetGrants(t *testing.T) {
+	asst := assert.New(t)
+
+	users, err := testApplicationMySQLRepo.GetUsers()
+	asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetGrants() failed", err))
+	for _, user := range users {
+		grants, err := testApplicationMySQLRepo.GetGrants(user.GetUser(), user.GetHost())
+		asst.Nil(err, common.CombineMessageWithError("test TestApplicationMySQLRepo_GetGrants() failed", err))
+		for _, grant := range grants {
+			asst.NotContains(grant, "IDENTIFIED BY PASSWORD '", "test TestApplicationMySQLRepo_GetGrants() failed")
+		}
+	}
+}
+
 func TestPrometheusRepo_GetFileSystems(t *testing.T) {
 	asst := assert.New(t)
 
@@ -551,6 +1474,14 @@ func TestPrometheusRepo_GetDiskCapacityUsage(t *testing.T) {
 	asst.GreaterOrEqual(len(datas), constant.ZeroInt, "test TestPrometheusRepo_GetDiskCapacityUsage() failed")
 }
 
+func TestPrometheusRepo_GetDiskCapacities(t *testing.T) {
+	asst := assert.New(t)
+
+	diskCapacities, err := testPrometheusRepo.GetDiskCapacities(testMountPoints)
+	asst.Nil(err, common.CombineMessageWithError("test TestPrometheusRepo_GetDiskCapacities() failed", err))
+	asst.GreaterOrEqual(len(diskCapacities), constant.ZeroInt, "test TestPrometheusRepo_GetDiskCapacities() failed")
+}
+
 func TestPrometheusRepo_GetConnectionUsage(t *testing.T) {
 	asst := assert.New(t)
 
@@ -582,31 +1513,42 @@ func TestPrometheusRepo_getServiceName(t *testing.T) {
 		"test TestPrometheusRepo_getServiceName() failed")
 }
 
-func TestPrometheusRepo_getPMMVersion(t *testing.T) {
+func TestPrometheusRepo_getNodeSelector(t *testing.T) {
 	asst := assert.New(t)
 
-	asst.Equal(testOperationInfo.GetMonitorSystem().GetSystemType(), testPrometheusRepo.getPMMVersion(),
-		"test TestPrometheusRepo_getPMMVersion() failed")
+	viper.Set(config.DBMonitorGenericNodeLabelKey, config.DefaultDBMonitorGenericNodeLabel)
+	viper.Set(config.DBMonitorGenericNodeJobKey, config.DefaultDBMonitorGenericNodeJob)
+	expect := fmt.Sprintf(`instance=~"%s(:[0-9]+)?",job=~"node"`, testOperationInfo.GetMySQLServer().GetServerName())
+	asst.Equal(expect, testPrometheusRepo.getNodeSelector(), "test TestPrometheusRepo_getNodeSelector() failed")
 }
 
-func TestPrometheusRepo_execute(t *testing.T) {
+func TestPrometheusRepo_getServiceSelector(t *testing.T) {
 	asst := assert.New(t)
 
-	var prometheusQuery string
+	viper.Set(config.DBMonitorGenericServiceLabelKey, "service_name")
+	viper.Set(config.DBMonitorGenericMySQLJobKey, config.DefaultDBMonitorGenericMySQLJob)
+	expect := fmt.Sprintf(`service_name=~"%s(:[0-9]+)?",job=~"mysql"`, testOperationInfo.GetMySQLServer().GetServiceName())
+	asst.Equal(expect, testPrometheusRepo.getServiceSelector(), "test TestPrometheusRepo_getServiceSelector() failed")
+	viper.Set(config.DBMonitorGenericServiceLabelKey, config.DefaultDBMonitorGenericServiceLabel)
+}
 
-	// prepare query
-	switch testPrometheusRepo.getPMMVersion() {
-	case 1:
-		// pmm 1.x
-		prometheusQuery = PrometheusCPUUsageV1
-	case 2:
-		// pmm 2.x
-		prometheusQuery = PrometheusCPUUsageV2
-	}
+func TestPrometheusRepo_render(t *testing.T) {
+	asst := assert.New(t)
 
-	nodeName := testPrometheusRepo.getNodeName()
-	prometheusQuery = fmt.Sprintf(prometheusQuery, nodeName, nodeName, nodeName, nodeName, nodeName, nodeName)
+	prometheusQuery, err := testPrometheusRepo.render(PromQLDiskCapacity, []string{testCapacityMountPoint})
+	asst.Nil(err, common.CombineMessageWithError("test TestPrometheusRepo_render() failed", err))
+	asst.Contains(prometheusQuery, testCapacityMountPoint, "test TestPrometheusRepo_render() failed")
+	asst.NotContains(prometheusQuery, "{{", "test TestPrometheusRepo_render() failed")
+	// invalid template name
+	_, err = testPrometheusRepo.render("invalid", nil)
+	asst.NotNil(err, "test TestPrometheusRepo_render() failed")
+}
 
+func TestPrometheusRepo_execute(t *testing.T) {
+	asst := assert.New(t)
+
+	prometheusQuery, err := testPrometheusRepo.render(PromQLCPUUsage, nil)
+	asst.Nil(err, common.CombineMessageWithError("test TestPrometheusRepo_execute() failed", err))
 	datas, err := testPrometheusRepo.execute(prometheusQuery)
 	asst.Nil(err, common.CombineMessageWithError("test TestPrometheusRepo_execute() failed", err))
 	asst.GreaterOrEqual(len(datas), constant.ZeroInt, "test TestPrometheusRepo_execute() failed")
@@ -667,3 +1609,50 @@ func TestClickhouseQueryRepo_getPMMVersion(t *testing.T) {
 			"test TestClickhouseQueryRepo_getServiceName() failed")
 	}
 }
+
+func TestPerformanceSchemaQueryRepo_GetSlowQuery(t *testing.T) {
+	asst := assert.New(t)
+
+	if testOperationInfo.GetMonitorSystem().GetSystemType() == 3 {
+		queries, err := testQueryRepo.(*PerformanceSchemaQueryRepo).GetSlowQuery()
+		asst.Nil(err, common.CombineMessageWithError("test TestPerformanceSchemaQueryRepo_GetSlowQuery() failed", err))
+		asst.LessOrEqual(len(queries), SlowQueryNumLimit, "test TestPerformanceSchemaQueryRepo_GetSlowQuery() failed")
+	}
+}
+
+func TestSlowLogQueryRepo_GetSlowQuery(t *testing.T) {
+	asst := assert.New(t)
+
+	slowLogQueryRepo := NewSlowLogQueryRepo(context.Background(), testOperationInfo, testDASRepo)
+	queries, err := slowLogQueryRepo.GetSlowQuery()
+	asst.Nil(err, common.CombineMessageWithError("test TestSlowLogQueryRepo_GetSlowQuery() failed", err))
+	asst.LessOrEqual(len(queries), SlowQueryNumLimit, "test TestSlowLogQueryRepo_GetSlowQuery() failed")
+}
+
+func TestDASRepo_LoadEngineConfigWithVersionByProfileID(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test LoadEngineConfigWithVersionByProfileID() failed", err))
+	engineConfig, version, err := testDASRepo.LoadEngineConfigWithVersionByProfileID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test LoadEngineConfigWithVersionByProfileID() failed", err))
+	asst.Equal(1, version, "test LoadEngineConfigWithVersionByProfileID() failed")
+	asst.Equal(len(profile.GetItemConfigs()), len(engineConfig.GetItemNames()), "test LoadEngineConfigWithVersionByProfileID() failed")
+	asst.Nil(engineConfig.Validate(), "test LoadEngineConfigWithVersionByProfileID() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test LoadEngineConfigWithVersionByProfileID() failed", err))
+}
+
+func TestDASRepo_LoadDBConfigRules(t *testing.T) {
+	asst := assert.New(t)
+
+	rule, err := testCreateDBConfigRule()
+	asst.Nil(err, common.CombineMessageWithError("test LoadDBConfigRules() failed", err))
+	rules, err := testDASRepo.LoadDBConfigRules()
+	asst.Nil(err, common.CombineMessageWithError("test LoadDBConfigRules() failed", err))
+	asst.NotZero(len(rules), "test LoadDBConfigRules() failed")
+	// delete
+	err = testDeleteDBConfigRuleByID(rule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test LoadDBConfigRules() failed", err))
+}
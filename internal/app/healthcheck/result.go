@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -14,6 +15,10 @@ import (
 )
 
 const (
+	// ResultScoreNotChecked is saved into the score columns of t_hc_result of the built-in items which were skipped or failed,
+	// so that these items will not be taken as the worst score
+	ResultScoreNotChecked = -1
+
 	resultOperationIDStruct                      = "OperationID"
 	resultDBConfigDataStruct                     = "DBConfigData"
 	resultDBConfigAdviceStruct                   = "DBConfigAdvice"
@@ -40,9 +45,23 @@ const (
 	resultSlowQueryDataStruct                    = "SlowQueryData"
 	resultSlowQueryAdviceStruct                  = "SlowQueryAdvice"
 	resultAccuracyReviewStruct                   = "AccuracyReview"
+	resultItemResultsStruct                      = "ItemResults"
 	resultDelFlagStruct                          = "DelFlag"
 	resultCreateTimeStruct                       = "CreateTime"
 	resultLastUpdateTimeStruct                   = "LastUpdateTime"
+
+	resultDBConfigScoreStruct                     = "DBConfigScore"
+	resultAvgBackupFailedRatioScoreStruct         = "AvgBackupFailedRatioScore"
+	resultStatisticFailedRatioScoreStruct         = "StatisticFailedRatioScore"
+	resultCPUUsageScoreStruct                     = "CPUUsageScore"
+	resultIOUtilScoreStruct                       = "IOUtilScore"
+	resultDiskCapacityUsageScoreStruct            = "DiskCapacityUsageScore"
+	resultConnectionUsageScoreStruct              = "ConnectionUsageScore"
+	resultAverageActiveSessionPercentsScoreStruct = "AverageActiveSessionPercentsScore"
+	resultCacheMissRatioScoreStruct               = "CacheMissRatioScore"
+	resultTableRowsScoreStruct                    = "TableRowsScore"
+	resultTableSizeScoreStruct                    = "TableSizeScore"
+	resultSlowQueryScoreStruct                    = "SlowQueryScore"
 )
 
 var (
@@ -70,6 +89,7 @@ var (
 		resultTableSizeHighStruct,
 		resultSlowQueryDataStruct,
 		resultAccuracyReviewStruct,
+		resultItemResultsStruct,
 		resultDelFlagStruct,
 		resultLastUpdateTimeStruct,
 	}
@@ -99,8 +119,32 @@ var (
 		resultSlowQueryDataStruct,
 		resultSlowQueryAdviceStruct,
 	}
+	// resultItemFields maps the built-in items to the fields of Result,
+	// the results of these items are also saved into the columns of t_hc_result to keep the result api compatible
+	resultItemFields = map[string]resultItemField{
+		defaultDBConfigItemName:                     {resultDBConfigScoreStruct, resultDBConfigDataStruct, constant.EmptyString, resultDBConfigAdviceStruct},
+		defaultAvgBackupFailedRatioItemName:         {resultAvgBackupFailedRatioScoreStruct, resultAvgBackupFailedRatioDataStruct, resultAvgBackupFailedRatioHighStruct, constant.EmptyString},
+		defaultStatisticFailedRatioItemName:         {resultStatisticFailedRatioScoreStruct, resultStatisticFailedRatioDataStruct, resultStatisticFailedRatioHighStruct, constant.EmptyString},
+		defaultCPUUsageItemName:                     {resultCPUUsageScoreStruct, resultCPUUsageDataStruct, resultCPUUsageHighStruct, constant.EmptyString},
+		defaultIOUtilItemName:                       {resultIOUtilScoreStruct, resultIOUtilDataStruct, resultIOUtilHighStruct, constant.EmptyString},
+		defaultDiskCapacityUsageItemName:            {resultDiskCapacityUsageScoreStruct, resultDiskCapacityUsageDataStruct, resultDiskCapacityUsageHighStruct, constant.EmptyString},
+		defaultConnectionUsageItemName:              {resultConnectionUsageScoreStruct, resultConnectionUsageDataStruct, resultConnectionUsageHighStruct, constant.EmptyString},
+		defaultAverageActiveSessionPercentsItemName: {resultAverageActiveSessionPercentsScoreStruct, resultAverageActiveSessionPercentsDataStruct, resultAverageActiveSessionPercentsHighStruct, constant.EmptyString},
+		defaultCacheMissRatioItemName:               {resultCacheMissRatioScoreStruct, resultCacheMissRatioDataStruct, resultCacheMissRatioHighStruct, constant.EmptyString},
+		defaultTableRowsItemName:                    {resultTableRowsScoreStruct, resultTableRowsDataStruct, resultTableRowsHighStruct, constant.EmptyString},
+		defaultTableSizeItemName:                    {resultTableSizeScoreStruct, resultTableSizeDataStruct, resultTableSizeHighStruct, constant.EmptyString},
+		defaultSlowQueryRowsExaminedItemName:        {resultSlowQueryScoreStruct, resultSlowQueryDataStruct, constant.EmptyString, resultSlowQueryAdviceStruct},
+	}
 )
 
+// resultItemField contains the field names of Result of a built-in item, empty name means the item has no such field
+type resultItemField struct {
+	score  string
+	data   string
+	high   string
+	advice string
+}
+
 // Result include all data needed in healthcheck
 type Result struct {
 	healthcheck.DASRepo
@@ -149,6 +193,9 @@ type Result struct {
 	DelFlag                           int       `middleware:"del_flag" json:"del_flag"`
 	CreateTime                        time.Time `middleware:"create_time" json:"create_time"`
 	LastUpdateTime                    time.Time `middleware:"last_update_time" json:"last_update_time"`
+
+	// ItemResults are the results of all the check items, they are saved in t_hc_item_result
+	ItemResults []healthcheck.ItemResult `json:"item_results"`
 }
 
 // NewResult returns a new *Result
@@ -493,6 +540,11 @@ func (r *Result) GetAccuracyReview() int {
 	return r.AccuracyReview
 }
 
+// GetItemResults returns the results of all the check items
+func (r *Result) GetItemResults() []healthcheck.ItemResult {
+	return r.ItemResults
+}
+
 // GetDelFlag returns the delete flag
 func (r *Result) GetDelFlag() int {
 	return r.DelFlag
@@ -527,6 +579,45 @@ func (r *Result) Set(fields map[string]interface{}) error {
 	return nil
 }
 
+// addItemResult adds the item result to the result, if the item is a built-in item,
+// it also sets the relevant fields of the result, the score of the skipped or failed item is set to ResultScoreNotChecked
+func (r *Result) addItemResult(itemResult healthcheck.ItemResult) error {
+	r.ItemResults = append(r.ItemResults, itemResult)
+
+	field, ok := resultItemFields[itemResult.GetItemName()]
+	if !ok {
+		return nil
+	}
+	if !itemResult.IsOK() {
+		return r.Set(map[string]interface{}{field.score: ResultScoreNotChecked})
+	}
+
+	fields := map[string]interface{}{
+		field.score: itemResult.GetScore(),
+		field.data:  itemResult.GetData(),
+	}
+	if field.high != constant.EmptyString {
+		fields[field.high] = itemResult.GetHigh()
+	}
+	if field.advice != constant.EmptyString {
+		fields[field.advice] = itemResult.GetAdvice()
+	}
+
+	return r.Set(fields)
+}
+
+// getErrorItemNames returns the names of the items which failed
+func (r *Result) getErrorItemNames() []string {
+	var itemNames []string
+	for _, itemResult := range r.ItemResults {
+		if itemResult.GetStatus() == ItemStatusError {
+			itemNames = append(itemNames, itemResult.GetItemName())
+		}
+	}
+
+	return itemNames
+}
+
 // MarshalJSON marshals health check to json string
 func (r *Result) MarshalJSON() ([]byte, error) {
 	// return common.MarshalStructWithTag(r, constant.DefaultMarshalTag)
@@ -572,6 +663,14 @@ func (r *Result) getString(ignoreList []string) string {
 				if fieldVal.String() != constant.EmptyString && common.StringInSlice(defaultSliceList, fieldType.Name) {
 					fieldStrTemplate = `"%s":%s,`
 				}
+			case reflect.Slice:
+				// the item results are marshaled as a json array
+				jsonBytes, err := json.Marshal(fieldVal.Interface())
+				if err != nil || fieldVal.Len() == constant.ZeroInt {
+					jsonBytes = []byte(defaultEmptyJSONArray)
+				}
+				s += fmt.Sprintf(`"%s":%s,`, fieldTag, jsonBytes)
+				continue
 			}
 
 			s += fmt.Sprintf(fieldStrTemplate, fieldTag, fieldVal)
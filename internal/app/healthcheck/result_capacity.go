@@ -0,0 +1,182 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	CapacityTargetTypeMySQLServer   = 1
+	CapacityTargetTypeMySQLCluster  = 2
+	CapacityTargetTypeResourceGroup = 3
+
+	tableGrowthKeySeparator = "."
+)
+
+var _ healthcheck.Capacity = (*Capacity)(nil)
+
+// TableGrowth is the growth of a table over the results, the sizes are in GB
+type TableGrowth struct {
+	DBName       string  `json:"db_name"`
+	TableName    string  `json:"table_name"`
+	TableSize    float64 `json:"table_size"`
+	GrowthPerDay float64 `json:"growth_per_day"`
+}
+
+// Capacity is the capacity forecast of a mysql server, it is calculated with the saved results,
+// the disk forecasts are the data of the latest disk capacity forecast item,
+// and the table growths are calculated with the table size data of all the results
+type Capacity struct {
+	MySQLServerID int             `json:"mysql_server_id"`
+	OperationID   int             `json:"operation_id"`
+	HostIP        string          `json:"host_ip"`
+	PortNum       int             `json:"port_num"`
+	DiskForecasts []*DiskForecast `json:"disk_forecasts"`
+	TableGrowths  []*TableGrowth  `json:"table_growths"`
+	DaysToFull    float64         `json:"days_to_full"`
+	CheckTime     time.Time       `json:"check_time"`
+}
+
+// NewCapacity returns a new *Capacity with given results, the results must be ordered by the create time
+func NewCapacity(mysqlServerID int, results []healthcheck.Result) (*Capacity, error) {
+	c := &Capacity{
+		MySQLServerID: mysqlServerID,
+		DaysToFull:    diskForecastNotGrowing,
+	}
+
+	tableGrowths, err := getTableGrowths(results)
+	if err != nil {
+		return nil, err
+	}
+	c.TableGrowths = tableGrowths
+
+	for i := len(results) - 1; i >= constant.ZeroInt; i-- {
+		for _, itemResult := range results[i].GetItemResults() {
+			if itemResult.GetItemName() != defaultDiskCapacityForecastItemName || !itemResult.IsOK() {
+				continue
+			}
+			// the data of the archived results is cleared
+			if itemResult.GetData() == constant.EmptyString {
+				continue
+			}
+			err = json.Unmarshal([]byte(itemResult.GetData()), &c.DiskForecasts)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			c.OperationID = results[i].GetOperationID()
+			c.HostIP = results[i].GetHostIP()
+			c.PortNum = results[i].GetPortNum()
+			c.CheckTime = results[i].GetCreateTime()
+			c.DaysToFull = getDaysToFull(c.DiskForecasts, c.TableGrowths)
+
+			return c, nil
+		}
+	}
+
+	return c, nil
+}
+
+// GetMySQLServerID returns the mysql server id
+func (c *Capacity) GetMySQLServerID() int {
+	return c.MySQLServerID
+}
+
+// GetOperationID returns the id of the latest operation which forecast the disk capacity
+func (c *Capacity) GetOperationID() int {
+	return c.OperationID
+}
+
+// GetDaysToFull returns the least days before any mount point of the mysql server is full
+func (c *Capacity) GetDaysToFull() float64 {
+	return c.DaysToFull
+}
+
+// GetCheckTime returns the time when the latest result was saved
+func (c *Capacity) GetCheckTime() time.Time {
+	return c.CheckTime
+}
+
+// getDaysToFull returns the least days before any mount point is full,
+// as the mount points of the tables are unknown, the growth of each mount point is the larger one
+// of its own growth and the total growth of the tables, so the estimation is conservative
+func getDaysToFull(diskForecasts []*DiskForecast, tableGrowths []*TableGrowth) float64 {
+	var tableGrowthSum float64
+	for _, tableGrowth := range tableGrowths {
+		tableGrowthSum += tableGrowth.GrowthPerDay
+	}
+
+	daysToFull := float64(diskForecastNotGrowing)
+	for _, diskForecast := range diskForecasts {
+		growth := diskForecast.GrowthPerDay
+		if tableGrowthSum > growth {
+			growth = tableGrowthSum
+		}
+		if growth <= 0 {
+			continue
+		}
+		days := (diskForecast.Size - diskForecast.Used) / growth
+		if days < 0 {
+			days = 0
+		}
+		if daysToFull == diskForecastNotGrowing || days < daysToFull {
+			daysToFull = days
+		}
+	}
+
+	return daysToFull
+}
+
+// getTableGrowths calculates the growth of the tables with the first and the last appearance in the table size data of the results,
+// the tables which appeared only once are not growing
+func getTableGrowths(results []healthcheck.Result) ([]*TableGrowth, error) {
+	type tableSize struct {
+		table *Table
+		time  time.Time
+	}
+
+	var keys []string
+	firsts := make(map[string]tableSize)
+	lasts := make(map[string]tableSize)
+	for _, result := range results {
+		if result.GetTableSizeData() == constant.EmptyString || result.GetTableSizeData() == constant.DefaultRandomString {
+			continue
+		}
+		var tables []*Table
+		err := json.Unmarshal([]byte(result.GetTableSizeData()), &tables)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, table := range tables {
+			key := table.GetSchema() + tableGrowthKeySeparator + table.GetName()
+			ts := tableSize{table: table, time: result.GetCreateTime()}
+			_, ok := firsts[key]
+			if !ok {
+				keys = append(keys, key)
+				firsts[key] = ts
+			}
+			lasts[key] = ts
+		}
+	}
+
+	tableGrowths := make([]*TableGrowth, len(keys))
+	for i, key := range keys {
+		first := firsts[key]
+		last := lasts[key]
+		tableGrowth := &TableGrowth{
+			DBName:    last.table.GetSchema(),
+			TableName: last.table.GetName(),
+			TableSize: last.table.GetSize(),
+		}
+		days := last.time.Sub(first.time).Hours() / 24
+		if days > 0 {
+			tableGrowth.GrowthPerDay = (last.table.GetSize() - first.table.GetSize()) / days
+		}
+		tableGrowths[i] = tableGrowth
+	}
+
+	return tableGrowths, nil
+}
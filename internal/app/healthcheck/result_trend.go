@@ -0,0 +1,386 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/query"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	itemNotScoredInBaseMessage   = "item was not scored in the base operation"
+	itemNotScoredInTargetMessage = "item was not scored in the target operation"
+)
+
+var (
+	_ healthcheck.TrendPoint = (*TrendPoint)(nil)
+	_ healthcheck.ResultDiff = (*ResultDiff)(nil)
+)
+
+// itemScore is the score of a check item
+type itemScore struct {
+	name  string
+	score int
+}
+
+// getItemScores returns the scores of the items which were checked successfully,
+// the results which were saved before the item results were introduced only have the built-in item scores
+func getItemScores(result healthcheck.Result) []itemScore {
+	if len(result.GetItemResults()) == constant.ZeroInt {
+		return getLegacyItemScores(result)
+	}
+
+	var itemScores []itemScore
+	for _, itemResult := range result.GetItemResults() {
+		// the skipped and failed items are not scored
+		if itemResult.IsOK() {
+			itemScores = append(itemScores, itemScore{itemResult.GetItemName(), itemResult.GetScore()})
+		}
+	}
+
+	return itemScores
+}
+
+// getLegacyItemScores returns the built-in item scores which were saved in the columns of t_hc_result,
+// the items of which the score is ResultScoreNotChecked were skipped or failed, they are not scored
+func getLegacyItemScores(result healthcheck.Result) []itemScore {
+	legacyItemScores := []itemScore{
+		{defaultDBConfigItemName, result.GetDBConfigScore()},
+		{defaultAvgBackupFailedRatioItemName, result.GetAvgBackupFailedRatioScore()},
+		{defaultStatisticFailedRatioItemName, result.GetStatisticFailedRatioScore()},
+		{defaultCPUUsageItemName, result.GetCPUUsageScore()},
+		{defaultIOUtilItemName, result.GetIOUtilScore()},
+		{defaultDiskCapacityUsageItemName, result.GetDiskCapacityUsageScore()},
+		{defaultConnectionUsageItemName, result.GetConnectionUsageScore()},
+		{defaultAverageActiveSessionPercentsItemName, result.GetAverageActiveSessionPercentsScore()},
+		{defaultCacheMissRatioItemName, result.GetCacheMissRatioScore()},
+		{defaultTableRowsItemName, result.GetTableRowsScore()},
+		{defaultTableSizeItemName, result.GetTableSizeScore()},
+		{defaultSlowQueryRowsExaminedItemName, result.GetSlowQueryScore()},
+	}
+
+	var itemScores []itemScore
+	for _, is := range legacyItemScores {
+		if is.score != ResultScoreNotChecked {
+			itemScores = append(itemScores, is)
+		}
+	}
+
+	return itemScores
+}
+
+// TrendPoint is the scores of a healthcheck operation, it is a point of the score trend
+type TrendPoint struct {
+	OperationID          int            `json:"operation_id"`
+	HostIP               string         `json:"host_ip"`
+	PortNum              int            `json:"port_num"`
+	WeightedAverageScore int            `json:"weighted_average_score"`
+	ItemScores           map[string]int `json:"item_scores"`
+	CheckTime            time.Time      `json:"check_time"`
+}
+
+// NewTrendPoint returns a new *TrendPoint with given result
+func NewTrendPoint(result healthcheck.Result) *TrendPoint {
+	itemScores := make(map[string]int)
+	for _, is := range getItemScores(result) {
+		itemScores[is.name] = is.score
+	}
+
+	return &TrendPoint{
+		OperationID:          result.GetOperationID(),
+		HostIP:               result.GetHostIP(),
+		PortNum:              result.GetPortNum(),
+		WeightedAverageScore: result.GetWeightedAverageScore(),
+		ItemScores:           itemScores,
+		CheckTime:            result.GetCreateTime(),
+	}
+}
+
+// GetOperationID returns the operation id
+func (tp *TrendPoint) GetOperationID() int {
+	return tp.OperationID
+}
+
+// GetHostIP returns the host ip
+func (tp *TrendPoint) GetHostIP() string {
+	return tp.HostIP
+}
+
+// GetPortNum returns the port number
+func (tp *TrendPoint) GetPortNum() int {
+	return tp.PortNum
+}
+
+// GetWeightedAverageScore returns the weighted average score
+func (tp *TrendPoint) GetWeightedAverageScore() int {
+	return tp.WeightedAverageScore
+}
+
+// GetItemScores returns the scores of the items which were checked successfully
+func (tp *TrendPoint) GetItemScores() map[string]int {
+	return tp.ItemScores
+}
+
+// GetCheckTime returns the time when the result was saved
+func (tp *TrendPoint) GetCheckTime() time.Time {
+	return tp.CheckTime
+}
+
+// ItemScoreDiff is the score difference of a check item between two results
+type ItemScoreDiff struct {
+	ItemName    string `json:"item_name"`
+	BaseScore   int    `json:"base_score"`
+	TargetScore int    `json:"target_score"`
+	Delta       int    `json:"delta"`
+	Message     string `json:"message"`
+}
+
+// ConfigChange is a variable of which the value changed between two results
+type ConfigChange struct {
+	VariableName string `json:"variable_name"`
+	BaseValue    string `json:"base_value"`
+	TargetValue  string `json:"target_value"`
+}
+
+// ResultDiff is the difference between the target result and the base result
+type ResultDiff struct {
+	BaseOperationID           int              `json:"base_operation_id"`
+	TargetOperationID         int              `json:"target_operation_id"`
+	BaseScore                 int              `json:"base_score"`
+	TargetScore               int              `json:"target_score"`
+	WeightedAverageScoreDelta int              `json:"weighted_average_score_delta"`
+	ItemScoreDiffs            []*ItemScoreDiff `json:"item_score_diffs"`
+	NewLargeTables            []*Table         `json:"new_large_tables"`
+	NewSlowQueries            []*query.Query   `json:"new_slow_queries"`
+	ConfigChanges             []*ConfigChange  `json:"config_changes"`
+}
+
+// NewResultDiff compares the target result with the base result and returns the difference
+func NewResultDiff(base, target healthcheck.Result) (*ResultDiff, error) {
+	rd := &ResultDiff{
+		BaseOperationID:           base.GetOperationID(),
+		TargetOperationID:         target.GetOperationID(),
+		BaseScore:                 base.GetWeightedAverageScore(),
+		TargetScore:               target.GetWeightedAverageScore(),
+		WeightedAverageScoreDelta: target.GetWeightedAverageScore() - base.GetWeightedAverageScore(),
+		ItemScoreDiffs:            getItemScoreDiffs(getItemScores(base), getItemScores(target)),
+	}
+
+	var err error
+	rd.NewLargeTables, err = getNewLargeTables(base, target)
+	if err != nil {
+		return nil, err
+	}
+	rd.NewSlowQueries, err = getNewSlowQueries(base, target)
+	if err != nil {
+		return nil, err
+	}
+	rd.ConfigChanges, err = getConfigChanges(base, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return rd, nil
+}
+
+// GetBaseOperationID returns the operation id of the base result
+func (rd *ResultDiff) GetBaseOperationID() int {
+	return rd.BaseOperationID
+}
+
+// GetTargetOperationID returns the operation id of the target result
+func (rd *ResultDiff) GetTargetOperationID() int {
+	return rd.TargetOperationID
+}
+
+// GetWeightedAverageScoreDelta returns the weighted average score of the target result minus the base result
+func (rd *ResultDiff) GetWeightedAverageScoreDelta() int {
+	return rd.WeightedAverageScoreDelta
+}
+
+// MarshalJSON marshals ResultDiff to json string
+func (rd *ResultDiff) MarshalJSON() ([]byte, error) {
+	type resultDiff ResultDiff
+
+	jsonBytes, err := json.Marshal((*resultDiff)(rd))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return jsonBytes, nil
+}
+
+// getItemScoreDiffs returns the score differences of the items, the items are ordered as the target result,
+// the items which were only scored in the base result are appended at the end
+func getItemScoreDiffs(baseScores, targetScores []itemScore) []*ItemScoreDiff {
+	baseScoreMap := make(map[string]int, len(baseScores))
+	for _, is := range baseScores {
+		baseScoreMap[is.name] = is.score
+	}
+
+	var itemScoreDiffs []*ItemScoreDiff
+	targetItems := make(map[string]bool, len(targetScores))
+	for _, is := range targetScores {
+		targetItems[is.name] = true
+		baseScore, ok := baseScoreMap[is.name]
+		if !ok {
+			itemScoreDiffs = append(itemScoreDiffs, &ItemScoreDiff{ItemName: is.name, TargetScore: is.score, Message: itemNotScoredInBaseMessage})
+			continue
+		}
+		itemScoreDiffs = append(itemScoreDiffs, &ItemScoreDiff{ItemName: is.name, BaseScore: baseScore, TargetScore: is.score, Delta: is.score - baseScore})
+	}
+	for _, is := range baseScores {
+		if !targetItems[is.name] {
+			itemScoreDiffs = append(itemScoreDiffs, &ItemScoreDiff{ItemName: is.name, BaseScore: is.score, Message: itemNotScoredInTargetMessage})
+		}
+	}
+
+	return itemScoreDiffs
+}
+
+// getNewLargeTables returns the tables of which the rows or the size exceeded the high watermark in the target result,
+// but not in the base result
+func getNewLargeTables(base, target healthcheck.Result) ([]*Table, error) {
+	baseTables, err := getHighTables(base)
+	if err != nil {
+		return nil, err
+	}
+	targetTables, err := getHighTables(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range targetTables {
+		if _, ok := baseTables[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var newLargeTables []*Table
+	for _, name := range names {
+		newLargeTables = append(newLargeTables, targetTables[name])
+	}
+
+	return newLargeTables, nil
+}
+
+// getHighTables returns the tables of which the rows or the size exceeded the high watermark, the key is db_name.table_name
+func getHighTables(result healthcheck.Result) (map[string]*Table, error) {
+	tables := make(map[string]*Table)
+	for _, high := range []string{result.GetTableRowsHigh(), result.GetTableSizeHigh()} {
+		if high == constant.EmptyString {
+			continue
+		}
+		var highTables []*Table
+		err := json.Unmarshal([]byte(high), &highTables)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, table := range highTables {
+			tables[fmt.Sprintf("%s.%s", table.GetSchema(), table.GetName())] = table
+		}
+	}
+
+	return tables, nil
+}
+
+// getNewSlowQueries returns the slow queries which exist in the target result but not in the base result
+func getNewSlowQueries(base, target healthcheck.Result) ([]*query.Query, error) {
+	baseQueries, err := getSlowQueries(base)
+	if err != nil {
+		return nil, err
+	}
+	baseSQLIDs := make(map[string]bool, len(baseQueries))
+	for _, q := range baseQueries {
+		baseSQLIDs[q.GetSQLID()] = true
+	}
+	targetQueries, err := getSlowQueries(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var newSlowQueries []*query.Query
+	for _, q := range targetQueries {
+		if !baseSQLIDs[q.GetSQLID()] {
+			newSlowQueries = append(newSlowQueries, q)
+		}
+	}
+
+	return newSlowQueries, nil
+}
+
+// getSlowQueries returns the slow queries of the result
+func getSlowQueries(result healthcheck.Result) ([]*query.Query, error) {
+	var queries []*query.Query
+	if result.GetSlowQueryData() == constant.EmptyString {
+		return queries, nil
+	}
+	err := json.Unmarshal([]byte(result.GetSlowQueryData()), &queries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return queries, nil
+}
+
+// getConfigChanges returns the variables of which the values differ between the base result and the target result,
+// the variables which only exist in one of the results are also returned
+func getConfigChanges(base, target healthcheck.Result) ([]*ConfigChange, error) {
+	baseVariables, err := getDBConfigVariables(base)
+	if err != nil {
+		return nil, err
+	}
+	targetVariables, err := getDBConfigVariables(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range baseVariables {
+		names = append(names, name)
+	}
+	for name := range targetVariables {
+		if _, ok := baseVariables[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var configChanges []*ConfigChange
+	for _, name := range names {
+		baseValue := baseVariables[name]
+		targetValue := targetVariables[name]
+		if !strings.EqualFold(baseValue, targetValue) {
+			configChanges = append(configChanges, &ConfigChange{VariableName: name, BaseValue: baseValue, TargetValue: targetValue})
+		}
+	}
+
+	return configChanges, nil
+}
+
+// getDBConfigVariables returns the db config variables of the result, the key is the variable name
+func getDBConfigVariables(result healthcheck.Result) (map[string]string, error) {
+	variables := make(map[string]string)
+	if result.GetDBConfigData() == constant.EmptyString {
+		return variables, nil
+	}
+
+	var globalVariables []*GlobalVariable
+	err := json.Unmarshal([]byte(result.GetDBConfigData()), &globalVariables)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, variable := range globalVariables {
+		variables[variable.GetName()] = variable.GetValue()
+	}
+
+	return variables, nil
+}
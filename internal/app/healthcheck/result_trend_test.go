@@ -0,0 +1,162 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/romberli/das/internal/app/query"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testResultTrendBaseOperationID   = 1
+	testResultTrendTargetOperationID = 2
+	testResultTrendDBName            = "das"
+	testResultTrendTableName         = "t_hc_result"
+	testResultTrendNewTableName      = "t_hc_operation_history"
+	testResultTrendSQLID             = "F4F85858E527B409"
+	testResultTrendNewSQLID          = "F9A57DD5A41825CA"
+)
+
+func TestResultTrend_All(t *testing.T) {
+	TestResultTrend_NewTrendPoint(t)
+	TestResultTrend_NewResultDiff(t)
+	TestResultDiff_MarshalJSON(t)
+}
+
+func testInitResultTrendResult(operationID int, score int, tables []*Table, queries []*query.Query, variables []*GlobalVariable) (*Result, error) {
+	result := NewEmptyResultWithOperationIDAndHostInfo(operationID, testResultHostIP, testResultPortNum)
+	result.WeightedAverageScore = score
+
+	tableBytes, err := json.Marshal(tables)
+	if err != nil {
+		return nil, err
+	}
+	result.TableRowsHigh = string(tableBytes)
+	result.TableSizeHigh = defaultEmptyJSONArray
+	queryBytes, err := json.Marshal(queries)
+	if err != nil {
+		return nil, err
+	}
+	result.SlowQueryData = string(queryBytes)
+	variableBytes, err := json.Marshal(variables)
+	if err != nil {
+		return nil, err
+	}
+	result.DBConfigData = string(variableBytes)
+
+	return result, nil
+}
+
+func testInitResultDiff() (*ResultDiff, error) {
+	base, err := testInitResultTrendResult(testResultTrendBaseOperationID, 90,
+		[]*Table{{DBName: testResultTrendDBName, TableName: testResultTrendTableName}},
+		[]*query.Query{{SQLID: testResultTrendSQLID}},
+		[]*GlobalVariable{NewGlobalVariable(dbConfigSyncBinlog, "1"), NewGlobalVariable(dbConfigGTIDMode, "ON"), NewGlobalVariable(dbConfigLogBin, "ON")},
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = base.addItemResult(NewItemResult(testResultTrendBaseOperationID, defaultCPUUsageItemName, DataSourcePrometheus, testItemResultItemWeight,
+		80, constant.EmptyString, constant.EmptyString, constant.EmptyString))
+	if err != nil {
+		return nil, err
+	}
+	err = base.addItemResult(NewItemResult(testResultTrendBaseOperationID, defaultIOUtilItemName, DataSourcePrometheus, testItemResultItemWeight,
+		100, constant.EmptyString, constant.EmptyString, constant.EmptyString))
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := testInitResultTrendResult(testResultTrendTargetOperationID, 70,
+		[]*Table{{DBName: testResultTrendDBName, TableName: testResultTrendTableName}, {DBName: testResultTrendDBName, TableName: testResultTrendNewTableName}},
+		[]*query.Query{{SQLID: testResultTrendSQLID}, {SQLID: testResultTrendNewSQLID}},
+		[]*GlobalVariable{NewGlobalVariable(dbConfigSyncBinlog, "0"), NewGlobalVariable(dbConfigGTIDMode, "on"), NewGlobalVariable(dbConfigBinlogFormat, "ROW")},
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = target.addItemResult(NewItemResult(testResultTrendTargetOperationID, defaultCPUUsageItemName, DataSourcePrometheus, testItemResultItemWeight,
+		50, constant.EmptyString, constant.EmptyString, constant.EmptyString))
+	if err != nil {
+		return nil, err
+	}
+	err = target.addItemResult(&ItemResult{ItemName: defaultIOUtilItemName, Status: ItemStatusError})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResultDiff(base, target)
+}
+
+func TestResultTrend_NewTrendPoint(t *testing.T) {
+	asst := assert.New(t)
+
+	result := NewEmptyResultWithOperationIDAndHostInfo(testResultTrendBaseOperationID, testResultHostIP, testResultPortNum)
+	result.WeightedAverageScore = 85
+	result.CPUUsageScore = 60
+	// the results which were saved before the item results were introduced only have the built-in item scores
+	trendPoint := NewTrendPoint(result)
+	asst.Equal(testResultTrendBaseOperationID, trendPoint.GetOperationID(), "test NewTrendPoint() failed")
+	asst.Equal(85, trendPoint.GetWeightedAverageScore(), "test NewTrendPoint() failed")
+	asst.Equal(12, len(trendPoint.GetItemScores()), "test NewTrendPoint() failed")
+	asst.Equal(60, trendPoint.GetItemScores()[defaultCPUUsageItemName], "test NewTrendPoint() failed")
+	// the built-in items which were not checked are not scored
+	result.IOUtilScore = ResultScoreNotChecked
+	trendPoint = NewTrendPoint(result)
+	asst.Equal(11, len(trendPoint.GetItemScores()), "test NewTrendPoint() failed")
+	_, ok := trendPoint.GetItemScores()[defaultIOUtilItemName]
+	asst.False(ok, "test NewTrendPoint() failed")
+	// the failed items are not scored
+	err := result.addItemResult(NewItemResult(testResultTrendBaseOperationID, testItemConfigName, DataSourcePrometheus, testItemResultItemWeight,
+		50, constant.EmptyString, constant.EmptyString, constant.EmptyString))
+	asst.Nil(err, common.CombineMessageWithError("test NewTrendPoint() failed", err))
+	err = result.addItemResult(&ItemResult{ItemName: defaultIOUtilItemName, Status: ItemStatusError})
+	asst.Nil(err, common.CombineMessageWithError("test NewTrendPoint() failed", err))
+	trendPoint = NewTrendPoint(result)
+	asst.Equal(map[string]int{testItemConfigName: 50}, trendPoint.GetItemScores(), "test NewTrendPoint() failed")
+}
+
+func TestResultTrend_NewResultDiff(t *testing.T) {
+	asst := assert.New(t)
+
+	resultDiff, err := testInitResultDiff()
+	asst.Nil(err, common.CombineMessageWithError("test NewResultDiff() failed", err))
+	asst.Equal(testResultTrendBaseOperationID, resultDiff.GetBaseOperationID(), "test NewResultDiff() failed")
+	asst.Equal(testResultTrendTargetOperationID, resultDiff.GetTargetOperationID(), "test NewResultDiff() failed")
+	asst.Equal(-20, resultDiff.GetWeightedAverageScoreDelta(), "test NewResultDiff() failed")
+	// cpu_usage dropped, io_util failed in the target operation
+	asst.Equal(2, len(resultDiff.ItemScoreDiffs), "test NewResultDiff() failed")
+	asst.Equal(defaultCPUUsageItemName, resultDiff.ItemScoreDiffs[constant.ZeroInt].ItemName, "test NewResultDiff() failed")
+	asst.Equal(-30, resultDiff.ItemScoreDiffs[constant.ZeroInt].Delta, "test NewResultDiff() failed")
+	asst.Equal(defaultIOUtilItemName, resultDiff.ItemScoreDiffs[1].ItemName, "test NewResultDiff() failed")
+	asst.Equal(itemNotScoredInTargetMessage, resultDiff.ItemScoreDiffs[1].Message, "test NewResultDiff() failed")
+	// large tables and slow queries
+	asst.Equal(1, len(resultDiff.NewLargeTables), "test NewResultDiff() failed")
+	asst.Equal(testResultTrendNewTableName, resultDiff.NewLargeTables[constant.ZeroInt].GetName(), "test NewResultDiff() failed")
+	asst.Equal(1, len(resultDiff.NewSlowQueries), "test NewResultDiff() failed")
+	asst.Equal(testResultTrendNewSQLID, resultDiff.NewSlowQueries[constant.ZeroInt].GetSQLID(), "test NewResultDiff() failed")
+	// binlog_format was added, log_bin was removed, sync_binlog changed, gtid_mode is the same with different case
+	asst.Equal(3, len(resultDiff.ConfigChanges), "test NewResultDiff() failed")
+	asst.Equal(dbConfigBinlogFormat, resultDiff.ConfigChanges[constant.ZeroInt].VariableName, "test NewResultDiff() failed")
+	asst.Equal(constant.EmptyString, resultDiff.ConfigChanges[constant.ZeroInt].BaseValue, "test NewResultDiff() failed")
+	asst.Equal(dbConfigLogBin, resultDiff.ConfigChanges[1].VariableName, "test NewResultDiff() failed")
+	asst.Equal(constant.EmptyString, resultDiff.ConfigChanges[1].TargetValue, "test NewResultDiff() failed")
+	asst.Equal(dbConfigSyncBinlog, resultDiff.ConfigChanges[2].VariableName, "test NewResultDiff() failed")
+	asst.Equal("0", resultDiff.ConfigChanges[2].TargetValue, "test NewResultDiff() failed")
+}
+
+func TestResultDiff_MarshalJSON(t *testing.T) {
+	asst := assert.New(t)
+
+	resultDiff, err := testInitResultDiff()
+	asst.Nil(err, common.CombineMessageWithError("test MarshalJSON() failed", err))
+	jsonBytes, err := resultDiff.MarshalJSON()
+	asst.Nil(err, common.CombineMessageWithError("test MarshalJSON() failed", err))
+	var m map[string]interface{}
+	err = json.Unmarshal(jsonBytes, &m)
+	asst.Nil(err, common.CombineMessageWithError("test MarshalJSON() failed", err))
+	asst.Equal(float64(testResultTrendTargetOperationID), m["target_operation_id"], "test MarshalJSON() failed")
+}
@@ -0,0 +1,162 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const retentionPolicyFullDataDaysStruct = "FullDataDays"
+
+var (
+	_ healthcheck.RetentionPolicy = (*RetentionPolicy)(nil)
+	_ healthcheck.RetentionStat   = (*RetentionStat)(nil)
+)
+
+// RetentionPolicy is the retention policy of the healthcheck data of an env
+type RetentionPolicy struct {
+	healthcheck.RetentionRepo
+	ID             int       `middleware:"id" json:"id"`
+	EnvID          int       `middleware:"env_id" json:"env_id"`
+	FullDataDays   int       `middleware:"full_data_days" json:"full_data_days"`
+	DelFlag        int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime     time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewRetentionPolicy returns a new *RetentionPolicy
+func NewRetentionPolicy(repo healthcheck.RetentionRepo, id, envID, fullDataDays, delFlag int, createTime, lastUpdateTime time.Time) *RetentionPolicy {
+	return &RetentionPolicy{
+		RetentionRepo:  repo,
+		ID:             id,
+		EnvID:          envID,
+		FullDataDays:   fullDataDays,
+		DelFlag:        delFlag,
+		CreateTime:     createTime,
+		LastUpdateTime: lastUpdateTime,
+	}
+}
+
+// NewRetentionPolicyWithDefault returns a new *RetentionPolicy with default RetentionRepo
+func NewRetentionPolicyWithDefault(envID, fullDataDays int) *RetentionPolicy {
+	return &RetentionPolicy{
+		RetentionRepo: NewRetentionRepoWithGlobal(),
+		EnvID:         envID,
+		FullDataDays:  fullDataDays,
+	}
+}
+
+// NewEmptyRetentionPolicyWithGlobal returns a new *RetentionPolicy with global repository
+func NewEmptyRetentionPolicyWithGlobal() *RetentionPolicy {
+	return &RetentionPolicy{RetentionRepo: NewRetentionRepoWithGlobal()}
+}
+
+// Identity returns the identity
+func (rp *RetentionPolicy) Identity() int {
+	return rp.ID
+}
+
+// GetEnvID returns the env id
+func (rp *RetentionPolicy) GetEnvID() int {
+	return rp.EnvID
+}
+
+// GetFullDataDays returns the days to keep the full data, only the scores will be kept after that
+func (rp *RetentionPolicy) GetFullDataDays() int {
+	return rp.FullDataDays
+}
+
+// GetDelFlag returns the delete flag
+func (rp *RetentionPolicy) GetDelFlag() int {
+	return rp.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (rp *RetentionPolicy) GetCreateTime() time.Time {
+	return rp.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (rp *RetentionPolicy) GetLastUpdateTime() time.Time {
+	return rp.LastUpdateTime
+}
+
+// Set sets entity with given fields, key is the field name and value is the relevant value of the key
+func (rp *RetentionPolicy) Set(fields map[string]interface{}) error {
+	for fieldName, fieldValue := range fields {
+		err := common.SetValueOfStruct(rp, fieldName, fieldValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete sets DelFlag to 1
+func (rp *RetentionPolicy) Delete() {
+	rp.DelFlag = 1
+}
+
+// MarshalJSON marshals RetentionPolicy to json string
+func (rp *RetentionPolicy) MarshalJSON() ([]byte, error) {
+	return common.MarshalStructWithTag(rp, constant.DefaultMarshalTag)
+}
+
+// MarshalJSONWithFields marshals only specified fields of RetentionPolicy to json string
+func (rp *RetentionPolicy) MarshalJSONWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(rp, fields...)
+}
+
+// RetentionStat is the statistics of the expired healthcheck data of an env
+type RetentionStat struct {
+	EnvID               int   `middleware:"env_id" json:"env_id"`
+	OperationNum        int   `middleware:"operation_num" json:"operation_num"`
+	DeletedOperationNum int   `middleware:"deleted_operation_num" json:"deleted_operation_num"`
+	ResultNum           int   `middleware:"result_num" json:"result_num"`
+	ItemResultNum       int   `middleware:"item_result_num" json:"item_result_num"`
+	SnapshotNum         int   `middleware:"snapshot_num" json:"snapshot_num"`
+	Bytes               int64 `middleware:"bytes" json:"bytes"`
+}
+
+// NewEmptyRetentionStat returns an empty *RetentionStat
+func NewEmptyRetentionStat() *RetentionStat {
+	return &RetentionStat{}
+}
+
+// GetEnvID returns the env id, it is 0 if the mysql server does not exist in the metadata any more
+func (rs *RetentionStat) GetEnvID() int {
+	return rs.EnvID
+}
+
+// GetOperationNum returns the number of the expired operations
+func (rs *RetentionStat) GetOperationNum() int {
+	return rs.OperationNum
+}
+
+// GetDeletedOperationNum returns the number of the expired operations which have no result, they will be removed only if purging is enabled
+func (rs *RetentionStat) GetDeletedOperationNum() int {
+	return rs.DeletedOperationNum
+}
+
+// GetResultNum returns the number of the results of which the data will be cleared
+func (rs *RetentionStat) GetResultNum() int {
+	return rs.ResultNum
+}
+
+// GetItemResultNum returns the number of the item results of which the data will be cleared
+func (rs *RetentionStat) GetItemResultNum() int {
+	return rs.ItemResultNum
+}
+
+// GetSnapshotNum returns the number of the snapshots which will be removed
+func (rs *RetentionStat) GetSnapshotNum() int {
+	return rs.SnapshotNum
+}
+
+// GetBytes returns the bytes of the data which will be cleared or removed
+func (rs *RetentionStat) GetBytes() int64 {
+	return rs.Bytes
+}
@@ -0,0 +1,385 @@
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware"
+	"github.com/romberli/log"
+)
+
+// retentionExpiredClause is the from and where clause of the operations of which the full data expired,
+// the queued and running operations are never expired,
+// the mysql servers which do not exist in the metadata any more use the default full data days
+const retentionExpiredClause = `
+		from t_hc_operation_history oh
+			left join t_meta_mysql_server_info msi on oh.mysql_server_id = msi.id
+			left join t_meta_mysql_cluster_info mci on msi.cluster_id = mci.id
+			left join t_hc_retention_policy rp on mci.env_id = rp.env_id and rp.del_flag = 0
+		where oh.del_flag = 0
+		  and oh.archive_flag = 0
+		  and oh.status in (2, 3, 4, 5)
+		  and oh.create_time < date_sub(now(), interval coalesce(rp.full_data_days, ?) day)
+`
+
+// retentionMiddlewareExpiredClause is the from and where clause of the middleware operations of which the full data expired,
+// the middleware clusters which do not exist in the metadata any more use the default full data days
+const retentionMiddlewareExpiredClause = `
+		from t_hc_middleware_result mr
+			inner join t_hc_middleware_operation_history moh on mr.middleware_operation_id = moh.id
+			left join t_meta_middleware_cluster_info mci on moh.middleware_cluster_id = mci.id
+			left join t_hc_retention_policy rp on mci.env_id = rp.env_id and rp.del_flag = 0
+		where mr.del_flag = 0
+		  and (mr.server_result <> '' or mr.endpoint_result <> '')
+		  and moh.create_time < date_sub(now(), interval coalesce(rp.full_data_days, ?) day)
+`
+
+// retentionResultDataColumns are the columns of t_hc_result which will be cleared when archiving
+var retentionResultDataColumns = []string{
+	"db_config_data", "db_config_advice",
+	"avg_backup_failed_ratio_data", "avg_backup_failed_ratio_high",
+	"statistics_failed_ratio_data", "statistics_failed_ratio_high",
+	"cpu_usage_data", "cpu_usage_high",
+	"io_util_data", "io_util_high",
+	"disk_capacity_usage_data", "disk_capacity_usage_high",
+	"connection_usage_data", "connection_usage_high",
+	"average_active_session_percents_data", "average_active_session_percents_high",
+	"cache_miss_ratio_data", "cache_miss_ratio_high",
+	"table_rows_data", "table_rows_high",
+	"table_size_data", "table_size_high",
+	"slow_query_data", "slow_query_advice",
+}
+
+// retentionClusterResultDataColumns are the columns of t_hc_cluster_result which will be cleared when archiving
+var retentionClusterResultDataColumns = []string{"node_result", "config_drift"}
+
+// retentionMiddlewareResultDataColumns are the columns of t_hc_middleware_result which will be cleared when archiving
+var retentionMiddlewareResultDataColumns = []string{"server_result", "endpoint_result"}
+
+var _ healthcheck.RetentionRepo = (*RetentionRepo)(nil)
+
+// RetentionRepo is the repository of the healthcheck retention policy and the archive of the healthcheck data
+type RetentionRepo struct {
+	Database middleware.Pool
+}
+
+// NewRetentionRepo returns healthcheck.RetentionRepo with given middleware.Pool
+func NewRetentionRepo(db middleware.Pool) healthcheck.RetentionRepo {
+	return newRetentionRepo(db)
+}
+
+// NewRetentionRepoWithGlobal returns healthcheck.RetentionRepo with global mysql pool
+func NewRetentionRepoWithGlobal() healthcheck.RetentionRepo {
+	return newRetentionRepo(global.DASMySQLPool)
+}
+
+// newRetentionRepo returns *RetentionRepo with given middleware.Pool
+func newRetentionRepo(db middleware.Pool) *RetentionRepo {
+	return &RetentionRepo{Database: db}
+}
+
+// Execute executes given command and placeholders on the middleware
+func (rr *RetentionRepo) Execute(command string, args ...interface{}) (middleware.Result, error) {
+	conn, err := rr.Database.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = conn.Close()
+		if err != nil {
+			log.Errorf("healthcheck RetentionRepo.Execute(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	return conn.Execute(command, args...)
+}
+
+// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+func (rr *RetentionRepo) Transaction() (middleware.Transaction, error) {
+	return rr.Database.Transaction()
+}
+
+// GetAll gets all retention policies from the middleware
+func (rr *RetentionRepo) GetAll() ([]healthcheck.RetentionPolicy, error) {
+	sql := `
+		select id, env_id, full_data_days, del_flag, create_time, last_update_time
+		from t_hc_retention_policy
+		where del_flag = 0
+		order by env_id;
+	`
+	log.Debugf("healthcheck RetentionRepo.GetAll() sql: \n%s", sql)
+
+	result, err := rr.Execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	// init []healthcheck.RetentionPolicy
+	policyList := make([]healthcheck.RetentionPolicy, result.RowNumber())
+	for i := range policyList {
+		policyList[i] = NewEmptyRetentionPolicyWithGlobal()
+	}
+	// map to struct
+	err = result.MapToStructSlice(policyList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return policyList, nil
+}
+
+// GetByEnvID gets the retention policy of the env from the middleware
+func (rr *RetentionRepo) GetByEnvID(envID int) (healthcheck.RetentionPolicy, error) {
+	sql := `
+		select id, env_id, full_data_days, del_flag, create_time, last_update_time
+		from t_hc_retention_policy
+		where del_flag = 0
+		  and env_id = ?;
+	`
+	log.Debugf("healthcheck RetentionRepo.GetByEnvID() sql: \n%s\nplaceholders: %d", sql, envID)
+
+	result, err := rr.Execute(sql, envID)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, errors.Errorf("healthcheck RetentionRepo.GetByEnvID(): data does not exists, env_id: %d", envID)
+	case 1:
+		policy := NewEmptyRetentionPolicyWithGlobal()
+		// map to struct
+		err = result.MapToStructByRowIndex(policy, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+
+		return policy, nil
+	default:
+		return nil, errors.Errorf("healthcheck RetentionRepo.GetByEnvID(): duplicate key exists, env_id: %d", envID)
+	}
+}
+
+// Create creates a retention policy in the middleware
+func (rr *RetentionRepo) Create(policy healthcheck.RetentionPolicy) (healthcheck.RetentionPolicy, error) {
+	sql := `insert into t_hc_retention_policy(env_id, full_data_days) values(?, ?);`
+	log.Debugf("healthcheck RetentionRepo.Create() insert sql: \n%s\nplaceholders: %d, %d", sql, policy.GetEnvID(), policy.GetFullDataDays())
+	// execute
+	_, err := rr.Execute(sql, policy.GetEnvID(), policy.GetFullDataDays())
+	if err != nil {
+		return nil, err
+	}
+	// get retention policy
+	return rr.GetByEnvID(policy.GetEnvID())
+}
+
+// Update updates the retention policy in the middleware
+func (rr *RetentionRepo) Update(policy healthcheck.RetentionPolicy) error {
+	sql := `update t_hc_retention_policy set env_id = ?, full_data_days = ?, del_flag = ? where id = ?;`
+	log.Debugf("healthcheck RetentionRepo.Update() update sql: \n%s\nplaceholders: %d, %d, %d, %d",
+		sql, policy.GetEnvID(), policy.GetFullDataDays(), policy.GetDelFlag(), policy.Identity())
+	_, err := rr.Execute(sql, policy.GetEnvID(), policy.GetFullDataDays(), policy.GetDelFlag(), policy.Identity())
+
+	return err
+}
+
+// Delete deletes the retention policy in the middleware
+func (rr *RetentionRepo) Delete(id int) error {
+	sql := `delete from t_hc_retention_policy where id = ?;`
+	log.Debugf("healthcheck RetentionRepo.Delete() delete sql: \n%s\nplaceholders: %d", sql, id)
+	_, err := rr.Execute(sql, id)
+
+	return err
+}
+
+// GetExpiredOperationIDs gets the ids of the operations of which the full data expired,
+// defaultFullDataDays is used for the envs which have no retention policy, at most limit ids will be returned
+func (rr *RetentionRepo) GetExpiredOperationIDs(defaultFullDataDays, limit int) ([]int, error) {
+	sql := `select oh.id` + retentionExpiredClause + `		order by oh.id limit ?;`
+	log.Debugf("healthcheck RetentionRepo.GetExpiredOperationIDs() select sql: \n%s\nplaceholders: %d, %d", sql, defaultFullDataDays, limit)
+
+	result, err := rr.Execute(sql, defaultFullDataDays, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	operationIDs := make([]int, result.RowNumber())
+	for i := range operationIDs {
+		operationIDs[i], err = result.GetInt(i, constant.ZeroInt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return operationIDs, nil
+}
+
+// GetRetentionStats gets the statistics of the expired data of each env from the middleware
+func (rr *RetentionRepo) GetRetentionStats(defaultFullDataDays int) ([]healthcheck.RetentionStat, error) {
+	resultLengths := make([]string, len(retentionResultDataColumns))
+	for i, column := range retentionResultDataColumns {
+		resultLengths[i] = fmt.Sprintf("coalesce(length(r.%s), 0)", column)
+	}
+
+	sql := `
+		select t.env_id,
+			   cast(count(*) as signed) as operation_num,
+			   cast(sum(t.result_num = 0) as signed) as deleted_operation_num,
+			   cast(sum(t.result_num) as signed) as result_num,
+			   cast(sum(t.item_result_num) as signed) as item_result_num,
+			   cast(sum(t.snapshot_num) as signed) as snapshot_num,
+			   cast(sum(t.bytes) as signed) as bytes
+		from (
+			select coalesce(mci.env_id, 0) as env_id,
+				   (select count(*) from t_hc_result r where r.operation_id = oh.id) as result_num,
+				   (select count(*) from t_hc_item_result ir where ir.operation_id = oh.id) as item_result_num,
+				   (select count(*) from t_hc_snapshot s where s.operation_id = oh.id) as snapshot_num,
+				   coalesce((select sum(%s) from t_hc_result r where r.operation_id = oh.id), 0)
+				   + coalesce((select sum(coalesce(length(ir.data), 0) + coalesce(length(ir.high), 0) + coalesce(length(ir.advice), 0))
+					   from t_hc_item_result ir where ir.operation_id = oh.id), 0)
+				   + coalesce((select sum(length(s.snapshot)) from t_hc_snapshot s where s.operation_id = oh.id), 0) as bytes
+		%s
+		) t
+		group by t.env_id
+		order by t.env_id;
+	`
+	sql = fmt.Sprintf(sql, strings.Join(resultLengths, " + "), retentionExpiredClause)
+	log.Debugf("healthcheck RetentionRepo.GetRetentionStats() select sql: \n%s\nplaceholders: %d", sql, defaultFullDataDays)
+
+	result, err := rr.Execute(sql, defaultFullDataDays)
+	if err != nil {
+		return nil, err
+	}
+	// init []healthcheck.RetentionStat
+	statList := make([]healthcheck.RetentionStat, result.RowNumber())
+	for i := range statList {
+		statList[i] = NewEmptyRetentionStat()
+	}
+	// map to struct
+	err = result.MapToStructSlice(statList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return statList, nil
+}
+
+// GetExpiredMiddlewareOperationIDs gets the ids of the middleware operations of which the full data expired,
+// defaultFullDataDays is used for the envs which have no retention policy, at most limit ids will be returned
+func (rr *RetentionRepo) GetExpiredMiddlewareOperationIDs(defaultFullDataDays, limit int) ([]int, error) {
+	sql := `select mr.middleware_operation_id` + retentionMiddlewareExpiredClause + `		order by mr.middleware_operation_id limit ?;`
+	log.Debugf("healthcheck RetentionRepo.GetExpiredMiddlewareOperationIDs() select sql: \n%s\nplaceholders: %d, %d", sql, defaultFullDataDays, limit)
+
+	result, err := rr.Execute(sql, defaultFullDataDays, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	middlewareOperationIDs := make([]int, result.RowNumber())
+	for i := range middlewareOperationIDs {
+		middlewareOperationIDs[i], err = result.GetInt(i, constant.ZeroInt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return middlewareOperationIDs, nil
+}
+
+// GetPurgeableOperationIDs gets the ids of the archived operations which have no result, such as the failed and the cancelled ones,
+// at most limit ids will be returned
+func (rr *RetentionRepo) GetPurgeableOperationIDs(limit int) ([]int, error) {
+	sql := `
+		select oh.id
+		from t_hc_operation_history oh
+		where oh.del_flag = 0
+		  and oh.archive_flag = 1
+		  and not exists (select 1 from t_hc_result r where r.operation_id = oh.id)
+		order by oh.id limit ?;
+	`
+	log.Debugf("healthcheck RetentionRepo.GetPurgeableOperationIDs() select sql: \n%s\nplaceholders: %d", sql, limit)
+
+	result, err := rr.Execute(sql, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	operationIDs := make([]int, result.RowNumber())
+	for i := range operationIDs {
+		operationIDs[i], err = result.GetInt(i, constant.ZeroInt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return operationIDs, nil
+}
+
+// Archive clears the data of the operations as a transaction, only the scores will be kept,
+// the data of the cluster results of the operations will be cleared as well,
+// the operation histories and the feedbacks are always kept, the operations are marked as archived
+func (rr *RetentionRepo) Archive(operationIDs []int) error {
+	if len(operationIDs) == constant.ZeroInt {
+		return nil
+	}
+	ol, err := common.ConvertInterfaceToSliceInterface(operationIDs)
+	if err != nil {
+		return err
+	}
+	inClause, err := middleware.ConvertSliceToString(ol...)
+	if err != nil {
+		return err
+	}
+
+	sqlList := []string{
+		fmt.Sprintf(`update t_hc_result set %s where operation_id in (%s);`, getRetentionSetClause(retentionResultDataColumns), inClause),
+		fmt.Sprintf(`update t_hc_cluster_result set %s where cluster_operation_id in (select cluster_operation_id from t_hc_operation_history where id in (%s) and cluster_operation_id > 0);`,
+			getRetentionSetClause(retentionClusterResultDataColumns), inClause),
+		fmt.Sprintf(`update t_hc_item_result set data = '', high = '', advice = '' where operation_id in (%s);`, inClause),
+		fmt.Sprintf(`delete from t_hc_snapshot where operation_id in (%s);`, inClause),
+		fmt.Sprintf(`delete from t_hc_operation_progress where operation_id in (%s);`, inClause),
+		fmt.Sprintf(`delete from t_hc_operation_callback where operation_id in (%s);`, inClause),
+		fmt.Sprintf(`update t_hc_operation_history set archive_flag = 1 where id in (%s);`, inClause),
+	}
+
+	return rr.executeInTransaction(sqlList)
+}
+
+// Purge removes the archived operations which have no result as a transaction, together with their item results and feedbacks,
+// the operations which are not archived or have results are never removed
+func (rr *RetentionRepo) Purge(operationIDs []int) error {
+	if len(operationIDs) == constant.ZeroInt {
+		return nil
+	}
+	ol, err := common.ConvertInterfaceToSliceInterface(operationIDs)
+	if err != nil {
+		return err
+	}
+	inClause, err := middleware.ConvertSliceToString(ol...)
+	if err != nil {
+		return err
+	}
+	// the operations which are archived and have no result
+	purgeableClause := fmt.Sprintf(`select oh.id from t_hc_operation_history oh where oh.id in (%s) and oh.archive_flag = 1 `+
+		`and not exists (select 1 from t_hc_result r where r.operation_id = oh.id)`, inClause)
+
+	return rr.executeInTransaction([]string{
+		fmt.Sprintf(`delete from t_hc_item_result where operation_id in (%s);`, purgeableClause),
+		fmt.Sprintf(`delete from t_hc_item_feedback where operation_id in (%s);`, purgeableClause),
+		fmt.Sprintf(`delete from t_hc_operation_history where id in (%s) and archive_flag = 1 `+
+			`and not exists (select 1 from t_hc_result r where r.operation_id = t_hc_operation_history.id);`, inClause),
+	})
+}
+
+// getRetentionSetClause returns the set clause which clears given columns
+func getRetentionSetClause(columns []string) string {
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = column + ` = ''`
+	}
+
+	return strings.Join(sets, ", ")
+}
@@ -0,0 +1,230 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRetentionEnvID              = 1
+	testRetentionFullDataDays       = 180
+	testRetentionUpdateFullDataDays = 365
+	testRetentionDefaultDays        = 90
+	testRetentionLimit              = 10
+)
+
+var testRetentionRepo *RetentionRepo
+
+func init() {
+	testInitDASMySQLPool()
+	testRetentionRepo = newRetentionRepo(global.DASMySQLPool)
+}
+
+func testCreateRetentionPolicy() (healthcheck.RetentionPolicy, error) {
+	return testRetentionRepo.Create(NewRetentionPolicyWithDefault(testRetentionEnvID, testRetentionFullDataDays))
+}
+
+func testDeleteRetentionPolicyByID(id int) error {
+	return testRetentionRepo.Delete(id)
+}
+
+func TestRetentionRepo_All(t *testing.T) {
+	TestRetentionRepo_Execute(t)
+	TestRetentionRepo_Transaction(t)
+	TestRetentionRepo_GetAll(t)
+	TestRetentionRepo_GetByEnvID(t)
+	TestRetentionRepo_Create(t)
+	TestRetentionRepo_Update(t)
+	TestRetentionRepo_Delete(t)
+	TestRetentionRepo_GetExpiredOperationIDs(t)
+	TestRetentionRepo_GetRetentionStats(t)
+	TestRetentionRepo_GetExpiredMiddlewareOperationIDs(t)
+	TestRetentionRepo_Archive(t)
+	TestRetentionRepo_ArchiveMiddleware(t)
+	TestRetentionRepo_GetPurgeableOperationIDs(t)
+	TestRetentionRepo_Purge(t)
+}
+
+func TestRetentionRepo_Execute(t *testing.T) {
+	asst := assert.New(t)
+
+	sql := `select 1;`
+	result, err := testRetentionRepo.Execute(sql)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	r, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	asst.Equal(1, r, "test Execute() failed")
+}
+
+func TestRetentionRepo_Transaction(t *testing.T) {
+	asst := assert.New(t)
+
+	tx, err := testRetentionRepo.Transaction()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Begin()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Rollback()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+}
+
+func TestRetentionRepo_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	policy, err := testCreateRetentionPolicy()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	policies, err := testRetentionRepo.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	asst.NotZero(len(policies), "test GetAll() failed")
+	// delete
+	err = testDeleteRetentionPolicyByID(policy.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestRetentionRepo_GetByEnvID(t *testing.T) {
+	asst := assert.New(t)
+
+	policy, err := testCreateRetentionPolicy()
+	asst.Nil(err, common.CombineMessageWithError("test GetByEnvID() failed", err))
+	entity, err := testRetentionRepo.GetByEnvID(testRetentionEnvID)
+	asst.Nil(err, common.CombineMessageWithError("test GetByEnvID() failed", err))
+	asst.Equal(testRetentionFullDataDays, entity.GetFullDataDays(), "test GetByEnvID() failed")
+	// delete
+	err = testDeleteRetentionPolicyByID(policy.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByEnvID() failed", err))
+}
+
+func TestRetentionRepo_Create(t *testing.T) {
+	asst := assert.New(t)
+
+	policy, err := testCreateRetentionPolicy()
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	asst.Equal(testRetentionEnvID, policy.GetEnvID(), "test Create() failed")
+	// delete
+	err = testDeleteRetentionPolicyByID(policy.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+}
+
+func TestRetentionRepo_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	policy, err := testCreateRetentionPolicy()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = policy.Set(map[string]interface{}{retentionPolicyFullDataDaysStruct: testRetentionUpdateFullDataDays})
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testRetentionRepo.Update(policy)
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	entity, err := testRetentionRepo.GetByEnvID(testRetentionEnvID)
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testRetentionUpdateFullDataDays, entity.GetFullDataDays(), "test Update() failed")
+	// delete
+	err = testDeleteRetentionPolicyByID(policy.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestRetentionRepo_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	policy, err := testCreateRetentionPolicy()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testDeleteRetentionPolicyByID(policy.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	_, err = testRetentionRepo.GetByEnvID(testRetentionEnvID)
+	asst.NotNil(err, "test Delete() failed")
+}
+
+func TestRetentionRepo_GetExpiredOperationIDs(t *testing.T) {
+	asst := assert.New(t)
+
+	operationIDs, err := testRetentionRepo.GetExpiredOperationIDs(testRetentionDefaultDays, testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test GetExpiredOperationIDs() failed", err))
+	asst.LessOrEqual(len(operationIDs), testRetentionLimit, "test GetExpiredOperationIDs() failed")
+}
+
+func TestRetentionRepo_GetRetentionStats(t *testing.T) {
+	asst := assert.New(t)
+
+	stats, err := testRetentionRepo.GetRetentionStats(testRetentionDefaultDays)
+	asst.Nil(err, common.CombineMessageWithError("test GetRetentionStats() failed", err))
+	for _, stat := range stats {
+		asst.GreaterOrEqual(stat.GetOperationNum(), stat.GetDeletedOperationNum(), "test GetRetentionStats() failed")
+	}
+}
+
+func TestRetentionRepo_GetExpiredMiddlewareOperationIDs(t *testing.T) {
+	asst := assert.New(t)
+
+	middlewareOperationIDs, err := testRetentionRepo.GetExpiredMiddlewareOperationIDs(testRetentionDefaultDays, testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test GetExpiredMiddlewareOperationIDs() failed", err))
+	asst.LessOrEqual(len(middlewareOperationIDs), testRetentionLimit, "test GetExpiredMiddlewareOperationIDs() failed")
+}
+
+func TestRetentionRepo_Archive(t *testing.T) {
+	asst := assert.New(t)
+
+	operationIDs, err := testRetentionRepo.GetExpiredOperationIDs(testRetentionDefaultDays, testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test Archive() failed", err))
+	if len(operationIDs) == constant.ZeroInt {
+		return
+	}
+	err = testRetentionRepo.Archive(operationIDs)
+	asst.Nil(err, common.CombineMessageWithError("test Archive() failed", err))
+	expiredIDs, err := testRetentionRepo.GetExpiredOperationIDs(testRetentionDefaultDays, testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test Archive() failed", err))
+	for _, id := range expiredIDs {
+		asst.NotContains(operationIDs, id, "test Archive() failed")
+	}
+	// the operation histories are kept and marked as archived
+	sql := `select count(*) from t_hc_operation_history where id = ? and archive_flag = 1;`
+	result, err := testRetentionRepo.Execute(sql, operationIDs[constant.ZeroInt])
+	asst.Nil(err, common.CombineMessageWithError("test Archive() failed", err))
+	count, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test Archive() failed", err))
+	asst.Equal(1, count, "test Archive() failed")
+}
+
+func TestRetentionRepo_ArchiveMiddleware(t *testing.T) {
+	asst := assert.New(t)
+
+	middlewareOperationIDs, err := testRetentionRepo.GetExpiredMiddlewareOperationIDs(testRetentionDefaultDays, testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test ArchiveMiddleware() failed", err))
+	if len(middlewareOperationIDs) == constant.ZeroInt {
+		return
+	}
+	err = testRetentionRepo.ArchiveMiddleware(middlewareOperationIDs)
+	asst.Nil(err, common.CombineMessageWithError("test ArchiveMiddleware() failed", err))
+	expiredIDs, err := testRetentionRepo.GetExpiredMiddlewareOperationIDs(testRetentionDefaultDays, testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test ArchiveMiddleware() failed", err))
+	for _, id := range expiredIDs {
+		asst.NotContains(middlewareOperationIDs, id, "test ArchiveMiddleware() failed")
+	}
+}
+
+func TestRetentionRepo_GetPurgeableOperationIDs(t *testing.T) {
+	asst := assert.New(t)
+
+	operationIDs, err := testRetentionRepo.GetPurgeableOperationIDs(testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test GetPurgeableOperationIDs() failed", err))
+	asst.LessOrEqual(len(operationIDs), testRetentionLimit, "test GetPurgeableOperationIDs() failed")
+}
+
+func TestRetentionRepo_Purge(t *testing.T) {
+	asst := assert.New(t)
+
+	operationIDs, err := testRetentionRepo.GetPurgeableOperationIDs(testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test Purge() failed", err))
+	if len(operationIDs) == constant.ZeroInt {
+		return
+	}
+	err = testRetentionRepo.Purge(operationIDs)
+	asst.Nil(err, common.CombineMessageWithError("test Purge() failed", err))
+	purgeableIDs, err := testRetentionRepo.GetPurgeableOperationIDs(testRetentionLimit)
+	asst.Nil(err, common.CombineMessageWithError("test Purge() failed", err))
+	for _, id := range purgeableIDs {
+		asst.NotContains(operationIDs, id, "test Purge() failed")
+	}
+}
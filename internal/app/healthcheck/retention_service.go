@@ -0,0 +1,150 @@
+package healthcheck
+
+import (
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/viper"
+)
+
+const (
+	retentionRetentionPoliciesStruct = "RetentionPolicies"
+	retentionRetentionStatsStruct    = "RetentionStats"
+)
+
+var _ healthcheck.RetentionService = (*RetentionService)(nil)
+
+// RetentionService is the service of the healthcheck retention policy
+type RetentionService struct {
+	healthcheck.RetentionRepo
+	RetentionPolicies []healthcheck.RetentionPolicy `json:"retention_policies"`
+	RetentionStats    []healthcheck.RetentionStat   `json:"retention_stats"`
+}
+
+// NewRetentionService returns a new healthcheck.RetentionService
+func NewRetentionService(repo healthcheck.RetentionRepo) healthcheck.RetentionService {
+	return newRetentionService(repo)
+}
+
+// NewRetentionServiceWithDefault returns a new healthcheck.RetentionService with default repository
+func NewRetentionServiceWithDefault() healthcheck.RetentionService {
+	return newRetentionService(NewRetentionRepoWithGlobal())
+}
+
+// newRetentionService returns a new *RetentionService
+func newRetentionService(repo healthcheck.RetentionRepo) *RetentionService {
+	return &RetentionService{
+		RetentionRepo:     repo,
+		RetentionPolicies: []healthcheck.RetentionPolicy{},
+		RetentionStats:    []healthcheck.RetentionStat{},
+	}
+}
+
+// GetRetentionPolicies returns the retention policies of the service
+func (rs *RetentionService) GetRetentionPolicies() []healthcheck.RetentionPolicy {
+	return rs.RetentionPolicies
+}
+
+// GetRetentionStats returns the retention statistics of the service
+func (rs *RetentionService) GetRetentionStats() []healthcheck.RetentionStat {
+	return rs.RetentionStats
+}
+
+// GetAll gets all retention policies from the middleware
+func (rs *RetentionService) GetAll() error {
+	var err error
+
+	rs.RetentionPolicies, err = rs.RetentionRepo.GetAll()
+
+	return err
+}
+
+// GetByEnvID gets the retention policy of the env from the middleware
+func (rs *RetentionService) GetByEnvID(envID int) error {
+	policy, err := rs.RetentionRepo.GetByEnvID(envID)
+	if err != nil {
+		return err
+	}
+
+	rs.RetentionPolicies = nil
+	rs.RetentionPolicies = append(rs.RetentionPolicies, policy)
+
+	return nil
+}
+
+// Save creates the retention policy of the env, or updates it if it exists
+func (rs *RetentionService) Save(envID, fullDataDays int) error {
+	if fullDataDays < config.MinHealthcheckRetentionFullDataDays || fullDataDays > config.MaxHealthcheckRetentionFullDataDays {
+		return message.NewMessage(message.ErrNotValidHealthcheckRetentionDays,
+			config.MinHealthcheckRetentionFullDataDays, config.MaxHealthcheckRetentionFullDataDays, fullDataDays)
+	}
+	// check if the env exists
+	err := metadata.NewEnvServiceWithDefault().GetByID(envID)
+	if err != nil {
+		return err
+	}
+
+	policies, err := rs.RetentionRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if policy.GetEnvID() != envID {
+			continue
+		}
+		// update the existing retention policy
+		err = policy.Set(map[string]interface{}{retentionPolicyFullDataDaysStruct: fullDataDays})
+		if err != nil {
+			return err
+		}
+		err = rs.RetentionRepo.Update(policy)
+		if err != nil {
+			return err
+		}
+
+		return rs.GetByEnvID(envID)
+	}
+	// create a new retention policy
+	policy, err := rs.RetentionRepo.Create(NewRetentionPolicyWithDefault(envID, fullDataDays))
+	if err != nil {
+		return err
+	}
+
+	rs.RetentionPolicies = nil
+	rs.RetentionPolicies = append(rs.RetentionPolicies, policy)
+
+	return nil
+}
+
+// DeleteByEnvID deletes the retention policy of the env, the env will use the default full data days
+func (rs *RetentionService) DeleteByEnvID(envID int) error {
+	err := rs.GetByEnvID(envID)
+	if err != nil {
+		return err
+	}
+
+	return rs.RetentionRepo.Delete(rs.RetentionPolicies[constant.ZeroInt].Identity())
+}
+
+// DryRun gets the statistics of the data which would be archived by the archiver,
+// it does not change any data
+func (rs *RetentionService) DryRun() error {
+	var err error
+
+	rs.RetentionStats, err = rs.RetentionRepo.GetRetentionStats(viper.GetInt(config.HealthcheckRetentionFullDataDaysKey))
+
+	return err
+}
+
+// Marshal marshals RetentionService.RetentionPolicies to json bytes
+func (rs *RetentionService) Marshal() ([]byte, error) {
+	return rs.MarshalWithFields(retentionRetentionPoliciesStruct)
+}
+
+// MarshalWithFields marshals only specified fields of the RetentionService to json bytes
+func (rs *RetentionService) MarshalWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(rs, fields...)
+}
@@ -0,0 +1,93 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRetentionService *RetentionService
+
+func init() {
+	testInitDASMySQLPool()
+	testRetentionService = newRetentionService(NewRetentionRepoWithGlobal())
+}
+
+func TestRetentionService_All(t *testing.T) {
+	TestRetentionService_GetAll(t)
+	TestRetentionService_GetByEnvID(t)
+	TestRetentionService_Save(t)
+	TestRetentionService_DeleteByEnvID(t)
+	TestRetentionService_DryRun(t)
+	TestRetentionService_Marshal(t)
+}
+
+func TestRetentionService_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testRetentionService.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestRetentionService_GetByEnvID(t *testing.T) {
+	asst := assert.New(t)
+
+	policy, err := testCreateRetentionPolicy()
+	asst.Nil(err, common.CombineMessageWithError("test GetByEnvID() failed", err))
+	err = testRetentionService.GetByEnvID(testRetentionEnvID)
+	asst.Nil(err, common.CombineMessageWithError("test GetByEnvID() failed", err))
+	asst.Equal(testRetentionFullDataDays, testRetentionService.GetRetentionPolicies()[constant.ZeroInt].GetFullDataDays(), "test GetByEnvID() failed")
+	// delete
+	err = testDeleteRetentionPolicyByID(policy.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByEnvID() failed", err))
+}
+
+func TestRetentionService_Save(t *testing.T) {
+	asst := assert.New(t)
+
+	// create
+	err := testRetentionService.Save(testRetentionEnvID, testRetentionFullDataDays)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(testRetentionFullDataDays, testRetentionService.GetRetentionPolicies()[constant.ZeroInt].GetFullDataDays(), "test Save() failed")
+	// update
+	err = testRetentionService.Save(testRetentionEnvID, testRetentionUpdateFullDataDays)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(testRetentionUpdateFullDataDays, testRetentionService.GetRetentionPolicies()[constant.ZeroInt].GetFullDataDays(), "test Save() failed")
+	// delete
+	err = testDeleteRetentionPolicyByID(testRetentionService.GetRetentionPolicies()[constant.ZeroInt].Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	// invalid full data days
+	err = testRetentionService.Save(testRetentionEnvID, constant.ZeroInt)
+	asst.NotNil(err, "test Save() failed")
+}
+
+func TestRetentionService_DeleteByEnvID(t *testing.T) {
+	asst := assert.New(t)
+
+	_, err := testCreateRetentionPolicy()
+	asst.Nil(err, common.CombineMessageWithError("test DeleteByEnvID() failed", err))
+	err = testRetentionService.DeleteByEnvID(testRetentionEnvID)
+	asst.Nil(err, common.CombineMessageWithError("test DeleteByEnvID() failed", err))
+	err = testRetentionService.GetByEnvID(testRetentionEnvID)
+	asst.NotNil(err, "test DeleteByEnvID() failed")
+}
+
+func TestRetentionService_DryRun(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testRetentionService.DryRun()
+	asst.Nil(err, common.CombineMessageWithError("test DryRun() failed", err))
+}
+
+func TestRetentionService_Marshal(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testRetentionService.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	_, err = testRetentionService.Marshal()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	_, err = testRetentionService.MarshalWithFields(retentionRetentionStatsStruct)
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+}
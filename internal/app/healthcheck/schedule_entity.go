@@ -0,0 +1,188 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	scheduleScheduleNameStruct   = "ScheduleName"
+	scheduleCronExpressionStruct = "CronExpression"
+	scheduleTargetTypeStruct     = "TargetType"
+	scheduleTargetIDStruct       = "TargetID"
+	scheduleLookbackStruct       = "Lookback"
+	scheduleStepStruct           = "Step"
+	scheduleLoginNameStruct      = "LoginName"
+
+	ScheduleTargetTypeMySQLServer   = 1
+	ScheduleTargetTypeMySQLCluster  = 2
+	ScheduleTargetTypeResourceGroup = 3
+	ScheduleTargetTypeEnv           = 4
+
+	scheduleEnabled = 1
+)
+
+var _ healthcheck.Schedule = (*Schedule)(nil)
+
+// Schedule is the schedule of the recurring healthcheck
+type Schedule struct {
+	healthcheck.ScheduleRepo
+	ID             int       `middleware:"id" json:"id"`
+	ScheduleName   string    `middleware:"schedule_name" json:"schedule_name"`
+	CronExpression string    `middleware:"cron_expression" json:"cron_expression"`
+	TargetType     int       `middleware:"target_type" json:"target_type"`
+	TargetID       int       `middleware:"target_id" json:"target_id"`
+	Lookback       int       `middleware:"lookback" json:"lookback"`
+	Step           int       `middleware:"step" json:"step"`
+	LoginName      string    `middleware:"login_name" json:"login_name"`
+	Enabled        int       `middleware:"enabled" json:"enabled"`
+	DelFlag        int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime     time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime time.Time `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewSchedule returns a new *Schedule
+func NewSchedule(repo healthcheck.ScheduleRepo, id int, scheduleName, cronExpression string, targetType, targetID, lookback, step int,
+	loginName string, enabled, delFlag int, createTime, lastUpdateTime time.Time) *Schedule {
+	return &Schedule{
+		ScheduleRepo:   repo,
+		ID:             id,
+		ScheduleName:   scheduleName,
+		CronExpression: cronExpression,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Lookback:       lookback,
+		Step:           step,
+		LoginName:      loginName,
+		Enabled:        enabled,
+		DelFlag:        delFlag,
+		CreateTime:     createTime,
+		LastUpdateTime: lastUpdateTime,
+	}
+}
+
+// NewScheduleWithDefault returns a new *Schedule with default ScheduleRepo
+func NewScheduleWithDefault(scheduleName, cronExpression string, targetType, targetID, lookback, step int, loginName string) *Schedule {
+	return &Schedule{
+		ScheduleRepo:   NewScheduleRepoWithGlobal(),
+		ScheduleName:   scheduleName,
+		CronExpression: cronExpression,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Lookback:       lookback,
+		Step:           step,
+		LoginName:      loginName,
+		Enabled:        scheduleEnabled,
+	}
+}
+
+// NewEmptyScheduleWithGlobal returns a new *Schedule with global repository
+func NewEmptyScheduleWithGlobal() *Schedule {
+	return &Schedule{ScheduleRepo: NewScheduleRepoWithGlobal()}
+}
+
+// NewScheduleWithMapAndRandom returns a new *Schedule with given map
+func NewScheduleWithMapAndRandom(fields map[string]interface{}) (*Schedule, error) {
+	s := &Schedule{Enabled: scheduleEnabled}
+	err := common.SetValuesWithMapAndRandom(s, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Identity returns the identity
+func (s *Schedule) Identity() int {
+	return s.ID
+}
+
+// GetScheduleName returns the schedule name
+func (s *Schedule) GetScheduleName() string {
+	return s.ScheduleName
+}
+
+// GetCronExpression returns the cron expression
+func (s *Schedule) GetCronExpression() string {
+	return s.CronExpression
+}
+
+// GetTargetType returns the target type
+func (s *Schedule) GetTargetType() int {
+	return s.TargetType
+}
+
+// GetTargetID returns the target id
+func (s *Schedule) GetTargetID() int {
+	return s.TargetID
+}
+
+// GetLookback returns the lookback, the unit is seconds
+func (s *Schedule) GetLookback() int {
+	return s.Lookback
+}
+
+// GetStep returns the step, the unit is seconds
+func (s *Schedule) GetStep() int {
+	return s.Step
+}
+
+// GetLoginName returns the login name of the user who runs the healthcheck
+func (s *Schedule) GetLoginName() string {
+	return s.LoginName
+}
+
+// GetEnabled returns the enabled flag
+func (s *Schedule) GetEnabled() int {
+	return s.Enabled
+}
+
+// GetDelFlag returns the delete flag
+func (s *Schedule) GetDelFlag() int {
+	return s.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (s *Schedule) GetCreateTime() time.Time {
+	return s.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (s *Schedule) GetLastUpdateTime() time.Time {
+	return s.LastUpdateTime
+}
+
+// IsEnabled returns if the schedule is enabled
+func (s *Schedule) IsEnabled() bool {
+	return s.Enabled == scheduleEnabled
+}
+
+// Set sets entity with given fields, key is the field name and value is the relevant value of the key
+func (s *Schedule) Set(fields map[string]interface{}) error {
+	for fieldName, fieldValue := range fields {
+		err := common.SetValueOfStruct(s, fieldName, fieldValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete sets DelFlag to 1
+func (s *Schedule) Delete() {
+	s.DelFlag = 1
+}
+
+// MarshalJSON marshals Schedule to json string
+func (s *Schedule) MarshalJSON() ([]byte, error) {
+	return common.MarshalStructWithTag(s, constant.DefaultMarshalTag)
+}
+
+// MarshalJSONWithFields marshals only specified fields of Schedule to json string
+func (s *Schedule) MarshalJSONWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(s, fields...)
+}
@@ -0,0 +1,226 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware"
+	"github.com/romberli/log"
+)
+
+var _ healthcheck.ScheduleRepo = (*ScheduleRepo)(nil)
+
+// ScheduleRepo is the repository of the healthcheck schedule
+type ScheduleRepo struct {
+	Database middleware.Pool
+}
+
+// NewScheduleRepo returns healthcheck.ScheduleRepo with given middleware.Pool
+func NewScheduleRepo(db middleware.Pool) healthcheck.ScheduleRepo {
+	return newScheduleRepo(db)
+}
+
+// NewScheduleRepoWithGlobal returns healthcheck.ScheduleRepo with global mysql pool
+func NewScheduleRepoWithGlobal() healthcheck.ScheduleRepo {
+	return newScheduleRepo(global.DASMySQLPool)
+}
+
+// newScheduleRepo returns *ScheduleRepo with given middleware.Pool
+func newScheduleRepo(db middleware.Pool) *ScheduleRepo {
+	return &ScheduleRepo{Database: db}
+}
+
+// Execute executes given command and placeholders on the middleware
+func (sr *ScheduleRepo) Execute(command string, args ...interface{}) (middleware.Result, error) {
+	conn, err := sr.Database.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = conn.Close()
+		if err != nil {
+			log.Errorf("healthcheck ScheduleRepo.Execute(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	return conn.Execute(command, args...)
+}
+
+// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+func (sr *ScheduleRepo) Transaction() (middleware.Transaction, error) {
+	return sr.Database.Transaction()
+}
+
+// GetAll gets all schedules from the middleware
+func (sr *ScheduleRepo) GetAll() ([]healthcheck.Schedule, error) {
+	sql := `
+		select id, schedule_name, cron_expression, target_type, target_id, lookback, step, login_name,
+			   enabled, del_flag, create_time, last_update_time
+		from t_hc_schedule
+		where del_flag = 0
+		order by id;
+	`
+	log.Debugf("healthcheck ScheduleRepo.GetAll() sql: \n%s", sql)
+
+	return sr.getSchedules(sql)
+}
+
+// GetEnabled gets all enabled schedules from the middleware
+func (sr *ScheduleRepo) GetEnabled() ([]healthcheck.Schedule, error) {
+	sql := `
+		select id, schedule_name, cron_expression, target_type, target_id, lookback, step, login_name,
+			   enabled, del_flag, create_time, last_update_time
+		from t_hc_schedule
+		where del_flag = 0
+		  and enabled = 1
+		order by id;
+	`
+	log.Debugf("healthcheck ScheduleRepo.GetEnabled() sql: \n%s", sql)
+
+	return sr.getSchedules(sql)
+}
+
+// getSchedules gets the schedules with given sql
+func (sr *ScheduleRepo) getSchedules(sql string, args ...interface{}) ([]healthcheck.Schedule, error) {
+	result, err := sr.Execute(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	// init []healthcheck.Schedule
+	scheduleList := make([]healthcheck.Schedule, result.RowNumber())
+	for i := range scheduleList {
+		scheduleList[i] = NewEmptyScheduleWithGlobal()
+	}
+	// map to struct
+	err = result.MapToStructSlice(scheduleList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduleList, nil
+}
+
+// GetByID gets a schedule by the identity from the middleware
+func (sr *ScheduleRepo) GetByID(id int) (healthcheck.Schedule, error) {
+	sql := `
+		select id, schedule_name, cron_expression, target_type, target_id, lookback, step, login_name,
+			   enabled, del_flag, create_time, last_update_time
+		from t_hc_schedule
+		where del_flag = 0
+		  and id = ?;
+	`
+	log.Debugf("healthcheck ScheduleRepo.GetByID() sql: \n%s\nplaceholders: %d", sql, id)
+
+	result, err := sr.Execute(sql, id)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, errors.Errorf("healthcheck ScheduleRepo.GetByID(): data does not exists, id: %d", id)
+	case 1:
+		schedule := NewEmptyScheduleWithGlobal()
+		// map to struct
+		err = result.MapToStructByRowIndex(schedule, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+
+		return schedule, nil
+	default:
+		return nil, errors.Errorf("healthcheck ScheduleRepo.GetByID(): duplicate key exists, id: %d", id)
+	}
+}
+
+// GetID gets the identity with given schedule name from the middleware
+func (sr *ScheduleRepo) GetID(scheduleName string) (int, error) {
+	sql := `select id from t_hc_schedule where del_flag = 0 and schedule_name = ?;`
+	log.Debugf("healthcheck ScheduleRepo.GetID() select sql: \n%s\nplaceholders: %s", sql, scheduleName)
+
+	result, err := sr.Execute(sql, scheduleName)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return result.GetInt(constant.ZeroInt, constant.ZeroInt)
+}
+
+// Create creates a schedule in the middleware
+func (sr *ScheduleRepo) Create(schedule healthcheck.Schedule) (healthcheck.Schedule, error) {
+	sql := `
+		insert into t_hc_schedule(schedule_name, cron_expression, target_type, target_id, lookback, step, login_name, enabled)
+		values(?, ?, ?, ?, ?, ?, ?, ?);
+	`
+	log.Debugf("healthcheck ScheduleRepo.Create() insert sql: \n%s\nplaceholders: %s, %s, %d, %d, %d, %d, %s, %d",
+		sql, schedule.GetScheduleName(), schedule.GetCronExpression(), schedule.GetTargetType(), schedule.GetTargetID(),
+		schedule.GetLookback(), schedule.GetStep(), schedule.GetLoginName(), schedule.GetEnabled())
+	// execute
+	_, err := sr.Execute(sql, schedule.GetScheduleName(), schedule.GetCronExpression(), schedule.GetTargetType(), schedule.GetTargetID(),
+		schedule.GetLookback(), schedule.GetStep(), schedule.GetLoginName(), schedule.GetEnabled())
+	if err != nil {
+		return nil, err
+	}
+	// get id
+	id, err := sr.GetID(schedule.GetScheduleName())
+	if err != nil {
+		return nil, err
+	}
+	// get schedule
+	return sr.GetByID(id)
+}
+
+// Update updates the schedule in the middleware,
+// the delete time is set when the schedule is deleted, so that the schedule name could be used by a new schedule
+func (sr *ScheduleRepo) Update(schedule healthcheck.Schedule) error {
+	// del_time must be set before del_flag, as the assignments are evaluated from left to right
+	sql := `
+		update t_hc_schedule set schedule_name = ?, cron_expression = ?, target_type = ?, target_id = ?,
+		lookback = ?, step = ?, login_name = ?, enabled = ?,
+		del_time = case when ? = 0 then '1970-01-01 00:00:00.000000' when del_flag = 0 then current_timestamp(6) else del_time end,
+		del_flag = ?
+		where id = ?;
+	`
+	log.Debugf("healthcheck ScheduleRepo.Update() update sql: \n%s\nplaceholders: %s, %s, %d, %d, %d, %d, %s, %d, %d, %d, %d",
+		sql, schedule.GetScheduleName(), schedule.GetCronExpression(), schedule.GetTargetType(), schedule.GetTargetID(),
+		schedule.GetLookback(), schedule.GetStep(), schedule.GetLoginName(), schedule.GetEnabled(), schedule.GetDelFlag(),
+		schedule.GetDelFlag(), schedule.Identity())
+	_, err := sr.Execute(sql, schedule.GetScheduleName(), schedule.GetCronExpression(), schedule.GetTargetType(), schedule.GetTargetID(),
+		schedule.GetLookback(), schedule.GetStep(), schedule.GetLoginName(), schedule.GetEnabled(), schedule.GetDelFlag(),
+		schedule.GetDelFlag(), schedule.Identity())
+
+	return err
+}
+
+// Delete deletes the schedule in the middleware
+func (sr *ScheduleRepo) Delete(id int) error {
+	sql := `delete from t_hc_schedule where id = ?;`
+	log.Debugf("healthcheck ScheduleRepo.Delete() delete sql: \n%s\nplaceholders: %d", sql, id)
+	_, err := sr.Execute(sql, id)
+
+	return err
+}
+
+// Claim claims the trigger of the schedule at given time, it returns false if the trigger was claimed by another das instance,
+// so that the schedule is triggered only once even if the scheduler is enabled on multiple das instances
+func (sr *ScheduleRepo) Claim(id int, triggerTime time.Time) (bool, error) {
+	triggerTimeStr := triggerTime.Format(constant.TimeLayoutSecond)
+	// keep the last update time as it is, as claiming the trigger does not change the schedule
+	sql := `
+		update t_hc_schedule set last_trigger_time = ?, last_update_time = last_update_time
+		where id = ? and last_trigger_time < ?;
+	`
+	log.Debugf("healthcheck ScheduleRepo.Claim() update sql: \n%s\nplaceholders: %s, %d, %s", sql, triggerTimeStr, id, triggerTimeStr)
+	result, err := sr.Execute(sql, triggerTimeStr, id, triggerTimeStr)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
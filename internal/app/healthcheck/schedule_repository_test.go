@@ -0,0 +1,215 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testScheduleScheduleName         = "test_schedule_name"
+	testScheduleCronExpression       = "0 2 * * 1"
+	testScheduleUpdateCronExpression = "0 3 * * 1"
+	testScheduleLookback             = 7 * 24 * 3600
+	testScheduleStep                 = 60
+)
+
+var testScheduleRepo *ScheduleRepo
+
+func init() {
+	testInitDASMySQLPool()
+	testScheduleRepo = newScheduleRepo(global.DASMySQLPool)
+}
+
+func testCreateSchedule() (healthcheck.Schedule, error) {
+	schedule := NewScheduleWithDefault(
+		testScheduleScheduleName,
+		testScheduleCronExpression,
+		ScheduleTargetTypeMySQLServer,
+		testHealthcheckMySQLServerID,
+		testScheduleLookback,
+		testScheduleStep,
+		testHealthcheckLoginName,
+	)
+
+	return testScheduleRepo.Create(schedule)
+}
+
+func testDeleteScheduleByID(id int) error {
+	return testScheduleRepo.Delete(id)
+}
+
+func TestScheduleRepo_All(t *testing.T) {
+	TestScheduleRepo_Execute(t)
+	TestScheduleRepo_Transaction(t)
+	TestScheduleRepo_GetAll(t)
+	TestScheduleRepo_GetEnabled(t)
+	TestScheduleRepo_GetByID(t)
+	TestScheduleRepo_GetID(t)
+	TestScheduleRepo_Create(t)
+	TestScheduleRepo_Update(t)
+	TestScheduleRepo_Delete(t)
+	TestScheduleRepo_SoftDelete(t)
+	TestScheduleRepo_Claim(t)
+}
+
+func TestScheduleRepo_Execute(t *testing.T) {
+	asst := assert.New(t)
+
+	sql := `select 1;`
+	result, err := testScheduleRepo.Execute(sql)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	r, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	asst.Equal(1, r, "test Execute() failed")
+}
+
+func TestScheduleRepo_Transaction(t *testing.T) {
+	asst := assert.New(t)
+
+	tx, err := testScheduleRepo.Transaction()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Begin()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Rollback()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+}
+
+func TestScheduleRepo_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	schedules, err := testScheduleRepo.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	asst.NotZero(len(schedules), "test GetAll() failed")
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestScheduleRepo_GetEnabled(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test GetEnabled() failed", err))
+	schedules, err := testScheduleRepo.GetEnabled()
+	asst.Nil(err, common.CombineMessageWithError("test GetEnabled() failed", err))
+	for _, s := range schedules {
+		asst.True(s.IsEnabled(), "test GetEnabled() failed")
+	}
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetEnabled() failed", err))
+}
+
+func TestScheduleRepo_GetByID(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	entity, err := testScheduleRepo.GetByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	asst.Equal(testScheduleScheduleName, entity.GetScheduleName(), "test GetByID() failed")
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+}
+
+func TestScheduleRepo_GetID(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test GetID() failed", err))
+	id, err := testScheduleRepo.GetID(testScheduleScheduleName)
+	asst.Nil(err, common.CombineMessageWithError("test GetID() failed", err))
+	asst.Equal(schedule.Identity(), id, "test GetID() failed")
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetID() failed", err))
+}
+
+func TestScheduleRepo_Create(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	asst.Equal(testScheduleCronExpression, schedule.GetCronExpression(), "test Create() failed")
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+}
+
+func TestScheduleRepo_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = schedule.Set(map[string]interface{}{scheduleCronExpressionStruct: testScheduleUpdateCronExpression})
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testScheduleRepo.Update(schedule)
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	entity, err := testScheduleRepo.GetByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testScheduleUpdateCronExpression, entity.GetCronExpression(), "test Update() failed")
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestScheduleRepo_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	_, err = testScheduleRepo.GetByID(schedule.Identity())
+	asst.NotNil(err, "test Delete() failed")
+}
+
+func TestScheduleRepo_SoftDelete(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test SoftDelete() failed", err))
+	schedule.Delete()
+	err = testScheduleRepo.Update(schedule)
+	asst.Nil(err, common.CombineMessageWithError("test SoftDelete() failed", err))
+	// the schedule name could be used again after the schedule was deleted
+	newSchedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test SoftDelete() failed", err))
+	asst.NotEqual(schedule.Identity(), newSchedule.Identity(), "test SoftDelete() failed")
+	// the schedules with the same name could be deleted more than once
+	newSchedule.Delete()
+	err = testScheduleRepo.Update(newSchedule)
+	asst.Nil(err, common.CombineMessageWithError("test SoftDelete() failed", err))
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test SoftDelete() failed", err))
+	err = testDeleteScheduleByID(newSchedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test SoftDelete() failed", err))
+}
+
+func TestScheduleRepo_Claim(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test Claim() failed", err))
+	triggerTime := time.Now().Add(time.Minute).Truncate(time.Minute)
+	claimed, err := testScheduleRepo.Claim(schedule.Identity(), triggerTime)
+	asst.Nil(err, common.CombineMessageWithError("test Claim() failed", err))
+	asst.True(claimed, "test Claim() failed")
+	// the same trigger could be claimed only once
+	claimed, err = testScheduleRepo.Claim(schedule.Identity(), triggerTime)
+	asst.Nil(err, common.CombineMessageWithError("test Claim() failed", err))
+	asst.False(claimed, "test Claim() failed")
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Claim() failed", err))
+}
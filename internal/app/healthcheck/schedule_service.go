@@ -0,0 +1,170 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/viper"
+)
+
+const scheduleSchedulesStruct = "Schedules"
+
+var _ healthcheck.ScheduleService = (*ScheduleService)(nil)
+
+// ScheduleService is the service of the healthcheck schedule
+type ScheduleService struct {
+	healthcheck.ScheduleRepo
+	Schedules []healthcheck.Schedule `json:"schedules"`
+}
+
+// NewScheduleService returns a new healthcheck.ScheduleService
+func NewScheduleService(repo healthcheck.ScheduleRepo) healthcheck.ScheduleService {
+	return newScheduleService(repo)
+}
+
+// NewScheduleServiceWithDefault returns a new healthcheck.ScheduleService with default repository
+func NewScheduleServiceWithDefault() healthcheck.ScheduleService {
+	return newScheduleService(NewScheduleRepoWithGlobal())
+}
+
+// newScheduleService returns a new *ScheduleService
+func newScheduleService(repo healthcheck.ScheduleRepo) *ScheduleService {
+	return &ScheduleService{
+		ScheduleRepo: repo,
+		Schedules:    []healthcheck.Schedule{},
+	}
+}
+
+// GetSchedules returns the schedules of the service
+func (ss *ScheduleService) GetSchedules() []healthcheck.Schedule {
+	return ss.Schedules
+}
+
+// GetAll gets all schedules from the middleware
+func (ss *ScheduleService) GetAll() error {
+	var err error
+
+	ss.Schedules, err = ss.ScheduleRepo.GetAll()
+
+	return err
+}
+
+// GetByID gets a schedule of the given id from the middleware
+func (ss *ScheduleService) GetByID(id int) error {
+	schedule, err := ss.ScheduleRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	ss.Schedules = nil
+	ss.Schedules = append(ss.Schedules, schedule)
+
+	return nil
+}
+
+// Create creates a schedule in the middleware
+func (ss *ScheduleService) Create(fields map[string]interface{}) error {
+	// check fields
+	for _, field := range []string{
+		scheduleScheduleNameStruct,
+		scheduleCronExpressionStruct,
+		scheduleTargetTypeStruct,
+		scheduleTargetIDStruct,
+		scheduleLookbackStruct,
+		scheduleStepStruct,
+		scheduleLoginNameStruct,
+	} {
+		_, ok := fields[field]
+		if !ok {
+			return message.NewMessage(message.ErrFieldNotExists, field)
+		}
+	}
+	// create a new entity
+	scheduleInfo, err := NewScheduleWithMapAndRandom(fields)
+	if err != nil {
+		return err
+	}
+	err = ss.validate(scheduleInfo)
+	if err != nil {
+		return err
+	}
+	// insert into middleware
+	schedule, err := ss.ScheduleRepo.Create(scheduleInfo)
+	if err != nil {
+		return err
+	}
+
+	ss.Schedules = nil
+	ss.Schedules = append(ss.Schedules, schedule)
+
+	return nil
+}
+
+// Update gets a schedule of the given id from the middleware,
+// and then updates its fields that was specified in fields argument,
+// key is the filed name and value is the new field value,
+// it saves the changes to the middleware
+func (ss *ScheduleService) Update(id int, fields map[string]interface{}) error {
+	err := ss.GetByID(id)
+	if err != nil {
+		return err
+	}
+	err = ss.Schedules[constant.ZeroInt].Set(fields)
+	if err != nil {
+		return err
+	}
+	err = ss.validate(ss.Schedules[constant.ZeroInt])
+	if err != nil {
+		return err
+	}
+
+	return ss.ScheduleRepo.Update(ss.Schedules[constant.ZeroInt])
+}
+
+// Delete deletes the schedule of given id in the middleware
+func (ss *ScheduleService) Delete(id int) error {
+	err := ss.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	return ss.ScheduleRepo.Delete(id)
+}
+
+// validate validates if the schedule is valid
+func (ss *ScheduleService) validate(schedule healthcheck.Schedule) error {
+	_, err := ParseCronExpression(schedule.GetCronExpression())
+	if err != nil {
+		return err
+	}
+	if schedule.GetTargetType() < ScheduleTargetTypeMySQLServer || schedule.GetTargetType() > ScheduleTargetTypeEnv {
+		return errors.Errorf("target type must be one of [%d, %d, %d, %d], %d is not valid",
+			ScheduleTargetTypeMySQLServer, ScheduleTargetTypeMySQLCluster, ScheduleTargetTypeResourceGroup, ScheduleTargetTypeEnv,
+			schedule.GetTargetType())
+	}
+	if schedule.GetStep() <= constant.ZeroInt {
+		return errors.Errorf("step must be larger than 0, %d is not valid", schedule.GetStep())
+	}
+	lookback := time.Duration(schedule.GetLookback()) * time.Second
+	maxRange := viper.GetInt(config.HealthcheckMaxRangeKey)
+	if lookback <= 0 || lookback > constant.Day*time.Duration(maxRange) {
+		return errors.Errorf("lookback must be larger than 0 and not larger than %d days, %d is not valid", maxRange, schedule.GetLookback())
+	}
+
+	return nil
+}
+
+// Marshal marshals ScheduleService.Schedules to json bytes
+func (ss *ScheduleService) Marshal() ([]byte, error) {
+	return ss.MarshalWithFields(scheduleSchedulesStruct)
+}
+
+// MarshalWithFields marshals only specified fields of the ScheduleService to json bytes
+func (ss *ScheduleService) MarshalWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(ss, fields...)
+}
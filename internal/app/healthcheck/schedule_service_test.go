@@ -0,0 +1,116 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+var testScheduleService *ScheduleService
+
+func init() {
+	testInitDASMySQLPool()
+	testScheduleService = newScheduleService(NewScheduleRepoWithGlobal())
+}
+
+func testScheduleFields() map[string]interface{} {
+	return map[string]interface{}{
+		scheduleScheduleNameStruct:   testScheduleScheduleName,
+		scheduleCronExpressionStruct: testScheduleCronExpression,
+		scheduleTargetTypeStruct:     ScheduleTargetTypeMySQLServer,
+		scheduleTargetIDStruct:       testHealthcheckMySQLServerID,
+		scheduleLookbackStruct:       testScheduleLookback,
+		scheduleStepStruct:           testScheduleStep,
+		scheduleLoginNameStruct:      testHealthcheckLoginName,
+	}
+}
+
+func TestScheduleService_All(t *testing.T) {
+	TestScheduleService_GetAll(t)
+	TestScheduleService_GetByID(t)
+	TestScheduleService_Create(t)
+	TestScheduleService_Update(t)
+	TestScheduleService_Delete(t)
+	TestScheduleService_Marshal(t)
+}
+
+func TestScheduleService_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testScheduleService.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestScheduleService_GetByID(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	err = testScheduleService.GetByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	asst.Equal(testScheduleScheduleName, testScheduleService.GetSchedules()[constant.ZeroInt].GetScheduleName(), "test GetByID() failed")
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+}
+
+func TestScheduleService_Create(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testScheduleService.Create(testScheduleFields())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	// delete
+	err = testDeleteScheduleByID(testScheduleService.GetSchedules()[constant.ZeroInt].Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	// invalid cron expression
+	fields := testScheduleFields()
+	fields[scheduleCronExpressionStruct] = "* * *"
+	err = testScheduleService.Create(fields)
+	asst.NotNil(err, "test Create() failed")
+	// invalid target type
+	fields = testScheduleFields()
+	fields[scheduleTargetTypeStruct] = 5
+	err = testScheduleService.Create(fields)
+	asst.NotNil(err, "test Create() failed")
+}
+
+func TestScheduleService_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testScheduleService.Update(schedule.Identity(), map[string]interface{}{scheduleCronExpressionStruct: testScheduleUpdateCronExpression})
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testScheduleService.GetByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testScheduleUpdateCronExpression, testScheduleService.GetSchedules()[constant.ZeroInt].GetCronExpression(), "test Update() failed")
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestScheduleService_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testScheduleService.Delete(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+}
+
+func TestScheduleService_Marshal(t *testing.T) {
+	asst := assert.New(t)
+
+	schedule, err := testCreateSchedule()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	err = testScheduleService.GetByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	jsonBytes, err := testScheduleService.Marshal()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	t.Log(string(jsonBytes))
+	// delete
+	err = testDeleteScheduleByID(schedule.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+}
@@ -0,0 +1,206 @@
+package healthcheck
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/log"
+)
+
+const defaultSchedulerInterval = time.Minute
+
+var _ healthcheck.Scheduler = (*Scheduler)(nil)
+
+// Scheduler triggers the healthcheck of the enabled schedules periodically
+type Scheduler struct {
+	scheduleRepo healthcheck.ScheduleRepo
+	dasRepo      healthcheck.DASRepo
+	stopChan     chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewScheduler returns a new healthcheck.Scheduler
+func NewScheduler(scheduleRepo healthcheck.ScheduleRepo, dasRepo healthcheck.DASRepo) healthcheck.Scheduler {
+	return newScheduler(scheduleRepo, dasRepo)
+}
+
+// NewSchedulerWithDefault returns a new healthcheck.Scheduler with default repositories
+func NewSchedulerWithDefault() healthcheck.Scheduler {
+	return newScheduler(NewScheduleRepoWithGlobal(), NewDASRepoWithGlobal())
+}
+
+// newScheduler returns a new *Scheduler
+func newScheduler(scheduleRepo healthcheck.ScheduleRepo, dasRepo healthcheck.DASRepo) *Scheduler {
+	return &Scheduler{
+		scheduleRepo: scheduleRepo,
+		dasRepo:      dasRepo,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start starts the scheduler in the background
+func (s *Scheduler) Start() {
+	go s.run()
+}
+
+// Stop stops the scheduler
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+// run checks the schedules at the beginning of every minute
+func (s *Scheduler) run() {
+	// wait until the beginning of the next minute
+	now := time.Now()
+	timer := time.NewTimer(now.Truncate(defaultSchedulerInterval).Add(defaultSchedulerInterval).Sub(now))
+	select {
+	case <-timer.C:
+	case <-s.stopChan:
+		timer.Stop()
+		return
+	}
+
+	s.schedule(time.Now().Truncate(defaultSchedulerInterval))
+
+	ticker := time.NewTicker(defaultSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case t := <-ticker.C:
+			s.schedule(t.Truncate(defaultSchedulerInterval))
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// schedule triggers the schedules which match the given time
+func (s *Scheduler) schedule(t time.Time) {
+	schedules, err := s.scheduleRepo.GetEnabled()
+	if err != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckScheduleLoad, err))
+		return
+	}
+
+	for _, schedule := range schedules {
+		cronExpression, err := ParseCronExpression(schedule.GetCronExpression())
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckScheduleTrigger, err, schedule.Identity()))
+			continue
+		}
+		if !cronExpression.Match(t) {
+			continue
+		}
+		// the scheduler may be enabled on multiple das instances, only the one which claims the trigger runs the schedule
+		claimed, err := s.scheduleRepo.Claim(schedule.Identity(), t)
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckScheduleTrigger, err, schedule.Identity()))
+			continue
+		}
+		if !claimed {
+			log.Debugf("healthcheck schedule was triggered by another das instance, skip it. schedule id: %d", schedule.Identity())
+			continue
+		}
+
+		go func(schedule healthcheck.Schedule) {
+			err := s.trigger(schedule, t)
+			if err != nil {
+				log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckScheduleTrigger, err, schedule.Identity()))
+			}
+		}(schedule)
+	}
+}
+
+// trigger performs healthcheck on all mysql servers of the schedule target
+func (s *Scheduler) trigger(schedule healthcheck.Schedule, t time.Time) error {
+	mysqlServers, err := s.getMySQLServers(schedule)
+	if err != nil {
+		return err
+	}
+
+	startTime := t.Add(-time.Duration(schedule.GetLookback()) * time.Second)
+	step := time.Duration(schedule.GetStep()) * time.Second
+
+	for _, mysqlServer := range mysqlServers {
+		// skip the mysql server of which the healthcheck is still running
+		isRunning, err := s.dasRepo.IsRunning(mysqlServer.Identity())
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckScheduleCheck, err, schedule.Identity(), mysqlServer.Identity()))
+			continue
+		}
+		if isRunning {
+			log.Warnf("healthcheck of mysql server is still running, skip it. schedule id: %d, mysql server id: %d",
+				schedule.Identity(), mysqlServer.Identity())
+			continue
+		}
+
+		operationID, err := NewServiceWithDefault().CheckWithSchedule(
+			schedule.Identity(), mysqlServer.Identity(), startTime, t, step, schedule.GetLoginName())
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckScheduleCheck, err, schedule.Identity(), mysqlServer.Identity()))
+			continue
+		}
+		log.Info(message.NewMessage(msghc.InfoHealthcheckScheduleCheck, schedule.Identity(), mysqlServer.Identity(), operationID).Error())
+	}
+
+	return nil
+}
+
+// getMySQLServers gets the mysql servers of the schedule target
+func (s *Scheduler) getMySQLServers(schedule healthcheck.Schedule) ([]depmeta.MySQLServer, error) {
+	switch schedule.GetTargetType() {
+	case ScheduleTargetTypeMySQLServer:
+		mysqlServerService := metadata.NewMySQLServerServiceWithDefault()
+		err := mysqlServerService.GetByID(schedule.GetTargetID())
+		if err != nil {
+			return nil, err
+		}
+
+		return mysqlServerService.GetMySQLServers(), nil
+	case ScheduleTargetTypeMySQLCluster:
+		mysqlClusterService := metadata.NewMySQLClusterServiceWithDefault()
+		err := mysqlClusterService.GetMySQLServersByID(schedule.GetTargetID())
+		if err != nil {
+			return nil, err
+		}
+
+		return mysqlClusterService.GetMySQLServers(), nil
+	case ScheduleTargetTypeResourceGroup:
+		resourceGroupService := metadata.NewResourceGroupServiceWithDefault()
+		err := resourceGroupService.GetMySQLServersByID(schedule.GetTargetID())
+		if err != nil {
+			return nil, err
+		}
+
+		return resourceGroupService.GetMySQLServers(), nil
+	case ScheduleTargetTypeEnv:
+		mysqlClusterService := metadata.NewMySQLClusterServiceWithDefault()
+		err := mysqlClusterService.GetByEnv(schedule.GetTargetID())
+		if err != nil {
+			return nil, err
+		}
+
+		var mysqlServers []depmeta.MySQLServer
+		for _, mysqlCluster := range mysqlClusterService.GetMySQLClusters() {
+			servers, err := mysqlCluster.GetMySQLServers()
+			if err != nil {
+				return nil, err
+			}
+			mysqlServers = append(mysqlServers, servers...)
+		}
+
+		return mysqlServers, nil
+	default:
+		return nil, errors.Errorf("healthcheck Scheduler.getMySQLServers(): target type %d is not valid. schedule id: %d",
+			schedule.GetTargetType(), schedule.Identity())
+	}
+}
@@ -0,0 +1,351 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	defaultSchemaQualityItemName = "schema_quality"
+
+	innodbEngineName    = "InnoDB"
+	primaryKeyIndexName = "PRIMARY"
+	unsignedColumnType  = "unsigned"
+
+	// the value of a table is the largest value of its issues,
+	// the value of the auto increment issue is the usage ratio of the auto increment column
+	schemaIssueValueNoPrimaryKey   = 1.0
+	schemaIssueValueNonInnoDB      = 1.0
+	schemaIssueValueRedundantIndex = 0.6
+
+	autoIncrementSuggestedColumnType = "bigint unsigned"
+)
+
+var (
+	_ healthcheck.CheckItem = (*SchemaQualityItem)(nil)
+
+	// autoIncrementDataTypeBits is the bits of the integer data types which could be auto incremented
+	autoIncrementDataTypeBits = map[string]int{
+		"tinyint":   8,
+		"smallint":  16,
+		"mediumint": 24,
+		"int":       32,
+		"integer":   32,
+		"bigint":    64,
+	}
+)
+
+func init() {
+	RegisterCheckItem(NewSchemaQualityItem())
+}
+
+// SchemaQualityData is a table which has schema issues and the suggested ddls to fix them
+type SchemaQualityData struct {
+	DBName    string   `json:"db_name"`
+	TableName string   `json:"table_name"`
+	Value     float64  `json:"value"`
+	Issues    []string `json:"issues"`
+	DDLs      []string `json:"ddls"`
+}
+
+// NewSchemaQualityData returns a new *SchemaQualityData
+func NewSchemaQualityData(dbName, tableName string) *SchemaQualityData {
+	return &SchemaQualityData{
+		DBName:    dbName,
+		TableName: tableName,
+	}
+}
+
+// addIssue adds the issue and the suggested ddl to the data, the value of the data is the largest value of the issues
+func (sqd *SchemaQualityData) addIssue(value float64, issue, ddl string) {
+	if value > sqd.Value {
+		sqd.Value = value
+	}
+	sqd.Issues = append(sqd.Issues, issue)
+	if ddl != constant.EmptyString {
+		sqd.DDLs = append(sqd.DDLs, ddl)
+	}
+}
+
+// schemaIndex is an index of a table which is assembled from the index statistics
+type schemaIndex struct {
+	name     string
+	unique   bool
+	nullable bool
+	columns  []string
+}
+
+// isPrimaryKey returns if the index is the primary key
+func (si *schemaIndex) isPrimaryKey() bool {
+	return si.name == primaryKeyIndexName
+}
+
+// redundantIndex is an index which is duplicate with or is a prefix of another index
+type redundantIndex struct {
+	index     *schemaIndex
+	coveredBy *schemaIndex
+	duplicate bool
+}
+
+// SchemaQualityItem checks the tables without primary key, the non-innodb tables,
+// the duplicate and prefix-redundant indexes and the usage ratio of the auto increment columns,
+// the value of each table is the largest value of its issues
+type SchemaQualityItem struct{}
+
+// NewSchemaQualityItem returns a new *SchemaQualityItem
+func NewSchemaQualityItem() *SchemaQualityItem {
+	return &SchemaQualityItem{}
+}
+
+// GetName returns the item name
+func (sqi *SchemaQualityItem) GetName() string {
+	return defaultSchemaQualityItemName
+}
+
+// GetDataSource returns the data source of the item
+func (sqi *SchemaQualityItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the schema quality of the tables
+func (sqi *SchemaQualityItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	repo := env.GetApplicationMySQLRepo()
+	tableSchemas, err := repo.GetTableSchemas()
+	if err != nil {
+		return nil, err
+	}
+	indexStatistics, err := repo.GetIndexStatistics()
+	if err != nil {
+		return nil, err
+	}
+	autoIncrements, err := repo.GetAutoIncrements()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := env.GetItemConfig(sqi.GetName())
+	datas := getSchemaQualityDatas(cfg, tableSchemas, indexStatistics, autoIncrements)
+
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		highDatas []*SchemaQualityData
+		advices   []string
+	)
+	for _, data := range datas {
+		switch {
+		case data.Value >= cfg.GetHighWatermark():
+			highDatas = append(highDatas, data)
+			highSum += data.Value
+			highCount++
+		case data.Value >= cfg.GetLowWatermark():
+			mediumSum += data.Value
+			mediumCount++
+		default:
+			continue
+		}
+		advices = append(advices, fmt.Sprintf("%s.%s: %s, suggested ddl: %s",
+			data.DBName, data.TableName, strings.Join(data.Issues, constant.CommaString), strings.Join(data.DDLs, constant.SpaceString)))
+	}
+
+	jsonBytesTotal, err := json.Marshal(datas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highDatas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(cfg, highSum, highCount, mediumSum, mediumCount)
+
+	return NewItemResultWithEnv(env, sqi, score, string(jsonBytesTotal), string(jsonBytesHigh), strings.Join(advices, constant.CommaString)), nil
+}
+
+// getSchemaQualityDatas returns the tables which have schema issues in the order of the table schemas,
+// the auto increment columns of which the usage ratios are lower than the low watermark are not regarded as issues
+func getSchemaQualityDatas(cfg healthcheck.ItemConfig, tableSchemas []healthcheck.TableSchema,
+	indexStatistics []depmeta.IndexStatistic, autoIncrements []healthcheck.AutoIncrement) []*SchemaQualityData {
+	tableIndexes := getTableIndexes(indexStatistics)
+	tableAutoIncrements := make(map[string]healthcheck.AutoIncrement)
+	for _, autoIncrement := range autoIncrements {
+		tableAutoIncrements[getSchemaTableKey(autoIncrement.GetDBName(), autoIncrement.GetTableName())] = autoIncrement
+	}
+
+	var datas []*SchemaQualityData
+	for _, tableSchema := range tableSchemas {
+		dbName := tableSchema.GetDBName()
+		tableName := tableSchema.GetTableName()
+		key := getSchemaTableKey(dbName, tableName)
+		indexes := tableIndexes[key]
+		data := NewSchemaQualityData(dbName, tableName)
+
+		// primary key
+		if !tableSchema.HasPrimaryKey() {
+			data.addIssue(schemaIssueValueNoPrimaryKey, "table has no primary key", getAddPrimaryKeyDDL(dbName, tableName, indexes))
+		}
+		// engine
+		engine := tableSchema.GetEngine()
+		if engine != constant.EmptyString && !strings.EqualFold(engine, innodbEngineName) {
+			data.addIssue(schemaIssueValueNonInnoDB, fmt.Sprintf("table engine is %s", engine),
+				fmt.Sprintf("alter table `%s`.`%s` engine = %s;", dbName, tableName, innodbEngineName))
+		}
+		// redundant indexes
+		for _, ri := range getRedundantIndexes(indexes) {
+			issue := fmt.Sprintf("index %s(%s) is a prefix of index %s(%s)",
+				ri.index.name, strings.Join(ri.index.columns, constant.CommaString), ri.coveredBy.name, strings.Join(ri.coveredBy.columns, constant.CommaString))
+			if ri.duplicate {
+				issue = fmt.Sprintf("index %s(%s) is duplicate with index %s",
+					ri.index.name, strings.Join(ri.index.columns, constant.CommaString), ri.coveredBy.name)
+			}
+			data.addIssue(schemaIssueValueRedundantIndex, issue, fmt.Sprintf("alter table `%s`.`%s` drop index `%s`;", dbName, tableName, ri.index.name))
+		}
+		// auto increment
+		autoIncrement, ok := tableAutoIncrements[key]
+		if ok {
+			ratio, ok := getAutoIncrementUsageRatio(autoIncrement)
+			if ok && ratio >= cfg.GetLowWatermark() {
+				data.addIssue(ratio, fmt.Sprintf("auto increment column %s(%s) has used %.2f%% of the max value",
+					autoIncrement.GetColumnName(), autoIncrement.GetColumnType(), ratio*100), getModifyAutoIncrementDDL(autoIncrement))
+			}
+		}
+
+		if len(data.Issues) > constant.ZeroInt {
+			datas = append(datas, data)
+		}
+	}
+
+	return datas
+}
+
+// getTableIndexes assembles the indexes from the index statistics, the indexes are keyed by the db name and the table name
+func getTableIndexes(indexStatistics []depmeta.IndexStatistic) map[string][]*schemaIndex {
+	tableIndexes := make(map[string][]*schemaIndex)
+	indexMap := make(map[string]*schemaIndex)
+	for _, is := range indexStatistics {
+		tableKey := getSchemaTableKey(is.GetDBName(), is.GetTableName())
+		indexKey := fmt.Sprintf("%s.%s", tableKey, is.GetIndexName())
+		index, ok := indexMap[indexKey]
+		if !ok {
+			index = &schemaIndex{name: is.GetIndexName(), unique: is.IsUnique()}
+			indexMap[indexKey] = index
+			tableIndexes[tableKey] = append(tableIndexes[tableKey], index)
+		}
+		index.columns = append(index.columns, is.GetColumnName())
+		if is.IsNullable() {
+			index.nullable = true
+		}
+	}
+
+	return tableIndexes
+}
+
+// getRedundantIndexes returns the indexes of a table which could be dropped,
+// an index is redundant if another index has the same columns, or it is not unique and its columns are a prefix of another index,
+// of the duplicate indexes, the primary key and the unique one are kept, otherwise, the first one is kept
+func getRedundantIndexes(indexes []*schemaIndex) []*redundantIndex {
+	var redundantIndexes []*redundantIndex
+	for i, index := range indexes {
+		if index.isPrimaryKey() {
+			continue
+		}
+		for j, other := range indexes {
+			if i == j {
+				continue
+			}
+			if isColumnsPrefix(index.columns, other.columns) && len(index.columns) == len(other.columns) {
+				if other.isPrimaryKey() || (other.unique && !index.unique) || (other.unique == index.unique && j < i) {
+					redundantIndexes = append(redundantIndexes, &redundantIndex{index: index, coveredBy: other, duplicate: true})
+					break
+				}
+				continue
+			}
+			if !index.unique && isColumnsPrefix(index.columns, other.columns) {
+				redundantIndexes = append(redundantIndexes, &redundantIndex{index: index, coveredBy: other})
+				break
+			}
+		}
+	}
+
+	return redundantIndexes
+}
+
+// isColumnsPrefix returns if the columns are the leftmost prefix of the other columns
+func isColumnsPrefix(columns, others []string) bool {
+	if len(columns) > len(others) {
+		return false
+	}
+	for i, column := range columns {
+		if !strings.EqualFold(column, others[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getAutoIncrementMaxValue returns the max value of the auto increment column,
+// it returns false if the data type is not an integer type
+func getAutoIncrementMaxValue(dataType, columnType string) (float64, bool) {
+	bits, ok := autoIncrementDataTypeBits[strings.ToLower(dataType)]
+	if !ok {
+		return 0, false
+	}
+	if strings.Contains(strings.ToLower(columnType), unsignedColumnType) {
+		return math.Pow(2, float64(bits)) - 1, true
+	}
+
+	return math.Pow(2, float64(bits-1)) - 1, true
+}
+
+// getAutoIncrementUsageRatio returns the ratio of the next auto increment value to the max value of the column,
+// it returns false if the max value of the column is unknown
+func getAutoIncrementUsageRatio(autoIncrement healthcheck.AutoIncrement) (float64, bool) {
+	maxValue, ok := getAutoIncrementMaxValue(autoIncrement.GetDataType(), autoIncrement.GetColumnType())
+	if !ok {
+		return 0, false
+	}
+
+	return autoIncrement.GetAutoIncrement() / maxValue, true
+}
+
+// getAddPrimaryKeyDDL returns the ddl which adds the primary key to the table,
+// the first unique index of which the columns are not nullable is promoted to the primary key if it exists,
+// otherwise, an auto increment column is added as the primary key
+func getAddPrimaryKeyDDL(dbName, tableName string, indexes []*schemaIndex) string {
+	for _, index := range indexes {
+		if index.unique && !index.nullable {
+			return fmt.Sprintf("alter table `%s`.`%s` drop index `%s`, add primary key (`%s`);",
+				dbName, tableName, index.name, strings.Join(index.columns, "`, `"))
+		}
+	}
+
+	return fmt.Sprintf("alter table `%s`.`%s` add column `id` %s not null auto_increment primary key first;", dbName, tableName, autoIncrementSuggestedColumnType)
+}
+
+// getModifyAutoIncrementDDL returns the ddl which enlarges the auto increment column,
+// it returns an empty string if the column is already bigint unsigned, the data should be archived in this case
+func getModifyAutoIncrementDDL(autoIncrement healthcheck.AutoIncrement) string {
+	if strings.HasPrefix(strings.ToLower(autoIncrement.GetColumnType()), "bigint") &&
+		strings.Contains(strings.ToLower(autoIncrement.GetColumnType()), unsignedColumnType) {
+		return constant.EmptyString
+	}
+
+	return fmt.Sprintf("alter table `%s`.`%s` modify column `%s` %s not null auto_increment;",
+		autoIncrement.GetDBName(), autoIncrement.GetTableName(), autoIncrement.GetColumnName(), autoIncrementSuggestedColumnType)
+}
+
+// getSchemaTableKey returns the key of the table which is used to match the table schemas, the indexes and the auto increments
+func getSchemaTableKey(dbName, tableName string) string {
+	return fmt.Sprintf("%s.%s", dbName, tableName)
+}
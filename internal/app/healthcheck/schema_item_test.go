@@ -0,0 +1,122 @@
+package healthcheck
+
+import (
+	"math"
+	"testing"
+
+	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSchemaDBName    = "das"
+	testSchemaTableName = "t01"
+)
+
+func TestSchemaItem_All(t *testing.T) {
+	TestSchemaItem_getRedundantIndexes(t)
+	TestSchemaItem_getAutoIncrementMaxValue(t)
+	TestSchemaItem_getAddPrimaryKeyDDL(t)
+	TestSchemaItem_getSchemaQualityDatas(t)
+}
+
+func testNewIndexStatistics(indexName string, unique, nullable bool, columns ...string) []depmeta.IndexStatistic {
+	indexStatistics := make([]depmeta.IndexStatistic, len(columns))
+	for i, column := range columns {
+		indexStatistics[i] = &metadata.IndexStatistic{
+			DBName:     testSchemaDBName,
+			TableName:  testSchemaTableName,
+			IndexName:  indexName,
+			Sequence:   i + 1,
+			ColumnName: column,
+			Unique:     unique,
+			Nullable:   nullable,
+		}
+	}
+
+	return indexStatistics
+}
+
+func TestSchemaItem_getRedundantIndexes(t *testing.T) {
+	asst := assert.New(t)
+
+	var indexStatistics []depmeta.IndexStatistic
+	indexStatistics = append(indexStatistics, testNewIndexStatistics(primaryKeyIndexName, true, false, "id")...)
+	indexStatistics = append(indexStatistics, testNewIndexStatistics("idx01_id", false, false, "id")...)
+	indexStatistics = append(indexStatistics, testNewIndexStatistics("idx02_a_b", false, true, "a", "b")...)
+	indexStatistics = append(indexStatistics, testNewIndexStatistics("idx03_a", false, true, "a")...)
+	indexStatistics = append(indexStatistics, testNewIndexStatistics("uk01_a", true, true, "a")...)
+	indexStatistics = append(indexStatistics, testNewIndexStatistics("idx04_c", false, true, "c")...)
+	indexStatistics = append(indexStatistics, testNewIndexStatistics("idx05_c", false, true, "c")...)
+
+	indexes := getTableIndexes(indexStatistics)[getSchemaTableKey(testSchemaDBName, testSchemaTableName)]
+	redundantIndexes := getRedundantIndexes(indexes)
+	asst.Equal(3, len(redundantIndexes), "test getRedundantIndexes() failed")
+	// duplicate with the primary key
+	asst.Equal("idx01_id", redundantIndexes[0].index.name, "test getRedundantIndexes() failed")
+	asst.True(redundantIndexes[0].duplicate, "test getRedundantIndexes() failed")
+	// prefix of another index, the unique index uk01_a is kept
+	asst.Equal("idx03_a", redundantIndexes[1].index.name, "test getRedundantIndexes() failed")
+	asst.Equal("idx02_a_b", redundantIndexes[1].coveredBy.name, "test getRedundantIndexes() failed")
+	// the first one of the duplicate indexes is kept
+	asst.Equal("idx05_c", redundantIndexes[2].index.name, "test getRedundantIndexes() failed")
+	asst.Equal("idx04_c", redundantIndexes[2].coveredBy.name, "test getRedundantIndexes() failed")
+}
+
+func TestSchemaItem_getAutoIncrementMaxValue(t *testing.T) {
+	asst := assert.New(t)
+
+	maxValue, ok := getAutoIncrementMaxValue("int", "int(11)")
+	asst.True(ok, "test getAutoIncrementMaxValue() failed")
+	asst.Equal(float64(math.MaxInt32), maxValue, "test getAutoIncrementMaxValue() failed")
+	maxValue, ok = getAutoIncrementMaxValue("INT", "int(10) unsigned")
+	asst.True(ok, "test getAutoIncrementMaxValue() failed")
+	asst.Equal(float64(math.MaxUint32), maxValue, "test getAutoIncrementMaxValue() failed")
+	maxValue, ok = getAutoIncrementMaxValue("tinyint", "tinyint(4)")
+	asst.True(ok, "test getAutoIncrementMaxValue() failed")
+	asst.Equal(float64(math.MaxInt8), maxValue, "test getAutoIncrementMaxValue() failed")
+	_, ok = getAutoIncrementMaxValue("double", "double")
+	asst.False(ok, "test getAutoIncrementMaxValue() failed")
+}
+
+func TestSchemaItem_getAddPrimaryKeyDDL(t *testing.T) {
+	asst := assert.New(t)
+
+	// the unique index is not nullable, it is promoted to the primary key
+	indexes := getTableIndexes(testNewIndexStatistics("uk01_a_b", true, false, "a", "b"))[getSchemaTableKey(testSchemaDBName, testSchemaTableName)]
+	asst.Equal("alter table `das`.`t01` drop index `uk01_a_b`, add primary key (`a`, `b`);",
+		getAddPrimaryKeyDDL(testSchemaDBName, testSchemaTableName, indexes), "test getAddPrimaryKeyDDL() failed")
+	// the unique index is nullable
+	indexes = getTableIndexes(testNewIndexStatistics("uk01_a_b", true, true, "a", "b"))[getSchemaTableKey(testSchemaDBName, testSchemaTableName)]
+	asst.Equal("alter table `das`.`t01` add column `id` bigint unsigned not null auto_increment primary key first;",
+		getAddPrimaryKeyDDL(testSchemaDBName, testSchemaTableName, indexes), "test getAddPrimaryKeyDDL() failed")
+}
+
+func TestSchemaItem_getSchemaQualityDatas(t *testing.T) {
+	asst := assert.New(t)
+
+	cfg := NewDefaultItemConfig(defaultSchemaQualityItemName, 4, 0.5, 0.8, 0.1, 20, 100, 10, 50)
+	tableSchemas := []healthcheck.TableSchema{
+		&TableSchema{DBName: testSchemaDBName, TableName: testSchemaTableName, Engine: "MyISAM", PrimaryKey: false},
+		&TableSchema{DBName: testSchemaDBName, TableName: "t02", Engine: innodbEngineName, PrimaryKey: true},
+		&TableSchema{DBName: testSchemaDBName, TableName: "t03", Engine: innodbEngineName, PrimaryKey: true},
+	}
+	autoIncrements := []healthcheck.AutoIncrement{
+		&AutoIncrement{DBName: testSchemaDBName, TableName: "t02", ColumnName: "id", DataType: "int", ColumnType: "int(11)", AutoIncrementValue: math.MaxInt32 * 0.9},
+		&AutoIncrement{DBName: testSchemaDBName, TableName: "t03", ColumnName: "id", DataType: "int", ColumnType: "int(11)", AutoIncrementValue: 100},
+	}
+
+	datas := getSchemaQualityDatas(cfg, tableSchemas, nil, autoIncrements)
+	asst.Equal(2, len(datas), "test getSchemaQualityDatas() failed")
+	// no primary key and non-innodb
+	asst.Equal(testSchemaTableName, datas[constant.ZeroInt].TableName, "test getSchemaQualityDatas() failed")
+	asst.Equal(schemaIssueValueNoPrimaryKey, datas[constant.ZeroInt].Value, "test getSchemaQualityDatas() failed")
+	asst.Equal(2, len(datas[constant.ZeroInt].DDLs), "test getSchemaQualityDatas() failed")
+	// auto increment usage, t03 is not listed as the usage ratio is lower than the low watermark
+	asst.Equal("t02", datas[1].TableName, "test getSchemaQualityDatas() failed")
+	asst.InDelta(0.9, datas[1].Value, 0.001, "test getSchemaQualityDatas() failed")
+	asst.Equal("alter table `das`.`t02` modify column `id` bigint unsigned not null auto_increment;", datas[1].DDLs[constant.ZeroInt], "test getSchemaQualityDatas() failed")
+}
@@ -0,0 +1,189 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	// defaultScoringProfileID is the id of the global default item configs,
+	// it is used when no scoring profile was assigned to the mysql cluster, the resource groups or the env
+	defaultScoringProfileID = 0
+
+	ScoringProfileTargetTypeMySQLCluster  = 2
+	ScoringProfileTargetTypeResourceGroup = 3
+	ScoringProfileTargetTypeEnv           = 4
+
+	// the descriptions of the config versions which are saved when creating or updating the scoring profile
+	scoringProfileCreateDescription = "scoring profile created"
+	scoringProfileUpdateDescription = "scoring profile updated"
+)
+
+var (
+	_ healthcheck.ScoringProfile           = (*ScoringProfile)(nil)
+	_ healthcheck.ScoringProfileAssignment = (*ScoringProfileAssignment)(nil)
+)
+
+// ScoringProfile is a named full item config set which could be assigned to envs, mysql clusters or resource groups
+type ScoringProfile struct {
+	ID             int                         `middleware:"id" json:"id"`
+	ProfileName    string                      `middleware:"profile_name" json:"profile_name"`
+	Description    string                      `middleware:"description" json:"description"`
+	ItemConfigs    []*DefaultItemConfig        `json:"item_configs"`
+	Assignments    []*ScoringProfileAssignment `json:"assignments"`
+	DelFlag        int                         `middleware:"del_flag" json:"del_flag"`
+	CreateTime     time.Time                   `middleware:"create_time" json:"create_time"`
+	LastUpdateTime time.Time                   `middleware:"last_update_time" json:"last_update_time"`
+}
+
+// NewScoringProfile returns a new *ScoringProfile
+func NewScoringProfile(profileName, description string, itemConfigs []healthcheck.ItemConfig) *ScoringProfile {
+	sp := &ScoringProfile{
+		ProfileName: profileName,
+		Description: description,
+		ItemConfigs: make([]*DefaultItemConfig, len(itemConfigs)),
+		Assignments: []*ScoringProfileAssignment{},
+	}
+	for i, itemConfig := range itemConfigs {
+		sp.ItemConfigs[i] = newDefaultItemConfig(
+			itemConfig.GetItemName(),
+			itemConfig.GetItemWeight(),
+			itemConfig.GetLowWatermark(),
+			itemConfig.GetHighWatermark(),
+			itemConfig.GetUnit(),
+			itemConfig.GetScoreDeductionPerUnitHigh(),
+			itemConfig.GetMaxScoreDeductionHigh(),
+			itemConfig.GetScoreDeductionPerUnitMedium(),
+			itemConfig.GetMaxScoreDeductionMedium(),
+		)
+	}
+
+	return sp
+}
+
+// NewDefaultItemConfigsWithJSON unmarshals the json array to the item configs
+func NewDefaultItemConfigsWithJSON(data []byte) ([]healthcheck.ItemConfig, error) {
+	var defaultItemConfigs []*DefaultItemConfig
+	err := json.Unmarshal(data, &defaultItemConfigs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	itemConfigs := make([]healthcheck.ItemConfig, len(defaultItemConfigs))
+	for i, itemConfig := range defaultItemConfigs {
+		itemConfigs[i] = itemConfig
+	}
+
+	return itemConfigs, nil
+}
+
+// NewEmptyScoringProfile returns a new *ScoringProfile with empty value
+func NewEmptyScoringProfile() *ScoringProfile {
+	return &ScoringProfile{
+		ItemConfigs: []*DefaultItemConfig{},
+		Assignments: []*ScoringProfileAssignment{},
+	}
+}
+
+// Identity returns the identity
+func (sp *ScoringProfile) Identity() int {
+	return sp.ID
+}
+
+// GetProfileName returns the profile name
+func (sp *ScoringProfile) GetProfileName() string {
+	return sp.ProfileName
+}
+
+// GetDescription returns the description
+func (sp *ScoringProfile) GetDescription() string {
+	return sp.Description
+}
+
+// GetItemConfigs returns the item configs of the profile
+func (sp *ScoringProfile) GetItemConfigs() []healthcheck.ItemConfig {
+	itemConfigs := make([]healthcheck.ItemConfig, len(sp.ItemConfigs))
+	for i, itemConfig := range sp.ItemConfigs {
+		itemConfigs[i] = itemConfig
+	}
+
+	return itemConfigs
+}
+
+// GetAssignments returns the assignments of the profile
+func (sp *ScoringProfile) GetAssignments() []healthcheck.ScoringProfileAssignment {
+	assignments := make([]healthcheck.ScoringProfileAssignment, len(sp.Assignments))
+	for i, assignment := range sp.Assignments {
+		assignments[i] = assignment
+	}
+
+	return assignments
+}
+
+// GetDelFlag returns the delete flag
+func (sp *ScoringProfile) GetDelFlag() int {
+	return sp.DelFlag
+}
+
+// GetCreateTime returns the create time
+func (sp *ScoringProfile) GetCreateTime() time.Time {
+	return sp.CreateTime
+}
+
+// GetLastUpdateTime returns the last update time
+func (sp *ScoringProfile) GetLastUpdateTime() time.Time {
+	return sp.LastUpdateTime
+}
+
+// GetEngineConfig returns the engine config which consists of the item configs of the profile
+func (sp *ScoringProfile) GetEngineConfig() healthcheck.EngineConfig {
+	engineConfig := NewEmptyDefaultEngineConfig()
+	for _, itemConfig := range sp.ItemConfigs {
+		engineConfig.SetItemConfig(itemConfig.GetItemName(), itemConfig)
+	}
+
+	return engineConfig
+}
+
+// MarshalJSON marshals ScoringProfile to json string
+func (sp *ScoringProfile) MarshalJSON() ([]byte, error) {
+	return common.MarshalStructWithTag(sp, constant.DefaultMarshalTag)
+}
+
+// ScoringProfileAssignment is the assignment of the scoring profile to an env, a mysql cluster or a resource group
+type ScoringProfileAssignment struct {
+	ID         int `middleware:"id" json:"id"`
+	ProfileID  int `middleware:"profile_id" json:"profile_id"`
+	TargetType int `middleware:"target_type" json:"target_type"`
+	TargetID   int `middleware:"target_id" json:"target_id"`
+}
+
+// NewEmptyScoringProfileAssignment returns a new *ScoringProfileAssignment with empty value
+func NewEmptyScoringProfileAssignment() *ScoringProfileAssignment {
+	return &ScoringProfileAssignment{}
+}
+
+// Identity returns the identity
+func (spa *ScoringProfileAssignment) Identity() int {
+	return spa.ID
+}
+
+// GetProfileID returns the profile id
+func (spa *ScoringProfileAssignment) GetProfileID() int {
+	return spa.ProfileID
+}
+
+// GetTargetType returns the target type
+func (spa *ScoringProfileAssignment) GetTargetType() int {
+	return spa.TargetType
+}
+
+// GetTargetID returns the target id
+func (spa *ScoringProfileAssignment) GetTargetID() int {
+	return spa.TargetID
+}
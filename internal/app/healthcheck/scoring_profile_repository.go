@@ -0,0 +1,342 @@
+package healthcheck
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware"
+	"github.com/romberli/log"
+)
+
+var _ healthcheck.ScoringProfileRepo = (*ScoringProfileRepo)(nil)
+
+// ScoringProfileRepo is the repository of the healthcheck scoring profile
+type ScoringProfileRepo struct {
+	Database middleware.Pool
+}
+
+// NewScoringProfileRepo returns healthcheck.ScoringProfileRepo with given middleware.Pool
+func NewScoringProfileRepo(db middleware.Pool) healthcheck.ScoringProfileRepo {
+	return newScoringProfileRepo(db)
+}
+
+// NewScoringProfileRepoWithGlobal returns healthcheck.ScoringProfileRepo with global mysql pool
+func NewScoringProfileRepoWithGlobal() healthcheck.ScoringProfileRepo {
+	return newScoringProfileRepo(global.DASMySQLPool)
+}
+
+// newScoringProfileRepo returns *ScoringProfileRepo with given middleware.Pool
+func newScoringProfileRepo(db middleware.Pool) *ScoringProfileRepo {
+	return &ScoringProfileRepo{Database: db}
+}
+
+// Execute executes given command and placeholders on the middleware
+func (spr *ScoringProfileRepo) Execute(command string, args ...interface{}) (middleware.Result, error) {
+	conn, err := spr.Database.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = conn.Close()
+		if err != nil {
+			log.Errorf("healthcheck ScoringProfileRepo.Execute(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	return conn.Execute(command, args...)
+}
+
+// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+func (spr *ScoringProfileRepo) Transaction() (middleware.Transaction, error) {
+	return spr.Database.Transaction()
+}
+
+// GetAll gets all scoring profiles from the middleware, the item configs and the assignments are not included
+func (spr *ScoringProfileRepo) GetAll() ([]healthcheck.ScoringProfile, error) {
+	sql := `
+		select id, profile_name, description, del_flag, create_time, last_update_time
+		from t_hc_scoring_profile
+		where del_flag = 0
+		order by id;
+	`
+	log.Debugf("healthcheck ScoringProfileRepo.GetAll() sql: \n%s", sql)
+
+	result, err := spr.Execute(sql)
+	if err != nil {
+		return nil, err
+	}
+	// init []healthcheck.ScoringProfile
+	profileList := make([]healthcheck.ScoringProfile, result.RowNumber())
+	for i := range profileList {
+		profileList[i] = NewEmptyScoringProfile()
+	}
+	// map to struct
+	err = result.MapToStructSlice(profileList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return profileList, nil
+}
+
+// GetByID gets a scoring profile with its item configs and assignments by the identity from the middleware
+func (spr *ScoringProfileRepo) GetByID(id int) (healthcheck.ScoringProfile, error) {
+	sql := `
+		select id, profile_name, description, del_flag, create_time, last_update_time
+		from t_hc_scoring_profile
+		where del_flag = 0
+		  and id = ?;
+	`
+	log.Debugf("healthcheck ScoringProfileRepo.GetByID() sql: \n%s\nplaceholders: %d", sql, id)
+
+	result, err := spr.Execute(sql, id)
+	if err != nil {
+		return nil, err
+	}
+	switch result.RowNumber() {
+	case 0:
+		return nil, errors.Errorf("healthcheck ScoringProfileRepo.GetByID(): data does not exists, id: %d", id)
+	case 1:
+		profile := NewEmptyScoringProfile()
+		// map to struct
+		err = result.MapToStructByRowIndex(profile, constant.ZeroInt, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return nil, err
+		}
+		profile.ItemConfigs, err = spr.getItemConfigs(id)
+		if err != nil {
+			return nil, err
+		}
+		profile.Assignments, err = spr.getAssignments(id)
+		if err != nil {
+			return nil, err
+		}
+
+		return profile, nil
+	default:
+		return nil, errors.Errorf("healthcheck ScoringProfileRepo.GetByID(): duplicate key exists, id: %d", id)
+	}
+}
+
+// getItemConfigs gets the item configs of the scoring profile from the middleware
+func (spr *ScoringProfileRepo) getItemConfigs(profileID int) ([]*DefaultItemConfig, error) {
+	sql := `
+		select id, item_name, item_weight, low_watermark, high_watermark, unit, score_deduction_per_unit_high, max_score_deduction_high,
+		score_deduction_per_unit_medium, max_score_deduction_medium, del_flag, create_time, last_update_time
+		from t_hc_default_engine_config
+		where del_flag = 0
+		  and profile_id = ?
+		order by id;
+	`
+	log.Debugf("healthcheck ScoringProfileRepo.getItemConfigs() sql: \n%s\nplaceholders: %d", sql, profileID)
+
+	result, err := spr.Execute(sql, profileID)
+	if err != nil {
+		return nil, err
+	}
+	itemConfigList := make([]*DefaultItemConfig, result.RowNumber())
+	for i := range itemConfigList {
+		itemConfigList[i] = &DefaultItemConfig{}
+	}
+	err = result.MapToStructSlice(itemConfigList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemConfigList, nil
+}
+
+// getAssignments gets the assignments of the scoring profile from the middleware
+func (spr *ScoringProfileRepo) getAssignments(profileID int) ([]*ScoringProfileAssignment, error) {
+	sql := `
+		select id, profile_id, target_type, target_id
+		from t_hc_scoring_profile_assignment
+		where del_flag = 0
+		  and profile_id = ?
+		order by target_type, target_id;
+	`
+	log.Debugf("healthcheck ScoringProfileRepo.getAssignments() sql: \n%s\nplaceholders: %d", sql, profileID)
+
+	result, err := spr.Execute(sql, profileID)
+	if err != nil {
+		return nil, err
+	}
+	assignmentList := make([]*ScoringProfileAssignment, result.RowNumber())
+	for i := range assignmentList {
+		assignmentList[i] = NewEmptyScoringProfileAssignment()
+	}
+	err = result.MapToStructSlice(assignmentList, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return assignmentList, nil
+}
+
+// GetID gets the identity with given profile name from the middleware
+func (spr *ScoringProfileRepo) GetID(profileName string) (int, error) {
+	sql := `select id from t_hc_scoring_profile where del_flag = 0 and profile_name = ?;`
+	log.Debugf("healthcheck ScoringProfileRepo.GetID() select sql: \n%s\nplaceholders: %s", sql, profileName)
+
+	result, err := spr.Execute(sql, profileName)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+
+	return result.GetInt(constant.ZeroInt, constant.ZeroInt)
+}
+
+// Create creates a scoring profile with its item configs in the middleware as a transaction
+func (spr *ScoringProfileRepo) Create(profile healthcheck.ScoringProfile) (healthcheck.ScoringProfile, error) {
+	tx, err := spr.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = tx.Close()
+		if err != nil {
+			log.Errorf("healthcheck ScoringProfileRepo.Create(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	err = tx.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	sql := `insert into t_hc_scoring_profile(profile_name, description) values(?, ?);`
+	log.Debugf("healthcheck ScoringProfileRepo.Create() insert sql: \n%s\nplaceholders: %s, %s",
+		sql, profile.GetProfileName(), profile.GetDescription())
+	result, err := tx.Execute(sql, profile.GetProfileName(), profile.GetDescription())
+	if err != nil {
+		return nil, spr.rollback(tx, err)
+	}
+	id, err := result.LastInsertID()
+	if err != nil {
+		return nil, spr.rollback(tx, err)
+	}
+	err = saveItemConfigs(tx, id, profile.GetItemConfigs())
+	if err != nil {
+		return nil, spr.rollback(tx, err)
+	}
+	_, err = saveEngineConfigVersion(tx, id, profile.GetItemConfigs(), constant.EmptyString, scoringProfileCreateDescription)
+	if err != nil {
+		return nil, spr.rollback(tx, err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return spr.GetByID(id)
+}
+
+// Update updates the scoring profile and replaces its item configs in the middleware as a transaction
+func (spr *ScoringProfileRepo) Update(profile healthcheck.ScoringProfile) error {
+	tx, err := spr.Transaction()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = tx.Close()
+		if err != nil {
+			log.Errorf("healthcheck ScoringProfileRepo.Update(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	err = tx.Begin()
+	if err != nil {
+		return err
+	}
+
+	sql := `update t_hc_scoring_profile set profile_name = ?, description = ?, del_flag = ? where id = ?;`
+	log.Debugf("healthcheck ScoringProfileRepo.Update() update sql: \n%s\nplaceholders: %s, %s, %d, %d",
+		sql, profile.GetProfileName(), profile.GetDescription(), profile.GetDelFlag(), profile.Identity())
+	_, err = tx.Execute(sql, profile.GetProfileName(), profile.GetDescription(), profile.GetDelFlag(), profile.Identity())
+	if err != nil {
+		return spr.rollback(tx, err)
+	}
+	sql = `delete from t_hc_default_engine_config where profile_id = ?;`
+	log.Debugf("healthcheck ScoringProfileRepo.Update() delete sql: \n%s\nplaceholders: %d", sql, profile.Identity())
+	_, err = tx.Execute(sql, profile.Identity())
+	if err != nil {
+		return spr.rollback(tx, err)
+	}
+	err = saveItemConfigs(tx, profile.Identity(), profile.GetItemConfigs())
+	if err != nil {
+		return spr.rollback(tx, err)
+	}
+	_, err = saveEngineConfigVersion(tx, profile.Identity(), profile.GetItemConfigs(), constant.EmptyString, scoringProfileUpdateDescription)
+	if err != nil {
+		return spr.rollback(tx, err)
+	}
+
+	return tx.Commit()
+}
+
+// Delete deletes the scoring profile with its item configs, config versions and assignments in the middleware as a transaction
+func (spr *ScoringProfileRepo) Delete(id int) error {
+	tx, err := spr.Transaction()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = tx.Close()
+		if err != nil {
+			log.Errorf("healthcheck ScoringProfileRepo.Delete(): close database connection failed.\n%+v", err)
+		}
+	}()
+
+	err = tx.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, sql := range []string{
+		`delete from t_hc_scoring_profile_assignment where profile_id = ?;`,
+		`delete from t_hc_default_engine_config where profile_id = ?;`,
+		`delete from t_hc_engine_config_version where profile_id = ?;`,
+		`delete from t_hc_scoring_profile where id = ?;`,
+	} {
+		log.Debugf("healthcheck ScoringProfileRepo.Delete() delete sql: \n%s\nplaceholders: %d", sql, id)
+		_, err = tx.Execute(sql, id)
+		if err != nil {
+			return spr.rollback(tx, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Assign assigns the scoring profile to the target, the former assignment of the target will be replaced
+func (spr *ScoringProfileRepo) Assign(profileID, targetType, targetID int) error {
+	sql := `
+		insert into t_hc_scoring_profile_assignment(profile_id, target_type, target_id) values(?, ?, ?)
+		on duplicate key update profile_id = values(profile_id), del_flag = 0;
+	`
+	log.Debugf("healthcheck ScoringProfileRepo.Assign() insert sql: \n%s\nplaceholders: %d, %d, %d", sql, profileID, targetType, targetID)
+	_, err := spr.Execute(sql, profileID, targetType, targetID)
+
+	return err
+}
+
+// Unassign removes the assignment of the target
+func (spr *ScoringProfileRepo) Unassign(targetType, targetID int) error {
+	sql := `delete from t_hc_scoring_profile_assignment where target_type = ? and target_id = ?;`
+	log.Debugf("healthcheck ScoringProfileRepo.Unassign() delete sql: \n%s\nplaceholders: %d, %d", sql, targetType, targetID)
+	_, err := spr.Execute(sql, targetType, targetID)
+
+	return err
+}
+
+// rollback rollbacks the transaction and returns the original error,
+// the error of the rollback will only be logged
+func (spr *ScoringProfileRepo) rollback(tx middleware.Transaction, err error) error {
+	rollbackErr := tx.Rollback()
+	if rollbackErr != nil {
+		log.Errorf("healthcheck ScoringProfileRepo.rollback(): rollback failed.\n%+v", rollbackErr)
+	}
+
+	return err
+}
@@ -0,0 +1,206 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/das/global"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testScoringProfileProfileName       = "test_profile_name"
+	testScoringProfileDescription       = "test profile description"
+	testScoringProfileUpdateDescription = "test profile update description"
+)
+
+var testScoringProfileRepo *ScoringProfileRepo
+
+func init() {
+	testInitDASMySQLPool()
+	testScoringProfileRepo = newScoringProfileRepo(global.DASMySQLPool)
+}
+
+// testScoringProfileItemConfigs returns the item configs of the global default engine config
+func testScoringProfileItemConfigs() ([]healthcheck.ItemConfig, error) {
+	engineConfig, err := testDASRepo.LoadEngineConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var itemConfigs []healthcheck.ItemConfig
+	for _, itemName := range engineConfig.GetItemNames() {
+		itemConfigs = append(itemConfigs, engineConfig.GetItemConfig(itemName))
+	}
+
+	return itemConfigs, nil
+}
+
+func testCreateScoringProfile() (healthcheck.ScoringProfile, error) {
+	itemConfigs, err := testScoringProfileItemConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	return testScoringProfileRepo.Create(NewScoringProfile(testScoringProfileProfileName, testScoringProfileDescription, itemConfigs))
+}
+
+func testDeleteScoringProfileByID(id int) error {
+	return testScoringProfileRepo.Delete(id)
+}
+
+func TestScoringProfileRepo_All(t *testing.T) {
+	TestScoringProfileRepo_Execute(t)
+	TestScoringProfileRepo_Transaction(t)
+	TestScoringProfileRepo_GetAll(t)
+	TestScoringProfileRepo_GetByID(t)
+	TestScoringProfileRepo_GetID(t)
+	TestScoringProfileRepo_Create(t)
+	TestScoringProfileRepo_Update(t)
+	TestScoringProfileRepo_Delete(t)
+	TestScoringProfileRepo_Assign(t)
+	TestScoringProfileRepo_Unassign(t)
+}
+
+func TestScoringProfileRepo_Execute(t *testing.T) {
+	asst := assert.New(t)
+
+	sql := `select 1;`
+	result, err := testScoringProfileRepo.Execute(sql)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	r, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	asst.Nil(err, common.CombineMessageWithError("test Execute() failed", err))
+	asst.Equal(1, r, "test Execute() failed")
+}
+
+func TestScoringProfileRepo_Transaction(t *testing.T) {
+	asst := assert.New(t)
+
+	tx, err := testScoringProfileRepo.Transaction()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Begin()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+	err = tx.Rollback()
+	asst.Nil(err, common.CombineMessageWithError("test Transaction() failed", err))
+}
+
+func TestScoringProfileRepo_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	profiles, err := testScoringProfileRepo.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+	asst.True(len(profiles) > constant.ZeroInt, "test GetAll() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestScoringProfileRepo_GetByID(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	p, err := testScoringProfileRepo.GetByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	asst.Equal(testScoringProfileProfileName, p.GetProfileName(), "test GetByID() failed")
+	asst.Nil(p.GetEngineConfig().Validate(), "test GetByID() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+}
+
+func TestScoringProfileRepo_GetID(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetID() failed", err))
+	id, err := testScoringProfileRepo.GetID(testScoringProfileProfileName)
+	asst.Nil(err, common.CombineMessageWithError("test GetID() failed", err))
+	asst.Equal(profile.Identity(), id, "test GetID() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetID() failed", err))
+}
+
+func TestScoringProfileRepo_Create(t *testing.T) {
+	asst := assert.New(t)
+
+	itemConfigs, err := testScoringProfileItemConfigs()
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	asst.Equal(testScoringProfileDescription, profile.GetDescription(), "test Create() failed")
+	asst.Equal(len(itemConfigs), len(profile.GetItemConfigs()), "test Create() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+}
+
+func TestScoringProfileRepo_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	profileInfo := NewScoringProfile(testScoringProfileProfileName, testScoringProfileUpdateDescription, profile.GetItemConfigs())
+	profileInfo.ID = profile.Identity()
+	err = testScoringProfileRepo.Update(profileInfo)
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	p, err := testScoringProfileRepo.GetByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testScoringProfileUpdateDescription, p.GetDescription(), "test Update() failed")
+	asst.Equal(len(profile.GetItemConfigs()), len(p.GetItemConfigs()), "test Update() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestScoringProfileRepo_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	_, err = testScoringProfileRepo.GetByID(profile.Identity())
+	asst.NotNil(err, "test Delete() failed")
+	engineConfig, err := testDASRepo.LoadEngineConfigByProfileID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	asst.Equal(constant.ZeroInt, len(engineConfig.GetItemNames()), "test Delete() failed")
+}
+
+func TestScoringProfileRepo_Assign(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Assign() failed", err))
+	err = testScoringProfileRepo.Assign(profile.Identity(), ScoringProfileTargetTypeMySQLCluster, testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test Assign() failed", err))
+	p, err := testScoringProfileRepo.GetByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Assign() failed", err))
+	asst.Equal(1, len(p.GetAssignments()), "test Assign() failed")
+	asst.Equal(testHealthcheckMySQLClusterID, p.GetAssignments()[constant.ZeroInt].GetTargetID(), "test Assign() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Assign() failed", err))
+}
+
+func TestScoringProfileRepo_Unassign(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+	err = testScoringProfileRepo.Assign(profile.Identity(), ScoringProfileTargetTypeMySQLCluster, testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+	err = testScoringProfileRepo.Unassign(ScoringProfileTargetTypeMySQLCluster, testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+	p, err := testScoringProfileRepo.GetByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+	asst.Equal(constant.ZeroInt, len(p.GetAssignments()), "test Unassign() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+}
@@ -0,0 +1,182 @@
+package healthcheck
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+)
+
+const scoringProfileScoringProfilesStruct = "ScoringProfiles"
+
+var _ healthcheck.ScoringProfileService = (*ScoringProfileService)(nil)
+
+// ScoringProfileService is the service of the healthcheck scoring profile
+type ScoringProfileService struct {
+	healthcheck.ScoringProfileRepo
+	ScoringProfiles []healthcheck.ScoringProfile `json:"scoring_profiles"`
+}
+
+// NewScoringProfileService returns a new healthcheck.ScoringProfileService
+func NewScoringProfileService(repo healthcheck.ScoringProfileRepo) healthcheck.ScoringProfileService {
+	return newScoringProfileService(repo)
+}
+
+// NewScoringProfileServiceWithDefault returns a new healthcheck.ScoringProfileService with default repository
+func NewScoringProfileServiceWithDefault() healthcheck.ScoringProfileService {
+	return newScoringProfileService(NewScoringProfileRepoWithGlobal())
+}
+
+// newScoringProfileService returns a new *ScoringProfileService
+func newScoringProfileService(repo healthcheck.ScoringProfileRepo) *ScoringProfileService {
+	return &ScoringProfileService{
+		ScoringProfileRepo: repo,
+		ScoringProfiles:    []healthcheck.ScoringProfile{},
+	}
+}
+
+// GetScoringProfiles returns the scoring profiles of the service
+func (sps *ScoringProfileService) GetScoringProfiles() []healthcheck.ScoringProfile {
+	return sps.ScoringProfiles
+}
+
+// GetAll gets all scoring profiles from the middleware
+func (sps *ScoringProfileService) GetAll() error {
+	var err error
+
+	sps.ScoringProfiles, err = sps.ScoringProfileRepo.GetAll()
+
+	return err
+}
+
+// GetByID gets a scoring profile of the given id from the middleware
+func (sps *ScoringProfileService) GetByID(id int) error {
+	profile, err := sps.ScoringProfileRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	sps.ScoringProfiles = nil
+	sps.ScoringProfiles = append(sps.ScoringProfiles, profile)
+
+	return nil
+}
+
+// Create creates a scoring profile in the middleware, the item configs must be a full item config set
+func (sps *ScoringProfileService) Create(profileName, description string, itemConfigs []healthcheck.ItemConfig) error {
+	profileInfo := NewScoringProfile(profileName, description, itemConfigs)
+	err := sps.validate(profileInfo)
+	if err != nil {
+		return err
+	}
+	// insert into middleware
+	profile, err := sps.ScoringProfileRepo.Create(profileInfo)
+	if err != nil {
+		return err
+	}
+
+	sps.ScoringProfiles = nil
+	sps.ScoringProfiles = append(sps.ScoringProfiles, profile)
+
+	return nil
+}
+
+// Update updates the scoring profile of the given id and replaces its item configs in the middleware,
+// the item configs must be a full item config set
+func (sps *ScoringProfileService) Update(id int, profileName, description string, itemConfigs []healthcheck.ItemConfig) error {
+	// check if the profile exists
+	_, err := sps.ScoringProfileRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	profileInfo := NewScoringProfile(profileName, description, itemConfigs)
+	profileInfo.ID = id
+	err = sps.validate(profileInfo)
+	if err != nil {
+		return err
+	}
+	err = sps.ScoringProfileRepo.Update(profileInfo)
+	if err != nil {
+		return err
+	}
+
+	return sps.GetByID(id)
+}
+
+// Delete deletes the scoring profile of given id in the middleware
+func (sps *ScoringProfileService) Delete(id int) error {
+	err := sps.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	return sps.ScoringProfileRepo.Delete(id)
+}
+
+// Assign assigns the scoring profile of the given id to an env, a mysql cluster or a resource group
+func (sps *ScoringProfileService) Assign(id, targetType, targetID int) error {
+	err := sps.validateTarget(targetType, targetID)
+	if err != nil {
+		return err
+	}
+	// check if the profile exists
+	_, err = sps.ScoringProfileRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	err = sps.ScoringProfileRepo.Assign(id, targetType, targetID)
+	if err != nil {
+		return err
+	}
+
+	return sps.GetByID(id)
+}
+
+// Unassign removes the scoring profile assignment of the env, the mysql cluster or the resource group
+func (sps *ScoringProfileService) Unassign(targetType, targetID int) error {
+	err := sps.validateTarget(targetType, targetID)
+	if err != nil {
+		return err
+	}
+
+	return sps.ScoringProfileRepo.Unassign(targetType, targetID)
+}
+
+// validate validates if the scoring profile is valid, the item configs of the profile must be a valid full item config set
+func (sps *ScoringProfileService) validate(profile healthcheck.ScoringProfile) error {
+	if profile.GetProfileName() == constant.EmptyString {
+		return errors.New("profile name must not be empty")
+	}
+	itemNames := make(map[string]bool)
+	for _, itemConfig := range profile.GetItemConfigs() {
+		if itemNames[itemConfig.GetItemName()] {
+			return errors.Errorf("item %s is duplicated in the item configs", itemConfig.GetItemName())
+		}
+		itemNames[itemConfig.GetItemName()] = true
+	}
+
+	return profile.GetEngineConfig().Validate()
+}
+
+// validateTarget validates if the target of the assignment is valid
+func (sps *ScoringProfileService) validateTarget(targetType, targetID int) error {
+	if targetType < ScoringProfileTargetTypeMySQLCluster || targetType > ScoringProfileTargetTypeEnv {
+		return errors.Errorf("target type must be one of [%d, %d, %d], %d is not valid",
+			ScoringProfileTargetTypeMySQLCluster, ScoringProfileTargetTypeResourceGroup, ScoringProfileTargetTypeEnv, targetType)
+	}
+	if targetID <= constant.ZeroInt {
+		return errors.Errorf("target id must be larger than 0, %d is not valid", targetID)
+	}
+
+	return nil
+}
+
+// Marshal marshals ScoringProfileService.ScoringProfiles to json bytes
+func (sps *ScoringProfileService) Marshal() ([]byte, error) {
+	return sps.MarshalWithFields(scoringProfileScoringProfilesStruct)
+}
+
+// MarshalWithFields marshals only specified fields of the ScoringProfileService to json bytes
+func (sps *ScoringProfileService) MarshalWithFields(fields ...string) ([]byte, error) {
+	return common.MarshalStructWithFields(sps, fields...)
+}
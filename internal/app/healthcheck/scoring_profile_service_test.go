@@ -0,0 +1,138 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+var testScoringProfileService *ScoringProfileService
+
+func init() {
+	testInitDASMySQLPool()
+	testScoringProfileService = newScoringProfileService(NewScoringProfileRepoWithGlobal())
+}
+
+func TestScoringProfileService_All(t *testing.T) {
+	TestScoringProfileService_GetAll(t)
+	TestScoringProfileService_GetByID(t)
+	TestScoringProfileService_Create(t)
+	TestScoringProfileService_Update(t)
+	TestScoringProfileService_Delete(t)
+	TestScoringProfileService_Assign(t)
+	TestScoringProfileService_Unassign(t)
+	TestScoringProfileService_Marshal(t)
+}
+
+func TestScoringProfileService_GetAll(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testScoringProfileService.GetAll()
+	asst.Nil(err, common.CombineMessageWithError("test GetAll() failed", err))
+}
+
+func TestScoringProfileService_GetByID(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	err = testScoringProfileService.GetByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+	asst.Equal(testScoringProfileProfileName, testScoringProfileService.GetScoringProfiles()[constant.ZeroInt].GetProfileName(), "test GetByID() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test GetByID() failed", err))
+}
+
+func TestScoringProfileService_Create(t *testing.T) {
+	asst := assert.New(t)
+
+	itemConfigs, err := testScoringProfileItemConfigs()
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	err = testScoringProfileService.Create(testScoringProfileProfileName, testScoringProfileDescription, itemConfigs)
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	// delete
+	err = testDeleteScoringProfileByID(testScoringProfileService.GetScoringProfiles()[constant.ZeroInt].Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Create() failed", err))
+	// the item weights do not sum to 100
+	err = testScoringProfileService.Create(testScoringProfileProfileName, testScoringProfileDescription, itemConfigs[1:])
+	asst.NotNil(err, "test Create() failed")
+	// duplicate item
+	err = testScoringProfileService.Create(testScoringProfileProfileName, testScoringProfileDescription, append(itemConfigs, itemConfigs[constant.ZeroInt]))
+	asst.NotNil(err, "test Create() failed")
+}
+
+func TestScoringProfileService_Update(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	err = testScoringProfileService.Update(profile.Identity(), testScoringProfileProfileName, testScoringProfileUpdateDescription, profile.GetItemConfigs())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(testScoringProfileUpdateDescription, testScoringProfileService.GetScoringProfiles()[constant.ZeroInt].GetDescription(), "test Update() failed")
+	// invalid item configs will not be saved
+	err = testScoringProfileService.Update(profile.Identity(), testScoringProfileProfileName, testScoringProfileUpdateDescription, profile.GetItemConfigs()[1:])
+	asst.NotNil(err, "test Update() failed")
+	err = testScoringProfileService.GetByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+	asst.Equal(len(profile.GetItemConfigs()), len(testScoringProfileService.GetScoringProfiles()[constant.ZeroInt].GetItemConfigs()), "test Update() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Update() failed", err))
+}
+
+func TestScoringProfileService_Delete(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+	err = testScoringProfileService.Delete(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Delete() failed", err))
+}
+
+func TestScoringProfileService_Assign(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Assign() failed", err))
+	err = testScoringProfileService.Assign(profile.Identity(), ScoringProfileTargetTypeMySQLCluster, testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test Assign() failed", err))
+	asst.Equal(1, len(testScoringProfileService.GetScoringProfiles()[constant.ZeroInt].GetAssignments()), "test Assign() failed")
+	// the mysql server could not be the target
+	err = testScoringProfileService.Assign(profile.Identity(), ScheduleTargetTypeMySQLServer, testHealthcheckMySQLServerID)
+	asst.NotNil(err, "test Assign() failed")
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Assign() failed", err))
+}
+
+func TestScoringProfileService_Unassign(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+	err = testScoringProfileService.Assign(profile.Identity(), ScoringProfileTargetTypeMySQLCluster, testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+	err = testScoringProfileService.Unassign(ScoringProfileTargetTypeMySQLCluster, testHealthcheckMySQLClusterID)
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Unassign() failed", err))
+}
+
+func TestScoringProfileService_Marshal(t *testing.T) {
+	asst := assert.New(t)
+
+	profile, err := testCreateScoringProfile()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	err = testScoringProfileService.GetByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	jsonBytes, err := testScoringProfileService.Marshal()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	t.Log(string(jsonBytes))
+	// delete
+	err = testDeleteScoringProfileByID(profile.Identity())
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+}
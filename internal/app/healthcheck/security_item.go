@@ -0,0 +1,322 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultSecurityItemName = "security"
+
+	requireSecureTransportVariable = "require_secure_transport"
+	requireSecureTransportOn       = "ON"
+	securityGlobalVariablesName    = "global variables"
+	securityAccountSeparator       = "@"
+	securityHostWildcard           = "%"
+	securityGlobalLevel            = "*.*"
+
+	grantPrivilegeAll         = "ALL PRIVILEGES"
+	grantPrivilegeSuper       = "SUPER"
+	grantPrivilegeFile        = "FILE"
+	grantPrivilegeUsage       = "USAGE"
+	grantPrivilegeProxy       = "PROXY"
+	grantPrivilegeGrantOption = "GRANT OPTION"
+
+	// the value of an account is the largest value of its issues
+	securityIssueValueHigh   = 1.0
+	securityIssueValueMedium = 0.6
+)
+
+var (
+	_ healthcheck.CheckItem = (*SecurityItem)(nil)
+
+	// the privilege grant statements look like:
+	// GRANT SELECT, SUPER ON *.* TO `u01`@`%` WITH GRANT OPTION
+	// GRANT ALL PRIVILEGES ON `db01`.* TO 'u01'@'%'
+	grantStatementRegexp = regexp.MustCompile("^GRANT (.+?) ON (\\S+) TO .+?( WITH GRANT OPTION)?$")
+	// the dangerous privileges which are only allowed for the admin accounts
+	securityAdminPrivileges = []string{grantPrivilegeAll, grantPrivilegeSuper, grantPrivilegeFile}
+)
+
+func init() {
+	RegisterCheckItem(NewSecurityItem())
+}
+
+// SecurityData is an account or the global variables which have security issues and the suggestions to fix them
+type SecurityData struct {
+	Name        string   `json:"name"`
+	Value       float64  `json:"value"`
+	Issues      []string `json:"issues"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// NewSecurityData returns a new *SecurityData
+func NewSecurityData(name string) *SecurityData {
+	return &SecurityData{Name: name}
+}
+
+// addIssue adds the issue and the suggestion to the data, the value of the data is the largest value of the issues
+func (sd *SecurityData) addIssue(value float64, issue, suggestion string) {
+	if value > sd.Value {
+		sd.Value = value
+	}
+	sd.Issues = append(sd.Issues, issue)
+	if suggestion != constant.EmptyString {
+		sd.Suggestions = append(sd.Suggestions, suggestion)
+	}
+}
+
+// accountPrivilege is the privileges of an account which are parsed from the grant statements
+type accountPrivilege struct {
+	// globalPrivileges are the privileges on *.*
+	globalPrivileges []string
+	// grantOptionLevels are the levels on which the account has the grant option, e.g. *.*, `db01`.*
+	grantOptionLevels []string
+}
+
+// isPrivileged returns if the account has any global privilege
+func (ap *accountPrivilege) isPrivileged() bool {
+	return len(ap.globalPrivileges) > constant.ZeroInt || len(ap.grantOptionLevels) > constant.ZeroInt
+}
+
+// getAdminPrivileges returns the global privileges which are only allowed for the admin accounts
+func (ap *accountPrivilege) getAdminPrivileges() []string {
+	var privileges []string
+	for _, privilege := range ap.globalPrivileges {
+		for _, adminPrivilege := range securityAdminPrivileges {
+			if strings.EqualFold(privilege, adminPrivilege) {
+				privileges = append(privileges, adminPrivilege)
+				break
+			}
+		}
+	}
+
+	return privileges
+}
+
+// SecurityItem checks the accounts of the mysql server and if the secure transport is required,
+// the accounts which match the exempt accounts in the config are not checked,
+// the value of each account is the largest value of its issues
+type SecurityItem struct{}
+
+// NewSecurityItem returns a new *SecurityItem
+func NewSecurityItem() *SecurityItem {
+	return &SecurityItem{}
+}
+
+// GetName returns the item name
+func (si *SecurityItem) GetName() string {
+	return defaultSecurityItemName
+}
+
+// GetDataSource returns the data source of the item
+func (si *SecurityItem) GetDataSource() string {
+	return DataSourceApplicationMySQL
+}
+
+// Check checks the security posture of the mysql server
+func (si *SecurityItem) Check(env healthcheck.CheckEnv) (healthcheck.ItemResult, error) {
+	repo := env.GetApplicationMySQLRepo()
+	users, err := repo.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	adminAccounts := getSecurityAccounts(viper.GetString(config.HealthcheckSecurityAdminAccountsKey))
+	exemptAccounts := getSecurityAccounts(viper.GetString(config.HealthcheckSecurityExemptAccountsKey))
+
+	var datas []*SecurityData
+	for _, user := range users {
+		// the locked accounts could not be used to login, e.g. mysql.sys, mysql.session
+		if user.IsLocked() || matchSecurityAccounts(exemptAccounts, user.GetUser(), user.GetHost()) {
+			continue
+		}
+		grants, err := repo.GetGrants(user.GetUser(), user.GetHost())
+		if err != nil {
+			return nil, err
+		}
+		data := getAccountSecurityData(user, parseGrants(grants), matchSecurityAccounts(adminAccounts, user.GetUser(), user.GetHost()))
+		if len(data.Issues) > constant.ZeroInt {
+			datas = append(datas, data)
+		}
+	}
+
+	// require_secure_transport
+	variables, err := repo.GetVariables([]string{requireSecureTransportVariable})
+	if err != nil {
+		return nil, err
+	}
+	data := getSecureTransportSecurityData(variables)
+	if len(data.Issues) > constant.ZeroInt {
+		datas = append(datas, data)
+	}
+
+	cfg := env.GetItemConfig(si.GetName())
+
+	var (
+		highSum     float64
+		highCount   int
+		mediumSum   float64
+		mediumCount int
+
+		highDatas []*SecurityData
+		advices   []string
+	)
+	for _, data := range datas {
+		switch {
+		case data.Value >= cfg.GetHighWatermark():
+			highDatas = append(highDatas, data)
+			highSum += data.Value
+			highCount++
+		case data.Value >= cfg.GetLowWatermark():
+			mediumSum += data.Value
+			mediumCount++
+		default:
+			continue
+		}
+		advices = append(advices, fmt.Sprintf("%s: %s, suggestion: %s",
+			data.Name, strings.Join(data.Issues, constant.CommaString), strings.Join(data.Suggestions, constant.SpaceString)))
+	}
+
+	jsonBytesTotal, err := json.Marshal(datas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jsonBytesHigh, err := json.Marshal(highDatas)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	score := calculateScore(cfg, highSum, highCount, mediumSum, mediumCount)
+
+	return NewItemResultWithEnv(env, si, score, string(jsonBytesTotal), string(jsonBytesHigh), strings.Join(advices, constant.CommaString)), nil
+}
+
+// getAccountSecurityData checks the account with its privileges, the admin accounts are allowed to have the admin privileges and the grant option
+func getAccountSecurityData(user healthcheck.MySQLUser, privilege *accountPrivilege, isAdmin bool) *SecurityData {
+	account := getSecurityAccountDesc(user.GetUser(), user.GetHost())
+	data := NewSecurityData(account)
+
+	// anonymous user
+	if user.GetUser() == constant.EmptyString {
+		data.addIssue(securityIssueValueHigh, "account is anonymous", fmt.Sprintf("drop user %s;", account))
+		// the anonymous user should be dropped, so the other issues are not needed
+		return data
+	}
+	// password
+	if user.HasEmptyPassword() {
+		data.addIssue(securityIssueValueHigh, "account has no password", fmt.Sprintf("alter user %s identified by '<password>';", account))
+	}
+	if user.HasOldPassword() {
+		data.addIssue(securityIssueValueHigh, "password is in the old pre-4.1 format",
+			fmt.Sprintf("alter user %s identified with mysql_native_password by '<password>';", account))
+	}
+	// host wildcard
+	if strings.Contains(user.GetHost(), securityHostWildcard) && privilege.isPrivileged() {
+		data.addIssue(securityIssueValueMedium, fmt.Sprintf("privileged account could login from any host which matches %s", user.GetHost()),
+			fmt.Sprintf("rename user %s to '%s'@'<host>';", account, escapeSingleQuote(user.GetUser())))
+	}
+	if isAdmin {
+		return data
+	}
+	// admin privileges
+	adminPrivileges := privilege.getAdminPrivileges()
+	if len(adminPrivileges) > constant.ZeroInt {
+		data.addIssue(securityIssueValueHigh, fmt.Sprintf("non-admin account has %s privileges", strings.Join(adminPrivileges, constant.CommaString)),
+			fmt.Sprintf("revoke %s on %s from %s;", strings.ToLower(strings.Join(adminPrivileges, ", ")), securityGlobalLevel, account))
+	}
+	for _, level := range privilege.grantOptionLevels {
+		data.addIssue(securityIssueValueHigh, fmt.Sprintf("non-admin account has grant option on %s", level),
+			fmt.Sprintf("revoke grant option on %s from %s;", level, account))
+	}
+
+	return data
+}
+
+// getSecureTransportSecurityData checks if the secure transport is required,
+// the variable does not exist if the mysql server is older than 5.7
+func getSecureTransportSecurityData(variables []healthcheck.Variable) *SecurityData {
+	data := NewSecurityData(securityGlobalVariablesName)
+	for _, variable := range variables {
+		if strings.EqualFold(variable.GetName(), requireSecureTransportVariable) {
+			if !strings.EqualFold(variable.GetValue(), requireSecureTransportOn) {
+				data.addIssue(securityIssueValueMedium, fmt.Sprintf("%s is %s", requireSecureTransportVariable, variable.GetValue()),
+					fmt.Sprintf("set global %s = ON;", requireSecureTransportVariable))
+			}
+
+			return data
+		}
+	}
+
+	data.addIssue(securityIssueValueMedium, fmt.Sprintf("%s is missing", requireSecureTransportVariable), constant.EmptyString)
+
+	return data
+}
+
+// parseGrants parses the privileges from the grant statements, the role grants and the proxy grants are ignored
+func parseGrants(grants []string) *accountPrivilege {
+	privilege := &accountPrivilege{}
+	for _, grant := range grants {
+		matches := grantStatementRegexp.FindStringSubmatch(strings.TrimSpace(grant))
+		if matches == nil {
+			continue
+		}
+		privileges := matches[1]
+		level := strings.ReplaceAll(matches[2], "`", constant.EmptyString)
+		if strings.HasPrefix(strings.ToUpper(privileges), grantPrivilegeProxy) {
+			continue
+		}
+		if matches[3] != constant.EmptyString {
+			privilege.grantOptionLevels = append(privilege.grantOptionLevels, matches[2])
+		}
+		if level != securityGlobalLevel {
+			continue
+		}
+		for _, p := range strings.Split(privileges, constant.CommaString) {
+			p = strings.ToUpper(strings.TrimSpace(p))
+			if p != grantPrivilegeUsage && p != grantPrivilegeGrantOption {
+				privilege.globalPrivileges = append(privilege.globalPrivileges, p)
+			}
+		}
+	}
+
+	return privilege
+}
+
+// getSecurityAccounts splits the comma separated accounts of the config
+func getSecurityAccounts(s string) []string {
+	var accounts []string
+	for _, account := range strings.Split(s, constant.CommaString) {
+		account = strings.TrimSpace(account)
+		if account != constant.EmptyString {
+			accounts = append(accounts, account)
+		}
+	}
+
+	return accounts
+}
+
+// matchSecurityAccounts returns if the account matches any of the accounts,
+// an account is either user@host or user, the latter matches all the hosts of the user
+func matchSecurityAccounts(accounts []string, user, host string) bool {
+	for _, account := range accounts {
+		if account == user || account == user+securityAccountSeparator+host {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSecurityAccountDesc returns the description of the account which could be used in the sql
+func getSecurityAccountDesc(user, host string) string {
+	return fmt.Sprintf("'%s'@'%s'", escapeSingleQuote(user), escapeSingleQuote(host))
+}
@@ -0,0 +1,78 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	"github.com/romberli/go-util/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSecurityUser = "u01"
+	testSecurityHost = "%"
+)
+
+func TestSecurityItem_All(t *testing.T) {
+	TestSecurityItem_parseGrants(t)
+	TestSecurityItem_matchSecurityAccounts(t)
+	TestSecurityItem_getAccountSecurityData(t)
+	TestSecurityItem_getSecureTransportSecurityData(t)
+}
+
+func TestSecurityItem_parseGrants(t *testing.T) {
+	asst := assert.New(t)
+
+	privilege := parseGrants([]string{
+		"GRANT SELECT, SUPER, FILE ON *.* TO `u01`@`%`",
+		"GRANT ALL PRIVILEGES ON `db01`.* TO `u01`@`%` WITH GRANT OPTION",
+		"GRANT PROXY ON ''@'' TO 'u01'@'%' WITH GRANT OPTION",
+		"GRANT `r01`@`%` TO `u01`@`%`",
+	})
+	asst.Equal([]string{"SELECT", grantPrivilegeSuper, grantPrivilegeFile}, privilege.globalPrivileges, "test parseGrants() failed")
+	asst.Equal([]string{"`db01`.*"}, privilege.grantOptionLevels, "test parseGrants() failed")
+	asst.Equal([]string{grantPrivilegeSuper, grantPrivilegeFile}, privilege.getAdminPrivileges(), "test parseGrants() failed")
+
+	privilege = parseGrants([]string{"GRANT USAGE ON *.* TO 'u01'@'%'"})
+	asst.False(privilege.isPrivileged(), "test parseGrants() failed")
+}
+
+func TestSecurityItem_matchSecurityAccounts(t *testing.T) {
+	asst := assert.New(t)
+
+	accounts := getSecurityAccounts(" root@localhost, u01 ,,")
+	asst.Equal(2, len(accounts), "test matchSecurityAccounts() failed")
+	asst.True(matchSecurityAccounts(accounts, "root", "localhost"), "test matchSecurityAccounts() failed")
+	asst.False(matchSecurityAccounts(accounts, "root", testSecurityHost), "test matchSecurityAccounts() failed")
+	asst.True(matchSecurityAccounts(accounts, testSecurityUser, testSecurityHost), "test matchSecurityAccounts() failed")
+}
+
+func TestSecurityItem_getAccountSecurityData(t *testing.T) {
+	asst := assert.New(t)
+
+	privilege := parseGrants([]string{"GRANT SUPER ON *.* TO `u01`@`%` WITH GRANT OPTION"})
+	// anonymous user
+	data := getAccountSecurityData(&MySQLUser{User: constant.EmptyString, Host: "localhost"}, privilege, false)
+	asst.Equal(1, len(data.Issues), "test getAccountSecurityData() failed")
+	asst.Equal("drop user ''@'localhost';", data.Suggestions[constant.ZeroInt], "test getAccountSecurityData() failed")
+	// non-admin account with empty password, host wildcard, super privilege and grant option
+	data = getAccountSecurityData(&MySQLUser{User: testSecurityUser, Host: testSecurityHost, EmptyPassword: true}, privilege, false)
+	asst.Equal(securityIssueValueHigh, data.Value, "test getAccountSecurityData() failed")
+	asst.Equal(4, len(data.Issues), "test getAccountSecurityData() failed")
+	asst.Equal("revoke super on *.* from 'u01'@'%';", data.Suggestions[2], "test getAccountSecurityData() failed")
+	// admin account with host wildcard
+	data = getAccountSecurityData(&MySQLUser{User: testSecurityUser, Host: testSecurityHost}, privilege, true)
+	asst.Equal(securityIssueValueMedium, data.Value, "test getAccountSecurityData() failed")
+	asst.Equal(1, len(data.Issues), "test getAccountSecurityData() failed")
+}
+
+func TestSecurityItem_getSecureTransportSecurityData(t *testing.T) {
+	asst := assert.New(t)
+
+	data := getSecureTransportSecurityData([]healthcheck.Variable{NewGlobalVariable(requireSecureTransportVariable, "OFF")})
+	asst.Equal(securityIssueValueMedium, data.Value, "test getSecureTransportSecurityData() failed")
+	data = getSecureTransportSecurityData([]healthcheck.Variable{NewGlobalVariable(requireSecureTransportVariable, requireSecureTransportOn)})
+	asst.Equal(constant.ZeroInt, len(data.Issues), "test getSecureTransportSecurityData() failed")
+	data = getSecureTransportSecurityData(nil)
+	asst.Equal(1, len(data.Issues), "test getSecureTransportSecurityData() failed")
+}
@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/romberli/das/config"
 	"github.com/romberli/das/internal/app/metadata"
 	"github.com/romberli/das/internal/app/privilege"
+	"github.com/romberli/das/internal/app/query"
 	"github.com/romberli/das/internal/dependency/healthcheck"
 	depmeta "github.com/romberli/das/internal/dependency/metadata"
 	"github.com/romberli/das/pkg/message"
@@ -27,8 +29,12 @@ const (
 	healthcheckResultStruct        = "Result"
 	defaultMonitorClickhouseDBName = "pmm"
 	defaultMonitorMySQLDBName      = "pmm"
+	defaultRunningStatus           = 1
 	defaultSuccessStatus           = 2
 	defaultFailedStatus            = 3
+	defaultPartialStatus           = 4
+	defaultCancelledStatus         = 5
+	defaultQueuedStatus            = 6
 )
 
 var _ healthcheck.Service = (*Service)(nil)
@@ -39,7 +45,19 @@ type Service struct {
 	OperationInfo      healthcheck.OperationInfo
 	Engine             healthcheck.Engine
 	Result             healthcheck.Result             `json:"result"`
+	ClusterResult      healthcheck.ClusterResult      `json:"cluster_result"`
+	MiddlewareResult   healthcheck.MiddlewareResult   `json:"middleware_result"`
 	OperationHistories []healthcheck.OperationHistory `json:"operation_histories"`
+	TrendPoints        []healthcheck.TrendPoint       `json:"trend_points"`
+	ResultDiff         healthcheck.ResultDiff         `json:"result_diff"`
+	Report             healthcheck.Report             `json:"-"`
+	Snapshot           healthcheck.Snapshot           `json:"-"`
+	ItemFeedbacks      []healthcheck.ItemFeedback     `json:"item_feedbacks"`
+	FeedbackStats      []healthcheck.FeedbackStat     `json:"feedback_stats"`
+	QueuedOperations   []healthcheck.OperationHistory `json:"queued_operations"`
+	RunningOperations  []healthcheck.OperationHistory `json:"running_operations"`
+	Progress           healthcheck.Progress           `json:"progress"`
+	Capacities         []healthcheck.Capacity         `json:"capacities"`
 }
 
 // NewService returns a new *Service
@@ -86,6 +104,66 @@ func (s *Service) GetResult() healthcheck.Result {
 	return s.Result
 }
 
+// GetClusterResult returns the healthcheck cluster result
+func (s *Service) GetClusterResult() healthcheck.ClusterResult {
+	return s.ClusterResult
+}
+
+// GetMiddlewareResult returns the healthcheck middleware result
+func (s *Service) GetMiddlewareResult() healthcheck.MiddlewareResult {
+	return s.MiddlewareResult
+}
+
+// GetTrendPoints returns the trend points
+func (s *Service) GetTrendPoints() []healthcheck.TrendPoint {
+	return s.TrendPoints
+}
+
+// GetResultDiff returns the result diff
+func (s *Service) GetResultDiff() healthcheck.ResultDiff {
+	return s.ResultDiff
+}
+
+// GetReport returns the report
+func (s *Service) GetReport() healthcheck.Report {
+	return s.Report
+}
+
+// GetSnapshot returns the snapshot
+func (s *Service) GetSnapshot() healthcheck.Snapshot {
+	return s.Snapshot
+}
+
+// GetItemFeedbacks returns the accuracy feedbacks of the check items
+func (s *Service) GetItemFeedbacks() []healthcheck.ItemFeedback {
+	return s.ItemFeedbacks
+}
+
+// GetFeedbackStats returns the aggregated accuracy feedbacks
+func (s *Service) GetFeedbackStats() []healthcheck.FeedbackStat {
+	return s.FeedbackStats
+}
+
+// GetQueuedOperations returns the queued operations
+func (s *Service) GetQueuedOperations() []healthcheck.OperationHistory {
+	return s.QueuedOperations
+}
+
+// GetRunningOperations returns the running operations
+func (s *Service) GetRunningOperations() []healthcheck.OperationHistory {
+	return s.RunningOperations
+}
+
+// GetProgress returns the progress of the operation
+func (s *Service) GetProgress() healthcheck.Progress {
+	return s.Progress
+}
+
+// GetCapacities returns the capacity forecasts
+func (s *Service) GetCapacities() []healthcheck.Capacity {
+	return s.Capacities
+}
+
 // GetOperationHistoriesByLoginName returns the operation histories by login name
 func (s *Service) GetOperationHistoriesByLoginName(loginName string) error {
 	userService := metadata.NewUserServiceWithDefault()
@@ -118,15 +196,178 @@ func (s *Service) GetResultByOperationID(id int) error {
 	return err
 }
 
+// GetClusterResultByClusterOperationID gets the cluster result of given cluster operation id
+func (s *Service) GetClusterResultByClusterOperationID(id int) error {
+	var err error
+
+	s.ClusterResult, err = s.GetDASRepo().GetClusterResultByClusterOperationID(id)
+
+	return err
+}
+
+// GetMiddlewareResultByMiddlewareOperationID gets the middleware result of given middleware operation id
+func (s *Service) GetMiddlewareResultByMiddlewareOperationID(id int) error {
+	var err error
+
+	s.MiddlewareResult, err = s.GetDASRepo().GetMiddlewareResultByMiddlewareOperationID(id)
+
+	return err
+}
+
+// GetTrendByMySQLServerID gets the score trend of the mysql server with given mysql server id,
+// the results which were saved between start time and end time are taken as the trend points
+func (s *Service) GetTrendByMySQLServerID(mysqlServerID int, startTime, endTime time.Time, loginName string) error {
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	err := privilegeService.CheckMySQLServerByID(mysqlServerID)
+	if err != nil {
+		return err
+	}
+
+	return s.getTrend([]int{mysqlServerID}, startTime, endTime)
+}
+
+// GetTrendByMySQLClusterID gets the score trend of the mysql servers of the mysql cluster with given mysql cluster id,
+// the results which were saved between start time and end time are taken as the trend points
+func (s *Service) GetTrendByMySQLClusterID(mysqlClusterID int, startTime, endTime time.Time, loginName string) error {
+	// get mysql servers
+	mysqlClusterService := metadata.NewMySQLClusterServiceWithDefault()
+	err := mysqlClusterService.GetMySQLServersByID(mysqlClusterID)
+	if err != nil {
+		return err
+	}
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	mysqlServerIDList := make([]int, len(mysqlClusterService.GetMySQLServers()))
+	for i, mysqlServer := range mysqlClusterService.GetMySQLServers() {
+		err = privilegeService.CheckMySQLServerByID(mysqlServer.Identity())
+		if err != nil {
+			return err
+		}
+		mysqlServerIDList[i] = mysqlServer.Identity()
+	}
+	if len(mysqlServerIDList) == constant.ZeroInt {
+		return errors.Errorf("healthcheck: there is no mysql server in the mysql cluster. mysql cluster id: %d", mysqlClusterID)
+	}
+
+	return s.getTrend(mysqlServerIDList, startTime, endTime)
+}
+
+// getTrend gets the score trend of given mysql servers
+func (s *Service) getTrend(mysqlServerIDList []int, startTime, endTime time.Time) error {
+	results, err := s.GetDASRepo().GetResultsByMySQLServerIDs(mysqlServerIDList, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	s.TrendPoints = make([]healthcheck.TrendPoint, len(results))
+	for i, result := range results {
+		s.TrendPoints[i] = NewTrendPoint(result)
+	}
+
+	return nil
+}
+
+// GetCapacity gets the capacity forecasts of the mysql servers of given target,
+// the results which were saved between start time and end time are used to forecast the capacity
+func (s *Service) GetCapacity(targetType, targetID int, startTime, endTime time.Time, loginName string) error {
+	mysqlServers, err := s.getCapacityMySQLServers(targetType, targetID)
+	if err != nil {
+		return err
+	}
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	for _, mysqlServer := range mysqlServers {
+		err = privilegeService.CheckMySQLServerByID(mysqlServer.Identity())
+		if err != nil {
+			return err
+		}
+	}
+
+	s.Capacities = make([]healthcheck.Capacity, len(mysqlServers))
+	for i, mysqlServer := range mysqlServers {
+		results, err := s.GetDASRepo().GetResultsByMySQLServerIDs([]int{mysqlServer.Identity()}, startTime, endTime)
+		if err != nil {
+			return err
+		}
+		s.Capacities[i], err = NewCapacity(mysqlServer.Identity(), results)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getCapacityMySQLServers gets the mysql servers of the capacity target
+func (s *Service) getCapacityMySQLServers(targetType, targetID int) ([]depmeta.MySQLServer, error) {
+	switch targetType {
+	case CapacityTargetTypeMySQLServer:
+		mysqlServerService := metadata.NewMySQLServerServiceWithDefault()
+		err := mysqlServerService.GetByID(targetID)
+		if err != nil {
+			return nil, err
+		}
+
+		return mysqlServerService.GetMySQLServers(), nil
+	case CapacityTargetTypeMySQLCluster:
+		mysqlClusterService := metadata.NewMySQLClusterServiceWithDefault()
+		err := mysqlClusterService.GetMySQLServersByID(targetID)
+		if err != nil {
+			return nil, err
+		}
+
+		return mysqlClusterService.GetMySQLServers(), nil
+	case CapacityTargetTypeResourceGroup:
+		resourceGroupService := metadata.NewResourceGroupServiceWithDefault()
+		err := resourceGroupService.GetMySQLServersByID(targetID)
+		if err != nil {
+			return nil, err
+		}
+
+		return resourceGroupService.GetMySQLServers(), nil
+	default:
+		return nil, errors.Errorf("healthcheck Service.getCapacityMySQLServers(): target type %d is not valid. target id: %d", targetType, targetID)
+	}
+}
+
+// CompareResults compares the result of the target operation with the result of the base operation
+func (s *Service) CompareResults(baseOperationID, targetOperationID int) error {
+	base, err := s.GetDASRepo().GetResultByOperationID(baseOperationID)
+	if err != nil {
+		return err
+	}
+	target, err := s.GetDASRepo().GetResultByOperationID(targetOperationID)
+	if err != nil {
+		return err
+	}
+
+	resultDiff, err := NewResultDiff(base, target)
+	if err != nil {
+		return err
+	}
+	s.ResultDiff = resultDiff
+
+	return nil
+}
+
 // Check performs healthcheck on the mysql server with given mysql server id,
 // initiating is synchronous, actual running is asynchronous
-func (s *Service) Check(mysqlServerID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error) {
-	return s.check(mysqlServerID, startTime, endTime, step, loginName)
+func (s *Service) Check(mysqlServerID int, startTime, endTime time.Time, step time.Duration, loginName string, callbackURLs []string) (int, error) {
+	return s.check(constant.ZeroInt, mysqlServerID, startTime, endTime, step, loginName, callbackURLs)
+}
+
+// CheckWithSchedule performs healthcheck on the mysql server with given mysql server id,
+// it is used by the scheduler, the schedule id will be recorded in the operation history,
+// initiating is synchronous, actual running is asynchronous
+func (s *Service) CheckWithSchedule(scheduleID, mysqlServerID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error) {
+	return s.check(scheduleID, mysqlServerID, startTime, endTime, step, loginName, nil)
 }
 
 // CheckByHostInfo performs healthcheck on the mysql server with given mysql server id,
 // initiating is synchronous, actual running is asynchronous
-func (s *Service) CheckByHostInfo(hostIP string, portNum int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error) {
+func (s *Service) CheckByHostInfo(hostIP string, portNum int, startTime, endTime time.Time, step time.Duration, loginName string,
+	callbackURLs []string) (int, error) {
 	// init mysql server service
 	mss := metadata.NewMySQLServerServiceWithDefault()
 	// get entities
@@ -136,204 +377,961 @@ func (s *Service) CheckByHostInfo(hostIP string, portNum int, startTime, endTime
 	}
 	mysqlServerID := mss.GetMySQLServers()[constant.ZeroInt].Identity()
 
-	return s.check(mysqlServerID, startTime, endTime, step, loginName)
+	return s.check(constant.ZeroInt, mysqlServerID, startTime, endTime, step, loginName, callbackURLs)
 }
 
-// check performs healthcheck on the mysql server with given mysql server id,
+// CheckWithSnapshot performs healthcheck on the mysql server of the snapshot with the inputs of the snapshot,
+// it does not connect to the data sources of the mysql server, the mysql server must exist in the metadata,
+// the operation does not wait in the queue of the executor as it does not connect to the data sources,
 // initiating is synchronous, actual running is asynchronous
-func (s *Service) check(mysqlServerID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error) {
-	// get user
-	userService := metadata.NewUserServiceWithDefault()
-	err := userService.GetByAccountNameOrEmployeeID(loginName)
-	if err != nil {
-		return constant.ZeroInt, err
-	}
-	// check privilege
-	privilegeService := privilege.NewServiceWithDefault(loginName)
-	err = privilegeService.CheckMySQLServerByID(mysqlServerID)
+func (s *Service) CheckWithSnapshot(snapshotData []byte, loginName string) (int, error) {
+	snapshot, err := NewSnapshotWithJSON(snapshotData)
 	if err != nil {
 		return constant.ZeroInt, err
 	}
 	// get mysql server
 	mysqlServerService := metadata.NewMySQLServerServiceWithDefault()
-	err = mysqlServerService.GetByID(mysqlServerID)
+	err = mysqlServerService.GetByHostInfo(snapshot.GetHostIP(), snapshot.GetPortNum())
 	if err != nil {
 		return constant.ZeroInt, err
 	}
-	mysqlServer := mysqlServerService.GetMySQLServers()[constant.ZeroInt]
-	// init
-	operationID, err := s.init(userService.GetUsers()[constant.ZeroInt], mysqlServer, startTime, endTime, step)
-	if err != nil {
-		updateErr := s.GetDASRepo().UpdateOperationStatus(operationID, defaultFailedStatus, err.Error())
-		if updateErr != nil {
-			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
-		}
-
-		return operationID, err
-	}
-	// run asynchronously
-	go s.GetEngine().Run()
-
-	return operationID, nil
-}
+	mysqlServerID := mysqlServerService.GetMySQLServers()[constant.ZeroInt].Identity()
 
-// init initiates healthcheck operation and engine
-func (s *Service) init(user depmeta.User, mysqlServer depmeta.MySQLServer, startTime, endTime time.Time, step time.Duration) (int, error) {
-	// insert operation message
-	operationID, err := s.GetDASRepo().InitOperation(
-		user.Identity(),
-		mysqlServer.Identity(),
-		startTime,
-		endTime,
-		step,
-	)
+	user, mysqlServer, operationID, err := s.prepare(constant.ZeroInt, constant.ZeroInt, mysqlServerID,
+		snapshot.GetStartTime(), snapshot.GetEndTime(), snapshot.GetStep(), loginName)
 	if err != nil {
 		return operationID, err
 	}
-	// check if operation with the same mysql server id is still running
-	isRunning, err := s.GetDASRepo().IsRunning(mysqlServer.Identity())
+	ctx, cancel := s.newOperationContext(operationID)
+	err = s.init(ctx, cancel, user, mysqlServer, operationID, snapshot.GetStartTime(), snapshot.GetEndTime(), snapshot.GetStep(), snapshot)
 	if err != nil {
+		runningOperations.release(operationID)
+		s.fail(operationID, err)
 		return operationID, err
 	}
-	if isRunning {
-		return operationID, errors.Errorf("healthcheck of mysql server is still running. mysql server id: %d", mysqlServer.Identity())
-	}
-	// get monitor system
-	monitorSystem, err := mysqlServer.GetMonitorSystem()
+	// update status to running, so that the other operations of the same mysql server will not be started
+	err = s.GetDASRepo().UpdateOperationStatus(operationID, defaultRunningStatus, fmt.Sprintf("healthcheck started. operation_id: %d", operationID))
 	if err != nil {
+		// the engine will not run, release the context of the operation
+		runningOperations.release(operationID)
 		return operationID, err
 	}
-	// get mysql cluster
-	mysqlCluster, err := mysqlServer.GetMySQLCluster()
+	// run asynchronously
+	go s.GetEngine().Run()
+
+	return operationID, nil
+}
+
+// check performs healthcheck on the mysql server with given mysql server id,
+// the operations triggered by the scheduler have lower priority than the manual ones,
+// the callback urls will be notified when the operation stops,
+// initiating is synchronous, actual running is asynchronous
+func (s *Service) check(scheduleID, mysqlServerID int, startTime, endTime time.Time, step time.Duration, loginName string,
+	callbackURLs []string) (int, error) {
+	err := validateCallbackURLsWithConfig(callbackURLs)
 	if err != nil {
-		return operationID, err
+		return constant.ZeroInt, err
 	}
-	// get dbs
-	dbs, err := mysqlCluster.GetDBs()
+	priority := defaultManualPriority
+	if scheduleID != constant.ZeroInt {
+		priority = defaultScheduledPriority
+	}
+	operationID, _, err := s.submit(scheduleID, constant.ZeroInt, mysqlServerID, startTime, endTime, step, priority, loginName, callbackURLs)
+
+	return operationID, err
+}
+
+// submit prepares the operation of the mysql server with given mysql server id, and then puts it into the queue of the executor,
+// the engine will be initiated and run after the operation is dequeued, so the queued operations do not connect to the data sources,
+// the callback urls are saved before queueing, so that they could be notified even if the process was restarted,
+// it returns a channel which will be closed after the operation stops
+func (s *Service) submit(scheduleID, clusterOperationID, mysqlServerID int, startTime, endTime time.Time, step time.Duration,
+	priority int, loginName string, callbackURLs []string) (int, <-chan struct{}, error) {
+	user, mysqlServer, operationID, err := s.prepare(scheduleID, clusterOperationID, mysqlServerID, startTime, endTime, step, loginName)
 	if err != nil {
-		return operationID, err
+		return operationID, nil, err
 	}
-	// get apps
-	var apps []depmeta.App
-	for _, db := range dbs {
-		applications, err := db.GetApps()
+	if len(callbackURLs) > constant.ZeroInt {
+		err = s.GetDASRepo().SaveCallbackURLs(operationID, callbackURLs)
 		if err != nil {
-			return operationID, err
-		}
-		for _, application := range applications {
-			exists, err := common.ElementInSlice(apps, application)
-			if err != nil {
-				return operationID, err
-			}
-			if !exists {
-				apps = append(apps, applications...)
-			}
+			s.fail(operationID, err)
+			return operationID, nil, err
 		}
 	}
-	// init operation information
-	s.OperationInfo = NewOperationInfo(operationID, user, apps, mysqlServer, monitorSystem, startTime, endTime, step)
-
-	// init application mysql connection
-	mysqlServerAddr := fmt.Sprintf("%s:%d", mysqlServer.GetHostIP(), mysqlServer.GetPortNum())
-	applicationMySQLConn, err := mysql.NewConn(mysqlServerAddr, constant.EmptyString, s.getApplicationMySQLUser(), s.getApplicationMySQLPass())
+	err = s.GetDASRepo().QueueOperation(operationID, priority)
 	if err != nil {
-		return operationID, message.NewMessage(
-			msghc.ErrHealthcheckCreateApplicationMySQLConnection, err, mysqlServerAddr, s.getApplicationMySQLUser())
+		s.fail(operationID, err)
+		return operationID, nil, err
 	}
-	// init application mysql repository
-	applicationMySQLRepo := NewApplicationMySQLRepo(s.GetOperationInfo(), applicationMySQLConn)
-
-	var (
-		prometheusConfig prometheus.Config
-		queryRepo        healthcheck.QueryRepo
-	)
+	done := getExecutor().submit(operationID, priority, func() {
+		s.start(user, mysqlServer, operationID, startTime, endTime, step)
+	})
 
-	prometheusAddr := fmt.Sprintf("%s:%d%s", monitorSystem.GetHostIP(), monitorSystem.GetPortNum(), monitorSystem.GetBaseURL())
-	slowQueryAddr := fmt.Sprintf("%s:%d", monitorSystem.GetHostIP(), monitorSystem.GetPortNumSlow())
+	return operationID, done, nil
+}
 
-	switch monitorSystem.GetSystemType() {
-	case 1:
-		// pmm 1.x
-		// init prometheus config
-		prometheusConfig = prometheus.NewConfig(prometheusAddr, prometheus.DefaultRoundTripper)
-		// init mysql connection
-		conn, err := mysql.NewConn(slowQueryAddr, defaultMonitorMySQLDBName, s.getMonitorMySQLUser(), s.getMonitorMySQLPass())
-		if err != nil {
-			return operationID, message.NewMessage(
-				msghc.ErrHealthcheckCreateMonitorMySQLConnection, err, slowQueryAddr, s.getMonitorMySQLUser())
-		}
-		queryRepo = NewMySQLQueryRepo(s.GetOperationInfo(), conn)
-	case 2:
-		// pmm 2.x
-		// init prometheus config
-		prometheusConfig = prometheus.NewConfigWithBasicAuth(prometheusAddr, s.getMonitorPrometheusUser(), s.getMonitorPrometheusPass())
-		// init clickhouse connection
-		conn, err := clickhouse.NewConnWithDefault(slowQueryAddr, defaultMonitorClickhouseDBName, s.getMonitorClickhouseUser(), s.getMonitorClickhousePass())
-		if err != nil {
-			return operationID, message.NewMessage(
-				msghc.ErrHealthcheckCreateMonitorClickhouseConnection, err, slowQueryAddr, s.getMonitorClickhouseUser())
-		}
-		queryRepo = NewClickhouseQueryRepo(s.GetOperationInfo(), conn)
-	default:
-		return operationID, fmt.Errorf("healthcheck: monitor system type should be either 1 or 2, %d is not valid", monitorSystem.GetSystemType())
+// start marks the queued operation as running, and then initiates and runs the engine of it,
+// it is called by the worker of the executor, the operation will be skipped if it is not queued any more
+func (s *Service) start(user depmeta.User, mysqlServer depmeta.MySQLServer, operationID int, startTime, endTime time.Time, step time.Duration) {
+	// the operation must be registered before it is marked as running,
+	// otherwise, it could not be cancelled until the engine is initiated
+	ctx, cancel := s.newOperationContext(operationID)
+	ok, err := s.GetDASRepo().StartQueuedOperation(operationID)
+	if err != nil {
+		runningOperations.release(operationID)
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckCheck, err, operationID))
+		s.fail(operationID, err)
+		return
 	}
-
-	prometheusConn, err := prometheus.NewConnWithConfig(prometheusConfig)
+	if !ok {
+		runningOperations.release(operationID)
+		log.Warnf("healthcheck operation is not queued any more, skip it. operation_id: %d", operationID)
+		return
+	}
+	err = s.init(ctx, cancel, user, mysqlServer, operationID, startTime, endTime, step, nil)
 	if err != nil {
-		return operationID, message.NewMessage(
-			msghc.ErrHealthcheckCreateMonitorPrometheusConnection, err, prometheusAddr, s.getMonitorPrometheusUser())
+		runningOperations.release(operationID)
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckCheck, err, operationID))
+		s.failRunning(operationID, err)
+		return
 	}
-	prometheusRepo := NewPrometheusRepo(s.GetOperationInfo(), prometheusConn)
-	s.Engine = NewDefaultEngine(s.GetOperationInfo(), s.GetDASRepo(), applicationMySQLRepo, prometheusRepo, queryRepo)
 
-	return operationID, nil
+	s.GetEngine().Run()
 }
 
-// getApplicationMySQLUser returns application mysql username
-func (s *Service) getApplicationMySQLUser() string {
-	return viper.GetString(config.DBApplicationMySQLUserKey)
-}
+// newOperationContext returns the context of the operation which times out after the healthcheck timeout,
+// the cancel function of the context is registered, so that the operation could be cancelled before it finishes
+func (s *Service) newOperationContext(operationID int) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.getTimeout())
+	runningOperations.register(operationID, cancel)
 
-// getApplicationMySQLPass returns application mysql password
-func (s *Service) getApplicationMySQLPass() string {
-	return viper.GetString(config.DBApplicationMySQLPassKey)
+	return ctx, cancel
 }
 
-// getMonitorPrometheusUser returns prometheus username of monitor system
-func (s *Service) getMonitorPrometheusUser() string {
-	return viper.GetString(config.DBMonitorPrometheusUserKey)
-}
+// prepare checks the privilege and initiates the operation of the mysql server with given mysql server id,
+// it returns the user and the mysql server of the operation, it does not initiate the engine,
+// if the operation was initiated but could not be started, it will be marked as failed
+func (s *Service) prepare(scheduleID, clusterOperationID, mysqlServerID int, startTime, endTime time.Time, step time.Duration,
+	loginName string) (depmeta.User, depmeta.MySQLServer, int, error) {
+	// get user
+	userService := metadata.NewUserServiceWithDefault()
+	err := userService.GetByAccountNameOrEmployeeID(loginName)
+	if err != nil {
+		return nil, nil, constant.ZeroInt, err
+	}
+	user := userService.GetUsers()[constant.ZeroInt]
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	err = privilegeService.CheckMySQLServerByID(mysqlServerID)
+	if err != nil {
+		return nil, nil, constant.ZeroInt, err
+	}
+	// get mysql server
+	mysqlServerService := metadata.NewMySQLServerServiceWithDefault()
+	err = mysqlServerService.GetByID(mysqlServerID)
+	if err != nil {
+		return nil, nil, constant.ZeroInt, err
+	}
+	mysqlServer := mysqlServerService.GetMySQLServers()[constant.ZeroInt]
+	// insert operation message
+	operationID, err := s.GetDASRepo().InitOperation(user.Identity(), scheduleID, clusterOperationID, mysqlServerID, startTime, endTime, step)
+	if err != nil {
+		return nil, nil, operationID, err
+	}
+	// check if operation with the same mysql server id is still running or queued
+	isRunning, err := s.GetDASRepo().IsRunning(mysqlServerID)
+	if err != nil {
+		s.fail(operationID, err)
+		return nil, nil, operationID, err
+	}
+	if isRunning {
+		err = errors.Errorf("healthcheck of mysql server is still running. mysql server id: %d", mysqlServerID)
+		s.fail(operationID, err)
+		return nil, nil, operationID, err
+	}
 
-// getMonitorPrometheusPass returns prometheus password of monitor system
-func (s *Service) getMonitorPrometheusPass() string {
-	return viper.GetString(config.DBMonitorPrometheusPassKey)
+	return user, mysqlServer, operationID, nil
 }
 
-// getMonitorClickhouseUser returns clickhouse username of monitor system
-func (s *Service) getMonitorClickhouseUser() string {
-	return viper.GetString(config.DBMonitorClickhouseUserKey)
-}
+// fail marks the operation as failed with the message of err, and then notifies the callback urls of it
+func (s *Service) fail(operationID int, err error) {
+	updateErr := s.GetDASRepo().UpdateOperationStatus(operationID, defaultFailedStatus, err.Error())
+	if updateErr != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+		return
+	}
 
-// getMonitorClickhousePass returns clickhouse password of monitor system
-func (s *Service) getMonitorClickhousePass() string {
-	return viper.GetString(config.DBMonitorClickhousePassKey)
+	go notifyCallbacks(s.GetDASRepo(), operationID)
 }
 
-// getMonitorMySQLUser returns mysql username of monitor system
-func (s *Service) getMonitorMySQLUser() string {
-	return viper.GetString(config.DBMonitorMySQLUserKey)
+// failRunning marks the running operation as failed with the message of err, and then notifies the callback urls of it,
+// the operation will not be marked if it is not running any more, e.g. it was cancelled
+func (s *Service) failRunning(operationID int, err error) {
+	ok, updateErr := s.GetDASRepo().CompareAndUpdateOperationStatus(operationID, defaultRunningStatus, defaultFailedStatus, err.Error())
+	if updateErr != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+		return
+	}
+	if !ok {
+		return
+	}
+
+	go notifyCallbacks(s.GetDASRepo(), operationID)
 }
 
-// getMonitorMySQLPass returns mysql password of monitor system
-func (s *Service) getMonitorMySQLPass() string {
+// CheckCluster performs healthcheck on all the mysql servers of the mysql cluster with given mysql cluster id,
+// each mysql server will be checked by its own operation which waits in the queue of the executor,
+// and all of them are tracked under a cluster operation,
+// after all the operations completed, a combined cluster result will be saved,
+// initiating is synchronous, actual running is asynchronous
+func (s *Service) CheckCluster(mysqlClusterID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error) {
+	// get user
+	userService := metadata.NewUserServiceWithDefault()
+	err := userService.GetByAccountNameOrEmployeeID(loginName)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	// get mysql servers
+	mysqlClusterService := metadata.NewMySQLClusterServiceWithDefault()
+	err = mysqlClusterService.GetMySQLServersByID(mysqlClusterID)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	mysqlServers := mysqlClusterService.GetMySQLServers()
+	if len(mysqlServers) == constant.ZeroInt {
+		return constant.ZeroInt, errors.Errorf("healthcheck: there is no mysql server in the mysql cluster. mysql cluster id: %d", mysqlClusterID)
+	}
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	for _, mysqlServer := range mysqlServers {
+		err = privilegeService.CheckMySQLServerByID(mysqlServer.Identity())
+		if err != nil {
+			return constant.ZeroInt, err
+		}
+	}
+	// get master servers
+	err = mysqlClusterService.GetMasterServersByID(mysqlClusterID)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	var masterServerIDList []int
+	for _, masterServer := range mysqlClusterService.GetMySQLServers() {
+		masterServerIDList = append(masterServerIDList, masterServer.Identity())
+	}
+	// init cluster operation
+	clusterOperationID, err := s.GetDASRepo().InitClusterOperation(userService.GetUsers()[constant.ZeroInt].Identity(), mysqlClusterID, startTime, endTime, step)
+	if err != nil {
+		return clusterOperationID, err
+	}
+	err = s.GetDASRepo().UpdateClusterOperationStatus(clusterOperationID, defaultRunningStatus,
+		fmt.Sprintf("cluster healthcheck started. cluster_operation_id: %d", clusterOperationID))
+	if err != nil {
+		return clusterOperationID, err
+	}
+	// init the engine of each mysql server
+	clusterEngine := NewClusterEngine(clusterOperationID, mysqlClusterID, masterServerIDList, s.GetDASRepo())
+	for _, mysqlServer := range mysqlServers {
+		nodeService := newService(s.GetDASRepo())
+		operationID, done, err := nodeService.submit(constant.ZeroInt, clusterOperationID, mysqlServer.Identity(),
+			startTime, endTime, step, defaultManualPriority, loginName, nil)
+		if err != nil {
+			// the failure of one mysql server should not stop checking the others,
+			// it will be recorded in the cluster result
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckCheck, err, operationID))
+			clusterEngine.AddNode(mysqlServer, operationID, nil, err)
+			continue
+		}
+		clusterEngine.AddNode(mysqlServer, operationID, done, nil)
+	}
+	// run asynchronously
+	go clusterEngine.Run()
+
+	return clusterOperationID, nil
+}
+
+// CheckMiddlewareCluster performs healthcheck on all the middleware servers of the middleware cluster with given middleware cluster id,
+// each middleware server will be probed for liveness and its resource usages will be got from the monitor system of the linked mysql clusters,
+// so all the linked mysql clusters must use the same monitor system,
+// and each mysql cluster which is linked to the middleware cluster should have at least one alive read-write and read-only endpoint,
+// initiating is synchronous, actual running is asynchronous
+func (s *Service) CheckMiddlewareCluster(middlewareClusterID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error) {
+	// get user
+	userService := metadata.NewUserServiceWithDefault()
+	err := userService.GetByAccountNameOrEmployeeID(loginName)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	// get middleware servers
+	middlewareClusterService := metadata.NewMiddlewareClusterServiceWithDefault()
+	err = middlewareClusterService.GetMiddlewareServersByID(middlewareClusterID)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	middlewareServers := middlewareClusterService.GetMiddlewareServers()
+	if len(middlewareServers) == constant.ZeroInt {
+		return constant.ZeroInt, errors.Errorf("healthcheck: there is no middleware server in the middleware cluster. middleware cluster id: %d", middlewareClusterID)
+	}
+	// get linked mysql clusters
+	mysqlClusterIDList, err := s.GetDASRepo().GetMySQLClusterIDsByMiddlewareClusterID(middlewareClusterID)
+	if err != nil {
+		return constant.ZeroInt, err
+	}
+	if len(mysqlClusterIDList) == constant.ZeroInt {
+		return constant.ZeroInt, errors.Errorf("healthcheck: there is no mysql cluster linked to the middleware cluster. middleware cluster id: %d", middlewareClusterID)
+	}
+	// check privilege, the user should have the privileges of all the mysql servers behind the middleware cluster
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	mysqlClusters := make([]depmeta.MySQLCluster, len(mysqlClusterIDList))
+	for i, mysqlClusterID := range mysqlClusterIDList {
+		mysqlClusterService := metadata.NewMySQLClusterServiceWithDefault()
+		err = mysqlClusterService.GetByID(mysqlClusterID)
+		if err != nil {
+			return constant.ZeroInt, err
+		}
+		mysqlClusters[i] = mysqlClusterService.GetMySQLClusters()[constant.ZeroInt]
+		// the resource usages of the middleware servers are got from the monitor system of the linked mysql clusters,
+		// so it is ambiguous which one should be used if the linked mysql clusters use different monitor systems
+		if mysqlClusters[i].GetMonitorSystemID() != mysqlClusters[constant.ZeroInt].GetMonitorSystemID() {
+			return constant.ZeroInt, errors.Errorf("healthcheck: the mysql clusters linked to the middleware cluster should use the same monitor system. "+
+				"middleware cluster id: %d, mysql cluster id: %d, monitor system id: %d, mysql cluster id: %d, monitor system id: %d",
+				middlewareClusterID, mysqlClusters[constant.ZeroInt].Identity(), mysqlClusters[constant.ZeroInt].GetMonitorSystemID(),
+				mysqlClusters[i].Identity(), mysqlClusters[i].GetMonitorSystemID())
+		}
+		mysqlServers, err := mysqlClusters[i].GetMySQLServers()
+		if err != nil {
+			return constant.ZeroInt, err
+		}
+		for _, mysqlServer := range mysqlServers {
+			err = privilegeService.CheckMySQLServerByID(mysqlServer.Identity())
+			if err != nil {
+				return constant.ZeroInt, err
+			}
+		}
+	}
+	// init middleware operation
+	middlewareOperationID, err := s.GetDASRepo().InitMiddlewareOperation(userService.GetUsers()[constant.ZeroInt].Identity(), middlewareClusterID, startTime, endTime, step)
+	if err != nil {
+		return middlewareOperationID, err
+	}
+	// check if the middleware operation with the same middleware cluster id is still running
+	isRunning, err := s.GetDASRepo().IsMiddlewareRunning(middlewareClusterID)
+	if err != nil {
+		s.failMiddleware(middlewareOperationID, err)
+		return middlewareOperationID, err
+	}
+	if isRunning {
+		err = errors.Errorf("healthcheck of middleware cluster is still running. middleware cluster id: %d", middlewareClusterID)
+		s.failMiddleware(middlewareOperationID, err)
+		return middlewareOperationID, err
+	}
+	err = s.GetDASRepo().UpdateMiddlewareOperationStatus(middlewareOperationID, defaultRunningStatus,
+		fmt.Sprintf("middleware healthcheck started. middleware_operation_id: %d", middlewareOperationID))
+	if err != nil {
+		return middlewareOperationID, err
+	}
+	// init prometheus repository with the monitor system shared by the linked mysql clusters,
+	// the liveness and the endpoints could still be checked if the monitor system is not available,
+	// the middleware operation could not be cancelled, it stops when it times out
+	ctx, cancel := context.WithTimeout(context.Background(), s.getTimeout())
+	prometheusRepo, err := s.initMiddlewarePrometheusRepo(ctx, mysqlClusters[constant.ZeroInt], startTime, endTime, step)
+	if err != nil {
+		log.Warnf("healthcheck Service.CheckMiddlewareCluster(): init prometheus repository failed, resource usages will not be checked. middleware_operation_id: %d\n%+v",
+			middlewareOperationID, err)
+	}
+	middlewareEngine := NewMiddlewareEngine(ctx, middlewareOperationID, middlewareClusterID, middlewareServers, mysqlClusters, s.GetDASRepo(), prometheusRepo)
+	// run asynchronously
+	go func() {
+		defer cancel()
+		middlewareEngine.Run()
+	}()
+
+	return middlewareOperationID, nil
+}
+
+// failMiddleware marks the middleware operation as failed with the message of err
+func (s *Service) failMiddleware(middlewareOperationID int, err error) {
+	updateErr := s.GetDASRepo().UpdateMiddlewareOperationStatus(middlewareOperationID, defaultFailedStatus, err.Error())
+	if updateErr != nil {
+		log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckUpdateOperationStatus, updateErr))
+	}
+}
+
+// initMiddlewarePrometheusRepo initiates the connection to the prometheus of the monitor system of the mysql cluster,
+// which is shared by all the mysql clusters linked to the middleware cluster,
+// and returns the prometheus repository of the middleware servers
+func (s *Service) initMiddlewarePrometheusRepo(ctx context.Context, mysqlCluster depmeta.MySQLCluster,
+	startTime, endTime time.Time, step time.Duration) (healthcheck.MiddlewarePrometheusRepo, error) {
+	// get monitor system
+	monitorSystemService := metadata.NewMonitorSystemServiceWithDefault()
+	err := monitorSystemService.GetByID(mysqlCluster.GetMonitorSystemID())
+	if err != nil {
+		return nil, err
+	}
+	monitorSystem := monitorSystemService.GetMonitorSystems()[constant.ZeroInt]
+
+	var prometheusConfig prometheus.Config
+	prometheusAddr := fmt.Sprintf("%s:%d%s", monitorSystem.GetHostIP(), monitorSystem.GetPortNum(), monitorSystem.GetBaseURL())
+	switch monitorSystem.GetSystemType() {
+	case 1:
+		// pmm 1.x
+		prometheusConfig = prometheus.NewConfig(prometheusAddr, prometheus.DefaultRoundTripper)
+	case 2:
+		// pmm 2.x
+		prometheusConfig = prometheus.NewConfigWithBasicAuth(prometheusAddr, s.getMonitorPrometheusUser(), s.getMonitorPrometheusPass())
+	case 3:
+		// generic prometheus
+		prometheusConfig = prometheus.NewConfigWithBasicAuth(prometheusAddr, s.getMonitorPrometheusUser(), s.getMonitorPrometheusPass())
+	default:
+		return nil, fmt.Errorf("healthcheck: monitor system type should be one of 1, 2 or 3, %d is not valid", monitorSystem.GetSystemType())
+	}
+
+	prometheusConn, err := prometheus.NewConnWithConfig(prometheusConfig)
+	if err != nil {
+		return nil, message.NewMessage(
+			msghc.ErrHealthcheckCreateMonitorPrometheusConnection, err, prometheusAddr, s.getMonitorPrometheusUser())
+	}
+
+	// init promql renderer
+	renderer, err := NewPromQLRendererWithGlobal(monitorSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMiddlewarePrometheusRepo(ctx, prometheusConn, startTime, endTime, step, renderer), nil
+}
+
+// init initiates the engine of the operation, ctx is the context of the operation, the repositories and the engine share it,
+// so that the running query of the operation is killed when it times out or is cancelled,
+// if snapshot is not nil, the engine reads the inputs from the snapshot, otherwise, it connects to the data sources
+func (s *Service) init(ctx context.Context, cancel context.CancelFunc, user depmeta.User, mysqlServer depmeta.MySQLServer,
+	operationID int, startTime, endTime time.Time, step time.Duration, snapshot *Snapshot) error {
+	// get monitor system
+	monitorSystem, err := mysqlServer.GetMonitorSystem()
+	if err != nil {
+		return err
+	}
+	// get apps
+	apps, err := s.getApps(mysqlServer)
+	if err != nil {
+		return err
+	}
+	// init operation information
+	s.OperationInfo = NewOperationInfo(operationID, user, apps, mysqlServer, monitorSystem, startTime, endTime, step)
+	// init repositories
+	var (
+		applicationMySQLRepo healthcheck.ApplicationMySQLRepo
+		prometheusRepo       healthcheck.PrometheusRepo
+		queryRepo            healthcheck.QueryRepo
+	)
+	if snapshot != nil {
+		applicationMySQLRepo = NewSnapshotApplicationMySQLRepo(snapshot)
+		prometheusRepo = NewSnapshotPrometheusRepo(snapshot)
+		queryRepo = NewSnapshotQueryRepo(snapshot)
+	} else {
+		applicationMySQLRepo, prometheusRepo, queryRepo, err = s.initRepos(ctx, mysqlServer, monitorSystem)
+		if err != nil {
+			return err
+		}
+	}
+	engine := newDefaultEngine(ctx, cancel, s.GetOperationInfo(), s.GetDASRepo(), applicationMySQLRepo, prometheusRepo, queryRepo)
+	if snapshot == nil && viper.GetBool(config.HealthcheckSnapshotEnabledKey) {
+		engine.recordSnapshot()
+	}
+	s.Engine = engine
+
+	return nil
+}
+
+// initRepos initiates the connections to the data sources of the mysql server, and returns the repositories of them,
+// the running queries of the repositories are killed when ctx is done
+func (s *Service) initRepos(ctx context.Context, mysqlServer depmeta.MySQLServer, monitorSystem depmeta.MonitorSystem) (
+	healthcheck.ApplicationMySQLRepo, healthcheck.PrometheusRepo, healthcheck.QueryRepo, error) {
+	// init application mysql connection
+	mysqlServerAddr := fmt.Sprintf("%s:%d", mysqlServer.GetHostIP(), mysqlServer.GetPortNum())
+	applicationMySQLConn, err := mysql.NewConn(mysqlServerAddr, constant.EmptyString, s.getApplicationMySQLUser(), s.getApplicationMySQLPass())
+	if err != nil {
+		return nil, nil, nil, message.NewMessage(
+			msghc.ErrHealthcheckCreateApplicationMySQLConnection, err, mysqlServerAddr, s.getApplicationMySQLUser())
+	}
+	// init application mysql repository
+	applicationMySQLRepo := NewApplicationMySQLRepo(ctx, s.GetOperationInfo(), applicationMySQLConn)
+	var queryRepo healthcheck.QueryRepo
+	// close the opened connections if any of the following initiations failed
+	defer func() {
+		if err != nil {
+			s.closeRepos(applicationMySQLRepo, queryRepo)
+		}
+	}()
+
+	var prometheusConfig prometheus.Config
+
+	prometheusAddr := fmt.Sprintf("%s:%d%s", monitorSystem.GetHostIP(), monitorSystem.GetPortNum(), monitorSystem.GetBaseURL())
+
+	switch monitorSystem.GetSystemType() {
+	case 1:
+		// pmm 1.x
+		prometheusConfig = prometheus.NewConfig(prometheusAddr, prometheus.DefaultRoundTripper)
+	case 2, 3:
+		// pmm 2.x and generic prometheus
+		prometheusConfig = prometheus.NewConfigWithBasicAuth(prometheusAddr, s.getMonitorPrometheusUser(), s.getMonitorPrometheusPass())
+	default:
+		err = fmt.Errorf("healthcheck: monitor system type should be one of 1, 2 or 3, %d is not valid", monitorSystem.GetSystemType())
+		return nil, nil, nil, err
+	}
+	// init query repository
+	queryRepo, err = s.initQueryRepo(ctx, mysqlServer, monitorSystem)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	prometheusConn, err := prometheus.NewConnWithConfig(prometheusConfig)
+	if err != nil {
+		err = message.NewMessage(msghc.ErrHealthcheckCreateMonitorPrometheusConnection, err, prometheusAddr, s.getMonitorPrometheusUser())
+		return nil, nil, nil, err
+	}
+	// init promql renderer
+	renderer, err := NewPromQLRendererWithGlobal(monitorSystem)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	prometheusRepo := NewPrometheusRepo(ctx, s.GetOperationInfo(), prometheusConn, renderer)
+
+	return applicationMySQLRepo, prometheusRepo, queryRepo, nil
+}
+
+// closeRepos closes the connections of given repositories, the repositories which are nil will be skipped,
+// as it is only used to release the resources when the initiation failed, the errors are logged only
+func (s *Service) closeRepos(applicationMySQLRepo healthcheck.ApplicationMySQLRepo, queryRepo healthcheck.QueryRepo) {
+	if applicationMySQLRepo != nil {
+		err := applicationMySQLRepo.Close()
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckCloseConnection, err))
+		}
+	}
+	if queryRepo != nil {
+		err := queryRepo.Close()
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckCloseConnection, err))
+		}
+	}
+}
+
+// initQueryRepo initiates the connection to the slow query source of the mysql server, and returns the repository of it,
+// the slow log statistics ingested from the slow log files are used only if the ingested slow log covers the whole check time range,
+// otherwise, the slow queries are got from the query analytics of the monitor system
+func (s *Service) initQueryRepo(ctx context.Context, mysqlServer depmeta.MySQLServer, monitorSystem depmeta.MonitorSystem) (healthcheck.QueryRepo, error) {
+	covered, err := query.NewSlowLogRepoWithGlobal(query.NewConfigWithDefault()).Covers(mysqlServer.Identity(),
+		s.GetOperationInfo().GetStartTime(), s.GetOperationInfo().GetEndTime())
+	if err != nil {
+		return nil, err
+	}
+	if covered {
+		return NewSlowLogQueryRepo(ctx, s.GetOperationInfo(), s.GetDASRepo()), nil
+	}
+
+	slowQueryAddr := fmt.Sprintf("%s:%d", monitorSystem.GetHostIP(), monitorSystem.GetPortNumSlow())
+
+	switch monitorSystem.GetSystemType() {
+	case 1:
+		// pmm 1.x
+		conn, err := mysql.NewConn(slowQueryAddr, defaultMonitorMySQLDBName, s.getMonitorMySQLUser(), s.getMonitorMySQLPass())
+		if err != nil {
+			return nil, message.NewMessage(msghc.ErrHealthcheckCreateMonitorMySQLConnection, err, slowQueryAddr, s.getMonitorMySQLUser())
+		}
+
+		return NewMySQLQueryRepo(ctx, s.GetOperationInfo(), conn), nil
+	case 2:
+		// pmm 2.x
+		conn, err := clickhouse.NewConnWithDefault(slowQueryAddr, defaultMonitorClickhouseDBName, s.getMonitorClickhouseUser(), s.getMonitorClickhousePass())
+		if err != nil {
+			return nil, message.NewMessage(msghc.ErrHealthcheckCreateMonitorClickhouseConnection, err, slowQueryAddr, s.getMonitorClickhouseUser())
+		}
+
+		return NewClickhouseQueryRepo(ctx, s.GetOperationInfo(), conn), nil
+	case 3:
+		// generic prometheus
+		// the slow queries are read from the performance_schema of the application mysql server
+		mysqlServerAddr := fmt.Sprintf("%s:%d", mysqlServer.GetHostIP(), mysqlServer.GetPortNum())
+		conn, err := mysql.NewConn(mysqlServerAddr, performanceSchema, s.getApplicationMySQLUser(), s.getApplicationMySQLPass())
+		if err != nil {
+			return nil, message.NewMessage(msghc.ErrHealthcheckCreateApplicationMySQLConnection, err, mysqlServerAddr, s.getApplicationMySQLUser())
+		}
+
+		return NewPerformanceSchemaQueryRepo(ctx, s.GetOperationInfo(), conn), nil
+	default:
+		return nil, fmt.Errorf("healthcheck: monitor system type should be one of 1, 2 or 3, %d is not valid", monitorSystem.GetSystemType())
+	}
+}
+
+// getApps returns the apps which use the dbs of the mysql cluster of given mysql server
+func (s *Service) getApps(mysqlServer depmeta.MySQLServer) ([]depmeta.App, error) {
+	// get mysql cluster
+	mysqlCluster, err := mysqlServer.GetMySQLCluster()
+	if err != nil {
+		return nil, err
+	}
+	// get dbs
+	dbs, err := mysqlCluster.GetDBs()
+	if err != nil {
+		return nil, err
+	}
+	// get apps
+	var apps []depmeta.App
+	for _, db := range dbs {
+		applications, err := db.GetApps()
+		if err != nil {
+			return nil, err
+		}
+		for _, application := range applications {
+			exists, err := common.ElementInSlice(apps, application)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				apps = append(apps, application)
+			}
+		}
+	}
+
+	return apps, nil
+}
+
+// getTimeout returns the timeout of the operation
+func (s *Service) getTimeout() time.Duration {
+	return time.Duration(viper.GetInt(config.HealthcheckTimeoutKey)) * time.Second
+}
+
+// getApplicationMySQLUser returns application mysql username
+func (s *Service) getApplicationMySQLUser() string {
+	return viper.GetString(config.DBApplicationMySQLUserKey)
+}
+
+// getApplicationMySQLPass returns application mysql password
+func (s *Service) getApplicationMySQLPass() string {
+	return viper.GetString(config.DBApplicationMySQLPassKey)
+}
+
+// getMonitorPrometheusUser returns prometheus username of monitor system
+func (s *Service) getMonitorPrometheusUser() string {
+	return viper.GetString(config.DBMonitorPrometheusUserKey)
+}
+
+// getMonitorPrometheusPass returns prometheus password of monitor system
+func (s *Service) getMonitorPrometheusPass() string {
+	return viper.GetString(config.DBMonitorPrometheusPassKey)
+}
+
+// getMonitorClickhouseUser returns clickhouse username of monitor system
+func (s *Service) getMonitorClickhouseUser() string {
+	return viper.GetString(config.DBMonitorClickhouseUserKey)
+}
+
+// getMonitorClickhousePass returns clickhouse password of monitor system
+func (s *Service) getMonitorClickhousePass() string {
+	return viper.GetString(config.DBMonitorClickhousePassKey)
+}
+
+// getMonitorMySQLUser returns mysql username of monitor system
+func (s *Service) getMonitorMySQLUser() string {
+	return viper.GetString(config.DBMonitorMySQLUserKey)
+}
+
+// getMonitorMySQLPass returns mysql password of monitor system
+func (s *Service) getMonitorMySQLPass() string {
 	return viper.GetString(config.DBMonitorMySQLPassKey)
 }
 
+// Cancel cancels the running or queued operation with given operation id,
+// if the operation is not running in this process(e.g. the process was restarted), it will only be marked as cancelled
+func (s *Service) Cancel(operationID int, loginName string) error {
+	operationHistory, err := s.GetDASRepo().GetOperationHistoryByID(operationID)
+	if err != nil {
+		return err
+	}
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	err = privilegeService.CheckMySQLServerByID(operationHistory.GetMySQLServerID())
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("healthcheck was cancelled. operation_id: %d, login_name: %s", operationID, loginName)
+	switch operationHistory.GetStatus() {
+	case defaultQueuedStatus:
+		// the operation will be skipped by the executor as it is not queued any more
+		getExecutor().remove(operationID)
+		ok, err := s.GetDASRepo().CompareAndUpdateOperationStatus(operationID, defaultQueuedStatus, defaultCancelledStatus, msg)
+		if err != nil {
+			return err
+		}
+		if ok {
+			go notifyCallbacks(s.GetDASRepo(), operationID)
+			return nil
+		}
+		// the operation was started after its status was got
+		return s.cancelRunning(operationID, msg)
+	case defaultRunningStatus:
+		return s.cancelRunning(operationID, msg)
+	default:
+		return message.NewMessage(msghc.ErrHealthcheckOperationNotRunning, operationID, operationHistory.GetStatus())
+	}
+}
+
+// cancelRunning cancels the running operation with given operation id,
+// if the operation is running in this process, the engine will mark it as cancelled when it stops,
+// otherwise, it will only be marked as cancelled if it is still running,
+// and the engine in the other process will neither save the result nor send the email, as it could not claim the final status any more
+func (s *Service) cancelRunning(operationID int, msg string) error {
+	if runningOperations.cancel(operationID) {
+		return nil
+	}
+
+	ok, err := s.GetDASRepo().CompareAndUpdateOperationStatus(operationID, defaultRunningStatus, defaultCancelledStatus, msg)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// the operation stopped before it was cancelled
+		operationHistory, err := s.GetDASRepo().GetOperationHistoryByID(operationID)
+		if err != nil {
+			return err
+		}
+
+		return message.NewMessage(msghc.ErrHealthcheckOperationNotRunning, operationID, operationHistory.GetStatus())
+	}
+
+	go notifyCallbacks(s.GetDASRepo(), operationID)
+
+	return nil
+}
+
+// GetOperationQueue gets the queued and the running operations of all the processes
+func (s *Service) GetOperationQueue() error {
+	queuedOperations, err := s.GetDASRepo().GetOperationHistoriesByStatus(defaultQueuedStatus)
+	if err != nil {
+		return err
+	}
+	runningOperations, err := s.GetDASRepo().GetOperationHistoriesByStatus(defaultRunningStatus)
+	if err != nil {
+		return err
+	}
+
+	s.QueuedOperations = queuedOperations
+	s.RunningOperations = runningOperations
+
+	return nil
+}
+
+// RecoverQueuedOperations puts the queued operations into the queue of the executor again,
+// it should be called when the process starts, so that the operations queued before the restart will not be left behind,
+// if the operation is also recovered by another process, only one of them will start it
+func (s *Service) RecoverQueuedOperations() error {
+	operationHistories, err := s.GetDASRepo().GetOperationHistoriesByStatus(defaultQueuedStatus)
+	if err != nil {
+		return err
+	}
+
+	for _, operationHistory := range operationHistories {
+		err = s.requeue(operationHistory)
+		if err != nil {
+			log.Errorf("%+v", message.NewMessage(msghc.ErrHealthcheckRecoverQueuedOperation, err, operationHistory.GetID()))
+			s.fail(operationHistory.GetID(), err)
+		}
+	}
+
+	return nil
+}
+
+// requeue puts the queued operation into the queue of the executor with its original priority
+func (s *Service) requeue(operationHistory healthcheck.OperationHistory) error {
+	// get user
+	userService := metadata.NewUserServiceWithDefault()
+	err := userService.GetByID(operationHistory.GetUserID())
+	if err != nil {
+		return err
+	}
+	// get mysql server
+	mysqlServerService := metadata.NewMySQLServerServiceWithDefault()
+	err = mysqlServerService.GetByID(operationHistory.GetMySQLServerID())
+	if err != nil {
+		return err
+	}
+
+	user := userService.GetUsers()[constant.ZeroInt]
+	mysqlServer := mysqlServerService.GetMySQLServers()[constant.ZeroInt]
+	operationID := operationHistory.GetID()
+	startTime := operationHistory.GetStartTime()
+	endTime := operationHistory.GetEndTime()
+	step := time.Duration(operationHistory.GetStep()) * time.Second
+	// each operation needs its own service, as the engine is stored in the service
+	operationService := newService(s.GetDASRepo())
+	getExecutor().submit(operationID, operationHistory.GetPriority(), func() {
+		operationService.start(user, mysqlServer, operationID, startTime, endTime, step)
+	})
+
+	return nil
+}
+
+// GenerateReport generates the report of the operation with given operation id
+func (s *Service) GenerateReport(operationID int, loginName string) error {
+	operationHistory, err := s.GetDASRepo().GetOperationHistoryByID(operationID)
+	if err != nil {
+		return err
+	}
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	err = privilegeService.CheckMySQLServerByID(operationHistory.GetMySQLServerID())
+	if err != nil {
+		return err
+	}
+	// get result
+	result, err := s.GetDASRepo().GetResultByOperationID(operationID)
+	if err != nil {
+		return err
+	}
+	// get user
+	userService := metadata.NewUserServiceWithDefault()
+	err = userService.GetByID(operationHistory.GetUserID())
+	if err != nil {
+		return err
+	}
+	// get mysql server
+	mysqlServerService := metadata.NewMySQLServerServiceWithDefault()
+	err = mysqlServerService.GetByID(operationHistory.GetMySQLServerID())
+	if err != nil {
+		return err
+	}
+	mysqlServer := mysqlServerService.GetMySQLServers()[constant.ZeroInt]
+	// get monitor system
+	monitorSystem, err := mysqlServer.GetMonitorSystem()
+	if err != nil {
+		return err
+	}
+	// get apps
+	apps, err := s.getApps(mysqlServer)
+	if err != nil {
+		return err
+	}
+
+	operationInfo := NewOperationInfo(operationID, userService.GetUsers()[constant.ZeroInt], apps, mysqlServer, monitorSystem,
+		operationHistory.GetStartTime(), operationHistory.GetEndTime(), time.Duration(operationHistory.GetStep())*time.Second)
+	s.Report = NewReport(operationInfo, result)
+
+	return nil
+}
+
+// GetSnapshotByOperationID gets the snapshot of the inputs collected by the operation with given operation id
+func (s *Service) GetSnapshotByOperationID(operationID int, loginName string) error {
+	operationHistory, err := s.GetDASRepo().GetOperationHistoryByID(operationID)
+	if err != nil {
+		return err
+	}
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	err = privilegeService.CheckMySQLServerByID(operationHistory.GetMySQLServerID())
+	if err != nil {
+		return err
+	}
+
+	s.Snapshot, err = s.GetDASRepo().GetSnapshotByOperationID(operationID)
+
+	return err
+}
+
 // ReviewAccuracy updates accuracy review with given operation id
 func (s *Service) ReviewAccuracy(id, review int) error {
 	return s.GetDASRepo().UpdateAccuracyReviewByOperationID(id, review)
 }
 
+// ReviewItemAccuracy saves the accuracy feedback of the check item of the operation with given operation id,
+// a reviewer has only one feedback for a check item of an operation, reviewing again replaces the existing feedback
+func (s *Service) ReviewItemAccuracy(operationID int, itemName string, accuracy int, comment, loginName string) error {
+	if accuracy != ItemAccuracyAccurate && accuracy != ItemAccuracyInaccurate {
+		return errors.Errorf("healthcheck: accuracy must be either %d or %d. accuracy: %d", ItemAccuracyAccurate, ItemAccuracyInaccurate, accuracy)
+	}
+	operationHistory, err := s.GetDASRepo().GetOperationHistoryByID(operationID)
+	if err != nil {
+		return err
+	}
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	err = privilegeService.CheckMySQLServerByID(operationHistory.GetMySQLServerID())
+	if err != nil {
+		return err
+	}
+	// check if the item was checked by the operation
+	itemResults, err := s.GetDASRepo().GetItemResultsByOperationID(operationID)
+	if err != nil {
+		return err
+	}
+	var exists bool
+	for _, itemResult := range itemResults {
+		if itemResult.GetItemName() == itemName {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return errors.Errorf("healthcheck: item was not checked by the operation. operation id: %d, item name: %s", operationID, itemName)
+	}
+
+	err = s.GetDASRepo().SaveItemFeedback(NewItemFeedback(operationID, itemName, accuracy, comment, loginName))
+	if err != nil {
+		return err
+	}
+
+	return s.GetItemFeedbacksByOperationID(operationID)
+}
+
+// GetItemFeedbacksByOperationID gets the accuracy feedbacks of the check items of the operation with given operation id
+func (s *Service) GetItemFeedbacksByOperationID(operationID int) error {
+	var err error
+
+	s.ItemFeedbacks, err = s.GetDASRepo().GetItemFeedbacksByOperationID(operationID)
+
+	return err
+}
+
+// GetFeedbackStatsByTimeRange gets the aggregated accuracy feedbacks of the operations which were created between start time and end time,
+// the feedbacks are grouped by the check date, the item, the environment and the engine config which was used by the operations
+func (s *Service) GetFeedbackStatsByTimeRange(startTime, endTime time.Time) error {
+	if !startTime.Before(endTime) {
+		return errors.Errorf("healthcheck: start time must be earlier than end time. start time: %s, end time: %s",
+			startTime.Format(constant.TimeLayoutSecond), endTime.Format(constant.TimeLayoutSecond))
+	}
+
+	var err error
+
+	s.FeedbackStats, err = s.GetDASRepo().GetFeedbackStatsByTimeRange(startTime, endTime)
+
+	return err
+}
+
+// GetProgressByOperationID gets the latest progress of the operation with given operation id,
+// it works for the operations running in any process, as the progress is saved in the middleware
+func (s *Service) GetProgressByOperationID(operationID int, loginName string) error {
+	operationHistory, err := s.GetDASRepo().GetOperationHistoryByID(operationID)
+	if err != nil {
+		return err
+	}
+	// check privilege
+	privilegeService := privilege.NewServiceWithDefault(loginName)
+	err = privilegeService.CheckMySQLServerByID(operationHistory.GetMySQLServerID())
+	if err != nil {
+		return err
+	}
+
+	s.Progress, err = s.GetDASRepo().GetProgressByOperationID(operationID)
+
+	return err
+}
+
+// SubscribeProgress subscribes the progress events of the operation which is running in this process,
+// the channel will be closed after the operation completes, the returned function must be called to unsubscribe
+// when the events are not needed any more, it is safe to call it after the channel is closed
+func (s *Service) SubscribeProgress(operationID int) (<-chan healthcheck.ProgressEvent, func()) {
+	return progressBroker.subscribe(operationID)
+}
+
 // Marshal marshals Service to json bytes
 func (s *Service) Marshal() ([]byte, error) {
 	// return []byte(fmt.Sprintf(healthcheckMarshalServiceTemplate, s.GetResult().String())), nil
@@ -39,6 +39,16 @@ func deleteByOperationID(operationID int) error {
 	if err != nil {
 		return err
 	}
+	sql = `delete from t_hc_item_feedback where operation_id = ?`
+	_, err = testDASRepo.Execute(sql, operationID)
+	if err != nil {
+		return err
+	}
+	sql = `delete from t_hc_operation_progress where operation_id = ?`
+	_, err = testDASRepo.Execute(sql, operationID)
+	if err != nil {
+		return err
+	}
 	sql = `delete from t_hc_operation_history where id = ?`
 	_, err = testDASRepo.Execute(sql, operationID)
 	if err != nil {
@@ -48,11 +58,56 @@ func deleteByOperationID(operationID int) error {
 	return tx.Commit()
 }
 
+func deleteByClusterOperationID(clusterOperationID int) error {
+	tx, err := testDASRepo.Transaction()
+	if err != nil {
+		return err
+	}
+	err = tx.Begin()
+	if err != nil {
+		return err
+	}
+
+	sql := `delete from t_hc_result where operation_id in (select id from t_hc_operation_history where cluster_operation_id = ?)`
+	_, err = tx.Execute(sql, clusterOperationID)
+	if err != nil {
+		return err
+	}
+	sql = `delete from t_hc_operation_history where cluster_operation_id = ?`
+	_, err = tx.Execute(sql, clusterOperationID)
+	if err != nil {
+		return err
+	}
+	sql = `delete from t_hc_cluster_result where cluster_operation_id = ?`
+	_, err = tx.Execute(sql, clusterOperationID)
+	if err != nil {
+		return err
+	}
+	sql = `delete from t_hc_cluster_operation_history where id = ?`
+	_, err = tx.Execute(sql, clusterOperationID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func TestService_All(t *testing.T) {
 	TestService_GetResult(t)
 	TestService_GetResultByOperationID(t)
 	TestService_Check(t)
+	TestService_CheckCluster(t)
 	TestService_ReviewAccuracy(t)
+	TestService_ReviewItemAccuracy(t)
+	TestService_GetFeedbackStatsByTimeRange(t)
+	TestService_Cancel(t)
+	TestService_GetOperationQueue(t)
+	TestService_GetProgressByOperationID(t)
+	TestService_SubscribeProgress(t)
+	TestService_GetTrendByMySQLServerID(t)
+	TestService_GetTrendByMySQLClusterID(t)
+	TestService_CompareResults(t)
+	TestService_GenerateReport(t)
 	TestService_Marshal(t)
 	TestService_MarshalWithFields(t)
 }
@@ -60,7 +115,7 @@ func TestService_All(t *testing.T) {
 func TestService_GetResult(t *testing.T) {
 	asst := assert.New(t)
 
-	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName)
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
 	asst.Nil(err, common.CombineMessageWithError("test GetResult() failed", err))
 	time.Sleep(testSleepTime)
 	err = testService.GetResultByOperationID(operationID)
@@ -74,7 +129,7 @@ func TestService_GetResult(t *testing.T) {
 func TestService_GetResultByOperationID(t *testing.T) {
 	asst := assert.New(t)
 
-	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName)
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
 	asst.Nil(err, common.CombineMessageWithError("test GetResultByOperationID() failed", err))
 	time.Sleep(testSleepTime)
 	err = testService.GetResultByOperationID(operationID)
@@ -89,7 +144,7 @@ func TestService_GetResultByOperationID(t *testing.T) {
 func TestService_Check(t *testing.T) {
 	asst := assert.New(t)
 
-	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName)
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
 	asst.Nil(err, common.CombineMessageWithError("test Check() failed", err))
 	time.Sleep(testSleepTime)
 	err = testService.GetResultByOperationID(operationID)
@@ -110,6 +165,7 @@ func TestService_CheckByHostInfo(t *testing.T) {
 		time.Now(),
 		testHealthcheckStep,
 		testLoginName,
+		nil,
 	)
 	asst.Nil(err, common.CombineMessageWithError("test CheckByHostInfo() failed", err))
 	time.Sleep(testSleepTime)
@@ -121,10 +177,24 @@ func TestService_CheckByHostInfo(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test CheckByHostInfo() failed", err))
 }
 
+func TestService_CheckCluster(t *testing.T) {
+	asst := assert.New(t)
+
+	clusterOperationID, err := testService.CheckCluster(testHealthcheckMySQLClusterID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test CheckCluster() failed", err))
+	time.Sleep(testSleepTime)
+	err = testService.GetClusterResultByClusterOperationID(clusterOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test CheckCluster() failed", err))
+	asst.Equal(testHealthcheckMySQLClusterID, testService.GetClusterResult().GetMySQLClusterID(), "test CheckCluster() failed")
+	// delete
+	err = deleteByClusterOperationID(clusterOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test CheckCluster() failed", err))
+}
+
 func TestService_ReviewAccuracy(t *testing.T) {
 	asst := assert.New(t)
 
-	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName)
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
 	asst.Nil(err, common.CombineMessageWithError("test ReviewAccuracy() failed", err))
 	time.Sleep(testSleepTime)
 	err = testService.ReviewAccuracy(operationID, testResultUpdateAccuracyReview)
@@ -137,10 +207,195 @@ func TestService_ReviewAccuracy(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test ReviewAccuracy() failed", err))
 }
 
+func TestService_ReviewItemAccuracy(t *testing.T) {
+	asst := assert.New(t)
+
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test ReviewItemAccuracy() failed", err))
+	time.Sleep(testSleepTime)
+	err = testService.ReviewItemAccuracy(operationID, defaultCPUUsageItemName, ItemAccuracyInaccurate, testItemFeedbackComment, testLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test ReviewItemAccuracy() failed", err))
+	asst.Equal(1, len(testService.GetItemFeedbacks()), "test ReviewItemAccuracy() failed")
+	asst.Equal(ItemAccuracyInaccurate, testService.GetItemFeedbacks()[constant.ZeroInt].GetAccuracy(), "test ReviewItemAccuracy() failed")
+	// invalid accuracy
+	err = testService.ReviewItemAccuracy(operationID, defaultCPUUsageItemName, constant.ZeroInt, testItemFeedbackComment, testLoginName)
+	asst.NotNil(err, "test ReviewItemAccuracy() failed")
+	// the item was not checked
+	err = testService.ReviewItemAccuracy(operationID, testLoginName, ItemAccuracyInaccurate, testItemFeedbackComment, testLoginName)
+	asst.NotNil(err, "test ReviewItemAccuracy() failed")
+	// delete
+	err = deleteByOperationID(operationID)
+	asst.Nil(err, common.CombineMessageWithError("test ReviewItemAccuracy() failed", err))
+}
+
+func TestService_GetFeedbackStatsByTimeRange(t *testing.T) {
+	asst := assert.New(t)
+
+	id, err := testInitItemFeedback()
+	asst.Nil(err, common.CombineMessageWithError("test GetFeedbackStatsByTimeRange() failed", err))
+	err = testService.GetFeedbackStatsByTimeRange(time.Now().Add(-constant.Day), time.Now().Add(constant.Day))
+	asst.Nil(err, common.CombineMessageWithError("test GetFeedbackStatsByTimeRange() failed", err))
+	asst.NotZero(len(testService.GetFeedbackStats()), "test GetFeedbackStatsByTimeRange() failed")
+	// start time is later than end time
+	err = testService.GetFeedbackStatsByTimeRange(time.Now(), time.Now().Add(-constant.Day))
+	asst.NotNil(err, "test GetFeedbackStatsByTimeRange() failed")
+	// delete
+	err = deleteByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test GetFeedbackStatsByTimeRange() failed", err))
+}
+
+func TestService_GetProgressByOperationID(t *testing.T) {
+	asst := assert.New(t)
+
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+	time.Sleep(testSleepTime)
+	err = testService.GetProgressByOperationID(operationID, testLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+	asst.True(testService.GetProgress().IsFinished(), "test GetProgressByOperationID() failed")
+	asst.Equal(testService.GetProgress().GetTotalItemNum(), testService.GetProgress().GetFinishedItemNum(), "test GetProgressByOperationID() failed")
+	// delete
+	err = deleteByOperationID(operationID)
+	asst.Nil(err, common.CombineMessageWithError("test GetProgressByOperationID() failed", err))
+}
+
+func TestService_SubscribeProgress(t *testing.T) {
+	asst := assert.New(t)
+
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test SubscribeProgress() failed", err))
+	events, unsubscribe := testService.SubscribeProgress(operationID)
+	defer unsubscribe()
+	var lastEvent healthcheck.ProgressEvent
+	timeout := time.After(testSleepTime)
+loop:
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				break loop
+			}
+			lastEvent = event
+		case <-timeout:
+			break loop
+		}
+	}
+	asst.NotNil(lastEvent, "test SubscribeProgress() failed")
+	asst.Equal(ProgressEventTypeCompleted, lastEvent.GetEventType(), "test SubscribeProgress() failed")
+	// delete
+	err = deleteByOperationID(operationID)
+	asst.Nil(err, common.CombineMessageWithError("test SubscribeProgress() failed", err))
+}
+
+func TestService_Cancel(t *testing.T) {
+	asst := assert.New(t)
+
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test Cancel() failed", err))
+	err = testService.Cancel(operationID, testLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test Cancel() failed", err))
+	time.Sleep(testSleepTime)
+	operationHistory, err := testDASRepo.GetOperationHistoryByID(operationID)
+	asst.Nil(err, common.CombineMessageWithError("test Cancel() failed", err))
+	asst.Equal(defaultCancelledStatus, operationHistory.GetStatus(), "test Cancel() failed")
+	// the operation is not running any more
+	err = testService.Cancel(operationID, testLoginName)
+	asst.NotNil(err, "test Cancel() failed")
+	// delete
+	err = deleteByOperationID(operationID)
+	asst.Nil(err, common.CombineMessageWithError("test Cancel() failed", err))
+}
+
+func TestService_GetOperationQueue(t *testing.T) {
+	asst := assert.New(t)
+
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test GetOperationQueue() failed", err))
+	err = testService.GetOperationQueue()
+	asst.Nil(err, common.CombineMessageWithError("test GetOperationQueue() failed", err))
+	// the operation is either queued or running
+	var found bool
+	for _, operationHistory := range append(testService.GetQueuedOperations(), testService.GetRunningOperations()...) {
+		if operationHistory.GetID() == operationID {
+			found = true
+		}
+	}
+	asst.True(found, "test GetOperationQueue() failed")
+	time.Sleep(testSleepTime)
+	// delete
+	err = deleteByOperationID(operationID)
+	asst.Nil(err, common.CombineMessageWithError("test GetOperationQueue() failed", err))
+}
+
+func TestService_GetTrendByMySQLServerID(t *testing.T) {
+	asst := assert.New(t)
+
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test GetTrendByMySQLServerID() failed", err))
+	time.Sleep(testSleepTime)
+	err = testService.GetTrendByMySQLServerID(testHealthcheckMySQLServerID, time.Now().Add(-constant.Day), time.Now(), testLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test GetTrendByMySQLServerID() failed", err))
+	trendPoints := testService.GetTrendPoints()
+	asst.NotZero(len(trendPoints), "test GetTrendByMySQLServerID() failed")
+	asst.Equal(operationID, trendPoints[len(trendPoints)-1].GetOperationID(), "test GetTrendByMySQLServerID() failed")
+	// delete
+	err = deleteByOperationID(operationID)
+	asst.Nil(err, common.CombineMessageWithError("test GetTrendByMySQLServerID() failed", err))
+}
+
+func TestService_GetTrendByMySQLClusterID(t *testing.T) {
+	asst := assert.New(t)
+
+	clusterOperationID, err := testService.CheckCluster(testHealthcheckMySQLClusterID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test GetTrendByMySQLClusterID() failed", err))
+	time.Sleep(testSleepTime)
+	err = testService.GetTrendByMySQLClusterID(testHealthcheckMySQLClusterID, time.Now().Add(-constant.Day), time.Now(), testLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test GetTrendByMySQLClusterID() failed", err))
+	asst.NotZero(len(testService.GetTrendPoints()), "test GetTrendByMySQLClusterID() failed")
+	// delete
+	err = deleteByClusterOperationID(clusterOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test GetTrendByMySQLClusterID() failed", err))
+}
+
+func TestService_CompareResults(t *testing.T) {
+	asst := assert.New(t)
+
+	baseOperationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test CompareResults() failed", err))
+	time.Sleep(testSleepTime)
+	targetOperationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test CompareResults() failed", err))
+	time.Sleep(testSleepTime)
+	err = testService.CompareResults(baseOperationID, targetOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test CompareResults() failed", err))
+	asst.Equal(baseOperationID, testService.GetResultDiff().GetBaseOperationID(), "test CompareResults() failed")
+	asst.Equal(targetOperationID, testService.GetResultDiff().GetTargetOperationID(), "test CompareResults() failed")
+	// delete
+	err = deleteByOperationID(baseOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test CompareResults() failed", err))
+	err = deleteByOperationID(targetOperationID)
+	asst.Nil(err, common.CombineMessageWithError("test CompareResults() failed", err))
+}
+
+func TestService_GenerateReport(t *testing.T) {
+	asst := assert.New(t)
+
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
+	asst.Nil(err, common.CombineMessageWithError("test GenerateReport() failed", err))
+	time.Sleep(testSleepTime)
+	err = testService.GenerateReport(operationID, testLoginName)
+	asst.Nil(err, common.CombineMessageWithError("test GenerateReport() failed", err))
+	_, err = testService.GetReport().Render(ReportFormatHTML)
+	asst.Nil(err, common.CombineMessageWithError("test GenerateReport() failed", err))
+	// delete
+	err = deleteByOperationID(operationID)
+	asst.Nil(err, common.CombineMessageWithError("test GenerateReport() failed", err))
+}
+
 func TestService_Marshal(t *testing.T) {
 	asst := assert.New(t)
 
-	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName)
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
 	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
 	time.Sleep(testSleepTime)
 	err = testService.GetResultByOperationID(operationID)
@@ -155,7 +410,7 @@ func TestService_Marshal(t *testing.T) {
 
 func TestService_MarshalWithFields(t *testing.T) {
 	asst := assert.New(t)
-	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName)
+	operationID, err := testService.Check(testHealthcheckMySQLServerID, time.Now().Add(-constant.Week), time.Now(), testHealthcheckStep, testLoginName, nil)
 	asst.Nil(err, common.CombineMessageWithError("test healthcheckResultStruct() failed", err))
 	time.Sleep(testSleepTime)
 	err = testService.GetResultByOperationID(operationID)
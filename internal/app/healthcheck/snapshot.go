@@ -0,0 +1,1146 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/das/internal/app/query"
+	"github.com/romberli/das/internal/dependency/healthcheck"
+	depmeta "github.com/romberli/das/internal/dependency/metadata"
+	depquery "github.com/romberli/das/internal/dependency/query"
+	"github.com/romberli/das/pkg/message"
+	msghc "github.com/romberli/das/pkg/message/healthcheck"
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	defaultSnapshotVersion      = 1
+	replicationThreadNotRunning = "No"
+
+	snapshotVariablesName          = "variables"
+	snapshotMySQLDirsName          = "mysql dirs"
+	snapshotLargeTablesName        = "large tables"
+	snapshotReplicationStatusName  = "replication status"
+	snapshotGTIDExecutedName       = "gtid executed"
+	snapshotGroupMembersName       = "group members"
+	snapshotLongTransactionsName   = "long transactions"
+	snapshotHistoryListLengthName  = "history list length"
+	snapshotLockWaitsName          = "lock waits"
+	snapshotLatestDeadlockName     = "latest deadlock"
+	snapshotTableSchemasName       = "table schemas"
+	snapshotIndexStatisticsName    = "index statistics"
+	snapshotAutoIncrementsName     = "auto increments"
+	snapshotUsersName              = "users"
+	snapshotFileSystemsName        = "file systems"
+	snapshotDiskCapacitiesName     = "disk capacities"
+	snapshotSlowQueriesName        = "slow queries"
+	snapshotDBNameTemplate         = "db name of tables %s"
+	snapshotMasterGTIDExecutedName = "gtid executed of master %s:%d"
+	snapshotGTIDSubtractTemplate   = "gtid subtract of %s and %s"
+	snapshotGrantsTemplate         = "grants of '%s'@'%s'"
+	snapshotPrometheusSeriesName   = "prometheus series %s"
+)
+
+var (
+	_ healthcheck.Snapshot             = (*Snapshot)(nil)
+	_ healthcheck.ApplicationMySQLRepo = (*SnapshotApplicationMySQLRepo)(nil)
+	_ healthcheck.PrometheusRepo       = (*SnapshotPrometheusRepo)(nil)
+	_ healthcheck.QueryRepo            = (*SnapshotQueryRepo)(nil)
+	_ healthcheck.ApplicationMySQLRepo = (*recordingApplicationMySQLRepo)(nil)
+	_ healthcheck.PrometheusRepo       = (*recordingPrometheusRepo)(nil)
+	_ healthcheck.QueryRepo            = (*recordingQueryRepo)(nil)
+)
+
+// SnapshotDBName is the db name of the tables which was got by the slow query item
+type SnapshotDBName struct {
+	TableNames []string `json:"table_names"`
+	DBName     string   `json:"db_name"`
+}
+
+// SnapshotMasterGTIDExecuted is the executed gtid set of a master of the mysql server
+type SnapshotMasterGTIDExecuted struct {
+	HostIP       string `json:"host_ip"`
+	PortNum      int    `json:"port_num"`
+	GTIDExecuted string `json:"gtid_executed"`
+}
+
+// SnapshotGTIDSubtract is the result of subtracting the second gtid set from the first gtid set
+type SnapshotGTIDSubtract struct {
+	GTIDSet1 string `json:"gtid_set1"`
+	GTIDSet2 string `json:"gtid_set2"`
+	Result   string `json:"result"`
+}
+
+// SnapshotGrants is the grant statements of an account
+type SnapshotGrants struct {
+	User   string   `json:"user"`
+	Host   string   `json:"host"`
+	Grants []string `json:"grants"`
+}
+
+// Snapshot is the inputs which were collected from the data sources by a healthcheck operation,
+// it could be scored again without connecting to the data sources,
+// the fields which were not collected are null, and the items which need them will fail when scoring the snapshot
+type Snapshot struct {
+	Version     int       `json:"version"`
+	OperationID int       `json:"operation_id"`
+	HostIP      string    `json:"host_ip"`
+	PortNum     int       `json:"port_num"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Step        int       `json:"step"`
+	// application mysql
+	Variables          []*GlobalVariable             `json:"variables"`
+	MySQLDirs          []string                      `json:"mysql_dirs"`
+	LargeTables        []*Table                      `json:"large_tables"`
+	DBNames            []*SnapshotDBName             `json:"db_names"`
+	ReplicationStatus  []*ReplicationStatus          `json:"replication_status"`
+	GTIDExecuted       *string                       `json:"gtid_executed"`
+	MasterGTIDExecuted []*SnapshotMasterGTIDExecuted `json:"master_gtid_executed"`
+	GTIDSubtracts      []*SnapshotGTIDSubtract       `json:"gtid_subtracts"`
+	GroupMembers       []*GroupMember                `json:"group_members"`
+	LongTransactions   []*InnoDBTransaction          `json:"long_transactions"`
+	HistoryListLength  *int                          `json:"history_list_length"`
+	LockWaits          []*InnoDBLockWait             `json:"lock_waits"`
+	LatestDeadlock     *InnoDBDeadlock               `json:"latest_deadlock"`
+	TableSchemas       []*TableSchema                `json:"table_schemas"`
+	IndexStatistics    []*metadata.IndexStatistic    `json:"index_statistics"`
+	AutoIncrements     []*AutoIncrement              `json:"auto_increments"`
+	Users              []*MySQLUser                  `json:"users"`
+	Grants             []*SnapshotGrants             `json:"grants"`
+	// prometheus, the series are keyed by the item name
+	FileSystems      []*FileSystem                `json:"file_systems"`
+	DiskCapacities   []*DiskCapacity              `json:"disk_capacities"`
+	PrometheusSeries map[string][]*PrometheusData `json:"prometheus_series"`
+	// query
+	SlowQueries []*query.Query `json:"slow_queries"`
+}
+
+// NewSnapshot returns a new *Snapshot of the operation
+func NewSnapshot(operationInfo healthcheck.OperationInfo) *Snapshot {
+	return &Snapshot{
+		Version:          defaultSnapshotVersion,
+		OperationID:      operationInfo.GetOperationID(),
+		HostIP:           operationInfo.GetMySQLServer().GetHostIP(),
+		PortNum:          operationInfo.GetMySQLServer().GetPortNum(),
+		StartTime:        operationInfo.GetStartTime(),
+		EndTime:          operationInfo.GetEndTime(),
+		Step:             int(operationInfo.GetStep().Seconds()),
+		PrometheusSeries: make(map[string][]*PrometheusData),
+	}
+}
+
+// NewEmptySnapshot returns a new empty *Snapshot
+func NewEmptySnapshot() *Snapshot {
+	return &Snapshot{
+		Version:          defaultSnapshotVersion,
+		PrometheusSeries: make(map[string][]*PrometheusData),
+	}
+}
+
+// NewSnapshotWithJSON unmarshals the json bytes to a *Snapshot and validates it
+func NewSnapshotWithJSON(data []byte) (*Snapshot, error) {
+	snapshot := NewEmptySnapshot()
+	err := json.Unmarshal(data, snapshot)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if snapshot.PrometheusSeries == nil {
+		snapshot.PrometheusSeries = make(map[string][]*PrometheusData)
+	}
+
+	err = snapshot.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// GetVersion returns the version of the snapshot format
+func (s *Snapshot) GetVersion() int {
+	return s.Version
+}
+
+// GetOperationID returns the operation id of the live run which collected the snapshot
+func (s *Snapshot) GetOperationID() int {
+	return s.OperationID
+}
+
+// GetHostIP returns the host ip of the mysql server
+func (s *Snapshot) GetHostIP() string {
+	return s.HostIP
+}
+
+// GetPortNum returns the port number of the mysql server
+func (s *Snapshot) GetPortNum() int {
+	return s.PortNum
+}
+
+// GetStartTime returns the start time of the check range
+func (s *Snapshot) GetStartTime() time.Time {
+	return s.StartTime
+}
+
+// GetEndTime returns the end time of the check range
+func (s *Snapshot) GetEndTime() time.Time {
+	return s.EndTime
+}
+
+// GetStep returns the step of the check range
+func (s *Snapshot) GetStep() time.Duration {
+	return time.Duration(s.Step) * time.Second
+}
+
+// Validate validates if the snapshot could be scored
+func (s *Snapshot) Validate() error {
+	if s.GetVersion() != defaultSnapshotVersion {
+		return message.NewMessage(msghc.ErrHealthcheckSnapshotVersionInvalid, defaultSnapshotVersion, s.GetVersion())
+	}
+	if s.GetHostIP() == constant.EmptyString || s.GetPortNum() <= constant.ZeroInt {
+		return message.NewMessage(msghc.ErrHealthcheckSnapshotHostInfoInvalid, s.GetHostIP(), s.GetPortNum())
+	}
+	if s.GetStartTime().IsZero() || !s.GetEndTime().After(s.GetStartTime()) || s.GetStep() <= constant.ZeroInt {
+		return message.NewMessage(msghc.ErrHealthcheckSnapshotTimeRangeInvalid,
+			s.GetStartTime().Format(constant.TimeLayoutSecond), s.GetEndTime().Format(constant.TimeLayoutSecond), s.Step)
+	}
+
+	return nil
+}
+
+// Marshal marshals Snapshot to json bytes
+func (s *Snapshot) Marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// notCollected returns the error which means the data was not collected in the snapshot
+func (s *Snapshot) notCollected(name string) error {
+	return message.NewMessage(msghc.ErrHealthcheckSnapshotDataNotCollected, name, s.GetOperationID())
+}
+
+// setVariable sets the value of the variable, the variable names are case-insensitive
+func (s *Snapshot) setVariable(name, value string) {
+	for _, variable := range s.Variables {
+		if strings.EqualFold(variable.GetName(), name) {
+			variable.VariableValue = value
+			return
+		}
+	}
+
+	s.Variables = append(s.Variables, NewGlobalVariable(name, value))
+}
+
+// SnapshotApplicationMySQLRepo serves the application mysql inputs from the snapshot
+type SnapshotApplicationMySQLRepo struct {
+	snapshot *Snapshot
+}
+
+// NewSnapshotApplicationMySQLRepo returns a new healthcheck.ApplicationMySQLRepo which reads from the snapshot
+func NewSnapshotApplicationMySQLRepo(snapshot *Snapshot) healthcheck.ApplicationMySQLRepo {
+	return &SnapshotApplicationMySQLRepo{snapshot: snapshot}
+}
+
+// Close does nothing, there is no connection to close
+func (r *SnapshotApplicationMySQLRepo) Close() error {
+	return nil
+}
+
+// GetVariables returns the variables of given items in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetVariables(items []string) ([]healthcheck.Variable, error) {
+	if r.snapshot.Variables == nil {
+		return nil, r.snapshot.notCollected(snapshotVariablesName)
+	}
+
+	variables := []healthcheck.Variable{}
+	for _, variable := range r.snapshot.Variables {
+		for _, item := range items {
+			if strings.EqualFold(variable.GetName(), item) {
+				variables = append(variables, NewGlobalVariable(variable.GetName(), variable.GetValue()))
+				break
+			}
+		}
+	}
+
+	return variables, nil
+}
+
+// GetMySQLDirs returns the mysql directories in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetMySQLDirs() ([]string, error) {
+	if r.snapshot.MySQLDirs == nil {
+		return nil, r.snapshot.notCollected(snapshotMySQLDirsName)
+	}
+
+	dirs := make([]string, len(r.snapshot.MySQLDirs))
+	copy(dirs, r.snapshot.MySQLDirs)
+
+	return dirs, nil
+}
+
+// GetLargeTables returns the large tables in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetLargeTables() ([]healthcheck.Table, error) {
+	if r.snapshot.LargeTables == nil {
+		return nil, r.snapshot.notCollected(snapshotLargeTablesName)
+	}
+
+	tables := make([]healthcheck.Table, len(r.snapshot.LargeTables))
+	for i, table := range r.snapshot.LargeTables {
+		tables[i] = NewTable(table.GetSchema(), table.GetName(), table.GetRows(), table.GetSize())
+	}
+
+	return tables, nil
+}
+
+// GetDBName returns the db name of given table names in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetDBName(tableNames []string) (string, error) {
+	for _, dbName := range r.snapshot.DBNames {
+		if strings.Join(dbName.TableNames, constant.CommaString) == strings.Join(tableNames, constant.CommaString) {
+			return dbName.DBName, nil
+		}
+	}
+
+	return constant.EmptyString, r.snapshot.notCollected(fmt.Sprintf(snapshotDBNameTemplate, strings.Join(tableNames, constant.CommaString)))
+}
+
+// GetReplicationStatus returns the replication status in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetReplicationStatus() ([]healthcheck.ReplicationStatus, error) {
+	if r.snapshot.ReplicationStatus == nil {
+		return nil, r.snapshot.notCollected(snapshotReplicationStatusName)
+	}
+
+	replicationStatuses := make([]healthcheck.ReplicationStatus, len(r.snapshot.ReplicationStatus))
+	for i, replicationStatus := range r.snapshot.ReplicationStatus {
+		rs := *replicationStatus
+		replicationStatuses[i] = &rs
+	}
+
+	return replicationStatuses, nil
+}
+
+// GetGTIDExecuted returns the executed gtid set in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetGTIDExecuted() (string, error) {
+	if r.snapshot.GTIDExecuted == nil {
+		return constant.EmptyString, r.snapshot.notCollected(snapshotGTIDExecutedName)
+	}
+
+	return *r.snapshot.GTIDExecuted, nil
+}
+
+// GetMasterGTIDExecuted returns the executed gtid set of the master with given host ip and port number in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetMasterGTIDExecuted(hostIP string, portNum int) (string, error) {
+	for _, masterGTIDExecuted := range r.snapshot.MasterGTIDExecuted {
+		if masterGTIDExecuted.HostIP == hostIP && masterGTIDExecuted.PortNum == portNum {
+			return masterGTIDExecuted.GTIDExecuted, nil
+		}
+	}
+
+	return constant.EmptyString, r.snapshot.notCollected(fmt.Sprintf(snapshotMasterGTIDExecutedName, hostIP, portNum))
+}
+
+// GetGTIDSubtract returns the result of subtracting the second gtid set from the first gtid set in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetGTIDSubtract(gtidSet1, gtidSet2 string) (string, error) {
+	for _, gtidSubtract := range r.snapshot.GTIDSubtracts {
+		if gtidSubtract.GTIDSet1 == gtidSet1 && gtidSubtract.GTIDSet2 == gtidSet2 {
+			return gtidSubtract.Result, nil
+		}
+	}
+
+	return constant.EmptyString, r.snapshot.notCollected(fmt.Sprintf(snapshotGTIDSubtractTemplate, gtidSet1, gtidSet2))
+}
+
+// GetGroupMembers returns the members of the group replication in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetGroupMembers() ([]healthcheck.GroupMember, error) {
+	if r.snapshot.GroupMembers == nil {
+		return nil, r.snapshot.notCollected(snapshotGroupMembersName)
+	}
+
+	groupMembers := make([]healthcheck.GroupMember, len(r.snapshot.GroupMembers))
+	for i, groupMember := range r.snapshot.GroupMembers {
+		groupMembers[i] = NewGroupMember(groupMember.GetHostIP(), groupMember.GetPortNum(), groupMember.GetState())
+	}
+
+	return groupMembers, nil
+}
+
+// GetLongTransactions returns the running innodb transactions in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetLongTransactions() ([]healthcheck.InnoDBTransaction, error) {
+	if r.snapshot.LongTransactions == nil {
+		return nil, r.snapshot.notCollected(snapshotLongTransactionsName)
+	}
+
+	transactions := make([]healthcheck.InnoDBTransaction, len(r.snapshot.LongTransactions))
+	for i, transaction := range r.snapshot.LongTransactions {
+		t := *transaction
+		transactions[i] = &t
+	}
+
+	return transactions, nil
+}
+
+// GetHistoryListLength returns the history list length in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetHistoryListLength() (int, error) {
+	if r.snapshot.HistoryListLength == nil {
+		return constant.ZeroInt, r.snapshot.notCollected(snapshotHistoryListLengthName)
+	}
+
+	return *r.snapshot.HistoryListLength, nil
+}
+
+// GetLockWaits returns the innodb lock waits in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetLockWaits() ([]healthcheck.InnoDBLockWait, error) {
+	if r.snapshot.LockWaits == nil {
+		return nil, r.snapshot.notCollected(snapshotLockWaitsName)
+	}
+
+	lockWaits := make([]healthcheck.InnoDBLockWait, len(r.snapshot.LockWaits))
+	for i, lockWait := range r.snapshot.LockWaits {
+		lw := *lockWait
+		lockWaits[i] = &lw
+	}
+
+	return lockWaits, nil
+}
+
+// GetLatestDeadlock returns the latest innodb deadlock in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetLatestDeadlock() (healthcheck.InnoDBDeadlock, error) {
+	if r.snapshot.LatestDeadlock == nil {
+		return nil, r.snapshot.notCollected(snapshotLatestDeadlockName)
+	}
+
+	return newSnapshotInnoDBDeadlock(r.snapshot.LatestDeadlock), nil
+}
+
+// GetTableSchemas returns the table schemas in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetTableSchemas() ([]healthcheck.TableSchema, error) {
+	if r.snapshot.TableSchemas == nil {
+		return nil, r.snapshot.notCollected(snapshotTableSchemasName)
+	}
+
+	tableSchemas := make([]healthcheck.TableSchema, len(r.snapshot.TableSchemas))
+	for i, tableSchema := range r.snapshot.TableSchemas {
+		ts := *tableSchema
+		tableSchemas[i] = &ts
+	}
+
+	return tableSchemas, nil
+}
+
+// GetIndexStatistics returns the index statistics in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetIndexStatistics() ([]depmeta.IndexStatistic, error) {
+	if r.snapshot.IndexStatistics == nil {
+		return nil, r.snapshot.notCollected(snapshotIndexStatisticsName)
+	}
+
+	indexStatistics := make([]depmeta.IndexStatistic, len(r.snapshot.IndexStatistics))
+	for i, indexStatistic := range r.snapshot.IndexStatistics {
+		is := *indexStatistic
+		indexStatistics[i] = &is
+	}
+
+	return indexStatistics, nil
+}
+
+// GetAutoIncrements returns the auto increments in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetAutoIncrements() ([]healthcheck.AutoIncrement, error) {
+	if r.snapshot.AutoIncrements == nil {
+		return nil, r.snapshot.notCollected(snapshotAutoIncrementsName)
+	}
+
+	autoIncrements := make([]healthcheck.AutoIncrement, len(r.snapshot.AutoIncrements))
+	for i, autoIncrement := range r.snapshot.AutoIncrements {
+		ai := *autoIncrement
+		autoIncrements[i] = &ai
+	}
+
+	return autoIncrements, nil
+}
+
+// GetUsers returns the accounts in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetUsers() ([]healthcheck.MySQLUser, error) {
+	if r.snapshot.Users == nil {
+		return nil, r.snapshot.notCollected(snapshotUsersName)
+	}
+
+	users := make([]healthcheck.MySQLUser, len(r.snapshot.Users))
+	for i, user := range r.snapshot.Users {
+		u := *user
+		users[i] = &u
+	}
+
+	return users, nil
+}
+
+// GetGrants returns the grant statements of given account in the snapshot
+func (r *SnapshotApplicationMySQLRepo) GetGrants(user, host string) ([]string, error) {
+	for _, grants := range r.snapshot.Grants {
+		if grants.User == user && grants.Host == host {
+			result := make([]string, len(grants.Grants))
+			copy(result, grants.Grants)
+
+			return result, nil
+		}
+	}
+
+	return nil, r.snapshot.notCollected(fmt.Sprintf(snapshotGrantsTemplate, user, host))
+}
+
+// SnapshotPrometheusRepo serves the prometheus inputs from the snapshot
+type SnapshotPrometheusRepo struct {
+	snapshot *Snapshot
+}
+
+// NewSnapshotPrometheusRepo returns a new healthcheck.PrometheusRepo which reads from the snapshot
+func NewSnapshotPrometheusRepo(snapshot *Snapshot) healthcheck.PrometheusRepo {
+	return &SnapshotPrometheusRepo{snapshot: snapshot}
+}
+
+// GetFileSystems returns the file systems in the snapshot
+func (r *SnapshotPrometheusRepo) GetFileSystems() ([]healthcheck.FileSystem, error) {
+	if r.snapshot.FileSystems == nil {
+		return nil, r.snapshot.notCollected(snapshotFileSystemsName)
+	}
+
+	fileSystems := make([]healthcheck.FileSystem, len(r.snapshot.FileSystems))
+	for i, fileSystem := range r.snapshot.FileSystems {
+		fileSystems[i] = NewFileSystem(fileSystem.GetMountPoint(), fileSystem.GetDevice())
+	}
+
+	return fileSystems, nil
+}
+
+// GetAvgBackupFailedRatio returns the average backup failed ratio in the snapshot
+func (r *SnapshotPrometheusRepo) GetAvgBackupFailedRatio() ([]healthcheck.PrometheusData, error) {
+	return r.getSeries(defaultAvgBackupFailedRatioItemName)
+}
+
+// GetStatisticFailedRatio returns the statistic failed ratio in the snapshot
+func (r *SnapshotPrometheusRepo) GetStatisticFailedRatio() ([]healthcheck.PrometheusData, error) {
+	return r.getSeries(defaultStatisticFailedRatioItemName)
+}
+
+// GetCPUUsage returns the cpu usage in the snapshot
+func (r *SnapshotPrometheusRepo) GetCPUUsage() ([]healthcheck.PrometheusData, error) {
+	return r.getSeries(defaultCPUUsageItemName)
+}
+
+// GetIOUtil returns the io util in the snapshot
+func (r *SnapshotPrometheusRepo) GetIOUtil() ([]healthcheck.PrometheusData, error) {
+	return r.getSeries(defaultIOUtilItemName)
+}
+
+// GetDiskCapacityUsage returns the disk capacity usage in the snapshot,
+// the mount points are ignored, because the series were collected with the mount points of the snapshot
+func (r *SnapshotPrometheusRepo) GetDiskCapacityUsage(mountPoints []string) ([]healthcheck.PrometheusData, error) {
+	return r.getSeries(defaultDiskCapacityUsageItemName)
+}
+
+// GetDiskCapacities returns the disk capacities in the snapshot,
+// the mount points are ignored, because the disk capacities were collected with the mount points of the snapshot
+func (r *SnapshotPrometheusRepo) GetDiskCapacities(mountPoints []string) ([]healthcheck.DiskCapacity, error) {
+	if r.snapshot.DiskCapacities == nil {
+		return nil, r.snapshot.notCollected(snapshotDiskCapacitiesName)
+	}
+
+	diskCapacities := make([]healthcheck.DiskCapacity, len(r.snapshot.DiskCapacities))
+	for i, diskCapacity := range r.snapshot.DiskCapacities {
+		diskCapacities[i] = NewDiskCapacity(diskCapacity.GetMountPoint(), diskCapacity.GetSize(), diskCapacity.GetUsedDatas())
+	}
+
+	return diskCapacities, nil
+}
+
+// GetConnectionUsage returns the connection usage in the snapshot
+func (r *SnapshotPrometheusRepo) GetConnectionUsage() ([]healthcheck.PrometheusData, error) {
+	return r.getSeries(defaultConnectionUsageItemName)
+}
+
+// GetAverageActiveSessionPercents returns the average active session percents in the snapshot
+func (r *SnapshotPrometheusRepo) GetAverageActiveSessionPercents() ([]healthcheck.PrometheusData, error) {
+	return r.getSeries(defaultAverageActiveSessionPercentsItemName)
+}
+
+// GetCacheMissRatio returns the cache miss ratio in the snapshot
+func (r *SnapshotPrometheusRepo) GetCacheMissRatio() ([]healthcheck.PrometheusData, error) {
+	return r.getSeries(defaultCacheMissRatioItemName)
+}
+
+// getSeries returns the prometheus series of given item name in the snapshot
+func (r *SnapshotPrometheusRepo) getSeries(itemName string) ([]healthcheck.PrometheusData, error) {
+	series, ok := r.snapshot.PrometheusSeries[itemName]
+	if !ok || series == nil {
+		return nil, r.snapshot.notCollected(fmt.Sprintf(snapshotPrometheusSeriesName, itemName))
+	}
+
+	datas := make([]healthcheck.PrometheusData, len(series))
+	for i, data := range series {
+		datas[i] = NewPrometheusData(data.GetTimestamp(), data.GetValue())
+	}
+
+	return datas, nil
+}
+
+// SnapshotQueryRepo serves the slow queries from the snapshot
+type SnapshotQueryRepo struct {
+	snapshot *Snapshot
+}
+
+// NewSnapshotQueryRepo returns a new healthcheck.QueryRepo which reads from the snapshot
+func NewSnapshotQueryRepo(snapshot *Snapshot) healthcheck.QueryRepo {
+	return &SnapshotQueryRepo{snapshot: snapshot}
+}
+
+// Close does nothing, there is no connection to close
+func (r *SnapshotQueryRepo) Close() error {
+	return nil
+}
+
+// GetSlowQuery returns the slow queries in the snapshot,
+// the queries are copied, because the slow query item sets the db names to them
+func (r *SnapshotQueryRepo) GetSlowQuery() ([]depquery.Query, error) {
+	if r.snapshot.SlowQueries == nil {
+		return nil, r.snapshot.notCollected(snapshotSlowQueriesName)
+	}
+
+	queries := make([]depquery.Query, len(r.snapshot.SlowQueries))
+	for i, slowQuery := range r.snapshot.SlowQueries {
+		q := *slowQuery
+		queries[i] = &q
+	}
+
+	return queries, nil
+}
+
+// recordingApplicationMySQLRepo records the inputs got from the application mysql into the snapshot
+type recordingApplicationMySQLRepo struct {
+	healthcheck.ApplicationMySQLRepo
+	snapshot *Snapshot
+}
+
+// newRecordingApplicationMySQLRepo returns a new *recordingApplicationMySQLRepo
+func newRecordingApplicationMySQLRepo(repo healthcheck.ApplicationMySQLRepo, snapshot *Snapshot) *recordingApplicationMySQLRepo {
+	return &recordingApplicationMySQLRepo{
+		ApplicationMySQLRepo: repo,
+		snapshot:             snapshot,
+	}
+}
+
+// GetVariables gets the variables from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetVariables(items []string) ([]healthcheck.Variable, error) {
+	variables, err := r.ApplicationMySQLRepo.GetVariables(items)
+	if err != nil {
+		return variables, err
+	}
+
+	if r.snapshot.Variables == nil {
+		r.snapshot.Variables = []*GlobalVariable{}
+	}
+	for _, variable := range variables {
+		r.snapshot.setVariable(variable.GetName(), variable.GetValue())
+	}
+
+	return variables, nil
+}
+
+// GetMySQLDirs gets the mysql directories from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetMySQLDirs() ([]string, error) {
+	dirs, err := r.ApplicationMySQLRepo.GetMySQLDirs()
+	if err != nil {
+		return dirs, err
+	}
+
+	r.snapshot.MySQLDirs = make([]string, len(dirs))
+	copy(r.snapshot.MySQLDirs, dirs)
+
+	return dirs, nil
+}
+
+// GetLargeTables gets the large tables from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetLargeTables() ([]healthcheck.Table, error) {
+	tables, err := r.ApplicationMySQLRepo.GetLargeTables()
+	if err != nil {
+		return tables, err
+	}
+
+	r.snapshot.LargeTables = make([]*Table, len(tables))
+	for i, table := range tables {
+		r.snapshot.LargeTables[i] = &Table{
+			DBName:    table.GetSchema(),
+			TableName: table.GetName(),
+			TableRows: table.GetRows(),
+			TableSize: table.GetSize(),
+		}
+	}
+
+	return tables, nil
+}
+
+// GetDBName gets the db name of given table names from the application mysql and records it
+func (r *recordingApplicationMySQLRepo) GetDBName(tableNames []string) (string, error) {
+	dbName, err := r.ApplicationMySQLRepo.GetDBName(tableNames)
+	if err != nil {
+		return dbName, err
+	}
+
+	names := make([]string, len(tableNames))
+	copy(names, tableNames)
+	r.snapshot.DBNames = append(r.snapshot.DBNames, &SnapshotDBName{TableNames: names, DBName: dbName})
+
+	return dbName, nil
+}
+
+// GetReplicationStatus gets the replication status from the application mysql and records it
+func (r *recordingApplicationMySQLRepo) GetReplicationStatus() ([]healthcheck.ReplicationStatus, error) {
+	replicationStatuses, err := r.ApplicationMySQLRepo.GetReplicationStatus()
+	if err != nil {
+		return replicationStatuses, err
+	}
+
+	r.snapshot.ReplicationStatus = make([]*ReplicationStatus, len(replicationStatuses))
+	for i, replicationStatus := range replicationStatuses {
+		r.snapshot.ReplicationStatus[i] = newSnapshotReplicationStatus(replicationStatus)
+	}
+
+	return replicationStatuses, nil
+}
+
+// GetGTIDExecuted gets the executed gtid set from the application mysql and records it
+func (r *recordingApplicationMySQLRepo) GetGTIDExecuted() (string, error) {
+	gtidExecuted, err := r.ApplicationMySQLRepo.GetGTIDExecuted()
+	if err != nil {
+		return gtidExecuted, err
+	}
+
+	r.snapshot.GTIDExecuted = &gtidExecuted
+
+	return gtidExecuted, nil
+}
+
+// GetMasterGTIDExecuted gets the executed gtid set of the master from the master and records it
+func (r *recordingApplicationMySQLRepo) GetMasterGTIDExecuted(hostIP string, portNum int) (string, error) {
+	gtidExecuted, err := r.ApplicationMySQLRepo.GetMasterGTIDExecuted(hostIP, portNum)
+	if err != nil {
+		return gtidExecuted, err
+	}
+
+	r.snapshot.MasterGTIDExecuted = append(r.snapshot.MasterGTIDExecuted, &SnapshotMasterGTIDExecuted{
+		HostIP:       hostIP,
+		PortNum:      portNum,
+		GTIDExecuted: gtidExecuted,
+	})
+
+	return gtidExecuted, nil
+}
+
+// GetGTIDSubtract gets the result of subtracting the gtid sets from the application mysql and records it
+func (r *recordingApplicationMySQLRepo) GetGTIDSubtract(gtidSet1, gtidSet2 string) (string, error) {
+	result, err := r.ApplicationMySQLRepo.GetGTIDSubtract(gtidSet1, gtidSet2)
+	if err != nil {
+		return result, err
+	}
+
+	r.snapshot.GTIDSubtracts = append(r.snapshot.GTIDSubtracts, &SnapshotGTIDSubtract{
+		GTIDSet1: gtidSet1,
+		GTIDSet2: gtidSet2,
+		Result:   result,
+	})
+
+	return result, nil
+}
+
+// GetGroupMembers gets the members of the group replication from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetGroupMembers() ([]healthcheck.GroupMember, error) {
+	groupMembers, err := r.ApplicationMySQLRepo.GetGroupMembers()
+	if err != nil {
+		return groupMembers, err
+	}
+
+	r.snapshot.GroupMembers = make([]*GroupMember, len(groupMembers))
+	for i, groupMember := range groupMembers {
+		r.snapshot.GroupMembers[i] = &GroupMember{
+			MemberHost:  groupMember.GetHostIP(),
+			MemberPort:  groupMember.GetPortNum(),
+			MemberState: groupMember.GetState(),
+		}
+	}
+
+	return groupMembers, nil
+}
+
+// GetLongTransactions gets the running innodb transactions from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetLongTransactions() ([]healthcheck.InnoDBTransaction, error) {
+	transactions, err := r.ApplicationMySQLRepo.GetLongTransactions()
+	if err != nil {
+		return transactions, err
+	}
+
+	r.snapshot.LongTransactions = make([]*InnoDBTransaction, len(transactions))
+	for i, transaction := range transactions {
+		r.snapshot.LongTransactions[i] = &InnoDBTransaction{
+			TrxID:           transaction.GetTrxID(),
+			TrxState:        transaction.GetState(),
+			TrxStarted:      transaction.GetStartTime(),
+			TrxDuration:     transaction.GetDuration(),
+			ThreadID:        transaction.GetThreadID(),
+			User:            transaction.GetUser(),
+			Host:            transaction.GetHost(),
+			DBName:          transaction.GetDBName(),
+			TrxRowsLocked:   transaction.GetRowsLocked(),
+			TrxRowsModified: transaction.GetRowsModified(),
+			TrxQuery:        transaction.GetQuery(),
+		}
+	}
+
+	return transactions, nil
+}
+
+// GetHistoryListLength gets the history list length from the application mysql and records it
+func (r *recordingApplicationMySQLRepo) GetHistoryListLength() (int, error) {
+	historyListLength, err := r.ApplicationMySQLRepo.GetHistoryListLength()
+	if err != nil {
+		return historyListLength, err
+	}
+
+	r.snapshot.HistoryListLength = &historyListLength
+
+	return historyListLength, nil
+}
+
+// GetLockWaits gets the innodb lock waits from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetLockWaits() ([]healthcheck.InnoDBLockWait, error) {
+	lockWaits, err := r.ApplicationMySQLRepo.GetLockWaits()
+	if err != nil {
+		return lockWaits, err
+	}
+
+	r.snapshot.LockWaits = make([]*InnoDBLockWait, len(lockWaits))
+	for i, lockWait := range lockWaits {
+		r.snapshot.LockWaits[i] = &InnoDBLockWait{
+			WaitingTrxID:     lockWait.GetWaitingTrxID(),
+			WaitingThreadID:  lockWait.GetWaitingThreadID(),
+			WaitingQuery:     lockWait.GetWaitingQuery(),
+			BlockingTrxID:    lockWait.GetBlockingTrxID(),
+			BlockingThreadID: lockWait.GetBlockingThreadID(),
+			BlockingQuery:    lockWait.GetBlockingQuery(),
+			WaitAge:          lockWait.GetWaitAge(),
+			LockedTable:      lockWait.GetLockedTable(),
+		}
+	}
+
+	return lockWaits, nil
+}
+
+// GetLatestDeadlock gets the latest innodb deadlock from the application mysql and records it
+func (r *recordingApplicationMySQLRepo) GetLatestDeadlock() (healthcheck.InnoDBDeadlock, error) {
+	deadlock, err := r.ApplicationMySQLRepo.GetLatestDeadlock()
+	if err != nil {
+		return deadlock, err
+	}
+
+	r.snapshot.LatestDeadlock = newSnapshotInnoDBDeadlock(deadlock)
+
+	return deadlock, nil
+}
+
+// GetTableSchemas gets the table schemas from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetTableSchemas() ([]healthcheck.TableSchema, error) {
+	tableSchemas, err := r.ApplicationMySQLRepo.GetTableSchemas()
+	if err != nil {
+		return tableSchemas, err
+	}
+
+	r.snapshot.TableSchemas = make([]*TableSchema, len(tableSchemas))
+	for i, tableSchema := range tableSchemas {
+		r.snapshot.TableSchemas[i] = &TableSchema{
+			DBName:     tableSchema.GetDBName(),
+			TableName:  tableSchema.GetTableName(),
+			Engine:     tableSchema.GetEngine(),
+			PrimaryKey: tableSchema.HasPrimaryKey(),
+		}
+	}
+
+	return tableSchemas, nil
+}
+
+// GetIndexStatistics gets the index statistics from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetIndexStatistics() ([]depmeta.IndexStatistic, error) {
+	indexStatistics, err := r.ApplicationMySQLRepo.GetIndexStatistics()
+	if err != nil {
+		return indexStatistics, err
+	}
+
+	r.snapshot.IndexStatistics = make([]*metadata.IndexStatistic, len(indexStatistics))
+	for i, indexStatistic := range indexStatistics {
+		r.snapshot.IndexStatistics[i] = &metadata.IndexStatistic{
+			DBName:      indexStatistic.GetDBName(),
+			TableName:   indexStatistic.GetTableName(),
+			IndexName:   indexStatistic.GetIndexName(),
+			Sequence:    indexStatistic.GetSequence(),
+			ColumnName:  indexStatistic.GetColumnName(),
+			Cardinality: indexStatistic.GetCardinality(),
+			Unique:      indexStatistic.IsUnique(),
+			Nullable:    indexStatistic.IsNullable(),
+		}
+	}
+
+	return indexStatistics, nil
+}
+
+// GetAutoIncrements gets the auto increments from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetAutoIncrements() ([]healthcheck.AutoIncrement, error) {
+	autoIncrements, err := r.ApplicationMySQLRepo.GetAutoIncrements()
+	if err != nil {
+		return autoIncrements, err
+	}
+
+	r.snapshot.AutoIncrements = make([]*AutoIncrement, len(autoIncrements))
+	for i, autoIncrement := range autoIncrements {
+		r.snapshot.AutoIncrements[i] = &AutoIncrement{
+			DBName:             autoIncrement.GetDBName(),
+			TableName:          autoIncrement.GetTableName(),
+			ColumnName:         autoIncrement.GetColumnName(),
+			DataType:           autoIncrement.GetDataType(),
+			ColumnType:         autoIncrement.GetColumnType(),
+			AutoIncrementValue: autoIncrement.GetAutoIncrement(),
+		}
+	}
+
+	return autoIncrements, nil
+}
+
+// GetUsers gets the accounts from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetUsers() ([]healthcheck.MySQLUser, error) {
+	users, err := r.ApplicationMySQLRepo.GetUsers()
+	if err != nil {
+		return users, err
+	}
+
+	r.snapshot.Users = make([]*MySQLUser, len(users))
+	for i, user := range users {
+		r.snapshot.Users[i] = &MySQLUser{
+			User:          user.GetUser(),
+			Host:          user.GetHost(),
+			Plugin:        user.GetPlugin(),
+			EmptyPassword: user.HasEmptyPassword(),
+			OldPassword:   user.HasOldPassword(),
+			AccountLocked: user.IsLocked(),
+		}
+	}
+
+	return users, nil
+}
+
+// GetGrants gets the grant statements of given account from the application mysql and records them
+func (r *recordingApplicationMySQLRepo) GetGrants(user, host string) ([]string, error) {
+	grants, err := r.ApplicationMySQLRepo.GetGrants(user, host)
+	if err != nil {
+		return grants, err
+	}
+
+	snapshotGrants := &SnapshotGrants{User: user, Host: host, Grants: make([]string, len(grants))}
+	copy(snapshotGrants.Grants, grants)
+	r.snapshot.Grants = append(r.snapshot.Grants, snapshotGrants)
+
+	return grants, nil
+}
+
+// newSnapshotInnoDBDeadlock copies the innodb deadlock for the snapshot
+func newSnapshotInnoDBDeadlock(deadlock healthcheck.InnoDBDeadlock) *InnoDBDeadlock {
+	d := &InnoDBDeadlock{
+		DetectedTime:     deadlock.GetDetectedTime(),
+		RolledBackTrxNum: deadlock.GetRolledBackTrxNum(),
+	}
+	for _, transaction := range deadlock.GetTransactions() {
+		d.Transactions = append(d.Transactions, &InnoDBDeadlockTransaction{
+			TrxNum:   transaction.GetTrxNum(),
+			TrxID:    transaction.GetTrxID(),
+			ThreadID: transaction.GetThreadID(),
+			Query:    transaction.GetQuery(),
+		})
+	}
+
+	return d
+}
+
+// newSnapshotReplicationStatus copies the replication status for the snapshot
+func newSnapshotReplicationStatus(replicationStatus healthcheck.ReplicationStatus) *ReplicationStatus {
+	rs, ok := replicationStatus.(*ReplicationStatus)
+	if ok {
+		rsCopy := *rs
+		return &rsCopy
+	}
+
+	slaveIORunning := replicationThreadNotRunning
+	if replicationStatus.IsIORunning() {
+		slaveIORunning = replicationThreadRunning
+	}
+	slaveSQLRunning := replicationThreadNotRunning
+	if replicationStatus.IsSQLRunning() {
+		slaveSQLRunning = replicationThreadRunning
+	}
+
+	return &ReplicationStatus{
+		ChannelName:         replicationStatus.GetChannelName(),
+		MasterHost:          replicationStatus.GetMasterHost(),
+		MasterPort:          replicationStatus.GetMasterPort(),
+		SlaveIORunning:      slaveIORunning,
+		SlaveSQLRunning:     slaveSQLRunning,
+		SecondsBehindMaster: replicationStatus.GetSecondsBehindMaster(),
+		LastIOError:         replicationStatus.GetLastIOError(),
+		LastSQLError:        replicationStatus.GetLastSQLError(),
+	}
+}
+
+// recordingPrometheusRepo records the inputs got from the prometheus into the snapshot
+type recordingPrometheusRepo struct {
+	healthcheck.PrometheusRepo
+	snapshot *Snapshot
+}
+
+// newRecordingPrometheusRepo returns a new *recordingPrometheusRepo
+func newRecordingPrometheusRepo(repo healthcheck.PrometheusRepo, snapshot *Snapshot) *recordingPrometheusRepo {
+	return &recordingPrometheusRepo{
+		PrometheusRepo: repo,
+		snapshot:       snapshot,
+	}
+}
+
+// GetFileSystems gets the file systems from the prometheus and records them
+func (r *recordingPrometheusRepo) GetFileSystems() ([]healthcheck.FileSystem, error) {
+	fileSystems, err := r.PrometheusRepo.GetFileSystems()
+	if err != nil {
+		return fileSystems, err
+	}
+
+	r.snapshot.FileSystems = make([]*FileSystem, len(fileSystems))
+	for i, fileSystem := range fileSystems {
+		r.snapshot.FileSystems[i] = &FileSystem{
+			MountPoint: fileSystem.GetMountPoint(),
+			Device:     fileSystem.GetDevice(),
+		}
+	}
+
+	return fileSystems, nil
+}
+
+// GetAvgBackupFailedRatio gets the average backup failed ratio from the prometheus and records it
+func (r *recordingPrometheusRepo) GetAvgBackupFailedRatio() ([]healthcheck.PrometheusData, error) {
+	datas, err := r.PrometheusRepo.GetAvgBackupFailedRatio()
+	return r.record(defaultAvgBackupFailedRatioItemName, datas, err)
+}
+
+// GetStatisticFailedRatio gets the statistic failed ratio from the prometheus and records it
+func (r *recordingPrometheusRepo) GetStatisticFailedRatio() ([]healthcheck.PrometheusData, error) {
+	datas, err := r.PrometheusRepo.GetStatisticFailedRatio()
+	return r.record(defaultStatisticFailedRatioItemName, datas, err)
+}
+
+// GetCPUUsage gets the cpu usage from the prometheus and records it
+func (r *recordingPrometheusRepo) GetCPUUsage() ([]healthcheck.PrometheusData, error) {
+	datas, err := r.PrometheusRepo.GetCPUUsage()
+	return r.record(defaultCPUUsageItemName, datas, err)
+}
+
+// GetIOUtil gets the io util from the prometheus and records it
+func (r *recordingPrometheusRepo) GetIOUtil() ([]healthcheck.PrometheusData, error) {
+	datas, err := r.PrometheusRepo.GetIOUtil()
+	return r.record(defaultIOUtilItemName, datas, err)
+}
+
+// GetDiskCapacityUsage gets the disk capacity usage from the prometheus and records it
+func (r *recordingPrometheusRepo) GetDiskCapacityUsage(mountPoints []string) ([]healthcheck.PrometheusData, error) {
+	datas, err := r.PrometheusRepo.GetDiskCapacityUsage(mountPoints)
+	return r.record(defaultDiskCapacityUsageItemName, datas, err)
+}
+
+// GetDiskCapacities gets the disk capacities from the prometheus and records them
+func (r *recordingPrometheusRepo) GetDiskCapacities(mountPoints []string) ([]healthcheck.DiskCapacity, error) {
+	diskCapacities, err := r.PrometheusRepo.GetDiskCapacities(mountPoints)
+	if err != nil {
+		return diskCapacities, err
+	}
+
+	r.snapshot.DiskCapacities = make([]*DiskCapacity, len(diskCapacities))
+	for i, diskCapacity := range diskCapacities {
+		r.snapshot.DiskCapacities[i] = NewDiskCapacity(diskCapacity.GetMountPoint(), diskCapacity.GetSize(), diskCapacity.GetUsedDatas())
+	}
+
+	return diskCapacities, nil
+}
+
+// GetConnectionUsage gets the connection usage from the prometheus and records it
+func (r *recordingPrometheusRepo) GetConnectionUsage() ([]healthcheck.PrometheusData, error) {
+	datas, err := r.PrometheusRepo.GetConnectionUsage()
+	return r.record(defaultConnectionUsageItemName, datas, err)
+}
+
+// GetAverageActiveSessionPercents gets the average active session percents from the prometheus and records it
+func (r *recordingPrometheusRepo) GetAverageActiveSessionPercents() ([]healthcheck.PrometheusData, error) {
+	datas, err := r.PrometheusRepo.GetAverageActiveSessionPercents()
+	return r.record(defaultAverageActiveSessionPercentsItemName, datas, err)
+}
+
+// GetCacheMissRatio gets the cache miss ratio from the prometheus and records it
+func (r *recordingPrometheusRepo) GetCacheMissRatio() ([]healthcheck.PrometheusData, error) {
+	datas, err := r.PrometheusRepo.GetCacheMissRatio()
+	return r.record(defaultCacheMissRatioItemName, datas, err)
+}
+
+// record records the prometheus series of given item name if it was got successfully
+func (r *recordingPrometheusRepo) record(itemName string, datas []healthcheck.PrometheusData, err error) ([]healthcheck.PrometheusData, error) {
+	if err != nil {
+		return datas, err
+	}
+
+	series := make([]*PrometheusData, len(datas))
+	for i, data := range datas {
+		series[i] = &PrometheusData{
+			Timestamp: data.GetTimestamp(),
+			Value:     data.GetValue(),
+		}
+	}
+	r.snapshot.PrometheusSeries[itemName] = series
+
+	return datas, nil
+}
+
+// recordingQueryRepo records the slow queries into the snapshot
+type recordingQueryRepo struct {
+	healthcheck.QueryRepo
+	snapshot *Snapshot
+}
+
+// newRecordingQueryRepo returns a new *recordingQueryRepo
+func newRecordingQueryRepo(repo healthcheck.QueryRepo, snapshot *Snapshot) *recordingQueryRepo {
+	return &recordingQueryRepo{
+		QueryRepo: repo,
+		snapshot:  snapshot,
+	}
+}
+
+// GetSlowQuery gets the slow queries and records them,
+// the queries are copied before the slow query item sets the db names to them
+func (r *recordingQueryRepo) GetSlowQuery() ([]depquery.Query, error) {
+	queries, err := r.QueryRepo.GetSlowQuery()
+	if err != nil {
+		return queries, err
+	}
+
+	r.snapshot.SlowQueries = make([]*query.Query, len(queries))
+	for i, q := range queries {
+		r.snapshot.SlowQueries[i] = &query.Query{
+			SQLID:           q.GetSQLID(),
+			Fingerprint:     q.GetFingerprint(),
+			Example:         q.GetExample(),
+			DBName:          q.GetDBName(),
+			ExecCount:       q.GetExecCount(),
+			TotalExecTime:   q.GetTotalExecTime(),
+			AvgExecTime:     q.GetAvgExecTime(),
+			RowsExaminedMax: q.GetRowsExaminedMax(),
+		}
+	}
+
+	return queries, nil
+}
@@ -0,0 +1,266 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/romberli/das/config"
+	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/das/internal/app/query"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSnapshotMySQLDir      = "/data/mysql/data"
+	testSnapshotMountPoint    = "/data"
+	testSnapshotDevice        = "/dev/sdb1"
+	testSnapshotGTIDExecuted  = "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10"
+	testSnapshotMasterGTIDSet = "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-8"
+	testSnapshotMasterPortNum = 3307
+
+	testSnapshotHistoryListLength = 100
+)
+
+func TestSnapshot_All(t *testing.T) {
+	TestSnapshot_NewSnapshotWithJSON(t)
+	TestSnapshot_Validate(t)
+	TestSnapshot_Replay(t)
+	TestSnapshot_Record(t)
+	TestDefaultEngine_RunWithSnapshot(t)
+}
+
+func testInitSnapshot() *Snapshot {
+	gtidExecuted := testSnapshotGTIDExecuted
+	historyListLength := testSnapshotHistoryListLength
+
+	snapshot := NewEmptySnapshot()
+	snapshot.OperationID = testResultOperationID
+	snapshot.HostIP = testResultHostIP
+	snapshot.PortNum = testResultPortNum
+	snapshot.EndTime = time.Now().Truncate(time.Second)
+	snapshot.StartTime = snapshot.EndTime.Add(-constant.Day)
+	snapshot.Step = int(testHealthcheckStep.Seconds())
+	snapshot.Variables = []*GlobalVariable{NewGlobalVariable(dbConfigSyncBinlog, "1"), NewGlobalVariable(dbConfigLogBin, "ON")}
+	snapshot.MySQLDirs = []string{testSnapshotMySQLDir}
+	snapshot.LargeTables = []*Table{{DBName: testResultTrendDBName, TableName: testResultTrendTableName, TableRows: 50000000, TableSize: 40}}
+	snapshot.DBNames = []*SnapshotDBName{{TableNames: []string{testResultTrendTableName}, DBName: testResultTrendDBName}}
+	snapshot.ReplicationStatus = []*ReplicationStatus{{MasterHost: testResultHostIP, MasterPort: testSnapshotMasterPortNum,
+		SlaveIORunning: replicationThreadRunning, SlaveSQLRunning: replicationThreadRunning}}
+	snapshot.GTIDExecuted = &gtidExecuted
+	snapshot.MasterGTIDExecuted = []*SnapshotMasterGTIDExecuted{{HostIP: testResultHostIP, PortNum: testSnapshotMasterPortNum, GTIDExecuted: testSnapshotMasterGTIDSet}}
+	snapshot.GTIDSubtracts = []*SnapshotGTIDSubtract{{GTIDSet1: testSnapshotGTIDExecuted, GTIDSet2: testSnapshotMasterGTIDSet, Result: constant.EmptyString}}
+	snapshot.GroupMembers = []*GroupMember{}
+	snapshot.LongTransactions = []*InnoDBTransaction{{TrxID: "12345", TrxDuration: 120, ThreadID: 10, TrxQuery: "select * from t01 for update"}}
+	snapshot.HistoryListLength = &historyListLength
+	snapshot.LockWaits = []*InnoDBLockWait{}
+	snapshot.LatestDeadlock = NewEmptyInnoDBDeadlock()
+	snapshot.TableSchemas = []*TableSchema{{DBName: testSchemaDBName, TableName: testSchemaTableName, Engine: innodbEngineName, PrimaryKey: true}}
+	snapshot.IndexStatistics = []*metadata.IndexStatistic{}
+	snapshot.AutoIncrements = []*AutoIncrement{}
+	snapshot.Users = []*MySQLUser{{User: testSecurityUser, Host: testSecurityHost, Plugin: "mysql_native_password"}}
+	snapshot.Grants = []*SnapshotGrants{{User: testSecurityUser, Host: testSecurityHost, Grants: []string{"GRANT SUPER ON *.* TO `u01`@`%`"}}}
+	snapshot.FileSystems = []*FileSystem{{MountPoint: constant.RootDir, Device: "/dev/sda1"}, {MountPoint: testSnapshotMountPoint, Device: testSnapshotDevice}}
+	snapshot.DiskCapacities = []*DiskCapacity{{MountPoint: testSnapshotMountPoint, Size: 100 * bytesPerGB,
+		UsedDatas: []*PrometheusData{{Timestamp: "2022-06-01 10:00:00", Value: 50 * bytesPerGB}, {Timestamp: "2022-06-02 10:00:00", Value: 60 * bytesPerGB}}}}
+	snapshot.PrometheusSeries[defaultCPUUsageItemName] = []*PrometheusData{{Timestamp: "2022-06-01 10:00:00", Value: 0.95}}
+	snapshot.PrometheusSeries[defaultDiskCapacityUsageItemName] = []*PrometheusData{}
+	snapshot.SlowQueries = []*query.Query{{SQLID: testResultTrendSQLID, Fingerprint: "select * from t01 where id = ?", RowsExaminedMax: 1000000}}
+
+	return snapshot
+}
+
+func TestSnapshot_NewSnapshotWithJSON(t *testing.T) {
+	asst := assert.New(t)
+
+	snapshot := testInitSnapshot()
+	jsonBytes, err := snapshot.Marshal()
+	asst.Nil(err, common.CombineMessageWithError("test NewSnapshotWithJSON() failed", err))
+	s, err := NewSnapshotWithJSON(jsonBytes)
+	asst.Nil(err, common.CombineMessageWithError("test NewSnapshotWithJSON() failed", err))
+	asst.Equal(testResultHostIP, s.GetHostIP(), "test NewSnapshotWithJSON() failed")
+	asst.Equal(testResultPortNum, s.GetPortNum(), "test NewSnapshotWithJSON() failed")
+	asst.True(snapshot.GetStartTime().Equal(s.GetStartTime()), "test NewSnapshotWithJSON() failed")
+	asst.Equal(testHealthcheckStep, s.GetStep(), "test NewSnapshotWithJSON() failed")
+	asst.Equal(testSnapshotGTIDExecuted, *s.GTIDExecuted, "test NewSnapshotWithJSON() failed")
+	// the empty series is collected, the missing series is not
+	asst.NotNil(s.PrometheusSeries[defaultDiskCapacityUsageItemName], "test NewSnapshotWithJSON() failed")
+	_, ok := s.PrometheusSeries[defaultIOUtilItemName]
+	asst.False(ok, "test NewSnapshotWithJSON() failed")
+	// invalid json
+	_, err = NewSnapshotWithJSON([]byte(testResultTableRowsHigh))
+	asst.NotNil(err, "test NewSnapshotWithJSON() failed")
+}
+
+func TestSnapshot_Validate(t *testing.T) {
+	asst := assert.New(t)
+
+	snapshot := testInitSnapshot()
+	err := snapshot.Validate()
+	asst.Nil(err, common.CombineMessageWithError("test Validate() failed", err))
+	snapshot.Version = defaultSnapshotVersion + 1
+	asst.NotNil(snapshot.Validate(), "test Validate() failed")
+	snapshot = testInitSnapshot()
+	snapshot.HostIP = constant.EmptyString
+	asst.NotNil(snapshot.Validate(), "test Validate() failed")
+	snapshot = testInitSnapshot()
+	snapshot.EndTime = snapshot.StartTime
+	asst.NotNil(snapshot.Validate(), "test Validate() failed")
+}
+
+func TestSnapshot_Replay(t *testing.T) {
+	asst := assert.New(t)
+
+	snapshot := testInitSnapshot()
+	amr := NewSnapshotApplicationMySQLRepo(snapshot)
+	variables, err := amr.GetVariables([]string{"SYNC_BINLOG", dbConfigBinlogFormat})
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(1, len(variables), "test Replay() failed")
+	asst.Equal("1", variables[constant.ZeroInt].GetValue(), "test Replay() failed")
+	dbName, err := amr.GetDBName([]string{testResultTrendTableName})
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(testResultTrendDBName, dbName, "test Replay() failed")
+	masterGTIDExecuted, err := amr.GetMasterGTIDExecuted(testResultHostIP, testSnapshotMasterPortNum)
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(testSnapshotMasterGTIDSet, masterGTIDExecuted, "test Replay() failed")
+	groupMembers, err := amr.GetGroupMembers()
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(constant.ZeroInt, len(groupMembers), "test Replay() failed")
+	historyListLength, err := amr.GetHistoryListLength()
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(testSnapshotHistoryListLength, historyListLength, "test Replay() failed")
+	deadlock, err := amr.GetLatestDeadlock()
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.True(deadlock.GetDetectedTime().IsZero(), "test Replay() failed")
+	tableSchemas, err := amr.GetTableSchemas()
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.True(tableSchemas[constant.ZeroInt].HasPrimaryKey(), "test Replay() failed")
+	grants, err := amr.GetGrants(testSecurityUser, testSecurityHost)
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(1, len(grants), "test Replay() failed")
+	// not collected
+	_, err = amr.GetDBName([]string{testResultTrendNewTableName})
+	asst.NotNil(err, "test Replay() failed")
+	_, err = amr.GetMasterGTIDExecuted(testResultHostIP, testResultPortNum)
+	asst.NotNil(err, "test Replay() failed")
+	_, err = amr.GetGrants(testSecurityUser, testResultHostIP)
+	asst.NotNil(err, "test Replay() failed")
+
+	pr := NewSnapshotPrometheusRepo(snapshot)
+	cpuUsage, err := pr.GetCPUUsage()
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(0.95, cpuUsage[constant.ZeroInt].GetValue(), "test Replay() failed")
+	diskCapacityUsage, err := pr.GetDiskCapacityUsage([]string{testSnapshotMountPoint})
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(constant.ZeroInt, len(diskCapacityUsage), "test Replay() failed")
+	diskCapacities, err := pr.GetDiskCapacities([]string{testSnapshotMountPoint})
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	asst.Equal(2, len(diskCapacities[constant.ZeroInt].GetUsedDatas()), "test Replay() failed")
+	_, err = pr.GetIOUtil()
+	asst.NotNil(err, "test Replay() failed")
+
+	// the slow query item sets the db names to the queries, it should not change the snapshot
+	qr := NewSnapshotQueryRepo(snapshot)
+	slowQueries, err := qr.GetSlowQuery()
+	asst.Nil(err, common.CombineMessageWithError("test Replay() failed", err))
+	slowQueries[constant.ZeroInt].SetDBName(testResultTrendDBName)
+	asst.Equal(constant.EmptyString, snapshot.SlowQueries[constant.ZeroInt].GetDBName(), "test Replay() failed")
+}
+
+func TestSnapshot_Record(t *testing.T) {
+	asst := assert.New(t)
+
+	source := testInitSnapshot()
+	snapshot := NewEmptySnapshot()
+	// record the inputs which are served by the source snapshot
+	amr := newRecordingApplicationMySQLRepo(NewSnapshotApplicationMySQLRepo(source), snapshot)
+	_, err := amr.GetVariables([]string{dbConfigSyncBinlog})
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	_, err = amr.GetVariables([]string{dbConfigSyncBinlog, dbConfigLogBin})
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	asst.Equal(2, len(snapshot.Variables), "test Record() failed")
+	_, err = amr.GetReplicationStatus()
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	asst.Equal(*source.ReplicationStatus[constant.ZeroInt], *snapshot.ReplicationStatus[constant.ZeroInt], "test Record() failed")
+	_, err = amr.GetGTIDSubtract(testSnapshotGTIDExecuted, testSnapshotMasterGTIDSet)
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	asst.Equal(1, len(snapshot.GTIDSubtracts), "test Record() failed")
+	_, err = amr.GetLongTransactions()
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	asst.Equal(*source.LongTransactions[constant.ZeroInt], *snapshot.LongTransactions[constant.ZeroInt], "test Record() failed")
+	_, err = amr.GetLockWaits()
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	asst.NotNil(snapshot.LockWaits, "test Record() failed")
+	_, err = amr.GetTableSchemas()
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	asst.Equal(*source.TableSchemas[constant.ZeroInt], *snapshot.TableSchemas[constant.ZeroInt], "test Record() failed")
+	// the failed calls are not recorded
+	_, err = amr.GetDBName([]string{testResultTrendNewTableName})
+	asst.NotNil(err, "test Record() failed")
+	asst.Nil(snapshot.DBNames, "test Record() failed")
+
+	pr := newRecordingPrometheusRepo(NewSnapshotPrometheusRepo(source), snapshot)
+	_, err = pr.GetCPUUsage()
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	asst.Equal(source.PrometheusSeries[defaultCPUUsageItemName], snapshot.PrometheusSeries[defaultCPUUsageItemName], "test Record() failed")
+	_, err = pr.GetIOUtil()
+	asst.NotNil(err, "test Record() failed")
+	_, ok := snapshot.PrometheusSeries[defaultIOUtilItemName]
+	asst.False(ok, "test Record() failed")
+
+	qr := newRecordingQueryRepo(NewSnapshotQueryRepo(source), snapshot)
+	slowQueries, err := qr.GetSlowQuery()
+	asst.Nil(err, common.CombineMessageWithError("test Record() failed", err))
+	slowQueries[constant.ZeroInt].SetDBName(testResultTrendDBName)
+	asst.Equal(constant.EmptyString, snapshot.SlowQueries[constant.ZeroInt].GetDBName(), "test Record() failed")
+}
+
+func TestDefaultEngine_RunWithSnapshot(t *testing.T) {
+	asst := assert.New(t)
+
+	viper.Set(config.AlertSMTPEnabledKey, false)
+	defer viper.Set(config.AlertSMTPEnabledKey, true)
+	// run with the live data sources and record the snapshot
+	id, err := testDASRepo.InitOperation(testOperationInfo.GetUser().Identity(), constant.ZeroInt, constant.ZeroInt, testHealthcheckMySQLServerID,
+		testOperationInfo.GetStartTime(), testOperationInfo.GetEndTime(), testOperationInfo.GetStep())
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	operationInfo := NewOperationInfo(id, testOperationInfo.GetUser(), testOperationInfo.GetApps(), testOperationInfo.GetMySQLServer(),
+		testOperationInfo.GetMonitorSystem(), testOperationInfo.GetStartTime(), testOperationInfo.GetEndTime(), testOperationInfo.GetStep())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt(config.HealthcheckTimeoutKey))*time.Second)
+	defer cancel()
+	de := newDefaultEngine(ctx, cancel, operationInfo, testDASRepo, testApplicationMySQLRepo, testPrometheusRepo, testQueryRepo)
+	de.recordSnapshot()
+	err = de.run()
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	liveResult, err := testDASRepo.GetResultByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	s, err := testDASRepo.GetSnapshotByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	snapshot, ok := s.(*Snapshot)
+	asst.True(ok, "test RunWithSnapshot() failed")
+	// score the snapshot without the live data sources, the score should be the same
+	offlineID, err := testDASRepo.InitOperation(testOperationInfo.GetUser().Identity(), constant.ZeroInt, constant.ZeroInt, testHealthcheckMySQLServerID,
+		snapshot.GetStartTime(), snapshot.GetEndTime(), snapshot.GetStep())
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	offlineOperationInfo := NewOperationInfo(offlineID, testOperationInfo.GetUser(), testOperationInfo.GetApps(), testOperationInfo.GetMySQLServer(),
+		testOperationInfo.GetMonitorSystem(), snapshot.GetStartTime(), snapshot.GetEndTime(), snapshot.GetStep())
+	offlineCtx, offlineCancel := context.WithTimeout(context.Background(), time.Duration(viper.GetInt(config.HealthcheckTimeoutKey))*time.Second)
+	defer offlineCancel()
+	offlineEngine := newDefaultEngine(offlineCtx, offlineCancel, offlineOperationInfo, testDASRepo,
+		NewSnapshotApplicationMySQLRepo(snapshot), NewSnapshotPrometheusRepo(snapshot), NewSnapshotQueryRepo(snapshot))
+	err = offlineEngine.run()
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	offlineResult, err := testDASRepo.GetResultByOperationID(offlineID)
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	asst.Equal(liveResult.GetWeightedAverageScore(), offlineResult.GetWeightedAverageScore(), "test RunWithSnapshot() failed")
+	// delete
+	err = deleteByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	err = deleteByOperationID(offlineID)
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+	err = testDeleteSnapshotByOperationID(id)
+	asst.Nil(err, common.CombineMessageWithError("test RunWithSnapshot() failed", err))
+}
@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"sort"
 	"strings"
 	"time"
 
@@ -12,45 +13,12 @@ import (
 )
 
 const (
-	dbConfigMaxUserConnection         = "max_user_connection"
-	dbConfigSlaveParallelWorkers      = "slave_parallel_workers"
-	dbConfigLogBin                    = "log_bin"
-	dbConfigBinlogFormat              = "binlog_format"
-	dbConfigBinlogRowImage            = "binlog_row_image"
-	dbConfigSyncBinlog                = "sync_binlog"
-	dbConfigInnodbFlushLogAtTrxCommit = "innodb_flush_log_at_trx_commit"
-	dbConfigGTIDMode                  = "gtid_mode"
-	dbConfigEnforceGTIDConsistency    = "enforce_gtid_consistency"
-	dbConfigSlaveParallelType         = "slave_parallel_type"
-	dbConfigMasterInfoRepository      = "master_info_repository"
-	dbConfigRelayLogInfoRepository    = "relay_log_info_repository"
-	dbConfigReportHost                = "report_host"
-	dbConfigReportPort                = "report_port"
-	dbConfigInnodbFlushMethod         = "innodb_flush_method"
-	dbConfigInnodbMonitorEnable       = "innodb_monitor_enable"
-	dbConfigInnodbPrintAllDeadlocks   = "innodb_print_all_deadlocks"
-	dbConfigSlowQueryLog              = "slow_query_log"
-	dbConfigPerformanceSchema         = "performance_schema"
-
-	dbConfigMaxUserConnectionValid         = 2000
-	dbConfigSlaveParallelWorkersValid      = 16
-	dbConfigLogBinValid                    = "ON"
-	dbConfigBinlogFormatValid              = "ROW"
-	dbConfigBinlogRowImageValid            = "FULL"
-	dbConfigSyncBinlogValid                = "1"
-	dbConfigInnodbFlushLogAtTrxCommitValid = "1"
-	dbConfigGTIDModeValid                  = "ON"
-	dbConfigEnforceGTIDConsistencyValid    = "ON"
-	dbConfigSlaveParallelTypeValid         = "LOGICAL_CLOCK"
-	dbConfigMasterInfoRepositoryValid      = "TABLE"
-	dbConfigRelayLogInfoRepositoryValid    = "TABLE"
-	dbConfigReportHostValid                = constant.EmptyString
-	dbConfigReportPortValid                = constant.EmptyString
-	dbConfigInnodbFlushMethodValid         = "O_DIRECT"
-	dbConfigInnodbMonitorEnableValid       = "ALL"
-	dbConfigInnodbPrintAllDeadlocksValid   = "ON"
-	dbConfigSlowQueryLogValid              = "ON"
-	dbConfigPerformanceSchemaValid         = "ON"
+	dbConfigLogBin       = "log_bin"
+	dbConfigBinlogFormat = "binlog_format"
+	dbConfigSyncBinlog   = "sync_binlog"
+	dbConfigGTIDMode     = "gtid_mode"
+	dbConfigReportHost   = "report_host"
+	dbConfigReportPort   = "report_port"
 )
 
 var (
@@ -58,26 +26,15 @@ var (
 	_ healthcheck.Variable       = (*GlobalVariable)(nil)
 	_ healthcheck.Table          = (*Table)(nil)
 	_ healthcheck.PrometheusData = (*PrometheusData)(nil)
-
-	dbConfigVariableNames = map[string]string{
-		dbConfigLogBin:                    dbConfigLogBinValid,
-		dbConfigBinlogFormat:              dbConfigBinlogFormatValid,
-		dbConfigBinlogRowImage:            dbConfigBinlogRowImageValid,
-		dbConfigSyncBinlog:                dbConfigSyncBinlogValid,
-		dbConfigInnodbFlushLogAtTrxCommit: dbConfigInnodbFlushLogAtTrxCommitValid,
-		dbConfigGTIDMode:                  dbConfigGTIDModeValid,
-		dbConfigEnforceGTIDConsistency:    dbConfigEnforceGTIDConsistencyValid,
-		dbConfigSlaveParallelType:         dbConfigSlaveParallelTypeValid,
-		dbConfigMasterInfoRepository:      dbConfigMasterInfoRepositoryValid,
-		dbConfigRelayLogInfoRepository:    dbConfigRelayLogInfoRepositoryValid,
-		dbConfigReportHost:                dbConfigReportHostValid,
-		dbConfigReportPort:                dbConfigReportPortValid,
-		dbConfigInnodbFlushMethod:         dbConfigInnodbFlushMethodValid,
-		// dbConfigInnodbMonitorEnable:       dbConfigInnodbMonitorEnableValid,
-		dbConfigInnodbPrintAllDeadlocks: dbConfigInnodbPrintAllDeadlocksValid,
-		dbConfigSlowQueryLog:            dbConfigSlowQueryLogValid,
-		dbConfigPerformanceSchema:       dbConfigPerformanceSchemaValid,
-	}
+	_ healthcheck.DiskCapacity   = (*DiskCapacity)(nil)
+
+	_ healthcheck.InnoDBTransaction         = (*InnoDBTransaction)(nil)
+	_ healthcheck.InnoDBLockWait            = (*InnoDBLockWait)(nil)
+	_ healthcheck.InnoDBDeadlockTransaction = (*InnoDBDeadlockTransaction)(nil)
+	_ healthcheck.InnoDBDeadlock            = (*InnoDBDeadlock)(nil)
+	_ healthcheck.TableSchema               = (*TableSchema)(nil)
+	_ healthcheck.AutoIncrement             = (*AutoIncrement)(nil)
+	_ healthcheck.MySQLUser                 = (*MySQLUser)(nil)
 )
 
 type OperationInfo struct {
@@ -162,20 +119,25 @@ func (oi *OperationInfo) GetAppName() string {
 }
 
 type OperationHistory struct {
-	ID             int       `middleware:"id" json:"id"`
-	UserID         int       `middleware:"user_id" json:"user_id"`
-	AccountName    string    `middleware:"account_name" json:"account_name"`
-	MySQLServerID  int       `middleware:"mysql_server_id" json:"mysql_server_id"`
-	HostIP         string    `middleware:"host_ip" json:"host_ip"`
-	PortNum        int       `middleware:"port_num" json:"port_num"`
-	StartTime      time.Time `middleware:"start_time" json:"start_time"`
-	EndTime        time.Time `middleware:"end_time" json:"end_time"`
-	Step           int       `middleware:"step" json:"step"`
-	Status         int       `middleware:"status" json:"status"`
-	Message        string    `middleware:"message" json:"message"`
-	DelFlag        int       `middleware:"del_flag" json:"del_flag"`
-	CreateTime     time.Time `middleware:"create_time" json:"create_time"`
-	LastUpdateTime time.Time `middleware:"last_update_time" json:"last_update_time"`
+	ID                 int       `middleware:"id" json:"id"`
+	UserID             int       `middleware:"user_id" json:"user_id"`
+	AccountName        string    `middleware:"account_name" json:"account_name"`
+	ScheduleID         int       `middleware:"schedule_id" json:"schedule_id"`
+	ClusterOperationID int       `middleware:"cluster_operation_id" json:"cluster_operation_id"`
+	MySQLServerID      int       `middleware:"mysql_server_id" json:"mysql_server_id"`
+	HostIP             string    `middleware:"host_ip" json:"host_ip"`
+	PortNum            int       `middleware:"port_num" json:"port_num"`
+	StartTime          time.Time `middleware:"start_time" json:"start_time"`
+	EndTime            time.Time `middleware:"end_time" json:"end_time"`
+	Step               int       `middleware:"step" json:"step"`
+	ProfileID          int       `middleware:"profile_id" json:"profile_id"`
+	ConfigVersion      int       `middleware:"config_version" json:"config_version"`
+	Priority           int       `middleware:"priority" json:"priority"`
+	Status             int       `middleware:"status" json:"status"`
+	Message            string    `middleware:"message" json:"message"`
+	DelFlag            int       `middleware:"del_flag" json:"del_flag"`
+	CreateTime         time.Time `middleware:"create_time" json:"create_time"`
+	LastUpdateTime     time.Time `middleware:"last_update_time" json:"last_update_time"`
 }
 
 // NewOperationHistory returns healthcheck.OperationHistory
@@ -223,6 +185,16 @@ func (oh *OperationHistory) GetAccountName() string {
 	return oh.AccountName
 }
 
+// GetScheduleID returns the schedule id, 0 means the operation was triggered manually
+func (oh *OperationHistory) GetScheduleID() int {
+	return oh.ScheduleID
+}
+
+// GetClusterOperationID returns the cluster operation id, 0 means the operation was not a part of a cluster check
+func (oh *OperationHistory) GetClusterOperationID() int {
+	return oh.ClusterOperationID
+}
+
 // GetMySQLServerID returns the mysql server id
 func (oh *OperationHistory) GetMySQLServerID() int {
 	return oh.MySQLServerID
@@ -253,6 +225,21 @@ func (oh *OperationHistory) GetStep() int {
 	return oh.Step
 }
 
+// GetProfileID returns the id of the scoring profile which was used by the operation, 0 means the global default engine config
+func (oh *OperationHistory) GetProfileID() int {
+	return oh.ProfileID
+}
+
+// GetConfigVersion returns the engine config version which was used by the operation, 0 means the config was never changed through the api
+func (oh *OperationHistory) GetConfigVersion() int {
+	return oh.ConfigVersion
+}
+
+// GetPriority returns the priority of the operation in the queue, the operation with higher priority runs first
+func (oh *OperationHistory) GetPriority() int {
+	return oh.Priority
+}
+
 // GetStatus returns the status
 func (oh *OperationHistory) GetStatus() int {
 	return oh.Status
@@ -369,7 +356,7 @@ func (dic *DefaultItemConfig) GetScoreDeductionPerUnitHigh() float64 {
 
 // GetMaxScoreDeductionHigh returns the max score deduction high
 func (dic *DefaultItemConfig) GetMaxScoreDeductionHigh() float64 {
-	return dic.ScoreDeductionPerUnitHigh
+	return dic.MaxScoreDeductionHigh
 }
 
 // GetScoreDeductionPerUnitMedium returns the score deduction per unit medium
@@ -405,6 +392,19 @@ func NewEmptyDefaultEngineConfig() healthcheck.EngineConfig {
 	return DefaultEngineConfig{}
 }
 
+// GetItemNames returns the names of all the configured items, ordered by the config identity
+func (dec DefaultEngineConfig) GetItemNames() []string {
+	itemNames := make([]string, constant.ZeroInt, len(dec))
+	for itemName := range dec {
+		itemNames = append(itemNames, itemName)
+	}
+	sort.Slice(itemNames, func(i, j int) bool {
+		return dec[itemNames[i]].GetID() < dec[itemNames[j]].GetID()
+	})
+
+	return itemNames
+}
+
 // GetItemConfig returns healthcheck.ItemConfig with given item name
 func (dec DefaultEngineConfig) GetItemConfig(item string) healthcheck.ItemConfig {
 	return dec[item]
@@ -423,6 +423,11 @@ func (dec DefaultEngineConfig) Validate() error {
 		return message.NewMessage(msghc.ErrHealthcheckDefaultEngineEmpty)
 	}
 	for itemName, defaultItemConfig := range dec {
+		// validate if the item is registered
+		_, ok := GetCheckItem(itemName)
+		if !ok {
+			return message.NewMessage(msghc.ErrHealthcheckCheckItemNotRegistered, itemName)
+		}
 		// validate item weight
 		if defaultItemConfig.GetItemWeight() > defaultHundred || defaultItemConfig.GetItemWeight() < constant.ZeroInt {
 			return message.NewMessage(msghc.ErrHealthcheckItemWeightItemInvalid, itemName, defaultItemConfig.GetItemWeight())
@@ -542,6 +547,425 @@ func (t *Table) GetSize() float64 {
 	return t.TableSize
 }
 
+// ReplicationStatus is the replication status of a replication channel
+type ReplicationStatus struct {
+	ChannelName         string `middleware:"channel_name" json:"channel_name"`
+	MasterHost          string `middleware:"master_host" json:"master_host"`
+	MasterPort          int    `middleware:"master_port" json:"master_port"`
+	SlaveIORunning      string `middleware:"slave_io_running" json:"slave_io_running"`
+	SlaveSQLRunning     string `middleware:"slave_sql_running" json:"slave_sql_running"`
+	SecondsBehindMaster int    `middleware:"seconds_behind_master" json:"seconds_behind_master"`
+	LastIOError         string `middleware:"last_io_error" json:"last_io_error"`
+	LastSQLError        string `middleware:"last_sql_error" json:"last_sql_error"`
+}
+
+// NewReplicationStatus returns a new healthcheck.ReplicationStatus
+func NewReplicationStatus(channelName, masterHost string, masterPort int, slaveIORunning, slaveSQLRunning string,
+	secondsBehindMaster int, lastIOError, lastSQLError string) healthcheck.ReplicationStatus {
+	return &ReplicationStatus{
+		ChannelName:         channelName,
+		MasterHost:          masterHost,
+		MasterPort:          masterPort,
+		SlaveIORunning:      slaveIORunning,
+		SlaveSQLRunning:     slaveSQLRunning,
+		SecondsBehindMaster: secondsBehindMaster,
+		LastIOError:         lastIOError,
+		LastSQLError:        lastSQLError,
+	}
+}
+
+// GetChannelName returns the replication channel name
+func (rs *ReplicationStatus) GetChannelName() string {
+	return rs.ChannelName
+}
+
+// GetMasterHost returns the host of the master
+func (rs *ReplicationStatus) GetMasterHost() string {
+	return rs.MasterHost
+}
+
+// GetMasterPort returns the port of the master
+func (rs *ReplicationStatus) GetMasterPort() int {
+	return rs.MasterPort
+}
+
+// IsIORunning returns if the io thread is running
+func (rs *ReplicationStatus) IsIORunning() bool {
+	return strings.EqualFold(rs.SlaveIORunning, replicationThreadRunning)
+}
+
+// IsSQLRunning returns if the sql thread is running
+func (rs *ReplicationStatus) IsSQLRunning() bool {
+	return strings.EqualFold(rs.SlaveSQLRunning, replicationThreadRunning)
+}
+
+// GetSecondsBehindMaster returns the replication lag, it returns -1 if the lag is unknown
+func (rs *ReplicationStatus) GetSecondsBehindMaster() int {
+	return rs.SecondsBehindMaster
+}
+
+// GetLastIOError returns the last error of the io thread
+func (rs *ReplicationStatus) GetLastIOError() string {
+	return rs.LastIOError
+}
+
+// GetLastSQLError returns the last error of the sql thread
+func (rs *ReplicationStatus) GetLastSQLError() string {
+	return rs.LastSQLError
+}
+
+// GroupMember is a member of the group replication
+type GroupMember struct {
+	MemberHost  string `middleware:"member_host" json:"member_host"`
+	MemberPort  int    `middleware:"member_port" json:"member_port"`
+	MemberState string `middleware:"member_state" json:"member_state"`
+}
+
+// NewGroupMember returns a new healthcheck.GroupMember
+func NewGroupMember(hostIP string, portNum int, state string) healthcheck.GroupMember {
+	return &GroupMember{
+		MemberHost:  hostIP,
+		MemberPort:  portNum,
+		MemberState: state,
+	}
+}
+
+// NewEmptyGroupMember returns an empty healthcheck.GroupMember
+func NewEmptyGroupMember() healthcheck.GroupMember {
+	return &GroupMember{}
+}
+
+// GetHostIP returns the host ip of the member
+func (gm *GroupMember) GetHostIP() string {
+	return gm.MemberHost
+}
+
+// GetPortNum returns the port number of the member
+func (gm *GroupMember) GetPortNum() int {
+	return gm.MemberPort
+}
+
+// GetState returns the state of the member
+func (gm *GroupMember) GetState() string {
+	return gm.MemberState
+}
+
+// InnoDBTransaction is a running innodb transaction
+type InnoDBTransaction struct {
+	TrxID           string    `middleware:"trx_id" json:"trx_id"`
+	TrxState        string    `middleware:"trx_state" json:"trx_state"`
+	TrxStarted      time.Time `middleware:"trx_started" json:"trx_started"`
+	TrxDuration     int       `middleware:"trx_duration" json:"trx_duration"`
+	ThreadID        int       `middleware:"thread_id" json:"thread_id"`
+	User            string    `middleware:"user" json:"user"`
+	Host            string    `middleware:"host" json:"host"`
+	DBName          string    `middleware:"db_name" json:"db_name"`
+	TrxRowsLocked   int       `middleware:"trx_rows_locked" json:"trx_rows_locked"`
+	TrxRowsModified int       `middleware:"trx_rows_modified" json:"trx_rows_modified"`
+	TrxQuery        string    `middleware:"trx_query" json:"trx_query"`
+}
+
+// NewEmptyInnoDBTransaction returns an empty healthcheck.InnoDBTransaction
+func NewEmptyInnoDBTransaction() healthcheck.InnoDBTransaction {
+	return &InnoDBTransaction{}
+}
+
+// GetTrxID returns the transaction id
+func (it *InnoDBTransaction) GetTrxID() string {
+	return it.TrxID
+}
+
+// GetState returns the state of the transaction
+func (it *InnoDBTransaction) GetState() string {
+	return it.TrxState
+}
+
+// GetStartTime returns the time when the transaction started
+func (it *InnoDBTransaction) GetStartTime() time.Time {
+	return it.TrxStarted
+}
+
+// GetDuration returns how long the transaction has been running, the unit is second
+func (it *InnoDBTransaction) GetDuration() int {
+	return it.TrxDuration
+}
+
+// GetThreadID returns the processlist id of the thread which runs the transaction
+func (it *InnoDBTransaction) GetThreadID() int {
+	return it.ThreadID
+}
+
+// GetUser returns the user of the thread
+func (it *InnoDBTransaction) GetUser() string {
+	return it.User
+}
+
+// GetHost returns the client host of the thread
+func (it *InnoDBTransaction) GetHost() string {
+	return it.Host
+}
+
+// GetDBName returns the default db name of the thread
+func (it *InnoDBTransaction) GetDBName() string {
+	return it.DBName
+}
+
+// GetRowsLocked returns the approximate number of the rows locked by the transaction
+func (it *InnoDBTransaction) GetRowsLocked() int {
+	return it.TrxRowsLocked
+}
+
+// GetRowsModified returns the number of the rows modified by the transaction
+func (it *InnoDBTransaction) GetRowsModified() int {
+	return it.TrxRowsModified
+}
+
+// GetQuery returns the sql which is being executed by the transaction, it is empty if the transaction is idle
+func (it *InnoDBTransaction) GetQuery() string {
+	return it.TrxQuery
+}
+
+// InnoDBLockWait is an innodb transaction which is waiting for a lock held by another transaction
+type InnoDBLockWait struct {
+	WaitingTrxID     string `middleware:"waiting_trx_id" json:"waiting_trx_id"`
+	WaitingThreadID  int    `middleware:"waiting_thread_id" json:"waiting_thread_id"`
+	WaitingQuery     string `middleware:"waiting_query" json:"waiting_query"`
+	BlockingTrxID    string `middleware:"blocking_trx_id" json:"blocking_trx_id"`
+	BlockingThreadID int    `middleware:"blocking_thread_id" json:"blocking_thread_id"`
+	BlockingQuery    string `middleware:"blocking_query" json:"blocking_query"`
+	WaitAge          int    `middleware:"wait_age" json:"wait_age"`
+	LockedTable      string `middleware:"locked_table" json:"locked_table"`
+}
+
+// NewEmptyInnoDBLockWait returns an empty healthcheck.InnoDBLockWait
+func NewEmptyInnoDBLockWait() healthcheck.InnoDBLockWait {
+	return &InnoDBLockWait{}
+}
+
+// GetWaitingTrxID returns the id of the transaction which is waiting for the lock
+func (ilw *InnoDBLockWait) GetWaitingTrxID() string {
+	return ilw.WaitingTrxID
+}
+
+// GetWaitingThreadID returns the processlist id of the waiting thread
+func (ilw *InnoDBLockWait) GetWaitingThreadID() int {
+	return ilw.WaitingThreadID
+}
+
+// GetWaitingQuery returns the sql which is waiting for the lock
+func (ilw *InnoDBLockWait) GetWaitingQuery() string {
+	return ilw.WaitingQuery
+}
+
+// GetBlockingTrxID returns the id of the transaction which holds the lock
+func (ilw *InnoDBLockWait) GetBlockingTrxID() string {
+	return ilw.BlockingTrxID
+}
+
+// GetBlockingThreadID returns the processlist id of the blocking thread
+func (ilw *InnoDBLockWait) GetBlockingThreadID() int {
+	return ilw.BlockingThreadID
+}
+
+// GetBlockingQuery returns the sql which is being executed by the blocking transaction, it is empty if the transaction is idle
+func (ilw *InnoDBLockWait) GetBlockingQuery() string {
+	return ilw.BlockingQuery
+}
+
+// GetWaitAge returns how long the transaction has been waiting for the lock, the unit is second
+func (ilw *InnoDBLockWait) GetWaitAge() int {
+	return ilw.WaitAge
+}
+
+// GetLockedTable returns the table which the lock is on
+func (ilw *InnoDBLockWait) GetLockedTable() string {
+	return ilw.LockedTable
+}
+
+// InnoDBDeadlockTransaction is a transaction which was involved in the deadlock
+type InnoDBDeadlockTransaction struct {
+	TrxNum   int    `json:"trx_num"`
+	TrxID    string `json:"trx_id"`
+	ThreadID int    `json:"thread_id"`
+	Query    string `json:"query"`
+}
+
+// GetTrxNum returns the number of the transaction in the deadlock, it starts from 1
+func (idt *InnoDBDeadlockTransaction) GetTrxNum() int {
+	return idt.TrxNum
+}
+
+// GetTrxID returns the transaction id
+func (idt *InnoDBDeadlockTransaction) GetTrxID() string {
+	return idt.TrxID
+}
+
+// GetThreadID returns the processlist id of the thread which ran the transaction
+func (idt *InnoDBDeadlockTransaction) GetThreadID() int {
+	return idt.ThreadID
+}
+
+// GetQuery returns the sql which was executed by the transaction when the deadlock was detected
+func (idt *InnoDBDeadlockTransaction) GetQuery() string {
+	return idt.Query
+}
+
+// InnoDBDeadlock is the latest deadlock detected by innodb, it is parsed from the output of show engine innodb status
+type InnoDBDeadlock struct {
+	DetectedTime     time.Time                    `json:"detected_time"`
+	Transactions     []*InnoDBDeadlockTransaction `json:"transactions"`
+	RolledBackTrxNum int                          `json:"rolled_back_trx_num"`
+}
+
+// NewEmptyInnoDBDeadlock returns an empty *InnoDBDeadlock, it means no deadlock was detected
+func NewEmptyInnoDBDeadlock() *InnoDBDeadlock {
+	return &InnoDBDeadlock{}
+}
+
+// GetDetectedTime returns the time when the deadlock was detected, it is zero if no deadlock was detected
+func (id *InnoDBDeadlock) GetDetectedTime() time.Time {
+	return id.DetectedTime
+}
+
+// GetTransactions returns the transactions which were involved in the deadlock
+func (id *InnoDBDeadlock) GetTransactions() []healthcheck.InnoDBDeadlockTransaction {
+	transactions := make([]healthcheck.InnoDBDeadlockTransaction, len(id.Transactions))
+	for i, transaction := range id.Transactions {
+		transactions[i] = transaction
+	}
+
+	return transactions
+}
+
+// GetRolledBackTrxNum returns the number of the transaction which was rolled back
+func (id *InnoDBDeadlock) GetRolledBackTrxNum() int {
+	return id.RolledBackTrxNum
+}
+
+// TableSchema is the engine and the primary key state of a table
+type TableSchema struct {
+	DBName     string `middleware:"db_name" json:"db_name"`
+	TableName  string `middleware:"table_name" json:"table_name"`
+	Engine     string `middleware:"engine" json:"engine"`
+	PrimaryKey bool   `middleware:"has_primary_key" json:"has_primary_key"`
+}
+
+// NewEmptyTableSchema returns an empty healthcheck.TableSchema
+func NewEmptyTableSchema() healthcheck.TableSchema {
+	return &TableSchema{}
+}
+
+// GetDBName returns the db name
+func (ts *TableSchema) GetDBName() string {
+	return ts.DBName
+}
+
+// GetTableName returns the table name
+func (ts *TableSchema) GetTableName() string {
+	return ts.TableName
+}
+
+// GetEngine returns the storage engine of the table
+func (ts *TableSchema) GetEngine() string {
+	return ts.Engine
+}
+
+// HasPrimaryKey returns if the table has a primary key
+func (ts *TableSchema) HasPrimaryKey() bool {
+	return ts.PrimaryKey
+}
+
+// AutoIncrement is the auto increment column of a table and the next auto increment value,
+// the value is a float64 as the max value of bigint unsigned overflows int
+type AutoIncrement struct {
+	DBName             string  `middleware:"db_name" json:"db_name"`
+	TableName          string  `middleware:"table_name" json:"table_name"`
+	ColumnName         string  `middleware:"column_name" json:"column_name"`
+	DataType           string  `middleware:"data_type" json:"data_type"`
+	ColumnType         string  `middleware:"column_type" json:"column_type"`
+	AutoIncrementValue float64 `middleware:"auto_increment" json:"auto_increment"`
+}
+
+// NewEmptyAutoIncrement returns an empty healthcheck.AutoIncrement
+func NewEmptyAutoIncrement() healthcheck.AutoIncrement {
+	return &AutoIncrement{}
+}
+
+// GetDBName returns the db name
+func (ai *AutoIncrement) GetDBName() string {
+	return ai.DBName
+}
+
+// GetTableName returns the table name
+func (ai *AutoIncrement) GetTableName() string {
+	return ai.TableName
+}
+
+// GetColumnName returns the name of the auto increment column
+func (ai *AutoIncrement) GetColumnName() string {
+	return ai.ColumnName
+}
+
+// GetDataType returns the data type of the column, e.g. int
+func (ai *AutoIncrement) GetDataType() string {
+	return ai.DataType
+}
+
+// GetColumnType returns the full column type, e.g. int(10) unsigned
+func (ai *AutoIncrement) GetColumnType() string {
+	return ai.ColumnType
+}
+
+// GetAutoIncrement returns the next auto increment value of the table
+func (ai *AutoIncrement) GetAutoIncrement() float64 {
+	return ai.AutoIncrementValue
+}
+
+// MySQLUser is an account of the mysql server
+type MySQLUser struct {
+	User          string `middleware:"user" json:"user"`
+	Host          string `middleware:"host" json:"host"`
+	Plugin        string `middleware:"plugin" json:"plugin"`
+	EmptyPassword bool   `middleware:"empty_password" json:"empty_password"`
+	OldPassword   bool   `middleware:"old_password" json:"old_password"`
+	AccountLocked bool   `middleware:"account_locked" json:"account_locked"`
+}
+
+// NewEmptyMySQLUser returns an empty healthcheck.MySQLUser
+func NewEmptyMySQLUser() healthcheck.MySQLUser {
+	return &MySQLUser{}
+}
+
+// GetUser returns the user name of the account, it is empty if the account is anonymous
+func (mu *MySQLUser) GetUser() string {
+	return mu.User
+}
+
+// GetHost returns the host of the account
+func (mu *MySQLUser) GetHost() string {
+	return mu.Host
+}
+
+// GetPlugin returns the authentication plugin of the account
+func (mu *MySQLUser) GetPlugin() string {
+	return mu.Plugin
+}
+
+// HasEmptyPassword returns if the account uses a password based plugin but has no password
+func (mu *MySQLUser) HasEmptyPassword() bool {
+	return mu.EmptyPassword
+}
+
+// HasOldPassword returns if the password of the account is in the old pre-4.1 format
+func (mu *MySQLUser) HasOldPassword() bool {
+	return mu.OldPassword
+}
+
+// IsLocked returns if the account is locked
+func (mu *MySQLUser) IsLocked() bool {
+	return mu.AccountLocked
+}
+
 type PrometheusData struct {
 	Timestamp string  `middleware:"timestamp" json:"timestamp"`
 	Value     float64 `middleware:"value" json:"value"`
@@ -566,6 +990,49 @@ func (pd *PrometheusData) GetValue() float64 {
 	return pd.Value
 }
 
+type DiskCapacity struct {
+	MountPoint string            `json:"mount_point"`
+	Size       float64           `json:"size"`
+	UsedDatas  []*PrometheusData `json:"used_datas"`
+}
+
+// NewDiskCapacity returns a new *DiskCapacity
+func NewDiskCapacity(mountPoint string, size float64, usedDatas []healthcheck.PrometheusData) *DiskCapacity {
+	dc := &DiskCapacity{
+		MountPoint: mountPoint,
+		Size:       size,
+		UsedDatas:  make([]*PrometheusData, len(usedDatas)),
+	}
+	for i, data := range usedDatas {
+		dc.UsedDatas[i] = &PrometheusData{
+			Timestamp: data.GetTimestamp(),
+			Value:     data.GetValue(),
+		}
+	}
+
+	return dc
+}
+
+// GetMountPoint returns the mount point
+func (dc *DiskCapacity) GetMountPoint() string {
+	return dc.MountPoint
+}
+
+// GetSize returns the latest size of the mount point, the unit is byte
+func (dc *DiskCapacity) GetSize() float64 {
+	return dc.Size
+}
+
+// GetUsedDatas returns the used bytes series of the mount point
+func (dc *DiskCapacity) GetUsedDatas() []healthcheck.PrometheusData {
+	datas := make([]healthcheck.PrometheusData, len(dc.UsedDatas))
+	for i, data := range dc.UsedDatas {
+		datas[i] = data
+	}
+
+	return datas
+}
+
 type FileSystem struct {
 	MountPoint string `middleware:"mount_point" json:"mount_point"`
 	Device     string `middleware:"device" json:"device"`
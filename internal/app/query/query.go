@@ -17,8 +17,9 @@ import (
 )
 
 const (
-	pmmMySQLDBName      = "pmm"
-	pmmClickhouseDBName = "pmm"
+	pmmMySQLDBName          = "pmm"
+	pmmClickhouseDBName     = "pmm"
+	performanceSchemaDBName = "performance_schema"
 )
 
 var _ query.Query = (*Query)(nil)
@@ -133,12 +134,13 @@ func (q *Querier) GetByMySQLClusterID(mysqlClusterID int) ([]query.Query, error)
 
 // GetByMySQLServerID get queries by mysql server id
 func (q *Querier) GetByMySQLServerID(mysqlServerID int) ([]query.Query, error) {
-	// init monitor repos
-	monitorSystem, err := q.getMonitorSystemByMySQLServerID(mysqlServerID)
+	// get mysql server
+	mysqlServer, err := q.getMySQLServerByID(mysqlServerID)
 	if err != nil {
 		return nil, err
 	}
-	monitorRepo, err := q.getMonitorRepo(monitorSystem)
+	// init monitor repos
+	monitorRepo, err := q.getMonitorRepoByMySQLServer(mysqlServer)
 	if err != nil {
 		return nil, err
 	}
@@ -148,11 +150,6 @@ func (q *Querier) GetByMySQLServerID(mysqlServerID int) ([]query.Query, error) {
 			log.Errorf("query Querier.GetByMySQLServerID(): close monitor repo failed. err: \n%+v", err)
 		}
 	}()
-	// get mysql server
-	mysqlServer, err := q.getMySQLServerByID(mysqlServerID)
-	if err != nil {
-		return nil, err
-	}
 
 	return monitorRepo.GetByServiceNames([]string{mysqlServer.GetServiceName()})
 }
@@ -166,12 +163,13 @@ func (q *Querier) GetByDBID(dbID int) ([]query.Query, error) {
 		return nil, err
 	}
 	mysqlServerID := dbService.GetDBs()[constant.ZeroInt].GetClusterID()
-	// init monitor repos
-	monitorSystem, err := q.getMonitorSystemByMySQLServerID(mysqlServerID)
+	// get mysql server
+	mysqlServer, err := q.getMySQLServerByID(mysqlServerID)
 	if err != nil {
 		return nil, err
 	}
-	monitorRepo, err := q.getMonitorRepo(monitorSystem)
+	// init monitor repos
+	monitorRepo, err := q.getMonitorRepoByMySQLServer(mysqlServer)
 	if err != nil {
 		return nil, err
 	}
@@ -181,11 +179,6 @@ func (q *Querier) GetByDBID(dbID int) ([]query.Query, error) {
 			log.Errorf("query Querier.GetByDBID(): close monitor repo failed. err: \n%+v", err)
 		}
 	}()
-	// get mysql server
-	mysqlServer, err := q.getMySQLServerByID(mysqlServerID)
-	if err != nil {
-		return nil, err
-	}
 	// get db
 	db, err := q.getDBByID(dbID)
 	if err != nil {
@@ -197,12 +190,13 @@ func (q *Querier) GetByDBID(dbID int) ([]query.Query, error) {
 
 // GetBySQLID get queries by sql id
 func (q *Querier) GetBySQLID(mysqlServerID int, sqlID string) ([]query.Query, error) {
-	// init monitor repos
-	monitorSystem, err := q.getMonitorSystemByMySQLServerID(mysqlServerID)
+	// get mysql server
+	mysqlServer, err := q.getMySQLServerByID(mysqlServerID)
 	if err != nil {
 		return nil, err
 	}
-	monitorRepo, err := q.getMonitorRepo(monitorSystem)
+	// init monitor repos
+	monitorRepo, err := q.getMonitorRepoByMySQLServer(mysqlServer)
 	if err != nil {
 		return nil, err
 	}
@@ -212,11 +206,6 @@ func (q *Querier) GetBySQLID(mysqlServerID int, sqlID string) ([]query.Query, er
 			log.Errorf("query Querier.GetBySQLID(): close monitor repo failed. err: \n%+v", err)
 		}
 	}()
-	// get mysql server
-	mysqlServer, err := q.getMySQLServerByID(mysqlServerID)
-	if err != nil {
-		return nil, err
-	}
 	queryResult, err := monitorRepo.GetBySQLID(mysqlServer.GetServiceName(), sqlID)
 
 	return []query.Query{queryResult}, err
@@ -281,7 +270,30 @@ func (q *Querier) getMonitorSystemByMySQLServerID(mysqlServerID int) (depmeta.Mo
 	return q.getMonitorSystemByMySQLClusterID(mysqlServer.GetClusterID())
 }
 
-func (q *Querier) getMonitorRepo(monitorSystem depmeta.MonitorSystem) (query.MonitorRepo, error) {
+// getMonitorRepoByMySQLServer returns the monitor repository of the mysql server,
+// the slow log statistics ingested from the slow log files are used only if the ingested slow log covers the whole query time range,
+// otherwise, the queries are got from the monitor system
+func (q *Querier) getMonitorRepoByMySQLServer(mysqlServer depmeta.MySQLServer) (query.MonitorRepo, error) {
+	slowLogRepo := NewSlowLogRepoWithGlobal(q.getConfig())
+	covered, err := slowLogRepo.Covers(mysqlServer.Identity(), q.getConfig().GetStartTime(), q.getConfig().GetEndTime())
+	if err != nil {
+		return nil, err
+	}
+	if covered {
+		return slowLogRepo, nil
+	}
+
+	monitorSystem, err := q.getMonitorSystemByMySQLServerID(mysqlServer.Identity())
+	if err != nil {
+		return nil, err
+	}
+
+	return q.getMonitorRepo(monitorSystem, mysqlServer)
+}
+
+// getMonitorRepo returns the monitor repository of the monitor system,
+// the generic prometheus monitor system reads the slow queries from the performance_schema of the mysql server
+func (q *Querier) getMonitorRepo(monitorSystem depmeta.MonitorSystem, mysqlServer depmeta.MySQLServer) (query.MonitorRepo, error) {
 	var monitorRepo query.MonitorRepo
 
 	addr := fmt.Sprintf("%s:%d", monitorSystem.GetHostIP(), monitorSystem.GetPortNumSlow())
@@ -300,6 +312,14 @@ func (q *Querier) getMonitorRepo(monitorSystem depmeta.MonitorSystem) (query.Mon
 			return nil, message.NewMessage(msgquery.ErrQueryCreateMonitorClickhouseConnection, err, addr, q.getMonitorClickhouseUser())
 		}
 		monitorRepo = NewClickHouseRepo(q.getConfig(), clickhouseConn)
+	case 3:
+		// generic prometheus
+		mysqlServerAddr := fmt.Sprintf("%s:%d", mysqlServer.GetHostIP(), mysqlServer.GetPortNum())
+		mysqlConn, err := mysql.NewConn(mysqlServerAddr, performanceSchemaDBName, q.getApplicationMySQLUser(), q.getApplicationMySQLPass())
+		if err != nil {
+			return nil, message.NewMessage(msgquery.ErrQueryCreateApplicationMySQLConnection, err, mysqlServerAddr, q.getApplicationMySQLUser())
+		}
+		monitorRepo = NewPerformanceSchemaRepo(q.getConfig(), mysqlConn)
 	default:
 		return nil, message.NewMessage(msgquery.ErrQueryMonitorSystemSystemType, monitorSystem.GetSystemType())
 	}
@@ -326,3 +346,13 @@ func (q *Querier) getMonitorClickhouseUser() string {
 func (q *Querier) getMonitorClickhousePass() string {
 	return viper.GetString(config.DBMonitorClickhousePassKey)
 }
+
+// getApplicationMySQLUser returns mysql username of application
+func (q *Querier) getApplicationMySQLUser() string {
+	return viper.GetString(config.DBApplicationMySQLUserKey)
+}
+
+// getApplicationMySQLPass returns mysql password of application
+func (q *Querier) getApplicationMySQLPass() string {
+	return viper.GetString(config.DBApplicationMySQLPassKey)
+}
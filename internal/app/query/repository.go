@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/romberli/das/config"
@@ -215,12 +216,113 @@ const (
                             group by queryid) m
                            on sm.sql_id = m.sql_id;
     `
+	// PerformanceSchemaDigestQuery selects the digests of performance_schema.events_statements_summary_by_digest which were seen in the time range,
+	// the statistics of the digest table are accumulated since the server started or the table was truncated,
+	// they could not be limited to the time range, so exec_count and total_exec_time are the lifetime values of the digests,
+	// and the digest table does not record the maximum rows examined of a single execution,
+	// so rows_examined_max is the lifetime average rows examined per execution,
+	// the conditions and the clauses appended to it must use the placeholders after the ones of the time range
+	PerformanceSchemaDigestQuery = `
+        select digest                                                   as sql_id,
+               digest_text                                              as fingerprint,
+               digest_text                                              as example,
+               ifnull(schema_name, '')                                  as db_name,
+               count_star                                               as exec_count,
+               truncate(sum_timer_wait / 1000000000000, 2)              as total_exec_time,
+               truncate(sum_timer_wait / count_star / 1000000000000, 2) as avg_exec_time,
+               ceil(sum_rows_examined / count_star)                     as rows_examined_max
+        from performance_schema.events_statements_summary_by_digest
+        where digest is not null
+          and count_star > 0
+          and last_seen >= ?
+          and first_seen < ?`
+	performanceSchemaQueryWithServiceNames = PerformanceSchemaDigestQuery + `
+          and sum_rows_examined / count_star >= ?
+        order by rows_examined_max desc
+        limit ? offset ?;
+    `
+	performanceSchemaQueryWithDBName = PerformanceSchemaDigestQuery + `
+          and schema_name = ?
+          and sum_rows_examined / count_star >= ?
+        order by rows_examined_max desc
+        limit ? offset ?;
+    `
+	performanceSchemaQueryWithSQLID = PerformanceSchemaDigestQuery + `
+          and digest = ?
+        limit 1;
+    `
+	// the statistics are aggregated in the windows of the slow log files, the windows which start in the time range are used
+	slowLogQueryWithServiceNames = `
+        select sls.sql_id,
+               max(sls.fingerprint)                                        as fingerprint,
+               max(sls.example)                                            as example,
+               sls.db_name,
+               sum(sls.exec_count)                                         as exec_count,
+               truncate(sum(sls.total_exec_time), 2)                       as total_exec_time,
+               truncate(sum(sls.total_exec_time) / sum(sls.exec_count), 2) as avg_exec_time,
+               max(sls.rows_examined_max)                                  as rows_examined_max
+        from t_query_slow_log_stat sls
+                 inner join t_meta_mysql_server_info msi on sls.mysql_server_id = msi.id
+        where sls.del_flag = 0
+          and msi.del_flag = 0
+          and msi.service_name in (%s)
+          and sls.window_start >= ?
+          and sls.window_start < ?
+        group by sls.sql_id, sls.db_name
+        having rows_examined_max >= ?
+        order by rows_examined_max desc
+        limit ? offset ?;
+    `
+	slowLogQueryWithDBName = `
+        select sls.sql_id,
+               max(sls.fingerprint)                                        as fingerprint,
+               max(sls.example)                                            as example,
+               sls.db_name,
+               sum(sls.exec_count)                                         as exec_count,
+               truncate(sum(sls.total_exec_time), 2)                       as total_exec_time,
+               truncate(sum(sls.total_exec_time) / sum(sls.exec_count), 2) as avg_exec_time,
+               max(sls.rows_examined_max)                                  as rows_examined_max
+        from t_query_slow_log_stat sls
+                 inner join t_meta_mysql_server_info msi on sls.mysql_server_id = msi.id
+        where sls.del_flag = 0
+          and msi.del_flag = 0
+          and msi.service_name in (%s)
+          and sls.db_name = ?
+          and sls.window_start >= ?
+          and sls.window_start < ?
+        group by sls.sql_id, sls.db_name
+        having rows_examined_max >= ?
+        order by rows_examined_max desc
+        limit ? offset ?;
+    `
+	slowLogQueryWithSQLID = `
+        select sls.sql_id,
+               max(sls.fingerprint)                                        as fingerprint,
+               max(sls.example)                                            as example,
+               max(sls.db_name)                                            as db_name,
+               sum(sls.exec_count)                                         as exec_count,
+               truncate(sum(sls.total_exec_time), 2)                       as total_exec_time,
+               truncate(sum(sls.total_exec_time) / sum(sls.exec_count), 2) as avg_exec_time,
+               max(sls.rows_examined_max)                                  as rows_examined_max
+        from t_query_slow_log_stat sls
+                 inner join t_meta_mysql_server_info msi on sls.mysql_server_id = msi.id
+        where sls.del_flag = 0
+          and msi.del_flag = 0
+          and msi.service_name in (%s)
+          and sls.sql_id = ?
+          and sls.window_start >= ?
+          and sls.window_start < ?
+        group by sls.sql_id
+        limit 1;
+    `
 )
 
 var (
 	_ query.DASRepo     = (*DASRepo)(nil)
 	_ query.MonitorRepo = (*MySQLRepo)(nil)
 	_ query.MonitorRepo = (*ClickhouseRepo)(nil)
+	_ query.MonitorRepo = (*PerformanceSchemaRepo)(nil)
+	_ query.SlowLogRepo = (*SlowLogRepo)(nil)
 )
 
 type DASRepo struct {
@@ -535,6 +637,414 @@ func (cr *ClickhouseRepo) execute(command string, args ...interface{}) ([]query.
 	return queries, nil
 }
 
+// PerformanceSchemaRepo reads the queries from performance_schema.events_statements_summary_by_digest of the mysql server,
+// it is used by the generic prometheus monitor system which has no query analytics,
+// the returned execution counts and execution times are the lifetime values of the digests which were seen in the time range,
+// and the returned maximum rows examined are the lifetime averages, see PerformanceSchemaDigestQuery
+type PerformanceSchemaRepo struct {
+	config query.Config
+	conn   *mysql.Conn
+}
+
+// NewPerformanceSchemaRepo returns a new query.MonitorRepo, conn should connect to the mysql server
+func NewPerformanceSchemaRepo(config query.Config, conn *mysql.Conn) query.MonitorRepo {
+	return newPerformanceSchemaRepo(config, conn)
+}
+
+// newPerformanceSchemaRepo returns a new *PerformanceSchemaRepo
+func newPerformanceSchemaRepo(config query.Config, conn *mysql.Conn) *PerformanceSchemaRepo {
+	return &PerformanceSchemaRepo{
+		config: config,
+		conn:   conn,
+	}
+}
+
+// getConfig returns the configuration
+func (psr *PerformanceSchemaRepo) getConfig() query.Config {
+	return psr.config
+}
+
+// Close closes the connection
+func (psr *PerformanceSchemaRepo) Close() error {
+	return psr.conn.Close()
+}
+
+// GetByServiceNames returns query.Query list of the mysql server,
+// the connection is bound to the mysql server, so the service names are ignored
+func (psr *PerformanceSchemaRepo) GetByServiceNames(serviceNames []string) ([]query.Query, error) {
+	return psr.execute(performanceSchemaQueryWithServiceNames,
+		psr.getConfig().GetStartTime().Format(constant.DefaultTimeLayout),
+		psr.getConfig().GetEndTime().Format(constant.DefaultTimeLayout),
+		getMinRowsExamined(),
+		psr.getConfig().GetLimit(),
+		psr.getConfig().GetOffset(),
+	)
+}
+
+// GetByDBName returns query.Query list by dbName
+func (psr *PerformanceSchemaRepo) GetByDBName(serviceName, dbName string) ([]query.Query, error) {
+	return psr.execute(performanceSchemaQueryWithDBName,
+		psr.getConfig().GetStartTime().Format(constant.DefaultTimeLayout),
+		psr.getConfig().GetEndTime().Format(constant.DefaultTimeLayout),
+		dbName,
+		getMinRowsExamined(),
+		psr.getConfig().GetLimit(),
+		psr.getConfig().GetOffset(),
+	)
+}
+
+// GetBySQLID returns query.Query by SQL ID, the sql id is the digest of the statement
+func (psr *PerformanceSchemaRepo) GetBySQLID(serviceName, sqlID string) (query.Query, error) {
+	queries, err := psr.execute(performanceSchemaQueryWithSQLID,
+		psr.getConfig().GetStartTime().Format(constant.DefaultTimeLayout),
+		psr.getConfig().GetEndTime().Format(constant.DefaultTimeLayout),
+		sqlID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("sql(id=%s) in service(name=%s) is not found", sqlID, serviceName)
+	}
+
+	return queries[constant.ZeroInt], nil
+}
+
+// execute executes the SQL with args
+func (psr *PerformanceSchemaRepo) execute(command string, args ...interface{}) ([]query.Query, error) {
+	log.Debugf("query PerformanceSchemaRepo.execute() sql: %s, args: %v", command, args)
+
+	// get queries from the performance_schema
+	result, err := psr.conn.Execute(command, args...)
+	if err != nil {
+		return nil, err
+	}
+	// init queries
+	queries := make([]query.Query, result.RowNumber())
+	for i := constant.ZeroInt; i < result.RowNumber(); i++ {
+		queries[i] = NewEmptyQuery()
+	}
+	// map result to queries
+	err = result.MapToStructSlice(queries, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
+
+// SlowLogRepo reads the queries from the slow log statistics in the das database,
+// the statistics are ingested from the slow log files of the mysql servers, they are used when the ingested slow log covers the query time range
+type SlowLogRepo struct {
+	config   query.Config
+	Database middleware.Pool
+}
+
+// NewSlowLogRepo returns a new query.SlowLogRepo
+func NewSlowLogRepo(config query.Config, db middleware.Pool) query.SlowLogRepo {
+	return newSlowLogRepo(config, db)
+}
+
+// NewSlowLogRepoWithGlobal returns a new query.SlowLogRepo with global mysql pool
+func NewSlowLogRepoWithGlobal(config query.Config) query.SlowLogRepo {
+	return newSlowLogRepo(config, global.DASMySQLPool)
+}
+
+// newSlowLogRepo returns a new *SlowLogRepo
+func newSlowLogRepo(config query.Config, db middleware.Pool) *SlowLogRepo {
+	return &SlowLogRepo{
+		config:   config,
+		Database: db,
+	}
+}
+
+// getConfig returns the configuration
+func (slr *SlowLogRepo) getConfig() query.Config {
+	return slr.config
+}
+
+// Execute executes given command and placeholders on the middleware
+func (slr *SlowLogRepo) Execute(command string, args ...interface{}) (middleware.Result, error) {
+	conn, err := slr.Database.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = conn.Close()
+		if err != nil {
+			log.Errorf("query SlowLogRepo.Execute(): close database connection failed. err: \n%+v", err)
+		}
+	}()
+
+	return conn.Execute(command, args...)
+}
+
+// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+func (slr *SlowLogRepo) Transaction() (middleware.Transaction, error) {
+	return slr.Database.Transaction()
+}
+
+// Close does nothing, the connections are returned to the pool after each execution
+func (slr *SlowLogRepo) Close() error {
+	return nil
+}
+
+// GetIngestedRange gets the time range of all the ingested slow log entries of the mysql server from the middleware,
+// the end keys are the keys of the ingested entries of the end time, they may come from several ingestions,
+// it returns nil if the slow log of the mysql server was never ingested
+func (slr *SlowLogRepo) GetIngestedRange(mysqlServerID int) (query.SlowLogIngestion, error) {
+	sql := `
+		select sli.mysql_server_id, min(sli.start_time) as start_time, max(sli.end_time) as end_time,
+			   coalesce(group_concat(case when sli.end_time = m.end_time then nullif(sli.end_keys, '') end separator ','), '') as end_keys,
+			   cast(sum(sli.entry_count) as signed) as entry_count
+		from t_query_slow_log_ingestion sli
+			inner join (select max(end_time) as end_time
+						from t_query_slow_log_ingestion
+						where del_flag = 0
+						  and mysql_server_id = ?) m
+		where sli.del_flag = 0
+		  and sli.mysql_server_id = ?
+		group by sli.mysql_server_id;
+	`
+	log.Debugf("query SlowLogRepo.GetIngestedRange() select sql: \n%s\nplaceholders: %d, %d", sql, mysqlServerID, mysqlServerID)
+
+	result, err := slr.Execute(sql, mysqlServerID, mysqlServerID)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowNumber() == constant.ZeroInt {
+		return nil, nil
+	}
+	ingestion := NewEmptySlowLogIngestion()
+	err = result.MapToStructByRowIndex(ingestion, constant.ZeroInt, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return ingestion, nil
+}
+
+// Covers checks if the ingested slow log of the mysql server covers the whole time range,
+// the ingestions are merged in the order of the start time, the gaps between the ingestions are not covered
+func (slr *SlowLogRepo) Covers(mysqlServerID int, startTime, endTime time.Time) (bool, error) {
+	sql := `
+		select mysql_server_id, start_time, end_time, end_keys, entry_count
+		from t_query_slow_log_ingestion
+		where del_flag = 0
+		  and mysql_server_id = ?
+		order by start_time, id;
+	`
+	log.Debugf("query SlowLogRepo.Covers() select sql: \n%s\nplaceholders: %d", sql, mysqlServerID)
+
+	result, err := slr.Execute(sql, mysqlServerID)
+	if err != nil {
+		return false, err
+	}
+	ingestions := make([]query.SlowLogIngestion, result.RowNumber())
+	for i := range ingestions {
+		ingestion := NewEmptySlowLogIngestion()
+		err = result.MapToStructByRowIndex(ingestion, i, constant.DefaultMiddlewareTag)
+		if err != nil {
+			return false, err
+		}
+		ingestions[i] = ingestion
+	}
+
+	return coversSlowLogTimeRange(ingestions, startTime, endTime), nil
+}
+
+// Save saves the ingestion and the slow log statistics of the ingested entries into the middleware as a transaction,
+// the statistics of the same sql identity, db name and window are accumulated, as the window may span several slow log files,
+// ingested is the ingested slow log which the entries were filtered with, it is nil if the slow log was never ingested,
+// the state row of the mysql server is inserted or updated first, so the ingestions of the same mysql server are serialized,
+// if any other ingestion of the mysql server was saved after ingested was read, nothing will be saved
+func (slr *SlowLogRepo) Save(ingested, ingestion query.SlowLogIngestion, stats []query.SlowLogStat) error {
+	tx, err := slr.Transaction()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = tx.Close()
+		if err != nil {
+			log.Errorf("query SlowLogRepo.Save(): close database connection failed. err: \n%+v", err)
+		}
+	}()
+
+	err = tx.Begin()
+	if err != nil {
+		return err
+	}
+
+	mysqlServerID := ingestion.GetMySQLServerID()
+	startTime := ingestion.GetStartTime().Format(constant.DefaultTimeLayout)
+	endTime := ingestion.GetEndTime().Format(constant.DefaultTimeLayout)
+	// lock the state row of the mysql server, so that the same entries could not be ingested concurrently,
+	// the row is inserted on the first ingestion, the unique key blocks the concurrent insertion until this transaction ends
+	sql := `
+		insert into t_query_slow_log_ingestion_state(mysql_server_id) values(?)
+		on duplicate key update del_flag = 0, last_update_time = current_timestamp(6);
+	`
+	log.Debugf("query SlowLogRepo.Save() insert sql: \n%s\nplaceholders: %d", sql, mysqlServerID)
+	_, err = tx.Execute(sql, mysqlServerID)
+	if err != nil {
+		return slr.rollback(tx, err)
+	}
+	// each ingestion has at least one entry, so the entry count changes once another ingestion is saved
+	ingestedCount := constant.ZeroInt
+	if ingested != nil {
+		ingestedCount = ingested.GetEntryCount()
+	}
+	sql = `select cast(coalesce(sum(entry_count), 0) as signed) from t_query_slow_log_ingestion where del_flag = 0 and mysql_server_id = ?;`
+	log.Debugf("query SlowLogRepo.Save() select sql: \n%s\nplaceholders: %d", sql, mysqlServerID)
+	result, err := tx.Execute(sql, mysqlServerID)
+	if err != nil {
+		return slr.rollback(tx, err)
+	}
+	count, err := result.GetInt(constant.ZeroInt, constant.ZeroInt)
+	if err != nil {
+		return slr.rollback(tx, err)
+	}
+	if count != ingestedCount {
+		return slr.rollback(tx, fmt.Errorf("query SlowLogRepo.Save(): the slow log of the mysql server was ingested concurrently, please ingest it again. mysql_server_id: %d",
+			mysqlServerID))
+	}
+
+	sql = `
+		insert into t_query_slow_log_stat(mysql_server_id, sql_id, fingerprint, example, db_name, window_start,
+										  exec_count, total_exec_time, rows_examined_max)
+		values(?, ?, ?, ?, ?, ?, ?, ?, ?)
+		on duplicate key update exec_count        = exec_count + values(exec_count),
+								total_exec_time   = total_exec_time + values(total_exec_time),
+								rows_examined_max = greatest(rows_examined_max, values(rows_examined_max));
+	`
+	for _, stat := range stats {
+		windowStart := stat.GetWindowStart().Format(constant.DefaultTimeLayout)
+		log.Debugf("query SlowLogRepo.Save() insert sql: \n%s\nplaceholders: %d, %s, %s, %s, %s, %s, %d, %f, %d",
+			sql, mysqlServerID, stat.GetSQLID(), stat.GetFingerprint(), stat.GetExample(), stat.GetDBName(), windowStart,
+			stat.GetExecCount(), stat.GetTotalExecTime(), stat.GetRowsExaminedMax())
+
+		_, err = tx.Execute(sql, mysqlServerID, stat.GetSQLID(), stat.GetFingerprint(), stat.GetExample(), stat.GetDBName(),
+			windowStart, stat.GetExecCount(), stat.GetTotalExecTime(), stat.GetRowsExaminedMax())
+		if err != nil {
+			return slr.rollback(tx, err)
+		}
+	}
+
+	endKeys := strings.Join(ingestion.GetEndKeys(), constant.CommaString)
+	sql = `insert into t_query_slow_log_ingestion(mysql_server_id, start_time, end_time, end_keys, entry_count) values(?, ?, ?, ?, ?);`
+	log.Debugf("query SlowLogRepo.Save() insert sql: \n%s\nplaceholders: %d, %s, %s, %s, %d", sql, mysqlServerID, startTime, endTime, endKeys, ingestion.GetEntryCount())
+	_, err = tx.Execute(sql, mysqlServerID, startTime, endTime, endKeys, ingestion.GetEntryCount())
+	if err != nil {
+		return slr.rollback(tx, err)
+	}
+
+	return tx.Commit()
+}
+
+// rollback rolls back the transaction, and returns the original error
+func (slr *SlowLogRepo) rollback(tx middleware.Transaction, err error) error {
+	rollbackErr := tx.Rollback()
+	if rollbackErr != nil {
+		log.Errorf("query SlowLogRepo.rollback(): rollback failed. err: \n%+v", rollbackErr)
+	}
+
+	return err
+}
+
+// GetByServiceNames returns query.Query list by serviceNames
+func (slr *SlowLogRepo) GetByServiceNames(serviceNames []string) ([]query.Query, error) {
+	services, err := slr.getServices(serviceNames)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(slowLogQueryWithServiceNames, services)
+
+	return slr.execute(sql,
+		slr.getConfig().GetStartTime().Format(constant.DefaultTimeLayout),
+		slr.getConfig().GetEndTime().Format(constant.DefaultTimeLayout),
+		getMinRowsExamined(),
+		slr.getConfig().GetLimit(),
+		slr.getConfig().GetOffset(),
+	)
+}
+
+// GetByDBName returns query.Query list by dbName
+func (slr *SlowLogRepo) GetByDBName(serviceName, dbName string) ([]query.Query, error) {
+	services, err := slr.getServices([]string{serviceName})
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(slowLogQueryWithDBName, services)
+
+	return slr.execute(sql,
+		dbName,
+		slr.getConfig().GetStartTime().Format(constant.DefaultTimeLayout),
+		slr.getConfig().GetEndTime().Format(constant.DefaultTimeLayout),
+		getMinRowsExamined(),
+		slr.getConfig().GetLimit(),
+		slr.getConfig().GetOffset(),
+	)
+}
+
+// GetBySQLID returns query.Query by SQL ID, the sql id is calculated from the fingerprint of the statement
+func (slr *SlowLogRepo) GetBySQLID(serviceName, sqlID string) (query.Query, error) {
+	services, err := slr.getServices([]string{serviceName})
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(slowLogQueryWithSQLID, services)
+
+	queries, err := slr.execute(sql,
+		sqlID,
+		slr.getConfig().GetStartTime().Format(constant.DefaultTimeLayout),
+		slr.getConfig().GetEndTime().Format(constant.DefaultTimeLayout),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("sql(id=%s) in service(name=%s) is not found", sqlID, serviceName)
+	}
+
+	return queries[constant.ZeroInt], nil
+}
+
+// getServices returns the service names which could be used in the in clause of the sql
+func (slr *SlowLogRepo) getServices(serviceNames []string) (string, error) {
+	interfaces, err := common.ConvertInterfaceToSliceInterface(serviceNames)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return middleware.ConvertSliceToString(interfaces...)
+}
+
+// execute executes the SQL with args
+func (slr *SlowLogRepo) execute(command string, args ...interface{}) ([]query.Query, error) {
+	log.Debugf("query SlowLogRepo.execute() sql: %s, args: %v", command, args)
+
+	// get queries from the slow log statistics
+	result, err := slr.Execute(command, args...)
+	if err != nil {
+		return nil, err
+	}
+	// init queries
+	queries := make([]query.Query, result.RowNumber())
+	for i := constant.ZeroInt; i < result.RowNumber(); i++ {
+		queries[i] = NewEmptyQuery()
+	}
+	// map result to queries
+	err = result.MapToStructSlice(queries, constant.DefaultMiddlewareTag)
+	if err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
+
 func getMinRowsExamined() int {
 	return viper.GetInt(config.QueryMinRowsExaminedKey)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/romberli/das/config"
 	"github.com/romberli/das/global"
 	"github.com/romberli/das/internal/app/metadata"
+	"github.com/romberli/das/internal/dependency/query"
 	"github.com/romberli/go-util/common"
 	"github.com/romberli/go-util/constant"
 	"github.com/romberli/go-util/middleware/clickhouse"
@@ -43,6 +44,13 @@ const (
 	testPMM2SQLID          = "F4F85858E527B409"
 
 	testMinRowsExamined = 1
+
+	testSlowLogSQLID         = "A1B2C3D4E5F60718"
+	testSlowLogFingerprint   = "select * from t_meta_db_info where id = ?"
+	testSlowLogExample       = "select * from t_meta_db_info where id = 1"
+	testSlowLogExecCount     = 2
+	testSlowLogTotalExecTime = 3.5
+	testSlowLogRowsExamined  = 1000
 )
 
 var (
@@ -56,9 +64,11 @@ var (
 	testDBName         string
 	testSQLID          string
 
-	testDASRepo        *DASRepo
-	testMySQLRepo      *MySQLRepo
-	testClickhouseRepo *ClickhouseRepo
+	testDASRepo               *DASRepo
+	testMySQLRepo             *MySQLRepo
+	testClickhouseRepo        *ClickhouseRepo
+	testPerformanceSchemaRepo *PerformanceSchemaRepo
+	testSlowLogRepo           *SlowLogRepo
 )
 
 func init() {
@@ -68,6 +78,7 @@ func init() {
 	testInitViper()
 
 	testDASRepo = newDASRepo(global.DASMySQLPool)
+	testSlowLogRepo = newSlowLogRepo(NewConfigWithDefault(), global.DASMySQLPool)
 
 	switch testPMMVersion {
 	case 1:
@@ -123,6 +134,8 @@ func testInitViper() {
 	viper.Set(config.DBMonitorMySQLPassKey, config.DefaultDBMonitorMySQLPass)
 	viper.Set(config.DBMonitorClickhouseUserKey, config.DefaultDBMonitorClickhouseUser)
 	viper.Set(config.DBMonitorClickhousePassKey, config.DefaultDBMonitorClickhousePass)
+	viper.Set(config.DBApplicationMySQLUserKey, config.DefaultDBApplicationMySQLUser)
+	viper.Set(config.DBApplicationMySQLPassKey, config.DefaultDBApplicationMySQLPass)
 	viper.Set(config.QueryMinRowsExaminedKey, testMinRowsExamined)
 }
 
@@ -174,12 +187,29 @@ func testInitClickhouseRepo() *ClickhouseRepo {
 	return newClickHouseRepo(NewConfigWithDefault(), conn)
 }
 
+func testInitPerformanceSchemaRepo() *PerformanceSchemaRepo {
+	addr := fmt.Sprintf("%s:%d", testMySQLHostIP, testMySQLPortNum)
+	dbUser := viper.GetString(config.DBApplicationMySQLUserKey)
+	dbPass := viper.GetString(config.DBApplicationMySQLPassKey)
+	conn, err := mysql.NewConn(addr, performanceSchemaDBName, dbUser, dbPass)
+	if err != nil {
+		log.Error(common.CombineMessageWithError("testInitPerformanceSchemaRepo() failed", err))
+		os.Exit(constant.DefaultAbnormalExitCode)
+	}
+
+	return newPerformanceSchemaRepo(NewConfigWithDefault(), conn)
+}
+
 func TestQueryRepository_All(t *testing.T) {
 	TestDASRepo_Save(t)
 	// test PMM1.x
 	TestQueryRepository_PMM1(t)
 	// test PMM2.x
 	TestQueryRepository_PMM2(t)
+	// test generic prometheus
+	TestQueryRepository_Generic(t)
+	// test slow log
+	TestQueryRepository_SlowLog(t)
 }
 
 func TestQueryRepository_PMM1(t *testing.T) {
@@ -198,6 +228,49 @@ func TestQueryRepository_PMM2(t *testing.T) {
 	TestClickhouseRepo_GetBySQLID(t)
 }
 
+func TestQueryRepository_Generic(t *testing.T) {
+	testInitMySQLInfo()
+	testPerformanceSchemaRepo = testInitPerformanceSchemaRepo()
+	TestPerformanceSchemaRepo_GetByServiceNames(t)
+	TestPerformanceSchemaRepo_GetByDBName(t)
+	TestPerformanceSchemaRepo_GetBySQLID(t)
+}
+
+func TestQueryRepository_SlowLog(t *testing.T) {
+	testInitMySQLInfo()
+	TestSlowLogRepo_Save(t)
+	TestSlowLogRepo_GetIngestedRange(t)
+	TestSlowLogRepo_Covers(t)
+	TestSlowLogRepo_GetByServiceNames(t)
+	TestSlowLogRepo_GetByDBName(t)
+	TestSlowLogRepo_GetBySQLID(t)
+}
+
+func testCreateSlowLogStat() error {
+	windowStart := time.Now().Truncate(DefaultSlowLogWindow)
+	stat := NewSlowLogStat(testSlowLogSQLID, testSlowLogFingerprint, testSlowLogExample, testDBName,
+		windowStart, testSlowLogExecCount, testSlowLogTotalExecTime, testSlowLogRowsExamined)
+
+	return testSlowLogRepo.Save(nil, NewSlowLogIngestion(testMySQLServerID, windowStart, windowStart, nil, testSlowLogExecCount), []query.SlowLogStat{stat})
+}
+
+func testDeleteSlowLogStat() error {
+	sql := `delete from t_query_slow_log_stat where mysql_server_id = ? and sql_id = ?;`
+	_, err := testSlowLogRepo.Execute(sql, testMySQLServerID, testSlowLogSQLID)
+	if err != nil {
+		return err
+	}
+	sql = `delete from t_query_slow_log_ingestion where mysql_server_id = ?;`
+	_, err = testSlowLogRepo.Execute(sql, testMySQLServerID)
+	if err != nil {
+		return err
+	}
+	sql = `delete from t_query_slow_log_ingestion_state where mysql_server_id = ?;`
+	_, err = testSlowLogRepo.Execute(sql, testMySQLServerID)
+
+	return err
+}
+
 func TestDASRepo_Save(t *testing.T) {
 	asst := assert.New(t)
 
@@ -261,3 +334,144 @@ func TestClickhouseRepo_GetBySQLID(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test GetBySQLID() failed", err))
 	asst.NotNil(query, "test GetBySQLID() Failed")
 }
+
+func TestPerformanceSchemaRepo_GetByServiceNames(t *testing.T) {
+	asst := assert.New(t)
+
+	queries, err := testPerformanceSchemaRepo.GetByServiceNames([]string{testServiceName})
+	asst.Nil(err, common.CombineMessageWithError("test GetByServiceNames() failed", err))
+	asst.GreaterOrEqual(len(queries), constant.ZeroInt, "test GetByServiceNames() failed")
+}
+
+func TestPerformanceSchemaRepo_GetByDBName(t *testing.T) {
+	asst := assert.New(t)
+
+	queries, err := testPerformanceSchemaRepo.GetByDBName(testServiceName, testDBName)
+	asst.Nil(err, common.CombineMessageWithError("test GetByDBName() failed", err))
+	for _, q := range queries {
+		asst.Equal(testDBName, q.GetDBName(), "test GetByDBName() failed")
+	}
+}
+
+func TestPerformanceSchemaRepo_GetBySQLID(t *testing.T) {
+	asst := assert.New(t)
+
+	queries, err := testPerformanceSchemaRepo.GetByServiceNames([]string{testServiceName})
+	asst.Nil(err, common.CombineMessageWithError("test GetBySQLID() failed", err))
+	if len(queries) == constant.ZeroInt {
+		return
+	}
+	query, err := testPerformanceSchemaRepo.GetBySQLID(testServiceName, queries[constant.ZeroInt].GetSQLID())
+	asst.Nil(err, common.CombineMessageWithError("test GetBySQLID() failed", err))
+	asst.Equal(queries[constant.ZeroInt].GetSQLID(), query.GetSQLID(), "test GetBySQLID() failed")
+}
+
+func TestSlowLogRepo_Save(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testCreateSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	// the entries which were filtered with the stale ingested slow log could not be saved
+	err = testCreateSlowLogStat()
+	asst.NotNil(err, "test Save() failed")
+	q, err := testSlowLogRepo.GetBySQLID(testServiceName, testSlowLogSQLID)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(testSlowLogExecCount, q.GetExecCount(), "test Save() failed")
+	// the statistics of the later entries in the same window are accumulated
+	windowStart := time.Now().Truncate(DefaultSlowLogWindow)
+	stat := NewSlowLogStat(testSlowLogSQLID, testSlowLogFingerprint, testSlowLogExample, testDBName,
+		windowStart, testSlowLogExecCount, testSlowLogTotalExecTime, testSlowLogRowsExamined)
+	ingested, err := testSlowLogRepo.GetIngestedRange(testMySQLServerID)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	ingestion := NewSlowLogIngestion(testMySQLServerID, windowStart.Add(time.Second), windowStart.Add(time.Minute), nil, testSlowLogExecCount)
+	err = testSlowLogRepo.Save(ingested, ingestion, []query.SlowLogStat{stat})
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	q, err = testSlowLogRepo.GetBySQLID(testServiceName, testSlowLogSQLID)
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+	asst.Equal(testSlowLogExecCount*2, q.GetExecCount(), "test Save() failed")
+	asst.Equal(testSlowLogRowsExamined, q.GetRowsExaminedMax(), "test Save() failed")
+	// delete
+	err = testDeleteSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test Save() failed", err))
+}
+
+func TestSlowLogRepo_GetIngestedRange(t *testing.T) {
+	asst := assert.New(t)
+
+	ingestion, err := testSlowLogRepo.GetIngestedRange(testMySQLServerID)
+	asst.Nil(err, common.CombineMessageWithError("test GetIngestedRange() failed", err))
+	asst.Nil(ingestion, "test GetIngestedRange() failed")
+	err = testCreateSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test GetIngestedRange() failed", err))
+	ingestion, err = testSlowLogRepo.GetIngestedRange(testMySQLServerID)
+	asst.Nil(err, common.CombineMessageWithError("test GetIngestedRange() failed", err))
+	asst.Equal(time.Now().Truncate(DefaultSlowLogWindow).Unix(), ingestion.GetEndTime().Unix(), "test GetIngestedRange() failed")
+	asst.Equal(testSlowLogExecCount, ingestion.GetEntryCount(), "test GetIngestedRange() failed")
+	// delete
+	err = testDeleteSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test GetIngestedRange() failed", err))
+}
+
+func TestSlowLogRepo_Covers(t *testing.T) {
+	asst := assert.New(t)
+
+	windowStart := time.Now().Truncate(DefaultSlowLogWindow)
+	covered, err := testSlowLogRepo.Covers(testMySQLServerID, windowStart, windowStart.Add(DefaultSlowLogWindow))
+	asst.Nil(err, common.CombineMessageWithError("test Covers() failed", err))
+	asst.False(covered, "test Covers() failed")
+	err = testCreateSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test Covers() failed", err))
+	// the window of the ingested entries is covered
+	covered, err = testSlowLogRepo.Covers(testMySQLServerID, windowStart, windowStart.Add(DefaultSlowLogWindow))
+	asst.Nil(err, common.CombineMessageWithError("test Covers() failed", err))
+	asst.True(covered, "test Covers() failed")
+	// the previous window is not covered
+	covered, err = testSlowLogRepo.Covers(testMySQLServerID, windowStart.Add(-DefaultSlowLogWindow), windowStart.Add(DefaultSlowLogWindow))
+	asst.Nil(err, common.CombineMessageWithError("test Covers() failed", err))
+	asst.False(covered, "test Covers() failed")
+	// delete
+	err = testDeleteSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test Covers() failed", err))
+}
+
+func TestSlowLogRepo_GetByServiceNames(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testCreateSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test GetByServiceNames() failed", err))
+	queries, err := testSlowLogRepo.GetByServiceNames([]string{testServiceName})
+	asst.Nil(err, common.CombineMessageWithError("test GetByServiceNames() failed", err))
+	asst.NotZero(len(queries), "test GetByServiceNames() failed")
+	// delete
+	err = testDeleteSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test GetByServiceNames() failed", err))
+}
+
+func TestSlowLogRepo_GetByDBName(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testCreateSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test GetByDBName() failed", err))
+	queries, err := testSlowLogRepo.GetByDBName(testServiceName, testDBName)
+	asst.Nil(err, common.CombineMessageWithError("test GetByDBName() failed", err))
+	for _, q := range queries {
+		asst.Equal(testDBName, q.GetDBName(), "test GetByDBName() failed")
+	}
+	// delete
+	err = testDeleteSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test GetByDBName() failed", err))
+}
+
+func TestSlowLogRepo_GetBySQLID(t *testing.T) {
+	asst := assert.New(t)
+
+	err := testCreateSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test GetBySQLID() failed", err))
+	q, err := testSlowLogRepo.GetBySQLID(testServiceName, testSlowLogSQLID)
+	asst.Nil(err, common.CombineMessageWithError("test GetBySQLID() failed", err))
+	asst.Equal(testSlowLogSQLID, q.GetSQLID(), "test GetBySQLID() failed")
+	asst.Equal(testSlowLogFingerprint, q.GetFingerprint(), "test GetBySQLID() failed")
+	// delete
+	err = testDeleteSlowLogStat()
+	asst.Nil(err, common.CombineMessageWithError("test GetBySQLID() failed", err))
+}
@@ -1,15 +1,26 @@
 package query
 
 import (
+	"io"
+
 	"github.com/romberli/das/internal/app/metadata"
 	"github.com/romberli/das/internal/dependency/query"
 	"github.com/romberli/go-util/common"
 	"github.com/romberli/go-util/constant"
 )
 
-const QueriesStruct = "Queries"
+const (
+	QueriesStruct = "Queries"
 
-var _ query.Service = (*Service)(nil)
+	slowLogServiceEntryCountStruct   = "EntryCount"
+	slowLogServiceSkippedCountStruct = "SkippedCount"
+	slowLogServiceStatCountStruct    = "StatCount"
+)
+
+var (
+	_ query.Service        = (*Service)(nil)
+	_ query.SlowLogService = (*SlowLogService)(nil)
+)
 
 type Service struct {
 	config  query.Config
@@ -132,3 +143,91 @@ func (s *Service) Save(mysqlClusterID, mysqlServerID, dbID int, sqlID string) er
 func (s *Service) Marshal() ([]byte, error) {
 	return common.MarshalStructWithFields(s, QueriesStruct)
 }
+
+// SlowLogService ingests the slow log files of the mysql servers as the query source
+type SlowLogService struct {
+	parser       *SlowLogParser
+	repo         query.SlowLogRepo
+	EntryCount   int `json:"entry_count"`
+	SkippedCount int `json:"skipped_count"`
+	StatCount    int `json:"stat_count"`
+}
+
+// NewSlowLogService returns a new query.SlowLogService
+func NewSlowLogService(parser *SlowLogParser, repo query.SlowLogRepo) query.SlowLogService {
+	return newSlowLogService(parser, repo)
+}
+
+// NewSlowLogServiceWithDefault returns a new query.SlowLogService with default parser and repository
+func NewSlowLogServiceWithDefault() query.SlowLogService {
+	return newSlowLogService(NewSlowLogParserWithDefault(), NewSlowLogRepoWithGlobal(NewConfigWithDefault()))
+}
+
+// newSlowLogService returns a new *SlowLogService
+func newSlowLogService(parser *SlowLogParser, repo query.SlowLogRepo) *SlowLogService {
+	return &SlowLogService{
+		parser: parser,
+		repo:   repo,
+	}
+}
+
+// GetEntryCount returns the number of the parsed slow log entries
+func (slss *SlowLogService) GetEntryCount() int {
+	return slss.EntryCount
+}
+
+// GetSkippedCount returns the number of the parsed slow log entries which were ingested before
+func (slss *SlowLogService) GetSkippedCount() int {
+	return slss.SkippedCount
+}
+
+// GetStatCount returns the number of the saved slow log statistics
+func (slss *SlowLogService) GetStatCount() int {
+	return slss.StatCount
+}
+
+// Ingest parses the slow log from the reader and saves the statistics of the mysql server,
+// the entries which are earlier than the last ingested entry of the mysql server are skipped,
+// the entries of the same time as the last ingested entry are skipped only if they have the same keys as the ingested ones,
+// so ingesting the same slow log file again or ingesting a growing slow log file repeatedly does not count any entry twice
+func (slss *SlowLogService) Ingest(mysqlServerID int, reader io.Reader) error {
+	// check if the mysql server exists
+	mysqlServerService := metadata.NewMySQLServerServiceWithDefault()
+	err := mysqlServerService.GetByID(mysqlServerID)
+	if err != nil {
+		return err
+	}
+	// get the ingested range first, so that the ingested entries could be skipped while parsing
+	ingested, err := slss.repo.GetIngestedRange(mysqlServerID)
+	if err != nil {
+		return err
+	}
+	// parse and aggregate the slow log as it is read
+	aggregator := newSlowLogAggregator(slss.parser, ingested)
+	err = slss.parser.ParseFunc(reader, aggregator.add)
+	if err != nil {
+		return err
+	}
+
+	slss.EntryCount = aggregator.getEntryCount()
+	slss.SkippedCount = aggregator.getSkippedCount()
+	slss.StatCount = constant.ZeroInt
+	ingestion := aggregator.getIngestion(mysqlServerID)
+	if ingestion == nil {
+		return nil
+	}
+
+	stats := aggregator.getStats()
+	err = slss.repo.Save(ingested, ingestion, stats)
+	if err != nil {
+		return err
+	}
+	slss.StatCount = len(stats)
+
+	return nil
+}
+
+// Marshal marshals SlowLogService to json bytes
+func (slss *SlowLogService) Marshal() ([]byte, error) {
+	return common.MarshalStructWithFields(slss, slowLogServiceEntryCountStruct, slowLogServiceSkippedCountStruct, slowLogServiceStatCountStruct)
+}
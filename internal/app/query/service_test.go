@@ -1,16 +1,22 @@
 package query
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
 	"github.com/stretchr/testify/assert"
 )
 
-var testService *Service
+var (
+	testService        *Service
+	testSlowLogService *SlowLogService
+)
 
 func init() {
 	testService = newService(NewConfigWithDefault(), testDASRepo)
+	testSlowLogService = newSlowLogService(NewSlowLogParserWithDefault(), testSlowLogRepo)
 }
 
 func TestService_All(t *testing.T) {
@@ -83,3 +89,37 @@ func TestService_Marshal(t *testing.T) {
 	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
 	t.Log(string(jsonBytes))
 }
+
+func TestSlowLogService_All(t *testing.T) {
+	TestSlowLogService_Ingest(t)
+	TestSlowLogService_Marshal(t)
+}
+
+func TestSlowLogService_Ingest(t *testing.T) {
+	asst := assert.New(t)
+
+	testInitMySQLInfo()
+	err := testSlowLogService.Ingest(testMySQLServerID, strings.NewReader(testSlowLogStandard))
+	asst.Nil(err, common.CombineMessageWithError("test Ingest() failed", err))
+	asst.Equal(2, testSlowLogService.GetEntryCount(), "test Ingest() failed")
+	asst.Equal(constant.ZeroInt, testSlowLogService.GetSkippedCount(), "test Ingest() failed")
+	asst.Equal(1, testSlowLogService.GetStatCount(), "test Ingest() failed")
+	// the ingested entries are skipped
+	err = testSlowLogService.Ingest(testMySQLServerID, strings.NewReader(testSlowLogStandard))
+	asst.Nil(err, common.CombineMessageWithError("test Ingest() failed", err))
+	asst.Equal(2, testSlowLogService.GetSkippedCount(), "test Ingest() failed")
+	asst.Equal(constant.ZeroInt, testSlowLogService.GetStatCount(), "test Ingest() failed")
+	// delete
+	_, err = testSlowLogRepo.Execute(`delete from t_query_slow_log_stat where mysql_server_id = ?;`, testMySQLServerID)
+	asst.Nil(err, common.CombineMessageWithError("test Ingest() failed", err))
+	_, err = testSlowLogRepo.Execute(`delete from t_query_slow_log_ingestion where mysql_server_id = ?;`, testMySQLServerID)
+	asst.Nil(err, common.CombineMessageWithError("test Ingest() failed", err))
+}
+
+func TestSlowLogService_Marshal(t *testing.T) {
+	asst := assert.New(t)
+
+	jsonBytes, err := testSlowLogService.Marshal()
+	asst.Nil(err, common.CombineMessageWithError("test Marshal() failed", err))
+	t.Log(string(jsonBytes))
+}
@@ -0,0 +1,598 @@
+package query
+
+import (
+	"bufio"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/romberli/das/internal/dependency/query"
+	"github.com/romberli/das/pkg/message"
+	msgquery "github.com/romberli/das/pkg/message/query"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/middleware/sql/parser"
+)
+
+const (
+	// DefaultSlowLogWindow is the length of the statistic windows of the slow log statistics
+	DefaultSlowLogWindow = time.Hour
+	// maxSlowLogLineSize is the maximum size of a line of the slow log file, a long statement may be written in one line
+	maxSlowLogLineSize = 64 * 1024 * 1024
+
+	slowLogHeaderPrefix       = "# "
+	slowLogTimePrefix         = "# Time:"
+	slowLogUserHostPrefix     = "# User@Host:"
+	slowLogAdminCommandPrefix = "# administrator command:"
+	slowLogUsePrefix          = "use "
+	slowLogSetTimestampPrefix = "SET timestamp="
+	slowLogStatementSuffix    = ";"
+	slowLogBacktick           = "`"
+
+	slowLogSchemaKey       = "Schema"
+	slowLogQueryTimeKey    = "Query_time"
+	slowLogLockTimeKey     = "Lock_time"
+	slowLogRowsSentKey     = "Rows_sent"
+	slowLogRowsExaminedKey = "Rows_examined"
+	slowLogIDKey           = "Id"
+	slowLogThreadIDKey     = "Thread_id"
+
+	// slowLogLegacyTimeLayout is the time layout of the slow log of mysql 5.6 and earlier, e.g. 230101  9:00:00
+	slowLogLegacyTimeLayout = "060102 15:04:05"
+)
+
+var (
+	_ query.SlowLogStat      = (*SlowLogStat)(nil)
+	_ query.SlowLogIngestion = (*SlowLogIngestion)(nil)
+
+	// the lines which are written when the mysql server starts or the slow log is flushed, they look like:
+	// /usr/sbin/mysqld, Version: 5.7.36-log (MySQL Community Server (GPL)). started with:
+	// Tcp port: 3306  Unix socket: /tmp/mysql.sock
+	// Time                 Id Command    Argument
+	slowLogStartupRegexp = regexp.MustCompile(`^(.+, Version: .+ started with:|Tcp port: \d+\s+Unix socket: .*|Time\s+Id\s+Command\s+Argument)$`)
+)
+
+// SlowLogEntry is an entry of the slow log file
+type SlowLogEntry struct {
+	Time         time.Time
+	ThreadID     int
+	DBName       string
+	QueryTime    float64
+	LockTime     float64
+	RowsSent     int
+	RowsExamined int
+	SQLText      string
+}
+
+// SlowLogStat is the statistic of the slow log entries which have the same sql identity and db name in a statistic window
+type SlowLogStat struct {
+	SQLID           string    `middleware:"sql_id" json:"sql_id"`
+	Fingerprint     string    `middleware:"fingerprint" json:"fingerprint"`
+	Example         string    `middleware:"example" json:"example"`
+	DBName          string    `middleware:"db_name" json:"db_name"`
+	WindowStart     time.Time `middleware:"window_start" json:"window_start"`
+	ExecCount       int       `middleware:"exec_count" json:"exec_count"`
+	TotalExecTime   float64   `middleware:"total_exec_time" json:"total_exec_time"`
+	RowsExaminedMax int       `middleware:"rows_examined_max" json:"rows_examined_max"`
+	maxQueryTime    float64
+}
+
+// NewSlowLogStat returns a new query.SlowLogStat
+func NewSlowLogStat(sqlID, fingerprint, example, dbName string, windowStart time.Time, execCount int,
+	totalExecTime float64, rowsExaminedMax int) query.SlowLogStat {
+	return &SlowLogStat{
+		SQLID:           sqlID,
+		Fingerprint:     fingerprint,
+		Example:         example,
+		DBName:          dbName,
+		WindowStart:     windowStart,
+		ExecCount:       execCount,
+		TotalExecTime:   totalExecTime,
+		RowsExaminedMax: rowsExaminedMax,
+	}
+}
+
+// GetSQLID returns the sql identity
+func (sls *SlowLogStat) GetSQLID() string {
+	return sls.SQLID
+}
+
+// GetFingerprint returns the fingerprint
+func (sls *SlowLogStat) GetFingerprint() string {
+	return sls.Fingerprint
+}
+
+// GetExample returns the example
+func (sls *SlowLogStat) GetExample() string {
+	return sls.Example
+}
+
+// GetDBName returns the db name
+func (sls *SlowLogStat) GetDBName() string {
+	return sls.DBName
+}
+
+// GetWindowStart returns the start time of the statistic window
+func (sls *SlowLogStat) GetWindowStart() time.Time {
+	return sls.WindowStart
+}
+
+// GetExecCount returns the execution count
+func (sls *SlowLogStat) GetExecCount() int {
+	return sls.ExecCount
+}
+
+// GetTotalExecTime returns the total execution time
+func (sls *SlowLogStat) GetTotalExecTime() float64 {
+	return sls.TotalExecTime
+}
+
+// GetRowsExaminedMax returns the maximum row examined
+func (sls *SlowLogStat) GetRowsExaminedMax() int {
+	return sls.RowsExaminedMax
+}
+
+// add adds the entry to the statistic, the slowest statement is kept as the example
+func (sls *SlowLogStat) add(entry *SlowLogEntry) {
+	sls.ExecCount++
+	sls.TotalExecTime += entry.QueryTime
+	if entry.RowsExamined > sls.RowsExaminedMax {
+		sls.RowsExaminedMax = entry.RowsExamined
+	}
+	if sls.Example == constant.EmptyString || entry.QueryTime > sls.maxQueryTime {
+		sls.Example = entry.SQLText
+		sls.maxQueryTime = entry.QueryTime
+	}
+}
+
+// Key returns the key of the entry, the entries which have the same time, thread id and statement are considered the same entry
+func (sle *SlowLogEntry) Key() string {
+	return fmt.Sprintf("%d-%d-%08x", sle.Time.UnixNano(), sle.ThreadID, crc32.ChecksumIEEE([]byte(sle.SQLText)))
+}
+
+// SlowLogIngestion is an ingestion of the slow log of the mysql server,
+// the start time is the time of the first entry of the slow log file, the entries which were ingested before are included,
+// the end time is the time of the last ingested entry, and the end keys are the keys of the ingested entries of the end time
+type SlowLogIngestion struct {
+	MySQLServerID int       `middleware:"mysql_server_id" json:"mysql_server_id"`
+	StartTime     time.Time `middleware:"start_time" json:"start_time"`
+	EndTime       time.Time `middleware:"end_time" json:"end_time"`
+	EndKeys       string    `middleware:"end_keys" json:"end_keys"`
+	EntryCount    int       `middleware:"entry_count" json:"entry_count"`
+}
+
+// NewSlowLogIngestion returns a new query.SlowLogIngestion
+func NewSlowLogIngestion(mysqlServerID int, startTime, endTime time.Time, endKeys []string, entryCount int) query.SlowLogIngestion {
+	return &SlowLogIngestion{
+		MySQLServerID: mysqlServerID,
+		StartTime:     startTime,
+		EndTime:       endTime,
+		EndKeys:       strings.Join(endKeys, constant.CommaString),
+		EntryCount:    entryCount,
+	}
+}
+
+// NewEmptySlowLogIngestion returns a new *SlowLogIngestion with empty value
+func NewEmptySlowLogIngestion() *SlowLogIngestion {
+	return &SlowLogIngestion{}
+}
+
+// GetMySQLServerID returns the mysql server identity
+func (sli *SlowLogIngestion) GetMySQLServerID() int {
+	return sli.MySQLServerID
+}
+
+// GetStartTime returns the time of the first ingested slow log entry
+func (sli *SlowLogIngestion) GetStartTime() time.Time {
+	return sli.StartTime
+}
+
+// GetEndTime returns the time of the last ingested slow log entry
+func (sli *SlowLogIngestion) GetEndTime() time.Time {
+	return sli.EndTime
+}
+
+// GetEndKeys returns the keys of the ingested slow log entries of the end time
+func (sli *SlowLogIngestion) GetEndKeys() []string {
+	if sli.EndKeys == constant.EmptyString {
+		return nil
+	}
+
+	return strings.Split(sli.EndKeys, constant.CommaString)
+}
+
+// GetEntryCount returns the number of the ingested slow log entries
+func (sli *SlowLogIngestion) GetEntryCount() int {
+	return sli.EntryCount
+}
+
+// slowLogAggregator skips the ingested entries and aggregates the new entries as they are parsed,
+// so the entries of the slow log file do not need to be kept in memory.
+// the entries earlier than the end time of the ingested slow log were ingested,
+// the entries of the end time were ingested only if their keys are in the end keys,
+// as a key may appear several times, each end key matches only one entry
+type slowLogAggregator struct {
+	parser          *SlowLogParser
+	ingested        query.SlowLogIngestion
+	ingestedEndKeys map[string]int
+	stats           []query.SlowLogStat
+	statMap         map[string]*SlowLogStat
+	entryCount      int
+	newEntryCount   int
+	startTime       time.Time
+	endTime         time.Time
+	endKeys         []string
+}
+
+// newSlowLogAggregator returns a new *slowLogAggregator, ingested is the ingested slow log of the mysql server,
+// it is nil if the slow log was never ingested
+func newSlowLogAggregator(parser *SlowLogParser, ingested query.SlowLogIngestion) *slowLogAggregator {
+	ingestedEndKeys := make(map[string]int)
+	if ingested != nil {
+		for _, key := range ingested.GetEndKeys() {
+			ingestedEndKeys[key]++
+		}
+	}
+
+	return &slowLogAggregator{
+		parser:          parser,
+		ingested:        ingested,
+		ingestedEndKeys: ingestedEndKeys,
+		statMap:         make(map[string]*SlowLogStat),
+	}
+}
+
+// isIngested checks if the entry was ingested before
+func (sla *slowLogAggregator) isIngested(entry *SlowLogEntry) bool {
+	if sla.ingested == nil || entry.Time.After(sla.ingested.GetEndTime()) {
+		return false
+	}
+	if entry.Time.Before(sla.ingested.GetEndTime()) {
+		return true
+	}
+
+	key := entry.Key()
+	if sla.ingestedEndKeys[key] > constant.ZeroInt {
+		sla.ingestedEndKeys[key]--
+		return true
+	}
+
+	return false
+}
+
+// add adds the entry to the statistic of its sql identity, db name and statistic window if it was not ingested before
+func (sla *slowLogAggregator) add(entry *SlowLogEntry) {
+	sla.entryCount++
+	if sla.entryCount == 1 || entry.Time.Before(sla.startTime) {
+		sla.startTime = entry.Time
+	}
+	if sla.isIngested(entry) {
+		return
+	}
+
+	sla.newEntryCount++
+	switch {
+	case sla.newEntryCount == 1 || entry.Time.After(sla.endTime):
+		sla.endTime = entry.Time
+		sla.endKeys = []string{entry.Key()}
+	case entry.Time.Equal(sla.endTime):
+		sla.endKeys = append(sla.endKeys, entry.Key())
+	}
+
+	sqlID := sla.parser.parser.GetSQLID(entry.SQLText)
+	windowStart := entry.Time.Truncate(sla.parser.GetWindow()).In(time.Local)
+	key := fmt.Sprintf("%s:%s:%d", sqlID, entry.DBName, windowStart.Unix())
+
+	stat, ok := sla.statMap[key]
+	if !ok {
+		stat = &SlowLogStat{
+			SQLID:       sqlID,
+			Fingerprint: sla.parser.parser.GetFingerprint(entry.SQLText),
+			DBName:      entry.DBName,
+			WindowStart: windowStart,
+		}
+		sla.statMap[key] = stat
+		sla.stats = append(sla.stats, stat)
+	}
+	stat.add(entry)
+}
+
+// getEntryCount returns the number of the added entries
+func (sla *slowLogAggregator) getEntryCount() int {
+	return sla.entryCount
+}
+
+// getSkippedCount returns the number of the added entries which were ingested before
+func (sla *slowLogAggregator) getSkippedCount() int {
+	return sla.entryCount - sla.newEntryCount
+}
+
+// getStats returns the statistics of the new entries in the order of their first entries
+func (sla *slowLogAggregator) getStats() []query.SlowLogStat {
+	return sla.stats
+}
+
+// getIngestion returns the ingestion of the new entries, the start time is the time of the first added entry,
+// the entries which were ingested before are included, it returns nil if there is no new entry
+func (sla *slowLogAggregator) getIngestion(mysqlServerID int) query.SlowLogIngestion {
+	if sla.newEntryCount == constant.ZeroInt {
+		return nil
+	}
+
+	return NewSlowLogIngestion(mysqlServerID, sla.startTime, sla.endTime, sla.endKeys, sla.newEntryCount)
+}
+
+// coversSlowLogTimeRange checks if the ingestions cover the whole time range, the ingestions must be ordered by the start time,
+// an ingestion covers the windows from the window of its start time to the window of its end time, as the windows without any slow query have no entry,
+// two ingestions are continuous if the later one starts not later than the window next to the end window of the earlier one,
+// otherwise, the windows between them are considered not ingested
+func coversSlowLogTimeRange(ingestions []query.SlowLogIngestion, startTime, endTime time.Time) bool {
+	var coveredStartTime, coveredEndTime time.Time
+	for i, ingestion := range ingestions {
+		ingestionStartTime := ingestion.GetStartTime().Truncate(DefaultSlowLogWindow)
+		ingestionEndTime := ingestion.GetEndTime().Truncate(DefaultSlowLogWindow).Add(DefaultSlowLogWindow)
+		if i == constant.ZeroInt || ingestionStartTime.After(coveredEndTime) {
+			if i > constant.ZeroInt && !startTime.Before(coveredStartTime) && !endTime.After(coveredEndTime) {
+				return true
+			}
+			coveredStartTime = ingestionStartTime
+			coveredEndTime = ingestionEndTime
+			continue
+		}
+		if ingestionEndTime.After(coveredEndTime) {
+			coveredEndTime = ingestionEndTime
+		}
+	}
+
+	return len(ingestions) > constant.ZeroInt && !startTime.Before(coveredStartTime) && !endTime.After(coveredEndTime)
+}
+
+// SlowLogParser parses the mysql slow log files of the standard format and the percona extended format,
+// and aggregates the entries by the fingerprint in the statistic windows
+type SlowLogParser struct {
+	parser *parser.Parser
+	window time.Duration
+}
+
+// NewSlowLogParser returns a new *SlowLogParser
+func NewSlowLogParser(window time.Duration) *SlowLogParser {
+	return &SlowLogParser{
+		parser: parser.NewParserWithDefault(),
+		window: window,
+	}
+}
+
+// NewSlowLogParserWithDefault returns a new *SlowLogParser with the default statistic window
+func NewSlowLogParserWithDefault() *SlowLogParser {
+	return NewSlowLogParser(DefaultSlowLogWindow)
+}
+
+// GetWindow returns the length of the statistic windows
+func (slp *SlowLogParser) GetWindow() time.Duration {
+	return slp.window
+}
+
+// Parse parses the slow log from the reader and returns the entries,
+// the administrator commands and the entries without time are ignored.
+// an entry looks like:
+// # Time: 2023-01-01T10:00:00.123456+08:00
+// # User@Host: root[root] @ localhost []  Id:    10
+// # Schema: das  Last_errno: 0  Killed: 0 (percona only)
+// # Query_time: 3.000125  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 0
+// use das;
+// SET timestamp=1672538400;
+// select sleep(3);
+func (slp *SlowLogParser) Parse(reader io.Reader) ([]*SlowLogEntry, error) {
+	var entries []*SlowLogEntry
+	err := slp.ParseFunc(reader, func(entry *SlowLogEntry) {
+		entries = append(entries, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ParseFunc parses the slow log from the reader line by line and calls handle with each entry once it is parsed,
+// so the slow log file is never loaded into memory as a whole, see Parse for the format of the entries
+func (slp *SlowLogParser) ParseFunc(reader io.Reader, handle func(entry *SlowLogEntry)) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, bufio.MaxScanTokenSize), maxSlowLogLineSize)
+
+	var (
+		lineNum     int
+		lastTime    time.Time
+		currentDB   string
+		schema      string
+		statement   []string
+		inStatement bool
+		hasStats    bool
+		isAdmin     bool
+	)
+
+	entry := &SlowLogEntry{}
+	finish := func() {
+		if inStatement && hasStats && !isAdmin && !entry.Time.IsZero() {
+			entry.DBName = currentDB
+			if schema != constant.EmptyString {
+				entry.DBName = schema
+			}
+			entry.SQLText = strings.TrimSuffix(strings.TrimSpace(strings.Join(statement, constant.LFString)), slowLogStatementSuffix)
+			if entry.SQLText != constant.EmptyString {
+				handle(entry)
+			}
+		}
+		entry = &SlowLogEntry{Time: lastTime}
+		schema = constant.EmptyString
+		statement = nil
+		inStatement = false
+		hasStats = false
+		isAdmin = false
+	}
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), constant.CRLFString)
+
+		if slowLogStartupRegexp.MatchString(line) {
+			finish()
+			continue
+		}
+		if strings.HasPrefix(line, slowLogTimePrefix) || strings.HasPrefix(line, slowLogUserHostPrefix) {
+			if inStatement || hasStats {
+				finish()
+			}
+		}
+		if inStatement {
+			statement = append(statement, line)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, slowLogTimePrefix):
+			t, err := parseSlowLogTime(strings.TrimSpace(strings.TrimPrefix(line, slowLogTimePrefix)))
+			if err != nil {
+				return message.NewMessage(msgquery.ErrQueryParseSlowLog, err, lineNum, line)
+			}
+			lastTime = t
+			entry.Time = t
+		case strings.HasPrefix(line, slowLogAdminCommandPrefix):
+			// the administrator commands such as ping and quit are not statements
+			isAdmin = true
+			inStatement = true
+		case strings.HasPrefix(line, slowLogHeaderPrefix):
+			attributes := parseSlowLogAttributes(line)
+			if value, ok := attributes[slowLogSchemaKey]; ok {
+				schema = value
+			}
+			err := entry.setThreadID(attributes)
+			if err != nil {
+				return message.NewMessage(msgquery.ErrQueryParseSlowLog, err, lineNum, line)
+			}
+			if value, ok := attributes[slowLogQueryTimeKey]; ok {
+				err = entry.setStats(value, attributes)
+				if err != nil {
+					return message.NewMessage(msgquery.ErrQueryParseSlowLog, err, lineNum, line)
+				}
+				hasStats = true
+			}
+		case strings.HasPrefix(line, slowLogUsePrefix) && strings.HasSuffix(line, slowLogStatementSuffix):
+			currentDB = strings.Trim(strings.TrimSuffix(strings.TrimPrefix(line, slowLogUsePrefix), slowLogStatementSuffix), slowLogBacktick)
+		case strings.HasPrefix(line, slowLogSetTimestampPrefix):
+			timestamp, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimPrefix(line, slowLogSetTimestampPrefix), slowLogStatementSuffix), 64)
+			if err != nil {
+				return message.NewMessage(msgquery.ErrQueryParseSlowLog, err, lineNum, line)
+			}
+			entry.Time = time.Unix(int64(timestamp), 0)
+		case strings.TrimSpace(line) == constant.EmptyString:
+			continue
+		default:
+			statement = append(statement, line)
+			inStatement = true
+		}
+	}
+	err := scanner.Err()
+	if err != nil {
+		return message.NewMessage(msgquery.ErrQueryParseSlowLog, err, lineNum, constant.EmptyString)
+	}
+	finish()
+
+	return nil
+}
+
+// Aggregate groups the entries by the sql identity, the db name and the statistic window,
+// the statistics are returned in the order of their first entries
+func (slp *SlowLogParser) Aggregate(entries []*SlowLogEntry) []query.SlowLogStat {
+	aggregator := newSlowLogAggregator(slp, nil)
+	for _, entry := range entries {
+		aggregator.add(entry)
+	}
+
+	return aggregator.getStats()
+}
+
+// setThreadID sets the thread id of the entry with the attributes of the header line,
+// mysql writes it in the user host line, e.g. # User@Host: root[root] @ localhost []  Id:    10,
+// percona writes it in a separate line, e.g. # Thread_id: 8  Schema: test
+func (sle *SlowLogEntry) setThreadID(attributes map[string]string) error {
+	for _, key := range []string{slowLogIDKey, slowLogThreadIDKey} {
+		value, ok := attributes[key]
+		if !ok || value == constant.EmptyString {
+			continue
+		}
+		threadID, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		sle.ThreadID = threadID
+	}
+
+	return nil
+}
+
+// setStats sets the statistics of the entry with the attributes of the query time line
+func (sle *SlowLogEntry) setStats(queryTime string, attributes map[string]string) error {
+	var err error
+
+	sle.QueryTime, err = strconv.ParseFloat(queryTime, 64)
+	if err != nil {
+		return err
+	}
+	if value, ok := attributes[slowLogLockTimeKey]; ok {
+		sle.LockTime, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+	}
+	if value, ok := attributes[slowLogRowsSentKey]; ok {
+		sle.RowsSent, err = strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+	}
+	if value, ok := attributes[slowLogRowsExaminedKey]; ok {
+		sle.RowsExamined, err = strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseSlowLogAttributes parses the key value pairs of the header line, e.g. # Query_time: 3.000125  Lock_time: 0.000000,
+// the value of the key is empty if it is followed by another key directly, e.g. # Schema:   Last_errno: 0
+func parseSlowLogAttributes(line string) map[string]string {
+	attributes := make(map[string]string)
+
+	fields := strings.Fields(strings.TrimPrefix(line, slowLogHeaderPrefix))
+	for i := constant.ZeroInt; i < len(fields); i++ {
+		if !strings.HasSuffix(fields[i], constant.ColonString) {
+			continue
+		}
+		key := strings.TrimSuffix(fields[i], constant.ColonString)
+		if i+1 < len(fields) && !strings.HasSuffix(fields[i+1], constant.ColonString) {
+			attributes[key] = fields[i+1]
+			i++
+			continue
+		}
+		attributes[key] = constant.EmptyString
+	}
+
+	return attributes
+}
+
+// parseSlowLogTime parses the value of the time line,
+// mysql 5.7 and later write the time in rfc3339 format, mysql 5.6 and earlier write it in the local time zone
+func parseSlowLogTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation(slowLogLegacyTimeLayout, strings.Join(strings.Fields(value), constant.SpaceString), time.Local)
+}
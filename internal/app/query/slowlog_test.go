@@ -0,0 +1,228 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romberli/das/internal/dependency/query"
+	"github.com/romberli/go-util/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSlowLogStandard = `/usr/sbin/mysqld, Version: 5.7.36-log (MySQL Community Server (GPL)). started with:
+Tcp port: 3306  Unix socket: /tmp/mysql.sock
+Time                 Id Command    Argument
+# Time: 2023-01-01T10:00:00.123456+08:00
+# User@Host: root[root] @ localhost []  Id:    10
+# Query_time: 3.000125  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 100
+use das;
+SET timestamp=1672538400;
+select * from t_meta_db_info where id = 1;
+# Time: 2023-01-01T10:05:00.123456+08:00
+# User@Host: root[root] @ localhost []  Id:    10
+# Query_time: 1.500000  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 300
+SET timestamp=1672538700;
+select *
+from t_meta_db_info
+where id = 2;
+# Time: 2023-01-01T10:06:00.123456+08:00
+# User@Host: root[root] @ localhost []  Id:    10
+# Query_time: 0.500000  Lock_time: 0.000000 Rows_sent: 0  Rows_examined: 0
+SET timestamp=1672538760;
+# administrator command: Quit;
+`
+	testSlowLogPercona = `# Time: 230101  9:00:00
+# User@Host: root[root] @ localhost []
+# Thread_id: 8  Schema: test  Last_errno: 0  Killed: 0
+# Query_time: 2.000000  Lock_time: 0.000100  Rows_sent: 1  Rows_examined: 10  Rows_affected: 0  Rows_read: 10
+# Bytes_sent: 56  Tmp_tables: 0  Tmp_disk_tables: 0  Tmp_table_sizes: 0
+# QC_Hit: No  Full_scan: Yes  Full_join: No  Tmp_table: No  Tmp_table_on_disk: No
+# No InnoDB statistics available for this query
+select count(*) from t1;
+# User@Host: root[root] @ localhost []
+# Thread_id: 8  Schema: test  Last_errno: 0  Killed: 0
+# Query_time: 1.000000  Lock_time: 0.000100  Rows_sent: 1  Rows_examined: 20  Rows_affected: 0  Rows_read: 20
+select count(*) from t2;
+`
+)
+
+func TestSlowLog_All(t *testing.T) {
+	TestSlowLogParser_Parse(t)
+	TestSlowLogParser_Aggregate(t)
+	TestSlowLogEntry_Key(t)
+	TestSlowLogParser_ParseFunc(t)
+	TestSlowLog_slowLogAggregator(t)
+	TestSlowLog_coversSlowLogTimeRange(t)
+	TestSlowLog_parseSlowLogAttributes(t)
+	TestSlowLog_parseSlowLogTime(t)
+}
+
+func TestSlowLogParser_Parse(t *testing.T) {
+	asst := assert.New(t)
+
+	slp := NewSlowLogParserWithDefault()
+	// standard format
+	entries, err := slp.Parse(strings.NewReader(testSlowLogStandard))
+	asst.Nil(err, common.CombineMessageWithError("test Parse() failed", err))
+	asst.Equal(2, len(entries), "test Parse() failed")
+	asst.Equal(time.Unix(1672538400, 0), entries[0].Time, "test Parse() failed")
+	asst.Equal("das", entries[0].DBName, "test Parse() failed")
+	asst.Equal(10, entries[0].ThreadID, "test Parse() failed")
+	asst.Equal(3.000125, entries[0].QueryTime, "test Parse() failed")
+	asst.Equal(100, entries[0].RowsExamined, "test Parse() failed")
+	asst.Equal("select * from t_meta_db_info where id = 1", entries[0].SQLText, "test Parse() failed")
+	asst.Equal("das", entries[1].DBName, "test Parse() failed")
+	asst.Equal("select *\nfrom t_meta_db_info\nwhere id = 2", entries[1].SQLText, "test Parse() failed")
+	// percona extended format
+	entries, err = slp.Parse(strings.NewReader(testSlowLogPercona))
+	asst.Nil(err, common.CombineMessageWithError("test Parse() failed", err))
+	asst.Equal(2, len(entries), "test Parse() failed")
+	asst.Equal("test", entries[0].DBName, "test Parse() failed")
+	asst.Equal(8, entries[0].ThreadID, "test Parse() failed")
+	asst.Equal(0.0001, entries[0].LockTime, "test Parse() failed")
+	asst.Equal(10, entries[0].RowsExamined, "test Parse() failed")
+	asst.Equal(entries[0].Time, entries[1].Time, "test Parse() failed")
+	// invalid query time
+	_, err = slp.Parse(strings.NewReader("# Time: 2023-01-01T10:00:00.123456+08:00\n# Query_time: x  Lock_time: 0.000000\n"))
+	asst.NotNil(err, "test Parse() failed")
+}
+
+func TestSlowLogParser_Aggregate(t *testing.T) {
+	asst := assert.New(t)
+
+	slp := NewSlowLogParserWithDefault()
+	entries, err := slp.Parse(strings.NewReader(testSlowLogStandard))
+	asst.Nil(err, common.CombineMessageWithError("test Aggregate() failed", err))
+	// the entries have the same fingerprint and are in the same window
+	stats := slp.Aggregate(entries)
+	asst.Equal(1, len(stats), "test Aggregate() failed")
+	asst.Equal(2, stats[0].GetExecCount(), "test Aggregate() failed")
+	asst.InDelta(4.500125, stats[0].GetTotalExecTime(), 0.000001, "test Aggregate() failed")
+	asst.Equal(300, stats[0].GetRowsExaminedMax(), "test Aggregate() failed")
+	asst.Equal("select * from t_meta_db_info where id = 1", stats[0].GetExample(), "test Aggregate() failed")
+	asst.Equal(time.Unix(1672538400, 0).Truncate(DefaultSlowLogWindow), stats[0].GetWindowStart(), "test Aggregate() failed")
+	// the entries are in different windows
+	slp = NewSlowLogParser(time.Minute)
+	stats = slp.Aggregate(entries)
+	asst.Equal(2, len(stats), "test Aggregate() failed")
+}
+
+func TestSlowLogEntry_Key(t *testing.T) {
+	asst := assert.New(t)
+
+	entry := &SlowLogEntry{Time: time.Unix(1672538400, 0), ThreadID: 10, SQLText: "select 1"}
+	asst.Equal(entry.Key(), (&SlowLogEntry{Time: time.Unix(1672538400, 0), ThreadID: 10, SQLText: "select 1", QueryTime: 1}).Key(), "test Key() failed")
+	// the entries of the same time differ in the thread id or the statement
+	asst.NotEqual(entry.Key(), (&SlowLogEntry{Time: time.Unix(1672538400, 0), ThreadID: 11, SQLText: "select 1"}).Key(), "test Key() failed")
+	asst.NotEqual(entry.Key(), (&SlowLogEntry{Time: time.Unix(1672538400, 0), ThreadID: 10, SQLText: "select 2"}).Key(), "test Key() failed")
+}
+
+func TestSlowLogParser_ParseFunc(t *testing.T) {
+	asst := assert.New(t)
+
+	slp := NewSlowLogParserWithDefault()
+	var sqlTexts []string
+	err := slp.ParseFunc(strings.NewReader(testSlowLogStandard), func(entry *SlowLogEntry) {
+		sqlTexts = append(sqlTexts, entry.SQLText)
+	})
+	asst.Nil(err, common.CombineMessageWithError("test ParseFunc() failed", err))
+	asst.Equal([]string{"select * from t_meta_db_info where id = 1", "select *\nfrom t_meta_db_info\nwhere id = 2"}, sqlTexts, "test ParseFunc() failed")
+}
+
+func TestSlowLog_slowLogAggregator(t *testing.T) {
+	asst := assert.New(t)
+
+	slp := NewSlowLogParserWithDefault()
+	first := &SlowLogEntry{Time: time.Unix(1672538400, 0), ThreadID: 10, SQLText: "select 1"}
+	second := &SlowLogEntry{Time: time.Unix(1672538700, 0), ThreadID: 10, SQLText: "select 2"}
+	third := &SlowLogEntry{Time: time.Unix(1672538700, 0), ThreadID: 11, SQLText: "select 2"}
+	fourth := &SlowLogEntry{Time: time.Unix(1672538760, 0), ThreadID: 10, SQLText: "select 3"}
+	aggregate := func(ingested query.SlowLogIngestion, entries ...*SlowLogEntry) *slowLogAggregator {
+		aggregator := newSlowLogAggregator(slp, ingested)
+		for _, entry := range entries {
+			aggregator.add(entry)
+		}
+		return aggregator
+	}
+	// the slow log was never ingested
+	aggregator := aggregate(nil, first, second)
+	asst.Equal(2, aggregator.getEntryCount(), "test slowLogAggregator failed")
+	asst.Equal(0, aggregator.getSkippedCount(), "test slowLogAggregator failed")
+	asst.Equal(2, len(aggregator.getStats()), "test slowLogAggregator failed")
+	ingested := aggregator.getIngestion(testMySQLServerID)
+	asst.Equal(first.Time, ingested.GetStartTime(), "test slowLogAggregator failed")
+	asst.Equal(second.Time, ingested.GetEndTime(), "test slowLogAggregator failed")
+	asst.Equal([]string{second.Key()}, ingested.GetEndKeys(), "test slowLogAggregator failed")
+	asst.Equal(2, ingested.GetEntryCount(), "test slowLogAggregator failed")
+	// the entry of the same time as the last ingested entry is not skipped if it was not ingested,
+	// the start time is the time of the first entry of the file, the end keys are the keys of the new entries of the end time
+	aggregator = aggregate(ingested, first, second, third)
+	asst.Equal(2, aggregator.getSkippedCount(), "test slowLogAggregator failed")
+	asst.Equal(1, len(aggregator.getStats()), "test slowLogAggregator failed")
+	ingestion := aggregator.getIngestion(testMySQLServerID)
+	asst.Equal(first.Time, ingestion.GetStartTime(), "test slowLogAggregator failed")
+	asst.Equal(third.Time, ingestion.GetEndTime(), "test slowLogAggregator failed")
+	asst.Equal([]string{third.Key()}, ingestion.GetEndKeys(), "test slowLogAggregator failed")
+	asst.Equal(1, ingestion.GetEntryCount(), "test slowLogAggregator failed")
+	// the end keys are replaced once a later entry is added
+	aggregator = aggregate(ingested, first, second, third, fourth)
+	asst.Equal([]string{fourth.Key()}, aggregator.getIngestion(testMySQLServerID).GetEndKeys(), "test slowLogAggregator failed")
+	// the same entry appears twice, only one of them was ingested
+	aggregator = aggregate(ingested, first, second, second)
+	asst.Equal(1, aggregator.getIngestion(testMySQLServerID).GetEntryCount(), "test slowLogAggregator failed")
+	// all the entries were ingested
+	aggregator = aggregate(ingested, first, second)
+	asst.Nil(aggregator.getIngestion(testMySQLServerID), "test slowLogAggregator failed")
+	asst.Nil(aggregator.getStats(), "test slowLogAggregator failed")
+}
+
+func TestSlowLog_coversSlowLogTimeRange(t *testing.T) {
+	asst := assert.New(t)
+
+	windowStart := time.Date(2023, 1, 1, 10, 0, 0, 0, time.Local)
+	first := NewSlowLogIngestion(testMySQLServerID, windowStart.Add(time.Minute), windowStart.Add(DefaultSlowLogWindow+time.Minute), nil, 1)
+	// the second ingestion starts in the window next to the end window of the first one
+	second := NewSlowLogIngestion(testMySQLServerID, windowStart.Add(2*DefaultSlowLogWindow+time.Minute), windowStart.Add(3*DefaultSlowLogWindow), nil, 1)
+	// the third ingestion starts long after the end of the second one
+	third := NewSlowLogIngestion(testMySQLServerID, windowStart.Add(6*DefaultSlowLogWindow), windowStart.Add(7*DefaultSlowLogWindow), nil, 1)
+	ingestions := []query.SlowLogIngestion{first, second, third}
+
+	asst.False(coversSlowLogTimeRange(nil, windowStart, windowStart.Add(DefaultSlowLogWindow)), "test coversSlowLogTimeRange() failed")
+	asst.True(coversSlowLogTimeRange(ingestions, windowStart, windowStart.Add(4*DefaultSlowLogWindow)), "test coversSlowLogTimeRange() failed")
+	asst.True(coversSlowLogTimeRange(ingestions, windowStart.Add(6*DefaultSlowLogWindow), windowStart.Add(8*DefaultSlowLogWindow)), "test coversSlowLogTimeRange() failed")
+	// the gap between the second and the third ingestions is not covered
+	asst.False(coversSlowLogTimeRange(ingestions, windowStart, windowStart.Add(8*DefaultSlowLogWindow)), "test coversSlowLogTimeRange() failed")
+	asst.False(coversSlowLogTimeRange(ingestions, windowStart.Add(4*DefaultSlowLogWindow), windowStart.Add(5*DefaultSlowLogWindow)), "test coversSlowLogTimeRange() failed")
+	// the range before the first ingestion is not covered
+	asst.False(coversSlowLogTimeRange(ingestions, windowStart.Add(-DefaultSlowLogWindow), windowStart.Add(DefaultSlowLogWindow)), "test coversSlowLogTimeRange() failed")
+}
+
+func TestSlowLog_parseSlowLogAttributes(t *testing.T) {
+	asst := assert.New(t)
+
+	attributes := parseSlowLogAttributes("# Query_time: 3.000125  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 100")
+	asst.Equal("3.000125", attributes[slowLogQueryTimeKey], "test parseSlowLogAttributes() failed")
+	asst.Equal("100", attributes[slowLogRowsExaminedKey], "test parseSlowLogAttributes() failed")
+	// empty schema
+	attributes = parseSlowLogAttributes("# Thread_id: 8  Schema:   Last_errno: 0  Killed: 0")
+	asst.Equal("", attributes[slowLogSchemaKey], "test parseSlowLogAttributes() failed")
+	asst.Equal("0", attributes["Last_errno"], "test parseSlowLogAttributes() failed")
+}
+
+func TestSlowLog_parseSlowLogTime(t *testing.T) {
+	asst := assert.New(t)
+
+	// mysql 5.7 and later
+	ts, err := parseSlowLogTime("2023-01-01T02:00:00.123456Z")
+	asst.Nil(err, common.CombineMessageWithError("test parseSlowLogTime() failed", err))
+	asst.Equal(int64(1672538400), ts.Unix(), "test parseSlowLogTime() failed")
+	// mysql 5.6 and earlier
+	ts, err = parseSlowLogTime("230101  9:00:00")
+	asst.Nil(err, common.CombineMessageWithError("test parseSlowLogTime() failed", err))
+	asst.Equal(time.Date(2023, 1, 1, 9, 0, 0, 0, time.Local), ts, "test parseSlowLogTime() failed")
+	// invalid time
+	_, err = parseSlowLogTime("invalid")
+	asst.NotNil(err, "test parseSlowLogTime() failed")
+}
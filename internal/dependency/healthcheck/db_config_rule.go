@@ -0,0 +1,84 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/das/internal/dependency/metadata"
+	"github.com/romberli/go-util/middleware"
+)
+
+type DBConfigRule interface {
+	// Identity returns the identity
+	Identity() int
+	// GetVariableName returns the variable name
+	GetVariableName() string
+	// GetComparator returns the comparator
+	GetComparator() string
+	// GetExpectedValue returns the expected value, it may contain placeholders
+	GetExpectedValue() string
+	// GetSeverity returns the severity
+	GetSeverity() int
+	// GetMinVersion returns the min mysql version, it is inclusive, empty means no limit
+	GetMinVersion() string
+	// GetMaxVersion returns the max mysql version, it is exclusive, empty means no limit
+	GetMaxVersion() string
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// IsApplicable returns if the rule is applicable to the mysql version
+	IsApplicable(mysqlVersion string) (bool, error)
+	// GetAdvice returns the advice of the rule for the mysql server, the placeholders of the expected value are replaced
+	GetAdvice(mysqlServer metadata.MySQLServer) string
+	// Check checks if the variable value of the mysql server matches the rule
+	Check(value string, mysqlServer metadata.MySQLServer) (bool, error)
+	// Set sets DBConfigRule with given fields, key is the field name and value is the relevant value of the key
+	Set(fields map[string]interface{}) error
+	// Delete sets DelFlag to 1
+	Delete()
+	// MarshalJSON marshals DBConfigRule to json string
+	MarshalJSON() ([]byte, error)
+	// MarshalJSONWithFields marshals only specified field of the DBConfigRule to json string
+	MarshalJSONWithFields(fields ...string) ([]byte, error)
+}
+
+type DBConfigRuleRepo interface {
+	// Execute executes given command and placeholders on the middleware
+	Execute(command string, args ...interface{}) (middleware.Result, error)
+	// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+	Transaction() (middleware.Transaction, error)
+	// GetAll gets all db config rules from the middleware
+	GetAll() ([]DBConfigRule, error)
+	// GetByID gets a db config rule by the identity from the middleware
+	GetByID(id int) (DBConfigRule, error)
+	// Create creates a db config rule in the middleware
+	Create(rule DBConfigRule) (DBConfigRule, error)
+	// Update updates the db config rule in the middleware
+	Update(rule DBConfigRule) error
+	// Delete deletes the db config rule in the middleware
+	Delete(id int) error
+}
+
+type DBConfigRuleService interface {
+	// GetDBConfigRules returns the db config rules of the service
+	GetDBConfigRules() []DBConfigRule
+	// GetAll gets all db config rules from the middleware
+	GetAll() error
+	// GetByID gets a db config rule of the given id from the middleware
+	GetByID(id int) error
+	// Create creates a db config rule in the middleware
+	Create(fields map[string]interface{}) error
+	// Update gets a db config rule of the given id from the middleware,
+	// and then updates its fields that was specified in fields argument,
+	// key is the filed name and value is the new field value,
+	// it saves the changes to the middleware
+	Update(id int, fields map[string]interface{}) error
+	// Delete deletes the db config rule of given id in the middleware
+	Delete(id int) error
+	// Marshal marshals DBConfigRuleService.DBConfigRules to json bytes
+	Marshal() ([]byte, error)
+	// MarshalWithFields marshals only specified fields of the DBConfigRuleService to json bytes
+	MarshalWithFields(fields ...string) ([]byte, error)
+}
@@ -0,0 +1,81 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/go-util/middleware"
+)
+
+type EngineConfigVersion interface {
+	// Identity returns the identity
+	Identity() int
+	// GetProfileID returns the scoring profile id, 0 means the global default engine config
+	GetProfileID() int
+	// GetVersion returns the config version
+	GetVersion() int
+	// GetItemConfigs returns the full item configs of the version
+	GetItemConfigs() []ItemConfig
+	// GetLoginName returns the login name of the user who made the change
+	GetLoginName() string
+	// GetDescription returns the description of the change
+	GetDescription() string
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// MarshalJSON marshals EngineConfigVersion to json string
+	MarshalJSON() ([]byte, error)
+}
+
+type EngineConfigRepo interface {
+	// Execute executes given command and placeholders on the middleware
+	Execute(command string, args ...interface{}) (middleware.Result, error)
+	// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+	Transaction() (middleware.Transaction, error)
+	// GetItemConfigs gets the item configs of the scoring profile from the middleware, ordered by the config identity
+	GetItemConfigs(profileID int) ([]ItemConfig, error)
+	// GetLatestVersion gets the latest config version of the scoring profile from the middleware,
+	// it returns 0 if the config was never changed through the api
+	GetLatestVersion(profileID int) (int, error)
+	// GetVersions gets all the config versions of the scoring profile from the middleware, ordered by the version descending
+	GetVersions(profileID int) ([]EngineConfigVersion, error)
+	// GetVersion gets the config version of the scoring profile from the middleware
+	GetVersion(profileID, version int) (EngineConfigVersion, error)
+	// Save replaces the item configs of the scoring profile and saves them as a new config version in the middleware as a transaction,
+	// it returns the new config version
+	Save(profileID int, itemConfigs []ItemConfig, loginName, description string) (int, error)
+	// Sync adds the item configs of the global default engine config which are missing in the scoring profiles with zero item weight,
+	// and saves the item configs of each scoring profile as a new config version if they differ from the latest config version
+	Sync() error
+}
+
+type EngineConfigService interface {
+	// GetItemConfigs returns the item configs of the service
+	GetItemConfigs() []ItemConfig
+	// GetVersion returns the current config version of the service
+	GetVersion() int
+	// GetVersions returns the config versions of the service
+	GetVersions() []EngineConfigVersion
+	// GetByProfileID gets the item configs and the current config version of the scoring profile from the middleware
+	GetByProfileID(profileID int) error
+	// GetVersionsByProfileID gets all the config versions of the scoring profile from the middleware
+	GetVersionsByProfileID(profileID int) error
+	// Update updates the given item configs of the scoring profile, the other item configs remain unchanged,
+	// the merged item configs must pass the engine config validation, and then they are saved as a new config version
+	Update(profileID int, itemConfigs []ItemConfig, loginName string) error
+	// Replace replaces all the item configs of the scoring profile with the given item configs,
+	// they must pass the engine config validation, and then they are saved as a new config version
+	Replace(profileID int, itemConfigs []ItemConfig, loginName string) error
+	// Rollback replaces the item configs of the scoring profile with the ones of the given config version,
+	// they are saved as a new config version
+	Rollback(profileID, version int, loginName string) error
+	// Sync syncs the item configs of all the scoring profiles with the global default engine config,
+	// the item configs changed by the upgrade are saved as new config versions
+	Sync() error
+	// Marshal marshals EngineConfigService to json bytes
+	Marshal() ([]byte, error)
+	// MarshalWithFields marshals only specified fields of the EngineConfigService to json bytes
+	MarshalWithFields(fields ...string) ([]byte, error)
+}
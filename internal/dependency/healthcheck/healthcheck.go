@@ -3,6 +3,7 @@ package healthcheck
 import (
 	"time"
 
+	"github.com/romberli/das/internal/dependency/metadata"
 	depquery "github.com/romberli/das/internal/dependency/query"
 	"github.com/romberli/go-util/middleware"
 )
@@ -14,20 +15,90 @@ type DASRepo interface {
 	Transaction() (middleware.Transaction, error)
 	// GetOperationHistories gets operation histories from the middleware
 	GetHealthCheckHistories(mysqlServerIDList []int, limit int) ([]OperationHistory, error)
-	// LoadEngineConfig loads engine config from the middleware
+	// GetOperationHistoryByID gets the operation history by the operation id from the middleware
+	GetOperationHistoryByID(operationID int) (OperationHistory, error)
+	// LoadEngineConfig loads the global default engine config from the middleware
 	LoadEngineConfig() (EngineConfig, error)
+	// LoadEngineConfigByProfileID loads the engine config of the scoring profile from the middleware,
+	// profile id 0 means the global default engine config
+	LoadEngineConfigByProfileID(profileID int) (EngineConfig, error)
+	// GetScoringProfileIDByMySQLClusterID returns the id of the most specific scoring profile of the mysql cluster,
+	// it returns 0 if no profile was assigned
+	GetScoringProfileIDByMySQLClusterID(mysqlClusterID int) (int, error)
+	// LoadEngineConfigWithVersionByProfileID loads the engine config of the scoring profile and its config version from the middleware,
+	// the returned config version always matches the returned engine config, it returns 0 as the config version if the config was never changed through the api
+	LoadEngineConfigWithVersionByProfileID(profileID int) (EngineConfig, int, error)
+	// LoadDBConfigRules loads all the db config rules from the middleware
+	LoadDBConfigRules() ([]DBConfigRule, error)
 	// GetResultByOperationID returns the result
 	GetResultByOperationID(operationID int) (Result, error)
-	// IsRunning returns if the healthcheck of given mysql server is still running
+	// GetResultsByMySQLServerIDs returns the results of given mysql servers which were saved between start time and end time,
+	// the results are ordered by the create time
+	GetResultsByMySQLServerIDs(mysqlServerIDList []int, startTime, endTime time.Time) ([]Result, error)
+	// IsRunning returns if the healthcheck of given mysql server is still running or queued
 	IsRunning(mysqlServerID int) (bool, error)
-	// InitOperation initiates the operation
-	InitOperation(userID, mysqlServerID int, startTime, endTime time.Time, step time.Duration) (int, error)
+	// InitOperation initiates the operation, scheduleID is 0 if the operation was triggered manually,
+	// clusterOperationID is 0 if the operation was not a part of a cluster check
+	InitOperation(userID, scheduleID, clusterOperationID, mysqlServerID int, startTime, endTime time.Time, step time.Duration) (int, error)
+	// UpdateOperationEngineConfig updates the id of the scoring profile and the engine config version which were used by the operation
+	UpdateOperationEngineConfig(operationID, profileID, configVersion int) error
 	// UpdateOperationStatus updates operation status
 	UpdateOperationStatus(operationID int, status int, message string) error
-	// SaveResult saves result into the middleware
+	// CompareAndUpdateOperationStatus updates operation status only if the current status is the expected status,
+	// it returns false if the current status is not the expected status
+	CompareAndUpdateOperationStatus(operationID int, expectedStatus, status int, message string) (bool, error)
+	// QueueOperation marks the operation as queued with given priority
+	QueueOperation(operationID, priority int) error
+	// StartQueuedOperation marks the queued operation as running,
+	// it returns false if the operation is not queued any more, e.g. it was cancelled or started by another process
+	StartQueuedOperation(operationID int) (bool, error)
+	// GetOperationHistoriesByStatus returns the operation histories with given status,
+	// the operation histories are ordered by the priority descending and then the operation id
+	GetOperationHistoriesByStatus(status int) ([]OperationHistory, error)
+	// InitClusterOperation initiates the cluster operation
+	InitClusterOperation(userID, mysqlClusterID int, startTime, endTime time.Time, step time.Duration) (int, error)
+	// UpdateClusterOperationStatus updates cluster operation status
+	UpdateClusterOperationStatus(clusterOperationID int, status int, message string) error
+	// GetClusterResultByClusterOperationID returns the cluster result
+	GetClusterResultByClusterOperationID(clusterOperationID int) (ClusterResult, error)
+	// SaveClusterResult saves cluster result into the middleware
+	SaveClusterResult(clusterResult ClusterResult) error
+	// GetMySQLClusterIDsByMiddlewareClusterID returns the ids of the mysql clusters which are linked to the middleware cluster
+	GetMySQLClusterIDsByMiddlewareClusterID(middlewareClusterID int) ([]int, error)
+	// IsMiddlewareRunning returns if the healthcheck of given middleware cluster is still running
+	IsMiddlewareRunning(middlewareClusterID int) (bool, error)
+	// InitMiddlewareOperation initiates the middleware operation
+	InitMiddlewareOperation(userID, middlewareClusterID int, startTime, endTime time.Time, step time.Duration) (int, error)
+	// UpdateMiddlewareOperationStatus updates middleware operation status
+	UpdateMiddlewareOperationStatus(middlewareOperationID int, status int, message string) error
+	// GetMiddlewareResultByMiddlewareOperationID returns the middleware result
+	GetMiddlewareResultByMiddlewareOperationID(middlewareOperationID int) (MiddlewareResult, error)
+	// SaveMiddlewareResult saves middleware result into the middleware
+	SaveMiddlewareResult(middlewareResult MiddlewareResult) error
+	// GetItemResultsByOperationID returns the results of all the check items of the operation
+	GetItemResultsByOperationID(operationID int) ([]ItemResult, error)
+	// SaveResult saves result and the item results into the middleware
 	SaveResult(result Result) error
+	// GetSnapshotByOperationID returns the snapshot of the inputs collected by the operation
+	GetSnapshotByOperationID(operationID int) (Snapshot, error)
+	// SaveSnapshot saves the snapshot of the inputs collected by the operation into the middleware
+	SaveSnapshot(operationID int, snapshot Snapshot) error
 	// UpdateAccuracyReviewByOperationID updates the accuracy review
 	UpdateAccuracyReviewByOperationID(operationID int, review int) error
+	// GetItemFeedbacksByOperationID returns the accuracy feedbacks of the check items of the operation
+	GetItemFeedbacksByOperationID(operationID int) ([]ItemFeedback, error)
+	// SaveItemFeedback saves the accuracy feedback of the check item into the middleware
+	SaveItemFeedback(itemFeedback ItemFeedback) error
+	// GetFeedbackStatsByTimeRange returns the aggregated accuracy feedbacks of the operations which were created between start time and end time
+	GetFeedbackStatsByTimeRange(startTime, endTime time.Time) ([]FeedbackStat, error)
+	// GetProgressByOperationID returns the latest progress of the operation
+	GetProgressByOperationID(operationID int) (Progress, error)
+	// SaveProgress saves the latest progress of the operation into the middleware
+	SaveProgress(progress Progress) error
+	// GetCallbackURLsByOperationID returns the callback urls of the operation
+	GetCallbackURLsByOperationID(operationID int) ([]string, error)
+	// SaveCallbackURLs saves the callback urls of the operation into the middleware
+	SaveCallbackURLs(operationID int, callbackURLs []string) error
 }
 
 type ApplicationMySQLRepo interface {
@@ -41,6 +112,38 @@ type ApplicationMySQLRepo interface {
 	GetLargeTables() ([]Table, error)
 	// GetDBName gets the db name of given table names
 	GetDBName(tableNames []string) (string, error)
+	// GetReplicationStatus gets the replication status of all the replication channels,
+	// it returns an empty slice if the mysql server is not a replica
+	GetReplicationStatus() ([]ReplicationStatus, error)
+	// GetGTIDExecuted gets the executed gtid set
+	GetGTIDExecuted() (string, error)
+	// GetMasterGTIDExecuted gets the executed gtid set of the master with given host ip and port number
+	GetMasterGTIDExecuted(hostIP string, portNum int) (string, error)
+	// GetGTIDSubtract returns the gtids of the first gtid set which are not in the second gtid set
+	GetGTIDSubtract(gtidSet1, gtidSet2 string) (string, error)
+	// GetGroupMembers gets the members of the group replication,
+	// it returns an empty slice if the mysql server is not a member of group replication
+	GetGroupMembers() ([]GroupMember, error)
+	// GetLongTransactions gets the running innodb transactions, the longest one comes first
+	GetLongTransactions() ([]InnoDBTransaction, error)
+	// GetHistoryListLength gets the history list length of the innodb undo logs
+	GetHistoryListLength() (int, error)
+	// GetLockWaits gets the innodb transactions which are waiting for the locks and the transactions which block them
+	GetLockWaits() ([]InnoDBLockWait, error)
+	// GetLatestDeadlock gets the latest deadlock detected by innodb since the mysql server started,
+	// it returns an empty deadlock of which the detected time is zero if there was no deadlock
+	GetLatestDeadlock() (InnoDBDeadlock, error)
+	// GetTableSchemas gets the engines and the primary key states of the tables which are not in the ignored dbs
+	GetTableSchemas() ([]TableSchema, error)
+	// GetIndexStatistics gets the index statistics of the tables which are not in the ignored dbs,
+	// the columns of an index are ordered by the sequence in the index
+	GetIndexStatistics() ([]metadata.IndexStatistic, error)
+	// GetAutoIncrements gets the auto increment columns and the next auto increment values of the tables which are not in the ignored dbs
+	GetAutoIncrements() ([]AutoIncrement, error)
+	// GetUsers gets the accounts of the mysql server, the password hashes are not returned
+	GetUsers() ([]MySQLUser, error)
+	// GetGrants gets the grant statements of given account
+	GetGrants(user, host string) ([]string, error)
 }
 
 type PrometheusRepo interface {
@@ -56,6 +159,8 @@ type PrometheusRepo interface {
 	GetIOUtil() ([]PrometheusData, error)
 	// GetDiskCapacityUsage gets the disk capacity usage
 	GetDiskCapacityUsage(mountPoints []string) ([]PrometheusData, error)
+	// GetDiskCapacities gets the size and the used bytes series of the mount points
+	GetDiskCapacities(mountPoints []string) ([]DiskCapacity, error)
 	// GetConnectionUsage gets the connection usage
 	GetConnectionUsage() ([]PrometheusData, error)
 	// GetAverageActiveSessionPercents gets the average active session percents
@@ -64,6 +169,13 @@ type PrometheusRepo interface {
 	GetCacheMissRatio() ([]PrometheusData, error)
 }
 
+type MiddlewarePrometheusRepo interface {
+	// GetCPUUsage gets the cpu usage of the middleware server with given server name
+	GetCPUUsage(serverName string) ([]PrometheusData, error)
+	// GetMemoryUsage gets the memory usage of the middleware server with given server name
+	GetMemoryUsage(serverName string) ([]PrometheusData, error)
+}
+
 type QueryRepo interface {
 	// Close closes the mysql or clickhouse connection
 	Close() error
@@ -82,16 +194,83 @@ type Service interface {
 	GetOperationHistories() []OperationHistory
 	// GetResult returns the result
 	GetResult() Result
+	// GetClusterResult returns the cluster result
+	GetClusterResult() ClusterResult
+	// GetTrendPoints returns the score trend points
+	GetTrendPoints() []TrendPoint
+	// GetResultDiff returns the difference of two results
+	GetResultDiff() ResultDiff
+	// GetReport returns the report
+	GetReport() Report
+	// GetSnapshot returns the snapshot
+	GetSnapshot() Snapshot
+	// GetItemFeedbacks returns the accuracy feedbacks of the check items
+	GetItemFeedbacks() []ItemFeedback
+	// GetFeedbackStats returns the aggregated accuracy feedbacks
+	GetFeedbackStats() []FeedbackStat
+	// GetQueuedOperations returns the queued operations
+	GetQueuedOperations() []OperationHistory
+	// GetRunningOperations returns the running operations
+	GetRunningOperations() []OperationHistory
+	// GetProgress returns the progress of the operation
+	GetProgress() Progress
+	// GetCapacities returns the capacity forecasts
+	GetCapacities() []Capacity
+	// GetMiddlewareResult returns the middleware result
+	GetMiddlewareResult() MiddlewareResult
 	// GetOperationHistoriesByLoginName returns the operation histories by login name
 	GetOperationHistoriesByLoginName(loginName string) error
 	// GetResultByOperationID gets the result by operation id from the middleware
 	GetResultByOperationID(id int) error
-	// Check checks the server health status
-	Check(mysqlServerID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error)
-	// Check checks the server health status
-	CheckByHostInfo(hostIP string, portNum int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error)
+	// GetClusterResultByClusterOperationID gets the cluster result by cluster operation id from the middleware
+	GetClusterResultByClusterOperationID(id int) error
+	// GetMiddlewareResultByMiddlewareOperationID gets the middleware result by middleware operation id from the middleware
+	GetMiddlewareResultByMiddlewareOperationID(id int) error
+	// GetTrendByMySQLServerID gets the score trend of the mysql server between start time and end time
+	GetTrendByMySQLServerID(mysqlServerID int, startTime, endTime time.Time, loginName string) error
+	// GetTrendByMySQLClusterID gets the score trend of all the mysql servers of the mysql cluster between start time and end time
+	GetTrendByMySQLClusterID(mysqlClusterID int, startTime, endTime time.Time, loginName string) error
+	// GetCapacity gets the capacity forecasts of the mysql servers of given target with the results which were saved between start time and end time,
+	// the target could be a mysql server, a mysql cluster or a resource group
+	GetCapacity(targetType, targetID int, startTime, endTime time.Time, loginName string) error
+	// CompareResults compares the target result with the base result by the operation ids
+	CompareResults(baseOperationID, targetOperationID int) error
+	// GenerateReport generates the report of the operation
+	GenerateReport(operationID int, loginName string) error
+	// GetSnapshotByOperationID gets the snapshot of the inputs collected by the operation from the middleware
+	GetSnapshotByOperationID(operationID int, loginName string) error
+	// Check checks the server health status, the callback urls will be notified when the operation stops
+	Check(mysqlServerID int, startTime, endTime time.Time, step time.Duration, loginName string, callbackURLs []string) (int, error)
+	// CheckWithSchedule checks the server health status, it is used by the scheduler
+	CheckWithSchedule(scheduleID, mysqlServerID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error)
+	// CheckCluster checks the health status of all the mysql servers of the mysql cluster
+	CheckCluster(mysqlClusterID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error)
+	// CheckMiddlewareCluster checks the health status of all the middleware servers of the middleware cluster
+	// and the endpoints of the mysql clusters which are linked to the middleware cluster
+	CheckMiddlewareCluster(middlewareClusterID int, startTime, endTime time.Time, step time.Duration, loginName string) (int, error)
+	// CheckByHostInfo checks the server health status, the callback urls will be notified when the operation stops
+	CheckByHostInfo(hostIP string, portNum int, startTime, endTime time.Time, step time.Duration, loginName string, callbackURLs []string) (int, error)
+	// CheckWithSnapshot checks the server health status with the inputs of the snapshot instead of the live data sources
+	CheckWithSnapshot(snapshot []byte, loginName string) (int, error)
+	// Cancel cancels the running or queued operation with given operation id
+	Cancel(operationID int, loginName string) error
+	// GetOperationQueue gets the queued and the running operations
+	GetOperationQueue() error
+	// RecoverQueuedOperations puts the queued operations into the queue of the executor again after the process restarts
+	RecoverQueuedOperations() error
 	// ReviewAccuracy reviews the accuracy of the check
 	ReviewAccuracy(id, review int) error
+	// ReviewItemAccuracy reviews the accuracy of a check item of the operation
+	ReviewItemAccuracy(operationID int, itemName string, accuracy int, comment, loginName string) error
+	// GetItemFeedbacksByOperationID gets the accuracy feedbacks of the check items of the operation
+	GetItemFeedbacksByOperationID(operationID int) error
+	// GetFeedbackStatsByTimeRange gets the aggregated accuracy feedbacks of the operations which were created between start time and end time
+	GetFeedbackStatsByTimeRange(startTime, endTime time.Time) error
+	// GetProgressByOperationID gets the latest progress of the operation
+	GetProgressByOperationID(operationID int, loginName string) error
+	// SubscribeProgress subscribes the progress events of the operation which is running in this process,
+	// the returned function must be called to unsubscribe when the events are not needed any more
+	SubscribeProgress(operationID int) (<-chan ProgressEvent, func())
 	// Marshal marshals Service to json string
 	Marshal() ([]byte, error)
 	// MarshalWithFields marshals only specified field of the Service to json string
@@ -104,3 +283,29 @@ type Engine interface {
 	// Run checks the server health status
 	Run()
 }
+
+type CheckEnv interface {
+	// GetOperationInfo returns the operation information
+	GetOperationInfo() OperationInfo
+	// GetItemConfig returns the config of given item
+	GetItemConfig(item string) ItemConfig
+	// GetDBConfigRules returns the db config rules which are applicable to the mysql version of the mysql server
+	GetDBConfigRules() []DBConfigRule
+	// GetMountPoints returns the mount points of the mysql directories
+	GetMountPoints() []string
+	// GetApplicationMySQLRepo returns the application mysql repository
+	GetApplicationMySQLRepo() ApplicationMySQLRepo
+	// GetPrometheusRepo returns the prometheus repository
+	GetPrometheusRepo() PrometheusRepo
+	// GetQueryRepo returns the query repository
+	GetQueryRepo() QueryRepo
+}
+
+type CheckItem interface {
+	// GetName returns the item name, it must be the same as the item name in the engine config
+	GetName() string
+	// GetDataSource returns the data source of the item
+	GetDataSource() string
+	// Check collects the data of the item from the data source of the check env, and scores it with the item config
+	Check(env CheckEnv) (ItemResult, error)
+}
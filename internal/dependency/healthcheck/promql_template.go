@@ -0,0 +1,76 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/go-util/middleware"
+)
+
+type PromQLTemplate interface {
+	// Identity returns the identity
+	Identity() int
+	// GetMonitorSystemID returns the monitor system id
+	GetMonitorSystemID() int
+	// GetTemplateName returns the template name
+	GetTemplateName() string
+	// GetTemplateText returns the template text
+	GetTemplateText() string
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// Set sets PromQLTemplate with given fields, key is the field name and value is the relevant value of the key
+	Set(fields map[string]interface{}) error
+	// Delete sets DelFlag to 1
+	Delete()
+	// MarshalJSON marshals PromQLTemplate to json string
+	MarshalJSON() ([]byte, error)
+	// MarshalJSONWithFields marshals only specified field of the PromQLTemplate to json string
+	MarshalJSONWithFields(fields ...string) ([]byte, error)
+}
+
+type PromQLTemplateRepo interface {
+	// Execute executes given command and placeholders on the middleware
+	Execute(command string, args ...interface{}) (middleware.Result, error)
+	// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+	Transaction() (middleware.Transaction, error)
+	// GetAll gets all overridden promql templates from the middleware
+	GetAll() ([]PromQLTemplate, error)
+	// GetByMonitorSystemID gets the overridden promql templates of the monitor system from the middleware
+	GetByMonitorSystemID(monitorSystemID int) ([]PromQLTemplate, error)
+	// GetByName gets the overridden promql template of the monitor system by the template name from the middleware
+	GetByName(monitorSystemID int, templateName string) (PromQLTemplate, error)
+	// Create creates a promql template in the middleware
+	Create(promQLTemplate PromQLTemplate) (PromQLTemplate, error)
+	// Update updates the promql template in the middleware
+	Update(promQLTemplate PromQLTemplate) error
+	// Delete deletes the promql template in the middleware
+	Delete(id int) error
+}
+
+type PromQLTemplateService interface {
+	// GetPromQLTemplates returns the promql templates of the service
+	GetPromQLTemplates() []PromQLTemplate
+	// GetRenderedQuery returns the rendered query of the dry run
+	GetRenderedQuery() string
+	// GetDatas returns the prometheus datas of the dry run
+	GetDatas() []PrometheusData
+	// GetAll gets all overridden promql templates from the middleware
+	GetAll() error
+	// GetByMonitorSystemID gets the overridden promql templates of the monitor system from the middleware
+	GetByMonitorSystemID(monitorSystemID int) error
+	// Save creates the promql template of the monitor system, or updates it if it exists
+	Save(monitorSystemID int, templateName, templateText string) error
+	// Delete deletes the promql template of the monitor system, the built-in template will be used after that
+	Delete(monitorSystemID int, templateName string) error
+	// DryRun renders the promql template with the variables of the mysql server and executes it on the monitor system,
+	// if templateText is empty, the overridden template or the built-in template of the monitor system will be used
+	DryRun(monitorSystemID, mysqlServerID int, templateName, templateText string, mountPoints []string,
+		startTime, endTime time.Time, step time.Duration) error
+	// Marshal marshals PromQLTemplateService.PromQLTemplates to json bytes
+	Marshal() ([]byte, error)
+	// MarshalWithFields marshals only specified fields of the PromQLTemplateService to json bytes
+	MarshalWithFields(fields ...string) ([]byte, error)
+}
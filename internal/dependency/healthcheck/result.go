@@ -89,6 +89,8 @@ type Result interface {
 	GetSlowQueryAdvice() string
 	// GetAccuracyReview returns the accuracy review
 	GetAccuracyReview() int
+	// GetItemResults returns the results of all the check items
+	GetItemResults() []ItemResult
 	// GetDelFlag returns the delete flag
 	GetDelFlag() int
 	// GetCreateTime returns the create time
@@ -104,3 +106,248 @@ type Result interface {
 	// MarshalJSON marshals only specified field of the Result to json string
 	MarshalJSONWithFields(fields ...string) ([]byte, error)
 }
+
+type ClusterResult interface {
+	// Identity returns the identity
+	Identity() int
+	// GetClusterOperationID returns the cluster operation id
+	GetClusterOperationID() int
+	// GetMySQLClusterID returns the mysql cluster id
+	GetMySQLClusterID() int
+	// GetWeightedAverageScore returns the lowest weighted average score of the mysql servers
+	GetWeightedAverageScore() int
+	// GetNodeResult returns the result summary of each mysql server in json string
+	GetNodeResult() string
+	// GetConfigDrift returns the variables that differ between the master and the replicas in json string
+	GetConfigDrift() string
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// MarshalJSON marshals ClusterResult to json string
+	MarshalJSON() ([]byte, error)
+	// MarshalJSONWithFields marshals only specified field of the ClusterResult to json string
+	MarshalJSONWithFields(fields ...string) ([]byte, error)
+}
+
+type MiddlewareResult interface {
+	// Identity returns the identity
+	Identity() int
+	// GetMiddlewareOperationID returns the middleware operation id
+	GetMiddlewareOperationID() int
+	// GetMiddlewareClusterID returns the middleware cluster id
+	GetMiddlewareClusterID() int
+	// GetScore returns the score of the middleware cluster
+	GetScore() int
+	// GetServerResult returns the liveness and the resource usages of each middleware server in json string
+	GetServerResult() string
+	// GetEndpointResult returns the alive endpoints of each linked mysql cluster in json string
+	GetEndpointResult() string
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// MarshalJSON marshals MiddlewareResult to json string
+	MarshalJSON() ([]byte, error)
+	// MarshalJSONWithFields marshals only specified field of the MiddlewareResult to json string
+	MarshalJSONWithFields(fields ...string) ([]byte, error)
+}
+
+type ItemResult interface {
+	// Identity returns the identity
+	Identity() int
+	// GetOperationID returns the operation id
+	GetOperationID() int
+	// GetItemName returns the item name
+	GetItemName() string
+	// GetDataSource returns the data source of the item
+	GetDataSource() string
+	// GetItemWeight returns the item weight that was used when checking
+	GetItemWeight() int
+	// GetScore returns the score of the item
+	GetScore() int
+	// GetStatus returns the status of the item, 0-ok, 1-skipped, 2-error
+	GetStatus() int
+	// GetMessage returns the reason why the item was skipped or failed
+	GetMessage() string
+	// IsOK returns if the item was checked successfully
+	IsOK() bool
+	// GetData returns the data of the item
+	GetData() string
+	// GetHigh returns the abnormal data of the item
+	GetHigh() string
+	// GetAdvice returns the advice of the item
+	GetAdvice() string
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// MarshalJSON marshals ItemResult to json string
+	MarshalJSON() ([]byte, error)
+	// MarshalJSONWithFields marshals only specified field of the ItemResult to json string
+	MarshalJSONWithFields(fields ...string) ([]byte, error)
+}
+
+type TrendPoint interface {
+	// GetOperationID returns the operation id
+	GetOperationID() int
+	// GetHostIP returns the host ip
+	GetHostIP() string
+	// GetPortNum returns the port number
+	GetPortNum() int
+	// GetWeightedAverageScore returns the weighted average score
+	GetWeightedAverageScore() int
+	// GetItemScores returns the scores of the items which were checked successfully
+	GetItemScores() map[string]int
+	// GetCheckTime returns the time when the result was saved
+	GetCheckTime() time.Time
+}
+
+type ResultDiff interface {
+	// GetBaseOperationID returns the operation id of the base result
+	GetBaseOperationID() int
+	// GetTargetOperationID returns the operation id of the target result
+	GetTargetOperationID() int
+	// GetWeightedAverageScoreDelta returns the weighted average score of the target result minus the base result
+	GetWeightedAverageScoreDelta() int
+	// MarshalJSON marshals ResultDiff to json string
+	MarshalJSON() ([]byte, error)
+}
+
+type Report interface {
+	// GetOperationInfo returns the operation information
+	GetOperationInfo() OperationInfo
+	// GetResult returns the result
+	GetResult() Result
+	// HTML renders the report as a html document
+	HTML() (string, error)
+	// Markdown renders the report as a markdown document
+	Markdown() (string, error)
+	// Render renders the report with given format, the format could be html or markdown
+	Render(format string) (string, error)
+}
+
+type Snapshot interface {
+	// GetVersion returns the version of the snapshot format
+	GetVersion() int
+	// GetOperationID returns the operation id of the live run which collected the snapshot
+	GetOperationID() int
+	// GetHostIP returns the host ip of the mysql server
+	GetHostIP() string
+	// GetPortNum returns the port number of the mysql server
+	GetPortNum() int
+	// GetStartTime returns the start time of the check range
+	GetStartTime() time.Time
+	// GetEndTime returns the end time of the check range
+	GetEndTime() time.Time
+	// GetStep returns the step of the check range
+	GetStep() time.Duration
+	// Marshal marshals Snapshot to json bytes
+	Marshal() ([]byte, error)
+}
+
+type ItemFeedback interface {
+	// Identity returns the identity
+	Identity() int
+	// GetOperationID returns the operation id
+	GetOperationID() int
+	// GetItemName returns the item name
+	GetItemName() string
+	// GetAccuracy returns the accuracy, 1 means accurate, 2 means inaccurate
+	GetAccuracy() int
+	// GetComment returns the comment
+	GetComment() string
+	// GetLoginName returns the login name of the reviewer
+	GetLoginName() string
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+}
+
+type FeedbackStat interface {
+	// GetCheckDate returns the date when the checks were run
+	GetCheckDate() string
+	// GetItemName returns the item name
+	GetItemName() string
+	// GetEnvID returns the environment id of the mysql servers
+	GetEnvID() int
+	// GetProfileID returns the id of the scoring profile which was used by the checks, 0 means the global default engine config
+	GetProfileID() int
+	// GetConfigVersion returns the engine config version which was used by the checks
+	GetConfigVersion() int
+	// GetFeedbackCount returns the number of the feedbacks
+	GetFeedbackCount() int
+	// GetInaccurateCount returns the number of the inaccurate feedbacks
+	GetInaccurateCount() int
+	// GetInaccurateRate returns the inaccurate count divided by the feedback count
+	GetInaccurateRate() float64
+}
+
+type ProgressEvent interface {
+	// GetOperationID returns the operation id
+	GetOperationID() int
+	// GetEventType returns the event type, it is one of item_started, item_finished and completed
+	GetEventType() string
+	// GetItemName returns the name of the item which the event is about, it is empty for the completed event
+	GetItemName() string
+	// GetItemStatus returns the status of the finished item, 0-ok, 1-skipped, 2-error
+	GetItemStatus() int
+	// GetItemScore returns the score of the finished item
+	GetItemScore() int
+	// GetItemElapsed returns the elapsed time of the finished item, the unit is millisecond
+	GetItemElapsed() int
+	// GetFinishedItemNum returns the number of the finished items
+	GetFinishedItemNum() int
+	// GetTotalItemNum returns the number of all the items
+	GetTotalItemNum() int
+	// GetPercent returns the percentage of the finished items
+	GetPercent() int
+	// GetElapsed returns the elapsed time of the operation, the unit is millisecond
+	GetElapsed() int
+	// GetStatus returns the status of the operation
+	GetStatus() int
+	// GetEventTime returns the time when the event happened
+	GetEventTime() time.Time
+}
+
+type Progress interface {
+	// GetOperationID returns the operation id
+	GetOperationID() int
+	// GetStatus returns the status of the operation
+	GetStatus() int
+	// GetTotalItemNum returns the number of all the items, it is 0 if the operation has not started checking the items
+	GetTotalItemNum() int
+	// GetFinishedItemNum returns the number of the finished items
+	GetFinishedItemNum() int
+	// GetPercent returns the percentage of the finished items
+	GetPercent() int
+	// GetCurrentItemName returns the name of the item which is being checked
+	GetCurrentItemName() string
+	// GetElapsed returns the elapsed time of the operation, the unit is millisecond
+	GetElapsed() int
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// IsFinished returns if the operation has stopped, no matter it succeeded or not
+	IsFinished() bool
+}
+
+type Capacity interface {
+	// GetMySQLServerID returns the mysql server id
+	GetMySQLServerID() int
+	// GetOperationID returns the id of the latest operation which forecast the disk capacity
+	GetOperationID() int
+	// GetDaysToFull returns the least days before any mount point of the mysql server is full,
+	// it returns -1 if none of the mount points is growing
+	GetDaysToFull() float64
+	// GetCheckTime returns the time when the latest result was saved
+	GetCheckTime() time.Time
+}
@@ -0,0 +1,109 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/go-util/middleware"
+)
+
+type RetentionPolicy interface {
+	// Identity returns the identity
+	Identity() int
+	// GetEnvID returns the env id
+	GetEnvID() int
+	// GetFullDataDays returns the days to keep the full data, only the scores will be kept after that
+	GetFullDataDays() int
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// Set sets RetentionPolicy with given fields, key is the field name and value is the relevant value of the key
+	Set(fields map[string]interface{}) error
+	// Delete sets DelFlag to 1
+	Delete()
+	// MarshalJSON marshals RetentionPolicy to json string
+	MarshalJSON() ([]byte, error)
+	// MarshalJSONWithFields marshals only specified field of the RetentionPolicy to json string
+	MarshalJSONWithFields(fields ...string) ([]byte, error)
+}
+
+type RetentionStat interface {
+	// GetEnvID returns the env id, it is 0 if the mysql server does not exist in the metadata any more
+	GetEnvID() int
+	// GetOperationNum returns the number of the expired operations
+	GetOperationNum() int
+	// GetDeletedOperationNum returns the number of the expired operations which have no result, they will be removed only if purging is enabled
+	GetDeletedOperationNum() int
+	// GetResultNum returns the number of the results of which the data will be cleared
+	GetResultNum() int
+	// GetItemResultNum returns the number of the item results of which the data will be cleared
+	GetItemResultNum() int
+	// GetSnapshotNum returns the number of the snapshots which will be removed
+	GetSnapshotNum() int
+	// GetBytes returns the bytes of the data which will be cleared or removed
+	GetBytes() int64
+}
+
+type RetentionRepo interface {
+	// Execute executes given command and placeholders on the middleware
+	Execute(command string, args ...interface{}) (middleware.Result, error)
+	// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+	Transaction() (middleware.Transaction, error)
+	// GetAll gets all retention policies from the middleware
+	GetAll() ([]RetentionPolicy, error)
+	// GetByEnvID gets the retention policy of the env from the middleware
+	GetByEnvID(envID int) (RetentionPolicy, error)
+	// Create creates a retention policy in the middleware
+	Create(policy RetentionPolicy) (RetentionPolicy, error)
+	// Update updates the retention policy in the middleware
+	Update(policy RetentionPolicy) error
+	// Delete deletes the retention policy in the middleware
+	Delete(id int) error
+	// GetExpiredOperationIDs gets the ids of the operations of which the full data expired,
+	// defaultFullDataDays is used for the envs which have no retention policy, at most limit ids will be returned
+	GetExpiredOperationIDs(defaultFullDataDays, limit int) ([]int, error)
+	// GetRetentionStats gets the statistics of the expired data of each env from the middleware
+	GetRetentionStats(defaultFullDataDays int) ([]RetentionStat, error)
+	// GetExpiredMiddlewareOperationIDs gets the ids of the middleware operations of which the full data expired,
+	// defaultFullDataDays is used for the envs which have no retention policy, at most limit ids will be returned
+	GetExpiredMiddlewareOperationIDs(defaultFullDataDays, limit int) ([]int, error)
+	// GetPurgeableOperationIDs gets the ids of the archived operations which have no result, at most limit ids will be returned
+	GetPurgeableOperationIDs(limit int) ([]int, error)
+	// Archive clears the data of the operations and their cluster results, only the scores will be kept,
+	// the operation histories and the feedbacks are always kept, the operations are marked as archived
+	Archive(operationIDs []int) error
+	// Purge removes the archived operations which have no result, together with their item results and feedbacks
+	Purge(operationIDs []int) error
+	// ArchiveMiddleware clears the server and endpoint data of the middleware operations, only the scores will be kept
+	ArchiveMiddleware(middlewareOperationIDs []int) error
+}
+
+type RetentionService interface {
+	// GetRetentionPolicies returns the retention policies of the service
+	GetRetentionPolicies() []RetentionPolicy
+	// GetRetentionStats returns the retention statistics of the service
+	GetRetentionStats() []RetentionStat
+	// GetAll gets all retention policies from the middleware
+	GetAll() error
+	// GetByEnvID gets the retention policy of the env from the middleware
+	GetByEnvID(envID int) error
+	// Save creates the retention policy of the env, or updates it if it exists
+	Save(envID, fullDataDays int) error
+	// DeleteByEnvID deletes the retention policy of the env, the env will use the default full data days
+	DeleteByEnvID(envID int) error
+	// DryRun gets the statistics of the data which would be archived by the archiver
+	DryRun() error
+	// Marshal marshals RetentionService.RetentionPolicies to json bytes
+	Marshal() ([]byte, error)
+	// MarshalWithFields marshals only specified fields of the RetentionService to json bytes
+	MarshalWithFields(fields ...string) ([]byte, error)
+}
+
+type Archiver interface {
+	// Start starts the archiver in the background
+	Start()
+	// Stop stops the archiver
+	Stop()
+}
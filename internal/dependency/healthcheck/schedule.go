@@ -0,0 +1,96 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/go-util/middleware"
+)
+
+type Schedule interface {
+	// Identity returns the identity
+	Identity() int
+	// GetScheduleName returns the schedule name
+	GetScheduleName() string
+	// GetCronExpression returns the cron expression
+	GetCronExpression() string
+	// GetTargetType returns the target type
+	GetTargetType() int
+	// GetTargetID returns the target id
+	GetTargetID() int
+	// GetLookback returns the lookback, the unit is seconds
+	GetLookback() int
+	// GetStep returns the step, the unit is seconds
+	GetStep() int
+	// GetLoginName returns the login name of the user who runs the healthcheck
+	GetLoginName() string
+	// GetEnabled returns the enabled flag
+	GetEnabled() int
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// IsEnabled returns if the schedule is enabled
+	IsEnabled() bool
+	// Set sets Schedule with given fields, key is the field name and value is the relevant value of the key
+	Set(fields map[string]interface{}) error
+	// Delete sets DelFlag to 1
+	Delete()
+	// MarshalJSON marshals Schedule to json string
+	MarshalJSON() ([]byte, error)
+	// MarshalJSONWithFields marshals only specified field of the Schedule to json string
+	MarshalJSONWithFields(fields ...string) ([]byte, error)
+}
+
+type ScheduleRepo interface {
+	// Execute executes given command and placeholders on the middleware
+	Execute(command string, args ...interface{}) (middleware.Result, error)
+	// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+	Transaction() (middleware.Transaction, error)
+	// GetAll gets all schedules from the middleware
+	GetAll() ([]Schedule, error)
+	// GetEnabled gets all enabled schedules from the middleware
+	GetEnabled() ([]Schedule, error)
+	// GetByID gets a schedule by the identity from the middleware
+	GetByID(id int) (Schedule, error)
+	// GetID gets the identity with given schedule name from the middleware
+	GetID(scheduleName string) (int, error)
+	// Create creates a schedule in the middleware
+	Create(schedule Schedule) (Schedule, error)
+	// Update updates the schedule in the middleware
+	Update(schedule Schedule) error
+	// Delete deletes the schedule in the middleware
+	Delete(id int) error
+	// Claim claims the trigger of the schedule at given time, it returns false if the trigger was claimed by another das instance
+	Claim(id int, triggerTime time.Time) (bool, error)
+}
+
+type ScheduleService interface {
+	// GetSchedules returns the schedules of the service
+	GetSchedules() []Schedule
+	// GetAll gets all schedules from the middleware
+	GetAll() error
+	// GetByID gets a schedule of the given id from the middleware
+	GetByID(id int) error
+	// Create creates a schedule in the middleware
+	Create(fields map[string]interface{}) error
+	// Update gets a schedule of the given id from the middleware,
+	// and then updates its fields that was specified in fields argument,
+	// key is the filed name and value is the new field value,
+	// it saves the changes to the middleware
+	Update(id int, fields map[string]interface{}) error
+	// Delete deletes the schedule of given id in the middleware
+	Delete(id int) error
+	// Marshal marshals ScheduleService.Schedules to json bytes
+	Marshal() ([]byte, error)
+	// MarshalWithFields marshals only specified fields of the ScheduleService to json bytes
+	MarshalWithFields(fields ...string) ([]byte, error)
+}
+
+type Scheduler interface {
+	// Start starts the scheduler in the background
+	Start()
+	// Stop stops the scheduler
+	Stop()
+}
@@ -0,0 +1,88 @@
+package healthcheck
+
+import (
+	"time"
+
+	"github.com/romberli/go-util/middleware"
+)
+
+type ScoringProfile interface {
+	// Identity returns the identity
+	Identity() int
+	// GetProfileName returns the profile name
+	GetProfileName() string
+	// GetDescription returns the description
+	GetDescription() string
+	// GetItemConfigs returns the item configs of the profile
+	GetItemConfigs() []ItemConfig
+	// GetAssignments returns the assignments of the profile
+	GetAssignments() []ScoringProfileAssignment
+	// GetDelFlag returns the delete flag
+	GetDelFlag() int
+	// GetCreateTime returns the create time
+	GetCreateTime() time.Time
+	// GetLastUpdateTime returns the last update time
+	GetLastUpdateTime() time.Time
+	// GetEngineConfig returns the engine config which consists of the item configs of the profile
+	GetEngineConfig() EngineConfig
+	// MarshalJSON marshals ScoringProfile to json string
+	MarshalJSON() ([]byte, error)
+}
+
+type ScoringProfileAssignment interface {
+	// Identity returns the identity
+	Identity() int
+	// GetProfileID returns the profile id
+	GetProfileID() int
+	// GetTargetType returns the target type
+	GetTargetType() int
+	// GetTargetID returns the target id
+	GetTargetID() int
+}
+
+type ScoringProfileRepo interface {
+	// Execute executes given command and placeholders on the middleware
+	Execute(command string, args ...interface{}) (middleware.Result, error)
+	// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+	Transaction() (middleware.Transaction, error)
+	// GetAll gets all scoring profiles from the middleware
+	GetAll() ([]ScoringProfile, error)
+	// GetByID gets a scoring profile with its item configs and assignments by the identity from the middleware
+	GetByID(id int) (ScoringProfile, error)
+	// GetID gets the identity with given profile name from the middleware
+	GetID(profileName string) (int, error)
+	// Create creates a scoring profile with its item configs in the middleware
+	Create(profile ScoringProfile) (ScoringProfile, error)
+	// Update updates the scoring profile and replaces its item configs in the middleware
+	Update(profile ScoringProfile) error
+	// Delete deletes the scoring profile with its item configs and assignments in the middleware
+	Delete(id int) error
+	// Assign assigns the scoring profile to the target, the former assignment of the target will be replaced
+	Assign(profileID, targetType, targetID int) error
+	// Unassign removes the assignment of the target
+	Unassign(targetType, targetID int) error
+}
+
+type ScoringProfileService interface {
+	// GetScoringProfiles returns the scoring profiles of the service
+	GetScoringProfiles() []ScoringProfile
+	// GetAll gets all scoring profiles from the middleware
+	GetAll() error
+	// GetByID gets a scoring profile of the given id from the middleware
+	GetByID(id int) error
+	// Create creates a scoring profile in the middleware, the item configs must be a full item config set
+	Create(profileName, description string, itemConfigs []ItemConfig) error
+	// Update updates the scoring profile of the given id and replaces its item configs in the middleware,
+	// the item configs must be a full item config set
+	Update(id int, profileName, description string, itemConfigs []ItemConfig) error
+	// Delete deletes the scoring profile of given id in the middleware
+	Delete(id int) error
+	// Assign assigns the scoring profile of the given id to an env, a mysql cluster or a resource group
+	Assign(id, targetType, targetID int) error
+	// Unassign removes the scoring profile assignment of the env, the mysql cluster or the resource group
+	Unassign(targetType, targetID int) error
+	// Marshal marshals ScoringProfileService.ScoringProfiles to json bytes
+	Marshal() ([]byte, error)
+	// MarshalWithFields marshals only specified fields of the ScoringProfileService to json bytes
+	MarshalWithFields(fields ...string) ([]byte, error)
+}
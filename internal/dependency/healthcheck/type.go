@@ -34,6 +34,10 @@ type OperationHistory interface {
 	GetUserID() int
 	// GetAccountName returns the user account name
 	GetAccountName() string
+	// GetScheduleID returns the schedule id, 0 means the operation was triggered manually
+	GetScheduleID() int
+	// GetClusterOperationID returns the cluster operation id, 0 means the operation was not a part of a cluster check
+	GetClusterOperationID() int
 	// GetMySQLServerID returns the mysql server id
 	GetMySQLServerID() int
 	// GetHostIP returns the host ip of mysql server
@@ -46,6 +50,12 @@ type OperationHistory interface {
 	GetEndTime() time.Time
 	// GetStep returns the step, the unit is seconds
 	GetStep() int
+	// GetProfileID returns the id of the scoring profile which was used by the operation, 0 means the global default engine config
+	GetProfileID() int
+	// GetConfigVersion returns the engine config version which was used by the operation, 0 means the config was never changed through the api
+	GetConfigVersion() int
+	// GetPriority returns the priority of the operation in the queue, the operation with higher priority runs first
+	GetPriority() int
 	// GetStatus returns the status
 	GetStatus() int
 	// GetMessage returns the message
@@ -88,6 +98,8 @@ type ItemConfig interface {
 }
 
 type EngineConfig interface {
+	// GetItemNames returns the names of all the configured items, ordered by the config identity
+	GetItemNames() []string
 	// GetItemConfig returns the item config
 	GetItemConfig(item string) ItemConfig
 	// SetItemConfig sets item config with given item and config
@@ -114,11 +126,153 @@ type Table interface {
 	GetSize() float64
 }
 
+type ReplicationStatus interface {
+	// GetChannelName returns the replication channel name
+	GetChannelName() string
+	// GetMasterHost returns the host of the master
+	GetMasterHost() string
+	// GetMasterPort returns the port of the master
+	GetMasterPort() int
+	// IsIORunning returns if the io thread is running
+	IsIORunning() bool
+	// IsSQLRunning returns if the sql thread is running
+	IsSQLRunning() bool
+	// GetSecondsBehindMaster returns the replication lag, it returns -1 if the lag is unknown
+	GetSecondsBehindMaster() int
+	// GetLastIOError returns the last error of the io thread
+	GetLastIOError() string
+	// GetLastSQLError returns the last error of the sql thread
+	GetLastSQLError() string
+}
+
+type GroupMember interface {
+	// GetHostIP returns the host ip of the member
+	GetHostIP() string
+	// GetPortNum returns the port number of the member
+	GetPortNum() int
+	// GetState returns the state of the member
+	GetState() string
+}
+
+type InnoDBTransaction interface {
+	// GetTrxID returns the transaction id
+	GetTrxID() string
+	// GetState returns the state of the transaction
+	GetState() string
+	// GetStartTime returns the time when the transaction started
+	GetStartTime() time.Time
+	// GetDuration returns how long the transaction has been running, the unit is second
+	GetDuration() int
+	// GetThreadID returns the processlist id of the thread which runs the transaction
+	GetThreadID() int
+	// GetUser returns the user of the thread
+	GetUser() string
+	// GetHost returns the client host of the thread
+	GetHost() string
+	// GetDBName returns the default db name of the thread
+	GetDBName() string
+	// GetRowsLocked returns the approximate number of the rows locked by the transaction
+	GetRowsLocked() int
+	// GetRowsModified returns the number of the rows modified by the transaction
+	GetRowsModified() int
+	// GetQuery returns the sql which is being executed by the transaction, it is empty if the transaction is idle
+	GetQuery() string
+}
+
+type InnoDBLockWait interface {
+	// GetWaitingTrxID returns the id of the transaction which is waiting for the lock
+	GetWaitingTrxID() string
+	// GetWaitingThreadID returns the processlist id of the waiting thread
+	GetWaitingThreadID() int
+	// GetWaitingQuery returns the sql which is waiting for the lock
+	GetWaitingQuery() string
+	// GetBlockingTrxID returns the id of the transaction which holds the lock
+	GetBlockingTrxID() string
+	// GetBlockingThreadID returns the processlist id of the blocking thread
+	GetBlockingThreadID() int
+	// GetBlockingQuery returns the sql which is being executed by the blocking transaction, it is empty if the transaction is idle
+	GetBlockingQuery() string
+	// GetWaitAge returns how long the transaction has been waiting for the lock, the unit is second
+	GetWaitAge() int
+	// GetLockedTable returns the table which the lock is on
+	GetLockedTable() string
+}
+
+type InnoDBDeadlockTransaction interface {
+	// GetTrxNum returns the number of the transaction in the deadlock, it starts from 1
+	GetTrxNum() int
+	// GetTrxID returns the transaction id
+	GetTrxID() string
+	// GetThreadID returns the processlist id of the thread which ran the transaction
+	GetThreadID() int
+	// GetQuery returns the sql which was executed by the transaction when the deadlock was detected
+	GetQuery() string
+}
+
+type InnoDBDeadlock interface {
+	// GetDetectedTime returns the time when the deadlock was detected, it is zero if no deadlock was detected
+	GetDetectedTime() time.Time
+	// GetTransactions returns the transactions which were involved in the deadlock
+	GetTransactions() []InnoDBDeadlockTransaction
+	// GetRolledBackTrxNum returns the number of the transaction which was rolled back
+	GetRolledBackTrxNum() int
+}
+
+type TableSchema interface {
+	// GetDBName returns the db name
+	GetDBName() string
+	// GetTableName returns the table name
+	GetTableName() string
+	// GetEngine returns the storage engine of the table
+	GetEngine() string
+	// HasPrimaryKey returns if the table has a primary key
+	HasPrimaryKey() bool
+}
+
+type AutoIncrement interface {
+	// GetDBName returns the db name
+	GetDBName() string
+	// GetTableName returns the table name
+	GetTableName() string
+	// GetColumnName returns the name of the auto increment column
+	GetColumnName() string
+	// GetDataType returns the data type of the column, e.g. int
+	GetDataType() string
+	// GetColumnType returns the full column type, e.g. int(10) unsigned
+	GetColumnType() string
+	// GetAutoIncrement returns the next auto increment value of the table
+	GetAutoIncrement() float64
+}
+
+type MySQLUser interface {
+	// GetUser returns the user name of the account, it is empty if the account is anonymous
+	GetUser() string
+	// GetHost returns the host of the account
+	GetHost() string
+	// GetPlugin returns the authentication plugin of the account
+	GetPlugin() string
+	// HasEmptyPassword returns if the account uses a password based plugin but has no password
+	HasEmptyPassword() bool
+	// HasOldPassword returns if the password of the account is in the old pre-4.1 format
+	HasOldPassword() bool
+	// IsLocked returns if the account is locked
+	IsLocked() bool
+}
+
 type FileSystem interface {
 	GetMountPoint() string
 	GetDevice() string
 }
 
+type DiskCapacity interface {
+	// GetMountPoint returns the mount point
+	GetMountPoint() string
+	// GetSize returns the latest size of the mount point, the unit is byte
+	GetSize() float64
+	// GetUsedDatas returns the used bytes series of the mount point
+	GetUsedDatas() []PrometheusData
+}
+
 type PrometheusData interface {
 	// GetTimestamp returns the timestamp
 	GetTimestamp() string
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"io"
 	"time"
 
 	"github.com/romberli/go-util/middleware"
@@ -68,6 +69,67 @@ type MonitorRepo interface {
 	GetBySQLID(serviceName, sqlID string) (Query, error)
 }
 
+type SlowLogStat interface {
+	// GetSQLID returns the sql identity
+	GetSQLID() string
+	// GetFingerprint returns the fingerprint
+	GetFingerprint() string
+	// GetExample returns the example
+	GetExample() string
+	// GetDBName returns the db name
+	GetDBName() string
+	// GetWindowStart returns the start time of the statistic window
+	GetWindowStart() time.Time
+	// GetExecCount returns the execution count
+	GetExecCount() int
+	// GetTotalExecTime returns the total execution time
+	GetTotalExecTime() float64
+	// GetRowsExaminedMax returns the maximum row examined
+	GetRowsExaminedMax() int
+}
+
+type SlowLogIngestion interface {
+	// GetMySQLServerID returns the mysql server identity
+	GetMySQLServerID() int
+	// GetStartTime returns the time of the first entry of the ingested slow log file
+	GetStartTime() time.Time
+	// GetEndTime returns the time of the last ingested slow log entry
+	GetEndTime() time.Time
+	// GetEndKeys returns the keys of the ingested slow log entries of the end time
+	GetEndKeys() []string
+	// GetEntryCount returns the number of the ingested slow log entries
+	GetEntryCount() int
+}
+
+type SlowLogRepo interface {
+	MonitorRepo
+	// Execute executes given command and placeholders on the middleware
+	Execute(command string, args ...interface{}) (middleware.Result, error)
+	// Transaction returns a middleware.Transaction that could execute multiple commands as a transaction
+	Transaction() (middleware.Transaction, error)
+	// GetIngestedRange gets the time range of all the ingested slow log entries of the mysql server from the middleware,
+	// it returns nil if the slow log of the mysql server was never ingested
+	GetIngestedRange(mysqlServerID int) (SlowLogIngestion, error)
+	// Covers checks if the ingested slow log of the mysql server covers the whole time range
+	Covers(mysqlServerID int, startTime, endTime time.Time) (bool, error)
+	// Save saves the ingestion and the slow log statistics of the ingested entries into the middleware,
+	// ingested is the ingested slow log which the entries were filtered with
+	Save(ingested, ingestion SlowLogIngestion, stats []SlowLogStat) error
+}
+
+type SlowLogService interface {
+	// GetEntryCount returns the number of the parsed slow log entries
+	GetEntryCount() int
+	// GetSkippedCount returns the number of the parsed slow log entries which were ingested before
+	GetSkippedCount() int
+	// GetStatCount returns the number of the saved slow log statistics
+	GetStatCount() int
+	// Ingest parses the slow log from the reader and saves the statistics of the mysql server
+	Ingest(mysqlServerID int, reader io.Reader) error
+	// Marshal marshals SlowLogService to json bytes
+	Marshal() ([]byte, error)
+}
+
 type Service interface {
 	// GetQueries returns the query slice
 	GetQueries() []Query
@@ -66,6 +66,16 @@ const (
 	ErrNotValidSoarConfig                  = 400059
 	ErrEmptySoarBlacklist                  = 400060
 	ErrNotValidSoarBlacklist               = 400061
+	ErrNotValidHealthcheckTimeout          = 400062
+	ErrNotValidHealthcheckConcurrency      = 400063
+	ErrNotValidHealthcheckForecastDays     = 400064
+	ErrNotValidHealthcheckOwnerThreshold   = 400065
+	ErrNotValidHealthcheckCallbackTimeout  = 400066
+	ErrNotValidHealthcheckRetentionDays    = 400067
+	ErrNotValidHealthcheckBatchSize        = 400068
+	ErrNotValidHealthcheckBatchInterval    = 400069
+	ErrNotValidMonitorGenericLabel         = 400070
+	ErrEmptyHealthcheckCallbackSecret      = 400071
 )
 
 func initErrorMessage() {
@@ -130,4 +140,14 @@ func initErrorMessage() {
 	Messages[ErrNotValidSoarConfig] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidSoarConfig, "soar config path must be either unix or windows path format, %s is not valid")
 	Messages[ErrEmptySoarBlacklist] = config.NewErrMessage(DefaultMessageHeader, ErrEmptySoarBlacklist, "soar blacklist path could not be an empty string")
 	Messages[ErrNotValidSoarBlacklist] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidSoarBlacklist, "soar blacklist path must be either unix or windows path format, %s is not valid")
+	Messages[ErrNotValidHealthcheckTimeout] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidHealthcheckTimeout, "healthcheck timeout must be between %d and %d, %d is not valid")
+	Messages[ErrNotValidHealthcheckConcurrency] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidHealthcheckConcurrency, "healthcheck concurrency must be between %d and %d, %d is not valid")
+	Messages[ErrNotValidHealthcheckForecastDays] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidHealthcheckForecastDays, "healthcheck capacity forecast days must be between %d and %d, %d is not valid")
+	Messages[ErrNotValidHealthcheckOwnerThreshold] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidHealthcheckOwnerThreshold, "healthcheck alert owner threshold must be between %d and %d, %d is not valid")
+	Messages[ErrNotValidHealthcheckCallbackTimeout] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidHealthcheckCallbackTimeout, "healthcheck callback timeout must be between %d and %d, %d is not valid")
+	Messages[ErrNotValidHealthcheckRetentionDays] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidHealthcheckRetentionDays, "healthcheck retention full data days must be between %d and %d, %d is not valid")
+	Messages[ErrNotValidHealthcheckBatchSize] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidHealthcheckBatchSize, "healthcheck retention batch size must be between %d and %d, %d is not valid")
+	Messages[ErrNotValidHealthcheckBatchInterval] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidHealthcheckBatchInterval, "healthcheck retention batch interval must be between %d and %d, %d is not valid")
+	Messages[ErrNotValidMonitorGenericLabel] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidMonitorGenericLabel, "label of the generic prometheus monitor system must not be empty, please check %s")
+	Messages[ErrEmptyHealthcheckCallbackSecret] = config.NewErrMessage(DefaultMessageHeader, ErrEmptyHealthcheckCallbackSecret, "healthcheck callback secret must not be empty when the callback allowed hosts are specified, the payloads of the callbacks must be signed")
 }
@@ -0,0 +1,87 @@
+package healthcheck
+
+import (
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/config"
+)
+
+func init() {
+	initDBConfigRuleDebugMessage()
+	initDBConfigRuleInfoMessage()
+	initDBConfigRuleErrorMessage()
+}
+
+const (
+	// debug
+	DebugHealthcheckGetDBConfigRuleAll     = 103401
+	DebugHealthcheckGetDBConfigRuleByID    = 103402
+	DebugHealthcheckAddDBConfigRule        = 103403
+	DebugHealthcheckUpdateDBConfigRule     = 103404
+	DebugHealthcheckDeleteDBConfigRuleByID = 103405
+	// info
+	InfoHealthcheckGetDBConfigRuleAll     = 203401
+	InfoHealthcheckGetDBConfigRuleByID    = 203402
+	InfoHealthcheckAddDBConfigRule        = 203403
+	InfoHealthcheckUpdateDBConfigRule     = 203404
+	InfoHealthcheckDeleteDBConfigRuleByID = 203405
+	// error
+	ErrHealthcheckGetDBConfigRuleAll     = 403401
+	ErrHealthcheckGetDBConfigRuleByID    = 403402
+	ErrHealthcheckAddDBConfigRule        = 403403
+	ErrHealthcheckUpdateDBConfigRule     = 403404
+	ErrHealthcheckDeleteDBConfigRuleByID = 403405
+)
+
+func initDBConfigRuleDebugMessage() {
+	message.Messages[DebugHealthcheckGetDBConfigRuleAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetDBConfigRuleAll,
+		"healthcheck: get all db config rules completed. message: %s")
+	message.Messages[DebugHealthcheckGetDBConfigRuleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetDBConfigRuleByID,
+		"healthcheck: get db config rule by id completed. message: %s")
+	message.Messages[DebugHealthcheckAddDBConfigRule] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckAddDBConfigRule,
+		"healthcheck: add new db config rule completed. message: %s")
+	message.Messages[DebugHealthcheckUpdateDBConfigRule] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckUpdateDBConfigRule,
+		"healthcheck: update db config rule completed. message: %s")
+	message.Messages[DebugHealthcheckDeleteDBConfigRuleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckDeleteDBConfigRuleByID,
+		"healthcheck: delete db config rule by id completed. message: %s")
+}
+
+func initDBConfigRuleInfoMessage() {
+	message.Messages[InfoHealthcheckGetDBConfigRuleAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetDBConfigRuleAll,
+		"healthcheck: get all db config rules completed")
+	message.Messages[InfoHealthcheckGetDBConfigRuleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetDBConfigRuleByID,
+		"healthcheck: get db config rule by id completed. id: %d")
+	message.Messages[InfoHealthcheckAddDBConfigRule] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckAddDBConfigRule,
+		"healthcheck: add new db config rule completed. variable name: %s")
+	message.Messages[InfoHealthcheckUpdateDBConfigRule] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckUpdateDBConfigRule,
+		"healthcheck: update db config rule completed. id: %d")
+	message.Messages[InfoHealthcheckDeleteDBConfigRuleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckDeleteDBConfigRuleByID,
+		"healthcheck: delete db config rule by id completed. id: %d")
+}
+
+func initDBConfigRuleErrorMessage() {
+	message.Messages[ErrHealthcheckGetDBConfigRuleAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetDBConfigRuleAll,
+		"healthcheck: get all db config rules failed")
+	message.Messages[ErrHealthcheckGetDBConfigRuleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetDBConfigRuleByID,
+		"healthcheck: get db config rule by id failed. id: %d")
+	message.Messages[ErrHealthcheckAddDBConfigRule] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckAddDBConfigRule,
+		"healthcheck: add new db config rule failed. variable name: %s")
+	message.Messages[ErrHealthcheckUpdateDBConfigRule] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckUpdateDBConfigRule,
+		"healthcheck: update db config rule failed. id: %d")
+	message.Messages[ErrHealthcheckDeleteDBConfigRuleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckDeleteDBConfigRuleByID,
+		"healthcheck: delete db config rule by id failed. id: %d")
+}
@@ -30,6 +30,18 @@ const (
 	ErrHealthcheckItemWeightSummaryInvalid               = 403011
 	ErrHealthcheckPmmVersionInvalid                      = 403012
 	ErrHealthcheckSQLAdvisorAdvice                       = 403013
+	ErrHealthcheckCheckItemNotRegistered                 = 403014
+	ErrHealthcheckMountPointsNotFound                    = 403015
+	ErrHealthcheckGetMountPoints                         = 403016
+	ErrHealthcheckCheckItemFailed                        = 403017
+	ErrHealthcheckAllCheckItemsFailed                    = 403018
+	ErrHealthcheckSaveSnapshot                           = 403019
+	ErrHealthcheckSnapshotDataNotCollected               = 403020
+	ErrHealthcheckSnapshotVersionInvalid                 = 403021
+	ErrHealthcheckSnapshotHostInfoInvalid                = 403022
+	ErrHealthcheckSnapshotTimeRangeInvalid               = 403023
+	ErrHealthcheckSaveProgress                           = 403024
+	ErrHealthcheckGetProgressByOperationID               = 403025
 )
 
 func initDefaultEngineDebugMessage() {
@@ -52,6 +64,18 @@ func initDefaultEngineErrorMessage() {
 	message.Messages[ErrHealthcheckScoreDeductionPerUnitMediumItemInvalid] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckScoreDeductionPerUnitMediumItemInvalid, "score deduction per unit medium of %s must be in [1, 100], %f is not valid")
 	message.Messages[ErrHealthcheckMaxScoreDeductionMediumItemInvalid] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckMaxScoreDeductionMediumItemInvalid, "max score deduction medium of %s must be in [1, 100], %f is not valid")
 	message.Messages[ErrHealthcheckItemWeightSummaryInvalid] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckItemWeightSummaryInvalid, "summary of all item weights should be 100, %d is not valid")
-	message.Messages[ErrHealthcheckPmmVersionInvalid] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckPmmVersionInvalid, "pmm version should be 1 or 2, %d is not valid")
+	message.Messages[ErrHealthcheckPmmVersionInvalid] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckPmmVersionInvalid, "monitor system type should be one of 1, 2 or 3, %d is not valid")
 	message.Messages[ErrHealthcheckSQLAdvisorAdvice] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckSQLAdvisorAdvice, "sql advisor returned error")
+	message.Messages[ErrHealthcheckCheckItemNotRegistered] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckCheckItemNotRegistered, "check item %s is not registered, please check the item name in the engine config")
+	message.Messages[ErrHealthcheckMountPointsNotFound] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckMountPointsNotFound, "mount points of the mysql server are not found, please check if the file systems could be got from the prometheus")
+	message.Messages[ErrHealthcheckGetMountPoints] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckGetMountPoints, "got error when getting the mount points of the mysql server. operation_id: %d")
+	message.Messages[ErrHealthcheckCheckItemFailed] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckCheckItemFailed, "check item %s failed. operation_id: %d")
+	message.Messages[ErrHealthcheckAllCheckItemsFailed] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckAllCheckItemsFailed, "none of the check items was checked successfully. operation_id: %d")
+	message.Messages[ErrHealthcheckSaveSnapshot] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckSaveSnapshot, "got error when saving the snapshot. operation_id: %d")
+	message.Messages[ErrHealthcheckSnapshotDataNotCollected] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckSnapshotDataNotCollected, "%s was not collected in the snapshot. snapshot operation_id: %d")
+	message.Messages[ErrHealthcheckSnapshotVersionInvalid] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckSnapshotVersionInvalid, "snapshot version should be %d, %d is not valid")
+	message.Messages[ErrHealthcheckSnapshotHostInfoInvalid] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckSnapshotHostInfoInvalid, "host info of the snapshot is not valid. host_ip: %s, port_num: %d")
+	message.Messages[ErrHealthcheckSnapshotTimeRangeInvalid] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckSnapshotTimeRangeInvalid, "time range of the snapshot is not valid. start_time: %s, end_time: %s, step: %d")
+	message.Messages[ErrHealthcheckSaveProgress] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckSaveProgress, "got error when saving the progress. operation_id: %d")
+	message.Messages[ErrHealthcheckGetProgressByOperationID] = config.NewErrMessage(message.DefaultMessageHeader, ErrHealthcheckGetProgressByOperationID, "got error when getting the progress. operation_id: %d")
 }
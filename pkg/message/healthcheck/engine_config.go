@@ -0,0 +1,91 @@
+package healthcheck
+
+import (
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/config"
+)
+
+func init() {
+	initEngineConfigDebugMessage()
+	initEngineConfigInfoMessage()
+	initEngineConfigErrorMessage()
+}
+
+const (
+	// debug
+	DebugHealthcheckGetEngineConfig         = 103501
+	DebugHealthcheckUpdateEngineConfig      = 103502
+	DebugHealthcheckReplaceEngineConfig     = 103503
+	DebugHealthcheckGetEngineConfigVersions = 103504
+	DebugHealthcheckRollbackEngineConfig    = 103505
+	// info
+	InfoHealthcheckGetEngineConfig         = 203501
+	InfoHealthcheckUpdateEngineConfig      = 203502
+	InfoHealthcheckReplaceEngineConfig     = 203503
+	InfoHealthcheckGetEngineConfigVersions = 203504
+	InfoHealthcheckRollbackEngineConfig    = 203505
+	// error
+	ErrHealthcheckGetEngineConfig         = 403501
+	ErrHealthcheckUpdateEngineConfig      = 403502
+	ErrHealthcheckReplaceEngineConfig     = 403503
+	ErrHealthcheckGetEngineConfigVersions = 403504
+	ErrHealthcheckRollbackEngineConfig    = 403505
+	ErrHealthcheckSyncEngineConfig        = 403506
+)
+
+func initEngineConfigDebugMessage() {
+	message.Messages[DebugHealthcheckGetEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetEngineConfig,
+		"healthcheck: get engine config completed. message: %s")
+	message.Messages[DebugHealthcheckUpdateEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckUpdateEngineConfig,
+		"healthcheck: update engine config completed. message: %s")
+	message.Messages[DebugHealthcheckReplaceEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckReplaceEngineConfig,
+		"healthcheck: replace engine config completed. message: %s")
+	message.Messages[DebugHealthcheckGetEngineConfigVersions] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetEngineConfigVersions,
+		"healthcheck: get engine config versions completed. message: %s")
+	message.Messages[DebugHealthcheckRollbackEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckRollbackEngineConfig,
+		"healthcheck: rollback engine config completed. message: %s")
+}
+
+func initEngineConfigInfoMessage() {
+	message.Messages[InfoHealthcheckGetEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetEngineConfig,
+		"healthcheck: get engine config completed. profile id: %d")
+	message.Messages[InfoHealthcheckUpdateEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckUpdateEngineConfig,
+		"healthcheck: update engine config completed. profile id: %d, version: %d, login name: %s")
+	message.Messages[InfoHealthcheckReplaceEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckReplaceEngineConfig,
+		"healthcheck: replace engine config completed. profile id: %d, version: %d, login name: %s")
+	message.Messages[InfoHealthcheckGetEngineConfigVersions] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetEngineConfigVersions,
+		"healthcheck: get engine config versions completed. profile id: %d")
+	message.Messages[InfoHealthcheckRollbackEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckRollbackEngineConfig,
+		"healthcheck: rollback engine config completed. profile id: %d, rollback to version: %d, login name: %s")
+}
+
+func initEngineConfigErrorMessage() {
+	message.Messages[ErrHealthcheckGetEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetEngineConfig,
+		"healthcheck: get engine config failed. profile id: %d")
+	message.Messages[ErrHealthcheckUpdateEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckUpdateEngineConfig,
+		"healthcheck: update engine config failed. profile id: %d")
+	message.Messages[ErrHealthcheckReplaceEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckReplaceEngineConfig,
+		"healthcheck: replace engine config failed. profile id: %d")
+	message.Messages[ErrHealthcheckGetEngineConfigVersions] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetEngineConfigVersions,
+		"healthcheck: get engine config versions failed. profile id: %d")
+	message.Messages[ErrHealthcheckRollbackEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckRollbackEngineConfig,
+		"healthcheck: rollback engine config failed. profile id: %d, version: %d")
+	message.Messages[ErrHealthcheckSyncEngineConfig] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckSyncEngineConfig,
+		"healthcheck: sync engine config failed")
+}
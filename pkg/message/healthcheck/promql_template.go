@@ -0,0 +1,95 @@
+package healthcheck
+
+import (
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/config"
+)
+
+func init() {
+	initPromQLTemplateDebugMessage()
+	initPromQLTemplateInfoMessage()
+	initPromQLTemplateErrorMessage()
+}
+
+const (
+	// debug
+	DebugHealthcheckGetPromQLTemplateAll               = 103701
+	DebugHealthcheckGetPromQLTemplateByMonitorSystemID = 103702
+	DebugHealthcheckSavePromQLTemplate                 = 103703
+	DebugHealthcheckDeletePromQLTemplate               = 103704
+	DebugHealthcheckPromQLTemplateDryRun               = 103705
+	// info
+	InfoHealthcheckGetPromQLTemplateAll               = 203701
+	InfoHealthcheckGetPromQLTemplateByMonitorSystemID = 203702
+	InfoHealthcheckSavePromQLTemplate                 = 203703
+	InfoHealthcheckDeletePromQLTemplate               = 203704
+	InfoHealthcheckPromQLTemplateDryRun               = 203705
+	// error
+	ErrHealthcheckGetPromQLTemplateAll               = 403701
+	ErrHealthcheckGetPromQLTemplateByMonitorSystemID = 403702
+	ErrHealthcheckSavePromQLTemplate                 = 403703
+	ErrHealthcheckDeletePromQLTemplate               = 403704
+	ErrHealthcheckPromQLTemplateDryRun               = 403705
+	ErrHealthcheckPromQLTemplateNameNotValid         = 403706
+	ErrHealthcheckPromQLTemplateNotValid             = 403707
+)
+
+func initPromQLTemplateDebugMessage() {
+	message.Messages[DebugHealthcheckGetPromQLTemplateAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetPromQLTemplateAll,
+		"healthcheck: get all promql templates completed. message: %s")
+	message.Messages[DebugHealthcheckGetPromQLTemplateByMonitorSystemID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetPromQLTemplateByMonitorSystemID,
+		"healthcheck: get promql templates by monitor system id completed. message: %s")
+	message.Messages[DebugHealthcheckSavePromQLTemplate] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckSavePromQLTemplate,
+		"healthcheck: save promql template completed. message: %s")
+	message.Messages[DebugHealthcheckDeletePromQLTemplate] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckDeletePromQLTemplate,
+		"healthcheck: delete promql template completed. message: %s")
+	message.Messages[DebugHealthcheckPromQLTemplateDryRun] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckPromQLTemplateDryRun,
+		"healthcheck: promql template dry run completed. message: %s")
+}
+
+func initPromQLTemplateInfoMessage() {
+	message.Messages[InfoHealthcheckGetPromQLTemplateAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetPromQLTemplateAll,
+		"healthcheck: get all promql templates completed")
+	message.Messages[InfoHealthcheckGetPromQLTemplateByMonitorSystemID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetPromQLTemplateByMonitorSystemID,
+		"healthcheck: get promql templates by monitor system id completed. monitor system id: %d")
+	message.Messages[InfoHealthcheckSavePromQLTemplate] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckSavePromQLTemplate,
+		"healthcheck: save promql template completed. monitor system id: %d, template name: %s")
+	message.Messages[InfoHealthcheckDeletePromQLTemplate] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckDeletePromQLTemplate,
+		"healthcheck: delete promql template completed. monitor system id: %d, template name: %s")
+	message.Messages[InfoHealthcheckPromQLTemplateDryRun] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckPromQLTemplateDryRun,
+		"healthcheck: promql template dry run completed. monitor system id: %d, template name: %s")
+}
+
+func initPromQLTemplateErrorMessage() {
+	message.Messages[ErrHealthcheckGetPromQLTemplateAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetPromQLTemplateAll,
+		"healthcheck: get all promql templates failed")
+	message.Messages[ErrHealthcheckGetPromQLTemplateByMonitorSystemID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetPromQLTemplateByMonitorSystemID,
+		"healthcheck: get promql templates by monitor system id failed. monitor system id: %d")
+	message.Messages[ErrHealthcheckSavePromQLTemplate] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckSavePromQLTemplate,
+		"healthcheck: save promql template failed. monitor system id: %d, template name: %s")
+	message.Messages[ErrHealthcheckDeletePromQLTemplate] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckDeletePromQLTemplate,
+		"healthcheck: delete promql template failed. monitor system id: %d, template name: %s")
+	message.Messages[ErrHealthcheckPromQLTemplateDryRun] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckPromQLTemplateDryRun,
+		"healthcheck: promql template dry run failed. monitor system id: %d, template name: %s")
+	message.Messages[ErrHealthcheckPromQLTemplateNameNotValid] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckPromQLTemplateNameNotValid,
+		"healthcheck: promql template name is not valid. template name: %s")
+	message.Messages[ErrHealthcheckPromQLTemplateNotValid] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckPromQLTemplateNotValid,
+		"healthcheck: render promql template failed. template name: %s")
+}
@@ -0,0 +1,95 @@
+package healthcheck
+
+import (
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/config"
+)
+
+func init() {
+	initRetentionDebugMessage()
+	initRetentionInfoMessage()
+	initRetentionErrorMessage()
+}
+
+const (
+	// debug
+	DebugHealthcheckGetRetentionAll        = 103601
+	DebugHealthcheckGetRetentionByEnvID    = 103602
+	DebugHealthcheckSaveRetention          = 103603
+	DebugHealthcheckDeleteRetentionByEnvID = 103604
+	DebugHealthcheckRetentionDryRun        = 103605
+	// info
+	InfoHealthcheckGetRetentionAll        = 203601
+	InfoHealthcheckGetRetentionByEnvID    = 203602
+	InfoHealthcheckSaveRetention          = 203603
+	InfoHealthcheckDeleteRetentionByEnvID = 203604
+	InfoHealthcheckRetentionDryRun        = 203605
+	InfoHealthcheckArchive                = 203606
+	// error
+	ErrHealthcheckGetRetentionAll        = 403601
+	ErrHealthcheckGetRetentionByEnvID    = 403602
+	ErrHealthcheckSaveRetention          = 403603
+	ErrHealthcheckDeleteRetentionByEnvID = 403604
+	ErrHealthcheckRetentionDryRun        = 403605
+	ErrHealthcheckArchive                = 403606
+)
+
+func initRetentionDebugMessage() {
+	message.Messages[DebugHealthcheckGetRetentionAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetRetentionAll,
+		"healthcheck: get all retention policies completed. message: %s")
+	message.Messages[DebugHealthcheckGetRetentionByEnvID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetRetentionByEnvID,
+		"healthcheck: get retention policy by env id completed. message: %s")
+	message.Messages[DebugHealthcheckSaveRetention] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckSaveRetention,
+		"healthcheck: save retention policy completed. message: %s")
+	message.Messages[DebugHealthcheckDeleteRetentionByEnvID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckDeleteRetentionByEnvID,
+		"healthcheck: delete retention policy by env id completed. message: %s")
+	message.Messages[DebugHealthcheckRetentionDryRun] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckRetentionDryRun,
+		"healthcheck: retention dry run completed. message: %s")
+}
+
+func initRetentionInfoMessage() {
+	message.Messages[InfoHealthcheckGetRetentionAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetRetentionAll,
+		"healthcheck: get all retention policies completed")
+	message.Messages[InfoHealthcheckGetRetentionByEnvID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetRetentionByEnvID,
+		"healthcheck: get retention policy by env id completed. env id: %d")
+	message.Messages[InfoHealthcheckSaveRetention] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckSaveRetention,
+		"healthcheck: save retention policy completed. env id: %d")
+	message.Messages[InfoHealthcheckDeleteRetentionByEnvID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckDeleteRetentionByEnvID,
+		"healthcheck: delete retention policy by env id completed. env id: %d")
+	message.Messages[InfoHealthcheckRetentionDryRun] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckRetentionDryRun,
+		"healthcheck: retention dry run completed")
+	message.Messages[InfoHealthcheckArchive] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckArchive,
+		"healthcheck: archive expired data completed. archived operations: %d, archived middleware operations: %d, purged operations: %d")
+}
+
+func initRetentionErrorMessage() {
+	message.Messages[ErrHealthcheckGetRetentionAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetRetentionAll,
+		"healthcheck: get all retention policies failed")
+	message.Messages[ErrHealthcheckGetRetentionByEnvID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetRetentionByEnvID,
+		"healthcheck: get retention policy by env id failed. env id: %d")
+	message.Messages[ErrHealthcheckSaveRetention] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckSaveRetention,
+		"healthcheck: save retention policy failed. env id: %d")
+	message.Messages[ErrHealthcheckDeleteRetentionByEnvID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckDeleteRetentionByEnvID,
+		"healthcheck: delete retention policy by env id failed. env id: %d")
+	message.Messages[ErrHealthcheckRetentionDryRun] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckRetentionDryRun,
+		"healthcheck: retention dry run failed")
+	message.Messages[ErrHealthcheckArchive] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckArchive,
+		"healthcheck: archive expired data failed")
+}
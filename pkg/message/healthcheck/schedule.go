@@ -0,0 +1,103 @@
+package healthcheck
+
+import (
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/config"
+)
+
+func init() {
+	initScheduleDebugMessage()
+	initScheduleInfoMessage()
+	initScheduleErrorMessage()
+}
+
+const (
+	// debug
+	DebugHealthcheckGetScheduleAll     = 103201
+	DebugHealthcheckGetScheduleByID    = 103202
+	DebugHealthcheckAddSchedule        = 103203
+	DebugHealthcheckUpdateSchedule     = 103204
+	DebugHealthcheckDeleteScheduleByID = 103205
+	// info
+	InfoHealthcheckGetScheduleAll     = 203201
+	InfoHealthcheckGetScheduleByID    = 203202
+	InfoHealthcheckAddSchedule        = 203203
+	InfoHealthcheckUpdateSchedule     = 203204
+	InfoHealthcheckDeleteScheduleByID = 203205
+	InfoHealthcheckScheduleCheck      = 203206
+	// error
+	ErrHealthcheckGetScheduleAll     = 403201
+	ErrHealthcheckGetScheduleByID    = 403202
+	ErrHealthcheckAddSchedule        = 403203
+	ErrHealthcheckUpdateSchedule     = 403204
+	ErrHealthcheckDeleteScheduleByID = 403205
+	ErrHealthcheckScheduleLoad       = 403206
+	ErrHealthcheckScheduleTrigger    = 403207
+	ErrHealthcheckScheduleCheck      = 403208
+)
+
+func initScheduleDebugMessage() {
+	message.Messages[DebugHealthcheckGetScheduleAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetScheduleAll,
+		"healthcheck: get all schedules completed. message: %s")
+	message.Messages[DebugHealthcheckGetScheduleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetScheduleByID,
+		"healthcheck: get schedule by id completed. message: %s")
+	message.Messages[DebugHealthcheckAddSchedule] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckAddSchedule,
+		"healthcheck: add new schedule completed. message: %s")
+	message.Messages[DebugHealthcheckUpdateSchedule] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckUpdateSchedule,
+		"healthcheck: update schedule completed. message: %s")
+	message.Messages[DebugHealthcheckDeleteScheduleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckDeleteScheduleByID,
+		"healthcheck: delete schedule by id completed. message: %s")
+}
+
+func initScheduleInfoMessage() {
+	message.Messages[InfoHealthcheckGetScheduleAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetScheduleAll,
+		"healthcheck: get all schedules completed")
+	message.Messages[InfoHealthcheckGetScheduleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetScheduleByID,
+		"healthcheck: get schedule by id completed. id: %d")
+	message.Messages[InfoHealthcheckAddSchedule] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckAddSchedule,
+		"healthcheck: add new schedule completed. schedule name: %s")
+	message.Messages[InfoHealthcheckUpdateSchedule] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckUpdateSchedule,
+		"healthcheck: update schedule completed. id: %d")
+	message.Messages[InfoHealthcheckDeleteScheduleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckDeleteScheduleByID,
+		"healthcheck: delete schedule by id completed. id: %d")
+	message.Messages[InfoHealthcheckScheduleCheck] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckScheduleCheck,
+		"healthcheck: scheduled check started. schedule id: %d, mysql server id: %d, operation id: %d")
+}
+
+func initScheduleErrorMessage() {
+	message.Messages[ErrHealthcheckGetScheduleAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetScheduleAll,
+		"healthcheck: get all schedules failed")
+	message.Messages[ErrHealthcheckGetScheduleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetScheduleByID,
+		"healthcheck: get schedule by id failed. id: %d")
+	message.Messages[ErrHealthcheckAddSchedule] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckAddSchedule,
+		"healthcheck: add new schedule failed. schedule name: %s")
+	message.Messages[ErrHealthcheckUpdateSchedule] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckUpdateSchedule,
+		"healthcheck: update schedule failed. id: %d")
+	message.Messages[ErrHealthcheckDeleteScheduleByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckDeleteScheduleByID,
+		"healthcheck: delete schedule by id failed. id: %d")
+	message.Messages[ErrHealthcheckScheduleLoad] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckScheduleLoad,
+		"healthcheck: load enabled schedules failed")
+	message.Messages[ErrHealthcheckScheduleTrigger] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckScheduleTrigger,
+		"healthcheck: trigger schedule failed. schedule id: %d")
+	message.Messages[ErrHealthcheckScheduleCheck] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckScheduleCheck,
+		"healthcheck: scheduled check failed. schedule id: %d, mysql server id: %d")
+}
@@ -0,0 +1,111 @@
+package healthcheck
+
+import (
+	"github.com/romberli/das/pkg/message"
+	"github.com/romberli/go-util/config"
+)
+
+func init() {
+	initScoringProfileDebugMessage()
+	initScoringProfileInfoMessage()
+	initScoringProfileErrorMessage()
+}
+
+const (
+	// debug
+	DebugHealthcheckGetScoringProfileAll     = 103301
+	DebugHealthcheckGetScoringProfileByID    = 103302
+	DebugHealthcheckAddScoringProfile        = 103303
+	DebugHealthcheckUpdateScoringProfile     = 103304
+	DebugHealthcheckDeleteScoringProfileByID = 103305
+	DebugHealthcheckAssignScoringProfile     = 103306
+	DebugHealthcheckUnassignScoringProfile   = 103307
+	// info
+	InfoHealthcheckGetScoringProfileAll     = 203301
+	InfoHealthcheckGetScoringProfileByID    = 203302
+	InfoHealthcheckAddScoringProfile        = 203303
+	InfoHealthcheckUpdateScoringProfile     = 203304
+	InfoHealthcheckDeleteScoringProfileByID = 203305
+	InfoHealthcheckAssignScoringProfile     = 203306
+	InfoHealthcheckUnassignScoringProfile   = 203307
+	// error
+	ErrHealthcheckGetScoringProfileAll     = 403301
+	ErrHealthcheckGetScoringProfileByID    = 403302
+	ErrHealthcheckAddScoringProfile        = 403303
+	ErrHealthcheckUpdateScoringProfile     = 403304
+	ErrHealthcheckDeleteScoringProfileByID = 403305
+	ErrHealthcheckAssignScoringProfile     = 403306
+	ErrHealthcheckUnassignScoringProfile   = 403307
+)
+
+func initScoringProfileDebugMessage() {
+	message.Messages[DebugHealthcheckGetScoringProfileAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetScoringProfileAll,
+		"healthcheck: get all scoring profiles completed. message: %s")
+	message.Messages[DebugHealthcheckGetScoringProfileByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetScoringProfileByID,
+		"healthcheck: get scoring profile by id completed. message: %s")
+	message.Messages[DebugHealthcheckAddScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckAddScoringProfile,
+		"healthcheck: add new scoring profile completed. message: %s")
+	message.Messages[DebugHealthcheckUpdateScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckUpdateScoringProfile,
+		"healthcheck: update scoring profile completed. message: %s")
+	message.Messages[DebugHealthcheckDeleteScoringProfileByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckDeleteScoringProfileByID,
+		"healthcheck: delete scoring profile by id completed. message: %s")
+	message.Messages[DebugHealthcheckAssignScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckAssignScoringProfile,
+		"healthcheck: assign scoring profile completed. message: %s")
+	message.Messages[DebugHealthcheckUnassignScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckUnassignScoringProfile,
+		"healthcheck: unassign scoring profile completed. message: %s")
+}
+
+func initScoringProfileInfoMessage() {
+	message.Messages[InfoHealthcheckGetScoringProfileAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetScoringProfileAll,
+		"healthcheck: get all scoring profiles completed")
+	message.Messages[InfoHealthcheckGetScoringProfileByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetScoringProfileByID,
+		"healthcheck: get scoring profile by id completed. id: %d")
+	message.Messages[InfoHealthcheckAddScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckAddScoringProfile,
+		"healthcheck: add new scoring profile completed. profile name: %s")
+	message.Messages[InfoHealthcheckUpdateScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckUpdateScoringProfile,
+		"healthcheck: update scoring profile completed. id: %d")
+	message.Messages[InfoHealthcheckDeleteScoringProfileByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckDeleteScoringProfileByID,
+		"healthcheck: delete scoring profile by id completed. id: %d")
+	message.Messages[InfoHealthcheckAssignScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckAssignScoringProfile,
+		"healthcheck: assign scoring profile completed. id: %d, target type: %d, target id: %d")
+	message.Messages[InfoHealthcheckUnassignScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckUnassignScoringProfile,
+		"healthcheck: unassign scoring profile completed. target type: %d, target id: %d")
+}
+
+func initScoringProfileErrorMessage() {
+	message.Messages[ErrHealthcheckGetScoringProfileAll] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetScoringProfileAll,
+		"healthcheck: get all scoring profiles failed")
+	message.Messages[ErrHealthcheckGetScoringProfileByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetScoringProfileByID,
+		"healthcheck: get scoring profile by id failed. id: %d")
+	message.Messages[ErrHealthcheckAddScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckAddScoringProfile,
+		"healthcheck: add new scoring profile failed. profile name: %s")
+	message.Messages[ErrHealthcheckUpdateScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckUpdateScoringProfile,
+		"healthcheck: update scoring profile failed. id: %d")
+	message.Messages[ErrHealthcheckDeleteScoringProfileByID] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckDeleteScoringProfileByID,
+		"healthcheck: delete scoring profile by id failed. id: %d")
+	message.Messages[ErrHealthcheckAssignScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckAssignScoringProfile,
+		"healthcheck: assign scoring profile failed. id: %d, target type: %d, target id: %d")
+	message.Messages[ErrHealthcheckUnassignScoringProfile] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckUnassignScoringProfile,
+		"healthcheck: unassign scoring profile failed. target type: %d, target id: %d")
+}
@@ -18,12 +18,48 @@ const (
 	DebugHealthcheckCheck                            = 103103
 	DebugHealthcheckCheckByHostInfo                  = 103103
 	DebugHealthcheckReviewAccuracy                   = 103104
+	DebugHealthcheckCheckCluster                     = 103105
+	DebugHealthcheckGetClusterResult                 = 103106
+	DebugHealthcheckCancel                           = 103107
+	DebugHealthcheckGetTrend                         = 103108
+	DebugHealthcheckGetClusterTrend                  = 103109
+	DebugHealthcheckCompareResults                   = 103110
+	DebugHealthcheckGetReport                        = 103111
+	DebugHealthcheckGetSnapshot                      = 103112
+	DebugHealthcheckCheckWithSnapshot                = 103113
+	DebugHealthcheckReviewItemAccuracy               = 103114
+	DebugHealthcheckGetItemFeedbacks                 = 103115
+	DebugHealthcheckGetFeedbackStats                 = 103116
+	DebugHealthcheckGetOperationQueue                = 103117
+	DebugHealthcheckGetProgress                      = 103118
+	DebugHealthcheckGetProgressStream                = 103119
+	DebugHealthcheckGetCapacity                      = 103120
+	DebugHealthcheckCheckMiddleware                  = 103121
+	DebugHealthcheckGetMiddlewareResult              = 103122
 	// info
 	InfoHealthcheckGetOperationHistoriesByLoginName = 203101
 	InfoHealthcheckGetResultByOperationID           = 203102
 	InfoHealthcheckCheck                            = 203103
 	InfoHealthcheckCheckByHostInfo                  = 203103
 	InfoHealthcheckReviewAccuracy                   = 203104
+	InfoHealthcheckCheckCluster                     = 203105
+	InfoHealthcheckGetClusterResult                 = 203106
+	InfoHealthcheckCancel                           = 203107
+	InfoHealthcheckGetTrend                         = 203108
+	InfoHealthcheckGetClusterTrend                  = 203109
+	InfoHealthcheckCompareResults                   = 203110
+	InfoHealthcheckGetReport                        = 203111
+	InfoHealthcheckGetSnapshot                      = 203112
+	InfoHealthcheckCheckWithSnapshot                = 203113
+	InfoHealthcheckReviewItemAccuracy               = 203114
+	InfoHealthcheckGetItemFeedbacks                 = 203115
+	InfoHealthcheckGetFeedbackStats                 = 203116
+	InfoHealthcheckGetOperationQueue                = 203117
+	InfoHealthcheckGetProgress                      = 203118
+	InfoHealthcheckGetProgressStream                = 203119
+	InfoHealthcheckGetCapacity                      = 203120
+	InfoHealthcheckCheckMiddleware                  = 203121
+	InfoHealthcheckGetMiddlewareResult              = 203122
 	// error
 	ErrHealthcheckCheckRange                        = 403101
 	ErrHealthcheckStartTime                         = 403102
@@ -38,6 +74,30 @@ const (
 	ErrHealthcheckCreateMonitorMySQLConnection      = 403111
 	ErrHealthcheckCreateMonitorClickhouseConnection = 403112
 	ErrHealthcheckCreateMonitorPrometheusConnection = 403113
+	ErrHealthcheckCheckCluster                      = 403114
+	ErrHealthcheckGetClusterResult                  = 403115
+	ErrHealthcheckClusterEngineRun                  = 403116
+	ErrHealthcheckCancel                            = 403117
+	ErrHealthcheckOperationNotRunning               = 403118
+	ErrHealthcheckGetTrend                          = 403119
+	ErrHealthcheckGetClusterTrend                   = 403120
+	ErrHealthcheckCompareResults                    = 403121
+	ErrHealthcheckGetReport                         = 403122
+	ErrHealthcheckGetSnapshot                       = 403123
+	ErrHealthcheckCheckWithSnapshot                 = 403124
+	ErrHealthcheckReviewItemAccuracy                = 403125
+	ErrHealthcheckGetItemFeedbacks                  = 403126
+	ErrHealthcheckGetFeedbackStats                  = 403127
+	ErrHealthcheckGetOperationQueue                 = 403128
+	ErrHealthcheckRecoverQueuedOperations           = 403129
+	ErrHealthcheckRecoverQueuedOperation            = 403130
+	ErrHealthcheckGetProgress                       = 403131
+	ErrHealthcheckGetProgressStream                 = 403132
+	ErrHealthcheckGetCapacity                       = 403133
+	ErrHealthcheckCheckMiddleware                   = 403134
+	ErrHealthcheckGetMiddlewareResult               = 403135
+	ErrHealthcheckMiddlewareEngineRun               = 403136
+	ErrHealthcheckSendCallback                      = 403137
 )
 
 func initServiceDebugMessage() {
@@ -56,6 +116,60 @@ func initServiceDebugMessage() {
 	message.Messages[DebugHealthcheckReviewAccuracy] = config.NewErrMessage(
 		message.DefaultMessageHeader, DebugHealthcheckReviewAccuracy,
 		"healthcheck: review accuracy message: %s")
+	message.Messages[DebugHealthcheckCheckCluster] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckCheckCluster,
+		"healthcheck: check cluster started. cluster operation id: %d")
+	message.Messages[DebugHealthcheckGetClusterResult] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetClusterResult,
+		"healthcheck: get cluster result by cluster operation id completed. message: %s")
+	message.Messages[DebugHealthcheckCancel] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckCancel,
+		"healthcheck: cancel completed. message: %s")
+	message.Messages[DebugHealthcheckGetTrend] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetTrend,
+		"healthcheck: get trend by mysql server id completed. message: %s")
+	message.Messages[DebugHealthcheckGetClusterTrend] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetClusterTrend,
+		"healthcheck: get trend by mysql cluster id completed. message: %s")
+	message.Messages[DebugHealthcheckCompareResults] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckCompareResults,
+		"healthcheck: compare results completed. message: %s")
+	message.Messages[DebugHealthcheckGetReport] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetReport,
+		"healthcheck: get report completed. operation id: %d, format: %s")
+	message.Messages[DebugHealthcheckGetSnapshot] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetSnapshot,
+		"healthcheck: get snapshot completed. operation id: %d")
+	message.Messages[DebugHealthcheckCheckWithSnapshot] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckCheckWithSnapshot,
+		"healthcheck: check with snapshot started. operation id: %d")
+	message.Messages[DebugHealthcheckReviewItemAccuracy] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckReviewItemAccuracy,
+		"healthcheck: review item accuracy completed. message: %s")
+	message.Messages[DebugHealthcheckGetItemFeedbacks] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetItemFeedbacks,
+		"healthcheck: get item feedbacks completed. message: %s")
+	message.Messages[DebugHealthcheckGetFeedbackStats] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetFeedbackStats,
+		"healthcheck: get feedback stats completed. message: %s")
+	message.Messages[DebugHealthcheckGetOperationQueue] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetOperationQueue,
+		"healthcheck: get operation queue completed. message: %s")
+	message.Messages[DebugHealthcheckGetProgress] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetProgress,
+		"healthcheck: get progress completed. message: %s")
+	message.Messages[DebugHealthcheckGetProgressStream] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetProgressStream,
+		"healthcheck: progress event sent. message: %s")
+	message.Messages[DebugHealthcheckGetCapacity] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetCapacity,
+		"healthcheck: get capacity completed. message: %s")
+	message.Messages[DebugHealthcheckCheckMiddleware] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckCheckMiddleware,
+		"healthcheck: check middleware cluster started. middleware operation id: %d")
+	message.Messages[DebugHealthcheckGetMiddlewareResult] = config.NewErrMessage(
+		message.DefaultMessageHeader, DebugHealthcheckGetMiddlewareResult,
+		"healthcheck: get middleware result by middleware operation id completed. message: %s")
 }
 
 func initServiceInfoMessage() {
@@ -74,6 +188,60 @@ func initServiceInfoMessage() {
 	message.Messages[InfoHealthcheckReviewAccuracy] = config.NewErrMessage(
 		message.DefaultMessageHeader, InfoHealthcheckReviewAccuracy,
 		"healthcheck: review accuracy completed. operation id: %d")
+	message.Messages[InfoHealthcheckCheckCluster] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckCheckCluster,
+		"healthcheck: check cluster started. cluster operation id: %d")
+	message.Messages[InfoHealthcheckGetClusterResult] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetClusterResult,
+		"healthcheck: get cluster result by cluster operation id completed. cluster operation id: %d")
+	message.Messages[InfoHealthcheckCancel] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckCancel,
+		"healthcheck: cancel completed. operation id: %d")
+	message.Messages[InfoHealthcheckGetTrend] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetTrend,
+		"healthcheck: get trend by mysql server id completed. mysql server id: %d")
+	message.Messages[InfoHealthcheckGetClusterTrend] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetClusterTrend,
+		"healthcheck: get trend by mysql cluster id completed. mysql cluster id: %d")
+	message.Messages[InfoHealthcheckCompareResults] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckCompareResults,
+		"healthcheck: compare results completed. base operation id: %d, target operation id: %d")
+	message.Messages[InfoHealthcheckGetReport] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetReport,
+		"healthcheck: get report completed. operation id: %d")
+	message.Messages[InfoHealthcheckGetSnapshot] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetSnapshot,
+		"healthcheck: get snapshot completed. operation id: %d")
+	message.Messages[InfoHealthcheckCheckWithSnapshot] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckCheckWithSnapshot,
+		"healthcheck: check with snapshot started. operation id: %d")
+	message.Messages[InfoHealthcheckReviewItemAccuracy] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckReviewItemAccuracy,
+		"healthcheck: review item accuracy completed. operation id: %d, item name: %s, login name: %s")
+	message.Messages[InfoHealthcheckGetItemFeedbacks] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetItemFeedbacks,
+		"healthcheck: get item feedbacks completed. operation id: %d")
+	message.Messages[InfoHealthcheckGetFeedbackStats] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetFeedbackStats,
+		"healthcheck: get feedback stats completed. start time: %s, end time: %s")
+	message.Messages[InfoHealthcheckGetOperationQueue] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetOperationQueue,
+		"healthcheck: get operation queue completed. queued: %d, running: %d")
+	message.Messages[InfoHealthcheckGetProgress] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetProgress,
+		"healthcheck: get progress completed. operation id: %d")
+	message.Messages[InfoHealthcheckGetProgressStream] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetProgressStream,
+		"healthcheck: progress stream closed. operation id: %d")
+	message.Messages[InfoHealthcheckGetCapacity] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetCapacity,
+		"healthcheck: get capacity completed. target type: %d, target id: %d")
+	message.Messages[InfoHealthcheckCheckMiddleware] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckCheckMiddleware,
+		"healthcheck: check middleware cluster started. middleware operation id: %d")
+	message.Messages[InfoHealthcheckGetMiddlewareResult] = config.NewErrMessage(
+		message.DefaultMessageHeader, InfoHealthcheckGetMiddlewareResult,
+		"healthcheck: get middleware result by middleware operation id completed. middleware operation id: %d")
 }
 
 func initServiceErrorMessage() {
@@ -116,4 +284,76 @@ func initServiceErrorMessage() {
 	message.Messages[ErrHealthcheckCreateMonitorPrometheusConnection] = config.NewErrMessage(
 		message.DefaultMessageHeader, ErrHealthcheckCreateMonitorPrometheusConnection,
 		"create prometheus connection failed. addr: %s, user: %s")
+	message.Messages[ErrHealthcheckCheckCluster] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckCheckCluster,
+		"healthcheck: check cluster failed. cluster operation id: %d")
+	message.Messages[ErrHealthcheckGetClusterResult] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetClusterResult,
+		"healthcheck: get cluster result by cluster operation id failed. cluster operation id: %d")
+	message.Messages[ErrHealthcheckClusterEngineRun] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckClusterEngineRun,
+		"healthcheck: cluster engine run failed. cluster operation id: %d")
+	message.Messages[ErrHealthcheckCancel] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckCancel,
+		"healthcheck: cancel failed. operation id: %d")
+	message.Messages[ErrHealthcheckOperationNotRunning] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckOperationNotRunning,
+		"healthcheck: operation is not running. operation id: %d, status: %d")
+	message.Messages[ErrHealthcheckGetTrend] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetTrend,
+		"healthcheck: get trend by mysql server id failed. mysql server id: %d")
+	message.Messages[ErrHealthcheckGetClusterTrend] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetClusterTrend,
+		"healthcheck: get trend by mysql cluster id failed. mysql cluster id: %d")
+	message.Messages[ErrHealthcheckCompareResults] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckCompareResults,
+		"healthcheck: compare results failed. base operation id: %d, target operation id: %d")
+	message.Messages[ErrHealthcheckGetReport] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetReport,
+		"healthcheck: get report failed. operation id: %d")
+	message.Messages[ErrHealthcheckGetSnapshot] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetSnapshot,
+		"healthcheck: get snapshot failed. operation id: %d")
+	message.Messages[ErrHealthcheckCheckWithSnapshot] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckCheckWithSnapshot,
+		"healthcheck: check with snapshot failed. operation id: %d")
+	message.Messages[ErrHealthcheckReviewItemAccuracy] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckReviewItemAccuracy,
+		"healthcheck: review item accuracy failed. operation id: %d, item name: %s")
+	message.Messages[ErrHealthcheckGetItemFeedbacks] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetItemFeedbacks,
+		"healthcheck: get item feedbacks failed. operation id: %d")
+	message.Messages[ErrHealthcheckGetFeedbackStats] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetFeedbackStats,
+		"healthcheck: get feedback stats failed. start time: %s, end time: %s")
+	message.Messages[ErrHealthcheckGetOperationQueue] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetOperationQueue,
+		"healthcheck: get operation queue failed")
+	message.Messages[ErrHealthcheckRecoverQueuedOperations] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckRecoverQueuedOperations,
+		"healthcheck: recover queued operations failed")
+	message.Messages[ErrHealthcheckRecoverQueuedOperation] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckRecoverQueuedOperation,
+		"healthcheck: recover queued operation failed. operation id: %d")
+	message.Messages[ErrHealthcheckGetProgress] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetProgress,
+		"healthcheck: get progress failed. operation id: %d")
+	message.Messages[ErrHealthcheckGetProgressStream] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetProgressStream,
+		"healthcheck: get progress stream failed. operation id: %d")
+	message.Messages[ErrHealthcheckGetCapacity] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetCapacity,
+		"healthcheck: get capacity failed. target type: %d, target id: %d")
+	message.Messages[ErrHealthcheckCheckMiddleware] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckCheckMiddleware,
+		"healthcheck: check middleware cluster failed. middleware operation id: %d")
+	message.Messages[ErrHealthcheckGetMiddlewareResult] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckGetMiddlewareResult,
+		"healthcheck: get middleware result by middleware operation id failed. middleware operation id: %d")
+	message.Messages[ErrHealthcheckMiddlewareEngineRun] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckMiddlewareEngineRun,
+		"healthcheck: middleware engine run failed. middleware operation id: %d")
+	message.Messages[ErrHealthcheckSendCallback] = config.NewErrMessage(
+		message.DefaultMessageHeader, ErrHealthcheckSendCallback,
+		"healthcheck: send callback failed. operation id: %d, callback url: %s")
 }
@@ -18,12 +18,14 @@ const (
 	DebugQueryGetByHostInfo       = 104003
 	DebugQueryGetByDBID           = 104004
 	DebugQueryGetBySQLID          = 104005
+	DebugQueryIngestSlowLog       = 104006
 	// info
 	InfoQueryGetByMySQLClusterID = 204001
 	InfoQueryGetByMySQLServerID  = 204002
 	InfoQueryGetByHostInfo       = 204003
 	InfoQueryGetByDBID           = 204004
 	InfoQueryGetBySQLID          = 204005
+	InfoQueryIngestSlowLog       = 204006
 	// error
 	ErrQueryGetByMySQLClusterID               = 404001
 	ErrQueryGetByMySQLServerID                = 404002
@@ -35,6 +37,10 @@ const (
 	ErrQueryCloseMonitorRepo                  = 404008
 	ErrQueryCreateMonitorMysqlConnection      = 404009
 	ErrQueryCreateMonitorClickhouseConnection = 404010
+	ErrQueryCreateApplicationMySQLConnection  = 404011
+	ErrQueryParseSlowLog                      = 404012
+	ErrQueryIngestSlowLog                     = 404013
+	ErrQueryOpenSlowLogFile                   = 404014
 )
 
 func initQueryDebugMessage() {
@@ -43,6 +49,7 @@ func initQueryDebugMessage() {
 	message.Messages[DebugQueryGetByHostInfo] = config.NewErrMessage(message.DefaultMessageHeader, DebugQueryGetByHostInfo, "get by mysql server host info. host_ip: %s, port_num: %d, message: %s")
 	message.Messages[DebugQueryGetByDBID] = config.NewErrMessage(message.DefaultMessageHeader, DebugQueryGetByDBID, "get by db id. db_id: %d, message: %s")
 	message.Messages[DebugQueryGetBySQLID] = config.NewErrMessage(message.DefaultMessageHeader, DebugQueryGetBySQLID, "get by sql id. mysql_server_id: %d, sql_id: %s, message: %s")
+	message.Messages[DebugQueryIngestSlowLog] = config.NewErrMessage(message.DefaultMessageHeader, DebugQueryIngestSlowLog, "ingest slow log. mysql_server_id: %d, message: %s")
 }
 
 func initQueryInfoMessage() {
@@ -51,6 +58,7 @@ func initQueryInfoMessage() {
 	message.Messages[InfoQueryGetByHostInfo] = config.NewErrMessage(message.DefaultMessageHeader, DebugQueryGetByHostInfo, "get by mysql server host info completed. host_ip: %s, port_num: %d")
 	message.Messages[InfoQueryGetByDBID] = config.NewErrMessage(message.DefaultMessageHeader, InfoQueryGetByDBID, "get by db id completed. db_id: %d.")
 	message.Messages[InfoQueryGetBySQLID] = config.NewErrMessage(message.DefaultMessageHeader, InfoQueryGetBySQLID, "get by sql id completed. mysql_server_id: %d, sql_id: %s")
+	message.Messages[InfoQueryIngestSlowLog] = config.NewErrMessage(message.DefaultMessageHeader, InfoQueryIngestSlowLog, "ingest slow log completed. mysql_server_id: %d, entry_count: %d, skipped_count: %d, stat_count: %d")
 }
 
 func initQueryErrorMessage() {
@@ -60,8 +68,12 @@ func initQueryErrorMessage() {
 	message.Messages[ErrQueryGetByDBID] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryGetByDBID, "get by db id failed. db_id: %d")
 	message.Messages[ErrQueryGetBySQLID] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryGetBySQLID, "get by sql id failed. mysql_server_id: %d, sql_id: %s")
 	message.Messages[ErrQueryConfigNotValid] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryConfigNotValid, "config is not valid. start_time: %s, end_time: %s, limit: %d")
-	message.Messages[ErrQueryMonitorSystemSystemType] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryMonitorSystemSystemType, "monitor system type should be either 1 or 2, %d is not valid")
+	message.Messages[ErrQueryMonitorSystemSystemType] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryMonitorSystemSystemType, "monitor system type should be one of 1, 2 or 3, %d is not valid")
 	message.Messages[ErrQueryCloseMonitorRepo] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryCloseMonitorRepo, "close monitor repo failed")
 	message.Messages[ErrQueryCreateMonitorMysqlConnection] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryCreateMonitorMysqlConnection, "create monitor mysql connection failed. addr: %s, user: %s")
 	message.Messages[ErrQueryCreateMonitorClickhouseConnection] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryCreateMonitorClickhouseConnection, "create monitor clickhouse connection failed. addr: %s, user: %s")
+	message.Messages[ErrQueryCreateApplicationMySQLConnection] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryCreateApplicationMySQLConnection, "create application mysql connection failed. addr: %s, user: %s")
+	message.Messages[ErrQueryParseSlowLog] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryParseSlowLog, "parse slow log failed. line: %d, content: %s")
+	message.Messages[ErrQueryIngestSlowLog] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryIngestSlowLog, "ingest slow log failed. mysql_server_id: %d")
+	message.Messages[ErrQueryOpenSlowLogFile] = config.NewErrMessage(message.DefaultMessageHeader, ErrQueryOpenSlowLogFile, "open slow log file failed. file: %s")
 }
@@ -28,3 +28,12 @@ func ResponseOK(c *gin.Context, respMessage string, code int, values ...interfac
 
 	c.String(http.StatusOK, respMessage)
 }
+
+// ResponseAttachment responses the content as an attachment with given file name and content type
+func ResponseAttachment(c *gin.Context, fileName, contentType string, content []byte, code int, values ...interface{}) {
+	msg := message.NewMessage(code, values...).Error()
+	log.Info(msg)
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Data(http.StatusOK, contentType, content)
+}
@@ -1,11 +1,16 @@
 package healthcheck
 
+import (
+	"encoding/json"
+)
+
 type Check struct {
-	ServerID  int    `json:"server_id" binding:"required"`
-	StartTime string `json:"start_time" binding:"required"`
-	EndTime   string `json:"end_time" binding:"required"`
-	Step      string `json:"step" binding:"required"`
-	LoginName string `json:"login_name" binding:"required"`
+	ServerID     int      `json:"server_id" binding:"required"`
+	StartTime    string   `json:"start_time" binding:"required"`
+	EndTime      string   `json:"end_time" binding:"required"`
+	Step         string   `json:"step" binding:"required"`
+	LoginName    string   `json:"login_name" binding:"required"`
+	CallbackURLs []string `json:"callback_urls" binding:"omitempty,dive,url"`
 }
 
 func (c *Check) GetServerID() int {
@@ -28,13 +33,18 @@ func (c *Check) GetLoginName() string {
 	return c.LoginName
 }
 
+func (c *Check) GetCallbackURLs() []string {
+	return c.CallbackURLs
+}
+
 type CheckByHostInfo struct {
-	HostIP    string `json:"host_ip" binding:"required"`
-	PortNum   int    `json:"port_num" binding:"required"`
-	StartTime string `json:"start_time" binding:"required"`
-	EndTime   string `json:"end_time" binding:"required"`
-	Step      string `json:"step" binding:"required"`
-	LoginName string `json:"login_name" binding:"required"`
+	HostIP       string   `json:"host_ip" binding:"required"`
+	PortNum      int      `json:"port_num" binding:"required"`
+	StartTime    string   `json:"start_time" binding:"required"`
+	EndTime      string   `json:"end_time" binding:"required"`
+	Step         string   `json:"step" binding:"required"`
+	LoginName    string   `json:"login_name" binding:"required"`
+	CallbackURLs []string `json:"callback_urls" binding:"omitempty,dive,url"`
 }
 
 func (cbhi *CheckByHostInfo) GetHostIP() string {
@@ -61,6 +71,66 @@ func (cbhi *CheckByHostInfo) GetLoginName() string {
 	return cbhi.LoginName
 }
 
+func (cbhi *CheckByHostInfo) GetCallbackURLs() []string {
+	return cbhi.CallbackURLs
+}
+
+type CheckCluster struct {
+	ClusterID int    `json:"cluster_id" binding:"required"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+	Step      string `json:"step" binding:"required"`
+	LoginName string `json:"login_name" binding:"required"`
+}
+
+func (cc *CheckCluster) GetClusterID() int {
+	return cc.ClusterID
+}
+
+func (cc *CheckCluster) GetStartTime() string {
+	return cc.StartTime
+}
+
+func (cc *CheckCluster) GetEndTime() string {
+	return cc.EndTime
+}
+
+func (cc *CheckCluster) GetStep() string {
+	return cc.Step
+}
+
+func (cc *CheckCluster) GetLoginName() string {
+	return cc.LoginName
+}
+
+type CheckMiddleware struct {
+	MiddlewareClusterID int    `json:"middleware_cluster_id" binding:"required"`
+	StartTime           string `json:"start_time" binding:"required"`
+	EndTime             string `json:"end_time" binding:"required"`
+	Step                string `json:"step" binding:"required"`
+	LoginName           string `json:"login_name" binding:"required"`
+}
+
+func (cm *CheckMiddleware) GetMiddlewareClusterID() int {
+	return cm.MiddlewareClusterID
+}
+
+func (cm *CheckMiddleware) GetStartTime() string {
+	return cm.StartTime
+}
+
+func (cm *CheckMiddleware) GetEndTime() string {
+	return cm.EndTime
+}
+
+func (cm *CheckMiddleware) GetStep() string {
+	return cm.Step
+}
+
+func (cm *CheckMiddleware) GetLoginName() string {
+	return cm.LoginName
+}
+
 type ReviewAccuracy struct {
 	OperationID int `json:"operation_id" binding:"required"`
 	Review      int `json:"review" binding:"required"`
@@ -73,3 +143,308 @@ func (ra *ReviewAccuracy) GetOperationID() int {
 func (ra *ReviewAccuracy) GetReview() int {
 	return ra.Review
 }
+
+type ReviewItemAccuracy struct {
+	OperationID int    `json:"operation_id" binding:"required"`
+	ItemName    string `json:"item_name" binding:"required"`
+	Accuracy    int    `json:"accuracy" binding:"required"`
+	Comment     string `json:"comment"`
+	LoginName   string `json:"login_name" binding:"required"`
+}
+
+func (ria *ReviewItemAccuracy) GetOperationID() int {
+	return ria.OperationID
+}
+
+func (ria *ReviewItemAccuracy) GetItemName() string {
+	return ria.ItemName
+}
+
+func (ria *ReviewItemAccuracy) GetAccuracy() int {
+	return ria.Accuracy
+}
+
+func (ria *ReviewItemAccuracy) GetComment() string {
+	return ria.Comment
+}
+
+func (ria *ReviewItemAccuracy) GetLoginName() string {
+	return ria.LoginName
+}
+
+type FeedbackStats struct {
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+func (fs *FeedbackStats) GetStartTime() string {
+	return fs.StartTime
+}
+
+func (fs *FeedbackStats) GetEndTime() string {
+	return fs.EndTime
+}
+
+type Cancel struct {
+	OperationID int    `json:"operation_id" binding:"required"`
+	LoginName   string `json:"login_name" binding:"required"`
+}
+
+func (c *Cancel) GetOperationID() int {
+	return c.OperationID
+}
+
+func (c *Cancel) GetLoginName() string {
+	return c.LoginName
+}
+
+type Trend struct {
+	ServerID  int    `json:"server_id" binding:"required"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+	LoginName string `json:"login_name" binding:"required"`
+}
+
+func (t *Trend) GetServerID() int {
+	return t.ServerID
+}
+
+func (t *Trend) GetStartTime() string {
+	return t.StartTime
+}
+
+func (t *Trend) GetEndTime() string {
+	return t.EndTime
+}
+
+func (t *Trend) GetLoginName() string {
+	return t.LoginName
+}
+
+type ClusterTrend struct {
+	ClusterID int    `json:"cluster_id" binding:"required"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+	LoginName string `json:"login_name" binding:"required"`
+}
+
+func (ct *ClusterTrend) GetClusterID() int {
+	return ct.ClusterID
+}
+
+func (ct *ClusterTrend) GetStartTime() string {
+	return ct.StartTime
+}
+
+func (ct *ClusterTrend) GetEndTime() string {
+	return ct.EndTime
+}
+
+func (ct *ClusterTrend) GetLoginName() string {
+	return ct.LoginName
+}
+
+type Capacity struct {
+	TargetType int    `json:"target_type" binding:"required"`
+	TargetID   int    `json:"target_id" binding:"required"`
+	StartTime  string `json:"start_time" binding:"required"`
+	EndTime    string `json:"end_time" binding:"required"`
+	LoginName  string `json:"login_name" binding:"required"`
+}
+
+func (c *Capacity) GetTargetType() int {
+	return c.TargetType
+}
+
+func (c *Capacity) GetTargetID() int {
+	return c.TargetID
+}
+
+func (c *Capacity) GetStartTime() string {
+	return c.StartTime
+}
+
+func (c *Capacity) GetEndTime() string {
+	return c.EndTime
+}
+
+func (c *Capacity) GetLoginName() string {
+	return c.LoginName
+}
+
+type Compare struct {
+	BaseOperationID   int `json:"base_operation_id" binding:"required"`
+	TargetOperationID int `json:"target_operation_id" binding:"required"`
+}
+
+func (c *Compare) GetBaseOperationID() int {
+	return c.BaseOperationID
+}
+
+func (c *Compare) GetTargetOperationID() int {
+	return c.TargetOperationID
+}
+
+type Report struct {
+	OperationID int    `json:"operation_id" binding:"required"`
+	Format      string `json:"format"`
+	LoginName   string `json:"login_name" binding:"required"`
+}
+
+func (r *Report) GetOperationID() int {
+	return r.OperationID
+}
+
+func (r *Report) GetFormat() string {
+	return r.Format
+}
+
+func (r *Report) GetLoginName() string {
+	return r.LoginName
+}
+
+type Snapshot struct {
+	OperationID int    `json:"operation_id" binding:"required"`
+	LoginName   string `json:"login_name" binding:"required"`
+}
+
+func (s *Snapshot) GetOperationID() int {
+	return s.OperationID
+}
+
+func (s *Snapshot) GetLoginName() string {
+	return s.LoginName
+}
+
+type CheckWithSnapshot struct {
+	Snapshot  json.RawMessage `json:"snapshot" binding:"required"`
+	LoginName string          `json:"login_name" binding:"required"`
+}
+
+func (cws *CheckWithSnapshot) GetSnapshot() []byte {
+	return cws.Snapshot
+}
+
+func (cws *CheckWithSnapshot) GetLoginName() string {
+	return cws.LoginName
+}
+
+type ScoringProfile struct {
+	ID          int             `json:"id"`
+	ProfileName string          `json:"profile_name" binding:"required"`
+	Description string          `json:"description"`
+	ItemConfigs json.RawMessage `json:"item_configs" binding:"required"`
+}
+
+func (sp *ScoringProfile) GetID() int {
+	return sp.ID
+}
+
+func (sp *ScoringProfile) GetProfileName() string {
+	return sp.ProfileName
+}
+
+func (sp *ScoringProfile) GetDescription() string {
+	return sp.Description
+}
+
+func (sp *ScoringProfile) GetItemConfigs() []byte {
+	return sp.ItemConfigs
+}
+
+type EngineConfig struct {
+	ProfileID   int             `json:"profile_id"`
+	Version     int             `json:"version"`
+	ItemConfigs json.RawMessage `json:"item_configs"`
+	LoginName   string          `json:"login_name"`
+}
+
+func (ec *EngineConfig) GetProfileID() int {
+	return ec.ProfileID
+}
+
+func (ec *EngineConfig) GetVersion() int {
+	return ec.Version
+}
+
+func (ec *EngineConfig) GetItemConfigs() []byte {
+	return ec.ItemConfigs
+}
+
+func (ec *EngineConfig) GetLoginName() string {
+	return ec.LoginName
+}
+
+type ScoringProfileAssignment struct {
+	ID         int `json:"id"`
+	TargetType int `json:"target_type" binding:"required"`
+	TargetID   int `json:"target_id" binding:"required"`
+}
+
+func (spa *ScoringProfileAssignment) GetID() int {
+	return spa.ID
+}
+
+func (spa *ScoringProfileAssignment) GetTargetType() int {
+	return spa.TargetType
+}
+
+func (spa *ScoringProfileAssignment) GetTargetID() int {
+	return spa.TargetID
+}
+
+type Progress struct {
+	OperationID int    `json:"operation_id" binding:"required"`
+	LoginName   string `json:"login_name" binding:"required"`
+}
+
+func (p *Progress) GetOperationID() int {
+	return p.OperationID
+}
+
+func (p *Progress) GetLoginName() string {
+	return p.LoginName
+}
+
+type PromQLTemplateDryRun struct {
+	MonitorSystemID int      `json:"monitor_system_id" binding:"required"`
+	ServerID        int      `json:"server_id" binding:"required"`
+	TemplateName    string   `json:"template_name" binding:"required"`
+	TemplateText    string   `json:"template_text"`
+	MountPoints     []string `json:"mount_points"`
+	StartTime       string   `json:"start_time" binding:"required"`
+	EndTime         string   `json:"end_time" binding:"required"`
+	Step            string   `json:"step" binding:"required"`
+}
+
+func (ptdr *PromQLTemplateDryRun) GetMonitorSystemID() int {
+	return ptdr.MonitorSystemID
+}
+
+func (ptdr *PromQLTemplateDryRun) GetServerID() int {
+	return ptdr.ServerID
+}
+
+func (ptdr *PromQLTemplateDryRun) GetTemplateName() string {
+	return ptdr.TemplateName
+}
+
+func (ptdr *PromQLTemplateDryRun) GetTemplateText() string {
+	return ptdr.TemplateText
+}
+
+func (ptdr *PromQLTemplateDryRun) GetMountPoints() []string {
+	return ptdr.MountPoints
+}
+
+func (ptdr *PromQLTemplateDryRun) GetStartTime() string {
+	return ptdr.StartTime
+}
+
+func (ptdr *PromQLTemplateDryRun) GetEndTime() string {
+	return ptdr.EndTime
+}
+
+func (ptdr *PromQLTemplateDryRun) GetStep() string {
+	return ptdr.Step
+}
@@ -180,6 +180,19 @@ func (sir *SQLIDRange) GetConfig() (depquery.Config, error) {
 	return getConfig(sir.GetStartTime(), sir.GetEndTime(), sir.GetLimit(), sir.GetOffset())
 }
 
+type SlowLogIngestion struct {
+	MySQLServerID int    `json:"mysql_server_id" bind:"required"`
+	Content       string `json:"content" bind:"required"`
+}
+
+func (sli *SlowLogIngestion) GetMySQLServerID() int {
+	return sli.MySQLServerID
+}
+
+func (sli *SlowLogIngestion) GetContent() string {
+	return sli.Content
+}
+
 func getConfig(startTime, endTime string, limit, offset int) (depquery.Config, error) {
 	st, err := time.ParseInLocation(constant.TimeLayoutSecond, startTime, time.Local)
 	if err != nil {
@@ -11,8 +11,64 @@ func RegisterHealthcheck(group *gin.RouterGroup) {
 	{
 		healthcheckGroup.POST("/history", healthcheck.GetOperationHistoriesByLoginName)
 		healthcheckGroup.POST("/result", healthcheck.GetResultByOperationID)
+		healthcheckGroup.POST("/result/cluster", healthcheck.GetClusterResultByClusterOperationID)
+		healthcheckGroup.POST("/result/middleware", healthcheck.GetMiddlewareResultByMiddlewareOperationID)
 		healthcheckGroup.POST("/check", healthcheck.Check)
 		healthcheckGroup.POST("/check/host-info", healthcheck.CheckByHostInfo)
+		healthcheckGroup.POST("/check/cluster", healthcheck.CheckCluster)
+		healthcheckGroup.POST("/check/middleware", healthcheck.CheckMiddleware)
+		healthcheckGroup.POST("/check/snapshot", healthcheck.CheckWithSnapshot)
 		healthcheckGroup.POST("/review", healthcheck.ReviewAccuracy)
+		healthcheckGroup.POST("/review/item", healthcheck.ReviewItemAccuracy)
+		healthcheckGroup.POST("/review/item/operation-id", healthcheck.GetItemFeedbacks)
+		healthcheckGroup.POST("/review/stats", healthcheck.GetFeedbackStats)
+		healthcheckGroup.POST("/cancel", healthcheck.Cancel)
+		healthcheckGroup.POST("/queue", healthcheck.GetOperationQueue)
+		healthcheckGroup.POST("/progress", healthcheck.GetProgress)
+		healthcheckGroup.POST("/progress/stream", healthcheck.GetProgressStream)
+		healthcheckGroup.POST("/trend", healthcheck.GetTrend)
+		healthcheckGroup.POST("/trend/cluster", healthcheck.GetClusterTrend)
+		healthcheckGroup.POST("/capacity", healthcheck.GetCapacity)
+		healthcheckGroup.POST("/compare", healthcheck.CompareResults)
+		healthcheckGroup.POST("/report", healthcheck.GetReport)
+		healthcheckGroup.POST("/snapshot", healthcheck.GetSnapshot)
+		// schedule
+		healthcheckGroup.POST("/schedule/all", healthcheck.GetSchedule)
+		healthcheckGroup.POST("/schedule/id", healthcheck.GetScheduleByID)
+		healthcheckGroup.POST("/schedule/add", healthcheck.AddSchedule)
+		healthcheckGroup.POST("/schedule/update", healthcheck.UpdateScheduleByID)
+		healthcheckGroup.POST("/schedule/delete", healthcheck.DeleteScheduleByID)
+		// scoring profile
+		healthcheckGroup.POST("/profile/all", healthcheck.GetScoringProfile)
+		healthcheckGroup.POST("/profile/id", healthcheck.GetScoringProfileByID)
+		healthcheckGroup.POST("/profile/add", healthcheck.AddScoringProfile)
+		healthcheckGroup.POST("/profile/update", healthcheck.UpdateScoringProfileByID)
+		healthcheckGroup.POST("/profile/delete", healthcheck.DeleteScoringProfileByID)
+		healthcheckGroup.POST("/profile/assign", healthcheck.AssignScoringProfile)
+		healthcheckGroup.POST("/profile/unassign", healthcheck.UnassignScoringProfile)
+		// engine config
+		healthcheckGroup.POST("/config/get", healthcheck.GetEngineConfig)
+		healthcheckGroup.POST("/config/update", healthcheck.UpdateEngineConfig)
+		healthcheckGroup.POST("/config/replace", healthcheck.ReplaceEngineConfig)
+		healthcheckGroup.POST("/config/version", healthcheck.GetEngineConfigVersions)
+		healthcheckGroup.POST("/config/rollback", healthcheck.RollbackEngineConfig)
+		// db config rule
+		healthcheckGroup.POST("/rule/all", healthcheck.GetDBConfigRule)
+		healthcheckGroup.POST("/rule/id", healthcheck.GetDBConfigRuleByID)
+		healthcheckGroup.POST("/rule/add", healthcheck.AddDBConfigRule)
+		healthcheckGroup.POST("/rule/update", healthcheck.UpdateDBConfigRuleByID)
+		healthcheckGroup.POST("/rule/delete", healthcheck.DeleteDBConfigRuleByID)
+		// retention
+		healthcheckGroup.POST("/retention/all", healthcheck.GetRetention)
+		healthcheckGroup.POST("/retention/env", healthcheck.GetRetentionByEnvID)
+		healthcheckGroup.POST("/retention/save", healthcheck.SaveRetention)
+		healthcheckGroup.POST("/retention/delete", healthcheck.DeleteRetentionByEnvID)
+		healthcheckGroup.POST("/retention/dry-run", healthcheck.RetentionDryRun)
+		// promql template
+		healthcheckGroup.POST("/promql/all", healthcheck.GetPromQLTemplate)
+		healthcheckGroup.POST("/promql/monitor-system", healthcheck.GetPromQLTemplateByMonitorSystemID)
+		healthcheckGroup.POST("/promql/save", healthcheck.SavePromQLTemplate)
+		healthcheckGroup.POST("/promql/delete", healthcheck.DeletePromQLTemplate)
+		healthcheckGroup.POST("/promql/dry-run", healthcheck.PromQLTemplateDryRun)
 	}
 }
@@ -5,6 +5,14 @@ import (
 	"github.com/romberli/das/api/v1/query"
 )
 
+const (
+	// querySlowLogIngestPath is the path of the slow log ingestion api
+	querySlowLogIngestPath = "/api/v1/query/slow-log/ingest"
+	// querySlowLogIngestMaxBodySize is the maximum size of the request body of the slow log ingestion api,
+	// the larger slow log files should be ingested with the slowlog command
+	querySlowLogIngestMaxBodySize = 32 * 1024 * 1024
+)
+
 // RegisterQuery is the sub-router of das for query
 func RegisterQuery(group *gin.RouterGroup) {
 	queryGroup := group.Group("/query")
@@ -14,5 +22,7 @@ func RegisterQuery(group *gin.RouterGroup) {
 		queryGroup.POST("/host-info", query.GetByHostInfo)
 		queryGroup.POST("/db", query.GetByDBID)
 		queryGroup.POST("/sql", query.GetBySQLID)
+		// slow log
+		queryGroup.POST("/slow-log/ingest", query.IngestSlowLog)
 	}
 }
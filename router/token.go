@@ -3,6 +3,7 @@ package router
 import (
 	"bytes"
 	"io/ioutil"
+	"net/http"
 	"strings"
 
 	"github.com/buger/jsonparser"
@@ -24,6 +25,12 @@ const (
 	tokenSwaggerPath = "/swagger"
 )
 
+// tokenMaxBodySizes are the maximum sizes of the request bodies of the apis which post the file contents,
+// the whole body is read to check the token, so it must be limited before being read
+var tokenMaxBodySizes = map[string]int64{
+	querySlowLogIngestPath: querySlowLogIngestMaxBodySize,
+}
+
 type TokenAuth struct {
 	Database middleware.Pool
 }
@@ -85,6 +92,9 @@ func (ta *TokenAuth) GetHandlerFunc(tokens []string) gin.HandlerFunc {
 			// do not check token for swagger
 			return
 		}
+		if maxBodySize, ok := tokenMaxBodySizes[c.Request.URL.Path]; ok {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
+		}
 		// get data
 		data, err := c.GetRawData()
 		if err != nil {
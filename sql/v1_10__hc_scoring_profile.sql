@@ -0,0 +1,35 @@
+CREATE TABLE `t_hc_scoring_profile`
+(
+    `id`               int(11)      NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `profile_name`     varchar(100) NOT NULL COMMENT '评分配置名称',
+    `description`      varchar(500) NOT NULL DEFAULT '' COMMENT '描述',
+    `del_flag`         tinyint(4)   NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_profile_name` (`profile_name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查评分配置表';
+
+CREATE TABLE `t_hc_scoring_profile_assignment`
+(
+    `id`               int(11)     NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `profile_id`       int(11)     NOT NULL COMMENT '评分配置ID',
+    `target_type`      tinyint(4)  NOT NULL COMMENT '对象类型: 2-mysql集群, 3-资源组, 4-环境',
+    `target_id`        int(11)     NOT NULL COMMENT '对象ID',
+    `del_flag`         tinyint(4)  NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_target_type_target_id` (`target_type`, `target_id`),
+    KEY `idx02_profile_id` (`profile_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查评分配置分配表';
+
+ALTER TABLE `t_hc_default_engine_config`
+    ADD COLUMN `profile_id` int(11) NOT NULL DEFAULT '0' COMMENT '评分配置ID, 0-全局默认配置' AFTER `id`,
+    DROP KEY `idx01_item_name`,
+    ADD UNIQUE KEY `idx01_profile_id_item_name` (`profile_id`, `item_name`);
+
+ALTER TABLE `t_hc_operation_history`
+    ADD COLUMN `profile_id` int(11) NOT NULL DEFAULT '0' COMMENT '检查使用的评分配置ID, 0-全局默认配置' AFTER `step`;
@@ -0,0 +1,51 @@
+CREATE TABLE `t_hc_db_config_rule`
+(
+    `id`               int(11)       NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `variable_name`    varchar(100)  NOT NULL COMMENT '参数名称',
+    `comparator`       varchar(20)   NOT NULL COMMENT '比较方式: eq-等于(不区分大小写), ge-大于等于, le-小于等于, in-在列表中(逗号分隔, 不区分大小写), regex-匹配正则表达式',
+    `expected_value`   varchar(1000) NOT NULL COMMENT '期望值, 支持占位符: ${host_ip}, ${port_num}, ${server_name}',
+    `severity`         tinyint(4)    NOT NULL DEFAULT '1' COMMENT '严重程度: 1-高, 2-中',
+    `min_version`      varchar(20)   NOT NULL DEFAULT '' COMMENT '适用的最低mysql版本(包含), 为空表示不限制',
+    `max_version`      varchar(20)   NOT NULL DEFAULT '' COMMENT '适用的最高mysql版本(不包含), 为空表示不限制',
+    `del_flag`         tinyint(4)    NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6)   NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6)   NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx01_variable_name` (`variable_name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查数据库参数配置规则表';
+
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('log_bin', 'eq', 'ON', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('binlog_format', 'eq', 'ROW', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('binlog_row_image', 'eq', 'FULL', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('sync_binlog', 'eq', '1', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('innodb_flush_log_at_trx_commit', 'eq', '1', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('gtid_mode', 'eq', 'ON', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('enforce_gtid_consistency', 'eq', 'ON', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('slave_parallel_type', 'eq', 'LOGICAL_CLOCK', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('master_info_repository', 'eq', 'TABLE', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('relay_log_info_repository', 'eq', 'TABLE', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('report_host', 'in', '${host_ip},${server_name}', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('report_port', 'eq', '${port_num}', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('innodb_flush_method', 'eq', 'O_DIRECT', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('innodb_print_all_deadlocks', 'eq', 'ON', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('slow_query_log', 'eq', 'ON', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('performance_schema', 'eq', 'ON', 1, '', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('max_user_connection', 'ge', '2000', 1, '', '');
@@ -0,0 +1,18 @@
+CREATE TABLE `t_hc_engine_config_version`
+(
+    `id`               int(11)      NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `profile_id`       int(11)      NOT NULL DEFAULT '0' COMMENT '评分配置ID, 0-全局默认配置',
+    `version`          int(11)      NOT NULL COMMENT '配置版本, 每个评分配置从1开始递增',
+    `item_configs`     mediumtext   NOT NULL COMMENT '该版本完整的检查项配置, json格式',
+    `login_name`       varchar(100) NOT NULL DEFAULT '' COMMENT '修改人登录名',
+    `description`      varchar(500) NOT NULL DEFAULT '' COMMENT '修改说明',
+    `del_flag`         tinyint(4)   NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_profile_id_version` (`profile_id`, `version`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查引擎配置版本表';
+
+ALTER TABLE `t_hc_operation_history`
+    ADD COLUMN `config_version` int(11) NOT NULL DEFAULT '0' COMMENT '检查使用的引擎配置版本, 0-配置未通过接口修改过' AFTER `profile_id`;
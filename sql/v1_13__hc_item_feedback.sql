@@ -0,0 +1,16 @@
+CREATE TABLE `t_hc_item_feedback`
+(
+    `id`               int(11)       NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `operation_id`     int(11)       NOT NULL COMMENT '操作ID',
+    `item_name`        varchar(100)  NOT NULL COMMENT '检查项名称, 与t_hc_item_result.item_name对应',
+    `accuracy`         tinyint(4)    NOT NULL COMMENT '准确性评价: 1-准确, 2-不准确',
+    `comment`          varchar(1000) NOT NULL DEFAULT '' COMMENT '评价说明',
+    `login_name`       varchar(100)  NOT NULL COMMENT '评价人登录名',
+    `del_flag`         tinyint(4)    NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6)   NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6)   NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_operation_id_item_name_login_name` (`operation_id`, `item_name`, `login_name`),
+    KEY `idx02_item_name_accuracy` (`item_name`, `accuracy`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查检查项准确性评价表';
@@ -0,0 +1,4 @@
+ALTER TABLE `t_hc_operation_history`
+    MODIFY COLUMN `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '运行状态: 0-未运行, 1-运行中, 2-已完成, 3-已失败, 4-部分完成, 5-已取消, 6-排队中',
+    ADD COLUMN `priority` tinyint(4) NOT NULL DEFAULT '0' COMMENT '排队优先级, 数值越大越先执行: 1-定时检查, 2-手动检查' AFTER `config_version`,
+    ADD KEY `idx07_status_priority` (`status`, `priority`);
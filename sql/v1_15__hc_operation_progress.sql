@@ -0,0 +1,15 @@
+CREATE TABLE `t_hc_operation_progress`
+(
+    `id`                int(11)      NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `operation_id`      int(11)      NOT NULL COMMENT '操作ID',
+    `total_item_num`    int(11)      NOT NULL DEFAULT '0' COMMENT '检查项总数',
+    `finished_item_num` int(11)      NOT NULL DEFAULT '0' COMMENT '已完成的检查项数',
+    `current_item_name` varchar(100) NOT NULL DEFAULT '' COMMENT '正在执行的检查项名称, 空字符串表示没有正在执行的检查项',
+    `elapsed`           int(11)      NOT NULL DEFAULT '0' COMMENT '已运行时间, 单位: 毫秒',
+    `del_flag`          tinyint(4)   NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`       datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time`  datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_operation_id` (`operation_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查进度表';
@@ -0,0 +1,8 @@
+insert into t_hc_default_engine_config(item_name, item_weight, low_watermark, high_watermark, unit, score_deduction_per_unit_high, max_score_deduction_high, score_deduction_per_unit_medium, max_score_deduction_medium)
+values('innodb_long_transaction', 0, 60, 600, 60, 10, 100, 5, 50);
+insert into t_hc_default_engine_config(item_name, item_weight, low_watermark, high_watermark, unit, score_deduction_per_unit_high, max_score_deduction_high, score_deduction_per_unit_medium, max_score_deduction_medium)
+values('innodb_history_list_length', 0, 100000, 1000000, 100000, 10, 100, 5, 50);
+insert into t_hc_default_engine_config(item_name, item_weight, low_watermark, high_watermark, unit, score_deduction_per_unit_high, max_score_deduction_high, score_deduction_per_unit_medium, max_score_deduction_medium)
+values('innodb_lock_wait', 0, 10, 60, 10, 10, 100, 5, 50);
+insert into t_hc_default_engine_config(item_name, item_weight, low_watermark, high_watermark, unit, score_deduction_per_unit_high, max_score_deduction_high, score_deduction_per_unit_medium, max_score_deduction_medium)
+values('innodb_deadlock', 0, 0.5, 0.5, 0.5, 20, 100, 0, 0);
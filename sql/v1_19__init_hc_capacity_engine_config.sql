@@ -0,0 +1,2 @@
+insert into t_hc_default_engine_config(item_name, item_weight, low_watermark, high_watermark, unit, score_deduction_per_unit_high, max_score_deduction_high, score_deduction_per_unit_medium, max_score_deduction_medium)
+values('disk_capacity_forecast', 0, 0.7, 0.9, 0.1, 40, 100, 10, 50);
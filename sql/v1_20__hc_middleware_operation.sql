@@ -0,0 +1,36 @@
+CREATE TABLE `t_hc_middleware_operation_history`
+(
+    `id`                    int(11)     NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id`               int(11)     NOT NULL COMMENT '用户ID',
+    `middleware_cluster_id` int(11)     NOT NULL COMMENT '中间件集群ID',
+    `start_time`            datetime(6) NOT NULL COMMENT '检查范围开始时间',
+    `end_time`              datetime(6) NOT NULL COMMENT '检查范围结束时间',
+    `step`                  int(11)     NOT NULL COMMENT '采样间隔, 单位: 秒',
+    `status`                tinyint(4)  NOT NULL DEFAULT '0' COMMENT '运行状态: 0-未运行, 1-运行中, 2-已完成, 3-已失败',
+    `message`               mediumtext           DEFAULT NULL COMMENT '运行日志',
+    `del_flag`              tinyint(4)  NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`           datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time`      datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx01_user_id_middleware_cluster_id` (`user_id`, `middleware_cluster_id`),
+    KEY `idx02_middleware_cluster_id_status` (`middleware_cluster_id`, `status`),
+    KEY `idx03_create_time` (`create_time`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查中间件操作表';
+
+CREATE TABLE `t_hc_middleware_result`
+(
+    `id`                      int(11)     NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `middleware_operation_id` int(11)     NOT NULL COMMENT '中间件操作ID',
+    `middleware_cluster_id`   int(11)     NOT NULL COMMENT '中间件集群ID',
+    `score`                   int(11)     NOT NULL DEFAULT '0' COMMENT '中间件集群得分',
+    `server_result`           mediumtext           DEFAULT NULL COMMENT '各中间件服务器的存活状态和资源使用率',
+    `endpoint_result`         mediumtext           DEFAULT NULL COMMENT '关联的各mysql集群的读写和只读入口存活情况',
+    `del_flag`                tinyint(4)  NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`             datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time`        datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_middleware_operation_id` (`middleware_operation_id`),
+    KEY `idx02_middleware_cluster_id` (`middleware_cluster_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查中间件结果表';
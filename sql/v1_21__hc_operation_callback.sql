@@ -0,0 +1,12 @@
+CREATE TABLE `t_hc_operation_callback`
+(
+    `id`               int(11)       NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `operation_id`     int(11)       NOT NULL COMMENT '操作ID',
+    `callback_url`     varchar(1000) NOT NULL COMMENT '回调地址, 操作完成, 失败或取消后会调用',
+    `del_flag`         tinyint(4)    NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6)   NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6)   NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx01_operation_id` (`operation_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查操作回调表';
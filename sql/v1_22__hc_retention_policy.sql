@@ -0,0 +1,16 @@
+CREATE TABLE `t_hc_retention_policy`
+(
+    `id`               int(11)     NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `env_id`           int(11)     NOT NULL COMMENT '环境ID',
+    `full_data_days`   int(11)     NOT NULL COMMENT '完整数据保留天数, 超过后只保留评分',
+    `del_flag`         tinyint(4)  NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_env_id` (`env_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查数据保留策略表';
+
+ALTER TABLE `t_hc_operation_history`
+    ADD COLUMN `archive_flag` tinyint(4) NOT NULL DEFAULT '0' COMMENT '归档标记: 0-未归档, 1-已归档, 归档后只保留评分' AFTER `message`,
+    ADD KEY `idx08_archive_flag_create_time` (`archive_flag`, `create_time`);
@@ -0,0 +1,2 @@
+ALTER TABLE `t_meta_monitor_system_info`
+    MODIFY COLUMN `system_type` tinyint(4) NOT NULL COMMENT '监控系统类型: 1-pmm1.x, 2-pmm2.x, 3-通用prometheus(node_exporter, mysqld_exporter, 慢查询来自performance_schema)';
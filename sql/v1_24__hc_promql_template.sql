@@ -0,0 +1,13 @@
+CREATE TABLE `t_hc_promql_template`
+(
+    `id`                int(11)      NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `monitor_system_id` int(11)      NOT NULL COMMENT '监控系统ID',
+    `template_name`     varchar(100) NOT NULL COMMENT '模板名称, 如cpu_usage, io_util等',
+    `template_text`     text         NOT NULL COMMENT '模板内容, 可使用的变量: {{.ServiceName}}, {{.NodeName}}, {{.MountPoints}}, {{.Window}}, {{.Step}}, {{.NodeSelector}}, {{.ServiceSelector}}',
+    `del_flag`          tinyint(4)   NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`       datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time`  datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_monitor_system_id_template_name` (`monitor_system_id`, `template_name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查PromQL模板覆盖表, 未覆盖的模板使用内置模板';
@@ -0,0 +1,48 @@
+CREATE TABLE `t_query_slow_log_stat`
+(
+    `id`                bigint(20)    NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `mysql_server_id`   int(11)       NOT NULL COMMENT 'MySQL实例ID',
+    `sql_id`            varchar(100)  NOT NULL COMMENT 'SQL ID, 由SQL指纹计算得出',
+    `fingerprint`       text          NOT NULL COMMENT 'SQL指纹',
+    `example`           mediumtext    NOT NULL COMMENT 'SQL样例, 为窗口内执行时间最长的SQL',
+    `db_name`           varchar(100)  NOT NULL DEFAULT '' COMMENT '数据库名称',
+    `window_start`      datetime(6)   NOT NULL COMMENT '统计窗口开始时间, 窗口长度为1小时',
+    `exec_count`        int(11)       NOT NULL DEFAULT '0' COMMENT '执行次数',
+    `total_exec_time`   decimal(20,6) NOT NULL DEFAULT '0.000000' COMMENT '总执行时间, 单位: 秒',
+    `rows_examined_max` bigint(20)    NOT NULL DEFAULT '0' COMMENT '最大扫描行数',
+    `del_flag`          tinyint(4)    NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`       datetime(6)   NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time`  datetime(6)   NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_mysql_server_id_sql_id_db_name_window_start` (`mysql_server_id`, `sql_id`, `db_name`, `window_start`),
+    KEY `idx02_mysql_server_id_window_start` (`mysql_server_id`, `window_start`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '慢日志统计表, 由慢日志文件解析后按SQL指纹和时间窗口聚合, 同一实例的慢日志条目只会被统计一次';
+
+CREATE TABLE `t_query_slow_log_ingestion`
+(
+    `id`               bigint(20)  NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `mysql_server_id`  int(11)     NOT NULL COMMENT 'MySQL实例ID',
+    `start_time`       datetime(6) NOT NULL COMMENT '本次导入的慢日志文件中第一条慢日志的时间, 包含之前已导入的条目',
+    `end_time`         datetime(6) NOT NULL COMMENT '本次导入的最后一条慢日志的时间',
+    `end_keys`         text        NOT NULL COMMENT '本次导入的时间等于end_time的慢日志条目的标识, 由时间, 线程ID和SQL校验和组成, 以逗号分隔',
+    `entry_count`      int(11)     NOT NULL DEFAULT '0' COMMENT '本次导入的慢日志条数',
+    `del_flag`         tinyint(4)  NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx01_mysql_server_id_end_time` (`mysql_server_id`, `end_time`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '慢日志导入记录表, 早于已导入的最后一条慢日志时间的条目, 以及时间相同且标识相同的条目不会被重复导入';
+
+CREATE TABLE `t_query_slow_log_ingestion_state`
+(
+    `id`               bigint(20)  NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `mysql_server_id`  int(11)     NOT NULL COMMENT 'MySQL实例ID',
+    `del_flag`         tinyint(4)  NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间, 即最后一次导入的时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_mysql_server_id` (`mysql_server_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '慢日志导入状态表, 每个实例一行, 导入时先插入或更新该行以锁定该实例, 同一实例的慢日志不会被并发导入';
@@ -0,0 +1,7 @@
+update t_hc_db_config_rule set max_version = '8.0.26' where variable_name = 'slave_parallel_type' and max_version = '' and del_flag = 0;
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('replica_parallel_type', 'eq', 'LOGICAL_CLOCK', 1, '8.0.26', '');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('slave_parallel_workers', 'ge', '16', 1, '', '8.0.26');
+insert into t_hc_db_config_rule(variable_name, comparator, expected_value, severity, min_version, max_version)
+values('replica_parallel_workers', 'ge', '16', 1, '8.0.26', '');
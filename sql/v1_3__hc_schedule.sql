@@ -0,0 +1,25 @@
+CREATE TABLE `t_hc_schedule`
+(
+    `id`                int(11)      NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `schedule_name`     varchar(100) NOT NULL COMMENT '调度名称',
+    `cron_expression`   varchar(100) NOT NULL COMMENT 'cron表达式, 格式: 分 时 日 月 周',
+    `target_type`       tinyint(4)   NOT NULL COMMENT '检查对象类型: 1-mysql服务器, 2-mysql集群, 3-资源组, 4-环境',
+    `target_id`         int(11)      NOT NULL COMMENT '检查对象ID',
+    `lookback`          int(11)      NOT NULL COMMENT '检查范围, 从触发时间往前回溯的时长, 单位: 秒',
+    `step`              int(11)      NOT NULL COMMENT '采样间隔, 单位: 秒',
+    `login_name`        varchar(100) NOT NULL COMMENT '执行检查的用户登录名',
+    `enabled`           tinyint(4)   NOT NULL DEFAULT '1' COMMENT '是否启用: 0-未启用, 1-已启用',
+    `last_trigger_time` datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '最后触发时间, 多个das实例通过更新该字段抢占同一分钟的触发',
+    `del_flag`          tinyint(4)   NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `del_time`          datetime(6)  NOT NULL DEFAULT '1970-01-01 00:00:00.000000' COMMENT '删除时间, 未删除时为1970-01-01 00:00:00, 删除后调度名称可以被重新使用',
+    `create_time`       datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time`  datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_schedule_name_del_time` (`schedule_name`, `del_time`),
+    KEY `idx02_target_type_target_id` (`target_type`, `target_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查调度表';
+
+ALTER TABLE `t_hc_operation_history`
+    ADD COLUMN `schedule_id` int(11) NOT NULL DEFAULT '0' COMMENT '调度ID, 0-手动触发' AFTER `user_id`,
+    ADD KEY `idx05_schedule_id` (`schedule_id`);
@@ -0,0 +1,40 @@
+CREATE TABLE `t_hc_cluster_operation_history`
+(
+    `id`               int(11)     NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `user_id`          int(11)     NOT NULL COMMENT '用户ID',
+    `mysql_cluster_id` int(11)     NOT NULL COMMENT 'mysql集群ID',
+    `start_time`       datetime(6) NOT NULL COMMENT '检查范围开始时间',
+    `end_time`         datetime(6) NOT NULL COMMENT '检查范围结束时间',
+    `step`             int(11)     NOT NULL COMMENT '采样间隔, 单位: 秒',
+    `status`           tinyint(4)  NOT NULL DEFAULT '0' COMMENT '运行状态: 0-未运行, 1-运行中, 2-已完成, 3-已失败',
+    `message`          mediumtext           DEFAULT NULL COMMENT '运行日志',
+    `del_flag`         tinyint(4)  NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    KEY `idx01_user_id_mysql_cluster_id` (`user_id`, `mysql_cluster_id`),
+    KEY `idx02_mysql_cluster_id_status` (`mysql_cluster_id`, `status`),
+    KEY `idx03_create_time` (`create_time`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查集群操作表';
+
+CREATE TABLE `t_hc_cluster_result`
+(
+    `id`                     int(11)     NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `cluster_operation_id`   int(11)     NOT NULL COMMENT '集群操作ID',
+    `mysql_cluster_id`       int(11)     NOT NULL COMMENT 'mysql集群ID',
+    `weighted_average_score` int(11)     NOT NULL DEFAULT '0' COMMENT '集群得分, 取各节点加权平均得分的最小值',
+    `node_result`            mediumtext           DEFAULT NULL COMMENT '各节点检查结果, 包含每个节点得分最低的检查项',
+    `config_drift`           mediumtext           DEFAULT NULL COMMENT '主从节点之间不一致的数据库参数',
+    `del_flag`               tinyint(4)  NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`            datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time`       datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_cluster_operation_id` (`cluster_operation_id`),
+    KEY `idx02_mysql_cluster_id` (`mysql_cluster_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查集群结果表';
+
+ALTER TABLE `t_hc_operation_history`
+    ADD COLUMN `cluster_operation_id` int(11) NOT NULL DEFAULT '0' COMMENT '集群操作ID, 0-单节点检查' AFTER `schedule_id`,
+    ADD KEY `idx06_cluster_operation_id` (`cluster_operation_id`);
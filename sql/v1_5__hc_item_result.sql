@@ -0,0 +1,19 @@
+CREATE TABLE `t_hc_item_result`
+(
+    `id`               int(11)      NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+    `operation_id`     int(11)      NOT NULL COMMENT '操作ID',
+    `item_name`        varchar(100) NOT NULL COMMENT '检查项名称, 与t_hc_default_engine_config.item_name对应',
+    `data_source`      varchar(100) NOT NULL COMMENT '数据来源: application_mysql-应用数据库, prometheus-监控系统, query-慢查询',
+    `item_weight`      int(11)      NOT NULL COMMENT '检查时使用的权重百分比',
+    `score`            int(11)      NOT NULL COMMENT '检查项评分',
+    `data`             mediumtext            DEFAULT NULL COMMENT '检查项数据',
+    `high`             mediumtext            DEFAULT NULL COMMENT '检查项异常数据',
+    `advice`           mediumtext            DEFAULT NULL COMMENT '检查项优化建议',
+    `del_flag`         tinyint(4)   NOT NULL DEFAULT '0' COMMENT '删除标记: 0-未删除, 1-已删除',
+    `create_time`      datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT '创建时间',
+    `last_update_time` datetime(6)  NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后更新时间',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `idx01_operation_id_item_name` (`operation_id`, `item_name`),
+    KEY `idx02_item_name_score` (`item_name`, `score`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT = '健康检查检查项结果表';
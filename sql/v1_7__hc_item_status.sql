@@ -0,0 +1,6 @@
+ALTER TABLE `t_hc_item_result`
+    ADD COLUMN `status`  tinyint(4) NOT NULL DEFAULT '0' COMMENT '检查项状态: 0-正常, 1-跳过, 2-错误' AFTER `score`,
+    ADD COLUMN `message` mediumtext          DEFAULT NULL COMMENT '检查项跳过或错误的原因' AFTER `status`;
+
+ALTER TABLE `t_hc_operation_history`
+    MODIFY COLUMN `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '运行状态: 0-未运行, 1-运行中, 2-已完成, 3-已失败, 4-部分完成';
@@ -0,0 +1,2 @@
+ALTER TABLE `t_hc_operation_history`
+    MODIFY COLUMN `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '运行状态: 0-未运行, 1-运行中, 2-已完成, 3-已失败, 4-部分完成, 5-已取消';